@@ -0,0 +1,147 @@
+// Package applog 是应用内部的结构化日志：按级别过滤，写入配置目录下自动轮转的文件，
+// 并在内存中保留最近的记录供调试日志窗口查看。未调用 Setup 时只保留内存记录。
+package applog
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const (
+	// FileName 是日志目录中的当前日志文件名，轮转后的文件依次为 .1、.2 ...
+	FileName = "ecs-gui.log"
+	// DefaultMaxSize 是单个日志文件的最大字节数
+	DefaultMaxSize = 1 << 20
+	// DefaultBackups 是保留的轮转文件个数
+	DefaultBackups = 3
+	recentCapacity = 1000
+)
+
+var (
+	level  slog.LevelVar
+	recent = newRing(recentCapacity)
+
+	mu   sync.RWMutex
+	base slog.Handler = newHandler(recent)
+	file *rotatingFile
+)
+
+func newHandler(w io.Writer) slog.Handler {
+	return slog.NewTextHandler(w, &slog.HandlerOptions{Level: &level})
+}
+
+// Setup 把日志写入 dir 下的轮转文件，重复调用时关闭上一个文件
+func Setup(dir string) error {
+	f, err := openRotating(filepath.Join(dir, FileName), DefaultMaxSize, DefaultBackups)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	previous := file
+	file = f
+	base = newHandler(io.MultiWriter(f, recent))
+	mu.Unlock()
+	if previous != nil {
+		previous.Close()
+	}
+	return nil
+}
+
+// Close 关闭日志文件，之后的记录只保留在内存中
+func Close() {
+	mu.Lock()
+	previous := file
+	file = nil
+	base = newHandler(recent)
+	mu.Unlock()
+	if previous != nil {
+		previous.Close()
+	}
+}
+
+// Path 返回当前日志文件路径，未调用 Setup 时为空
+func Path() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	if file == nil {
+		return ""
+	}
+	return file.path
+}
+
+// For 返回带 component 属性的日志记录器，可在 Setup 之前创建并保存在包级变量中
+func For(component string) *slog.Logger {
+	return slog.New(forwardHandler{}).With("component", component)
+}
+
+// SetLevel 设置最低记录级别，默认 Info
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// Level 返回当前的最低记录级别
+func Level() slog.Level {
+	return level.Level()
+}
+
+// Levels 是可选的级别名称，由低到高
+var Levels = []string{"debug", "info", "warn", "error"}
+
+// ParseLevel 解析 Levels 中的级别名称（不区分大小写）
+func ParseLevel(name string) (slog.Level, bool) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return slog.LevelInfo, false
+	}
+	return l, true
+}
+
+// Recent 返回内存中保留的最近记录，每项一行，按时间顺序
+func Recent() []string {
+	return recent.lines()
+}
+
+// forwardHandler 在每次记录时转交给当前的 base，使 Setup 之前创建的记录器也写入文件
+type forwardHandler struct {
+	with func(slog.Handler) slog.Handler
+}
+
+func (h forwardHandler) current() slog.Handler {
+	mu.RLock()
+	handler := base
+	mu.RUnlock()
+	if h.with != nil {
+		handler = h.with(handler)
+	}
+	return handler
+}
+
+func (h forwardHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+func (h forwardHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.current().Handle(ctx, record)
+}
+
+func (h forwardHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.chain(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h forwardHandler) WithGroup(name string) slog.Handler {
+	return h.chain(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h forwardHandler) chain(step func(slog.Handler) slog.Handler) slog.Handler {
+	previous := h.with
+	return forwardHandler{with: func(next slog.Handler) slog.Handler {
+		if previous != nil {
+			next = previous(next)
+		}
+		return step(next)
+	}}
+}
@@ -0,0 +1,89 @@
+package applog
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoggerCreatedBeforeSetupWritesToFile(t *testing.T) {
+	logger := For("ssh").With("host", "10.0.0.1")
+	dir := t.TempDir()
+	if err := Setup(dir); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	t.Cleanup(Close)
+	SetLevel(slog.LevelInfo)
+
+	logger.Debug("hidden")
+	logger.Info("connected", "user", "root")
+	data, err := os.ReadFile(filepath.Join(dir, FileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	if strings.Contains(got, "hidden") {
+		t.Fatalf("debug record written at info level: %q", got)
+	}
+	for _, want := range []string{"msg=connected", "component=ssh", "host=10.0.0.1", "user=root"} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("log %q missing %q", got, want)
+		}
+	}
+	recent := Recent()
+	if len(recent) == 0 || !strings.Contains(recent[len(recent)-1], "msg=connected") {
+		t.Fatalf("Recent() = %q", recent)
+	}
+	if Path() != filepath.Join(dir, FileName) {
+		t.Fatalf("Path() = %q", Path())
+	}
+}
+
+func TestRotatingFileKeepsBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	r, err := openRotating(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	for _, line := range []string{"first-0\n", "second\n", "third-0\n", "fourth\n"} {
+		if _, err := r.Write([]byte(line)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, want := range map[string]string{path: "fourth\n", path + ".1": "third-0\n", path + ".2": "second\n"} {
+		data, err := os.ReadFile(name)
+		if err != nil || string(data) != want {
+			t.Fatalf("%s = %q, %v; want %q", filepath.Base(name), data, err, want)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Fatal("only two backups should be kept")
+	}
+}
+
+func TestRingKeepsNewestLines(t *testing.T) {
+	r := newRing(3)
+	for _, line := range []string{"a\n", "b\nc\n", "d\n"} {
+		r.Write([]byte(line))
+	}
+	if got := strings.Join(r.lines(), ","); got != "b,c,d" {
+		t.Fatalf("lines() = %q", got)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	for _, name := range Levels {
+		if _, ok := ParseLevel(name); !ok {
+			t.Fatalf("ParseLevel(%q) failed", name)
+		}
+	}
+	if l, ok := ParseLevel("WARN"); !ok || l != slog.LevelWarn {
+		t.Fatalf("ParseLevel(WARN) = %v, %v", l, ok)
+	}
+	if _, ok := ParseLevel("verbose"); ok {
+		t.Fatal("unknown level should fail")
+	}
+}
@@ -66,6 +66,23 @@ build_desktop() {
     fi
 }
 
+# 无界面命令行构建（用于服务器与 CI，不依赖图形环境）
+build_headless() {
+    echo "=========================================="
+    echo "  构建无界面命令行"
+    echo "=========================================="
+
+    go build -trimpath -buildvcs=false -ldflags="-checklinkname=0 -s -w -buildid=" -o goecs-headless ./cmd/goecs-headless
+
+    if [ $? -eq 0 ]; then
+        echo "✓ 无界面命令行编译成功！"
+        ls -lh goecs-headless
+    else
+        echo "✗ 无界面命令行编译失败"
+        exit 1
+    fi
+}
+
 # 获取版本信息
 get_app_version() {
     local version
@@ -227,6 +244,9 @@ case "$BUILD_TYPE" in
     "desktop")
         build_desktop
         ;;
+    "headless")
+        build_headless
+        ;;
     "android")
         check_fyne_cli
         build_android
@@ -257,9 +277,10 @@ case "$BUILD_TYPE" in
         build_android
         ;;
     *)
-        echo "用法: $0 [desktop|android|macos|windows|linux|all]"
+        echo "用法: $0 [desktop|headless|android|macos|windows|linux|all]"
         echo ""
         echo "  desktop - 构建桌面端应用（默认，用于快速测试）"
+        echo "  headless - 构建无界面命令行（服务器与 CI）"
         echo "  android - 构建 Android APK (arm64 + x86_64)"
         echo "  macos   - 构建 macOS 应用 (arm64 + amd64)"
         echo "  windows - 构建 Windows 应用 (arm64 + amd64)"
@@ -0,0 +1,66 @@
+// Package childproc 登记由工具页、容器目标与自动更新等自行管理的子进程。本机测试中 goecs 在进程内直接派生
+// fio、dd 等工具，停止测试或阶段超时时只能按父进程找出它们；登记过的进程由各自的启动者通过 context 结束，不在其列。
+package childproc
+
+import (
+	"bytes"
+	"os/exec"
+	"sync"
+)
+
+var (
+	mu    sync.Mutex
+	owned = map[int]struct{}{}
+)
+
+// Start 启动 cmd 并登记其进程，需以 Wait 等待结束
+func Start(cmd *exec.Cmd) error {
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	mu.Lock()
+	owned[cmd.Process.Pid] = struct{}{}
+	mu.Unlock()
+	return nil
+}
+
+// Wait 等待 Start 启动的进程结束并取消登记
+func Wait(cmd *exec.Cmd) error {
+	err := cmd.Wait()
+	mu.Lock()
+	delete(owned, cmd.Process.Pid)
+	mu.Unlock()
+	return err
+}
+
+// Run 与 exec.Cmd.Run 相同，运行期间登记进程
+func Run(cmd *exec.Cmd) error {
+	if err := Start(cmd); err != nil {
+		return err
+	}
+	return Wait(cmd)
+}
+
+// Output 与 exec.Cmd.Output 相同，返回标准输出
+func Output(cmd *exec.Cmd) ([]byte, error) {
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := Run(cmd)
+	return stdout.Bytes(), err
+}
+
+// CombinedOutput 与 exec.Cmd.CombinedOutput 相同，返回标准输出与标准错误
+func CombinedOutput(cmd *exec.Cmd) ([]byte, error) {
+	var out bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &out, &out
+	err := Run(cmd)
+	return out.Bytes(), err
+}
+
+// Owned 报告 pid 是否为登记中的进程
+func Owned(pid int) bool {
+	mu.Lock()
+	defer mu.Unlock()
+	_, ok := owned[pid]
+	return ok
+}
@@ -0,0 +1,41 @@
+package childproc
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestRunRegistersWhileRunning(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sleep")
+	}
+	cmd := exec.Command("sleep", "5")
+	if err := Start(cmd); err != nil {
+		t.Skip("sleep unavailable:", err)
+	}
+	pid := cmd.Process.Pid
+	if !Owned(pid) {
+		t.Fatal("started process should be registered")
+	}
+	cmd.Process.Kill()
+	Wait(cmd)
+	if Owned(pid) {
+		t.Fatal("finished process should be released")
+	}
+}
+
+func TestCombinedOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	out, err := CombinedOutput(exec.Command("sh", "-c", "echo out; echo err >&2"))
+	if err != nil || !strings.Contains(string(out), "out") || !strings.Contains(string(out), "err") {
+		t.Fatalf("CombinedOutput() = %q, %v", out, err)
+	}
+	out, err = Output(exec.Command("sh", "-c", "echo out; echo err >&2"))
+	if err != nil || strings.TrimSpace(string(out)) != "out" {
+		t.Fatalf("Output() = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,74 @@
+// goecs-headless 不打开窗口运行融合怪测试，适用于 CI 与没有图形环境的服务器。
+// 它与图形界面共用执行器、结果解析与导出代码，并读取图形界面保存的设置。
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/oneclickvirt/ecs-gui/internal/appmeta"
+	"github.com/oneclickvirt/ecs-gui/ui"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	opts, showVersion, err := parseFlags(args, stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return ui.HeadlessExitDone
+	}
+	if err != nil {
+		return ui.HeadlessExitUsage
+	}
+	if showVersion {
+		fmt.Fprintf(stdout, "%s %s (upstream ecs %s)\n", appmeta.AppName, appmeta.Version, appmeta.UpstreamECSVersion)
+		return ui.HeadlessExitDone
+	}
+	opts.Stdout, opts.Stderr = stdout, stderr
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return ui.RunHeadless(ctx, opts)
+}
+
+// parseFlags 解析命令行参数；未指定 -config 时使用图形界面自动保存的设置（若存在）
+func parseFlags(args []string, stderr io.Writer) (opts ui.HeadlessOptions, showVersion bool, err error) {
+	flags := flag.NewFlagSet("goecs-headless", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	var tests string
+	flags.StringVar(&opts.SettingsPath, "config", "", "设置文件（图形界面导出的设置或 settings.json），默认使用图形界面保存的设置")
+	flags.StringVar(&tests, "tests", "", "逗号分隔的测试项，覆盖设置文件: "+strings.Join(ui.HeadlessTestKeys(), ","))
+	flags.StringVar(&opts.Language, "lang", "", "界面与输出语言: zh、en 或 auto")
+	flags.StringVar(&opts.OutputDir, "out", "", "结果目录，写入 results.json、results.csv、results.md、results.html 与 report.json，远程运行取回的文件写入 remote 子目录")
+	flags.StringVar(&opts.DataDir, "data-dir", ui.DefaultDataDir(), "应用数据目录（SSH known_hosts 所在位置）")
+	flags.BoolVar(&showVersion, "version", false, "显示版本信息")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "用法: goecs-headless [选项]\n\n远程测试的 SSH 密码从环境变量 %s 读取。\n退出码: 0 完成, 1 失败或超时, 2 参数错误, 3 部分完成, 130 已中断\n\n选项:\n", ui.HeadlessRemotePasswordEnv)
+		flags.PrintDefaults()
+	}
+	if err = flags.Parse(args); err != nil {
+		return opts, false, err
+	}
+	if flags.NArg() > 0 {
+		fmt.Fprintf(stderr, "unexpected argument %q\n", flags.Arg(0))
+		return opts, false, errors.New("unexpected argument")
+	}
+	if tests != "" {
+		opts.Tests = strings.Split(tests, ",")
+	}
+	if opts.SettingsPath == "" {
+		if _, statErr := os.Stat(ui.DefaultSettingsPath()); statErr == nil {
+			opts.SettingsPath = ui.DefaultSettingsPath()
+		}
+	}
+	return opts, showVersion, nil
+}
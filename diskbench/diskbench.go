@@ -0,0 +1,370 @@
+// Package diskbench 按用户指定的块大小与测试文件大小调用 fio 做随机读写测试，
+// 输出与 ecs 硬盘测试相同格式的表格，并在写入前检查目标磁盘的剩余空间。
+package diskbench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/disk"
+)
+
+// DefaultBlockSizes 与 ecs 默认的 fio 测试一致
+var DefaultBlockSizes = []string{"4k", "64k", "512k", "1m"}
+
+const (
+	// DefaultSize 是未指定测试文件大小时的 fio --size
+	DefaultSize = 2 << 30
+	minSize     = 16 << 20
+	maxSize     = 64 << 30
+	// fio 单个块大小的上下限
+	minBlockSize = 512
+	maxBlockSize = 64 << 20
+)
+
+var sizePattern = regexp.MustCompile(`^(\d+(?:\.\d+)?)\s*([kKmMgGtT]?)(?:i?[bB])?$`)
+
+// parseBytes 解析 "4k"、"512M"、"1.5G" 这类以 1024 为进制的大小
+func parseBytes(text string) (int64, error) {
+	match := sizePattern.FindStringSubmatch(strings.TrimSpace(text))
+	if match == nil {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+	value, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q", text)
+	}
+	shift := map[string]uint{"": 0, "k": 10, "m": 20, "g": 30, "t": 40}[strings.ToLower(match[2])]
+	return int64(value * float64(uint64(1)<<shift)), nil
+}
+
+// ParseBlockSizes 解析逗号或空格分隔的块大小列表，空文本返回默认块大小
+func ParseBlockSizes(text string) ([]string, error) {
+	fields := strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ' ' || r == ';' })
+	if len(fields) == 0 {
+		return append([]string(nil), DefaultBlockSizes...), nil
+	}
+	sizes := make([]string, 0, len(fields))
+	for _, field := range fields {
+		bytes, err := parseBytes(field)
+		if err != nil || strings.Contains(field, ".") {
+			return nil, fmt.Errorf("invalid block size %q", field)
+		}
+		if bytes < minBlockSize || bytes > maxBlockSize {
+			return nil, fmt.Errorf("block size %q must be between 512 and 64m", field)
+		}
+		sizes = append(sizes, formatBlockSize(bytes))
+	}
+	return sizes, nil
+}
+
+// formatBlockSize 把块大小统一写成 fio 的 "4k"、"1m" 形式
+func formatBlockSize(bytes int64) string {
+	switch {
+	case bytes%(1<<20) == 0:
+		return fmt.Sprintf("%dm", bytes>>20)
+	case bytes%(1<<10) == 0:
+		return fmt.Sprintf("%dk", bytes>>10)
+	}
+	return strconv.FormatInt(bytes, 10)
+}
+
+// ParseSize 解析测试文件大小，空文本返回 0（由 Run 使用默认值）
+func ParseSize(text string) (int64, error) {
+	if strings.TrimSpace(text) == "" {
+		return 0, nil
+	}
+	size, err := parseBytes(text)
+	if err != nil {
+		return 0, err
+	}
+	if size < minSize || size > maxSize {
+		return 0, fmt.Errorf("test file size %q must be between 16M and 64G", text)
+	}
+	return size, nil
+}
+
+// FormatSize 把字节数写成 fio 接受的 "512M"、"2G" 形式
+func FormatSize(size int64) string {
+	if size%(1<<30) == 0 {
+		return fmt.Sprintf("%dG", size>>30)
+	}
+	return fmt.Sprintf("%dM", size>>20)
+}
+
+// Options 是一次自定义 fio 测试的参数
+type Options struct {
+	// Path 为测试目录，空表示 DefaultPath()
+	Path       string
+	BlockSizes []string
+	// Size 为测试文件大小（字节），0 表示 DefaultSize
+	Size int64
+}
+
+func (o Options) path() string {
+	if o.Path != "" {
+		return o.Path
+	}
+	return DefaultPath()
+}
+
+// FileSize 返回实际使用的测试文件大小
+func (o Options) FileSize() int64 {
+	if o.Size > 0 {
+		return o.Size
+	}
+	return DefaultSize
+}
+
+// DefaultPath 与 ecs 的默认测试目录一致：Windows 为用户目录，其他系统为 /root，不可写时退回临时目录
+func DefaultPath() string {
+	if runtime.GOOS == "windows" {
+		if profile := os.Getenv("USERPROFILE"); profile != "" {
+			return profile
+		}
+		return os.TempDir()
+	}
+	if file, err := os.CreateTemp("/root", ".diskbench-*"); err == nil {
+		file.Close()
+		os.Remove(file.Name())
+		return "/root"
+	}
+	return os.TempDir()
+}
+
+// Space 是测试目录所在磁盘的容量
+type Space struct {
+	Path  string
+	Total uint64
+	Free  uint64
+}
+
+// UsedPercent 返回已用比例（0-100）
+func (s Space) UsedPercent() float64 {
+	if s.Total == 0 {
+		return 0
+	}
+	return float64(s.Total-s.Free) * 100 / float64(s.Total)
+}
+
+// ErrNearlyFull 表示写入测试文件后磁盘将几乎写满
+var ErrNearlyFull = errors.New("disk is nearly full")
+
+// diskUsage 读取磁盘容量，测试中替换
+var diskUsage = func(path string) (total, free uint64, err error) {
+	usage, err := disk.Usage(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	return usage.Total, usage.Free, nil
+}
+
+// CheckSpace 检查测试目录的剩余空间：剩余空间不足测试文件的两倍，
+// 或写入后剩余不到总容量的 10% 时返回包装了 ErrNearlyFull 的错误
+func CheckSpace(opts Options) (Space, error) {
+	space := Space{Path: opts.path()}
+	total, free, err := diskUsage(space.Path)
+	if err != nil {
+		return space, err
+	}
+	space.Total, space.Free = total, free
+	size := uint64(opts.FileSize())
+	if free < 2*size || (total > 0 && free-size < total/10) {
+		return space, fmt.Errorf("%w: %s has %s free of %s (%.0f%% used), test file is %s",
+			ErrNearlyFull, space.Path, FormatSize(int64(free)), FormatSize(int64(total)), space.UsedPercent(), FormatSize(opts.FileSize()))
+	}
+	return space, nil
+}
+
+// sizeStep 是缩小测试文件时的取整单位
+const sizeStep = 16 << 20
+
+// Shrink 返回在 space 中写入后仍满足 CheckSpace 要求的最大测试文件大小（不超过原大小，按 16M 向下取整）；
+// 连最小的 16M 测试文件都放不下时返回 0
+func Shrink(space Space, opts Options) int64 {
+	limit := space.Free / 2
+	if reserve := space.Total / 10; space.Free > reserve {
+		limit = min(limit, space.Free-reserve)
+	} else {
+		return 0
+	}
+	size := min(int64(limit), opts.FileSize()) / sizeStep * sizeStep
+	if size < minSize {
+		return 0
+	}
+	return size
+}
+
+// SizeLine 输出说明测试文件大小的一行，结果解析器据此显示实际大小；from 大于 0 时注明由该大小缩小而来
+func SizeLine(language string, size, from int64) string {
+	if language == "zh" {
+		if from > 0 {
+			return fmt.Sprintf(" 测试文件大小: %s（剩余空间不足，已由 %s 缩小）\n", FormatSize(size), FormatSize(from))
+		}
+		return fmt.Sprintf(" 测试文件大小: %s\n", FormatSize(size))
+	}
+	if from > 0 {
+		return fmt.Sprintf(" Test file size: %s (shrunk from %s, low free space)\n", FormatSize(size), FormatSize(from))
+	}
+	return fmt.Sprintf(" Test file size: %s\n", FormatSize(size))
+}
+
+// Args 返回测试一个块大小的 fio 参数；--minimal 输出便于解析
+func Args(opts Options, blockSize, ioEngine string) []string {
+	direct := "1"
+	if runtime.GOOS == "darwin" || runtime.GOOS == "windows" {
+		direct = "0"
+	}
+	return []string{
+		"--name=rand_rw_" + blockSize,
+		"--ioengine=" + ioEngine,
+		"--rw=randrw",
+		"--rwmixread=50",
+		"--bs=" + blockSize,
+		"--iodepth=64",
+		"--numjobs=2",
+		"--size=" + FormatSize(opts.FileSize()),
+		"--runtime=30",
+		"--direct=" + direct,
+		"--filename=" + filepath.Join(opts.path(), "test.fio"),
+		"--group_reporting",
+		"--minimal",
+	}
+}
+
+// Row 是一个块大小的读写结果，带宽单位为 KB/s
+type Row struct {
+	BlockSize string
+	ReadKBps  float64
+	WriteKBps float64
+	ReadIOPS  float64
+	WriteIOPS float64
+}
+
+// ParseMinimal 从 fio --minimal 输出中取出指定任务的读写带宽与 IOPS
+func ParseMinimal(output, blockSize string) (Row, bool) {
+	for _, line := range strings.Split(output, "\n") {
+		if !strings.Contains(line, "rand_rw_"+blockSize+";") {
+			continue
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) <= 48 {
+			continue
+		}
+		row := Row{BlockSize: blockSize}
+		row.ReadKBps, _ = strconv.ParseFloat(fields[6], 64)
+		row.ReadIOPS, _ = strconv.ParseFloat(fields[7], 64)
+		row.WriteKBps, _ = strconv.ParseFloat(fields[47], 64)
+		row.WriteIOPS, _ = strconv.ParseFloat(fields[48], 64)
+		return row, true
+	}
+	return Row{}, false
+}
+
+func formatSpeed(kbps float64) string {
+	switch {
+	case kbps >= 1000000:
+		return fmt.Sprintf("%.2f GB/s", kbps/1000000)
+	case kbps >= 1000:
+		return fmt.Sprintf("%.2f MB/s", kbps/1000)
+	}
+	return fmt.Sprintf("%.2f KB/s", kbps)
+}
+
+func formatIOPS(iops float64) string {
+	if iops >= 10000 {
+		return fmt.Sprintf("%.1fk", iops/1000)
+	}
+	return strconv.FormatFloat(iops, 'f', 0, 64)
+}
+
+// Render 输出与 ecs fio 测试相同列的表格，结果解析器可直接识别
+func Render(language, path string, rows []Row) string {
+	if len(rows) == 0 {
+		return ""
+	}
+	width := max(15, len(path))
+	var b strings.Builder
+	if language == "zh" {
+		fmt.Fprintf(&b, "%-*s   %-7s   %-20s %-20s %-20s\n", width, "测试路径", "块大小", "读测试(IOPS)", "写测试(IOPS)", "总和(IOPS)")
+	} else {
+		fmt.Fprintf(&b, "%-*s   %-7s   %-20s %-20s %-20s\n", width, "Test Path", "Block", "Read(IOPS)", "Write(IOPS)", "Total(IOPS)")
+	}
+	for _, row := range rows {
+		fmt.Fprintf(&b, "%-*s   %-7s   %-23s %-23s %-23s\n", width, path, row.BlockSize,
+			formatSpeed(row.ReadKBps)+"("+formatIOPS(row.ReadIOPS)+")",
+			formatSpeed(row.WriteKBps)+"("+formatIOPS(row.WriteIOPS)+")",
+			formatSpeed(row.ReadKBps+row.WriteKBps)+"("+formatIOPS(row.ReadIOPS+row.WriteIOPS)+")")
+	}
+	return b.String()
+}
+
+// ioEngines 是各系统按优先级尝试的异步 IO 引擎，都不可用时使用 psync
+func ioEngines() []string {
+	switch runtime.GOOS {
+	case "linux":
+		return []string{"io_uring", "libaio", "posixaio", "psync"}
+	case "windows":
+		return []string{"windowsaio", "psync"}
+	}
+	return []string{"posixaio", "psync"}
+}
+
+// Run 使用 fio 命令（可能带 sudo 等前缀）依次测试各块大小，结束后删除测试文件。
+// 部分块大小失败时返回已有结果和第一个错误
+func Run(ctx context.Context, fio []string, language string, opts Options) (string, error) {
+	if len(fio) == 0 {
+		return "", errors.New("fio command is empty")
+	}
+	if len(opts.BlockSizes) == 0 {
+		opts.BlockSizes = DefaultBlockSizes
+	}
+	path := opts.path()
+	if err := os.MkdirAll(path, 0o755); err != nil {
+		return "", err
+	}
+	defer os.Remove(filepath.Join(path, "test.fio"))
+
+	engine := ""
+	var rows []Row
+	var firstErr error
+	for _, blockSize := range opts.BlockSizes {
+		if ctx.Err() != nil {
+			return Render(language, path, rows), ctx.Err()
+		}
+		engines := ioEngines()
+		if engine != "" {
+			engines = []string{engine}
+		}
+		var row Row
+		var err error
+		for _, candidate := range engines {
+			var output []byte
+			output, err = exec.CommandContext(ctx, fio[0], append(fio[1:], Args(opts, blockSize, candidate)...)...).CombinedOutput()
+			var ok bool
+			if row, ok = ParseMinimal(string(output), blockSize); ok {
+				engine, err = candidate, nil
+				break
+			}
+			if err == nil {
+				err = fmt.Errorf("fio output contains no result for block size %s", blockSize)
+			}
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		rows = append(rows, row)
+	}
+	return Render(language, path, rows), firstErr
+}
@@ -0,0 +1,119 @@
+package diskbench
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseBlockSizes(t *testing.T) {
+	sizes, err := ParseBlockSizes("4K, 16kb 1MiB;512")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(sizes, ",") != "4k,16k,1m,512" {
+		t.Fatalf("ParseBlockSizes() = %v", sizes)
+	}
+	if sizes, err := ParseBlockSizes("  "); err != nil || strings.Join(sizes, ",") != "4k,64k,512k,1m" {
+		t.Fatalf("ParseBlockSizes(empty) = %v, %v", sizes, err)
+	}
+	for _, text := range []string{"4x", "1.5k", "256", "128m"} {
+		if _, err := ParseBlockSizes(text); err == nil {
+			t.Errorf("ParseBlockSizes(%q) accepted", text)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{"": 0, "512M": 512 << 20, "1.5G": 3 << 29, "2gb": 2 << 30}
+	for text, want := range cases {
+		if got, err := ParseSize(text); err != nil || got != want {
+			t.Errorf("ParseSize(%q) = %d, %v, want %d", text, got, err, want)
+		}
+	}
+	for _, text := range []string{"1M", "100G", "big"} {
+		if _, err := ParseSize(text); err == nil {
+			t.Errorf("ParseSize(%q) accepted", text)
+		}
+	}
+	if FormatSize(2<<30) != "2G" || FormatSize(1536<<20) != "1536M" {
+		t.Fatalf("FormatSize() = %s, %s", FormatSize(2<<30), FormatSize(1536<<20))
+	}
+}
+
+func TestCheckSpace(t *testing.T) {
+	old := diskUsage
+	t.Cleanup(func() { diskUsage = old })
+	diskUsage = func(string) (uint64, uint64, error) { return 100 << 30, 50 << 30, nil }
+	if _, err := CheckSpace(Options{Path: "/data", Size: 1 << 30}); err != nil {
+		t.Fatalf("CheckSpace(half free) = %v", err)
+	}
+	diskUsage = func(string) (uint64, uint64, error) { return 100 << 30, 10 << 30, nil }
+	space, err := CheckSpace(Options{Path: "/data", Size: 1 << 30})
+	if !errors.Is(err, ErrNearlyFull) || space.UsedPercent() != 90 {
+		t.Fatalf("CheckSpace(90%% used) = %+v, %v", space, err)
+	}
+	diskUsage = func(string) (uint64, uint64, error) { return 1 << 40, 3 << 30, nil }
+	if _, err := CheckSpace(Options{Path: "/data"}); !errors.Is(err, ErrNearlyFull) {
+		t.Fatalf("CheckSpace(less than twice the default size) = %v", err)
+	}
+}
+
+func TestShrink(t *testing.T) {
+	old := diskUsage
+	t.Cleanup(func() { diskUsage = old })
+	// 20G 的盘剩 3G：原 2G 放不下，缩小后写入需同时满足剩余两倍与保留 10%
+	space := Space{Path: "/data", Total: 20 << 30, Free: 3 << 30}
+	size := Shrink(space, Options{Path: "/data"})
+	if size != 1<<30 {
+		t.Fatalf("Shrink() = %s", FormatSize(size))
+	}
+	diskUsage = func(string) (uint64, uint64, error) { return space.Total, space.Free, nil }
+	if _, err := CheckSpace(Options{Path: "/data", Size: size}); err != nil {
+		t.Fatalf("CheckSpace(shrunk) = %v", err)
+	}
+	if got := Shrink(Space{Total: 100 << 30, Free: 50 << 30}, Options{Size: 512 << 20}); got != 512<<20 {
+		t.Fatalf("Shrink() should not grow the test file: %s", FormatSize(got))
+	}
+	if got := Shrink(Space{Total: 100 << 30, Free: 10<<30 + 8<<20}, Options{}); got != 0 {
+		t.Fatalf("Shrink(nearly full) = %s, want 0", FormatSize(got))
+	}
+	if line := SizeLine("zh", size, DefaultSize); line != " 测试文件大小: 1G（剩余空间不足，已由 2G 缩小）\n" {
+		t.Fatalf("SizeLine() = %q", line)
+	}
+	if line := SizeLine("en", 512<<20, 0); line != " Test file size: 512M\n" {
+		t.Fatalf("SizeLine() = %q", line)
+	}
+}
+
+func TestParseMinimalAndRender(t *testing.T) {
+	fields := make([]string, 60)
+	fields[2] = "rand_rw_4k"
+	fields[6], fields[7] = "51200", "12800"
+	fields[47], fields[48] = "51000", "12750"
+	row, ok := ParseMinimal("fio-3.36\n"+strings.Join(fields, ";"), "4k")
+	if !ok || row.ReadKBps != 51200 || row.WriteIOPS != 12750 {
+		t.Fatalf("ParseMinimal() = %+v, %v", row, ok)
+	}
+	if _, ok := ParseMinimal(strings.Join(fields, ";"), "64k"); ok {
+		t.Fatal("ParseMinimal() matched another block size")
+	}
+	out := Render("en", "/data", []Row{row})
+	for _, want := range []string{"Test Path", "/data", "51.20 MB/s(12.8k)", "102.20 MB/s(25.6k)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Render() missing %q:\n%s", want, out)
+		}
+	}
+	if Render("zh", "/data", nil) != "" {
+		t.Fatal("Render() without rows should be empty")
+	}
+}
+
+func TestArgs(t *testing.T) {
+	args := strings.Join(Args(Options{Path: "/data", Size: 512 << 20}, "64k", "libaio"), " ")
+	for _, want := range []string{"--name=rand_rw_64k", "--bs=64k", "--size=512M", "--ioengine=libaio", "test.fio", "--minimal"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("Args() missing %q: %s", want, args)
+		}
+	}
+}
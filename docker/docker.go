@@ -0,0 +1,265 @@
+// Package docker 通过 docker 命令行在容器中准备并运行 goecs。
+// 不依赖 Docker SDK：远程 Docker 主机通过 DOCKER_HOST（如 ssh://user@host）访问。
+package docker
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/childproc"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// WorkDir 是容器内存放 goecs 的目录，goecs 的结果文件也写在这里
+const WorkDir = "/tmp/.goecs-gui"
+
+// Target 描述运行测试的容器
+type Target struct {
+	Container string `json:"container"`
+	// Host 为 DOCKER_HOST，例如 ssh://root@example.com 或 tcp://10.0.0.1:2376；为空时使用本机默认的 Docker 环境
+	Host string `json:"host,omitempty"`
+}
+
+// Validate 检查必填字段
+func (t Target) Validate() error {
+	if strings.TrimSpace(t.Container) == "" {
+		return errors.New("docker container is empty")
+	}
+	if host := strings.TrimSpace(t.Host); host != "" && !strings.Contains(host, "://") {
+		return fmt.Errorf("invalid DOCKER_HOST %q (expected ssh://, tcp:// or unix://)", host)
+	}
+	return nil
+}
+
+// Label 返回用于显示与历史记录的名称：本机为容器名，远程为 容器名@主机名
+func (t Target) Label() string {
+	host := strings.TrimSpace(t.Host)
+	if host == "" || strings.HasPrefix(host, "unix://") || strings.HasPrefix(host, "npipe://") {
+		return t.Container
+	}
+	host = host[strings.Index(host, "://")+3:]
+	if i := strings.LastIndex(host, "@"); i >= 0 {
+		host = host[i+1:]
+	}
+	if i := strings.IndexAny(host, ":/"); i >= 0 {
+		host = host[:i]
+	}
+	return t.Container + "@" + host
+}
+
+// Container 是 docker ps 列出的一个运行中的容器
+type Container struct {
+	ID     string
+	Name   string
+	Image  string
+	Status string
+}
+
+// containerFormat 与 ParseContainers 对应
+const containerFormat = "{{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}"
+
+// ParseContainers 解析 docker ps --format containerFormat 的输出
+func ParseContainers(text string) []Container {
+	var containers []Container
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Split(strings.TrimRight(line, "\r"), "\t")
+		if len(fields) < 4 || fields[0] == "" {
+			continue
+		}
+		containers = append(containers, Container{ID: fields[0], Name: fields[1], Image: fields[2], Status: fields[3]})
+	}
+	return containers
+}
+
+// Client 调用 docker 命令行
+type Client struct {
+	// Host 为 DOCKER_HOST，为空时沿用当前环境
+	Host string
+	// Binary 为 docker 可执行文件，为空时从 PATH 查找
+	Binary string
+}
+
+// NewClient 返回访问 target 所在 Docker 主机的客户端
+func NewClient(target Target) Client {
+	return Client{Host: strings.TrimSpace(target.Host)}
+}
+
+func (c Client) command(ctx context.Context, args ...string) *exec.Cmd {
+	binary := c.Binary
+	if binary == "" {
+		binary = "docker"
+	}
+	cmd := exec.CommandContext(ctx, binary, args...)
+	if c.Host != "" {
+		cmd.Env = append(os.Environ(), "DOCKER_HOST="+c.Host)
+	}
+	cmd.WaitDelay = 3 * time.Second
+	return cmd
+}
+
+// output 执行 docker 子命令并返回 stdout，失败时错误中带上 stderr
+func (c Client) output(ctx context.Context, args ...string) (string, error) {
+	cmd := c.command(ctx, args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := childproc.Output(cmd)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", ctxErr
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("docker %s: %s", args[0], msg)
+		}
+		return "", fmt.Errorf("docker %s: %w", args[0], err)
+	}
+	return string(out), nil
+}
+
+// Containers 列出运行中的容器
+func (c Client) Containers(ctx context.Context) ([]Container, error) {
+	out, err := c.output(ctx, "ps", "--format", containerFormat)
+	if err != nil {
+		return nil, err
+	}
+	return ParseContainers(out), nil
+}
+
+// Output 在容器中执行一条短命令并返回 stdout
+func (c Client) Output(ctx context.Context, container string, args ...string) (string, error) {
+	return c.output(ctx, append([]string{"exec", container}, args...)...)
+}
+
+// Copy 把本机文件复制到容器内的 path
+func (c Client) Copy(ctx context.Context, local, container, path string) error {
+	_, err := c.output(ctx, "cp", local, container+":"+path)
+	return err
+}
+
+// Run 在容器的 workDir 中执行 args，分配伪终端以保留彩色输出，stdout/stderr 实时交给 output。
+// 进程号写入 workDir/goecs.pid；ctx 取消时先向该进程发送 SIGINT，3 秒后结束 docker 命令。
+func (c Client) Run(ctx context.Context, container, workDir string, args []string, output func(string)) error {
+	return c.RunWithInput(ctx, container, workDir, args, nil, output)
+}
+
+// RunWithInput 与 Run 相同，另把 input 中的文本写入进程的标准输入，用于回应交互式提示；input 为 nil 时不接标准输入
+func (c Client) RunWithInput(ctx context.Context, container, workDir string, args []string, input <-chan string, output func(string)) error {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = remote.Quote(arg)
+	}
+	pidFile := workDir + "/goecs.pid"
+	script := fmt.Sprintf("echo $$ > %s && exec %s", remote.Quote(pidFile), strings.Join(quoted, " "))
+
+	execArgs := []string{"exec", "-t"}
+	if input != nil {
+		execArgs = append(execArgs, "-i")
+	}
+	cmd := c.command(context.WithoutCancel(ctx), append(execArgs, "-w", workDir, container, "sh", "-c", script)...)
+	writer := &callbackWriter{output: output}
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if input != nil {
+		// docker 命令退出后 Wait 会关闭该管道，转发随之结束
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		go remote.ForwardInput(stdin, input, stop)
+	}
+	if err := childproc.Start(cmd); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- childproc.Wait(cmd) }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		interrupt, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, _ = c.Output(interrupt, container, "sh", "-c", "kill -INT $(cat "+remote.Quote(pidFile)+")")
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+			_ = cmd.Process.Kill()
+			<-done
+		}
+		return ctx.Err()
+	}
+}
+
+// Prepare 确保容器内存在 goecs：localBinary 非空时复制本地文件；否则按容器架构选择发布包，
+// fetch 非空时在本机下载校验后复制进容器，失败或未提供时由容器直接下载。返回容器内可执行文件的路径。
+func Prepare(ctx context.Context, c Client, container, version, localBinary string, fetch remote.Fetcher, output func(string)) (string, error) {
+	emit := func(text string) {
+		if output != nil {
+			output(text)
+		}
+	}
+	binary := WorkDir + "/goecs"
+	if _, err := c.Output(ctx, container, "mkdir", "-p", WorkDir); err != nil {
+		return "", fmt.Errorf("create work dir in container: %w", err)
+	}
+	install := func(local string) (string, error) {
+		emit(fmt.Sprintf("docker cp %s %s:%s\n", local, container, binary))
+		if err := c.Copy(ctx, local, container, binary); err != nil {
+			return "", err
+		}
+		if _, err := c.Output(ctx, container, "chmod", "+x", binary); err != nil {
+			return "", err
+		}
+		return binary, nil
+	}
+	if localBinary = strings.TrimSpace(localBinary); localBinary != "" {
+		return install(localBinary)
+	}
+
+	uname, err := c.Output(ctx, container, "uname", "-sm")
+	if err != nil {
+		return "", fmt.Errorf("detect container system: %w", err)
+	}
+	asset, err := remote.ReleaseAsset(uname)
+	if err != nil {
+		return "", err
+	}
+	if fetch != nil {
+		emit(fmt.Sprintf("fetch %s %s\n", asset, version))
+		local, err := fetch(ctx, version, asset)
+		if err == nil {
+			return install(local)
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		emit(fmt.Sprintf("local download failed: %v\n", err))
+	}
+	emit(fmt.Sprintf("download %s %s -> %s\n", asset, version, binary))
+	if err := c.Run(ctx, container, WorkDir, []string{"sh", "-c", remote.InstallCommand(version, asset, WorkDir)}, output); err != nil {
+		return "", fmt.Errorf("install goecs in container: %w", err)
+	}
+	return binary, nil
+}
+
+type callbackWriter struct {
+	mu     sync.Mutex
+	output func(string)
+}
+
+func (w *callbackWriter) Write(p []byte) (int, error) {
+	if w.output != nil && len(p) > 0 {
+		w.mu.Lock()
+		w.output(strings.ReplaceAll(string(p), "\r\n", "\n"))
+		w.mu.Unlock()
+	}
+	return len(p), nil
+}
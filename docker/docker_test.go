@@ -0,0 +1,114 @@
+package docker
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestTargetLabelAndValidate(t *testing.T) {
+	cases := map[Target]string{
+		{Container: "web"}: "web",
+		{Container: "web", Host: "unix:///var/run/docker.sock"}: "web",
+		{Container: "web", Host: "ssh://root@vps.example.com"}:  "web@vps.example.com",
+		{Container: "db", Host: "tcp://10.0.0.5:2376"}:          "db@10.0.0.5",
+	}
+	for target, want := range cases {
+		if got := target.Label(); got != want {
+			t.Fatalf("Label(%+v) = %q, want %q", target, got, want)
+		}
+		if err := target.Validate(); err != nil {
+			t.Fatalf("Validate(%+v) = %v", target, err)
+		}
+	}
+	for _, target := range []Target{{}, {Container: "web", Host: "vps.example.com"}} {
+		if err := target.Validate(); err == nil {
+			t.Fatalf("Validate(%+v) should fail", target)
+		}
+	}
+}
+
+func TestParseContainers(t *testing.T) {
+	got := ParseContainers("a1b2\tweb\tnginx:1.27\tUp 3 hours\n\nbad line\nc3d4\tdb\tpostgres:16\tUp 2 minutes\r\n")
+	if len(got) != 2 || got[0] != (Container{ID: "a1b2", Name: "web", Image: "nginx:1.27", Status: "Up 3 hours"}) || got[1].Name != "db" {
+		t.Fatalf("ParseContainers = %+v", got)
+	}
+}
+
+// fakeDocker 写一个记录参数的 docker 脚本，返回客户端与记录文件
+func fakeDocker(t *testing.T) (Client, string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker script needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "calls.log")
+	script := `#!/bin/sh
+echo "$DOCKER_HOST|$*" >> ` + logFile + `
+case "$*" in
+  "ps "*) printf 'a1b2\tweb\tnginx\tUp\n' ;;
+  *"uname -sm") echo "Linux aarch64" ;;
+  "exec -t "*) echo "goecs output" ;;
+esac
+`
+	binary := filepath.Join(dir, "docker")
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	return Client{Host: "ssh://root@vps", Binary: binary}, logFile
+}
+
+func readCalls(t *testing.T, logFile string) []string {
+	t.Helper()
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(data)), "\n")
+}
+
+func TestPrepareCopiesFetchedBinaryAndRuns(t *testing.T) {
+	client, logFile := fakeDocker(t)
+	ctx := context.Background()
+	containers, err := client.Containers(ctx)
+	if err != nil || len(containers) != 1 || containers[0].Name != "web" {
+		t.Fatalf("Containers = %+v, %v", containers, err)
+	}
+
+	var fetched string
+	fetch := func(_ context.Context, version, asset string) (string, error) {
+		fetched = version + " " + asset
+		return "/cache/goecs", nil
+	}
+	binary, err := Prepare(ctx, client, "web", "0.1.171", "", fetch, nil)
+	if err != nil || binary != WorkDir+"/goecs" {
+		t.Fatalf("Prepare = %q, %v", binary, err)
+	}
+	if fetched != "0.1.171 goecs_linux_arm64.zip" {
+		t.Fatalf("fetched %q", fetched)
+	}
+
+	var out strings.Builder
+	if err := client.Run(ctx, "web", WorkDir, []string{binary, "-menu=false", "-l", "zh"}, func(s string) { out.WriteString(s) }); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "goecs output\n" {
+		t.Fatalf("Run output = %q", out.String())
+	}
+
+	calls := readCalls(t, logFile)
+	want := []string{
+		"ssh://root@vps|ps --format {{.ID}}\t{{.Names}}\t{{.Image}}\t{{.Status}}",
+		"ssh://root@vps|exec web mkdir -p " + WorkDir,
+		"ssh://root@vps|exec web uname -sm",
+		"ssh://root@vps|cp /cache/goecs web:" + WorkDir + "/goecs",
+		"ssh://root@vps|exec web chmod +x " + WorkDir + "/goecs",
+		"ssh://root@vps|exec -t -w " + WorkDir + " web sh -c echo $$ > " + WorkDir + "/goecs.pid && exec " + WorkDir + "/goecs -menu=false -l zh",
+	}
+	if strings.Join(calls, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("docker calls:\n%s\nwant:\n%s", strings.Join(calls, "\n"), strings.Join(want, "\n"))
+	}
+}
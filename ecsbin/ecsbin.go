@@ -0,0 +1,198 @@
+// Package ecsbin 从 oneclickvirt/ecs 的 GitHub Releases 下载 goecs 发布包，
+// 校验 SHA-256 后解压到本机缓存，供上传到 SSH 目标或在本机直接使用。
+package ecsbin
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/oneclickvirt/ecs-gui/internal/ghrelease"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// Repo 是 goecs 发布所在的 GitHub 仓库
+const Repo = "oneclickvirt/ecs"
+
+// maxBinarySize 限制解压出的可执行文件大小，防止异常压缩包占满磁盘
+const maxBinarySize = 256 << 20
+
+// fetchMu 串行化下载，批量测试多台同架构主机时只下载一次
+var fetchMu sync.Mutex
+
+// Releases 返回 goecs 最近 limit 个正式发布（不含草稿），按发布时间从新到旧
+func Releases(ctx context.Context, client *http.Client, limit int) ([]ghrelease.Release, error) {
+	return ghrelease.List(ctx, client, Repo, limit)
+}
+
+// ReleaseFor 返回 goecs 指定版本的发布
+func ReleaseFor(ctx context.Context, client *http.Client, version string) (ghrelease.Release, error) {
+	return ghrelease.ByTag(ctx, client, Repo, "v"+ghrelease.NormalizeVersion(version))
+}
+
+// AssetFor 返回指定系统与架构（GOOS/GOARCH 取值）的发布包名称
+func AssetFor(goos, goarch string) (string, error) {
+	if goos == "windows" {
+		if goarch != "amd64" && goarch != "arm64" && goarch != "386" {
+			return "", fmt.Errorf("unsupported architecture %q on windows", goarch)
+		}
+		return fmt.Sprintf("goecs_windows_%s.zip", goarch), nil
+	}
+	return remote.ReleaseAsset(goos + " " + goarch)
+}
+
+// LocalAsset 返回本机可运行的发布包名称
+func LocalAsset() (string, error) {
+	return AssetFor(runtime.GOOS, runtime.GOARCH)
+}
+
+// binaryName 返回发布包中的可执行文件名
+func binaryName(asset string) string {
+	if strings.Contains(asset, "_windows_") {
+		return "goecs.exe"
+	}
+	return "goecs"
+}
+
+// Cache 是按 v<版本>/<发布包名>/goecs 存放已校验可执行文件的本机目录
+type Cache struct {
+	Dir string
+	// Client 用于下载发布包，为 nil 时使用 http.DefaultClient
+	Client *http.Client
+}
+
+// Entry 是缓存中的一个可执行文件
+type Entry struct {
+	Version string
+	Asset   string
+	Path    string
+	Size    int64
+}
+
+// Path 返回指定版本与发布包在缓存中的可执行文件路径
+func (c Cache) Path(version, asset string) string {
+	return filepath.Join(c.Dir, "v"+ghrelease.NormalizeVersion(version), strings.TrimSuffix(asset, ".zip"), binaryName(asset))
+}
+
+// Lookup 返回已缓存的可执行文件
+func (c Cache) Lookup(version, asset string) (string, bool) {
+	path := c.Path(version, asset)
+	if info, err := os.Stat(path); err != nil || !info.Mode().IsRegular() {
+		return "", false
+	}
+	return path, true
+}
+
+// Entries 列出缓存中的全部可执行文件，按版本从新到旧
+func (c Cache) Entries() ([]Entry, error) {
+	matches, err := filepath.Glob(filepath.Join(c.Dir, "v*", "goecs_*", "goecs*"))
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, match := range matches {
+		info, err := os.Stat(match)
+		if err != nil || !info.Mode().IsRegular() || (info.Name() != "goecs" && info.Name() != "goecs.exe") {
+			continue
+		}
+		assetDir := filepath.Dir(match)
+		entries = append(entries, Entry{
+			Version: ghrelease.NormalizeVersion(filepath.Base(filepath.Dir(assetDir))),
+			Asset:   filepath.Base(assetDir) + ".zip",
+			Path:    match,
+			Size:    info.Size(),
+		})
+	}
+	slices.SortStableFunc(entries, func(a, b Entry) int { return ghrelease.CompareVersions(b.Version, a.Version) })
+	return entries, nil
+}
+
+// Remove 删除某个版本的全部缓存
+func (c Cache) Remove(version string) error {
+	return os.RemoveAll(filepath.Join(c.Dir, "v"+ghrelease.NormalizeVersion(version)))
+}
+
+// Fetch 返回指定版本与发布包的可执行文件路径：已缓存时直接返回，
+// 否则下载发布包、校验 SHA-256 并解压到缓存
+func (c Cache) Fetch(ctx context.Context, version, asset string) (string, error) {
+	fetchMu.Lock()
+	defer fetchMu.Unlock()
+	if path, ok := c.Lookup(version, asset); ok {
+		return path, nil
+	}
+	release, err := ReleaseFor(ctx, c.Client, version)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return "", err
+	}
+	archive, err := os.CreateTemp(c.Dir, ".download-*.zip")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(archive.Name())
+	defer archive.Close()
+	if err := ghrelease.Download(ctx, c.Client, release, asset, archive); err != nil {
+		return "", err
+	}
+	path := c.Path(version, asset)
+	if err := extract(archive, binaryName(asset), path); err != nil {
+		return "", fmt.Errorf("extract %s: %w", asset, err)
+	}
+	return path, nil
+}
+
+// extract 从 zip 中取出名为 name 的文件写到 dest，先写临时文件再改名，避免留下不完整的缓存
+func extract(archive *os.File, name, dest string) error {
+	info, err := archive.Stat()
+	if err != nil {
+		return err
+	}
+	reader, err := zip.NewReader(archive, info.Size())
+	if err != nil {
+		return err
+	}
+	for _, file := range reader.File {
+		if path.Base(file.Name) != name || file.FileInfo().IsDir() {
+			continue
+		}
+		if file.UncompressedSize64 > maxBinarySize {
+			return fmt.Errorf("%s is too large (%d bytes)", name, file.UncompressedSize64)
+		}
+		src, err := file.Open()
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+			return err
+		}
+		tmp, err := os.CreateTemp(filepath.Dir(dest), ".goecs-*")
+		if err != nil {
+			return err
+		}
+		defer os.Remove(tmp.Name())
+		_, err = io.Copy(tmp, io.LimitReader(src, maxBinarySize))
+		if closeErr := tmp.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return err
+		}
+		if err := os.Chmod(tmp.Name(), 0o755); err != nil {
+			return err
+		}
+		return os.Rename(tmp.Name(), dest)
+	}
+	return fmt.Errorf("%s not found in archive", name)
+}
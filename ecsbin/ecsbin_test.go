@@ -0,0 +1,148 @@
+package ecsbin
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/internal/ghrelease"
+)
+
+func zipWith(t *testing.T, name, content string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte(content))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// startReleaseServer 模拟 GitHub API 与发布下载，v0.1.171 提供 digest，v0.1.170 只有 checksums.txt
+func startReleaseServer(t *testing.T, archive []byte, digest string) *atomic.Int32 {
+	t.Helper()
+	sum := sha256.Sum256(archive)
+	var downloads atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		release := func(tag, digest string, extra ...ghrelease.Asset) ghrelease.Release {
+			return ghrelease.Release{Tag: tag, Assets: append([]ghrelease.Asset{{
+				Name: "goecs_linux_amd64.zip", URL: server.URL + "/dl/" + tag + "/goecs_linux_amd64.zip", Digest: digest,
+			}}, extra...)}
+		}
+		switch r.URL.Path {
+		case "/repos/oneclickvirt/ecs/releases":
+			json.NewEncoder(w).Encode([]ghrelease.Release{release("v0.1.171", digest), {Tag: "v0.1.172", Draft: true}, release("v0.1.170", "")})
+		case "/repos/oneclickvirt/ecs/releases/tags/v0.1.171":
+			json.NewEncoder(w).Encode(release("v0.1.171", digest))
+		case "/repos/oneclickvirt/ecs/releases/tags/v0.1.170":
+			json.NewEncoder(w).Encode(release("v0.1.170", "", ghrelease.Asset{Name: "checksums.txt", URL: server.URL + "/dl/v0.1.170/checksums.txt"}))
+		case "/repos/oneclickvirt/ecs/releases/tags/v0.1.169":
+			json.NewEncoder(w).Encode(release("v0.1.169", ""))
+		case "/dl/v0.1.170/checksums.txt":
+			w.Write([]byte(strings.Repeat("0", 64) + "  goecs_darwin_arm64.zip\n" + hex.EncodeToString(sum[:]) + " *goecs_linux_amd64.zip\n"))
+		case "/dl/v0.1.171/goecs_linux_amd64.zip", "/dl/v0.1.170/goecs_linux_amd64.zip", "/dl/v0.1.169/goecs_linux_amd64.zip":
+			downloads.Add(1)
+			w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	old := ghrelease.APIBaseURL
+	ghrelease.APIBaseURL = server.URL
+	t.Cleanup(func() { ghrelease.APIBaseURL = old })
+	return &downloads
+}
+
+func TestReleasesSkipsDrafts(t *testing.T) {
+	startReleaseServer(t, nil, "")
+	list, err := Releases(context.Background(), nil, 10)
+	if err != nil || len(list) != 2 || list[0].Version() != "0.1.171" || list[1].Version() != "0.1.170" {
+		t.Fatalf("Releases() = %+v, %v", list, err)
+	}
+}
+
+func TestFetchVerifiesAndCaches(t *testing.T) {
+	archive := zipWith(t, "goecs", "#!/bin/sh\necho goecs\n")
+	sum := sha256.Sum256(archive)
+	downloads := startReleaseServer(t, archive, "sha256:"+hex.EncodeToString(sum[:]))
+	cache := Cache{Dir: t.TempDir()}
+
+	for _, version := range []string{"v0.1.171", "0.1.170"} {
+		path, err := cache.Fetch(context.Background(), version, "goecs_linux_amd64.zip")
+		if err != nil {
+			t.Fatalf("Fetch(%s) error = %v", version, err)
+		}
+		data, _ := os.ReadFile(path)
+		info, _ := os.Stat(path)
+		if string(data) != "#!/bin/sh\necho goecs\n" || info.Mode().Perm()&0o100 == 0 {
+			t.Fatalf("Fetch(%s) wrote %q with mode %v", version, data, info.Mode())
+		}
+	}
+	// 再次获取使用缓存，不重复下载
+	if _, err := cache.Fetch(context.Background(), "0.1.171", "goecs_linux_amd64.zip"); err != nil || downloads.Load() != 2 {
+		t.Fatalf("cached Fetch() error = %v, downloads = %d", err, downloads.Load())
+	}
+
+	// 没有校验和的发布拒绝下载
+	if _, err := cache.Fetch(context.Background(), "0.1.169", "goecs_linux_amd64.zip"); !errors.Is(err, ghrelease.ErrNoChecksum) {
+		t.Fatalf("Fetch(no checksum) error = %v", err)
+	}
+
+	entries, err := cache.Entries()
+	if err != nil || len(entries) != 2 || entries[0].Version != "0.1.171" || entries[1].Asset != "goecs_linux_amd64.zip" {
+		t.Fatalf("Entries() = %+v, %v", entries, err)
+	}
+	if err := cache.Remove("0.1.171"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Lookup("0.1.171", "goecs_linux_amd64.zip"); ok {
+		t.Fatal("removed version is still cached")
+	}
+}
+
+func TestFetchRejectsChecksumMismatch(t *testing.T) {
+	startReleaseServer(t, zipWith(t, "goecs", "tampered"), "sha256:"+strings.Repeat("ab", 32))
+	cache := Cache{Dir: t.TempDir()}
+	if _, err := cache.Fetch(context.Background(), "0.1.171", "goecs_linux_amd64.zip"); !errors.Is(err, ghrelease.ErrChecksumMismatch) {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if entries, _ := cache.Entries(); len(entries) != 0 {
+		t.Fatalf("mismatched download was cached: %+v", entries)
+	}
+}
+
+func TestAssetFor(t *testing.T) {
+	cases := map[[2]string]string{
+		{"linux", "amd64"}:   "goecs_linux_amd64.zip",
+		{"darwin", "arm64"}:  "goecs_darwin_arm64.zip",
+		{"windows", "amd64"}: "goecs_windows_amd64.zip",
+		{"windows", "mips"}:  "",
+		{"plan9", "amd64"}:   "",
+	}
+	for in, want := range cases {
+		got, err := AssetFor(in[0], in[1])
+		if (want == "") != (err != nil) || got != want {
+			t.Errorf("AssetFor(%s/%s) = %q, %v; want %q", in[0], in[1], got, err, want)
+		}
+	}
+	if got := (Cache{Dir: "c"}).Path("v1.0.0", "goecs_windows_amd64.zip"); !strings.HasSuffix(got, "goecs.exe") {
+		t.Errorf("Path(windows) = %q", got)
+	}
+}
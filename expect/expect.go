@@ -0,0 +1,177 @@
+// Package expect 按规则自动回应 ecs 后端的交互式提示（如"按回车继续"、许可协议 y/n），
+// 让无人值守与定时运行不会停在提示上。规则是匹配输出行的正则与要发送的回复，内置规则可单独停用。
+package expect
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// MaxReplies 是一次运行中自动回复的上限，防止规则与提示互相触发时无限循环
+const MaxReplies = 32
+
+// Rule 是一条回应规则：输出的一行匹配 Pattern 时发送 Reply 并换行，Reply 为空表示只按回车
+type Rule struct {
+	ID      string `json:"id,omitempty"` // 内置规则的标识，自定义规则为空
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+	Reply   string `json:"reply,omitempty"`
+}
+
+// Builtin 是内置规则，覆盖 ecs 及其调用的脚本中已知的提示
+var Builtin = []Rule{
+	{ID: "enter", Name: "Press Enter to continue", Pattern: `(?i)(press|hit)\s+(enter|return|any key)|按(回车|任意键|enter)`},
+	{ID: "eula", Name: "License agreement (y/n)", Pattern: `(?i)(accept|agree).*(\[|\()\s*y(es)?\s*/\s*no?\s*(\]|\))|同意.*(\[|\()\s*y\s*/\s*n\s*(\]|\))`, Reply: "y"},
+	{ID: "continue", Name: "Continue? (y/n)", Pattern: `(?i)(continue|proceed)\s*\?\s*(\[|\()\s*y(es)?\s*/\s*no?\s*(\]|\))|是否继续`, Reply: "y"},
+}
+
+// Settings 是自动回应设置
+type Settings struct {
+	// Off 为 true 时不自动回应任何提示
+	Off bool `json:"off,omitempty"`
+	// Disabled 是停用的内置规则 ID
+	Disabled []string `json:"disabled,omitempty"`
+	Custom   []Rule   `json:"custom,omitempty"`
+}
+
+// Rules 返回生效的规则：未停用的内置规则在前，自定义规则在后；Off 时为空
+func (s Settings) Rules() []Rule {
+	if s.Off {
+		return nil
+	}
+	var rules []Rule
+	for _, rule := range Builtin {
+		if !slices.Contains(s.Disabled, rule.ID) {
+			rules = append(rules, rule)
+		}
+	}
+	return append(rules, s.Custom...)
+}
+
+// Validate 检查自定义规则的名称与正则
+func (s Settings) Validate() error {
+	for _, rule := range s.Custom {
+		if strings.TrimSpace(rule.Name) == "" {
+			return errors.New("rule name is empty")
+		}
+		if strings.TrimSpace(rule.Pattern) == "" {
+			return fmt.Errorf("rule %q has no pattern", rule.Name)
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("rule %q: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
+
+// Responder 逐段接收输出，在某一行匹配规则时给出回复。同一行只回复一次，可在多个 goroutine 中使用
+type Responder struct {
+	rules    []Rule
+	patterns []*regexp.Regexp
+
+	mu      sync.Mutex
+	line    string // 当前尚未结束的一行
+	replied bool   // 当前行是否已经回复过
+	count   int
+}
+
+// NewResponder 按规则创建应答器，无效的正则跳过；没有可用规则时返回 nil
+func NewResponder(rules []Rule) *Responder {
+	r := &Responder{}
+	for _, rule := range rules {
+		pattern, err := regexp.Compile(rule.Pattern)
+		if err != nil || rule.Pattern == "" {
+			continue
+		}
+		r.rules = append(r.rules, rule)
+		r.patterns = append(r.patterns, pattern)
+	}
+	if len(r.rules) == 0 {
+		return nil
+	}
+	return r
+}
+
+// Feed 接收一段输出，返回需要发送的回复；提示多不换行，因此未结束的行也参与匹配
+func (r *Responder) Feed(text string) []Rule {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var replies []Rule
+	for {
+		i := strings.IndexAny(text, "\r\n")
+		if i < 0 {
+			break
+		}
+		r.line += text[:i]
+		if rule, ok := r.match(); ok {
+			replies = append(replies, rule)
+		}
+		r.line, r.replied = "", false
+		text = text[i+1:]
+	}
+	r.line += text
+	if rule, ok := r.match(); ok {
+		replies = append(replies, rule)
+	}
+	return replies
+}
+
+// match 检查当前行，调用方需持有 mu
+func (r *Responder) match() (Rule, bool) {
+	if r.replied || r.count >= MaxReplies || strings.TrimSpace(r.line) == "" {
+		return Rule{}, false
+	}
+	line := results.StripANSI(r.line)
+	for i, pattern := range r.patterns {
+		if pattern.MatchString(line) {
+			r.replied = true
+			r.count++
+			return r.rules[i], true
+		}
+	}
+	return Rule{}, false
+}
+
+// Load 读取自动回应设置，文件不存在时返回默认设置（启用全部内置规则）
+func Load(path string) (Settings, error) {
+	var settings Settings
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// Save 校验并保存自动回应设置
+func Save(path string, settings Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	for i, rule := range settings.Custom {
+		settings.Custom[i].ID = ""
+		settings.Custom[i].Name = strings.TrimSpace(rule.Name)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
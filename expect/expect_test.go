@@ -0,0 +1,81 @@
+package expect
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func replies(r *Responder, chunks ...string) []string {
+	var out []string
+	for _, chunk := range chunks {
+		for _, rule := range r.Feed(chunk) {
+			out = append(out, rule.ID+"="+rule.Reply)
+		}
+	}
+	return out
+}
+
+func TestBuiltinRulesAnswerKnownPrompts(t *testing.T) {
+	r := NewResponder(Settings{}.Rules())
+	cases := map[string]string{
+		"Press Enter to continue...":                  "enter=",
+		"\x1b[33m按回车键继续\x1b[0m":                       "enter=",
+		"Do you accept the license agreement? [y/N] ": "eula=y",
+		"Continue? (y/n)":                             "continue=y",
+		"是否继续":                                        "continue=y",
+	}
+	for prompt, want := range cases {
+		got := replies(r, "\n"+prompt)
+		if len(got) != 1 || got[0] != want {
+			t.Fatalf("%q: replies = %v, want %s", prompt, got, want)
+		}
+	}
+	if got := replies(r, "\nCPU 测试完成\n"); len(got) != 0 {
+		t.Fatalf("ordinary output should not be answered: %v", got)
+	}
+}
+
+func TestResponderRepliesOncePerLine(t *testing.T) {
+	r := NewResponder([]Rule{{Name: "go", Pattern: `Ready\?`, Reply: "go"}})
+	if got := replies(r, "Rea", "dy?", " "); len(got) != 1 {
+		t.Fatalf("a prompt split across chunks should be answered once, got %v", got)
+	}
+	if got := replies(r, "\n"); len(got) != 0 {
+		t.Fatalf("finishing the line should not answer again, got %v", got)
+	}
+	if got := replies(r, strings.Repeat("Ready?\n", MaxReplies+5)); len(got) != MaxReplies-1 {
+		t.Fatalf("replies = %d, want the per-run cap", len(got))
+	}
+}
+
+func TestSettingsRulesAndPersistence(t *testing.T) {
+	settings := Settings{Disabled: []string{"eula"}, Custom: []Rule{{Name: " mine ", Pattern: `Token:`, Reply: "abc"}}}
+	rules := settings.Rules()
+	if len(rules) != len(Builtin) || rules[len(rules)-1].Reply != "abc" {
+		t.Fatalf("rules = %+v", rules)
+	}
+	for _, rule := range rules {
+		if rule.ID == "eula" {
+			t.Fatal("disabled built-in rules should be skipped")
+		}
+	}
+	if (Settings{Off: true}).Rules() != nil || NewResponder(nil) != nil {
+		t.Fatal("turning the engine off should leave no rules")
+	}
+
+	path := filepath.Join(t.TempDir(), "expect.json")
+	if err := Save(path, Settings{Custom: []Rule{{Name: "bad", Pattern: "("}}}); err == nil {
+		t.Fatal("an invalid pattern should be rejected")
+	}
+	if err := Save(path, settings); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := Load(path)
+	if err != nil || loaded.Custom[0].Name != "mine" || loaded.Disabled[0] != "eula" {
+		t.Fatalf("loaded = %+v, %v", loaded, err)
+	}
+	if empty, err := Load(filepath.Join(t.TempDir(), "missing.json")); err != nil || empty.Off || len(empty.Rules()) != len(Builtin) {
+		t.Fatal("a missing file should enable the built-in rules")
+	}
+}
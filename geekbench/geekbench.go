@@ -0,0 +1,123 @@
+// Package geekbench 按用户选择的主版本查找并运行 Geekbench，
+// 从上传输出中取出得分、结果链接与认领链接（claim URL）。
+package geekbench
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Versions 是可选的 Geekbench 主版本，第一个为默认值
+var Versions = []string{"6", "5"}
+
+// EULAURL 是 Primate Labs 的最终用户许可协议
+const EULAURL = "https://www.primatelabs.com/legal/eula.html"
+
+// ErrNotFound 表示 PATH 中没有所选版本的 Geekbench
+var ErrNotFound = errors.New("geekbench not found")
+
+var (
+	lookPath = exec.LookPath
+	// versionOf 返回 geekbench --version 的输出，测试中替换
+	versionOf = func(bin string) string {
+		out, _ := exec.Command(bin, "--version").CombinedOutput()
+		return strings.TrimSpace(string(out))
+	}
+	scorePattern = regexp.MustCompile(`(?m)^\s*(Single|Multi)-Core Score\s+(\d+)\s*$`)
+	urlPattern   = regexp.MustCompile(`https://browser\.geekbench\.com/\S+`)
+)
+
+// Find 在 PATH 中查找指定主版本的 Geekbench：先找 geekbench6 这类带版本号的命令，
+// 再找 --version 输出匹配的 geekbench
+func Find(version string) (bin, fullVersion string, err error) {
+	for _, name := range []string{"geekbench" + version, "geekbench"} {
+		path, err := lookPath(name)
+		if err != nil {
+			continue
+		}
+		if full := versionOf(path); strings.HasPrefix(full, "Geekbench "+version+".") {
+			return path, firstLine(full), nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: Geekbench %s", ErrNotFound, version)
+}
+
+func firstLine(text string) string {
+	line, _, _ := strings.Cut(text, "\n")
+	return strings.TrimSpace(line)
+}
+
+// Result 是一次 Geekbench 上传的结果
+type Result struct {
+	Version    string
+	SingleCore string
+	MultiCore  string
+	// Link 为公开的结果页，ClaimURL 用于把结果加入自己的 Geekbench Browser 账号
+	Link     string
+	ClaimURL string
+}
+
+// ParseOutput 解析 geekbench --upload 的输出
+func ParseOutput(output string) Result {
+	var result Result
+	for _, m := range scorePattern.FindAllStringSubmatch(output, -1) {
+		if m[1] == "Single" {
+			result.SingleCore = m[2]
+		} else {
+			result.MultiCore = m[2]
+		}
+	}
+	for _, url := range urlPattern.FindAllString(output, -1) {
+		switch {
+		case strings.Contains(url, "/claim"):
+			if result.ClaimURL == "" {
+				result.ClaimURL = url
+			}
+		case strings.Contains(url, "/cpu/") && result.Link == "":
+			result.Link = url
+		}
+	}
+	return result
+}
+
+// Render 输出与 ecs Geekbench 测试相同格式的文本，并在末尾附上认领链接
+func Render(result Result) string {
+	var b strings.Builder
+	b.WriteString(result.Version + "\n")
+	if result.SingleCore != "" {
+		b.WriteString("Single-Core Score: " + result.SingleCore + "\n")
+	}
+	if result.MultiCore != "" {
+		b.WriteString("Multi-Core Score: " + result.MultiCore + "\n")
+	}
+	b.WriteString("Link: " + result.Link + "\n")
+	if result.ClaimURL != "" {
+		b.WriteString("Claim: " + result.ClaimURL + "\n")
+	}
+	return b.String()
+}
+
+// Run 运行指定主版本的 Geekbench 并上传结果，stdin 为 nil 时不提供输入；输出中没有结果链接时返回错误
+func Run(ctx context.Context, version string, stdin io.Reader) (Result, error) {
+	bin, fullVersion, err := Find(version)
+	if err != nil {
+		return Result{}, err
+	}
+	cmd := exec.CommandContext(ctx, bin, "--upload")
+	cmd.Stdin = stdin
+	out, err := cmd.CombinedOutput()
+	result := ParseOutput(string(out))
+	result.Version = fullVersion
+	if result.Link == "" {
+		if err == nil {
+			err = errors.New("geekbench output contains no result link")
+		}
+		return result, err
+	}
+	return result, nil
+}
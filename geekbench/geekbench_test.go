@@ -0,0 +1,65 @@
+package geekbench
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+const uploadOutput = `Geekbench 6.3.0 : https://www.geekbench.com/
+
+Benchmark Summary
+  Single-Core Score             1234
+    File Compression            1100
+  Multi-Core Score              4567
+    File Compression            4000
+
+Upload succeeded. Visit the following link and view your results online:
+
+  https://browser.geekbench.com/v6/cpu/7654321
+
+Visit the following link and add this result to your profile:
+
+  https://browser.geekbench.com/v6/cpu/7654321/claim?key=112233
+`
+
+func TestParseOutputAndRender(t *testing.T) {
+	result := ParseOutput(uploadOutput)
+	want := Result{
+		SingleCore: "1234",
+		MultiCore:  "4567",
+		Link:       "https://browser.geekbench.com/v6/cpu/7654321",
+		ClaimURL:   "https://browser.geekbench.com/v6/cpu/7654321/claim?key=112233",
+	}
+	if result != want {
+		t.Fatalf("ParseOutput() = %+v", result)
+	}
+	result.Version = "Geekbench 6.3.0 Tryout"
+	out := Render(result)
+	for _, line := range []string{"Geekbench 6.3.0 Tryout", "Single-Core Score: 1234", "Multi-Core Score: 4567", "Link: " + want.Link, "Claim: " + want.ClaimURL} {
+		if !strings.Contains(out, line+"\n") {
+			t.Fatalf("Render() missing %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestFindMatchesMajorVersion(t *testing.T) {
+	oldLook, oldVersion := lookPath, versionOf
+	t.Cleanup(func() { lookPath, versionOf = oldLook, oldVersion })
+	lookPath = func(name string) (string, error) {
+		if name == "geekbench" {
+			return "/usr/local/bin/geekbench", nil
+		}
+		return "", exec.ErrNotFound
+	}
+	versionOf = func(string) string { return "Geekbench 5.4.5 Tryout Build 503938\n" }
+
+	bin, version, err := Find("5")
+	if err != nil || bin != "/usr/local/bin/geekbench" || version != "Geekbench 5.4.5 Tryout Build 503938" {
+		t.Fatalf("Find(5) = %q, %q, %v", bin, version, err)
+	}
+	if _, _, err := Find("6"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Find(6) = %v", err)
+	}
+}
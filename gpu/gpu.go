@@ -0,0 +1,237 @@
+// Package gpu 收集本机 GPU 的型号、显存与驱动版本，并可调用 hashcat 做一次简短的基准测试。
+package gpu
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound 表示没有找到 GPU 或可用的查询工具
+var ErrNotFound = errors.New("no gpu found")
+
+// Device 是一块 GPU，MemoryMB 与 Driver 为 0 或空时表示查询工具没有给出
+type Device struct {
+	Index    int    `json:"index"`
+	Model    string `json:"model"`
+	MemoryMB int    `json:"memory_mb,omitempty"`
+	Driver   string `json:"driver,omitempty"`
+	Source   string `json:"source"` // 信息来源：nvidia-smi、lspci 或 wmi
+}
+
+// Runner 执行命令并返回标准输出，测试中替换
+type Runner func(ctx context.Context, name string, args ...string) (string, error)
+
+// Exec 是默认的 Runner，直接在本机执行命令
+func Exec(ctx context.Context, name string, args ...string) (string, error) {
+	out, err := exec.CommandContext(ctx, name, args...).Output()
+	return string(out), err
+}
+
+// Detect 依次尝试 nvidia-smi 与系统自带的设备列表（Linux 为 lspci，Windows 为 Win32_VideoController），
+// 返回第一个有结果的来源；都没有结果时返回 ErrNotFound
+func Detect(ctx context.Context, run Runner) ([]Device, error) {
+	if out, err := run(ctx, "nvidia-smi", "--query-gpu=name,memory.total,driver_version", "--format=csv,noheader,nounits"); err == nil {
+		if devices := ParseNvidiaSMI(out); len(devices) > 0 {
+			return devices, nil
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	switch runtime.GOOS {
+	case "windows":
+		out, err := run(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command",
+			`Get-CimInstance Win32_VideoController | ForEach-Object { "$($_.Name)|$($_.AdapterRAM)|$($_.DriverVersion)" }`)
+		if err == nil {
+			if devices := ParseVideoControllers(out); len(devices) > 0 {
+				return devices, nil
+			}
+		}
+	case "linux", "freebsd":
+		if out, err := run(ctx, "lspci", "-mm"); err == nil {
+			if devices := ParseLspci(out); len(devices) > 0 {
+				return devices, nil
+			}
+		}
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, ErrNotFound
+}
+
+// ParseNvidiaSMI 解析 nvidia-smi --query-gpu=name,memory.total,driver_version --format=csv,noheader,nounits 的输出
+func ParseNvidiaSMI(out string) []Device {
+	var devices []Device
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 || strings.TrimSpace(fields[0]) == "" {
+			continue
+		}
+		memory, _ := strconv.Atoi(strings.TrimSpace(fields[1]))
+		devices = append(devices, Device{
+			Index:    len(devices),
+			Model:    strings.TrimSpace(fields[0]),
+			MemoryMB: memory,
+			Driver:   strings.TrimSpace(fields[2]),
+			Source:   "nvidia-smi",
+		})
+	}
+	return devices
+}
+
+// lspciClasses 是 lspci 中表示显卡的设备类别
+var lspciClasses = []string{"VGA compatible controller", "3D controller", "Display controller"}
+
+// ParseLspci 解析 lspci -mm 的输出，只保留显卡；每行为带引号的字段：槽位 "类别" "厂商" "设备" ...
+func ParseLspci(out string) []Device {
+	var devices []Device
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		fields := quotedFields(scanner.Text())
+		if len(fields) < 4 {
+			continue
+		}
+		isDisplay := false
+		for _, class := range lspciClasses {
+			if strings.HasPrefix(fields[1], class) {
+				isDisplay = true
+				break
+			}
+		}
+		if !isDisplay {
+			continue
+		}
+		devices = append(devices, Device{Index: len(devices), Model: strings.TrimSpace(fields[2] + " " + fields[3]), Source: "lspci"})
+	}
+	return devices
+}
+
+// quotedFields 按空白切分 lspci -mm 的一行，双引号内的空白不切分
+func quotedFields(line string) []string {
+	var fields []string
+	var current strings.Builder
+	quoted, started := false, false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			quoted, started = !quoted, true
+		case (r == ' ' || r == '\t') && !quoted:
+			if started {
+				fields = append(fields, current.String())
+				current.Reset()
+				started = false
+			}
+		default:
+			current.WriteRune(r)
+			started = true
+		}
+	}
+	if started {
+		fields = append(fields, current.String())
+	}
+	return fields
+}
+
+// ParseVideoControllers 解析 Windows 上 Win32_VideoController 的 "名称|AdapterRAM 字节|驱动版本" 行，
+// 跳过远程桌面与 Basic Display 这类虚拟显示适配器
+func ParseVideoControllers(out string) []Device {
+	var devices []Device
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "|")
+		if len(fields) != 3 || strings.TrimSpace(fields[0]) == "" {
+			continue
+		}
+		name := strings.TrimSpace(fields[0])
+		if strings.Contains(name, "Remote Display") || strings.Contains(name, "Basic Display") {
+			continue
+		}
+		ram, _ := strconv.ParseInt(strings.TrimSpace(fields[1]), 10, 64)
+		devices = append(devices, Device{
+			Index:    len(devices),
+			Model:    name,
+			MemoryMB: int(ram / (1 << 20)),
+			Driver:   strings.TrimSpace(fields[2]),
+			Source:   "wmi",
+		})
+	}
+	return devices
+}
+
+// BenchMode 是基准测试使用的 hashcat 哈希模式（SHA-256），各家 GPU 都支持且单次运行只需数秒
+const BenchMode = 1400
+
+// BenchResult 是 hashcat 在一个计算设备上的基准成绩
+type BenchResult struct {
+	Device       int     `json:"device"`
+	Mode         int     `json:"mode"`
+	HashesPerSec float64 `json:"hashes_per_sec"`
+}
+
+// BenchArgs 返回运行 hashcat 基准测试的参数
+func BenchArgs() []string {
+	return []string{"-b", "-m", strconv.Itoa(BenchMode), "--machine-readable", "--quiet"}
+}
+
+// Benchmark 用 hashcat 对每个计算设备运行一次 SHA-256 基准测试
+func Benchmark(ctx context.Context, run Runner, binary string) ([]BenchResult, error) {
+	out, err := run(ctx, binary, BenchArgs()...)
+	results := ParseHashcat(out)
+	if len(results) > 0 {
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, errors.New("hashcat reported no benchmark results")
+}
+
+// ParseHashcat 解析 hashcat -b --machine-readable 的输出，每行为 设备:模式:核心频率:显存频率:耗时毫秒:每秒哈希数
+func ParseHashcat(out string) []BenchResult {
+	var results []BenchResult
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), ":")
+		if len(fields) != 6 {
+			continue
+		}
+		device, err1 := strconv.Atoi(fields[0])
+		mode, err2 := strconv.Atoi(fields[1])
+		speed, err3 := strconv.ParseFloat(fields[5], 64)
+		if err1 != nil || err2 != nil || err3 != nil {
+			continue
+		}
+		results = append(results, BenchResult{Device: device, Mode: mode, HashesPerSec: speed})
+	}
+	return results
+}
+
+// FormatRate 把每秒哈希数格式化为 H/s、kH/s、MH/s、GH/s 或 TH/s
+func FormatRate(hashesPerSec float64) string {
+	units := []string{"H/s", "kH/s", "MH/s", "GH/s", "TH/s"}
+	unit := 0
+	for hashesPerSec >= 1000 && unit < len(units)-1 {
+		hashesPerSec /= 1000
+		unit++
+	}
+	return fmt.Sprintf("%.2f %s", hashesPerSec, units[unit])
+}
+
+// ParseRate 是 FormatRate 的逆操作，无法识别时返回 0
+func ParseRate(text string) float64 {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return 0
+	}
+	value, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0
+	}
+	scale := map[string]float64{"H/s": 1, "kH/s": 1e3, "MH/s": 1e6, "GH/s": 1e9, "TH/s": 1e12}[fields[1]]
+	return value * scale
+}
@@ -0,0 +1,101 @@
+package gpu
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"testing"
+)
+
+func TestParseNvidiaSMI(t *testing.T) {
+	devices := ParseNvidiaSMI("Tesla T4, 15360, 535.104.05\nNVIDIA A100-SXM4-40GB, 40960, 535.104.05\n\n")
+	if len(devices) != 2 {
+		t.Fatalf("devices = %+v", devices)
+	}
+	if devices[0] != (Device{Index: 0, Model: "Tesla T4", MemoryMB: 15360, Driver: "535.104.05", Source: "nvidia-smi"}) {
+		t.Fatalf("devices[0] = %+v", devices[0])
+	}
+	if devices[1].Index != 1 || devices[1].MemoryMB != 40960 {
+		t.Fatalf("devices[1] = %+v", devices[1])
+	}
+}
+
+func TestParseLspciKeepsDisplayControllers(t *testing.T) {
+	out := `00:00.0 "Host bridge" "Intel Corporation" "440FX - 82441FX PMC [Natoma]" -r02 "Red Hat, Inc." "Qemu virtual machine"
+00:02.0 "VGA compatible controller" "Cirrus Logic" "GD 5446" "Red Hat, Inc." "QEMU Virtual Machine"
+00:05.0 "3D controller" "NVIDIA Corporation" "TU104GL [Tesla T4]" -ra1 "NVIDIA Corporation" "Device 12a2"
+`
+	devices := ParseLspci(out)
+	if len(devices) != 2 {
+		t.Fatalf("devices = %+v", devices)
+	}
+	if devices[1].Model != "NVIDIA Corporation TU104GL [Tesla T4]" || devices[1].Index != 1 || devices[1].Source != "lspci" {
+		t.Fatalf("devices[1] = %+v", devices[1])
+	}
+}
+
+func TestParseVideoControllersSkipsVirtualAdapters(t *testing.T) {
+	out := "Microsoft Remote Display Adapter||10.0.20348.1\r\nNVIDIA Tesla T4|4293918720|31.0.15.3623\r\n"
+	devices := ParseVideoControllers(out)
+	if len(devices) != 1 || devices[0].Model != "NVIDIA Tesla T4" || devices[0].MemoryMB != 4095 || devices[0].Driver != "31.0.15.3623" {
+		t.Fatalf("devices = %+v", devices)
+	}
+}
+
+func TestParseHashcat(t *testing.T) {
+	out := "1:1400:1590:5001:52.35:5220958432\nnot a result\n2:1400:0:0:10.00:abc\n"
+	results := ParseHashcat(out)
+	if len(results) != 1 || results[0] != (BenchResult{Device: 1, Mode: 1400, HashesPerSec: 5220958432}) {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestFormatAndParseRate(t *testing.T) {
+	cases := map[float64]string{512: "512.00 H/s", 5220958432: "5.22 GH/s", 1_500_000: "1.50 MH/s"}
+	for value, want := range cases {
+		got := FormatRate(value)
+		if got != want {
+			t.Errorf("FormatRate(%v) = %q, want %q", value, got, want)
+		}
+		if parsed := ParseRate(got); parsed < value*0.99 || parsed > value*1.01 {
+			t.Errorf("ParseRate(%q) = %v, want about %v", got, parsed, value)
+		}
+	}
+	if ParseRate("fast") != 0 {
+		t.Fatal("ParseRate should return 0 for unknown text")
+	}
+}
+
+func TestDetectFallsBackWithoutNvidiaSMI(t *testing.T) {
+	var calls []string
+	run := func(ctx context.Context, name string, args ...string) (string, error) {
+		calls = append(calls, name)
+		switch name {
+		case "lspci":
+			return `00:02.0 "VGA compatible controller" "Matrox Electronics Systems Ltd." "MGA G200eW WPCM450" "Dell" "PowerEdge R720"` + "\n", nil
+		case "powershell":
+			return "Matrox G200eW|16777216|9.15.1.224\n", nil
+		}
+		return "", errors.New("executable file not found")
+	}
+	devices, err := Detect(context.Background(), run)
+	switch runtime.GOOS {
+	case "linux", "freebsd", "windows":
+		if err != nil || len(devices) != 1 || calls[0] != "nvidia-smi" {
+			t.Fatalf("Detect() = %+v, %v; calls = %v", devices, err, calls)
+		}
+	default:
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("Detect() error = %v, want ErrNotFound", err)
+		}
+	}
+}
+
+func TestBenchmarkReportsMissingResults(t *testing.T) {
+	run := func(ctx context.Context, name string, args ...string) (string, error) {
+		return "hashcat (v6.2.6) starting in benchmark mode\n", nil
+	}
+	if _, err := Benchmark(context.Background(), run, "hashcat"); err == nil {
+		t.Fatal("Benchmark should fail without results")
+	}
+}
@@ -0,0 +1,422 @@
+// Package history 将每次完成的测试（原始输出 + 解析结果 + 时间与主机信息）持久化到本地目录，
+// 每次运行保存为一个 JSON 文件，并维护一个轻量索引用于列表展示。
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	indexFileName = "index.json"
+	// DefaultMaxRuns 是默认保留的历史记录条数，超出后删除最旧的记录
+	DefaultMaxRuns = 100
+)
+
+// ErrNotFound 表示指定的历史记录不存在
+var ErrNotFound = errors.New("history run not found")
+
+// Summary 是历史列表中展示的一条摘要
+type Summary struct {
+	ID         string        `json:"id"`
+	StartedAt  time.Time     `json:"started_at"`
+	FinishedAt time.Time     `json:"finished_at"`
+	Duration   time.Duration `json:"duration"`
+	Status     string        `json:"status"`
+	Host       string        `json:"host"`
+	Preset     string        `json:"preset,omitempty"`
+	Label      string        `json:"label,omitempty"`
+	// Tests 为本次运行所选的测试项
+	Tests []string `json:"tests,omitempty"`
+	// Baseline 为真时该运行是所在主机的基准，之后的运行与它比较；每台主机最多一条
+	Baseline bool `json:"baseline,omitempty"`
+	// Tags 为主机管理中给目标主机加的标签（服务商、地区、价格等）
+	Tags []string `json:"tags,omitempty"`
+	// MonthlyCost 为运行时主机管理中该主机的月费，0 表示未知
+	MonthlyCost float64 `json:"monthly_cost,omitempty"`
+	// Retests 为之后单独重跑并合并进本记录的测试项，按时间先后排列
+	Retests []Retest `json:"retests,omitempty"`
+}
+
+// Retest 是一次单项重跑：Tests 为重跑的测试项，At 为重跑结束的时间
+type Retest struct {
+	Tests []string  `json:"tests"`
+	At    time.Time `json:"at"`
+}
+
+// RetestedAt 返回 test 最近一次重跑的时间，未重跑过时返回零值
+func (s Summary) RetestedAt(test string) time.Time {
+	var at time.Time
+	for _, retest := range s.Retests {
+		if slices.Contains(retest.Tests, test) && retest.At.After(at) {
+			at = retest.At
+		}
+	}
+	return at
+}
+
+// HasTag 返回记录是否带有标签 tag，不区分大小写
+func (s Summary) HasTag(tag string) bool {
+	for _, item := range s.Tags {
+		if strings.EqualFold(item, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterByTag 返回带有标签 tag 的记录，tag 为空时返回全部
+func FilterByTag(items []Summary, tag string) []Summary {
+	if tag == "" {
+		return items
+	}
+	var out []Summary
+	for _, item := range items {
+		if item.HasTag(tag) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// AllTags 返回记录中出现过的全部标签，按名称排序
+func AllTags(items []Summary) []string {
+	var tags []string
+	for _, item := range items {
+		for _, tag := range item.Tags {
+			if !slices.ContainsFunc(tags, func(seen string) bool { return strings.EqualFold(seen, tag) }) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// Run 是一次完整的测试记录
+type Run struct {
+	Summary
+	Output  string          `json:"output"`
+	Results *results.Report `json:"results,omitempty"`
+	// Artifacts 是运行后从远程目标取回的文件（结果文本、Geekbench JSON 等）
+	Artifacts []Artifact `json:"artifacts,omitempty"`
+}
+
+// Artifact 是随记录保存的一个文件
+type Artifact struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// Store 是基于目录的历史记录存储，可被多个 goroutine 并发使用
+type Store struct {
+	dir     string
+	maxRuns int
+	mu      sync.Mutex
+}
+
+// Open 打开（必要时创建）历史记录目录
+func Open(dir string) (*Store, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errors.New("history directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, maxRuns: DefaultMaxRuns}, nil
+}
+
+// Dir 返回存储目录
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// SetMaxRuns 设置保留条数，<=0 表示不限制
+func (s *Store) SetMaxRuns(n int) {
+	s.mu.Lock()
+	s.maxRuns = n
+	s.mu.Unlock()
+}
+
+// Save 保存一次运行并返回带 ID 的记录
+func (s *Store) Save(run Run) (Run, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if run.StartedAt.IsZero() {
+		run.StartedAt = time.Now()
+	}
+	if run.FinishedAt.IsZero() {
+		run.FinishedAt = time.Now()
+	}
+	if run.Duration == 0 {
+		run.Duration = run.FinishedAt.Sub(run.StartedAt)
+	}
+	if run.ID == "" {
+		run.ID = s.newIDLocked(run.StartedAt)
+	}
+	if err := writeJSON(s.runPath(run.ID), run); err != nil {
+		return Run{}, err
+	}
+
+	index, err := s.loadIndexLocked()
+	if err != nil {
+		return Run{}, err
+	}
+	index = append(removeSummary(index, run.ID), run.Summary)
+	sortSummaries(index)
+	if s.maxRuns > 0 && len(index) > s.maxRuns {
+		// 超出保留条数时删除最旧的记录，基准记录不会被删除
+		kept := index[:0:0]
+		for i, item := range index {
+			if i < s.maxRuns || item.Baseline {
+				kept = append(kept, item)
+				continue
+			}
+			_ = os.Remove(s.runPath(item.ID))
+		}
+		index = kept
+	}
+	return run, writeJSON(s.indexPath(), index)
+}
+
+// SetBaseline 把记录 id 设为（on 为假时取消）其主机的基准，同一主机原有的基准会被取消
+func (s *Store) SetBaseline(id string, on bool) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, err := s.loadIndexLocked()
+	if err != nil {
+		return err
+	}
+	pos := -1
+	for i, item := range index {
+		if item.ID == id {
+			pos = i
+		}
+	}
+	if pos < 0 {
+		return ErrNotFound
+	}
+	for i := range index {
+		want := i == pos && on
+		if i != pos && (!on || index[i].Host != index[pos].Host) {
+			continue
+		}
+		if index[i].Baseline == want {
+			continue
+		}
+		run, err := s.Load(index[i].ID)
+		if err != nil {
+			return err
+		}
+		run.Baseline = want
+		if err := writeJSON(s.runPath(run.ID), run); err != nil {
+			return err
+		}
+		index[i].Baseline = want
+	}
+	return writeJSON(s.indexPath(), index)
+}
+
+// SetHostTags 把主机 host 的全部记录的标签改为 tags，用于在主机管理中修改标签后同步已有的记录
+func (s *Store) SetHostTags(host string, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	index, err := s.loadIndexLocked()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i := range index {
+		if index[i].Host != host || slices.Equal(index[i].Tags, tags) {
+			continue
+		}
+		run, err := s.Load(index[i].ID)
+		if err != nil {
+			return err
+		}
+		run.Tags = tags
+		if err := writeJSON(s.runPath(run.ID), run); err != nil {
+			return err
+		}
+		index[i].Tags, changed = tags, true
+	}
+	if !changed {
+		return nil
+	}
+	return writeJSON(s.indexPath(), index)
+}
+
+// Baseline 返回主机 host 的基准记录，没有时返回 ErrNotFound
+func (s *Store) Baseline(host string) (Run, error) {
+	s.mu.Lock()
+	index, err := s.loadIndexLocked()
+	s.mu.Unlock()
+	if err != nil {
+		return Run{}, err
+	}
+	for _, item := range index {
+		if item.Baseline && item.Host == host {
+			return s.Load(item.ID)
+		}
+	}
+	return Run{}, ErrNotFound
+}
+
+// List 返回按开始时间倒序排列的摘要
+func (s *Store) List() ([]Summary, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadIndexLocked()
+}
+
+// Load 读取完整的运行记录
+func (s *Store) Load(id string) (Run, error) {
+	if !validID(id) {
+		return Run{}, ErrNotFound
+	}
+	data, err := os.ReadFile(s.runPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Run{}, ErrNotFound
+	}
+	if err != nil {
+		return Run{}, err
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return Run{}, fmt.Errorf("decode history run %s: %w", id, err)
+	}
+	return run, nil
+}
+
+// Delete 删除一条记录
+func (s *Store) Delete(id string) error {
+	if !validID(id) {
+		return ErrNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.Remove(s.runPath(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	index, err := s.loadIndexLocked()
+	if err != nil {
+		return err
+	}
+	return writeJSON(s.indexPath(), removeSummary(index, id))
+}
+
+func (s *Store) runPath(id string) string {
+	return filepath.Join(s.dir, id+".json")
+}
+
+func (s *Store) indexPath() string {
+	return filepath.Join(s.dir, indexFileName)
+}
+
+func (s *Store) newIDLocked(started time.Time) string {
+	base := started.Format("20060102-150405")
+	id := base
+	for i := 1; ; i++ {
+		if _, err := os.Stat(s.runPath(id)); errors.Is(err, os.ErrNotExist) {
+			return id
+		}
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// loadIndexLocked 读取索引；索引缺失或损坏时扫描目录重建
+func (s *Store) loadIndexLocked() ([]Summary, error) {
+	data, err := os.ReadFile(s.indexPath())
+	if err == nil {
+		var index []Summary
+		if json.Unmarshal(data, &index) == nil {
+			return index, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	return s.rebuildIndexLocked()
+}
+
+func (s *Store) rebuildIndexLocked() ([]Summary, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var index []Summary
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || name == indexFileName || filepath.Ext(name) != ".json" {
+			continue
+		}
+		run, err := s.Load(strings.TrimSuffix(name, ".json"))
+		if err != nil {
+			continue
+		}
+		index = append(index, run.Summary)
+	}
+	sortSummaries(index)
+	return index, nil
+}
+
+func removeSummary(index []Summary, id string) []Summary {
+	out := index[:0:0]
+	for _, item := range index {
+		if item.ID != id {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func sortSummaries(index []Summary) {
+	sort.SliceStable(index, func(i, j int) bool {
+		return index[i].StartedAt.After(index[j].StartedAt)
+	})
+}
+
+// validID 防止通过 ID 访问存储目录之外的文件
+func validID(id string) bool {
+	return id != "" && !strings.ContainsAny(id, `/\.`) && id != strings.TrimSuffix(indexFileName, ".json")
+}
+
+// writeJSON 原子地写入 path：先写入同目录下的临时文件并落盘，再改名覆盖，
+// 中途崩溃或写入失败时原文件保持不变，不会留下写了一半的索引或记录
+func writeJSON(path string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+	}
+	return err
+}
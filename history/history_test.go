@@ -0,0 +1,180 @@
+package history
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestStoreSaveListLoadDelete(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	first, err := store.Save(Run{Summary: Summary{StartedAt: base, Status: "done", Host: "a"}, Output: "one"})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	second, err := store.Save(Run{
+		Summary: Summary{StartedAt: base.Add(time.Hour), FinishedAt: base.Add(2 * time.Hour), Status: "failed", Host: "b"},
+		Output:  "two",
+		Results: &results.Report{CPU: []results.CPUScore{{Label: "1", Score: 10}}},
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	list, err := store.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List() = %#v, %v", list, err)
+	}
+	if list[0].ID != second.ID || list[0].Duration != time.Hour {
+		t.Fatalf("List()[0] = %#v, want newest run first", list[0])
+	}
+
+	loaded, err := store.Load(second.ID)
+	if err != nil || loaded.Output != "two" || loaded.Results == nil || loaded.Results.CPU[0].Score != 10 {
+		t.Fatalf("Load() = %#v, %v", loaded, err)
+	}
+
+	if err := store.Delete(first.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := store.Load(first.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load(deleted) error = %v, want ErrNotFound", err)
+	}
+	if list, _ := store.List(); len(list) != 1 {
+		t.Fatalf("List() after delete = %#v", list)
+	}
+}
+
+func TestStoreTrimsToMaxRunsAndRebuildsIndex(t *testing.T) {
+	dir := t.TempDir()
+	store, _ := Open(dir)
+	store.SetMaxRuns(2)
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		if _, err := store.Save(Run{Summary: Summary{StartedAt: base.Add(time.Duration(i) * time.Minute)}}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	if err := os.Remove(filepath.Join(dir, indexFileName)); err != nil {
+		t.Fatalf("remove index: %v", err)
+	}
+	list, err := store.List()
+	if err != nil || len(list) != 2 {
+		t.Fatalf("List() = %#v, %v; want 2 rebuilt entries", list, err)
+	}
+	if !list[0].StartedAt.Equal(base.Add(2 * time.Minute)) {
+		t.Fatalf("oldest run was not trimmed: %#v", list)
+	}
+}
+
+func TestStoreBaselinePerHostSurvivesTrimming(t *testing.T) {
+	store, _ := Open(t.TempDir())
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	var ids []string
+	for i, host := range []string{"a", "a", "b"} {
+		run, err := store.Save(Run{Summary: Summary{StartedAt: base.Add(time.Duration(i) * time.Minute), Host: host}})
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		ids = append(ids, run.ID)
+	}
+	if err := store.SetBaseline(ids[0], true); err != nil {
+		t.Fatalf("SetBaseline() error = %v", err)
+	}
+	if err := store.SetBaseline(ids[1], true); err != nil {
+		t.Fatalf("SetBaseline() error = %v", err)
+	}
+	if err := store.SetBaseline(ids[2], true); err != nil {
+		t.Fatalf("SetBaseline() error = %v", err)
+	}
+	if run, err := store.Baseline("a"); err != nil || run.ID != ids[1] || !run.Baseline {
+		t.Fatalf("Baseline(a) = %#v, %v; want the second run only", run.Summary, err)
+	}
+	if first, _ := store.Load(ids[0]); first.Baseline {
+		t.Fatal("marking a new baseline should clear the previous one on the same host")
+	}
+
+	store.SetMaxRuns(1)
+	if _, err := store.Save(Run{Summary: Summary{StartedAt: base.Add(time.Hour), Host: "c"}}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if list, _ := store.List(); len(list) != 3 {
+		t.Fatalf("List() = %#v, want the newest run plus two baselines", list)
+	}
+	if err := store.SetBaseline(ids[2], false); err != nil {
+		t.Fatalf("SetBaseline(off) error = %v", err)
+	}
+	if _, err := store.Baseline("b"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Baseline(b) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreHostTagsAndFilter(t *testing.T) {
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for i, host := range []string{"a", "b", "a"} {
+		if _, err := store.Save(Run{Summary: Summary{StartedAt: base.Add(time.Duration(i) * time.Minute), Host: host, Tags: []string{"old"}}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := store.SetHostTags("a", []string{"Vultr", "Tokyo"}); err != nil {
+		t.Fatal(err)
+	}
+	items, _ := store.List()
+	if tagged := FilterByTag(items, "vultr"); len(tagged) != 2 || tagged[0].Host != "a" || tagged[1].Host != "a" {
+		t.Fatalf("FilterByTag() = %+v", tagged)
+	}
+	if len(FilterByTag(items, "")) != 3 || len(FilterByTag(items, "missing")) != 0 {
+		t.Fatal("an empty tag keeps every run")
+	}
+	if got := AllTags(items); !slices.Equal(got, []string{"Tokyo", "Vultr", "old"}) {
+		t.Fatalf("AllTags() = %v", got)
+	}
+	run, err := store.Load(items[0].ID)
+	if err != nil || !slices.Equal(run.Tags, []string{"Vultr", "Tokyo"}) {
+		t.Fatalf("run tags = %v, %v", run.Tags, err)
+	}
+}
+
+func TestStoreRejectsPathTraversal(t *testing.T) {
+	store, _ := Open(t.TempDir())
+	if _, err := store.Load("../secret"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load(traversal) error = %v", err)
+	}
+}
+
+func TestWriteJSONReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, indexFileName)
+	if err := writeJSON(path, []Summary{{ID: "a"}}); err != nil {
+		t.Fatalf("writeJSON() error = %v", err)
+	}
+	// 目标被目录占据时改名失败，临时文件应被清理
+	blocked := filepath.Join(dir, "blocked.json")
+	if err := os.MkdirAll(filepath.Join(blocked, "x"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeJSON(blocked, []Summary{{ID: "b"}}); err == nil {
+		t.Fatal("writeJSON() over a directory succeeded")
+	}
+	entries, _ := os.ReadDir(dir)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	if !slices.Equal(names, []string{"blocked.json", indexFileName}) {
+		t.Fatalf("directory = %v; want no leftover temp files", names)
+	}
+}
@@ -0,0 +1,354 @@
+// Package hooks 在运行生命周期中调用用户编写的 Starlark 脚本。
+// 脚本可定义 pre_run、stage_complete、post_run 三个函数，
+// 用于实现自定义的通过/失败策略，或把结构化结果推送到内部系统。
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/starlarkjson"
+	"go.starlark.net/starlarkstruct"
+	"go.starlark.net/syntax"
+
+	"github.com/oneclickvirt/ecs-gui/applog"
+)
+
+// FileExt 是钩子脚本的扩展名，目录中其他文件会被忽略
+const FileExt = ".star"
+
+// 钩子函数名
+const (
+	HookPreRun        = "pre_run"
+	HookStageComplete = "stage_complete"
+	HookPostRun       = "post_run"
+)
+
+const (
+	// DefaultTimeout 是单次钩子调用（含其中的 http_post）的默认时限
+	DefaultTimeout = 10 * time.Second
+	// maxExecutionSteps 限制单次调用的计算量，防止死循环卡住运行
+	maxExecutionSteps = 50_000_000
+)
+
+var logger = applog.For("hooks")
+
+// Run 是传给钩子的运行信息
+type Run struct {
+	Host      string
+	Preset    string
+	Tests     []string
+	StartedAt time.Time
+}
+
+// Rejection 表示脚本拒绝了运行（pre_run）或判定结果不合格（post_run）
+type Rejection struct {
+	Script string
+	Hook   string
+	Reason string
+}
+
+func (r *Rejection) Error() string {
+	if r.Reason == "" {
+		return fmt.Sprintf("%s: %s rejected", r.Script, r.Hook)
+	}
+	return fmt.Sprintf("%s: %s rejected: %s", r.Script, r.Hook, r.Reason)
+}
+
+// Options 控制脚本的运行环境
+type Options struct {
+	// Print 接收脚本 print() 的输出以及脚本出错的消息，为 nil 时丢弃
+	Print func(msg string)
+	// Client 供 http_post 使用，为 nil 时使用 http.DefaultClient
+	Client *http.Client
+	// Timeout 为 0 时使用 DefaultTimeout
+	Timeout time.Duration
+}
+
+type script struct {
+	name    string
+	globals starlark.StringDict
+}
+
+// Engine 保存已加载的脚本。脚本的全局变量在加载后冻结，
+// 因此 Engine 可被多个 goroutine 并发调用。
+type Engine struct {
+	scripts []script
+	opts    Options
+}
+
+// Load 按文件名顺序加载 dir 中的脚本。目录不存在时返回空 Engine；
+// 个别脚本加载失败时跳过该脚本，其余脚本照常可用，错误合并后返回。
+func Load(dir string, opts Options) (*Engine, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	engine := &Engine{opts: opts}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return engine, nil
+	}
+	if err != nil {
+		return engine, err
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.EqualFold(filepath.Ext(entry.Name()), FileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	var errs []error
+	for _, name := range names {
+		thread := engine.newThread(name)
+		globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, filepath.Join(dir, name), nil, engine.predeclared())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+		engine.scripts = append(engine.scripts, script{name: name, globals: globals})
+	}
+	logger.Debug("hooks loaded", "dir", dir, "scripts", len(engine.scripts), "failed", len(errs))
+	return engine, errors.Join(errs...)
+}
+
+// Scripts 返回成功加载的脚本文件名
+func (e *Engine) Scripts() []string {
+	names := make([]string, len(e.scripts))
+	for i, s := range e.scripts {
+		names[i] = s.name
+	}
+	return names
+}
+
+// Empty 报告是否没有可用的脚本
+func (e *Engine) Empty() bool {
+	return e == nil || len(e.scripts) == 0
+}
+
+// PreRun 在运行开始前调用 pre_run(run)。返回 False 或字符串（原因）时运行被拒绝，
+// 返回第一个拒绝；脚本出错只会报告，不阻止运行。
+func (e *Engine) PreRun(run Run) error {
+	if e.Empty() {
+		return nil
+	}
+	args := starlark.Tuple{runValue(run)}
+	for _, s := range e.scripts {
+		if rejection := e.call(s, HookPreRun, args); rejection != nil {
+			return rejection
+		}
+	}
+	return nil
+}
+
+// StageComplete 在某个阶段完成后调用 stage_complete(run, stage)，返回值被忽略
+func (e *Engine) StageComplete(run Run, stage string) {
+	if e.Empty() {
+		return
+	}
+	args := starlark.Tuple{runValue(run), starlark.String(stage)}
+	for _, s := range e.scripts {
+		e.call(s, HookStageComplete, args)
+	}
+}
+
+// PostRun 在运行结束后调用 post_run(run, status, results)，results 按 JSON 结构转换为
+// dict/list 等值。每个脚本都会被调用，返回第一个不合格的判定。
+func (e *Engine) PostRun(run Run, status string, results any) error {
+	if e.Empty() {
+		return nil
+	}
+	value, err := jsonValue(results)
+	if err != nil {
+		e.report(fmt.Errorf("%s: convert results: %w", HookPostRun, err))
+		value = starlark.None
+	}
+	args := starlark.Tuple{runValue(run), starlark.String(status), value}
+	var first error
+	for _, s := range e.scripts {
+		if rejection := e.call(s, HookPostRun, args); rejection != nil && first == nil {
+			first = rejection
+		}
+	}
+	return first
+}
+
+// call 调用脚本中的钩子函数，未定义时跳过；返回值为 False 或字符串时返回 Rejection
+func (e *Engine) call(s script, hook string, args starlark.Tuple) *Rejection {
+	fn, ok := s.globals[hook].(starlark.Callable)
+	if !ok {
+		return nil
+	}
+	thread := e.newThread(s.name)
+	timer := time.AfterFunc(e.opts.Timeout, func() { thread.Cancel("timed out after " + e.opts.Timeout.String()) })
+	defer timer.Stop()
+	result, err := starlark.Call(thread, fn, args, nil)
+	if err != nil {
+		e.report(fmt.Errorf("%s: %s: %w", s.name, hook, err))
+		return nil
+	}
+	switch v := result.(type) {
+	case starlark.Bool:
+		if !v {
+			return &Rejection{Script: s.name, Hook: hook}
+		}
+	case starlark.String:
+		return &Rejection{Script: s.name, Hook: hook, Reason: string(v)}
+	}
+	return nil
+}
+
+func (e *Engine) newThread(name string) *starlark.Thread {
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			if e.opts.Print != nil {
+				e.opts.Print(fmt.Sprintf("[%s] %s", name, msg))
+			}
+		},
+	}
+	thread.SetMaxExecutionSteps(maxExecutionSteps)
+	return thread
+}
+
+func (e *Engine) report(err error) {
+	logger.Warn("hook failed", "err", err)
+	if e.opts.Print != nil {
+		e.opts.Print(err.Error())
+	}
+}
+
+func (e *Engine) predeclared() starlark.StringDict {
+	return starlark.StringDict{
+		"json":      starlarkjson.Module,
+		"http_post": starlark.NewBuiltin("http_post", e.httpPost),
+	}
+}
+
+// httpPost 实现 http_post(url, body, content_type="application/json", headers={})，
+// body 不是字符串时按 JSON 编码，返回 HTTP 状态码
+func (e *Engine) httpPost(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var url string
+	var body starlark.Value
+	contentType := "application/json"
+	headers := &starlark.Dict{}
+	if err := starlark.UnpackArgs(b.Name(), args, kwargs, "url", &url, "body", &body, "content_type?", &contentType, "headers?", &headers); err != nil {
+		return nil, err
+	}
+	payload, ok := starlark.AsString(body)
+	if !ok {
+		encoded, err := starlark.Call(thread, starlarkjson.Module.Members["encode"], starlark.Tuple{body}, nil)
+		if err != nil {
+			return nil, err
+		}
+		payload = string(encoded.(starlark.String))
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), e.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, strings.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for _, item := range headers.Items() {
+		key, ok1 := starlark.AsString(item[0])
+		value, ok2 := starlark.AsString(item[1])
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("%s: headers must map strings to strings", b.Name())
+		}
+		req.Header.Set(key, value)
+	}
+	client := e.opts.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", b.Name(), err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	return starlark.MakeInt(resp.StatusCode), nil
+}
+
+func runValue(run Run) starlark.Value {
+	tests := make(starlark.Tuple, len(run.Tests))
+	for i, test := range run.Tests {
+		tests[i] = starlark.String(test)
+	}
+	startedAt := ""
+	if !run.StartedAt.IsZero() {
+		startedAt = run.StartedAt.Format(time.RFC3339)
+	}
+	value := starlarkstruct.FromStringDict(starlark.String("run"), starlark.StringDict{
+		"host":       starlark.String(run.Host),
+		"preset":     starlark.String(run.Preset),
+		"tests":      tests,
+		"started_at": starlark.String(startedAt),
+	})
+	value.Freeze()
+	return value
+}
+
+// jsonValue 把任意可 JSON 编码的值转换为冻结的 Starlark 值
+func jsonValue(v any) (starlark.Value, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var decoded any
+	if err := decoder.Decode(&decoded); err != nil {
+		return nil, err
+	}
+	value := toStarlark(decoded)
+	value.Freeze()
+	return value, nil
+}
+
+func toStarlark(v any) starlark.Value {
+	switch v := v.(type) {
+	case nil:
+		return starlark.None
+	case bool:
+		return starlark.Bool(v)
+	case string:
+		return starlark.String(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return starlark.MakeInt64(i)
+		}
+		f, _ := v.Float64()
+		return starlark.Float(f)
+	case []any:
+		list := make([]starlark.Value, len(v))
+		for i, item := range v {
+			list[i] = toStarlark(item)
+		}
+		return starlark.NewList(list)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		dict := starlark.NewDict(len(v))
+		for _, key := range keys {
+			_ = dict.SetKey(starlark.String(key), toStarlark(v[key]))
+		}
+		return dict
+	}
+	return starlark.String(fmt.Sprint(v))
+}
@@ -0,0 +1,170 @@
+package hooks
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func writeScript(t *testing.T, dir, name, src string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type printLog struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (p *printLog) print(msg string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lines = append(p.lines, msg)
+}
+
+func (p *printLog) text() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return strings.Join(p.lines, "\n")
+}
+
+func TestLoadMissingDirectoryIsEmpty(t *testing.T) {
+	engine, err := Load(filepath.Join(t.TempDir(), "missing"), Options{})
+	if err != nil || !engine.Empty() {
+		t.Fatalf("Load() = %v, %v; want empty engine", engine.Scripts(), err)
+	}
+	if err := engine.PreRun(Run{}); err != nil {
+		t.Fatalf("PreRun() on empty engine = %v", err)
+	}
+}
+
+func TestLoadSkipsBrokenScripts(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "b_ok.star", "def pre_run(run):\n    return True\n")
+	writeScript(t, dir, "a_broken.star", "def pre_run(run)\n")
+	writeScript(t, dir, "notes.txt", "ignored")
+
+	engine, err := Load(dir, Options{})
+	if err == nil || !strings.Contains(err.Error(), "a_broken.star") {
+		t.Fatalf("Load() error = %v, want a_broken.star syntax error", err)
+	}
+	if got := engine.Scripts(); len(got) != 1 || got[0] != "b_ok.star" {
+		t.Fatalf("Scripts() = %v", got)
+	}
+}
+
+func TestPreRunRejection(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "policy.star", `
+def pre_run(run):
+    if "speed" in run.tests and run.host.startswith("prod-"):
+        return "no speed tests against production"
+    return True
+`)
+	engine, err := Load(dir, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.PreRun(Run{Host: "staging-1", Tests: []string{"speed"}}); err != nil {
+		t.Fatalf("PreRun(staging) = %v", err)
+	}
+	err = engine.PreRun(Run{Host: "prod-1", Tests: []string{"cpu", "speed"}})
+	var rejection *Rejection
+	if !errors.As(err, &rejection) || rejection.Reason != "no speed tests against production" || rejection.Hook != HookPreRun {
+		t.Fatalf("PreRun(prod) = %#v", err)
+	}
+}
+
+func TestPostRunSeesResultsAndScriptErrorsAreReported(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "10_policy.star", `
+def post_run(run, status, results):
+    print(status, results["cpu"]["single"])
+    if results["cpu"]["single"] < 1000:
+        return False
+`)
+	writeScript(t, dir, "20_broken.star", `
+def post_run(run, status, results):
+    return results["missing"]
+`)
+	log := &printLog{}
+	engine, err := Load(dir, Options{Print: log.print})
+	if err != nil {
+		t.Fatal(err)
+	}
+	report := map[string]any{"cpu": map[string]any{"single": 812.5}}
+	err = engine.PostRun(Run{Host: "h"}, "done", report)
+	var rejection *Rejection
+	if !errors.As(err, &rejection) || rejection.Script != "10_policy.star" {
+		t.Fatalf("PostRun() = %v, want rejection from 10_policy.star", err)
+	}
+	text := log.text()
+	if !strings.Contains(text, "[10_policy.star] done 812.5") {
+		t.Fatalf("print output missing: %q", text)
+	}
+	if !strings.Contains(text, "20_broken.star: post_run") {
+		t.Fatalf("script error should be reported: %q", text)
+	}
+}
+
+func TestHookTimeoutCancelsScript(t *testing.T) {
+	dir := t.TempDir()
+	writeScript(t, dir, "loop.star", `
+def stage_complete(run, stage):
+    for i in range(1000000000):
+        pass
+`)
+	log := &printLog{}
+	engine, err := Load(dir, Options{Print: log.print, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	engine.StageComplete(Run{}, "cpu")
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("stage_complete ran for %v", elapsed)
+	}
+	if !strings.Contains(log.text(), "loop.star: stage_complete") {
+		t.Fatalf("timeout should be reported: %q", log.text())
+	}
+}
+
+func TestHTTPPostEncodesBody(t *testing.T) {
+	var gotBody, gotType, gotToken string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		gotBody, gotType, gotToken = string(data), r.Header.Get("Content-Type"), r.Header.Get("X-Token")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	writeScript(t, dir, "push.star", `
+def post_run(run, status, results):
+    code = http_post(URL, {"host": run.host, "status": status}, headers={"X-Token": "secret"})
+    if code != 202:
+        return "push failed: %d" % code
+`)
+	src, _ := os.ReadFile(filepath.Join(dir, "push.star"))
+	writeScript(t, dir, "push.star", strings.Replace(string(src), "URL", `"`+server.URL+`"`, 1))
+
+	engine, err := Load(dir, Options{Client: server.Client()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := engine.PostRun(Run{Host: "h1"}, "done", nil); err != nil {
+		t.Fatalf("PostRun() = %v", err)
+	}
+	if gotBody != `{"host":"h1","status":"done"}` || gotType != "application/json" || gotToken != "secret" {
+		t.Fatalf("request body=%q type=%q token=%q", gotBody, gotType, gotToken)
+	}
+}
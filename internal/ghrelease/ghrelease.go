@@ -0,0 +1,221 @@
+// Package ghrelease 读取 GitHub Releases 并按发布的 SHA-256 校验下载内容，
+// 供 goecs 后端下载（ecsbin）与 GUI 自更新（selfupdate）共用。
+package ghrelease
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	maxChecksumLen = 1 << 20
+	maxJSONSize    = 8 << 20
+)
+
+var (
+	// ErrNoChecksum 表示发布中没有该文件的 SHA-256，不下载未经校验的文件
+	ErrNoChecksum = errors.New("release provides no sha256 checksum for asset")
+	// ErrChecksumMismatch 表示下载内容与发布的 SHA-256 不一致
+	ErrChecksumMismatch = errors.New("sha256 checksum mismatch")
+
+	// APIBaseURL 是 GitHub API 地址，测试中替换为本地服务
+	APIBaseURL = "https://api.github.com"
+)
+
+// Asset 是发布中的一个文件
+type Asset struct {
+	Name string `json:"name"`
+	URL  string `json:"browser_download_url"`
+	Size int64  `json:"size"`
+	// Digest 为 GitHub 计算的 "sha256:<hex>"，旧发布可能为空
+	Digest string `json:"digest"`
+}
+
+// Release 是 GitHub Releases 中的一个版本
+type Release struct {
+	Tag        string    `json:"tag_name"`
+	Name       string    `json:"name"`
+	Body       string    `json:"body"`
+	HTMLURL    string    `json:"html_url"`
+	Published  time.Time `json:"published_at"`
+	Draft      bool      `json:"draft"`
+	Prerelease bool      `json:"prerelease"`
+	Assets     []Asset   `json:"assets"`
+}
+
+// Version 返回去掉 v 前缀的版本号
+func (r Release) Version() string {
+	return NormalizeVersion(r.Tag)
+}
+
+// Asset 按名称查找发布中的文件
+func (r Release) Asset(name string) (Asset, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset, true
+		}
+	}
+	return Asset{}, false
+}
+
+// NormalizeVersion 去掉版本号的空白与 v 前缀
+func NormalizeVersion(version string) string {
+	return strings.TrimPrefix(strings.TrimSpace(version), "v")
+}
+
+// IsVersion 判断是否为 0.1.171 这类纯数字版本号（按日期命名的自动构建不算）
+func IsVersion(version string) bool {
+	parts := strings.Split(NormalizeVersion(version), ".")
+	if len(parts) < 2 {
+		return false
+	}
+	for _, part := range parts {
+		if _, err := strconv.Atoi(part); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// CompareVersions 按数字逐段比较 0.1.99 与 0.1.171 这类版本号
+func CompareVersions(a, b string) int {
+	pa := strings.Split(NormalizeVersion(a), ".")
+	pb := strings.Split(NormalizeVersion(b), ".")
+	for i := range max(len(pa), len(pb)) {
+		var x, y int
+		if i < len(pa) {
+			x, _ = strconv.Atoi(pa[i])
+		}
+		if i < len(pb) {
+			y, _ = strconv.Atoi(pb[i])
+		}
+		if x != y {
+			return x - y
+		}
+	}
+	return 0
+}
+
+// List 返回 repo（owner/name）最近 limit 个发布（不含草稿），按发布时间从新到旧。
+// 本包的函数均使用传入的 client 访问网络，为 nil 时使用 http.DefaultClient。
+func List(ctx context.Context, client *http.Client, repo string, limit int) ([]Release, error) {
+	var list []Release
+	if err := getJSON(ctx, client, fmt.Sprintf("%s/repos/%s/releases?per_page=%d", APIBaseURL, repo, max(limit, 1)), &list); err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(list, func(r Release) bool { return r.Draft }), nil
+}
+
+// ByTag 返回 repo 中指定标签的发布
+func ByTag(ctx context.Context, client *http.Client, repo, tag string) (Release, error) {
+	var release Release
+	err := getJSON(ctx, client, fmt.Sprintf("%s/repos/%s/releases/tags/%s", APIBaseURL, repo, tag), &release)
+	return release, err
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	body, err := get(ctx, client, url)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	return json.NewDecoder(io.LimitReader(body, maxJSONSize)).Decode(v)
+}
+
+func get(ctx context.Context, client *http.Client, url string) (io.ReadCloser, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return resp.Body, nil
+}
+
+// Download 把发布中的 name 写入 w，并按发布的 SHA-256 校验；校验失败时 w 中的内容不可使用
+func Download(ctx context.Context, client *http.Client, release Release, name string, w io.Writer) error {
+	asset, ok := release.Asset(name)
+	if !ok {
+		return fmt.Errorf("release %s has no asset %s", release.Tag, name)
+	}
+	want, err := checksum(ctx, client, release, asset)
+	if err != nil {
+		return err
+	}
+	body, err := get(ctx, client, asset.URL)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hash), body); err != nil {
+		return fmt.Errorf("download %s: %w", name, err)
+	}
+	if got := hex.EncodeToString(hash.Sum(nil)); got != want {
+		return fmt.Errorf("%w for %s: got %s, want %s", ErrChecksumMismatch, name, got, want)
+	}
+	return nil
+}
+
+// checksum 返回发布文件的 SHA-256：优先使用 GitHub 提供的 digest，其次查找发布中的校验和文件
+func checksum(ctx context.Context, client *http.Client, release Release, asset Asset) (string, error) {
+	if hexSum, ok := strings.CutPrefix(asset.Digest, "sha256:"); ok && len(hexSum) == sha256.Size*2 {
+		return strings.ToLower(hexSum), nil
+	}
+	for _, candidate := range release.Assets {
+		name := strings.ToLower(candidate.Name)
+		if name != strings.ToLower(asset.Name)+".sha256" && !strings.Contains(name, "checksum") {
+			continue
+		}
+		body, err := get(ctx, client, candidate.URL)
+		if err != nil {
+			return "", err
+		}
+		sum, ok := ParseChecksums(io.LimitReader(body, maxChecksumLen), asset.Name)
+		body.Close()
+		if ok {
+			return sum, nil
+		}
+	}
+	return "", fmt.Errorf("%w %s", ErrNoChecksum, asset.Name)
+}
+
+// ParseChecksums 从 sha256sum 格式（"<hex>  <文件名>"，单文件的 .sha256 可省略文件名）中取出 name 的校验和
+func ParseChecksums(r io.Reader, name string) (string, bool) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || len(fields[0]) != sha256.Size*2 {
+			continue
+		}
+		if _, err := hex.DecodeString(fields[0]); err != nil {
+			continue
+		}
+		if len(fields) == 1 || path.Base(strings.TrimPrefix(fields[1], "*")) == name {
+			return strings.ToLower(fields[0]), true
+		}
+	}
+	return "", false
+}
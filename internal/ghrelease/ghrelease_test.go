@@ -0,0 +1,31 @@
+package ghrelease
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	if CompareVersions("0.1.99", "v0.1.171") >= 0 || CompareVersions("0.2", "0.1.171") <= 0 || CompareVersions("v1.0", "1.0.0") != 0 {
+		t.Error("CompareVersions orders numerically")
+	}
+	for version, want := range map[string]bool{"v0.1.174": true, "1.2": true, "v20260101-120000": false, "1": false, "0.1.x": false} {
+		if got := IsVersion(version); got != want {
+			t.Errorf("IsVersion(%q) = %v, want %v", version, got, want)
+		}
+	}
+}
+
+func TestParseChecksums(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	text := "not a checksum line\n" + strings.Repeat("0", 64) + "  other.zip\n" + strings.ToUpper(sum) + " *dist/goecs_linux_amd64.zip\n"
+	if got, ok := ParseChecksums(strings.NewReader(text), "goecs_linux_amd64.zip"); !ok || got != sum {
+		t.Fatalf("ParseChecksums() = %q, %v", got, ok)
+	}
+	if _, ok := ParseChecksums(strings.NewReader(text), "missing.zip"); ok {
+		t.Fatal("missing asset should not match")
+	}
+	if got, ok := ParseChecksums(strings.NewReader(sum+"\n"), "single.exe"); !ok || got != sum {
+		t.Fatalf("single-file .sha256 = %q, %v", got, ok)
+	}
+}
@@ -0,0 +1,203 @@
+// Package iperf 解析用户配置的 iperf3 服务器，调用本机 iperf3 客户端并解析其 JSON 输出。
+package iperf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// 默认端口与测试时长，与 iperf3 客户端的默认值一致
+const (
+	DefaultPort     = 5201
+	DefaultDuration = 10
+	maxDuration     = 120
+	maxParallel     = 128
+)
+
+// Target 是一个 iperf3 服务器及测试参数
+type Target struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Duration int    `json:"duration"` // 秒
+	Parallel int    `json:"parallel"`
+	// Reverse 为真时由服务器发送，即测试下载方向
+	Reverse bool `json:"reverse,omitempty"`
+	// Bind 非空时作为 -B 参数，从该本机地址发起测试
+	Bind string `json:"bind,omitempty"`
+	// Bitrate 非 0 时作为 -b 参数限制每条流的速率，单位 Mbps
+	Bitrate int `json:"bitrate,omitempty"`
+}
+
+// ParseTarget 解析一行配置，格式为 "host[:port] [-t 秒] [-P 并发数] [-R]"，IPv6 地址写成 [addr]:port
+func ParseTarget(line string) (Target, error) {
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return Target{}, errors.New("empty iperf3 target")
+	}
+	target := Target{Port: DefaultPort, Duration: DefaultDuration, Parallel: 1}
+	host, port, err := net.SplitHostPort(fields[0])
+	if err != nil {
+		// 没有端口：普通主机名或不带方括号的 IPv6 地址
+		host = strings.Trim(fields[0], "[]")
+	} else {
+		if target.Port, err = strconv.Atoi(port); err != nil || target.Port <= 0 || target.Port > 65535 {
+			return Target{}, fmt.Errorf("invalid port %q", port)
+		}
+	}
+	if host == "" || strings.HasPrefix(host, "-") {
+		return Target{}, fmt.Errorf("invalid iperf3 host %q", fields[0])
+	}
+	target.Host = host
+	for i := 1; i < len(fields); i++ {
+		switch flag := fields[i]; flag {
+		case "-R":
+			target.Reverse = true
+		case "-t", "-P":
+			if i+1 >= len(fields) {
+				return Target{}, fmt.Errorf("%s needs a value", flag)
+			}
+			i++
+			value, err := strconv.Atoi(fields[i])
+			if flag == "-t" {
+				if err != nil || value <= 0 || value > maxDuration {
+					return Target{}, fmt.Errorf("duration must be 1-%d seconds", maxDuration)
+				}
+				target.Duration = value
+			} else {
+				if err != nil || value <= 0 || value > maxParallel {
+					return Target{}, fmt.Errorf("parallel streams must be 1-%d", maxParallel)
+				}
+				target.Parallel = value
+			}
+		default:
+			return Target{}, fmt.Errorf("unknown option %q (supported: -t, -P, -R)", flag)
+		}
+	}
+	return target, nil
+}
+
+// ParseTargets 按行解析配置，跳过空行与 # 开头的注释；返回所有有效目标以及第一条错误（带行号）
+func ParseTargets(text string) ([]Target, error) {
+	var targets []Target
+	var firstErr error
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		target, err := ParseTarget(line)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("line %d: %w", i+1, err)
+			}
+			continue
+		}
+		targets = append(targets, target)
+	}
+	return targets, firstErr
+}
+
+// Address 返回 host:port
+func (t Target) Address() string {
+	return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+}
+
+// String 返回可被 ParseTarget 解析的配置行，省略默认值
+func (t Target) String() string {
+	parts := []string{t.Address()}
+	if t.Duration > 0 && t.Duration != DefaultDuration {
+		parts = append(parts, "-t", strconv.Itoa(t.Duration))
+	}
+	if t.Parallel > 1 {
+		parts = append(parts, "-P", strconv.Itoa(t.Parallel))
+	}
+	if t.Reverse {
+		parts = append(parts, "-R")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Args 返回 iperf3 客户端参数，始终输出 JSON
+func (t Target) Args() []string {
+	args := []string{"-c", t.Host, "-p", strconv.Itoa(t.Port), "-t", strconv.Itoa(max(t.Duration, 1)), "-P", strconv.Itoa(max(t.Parallel, 1)), "-J"}
+	if t.Reverse {
+		args = append(args, "-R")
+	}
+	if t.Bind != "" {
+		args = append(args, "-B", t.Bind)
+	}
+	if t.Bitrate > 0 {
+		args = append(args, "-b", strconv.Itoa(t.Bitrate)+"M")
+	}
+	return args
+}
+
+// Result 是一个目标的测试结果，速率单位为 Mbps
+type Result struct {
+	Target       Target  `json:"target"`
+	SentMbps     float64 `json:"sent_mbps"`
+	ReceivedMbps float64 `json:"received_mbps"`
+	Retransmits  int     `json:"retransmits,omitempty"`
+	Error        string  `json:"error,omitempty"`
+}
+
+// report 是 iperf3 -J 输出中用到的部分
+type report struct {
+	End struct {
+		SumSent struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+			Retransmits   int     `json:"retransmits"`
+		} `json:"sum_sent"`
+		SumReceived struct {
+			BitsPerSecond float64 `json:"bits_per_second"`
+		} `json:"sum_received"`
+	} `json:"end"`
+	Error string `json:"error"`
+}
+
+// ParseJSON 解析 iperf3 -J 的输出；iperf3 报告的错误作为 error 返回
+func ParseJSON(data []byte) (Result, error) {
+	var r report
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Result{}, fmt.Errorf("decode iperf3 output: %w", err)
+	}
+	if r.Error != "" {
+		return Result{}, errors.New(r.Error)
+	}
+	return Result{
+		SentMbps:     r.End.SumSent.BitsPerSecond / 1e6,
+		ReceivedMbps: r.End.SumReceived.BitsPerSecond / 1e6,
+		Retransmits:  r.End.SumSent.Retransmits,
+	}, nil
+}
+
+// Run 使用 binary（通常为 "iperf3"）测试一个目标；失败时 Result.Error 与返回的 error 相同
+func Run(ctx context.Context, binary string, target Target) (Result, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, target.Args()...)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	runErr := cmd.Run()
+	// iperf3 出错时仍会输出带 error 字段的 JSON，优先使用其中的说明
+	result, err := ParseJSON(stdout.Bytes())
+	if err == nil && runErr != nil {
+		err = runErr
+	}
+	if err != nil && stdout.Len() == 0 {
+		err = runErr
+		if message := strings.TrimSpace(stderr.String()); message != "" {
+			err = fmt.Errorf("%w: %s", runErr, message)
+		}
+	}
+	result.Target = target
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, err
+}
@@ -0,0 +1,71 @@
+package iperf
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := ParseTargets("# lab\niperf.example.com\n[2001:db8::1]:5202 -t 5 -P 4 -R\n\nbad -x\n10.0.0.1:0\n")
+	if err == nil || !strings.Contains(err.Error(), "line 5") {
+		t.Fatalf("ParseTargets() error = %v, want line 5", err)
+	}
+	if len(targets) != 2 {
+		t.Fatalf("targets = %+v", targets)
+	}
+	if targets[0] != (Target{Host: "iperf.example.com", Port: DefaultPort, Duration: DefaultDuration, Parallel: 1}) {
+		t.Fatalf("targets[0] = %+v", targets[0])
+	}
+	v6 := targets[1]
+	if v6.Host != "2001:db8::1" || v6.Port != 5202 || v6.Duration != 5 || v6.Parallel != 4 || !v6.Reverse {
+		t.Fatalf("targets[1] = %+v", v6)
+	}
+	if got := v6.String(); got != "[2001:db8::1]:5202 -t 5 -P 4 -R" {
+		t.Fatalf("String() = %q", got)
+	}
+	if got := strings.Join(v6.Args(), " "); got != "-c 2001:db8::1 -p 5202 -t 5 -P 4 -J -R" {
+		t.Fatalf("Args() = %q", got)
+	}
+	v6.Bind = "10.8.0.2"
+	if got := strings.Join(v6.Args(), " "); !strings.HasSuffix(got, "-R -B 10.8.0.2") || v6.String() != "[2001:db8::1]:5202 -t 5 -P 4 -R" {
+		t.Fatalf("Args() with bind = %q", got)
+	}
+	v6.Bitrate = 50
+	if got := strings.Join(v6.Args(), " "); !strings.HasSuffix(got, "-B 10.8.0.2 -b 50M") {
+		t.Fatalf("Args() with bitrate = %q", got)
+	}
+	for _, line := range []string{"host -t 0", "host -P", "host -t 999", "-R"} {
+		if _, err := ParseTarget(line); err == nil {
+			t.Errorf("ParseTarget(%q) accepted", line)
+		}
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	result, err := ParseJSON([]byte(`{"end":{"sum_sent":{"bits_per_second":945000000,"retransmits":12},"sum_received":{"bits_per_second":940000000}}}`))
+	if err != nil || result.SentMbps != 945 || result.ReceivedMbps != 940 || result.Retransmits != 12 {
+		t.Fatalf("ParseJSON() = %+v, %v", result, err)
+	}
+	if _, err := ParseJSON([]byte(`{"start":{},"end":{},"error":"unable to connect to server: Connection refused"}`)); err == nil || !strings.Contains(err.Error(), "Connection refused") {
+		t.Fatalf("ParseJSON(error) = %v", err)
+	}
+}
+
+func TestRunReportsIperfError(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the iperf3 binary")
+	}
+	binary := filepath.Join(t.TempDir(), "iperf3")
+	script := "#!/bin/sh\necho '{\"error\":\"the server is busy running a test. try again later\"}'\nexit 1\n"
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Run(context.Background(), binary, Target{Host: "h", Port: 5201})
+	if err == nil || !strings.Contains(result.Error, "server is busy") || result.Target.Host != "h" {
+		t.Fatalf("Run() = %+v, %v", result, err)
+	}
+}
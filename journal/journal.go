@@ -0,0 +1,141 @@
+// Package journal 在测试运行期间把输出与已完成的阶段实时写入磁盘，
+// 程序崩溃或被强制结束后，下次启动可据此恢复日志并只重跑剩余阶段。
+package journal
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	sessionFileName = "session.json"
+	outputFileName  = "output.log"
+)
+
+// ErrNotFound 表示目录中没有未结束的会话
+var ErrNotFound = errors.New("no interrupted session")
+
+// Session 描述一次运行：目标、选中的测试项以及已经完成的阶段
+type Session struct {
+	Host      string    `json:"host"`
+	Preset    string    `json:"preset,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	// Tests 是本次运行选中的测试项键，与界面勾选项一致
+	Tests []string `json:"tests"`
+	// Completed 是按完成顺序记录的阶段键
+	Completed []string `json:"completed,omitempty"`
+}
+
+// Journal 是一次运行的日志，可被多个 goroutine 并发使用
+type Journal struct {
+	dir     string
+	mu      sync.Mutex
+	session Session
+	output  *os.File
+}
+
+// Start 在 dir 中开始新的会话，覆盖上一次残留的会话
+func Start(dir string, session Session) (*Journal, error) {
+	if strings.TrimSpace(dir) == "" {
+		return nil, errors.New("journal directory is empty")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	output, err := os.OpenFile(filepath.Join(dir, outputFileName), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	j := &Journal{dir: dir, session: session, output: output}
+	if err := j.writeSession(); err != nil {
+		output.Close()
+		return nil, err
+	}
+	return j, nil
+}
+
+// Write 追加一段输出。写入失败只影响崩溃恢复，不打断测试，因此忽略错误
+func (j *Journal) Write(text string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.output != nil {
+		_, _ = j.output.WriteString(text)
+	}
+}
+
+// Complete 记录一个阶段已完成
+func (j *Journal) Complete(stage string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.output == nil || slices.Contains(j.session.Completed, stage) {
+		return
+	}
+	j.session.Completed = append(j.session.Completed, stage)
+	_ = j.writeSession()
+}
+
+// Close 在运行正常结束（完成、失败或停止）后删除会话，之后不会再提示恢复
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.output == nil {
+		return nil
+	}
+	j.output.Close()
+	j.output = nil
+	return Discard(j.dir)
+}
+
+func (j *Journal) writeSession() error {
+	data, err := json.MarshalIndent(j.session, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(j.dir, sessionFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load 读取未结束的会话及其输出，updated 为输出最后写入的时间
+func Load(dir string) (session Session, output string, updated time.Time, err error) {
+	data, err := os.ReadFile(filepath.Join(dir, sessionFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return Session{}, "", time.Time{}, ErrNotFound
+	}
+	if err != nil {
+		return Session{}, "", time.Time{}, err
+	}
+	if err := json.Unmarshal(data, &session); err != nil {
+		return Session{}, "", time.Time{}, err
+	}
+	path := filepath.Join(dir, outputFileName)
+	raw, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Session{}, "", time.Time{}, err
+	}
+	updated = session.StartedAt
+	if info, statErr := os.Stat(path); statErr == nil {
+		updated = info.ModTime()
+	}
+	return session, string(raw), updated, nil
+}
+
+// Discard 删除会话文件
+func Discard(dir string) error {
+	var errs []error
+	for _, name := range []string{sessionFileName, outputFileName} {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,65 @@
+package journal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestJournalSurvivesUntilClosed(t *testing.T) {
+	dir := t.TempDir()
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	j, err := Start(dir, Session{Host: "10.0.0.1", StartedAt: started, Tests: []string{"cpu", "disk"}})
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	j.Write("cpu ok\n")
+	j.Complete("progress.cpu")
+	j.Complete("progress.cpu")
+	j.Write("disk ...")
+
+	// 模拟崩溃：不调用 Close，直接读取磁盘上的内容
+	session, output, updated, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if session.Host != "10.0.0.1" || !session.StartedAt.Equal(started) || len(session.Completed) != 1 || session.Completed[0] != "progress.cpu" {
+		t.Fatalf("unexpected session %+v", session)
+	}
+	if output != "cpu ok\ndisk ..." {
+		t.Fatalf("output = %q", output)
+	}
+	if updated.Before(started) {
+		t.Fatalf("updated = %v, want after start", updated)
+	}
+
+	if err := j.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	j.Write("ignored")
+	if _, _, _, err := Load(dir); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load() after Close error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStartReplacesLeftoverSession(t *testing.T) {
+	dir := t.TempDir()
+	old, err := Start(dir, Session{Host: "old"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	old.Write("old output")
+	if _, err := Start(dir, Session{Host: "new"}); err != nil {
+		t.Fatal(err)
+	}
+	session, output, _, err := Load(dir)
+	if err != nil || session.Host != "new" || output != "" {
+		t.Fatalf("Load() = %+v, %q, %v", session, output, err)
+	}
+	if err := Discard(dir); err != nil {
+		t.Fatalf("Discard() error = %v", err)
+	}
+	if err := Discard(dir); err != nil {
+		t.Fatalf("Discard() on empty dir error = %v", err)
+	}
+}
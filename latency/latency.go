@@ -0,0 +1,175 @@
+// Package latency 解析延迟工具的目标列表，执行 TCP 连接探测并汇总 ICMP ping 输出。
+package latency
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// Target 是一个探测目标；Port 为 0 时使用 ICMP，否则探测 TCP 端口
+type Target struct {
+	Host string `json:"host"`
+	Port int    `json:"port,omitempty"`
+}
+
+// Protocol 返回 "icmp" 或 "tcp"
+func (t Target) Protocol() string {
+	if t.Port > 0 {
+		return "tcp"
+	}
+	return "icmp"
+}
+
+// String 返回可被 ParseTargets 解析的写法
+func (t Target) String() string {
+	if t.Port > 0 {
+		return net.JoinHostPort(t.Host, strconv.Itoa(t.Port))
+	}
+	return t.Host
+}
+
+// ParseTarget 解析 "host"（ICMP）或 "host:port"（TCP），IPv6 带端口时写成 [addr]:port
+func ParseTarget(text string) (Target, error) {
+	text = strings.TrimSpace(text)
+	host, port, err := net.SplitHostPort(text)
+	if err != nil {
+		// 没有端口：普通主机名或不带方括号的 IPv6 地址
+		host = strings.Trim(text, "[]")
+		port = ""
+	}
+	if host == "" || strings.ContainsAny(host, " /") || strings.HasPrefix(host, "-") {
+		return Target{}, fmt.Errorf("invalid host %q", text)
+	}
+	target := Target{Host: host}
+	if port != "" {
+		if target.Port, err = strconv.Atoi(port); err != nil || target.Port <= 0 || target.Port > 65535 {
+			return Target{}, fmt.Errorf("invalid port %q", port)
+		}
+	}
+	return target, nil
+}
+
+// ParseTargets 解析粘贴的目标列表，目标之间可用换行、空格、逗号或分号分隔，
+// # 开头的行为注释；重复目标只保留一个。返回所有有效目标以及第一条错误（带行号）
+func ParseTargets(text string) ([]Target, error) {
+	var targets []Target
+	var firstErr error
+	seen := map[string]bool{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		for _, field := range strings.FieldsFunc(line, func(r rune) bool { return r == ',' || r == ';' || r == ' ' || r == '\t' }) {
+			target, err := ParseTarget(field)
+			if err != nil {
+				if firstErr == nil {
+					firstErr = fmt.Errorf("line %d: %w", i+1, err)
+				}
+				continue
+			}
+			if !seen[target.String()] {
+				seen[target.String()] = true
+				targets = append(targets, target)
+			}
+		}
+	}
+	return targets, firstErr
+}
+
+var (
+	pingRTTPattern  = regexp.MustCompile(`(?:time|时间)\s*[=<]\s*([\d.]+)\s*ms`)
+	pingSentPattern = regexp.MustCompile(`(\d+)\s+packets transmitted|(?:Sent|已发送)\s*=\s*(\d+)`)
+)
+
+// ParsePingOutput 从系统 ping 的输出中取出每个回复的往返时间，支持 Linux/macOS 与中英文 Windows；
+// 没有汇总行时发送数为 0。Windows 的 "time<1ms" 记为 1ms
+func ParsePingOutput(output string) (sent int, rtts []float64) {
+	for _, line := range strings.Split(output, "\n") {
+		if match := pingRTTPattern.FindStringSubmatch(line); match != nil {
+			if value, err := strconv.ParseFloat(match[1], 64); err == nil {
+				rtts = append(rtts, value)
+			}
+			continue
+		}
+		if match := pingSentPattern.FindStringSubmatch(line); match != nil {
+			sent, _ = strconv.Atoi(match[1] + match[2])
+		}
+	}
+	return sent, rtts
+}
+
+// Summarize 汇总一个目标的探测结果；sent 小于回复数时以回复数为准
+func Summarize(target Target, sent int, rtts []float64) results.LatencyResult {
+	result := results.LatencyResult{Target: target.String(), Protocol: target.Protocol(), Sent: max(sent, len(rtts)), Received: len(rtts)}
+	if len(rtts) == 0 {
+		return result
+	}
+	result.MinMs, result.MaxMs = rtts[0], rtts[0]
+	total := 0.0
+	for _, rtt := range rtts {
+		result.MinMs = min(result.MinMs, rtt)
+		result.MaxMs = max(result.MaxMs, rtt)
+		total += rtt
+	}
+	result.AvgMs = total / float64(len(rtts))
+	return result
+}
+
+// TCPInterval 是两次 TCP 连接探测之间的间隔
+var TCPInterval = 200 * time.Millisecond
+
+// ProbeTCP 对目标端口发起 count 次 TCP 连接，以建立连接的耗时作为延迟；
+// 全部失败时 Error 为最后一次的错误
+func ProbeTCP(ctx context.Context, target Target, count int, timeout time.Duration) results.LatencyResult {
+	return ProbeTCPWith(ctx, &net.Dialer{Timeout: timeout}, target, count)
+}
+
+// ProbeTCPWith 与 ProbeTCP 相同，但使用调用方提供的 dialer（例如绑定到指定网卡）
+func ProbeTCPWith(ctx context.Context, dialer *net.Dialer, target Target, count int) results.LatencyResult {
+	if target.Port == 0 {
+		return results.LatencyResult{Target: target.String(), Protocol: target.Protocol(), Error: "no TCP port"}
+	}
+	var rtts []float64
+	var lastErr error
+	sent := 0
+	for i := 0; i < count && ctx.Err() == nil; i++ {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(TCPInterval):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		sent++
+		started := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", target.String())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		rtts = append(rtts, float64(time.Since(started).Microseconds())/1000)
+		conn.Close()
+	}
+	result := Summarize(target, sent, rtts)
+	if len(rtts) == 0 {
+		if lastErr == nil {
+			lastErr = ctx.Err()
+		}
+		if lastErr == nil {
+			lastErr = errors.New("no probes sent")
+		}
+		result.Error = lastErr.Error()
+	}
+	return result
+}
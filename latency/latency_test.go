@@ -0,0 +1,92 @@
+package latency
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := ParseTargets("# my vps\n1.1.1.1, example.com:443\n[2001:db8::1]:22 2001:db8::2;1.1.1.1\nbad:port")
+	if err == nil || !strings.Contains(err.Error(), "line 4") {
+		t.Fatalf("ParseTargets() error = %v", err)
+	}
+	want := []string{"1.1.1.1", "example.com:443", "[2001:db8::1]:22", "2001:db8::2"}
+	if len(targets) != len(want) {
+		t.Fatalf("ParseTargets() = %+v", targets)
+	}
+	for i, target := range targets {
+		if target.String() != want[i] {
+			t.Fatalf("targets[%d] = %q, want %q", i, target.String(), want[i])
+		}
+	}
+	if targets[0].Protocol() != "icmp" || targets[1].Protocol() != "tcp" || targets[3].Protocol() != "icmp" {
+		t.Fatalf("protocols = %+v", targets)
+	}
+}
+
+func TestParsePingOutput(t *testing.T) {
+	cases := []struct {
+		name   string
+		output string
+		sent   int
+		rtts   []float64
+	}{
+		{"linux", "64 bytes from 1.1.1.1: icmp_seq=1 ttl=57 time=1.20 ms\n64 bytes from 1.1.1.1: icmp_seq=3 ttl=57 time=3.40 ms\n\n--- 1.1.1.1 ping statistics ---\n3 packets transmitted, 2 received, 33% packet loss", 3, []float64{1.2, 3.4}},
+		{"windows", "Reply from 1.1.1.1: bytes=32 time=5ms TTL=57\nReply from 1.1.1.1: bytes=32 time<1ms TTL=57\n    Packets: Sent = 4, Received = 2, Lost = 2 (50% loss),", 4, []float64{5, 1}},
+		{"windows-zh", "来自 1.1.1.1 的回复: 字节=32 时间=12ms TTL=57\n    数据包: 已发送 = 2，已接收 = 1，丢失 = 1 (50% 丢失)，", 2, []float64{12}},
+	}
+	for _, tc := range cases {
+		sent, rtts := ParsePingOutput(tc.output)
+		if sent != tc.sent || len(rtts) != len(tc.rtts) {
+			t.Fatalf("%s: ParsePingOutput() = %d, %v", tc.name, sent, rtts)
+		}
+		for i := range rtts {
+			if rtts[i] != tc.rtts[i] {
+				t.Fatalf("%s: rtts = %v, want %v", tc.name, rtts, tc.rtts)
+			}
+		}
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	result := Summarize(Target{Host: "1.1.1.1"}, 4, []float64{2, 4, 6})
+	if result.MinMs != 2 || result.AvgMs != 4 || result.MaxMs != 6 || result.LossPercent() != 25 || result.Protocol != "icmp" {
+		t.Fatalf("Summarize() = %+v", result)
+	}
+}
+
+func TestProbeTCP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	old := TCPInterval
+	TCPInterval = time.Millisecond
+	t.Cleanup(func() { TCPInterval = old })
+
+	target, err := ParseTarget(listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := ProbeTCP(context.Background(), target, 3, time.Second)
+	if result.Sent != 3 || result.Received != 3 || result.Error != "" || result.Protocol != "tcp" {
+		t.Fatalf("ProbeTCP() = %+v", result)
+	}
+	listener.Close()
+	if result := ProbeTCP(context.Background(), target, 2, time.Second); result.Received != 0 || result.Error == "" {
+		t.Fatalf("ProbeTCP(closed) = %+v", result)
+	}
+}
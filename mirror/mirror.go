@@ -0,0 +1,265 @@
+// Package mirror 为 GitHub 发布包下载提供 ghproxy 式镜像：把 github.com 等地址加上镜像前缀后请求，
+// 支持内置与自定义镜像、按延迟自动选择，镜像失败时回退直连。下载内容仍按发布的 SHA-256 校验。
+package mirror
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Auto 表示按延迟自动选择镜像
+const Auto = "auto"
+
+// probeTarget 是测速用的小文件，内容为 success，与 goecs 安装脚本检测 CDN 的方式一致
+const probeTarget = "https://raw.githubusercontent.com/spiritLHLS/ecs/main/back/test"
+
+// probeTimeout 是单个镜像测速的超时
+const probeTimeout = 6 * time.Second
+
+// Mirror 是一个镜像，URL 为带结尾斜杠的前缀，如 https://ghfast.top/
+type Mirror struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Builtin 是内置镜像，前四个与 goecs 安装脚本使用的 CDN 相同
+var Builtin = []Mirror{
+	{Name: "spiritlhl CDN 0", URL: "https://cdn0.spiritlhl.top/"},
+	{Name: "spiritlhl CDN 1", URL: "http://cdn1.spiritlhl.net/"},
+	{Name: "spiritlhl CDN 2", URL: "http://cdn2.spiritlhl.net/"},
+	{Name: "spiritlhl CDN 3", URL: "http://cdn3.spiritlhl.net/"},
+	{Name: "ghfast", URL: "https://ghfast.top/"},
+}
+
+// mirroredHosts 是经镜像下载的地址，api.github.com 不经过镜像
+var mirroredHosts = []string{"github.com", "raw.githubusercontent.com", "objects.githubusercontent.com", "release-assets.githubusercontent.com"}
+
+// Settings 是镜像设置
+type Settings struct {
+	// Mode 为空时直连 GitHub，为 Auto 时自动选择，否则为所用镜像的 URL
+	Mode   string   `json:"mode,omitempty"`
+	Custom []Mirror `json:"custom,omitempty"`
+}
+
+// Normalize 校验镜像前缀并补上结尾斜杠
+func Normalize(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid mirror URL %q", raw)
+	}
+	if !strings.HasSuffix(raw, "/") {
+		raw += "/"
+	}
+	return raw, nil
+}
+
+// Mirrors 返回内置与自定义镜像
+func (s Settings) Mirrors() []Mirror {
+	return append(slices.Clone(Builtin), s.Custom...)
+}
+
+// Validate 检查自定义镜像与所选镜像
+func (s Settings) Validate() error {
+	for _, m := range s.Custom {
+		if strings.TrimSpace(m.Name) == "" {
+			return errors.New("mirror name is empty")
+		}
+		if _, err := Normalize(m.URL); err != nil {
+			return err
+		}
+	}
+	if s.Mode != "" && s.Mode != Auto {
+		if _, err := Normalize(s.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Rewrite 为 GitHub 下载地址加上镜像前缀，其他地址原样返回
+func Rewrite(prefix, raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || prefix == "" || !mirrorable(u) {
+		return raw
+	}
+	return prefix + raw
+}
+
+func mirrorable(u *url.URL) bool {
+	return slices.Contains(mirroredHosts, u.Hostname())
+}
+
+// Result 是一个镜像的测速结果
+type Result struct {
+	Mirror  Mirror
+	Latency time.Duration
+	Err     error
+}
+
+// Probe 并发测速，结果按延迟从低到高排列，失败的排在最后。client 为 nil 时使用 http.DefaultClient。
+func Probe(ctx context.Context, client *http.Client, mirrors []Mirror) []Result {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	results := make([]Result, len(mirrors))
+	var wg sync.WaitGroup
+	for i, m := range mirrors {
+		wg.Go(func() {
+			start := time.Now()
+			err := probe(ctx, client, m.URL)
+			results[i] = Result{Mirror: m, Latency: time.Since(start), Err: err}
+		})
+	}
+	wg.Wait()
+	slices.SortStableFunc(results, func(a, b Result) int {
+		if (a.Err == nil) != (b.Err == nil) {
+			if a.Err == nil {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Compare(a.Latency, b.Latency)
+	})
+	return results
+}
+
+func probe(ctx context.Context, client *http.Client, prefix string) error {
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, prefix+probeTarget, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "success") {
+		return fmt.Errorf("probe %s: %s", prefix, resp.Status)
+	}
+	return nil
+}
+
+// Client 返回按镜像设置下载的客户端：GitHub 下载地址改走镜像，镜像出错时回退直连，
+// 其余请求与 base 相同。自动选择在第一次下载时测速，结果在该客户端内复用。
+func (s Settings) Client(base *http.Client) *http.Client {
+	if base == nil {
+		base = http.DefaultClient
+	}
+	if s.Mode == "" {
+		return base
+	}
+	next := base.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	client := *base
+	client.Transport = &transport{next: next, probeClient: base, settings: s}
+	return &client
+}
+
+type transport struct {
+	next        http.RoundTripper
+	probeClient *http.Client
+	settings    Settings
+
+	once   sync.Once
+	prefix string
+}
+
+// resolve 返回要使用的镜像前缀，空字符串表示直连
+func (t *transport) resolve(ctx context.Context) string {
+	t.once.Do(func() {
+		if t.settings.Mode != Auto {
+			t.prefix, _ = Normalize(t.settings.Mode)
+			return
+		}
+		if results := Probe(ctx, t.probeClient, t.settings.Mirrors()); len(results) > 0 && results[0].Err == nil {
+			t.prefix = results[0].Mirror.URL
+		}
+	})
+	return t.prefix
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || !mirrorable(req.URL) {
+		return t.next.RoundTrip(req)
+	}
+	prefix := t.resolve(req.Context())
+	if prefix == "" {
+		return t.next.RoundTrip(req)
+	}
+	mirrored, err := url.Parse(Rewrite(prefix, req.URL.String()))
+	if err != nil {
+		return t.next.RoundTrip(req)
+	}
+	clone := req.Clone(req.Context())
+	clone.URL, clone.Host = mirrored, ""
+	resp, err := t.next.RoundTrip(clone)
+	if err == nil && resp.StatusCode < http.StatusBadRequest {
+		return resp, nil
+	}
+	if err == nil {
+		resp.Body.Close()
+	}
+	if req.Context().Err() != nil {
+		return nil, req.Context().Err()
+	}
+	return t.next.RoundTrip(req)
+}
+
+// Load 读取镜像设置，文件不存在时返回空设置（直连 GitHub）
+func Load(path string) (Settings, error) {
+	var settings Settings
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// Save 校验并保存镜像设置
+func Save(path string, settings Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	for i, m := range settings.Custom {
+		settings.Custom[i].Name = strings.TrimSpace(m.Name)
+		settings.Custom[i].URL, _ = Normalize(m.URL)
+	}
+	if settings.Mode != "" && settings.Mode != Auto {
+		settings.Mode, _ = Normalize(settings.Mode)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
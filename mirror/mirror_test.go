@@ -0,0 +1,119 @@
+package mirror
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// startMirror 模拟 ghproxy 式镜像：路径为完整的原始地址，delay 为应答延迟，ok 为 false 时返回 502
+func startMirror(t *testing.T, delay time.Duration, ok bool, hits *atomic.Int32) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		if hits != nil {
+			hits.Add(1)
+		}
+		if !ok {
+			http.Error(w, "bad gateway", http.StatusBadGateway)
+			return
+		}
+		original := strings.TrimPrefix(r.URL.Path, "/")
+		if original == probeTarget {
+			io.WriteString(w, "success")
+			return
+		}
+		io.WriteString(w, "mirrored "+original)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/"
+}
+
+func TestRewriteOnlyTouchesGitHubDownloads(t *testing.T) {
+	cases := map[string]string{
+		"https://github.com/oneclickvirt/ecs/releases/download/v0.1.171/goecs_linux_amd64.zip": "https://m.example/https://github.com/oneclickvirt/ecs/releases/download/v0.1.171/goecs_linux_amd64.zip",
+		"https://api.github.com/repos/oneclickvirt/ecs/releases":                               "https://api.github.com/repos/oneclickvirt/ecs/releases",
+		"https://example.com/file.zip":                                                         "https://example.com/file.zip",
+	}
+	for raw, want := range cases {
+		if got := Rewrite("https://m.example/", raw); got != want {
+			t.Errorf("Rewrite(%q) = %q, want %q", raw, got, want)
+		}
+	}
+}
+
+func TestProbeOrdersByLatency(t *testing.T) {
+	slow := Mirror{Name: "slow", URL: startMirror(t, 80*time.Millisecond, true, nil)}
+	fast := Mirror{Name: "fast", URL: startMirror(t, 0, true, nil)}
+	broken := Mirror{Name: "broken", URL: startMirror(t, 0, false, nil)}
+	results := Probe(context.Background(), nil, []Mirror{broken, slow, fast})
+	if results[0].Mirror.Name != "fast" || results[1].Mirror.Name != "slow" || results[2].Err == nil {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestClientAutoSelectsAndFallsBack(t *testing.T) {
+	old := Builtin
+	t.Cleanup(func() { Builtin = old })
+	var brokenHits atomic.Int32
+	Builtin = []Mirror{{Name: "broken", URL: startMirror(t, 0, false, &brokenHits)}}
+	fast := startMirror(t, 0, true, nil)
+
+	client := Settings{Mode: Auto, Custom: []Mirror{{Name: "fast", URL: fast}}}.Client(nil)
+	resp, err := client.Get("https://github.com/oneclickvirt/ecs/releases/download/v1/goecs.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "mirrored https://github.com/oneclickvirt/ecs/releases/download/v1/goecs.zip" {
+		t.Fatalf("body = %q", body)
+	}
+
+	// 固定使用的镜像出错时回退直连：直连地址是本地服务，可以直接验证
+	direct := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { io.WriteString(w, "direct") }))
+	defer direct.Close()
+	pinned := Settings{Mode: Builtin[0].URL}.Client(nil)
+	pinned.Transport.(*transport).next = rewriteHost{to: direct.URL}
+	resp, err = pinned.Get("https://github.com/x.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "direct" {
+		t.Fatalf("fallback body = %q", body)
+	}
+}
+
+// rewriteHost 把发往 github.com 的请求转到本地服务，其余请求照常发送
+type rewriteHost struct{ to string }
+
+func (r rewriteHost) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.URL.Host == "github.com" {
+		clone := req.Clone(req.Context())
+		clone.URL.Scheme, clone.URL.Host = "http", strings.TrimPrefix(r.to, "http://")
+		return http.DefaultTransport.RoundTrip(clone)
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSaveNormalizesMirrors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mirrors.json")
+	if err := Save(path, Settings{Mode: "https://m.example", Custom: []Mirror{{Name: " mine ", URL: "https://m.example"}}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := Load(path)
+	if err != nil || got.Mode != "https://m.example/" || got.Custom[0] != (Mirror{Name: "mine", URL: "https://m.example/"}) {
+		t.Fatalf("Load() = %+v, %v", got, err)
+	}
+	if err := Save(path, Settings{Custom: []Mirror{{Name: "x", URL: "ftp://m"}}}); err == nil {
+		t.Fatal("Save() should reject non-HTTP mirrors")
+	}
+}
@@ -0,0 +1,122 @@
+// Package mtr 以系统 ping 实现类似 MTR 的持续路由质量监测：先逐个 TTL 发现路径上的各跳，
+// 再按轮次直接 ping 每一跳并累计丢包与延迟，用于发现一次性路由追踪看不到的间歇丢包。
+package mtr
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"sync"
+
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// MaxHops 是发现路径时的最大 TTL
+const MaxHops = 30
+
+// discoverConcurrency 是发现路径时同时运行的 ping 进程数
+const discoverConcurrency = 8
+
+// ErrNoRoute 表示没有任何一跳回应探测
+var ErrNoRoute = errors.New("no hop replied")
+
+// Pinger 向 host 发送一个 ICMP 探测包并返回 ping 的输出；ttl 为 0 时不限制 TTL
+type Pinger func(ctx context.Context, host string, ttl int) (string, error)
+
+// responderPattern 取出回应者的地址，支持 Linux/macOS 与中英文 Windows：
+// "From 10.0.0.1 icmp_seq=1 Time to live exceeded"、"64 bytes from 1.1.1.1: ..."、
+// "Reply from 10.0.0.1: TTL expired in transit."、"来自 10.0.0.1 的回复: TTL 传输中过期。"
+var responderPattern = regexp.MustCompile(`(?i)(?:from|来自)\s+(?:\S+\s+\()?([0-9a-f]*[.:][0-9a-f.:]*[0-9a-f])\)?`)
+
+// ParseHop 从一次限定 TTL 的 ping 输出中取出回应者地址；带有往返时间的回复说明已到达目标
+func ParseHop(output string) (addr string, reached bool) {
+	if m := responderPattern.FindStringSubmatch(output); m != nil {
+		addr = m[1]
+	}
+	_, rtts := latency.ParsePingOutput(output)
+	return addr, addr != "" && len(rtts) > 0
+}
+
+// Discover 以 TTL 1 到 maxHops 各 ping 一次目标，返回到达目标为止的各跳，不回应的跳 Host 为空；
+// 未到达目标时截去末尾不回应的跳，一跳都没有回应时返回 ErrNoRoute
+func Discover(ctx context.Context, ping Pinger, host string, maxHops int) ([]results.MonitorHop, error) {
+	addrs := make([]string, maxHops)
+	reached := make([]bool, maxHops)
+	sem := make(chan struct{}, discoverConcurrency)
+	var wg sync.WaitGroup
+	for ttl := 1; ttl <= maxHops; ttl++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+			out, _ := ping(ctx, host, ttl)
+			addrs[ttl-1], reached[ttl-1] = ParseHop(out)
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	last := -1
+	for i := range addrs {
+		if reached[i] {
+			last = i
+			break
+		}
+		if addrs[i] != "" {
+			last = i
+		}
+	}
+	if last < 0 {
+		return nil, ErrNoRoute
+	}
+	hops := make([]results.MonitorHop, last+1)
+	for i := range hops {
+		hops[i] = results.MonitorHop{Hop: i + 1, Host: addrs[i]}
+	}
+	return hops, nil
+}
+
+// Probe 直接 ping 一跳一次，返回往返时间；没有回复时 ok 为假
+func Probe(ctx context.Context, ping Pinger, host string) (rtt float64, ok bool) {
+	out, _ := ping(ctx, host, 0)
+	if _, rtts := latency.ParsePingOutput(out); len(rtts) > 0 {
+		return rtts[0], true
+	}
+	return 0, false
+}
+
+// Round 并发探测每个有地址的跳一次并累计到 hops 中；ctx 取消后不再记录结果
+func Round(ctx context.Context, ping Pinger, hops []results.MonitorHop) {
+	type sample struct {
+		rtt float64
+		ok  bool
+	}
+	samples := make([]sample, len(hops))
+	var wg sync.WaitGroup
+	for i, hop := range hops {
+		if hop.Host == "" {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			samples[i].rtt, samples[i].ok = Probe(ctx, ping, hop.Host)
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+	for i := range hops {
+		if hops[i].Host != "" {
+			hops[i].Record(samples[i].rtt, samples[i].ok)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package mtr
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestParseHop(t *testing.T) {
+	cases := []struct {
+		out     string
+		addr    string
+		reached bool
+	}{
+		{"PING 1.1.1.1 (1.1.1.1) 56(84) bytes of data.\nFrom 10.0.0.1 icmp_seq=1 Time to live exceeded\n", "10.0.0.1", false},
+		{"From _gateway (192.168.1.1) icmp_seq=1 Time to live exceeded\n", "192.168.1.1", false},
+		{"64 bytes from 1.1.1.1: icmp_seq=1 ttl=57 time=3.21 ms\n", "1.1.1.1", true},
+		{"92 bytes from 172.16.0.1: Time to live exceeded\n", "172.16.0.1", false},
+		{"Pinging 1.1.1.1 with 32 bytes of data:\r\nReply from 10.0.0.1: TTL expired in transit.\r\n", "10.0.0.1", false},
+		{"来自 1.1.1.1 的回复: 字节=32 时间=3ms TTL=57\r\n", "1.1.1.1", true},
+		{"64 bytes from 2606:4700:4700::1111: icmp_seq=1 ttl=57 time=4.0 ms\n", "2606:4700:4700::1111", true},
+		{"Request timed out.\r\n", "", false},
+	}
+	for _, c := range cases {
+		addr, reached := ParseHop(c.out)
+		if addr != c.addr || reached != c.reached {
+			t.Errorf("ParseHop(%q) = %q, %v; want %q, %v", c.out, addr, reached, c.addr, c.reached)
+		}
+	}
+}
+
+// fakePath 模拟一条 4 跳的路径：第 2 跳不回应，第 4 跳为目标
+func fakePath(ctx context.Context, host string, ttl int) (string, error) {
+	switch {
+	case ttl == 0 && host == "10.0.0.1":
+		return "64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=1.0 ms\n", nil
+	case ttl == 0 && host == "172.16.0.1":
+		return "", errors.New("timeout")
+	case ttl == 0:
+		return "64 bytes from " + host + ": icmp_seq=1 ttl=57 time=20.5 ms\n", nil
+	case ttl == 1:
+		return "From 10.0.0.1 icmp_seq=1 Time to live exceeded\n", nil
+	case ttl == 2:
+		return "", errors.New("timeout")
+	case ttl == 3:
+		return "From 172.16.0.1 icmp_seq=1 Time to live exceeded\n", nil
+	}
+	return "64 bytes from 203.0.113.7: icmp_seq=1 ttl=57 time=20.5 ms\n", nil
+}
+
+func TestDiscoverStopsAtTarget(t *testing.T) {
+	hops, err := Discover(context.Background(), fakePath, "203.0.113.7", MaxHops)
+	if err != nil || len(hops) != 4 {
+		t.Fatalf("Discover() = %+v, %v", hops, err)
+	}
+	if hops[0].Host != "10.0.0.1" || hops[1].Host != "" || hops[3] != (results.MonitorHop{Hop: 4, Host: "203.0.113.7"}) {
+		t.Fatalf("hops = %+v", hops)
+	}
+
+	silent := func(ctx context.Context, host string, ttl int) (string, error) { return "", errors.New("timeout") }
+	if _, err := Discover(context.Background(), silent, "203.0.113.7", 5); !errors.Is(err, ErrNoRoute) {
+		t.Fatalf("Discover(silent) error = %v", err)
+	}
+}
+
+func TestRoundAccumulates(t *testing.T) {
+	hops, err := Discover(context.Background(), fakePath, "203.0.113.7", MaxHops)
+	if err != nil {
+		t.Fatal(err)
+	}
+	Round(context.Background(), fakePath, hops)
+	Round(context.Background(), fakePath, hops)
+	if hops[0].Sent != 2 || hops[0].Received != 2 || hops[0].AvgMs != 1 {
+		t.Fatalf("hop 1 = %+v", hops[0])
+	}
+	if hops[1].Sent != 0 {
+		t.Fatalf("silent hop was probed: %+v", hops[1])
+	}
+	if hops[2].LossPercent() != 100 || hops[3].LossPercent() != 0 || hops[3].LastMs != 20.5 {
+		t.Fatalf("hops = %+v", hops)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Round(ctx, fakePath, hops)
+	if hops[0].Sent != 2 {
+		t.Fatal("canceled round was recorded")
+	}
+}
@@ -0,0 +1,261 @@
+// Package notify 把测试摘要推送到通用 Webhook、Telegram 机器人、Discord Webhook 与 Server酱。
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Kind 是推送渠道类型
+type Kind string
+
+const (
+	KindWebhook    Kind = "webhook"
+	KindTelegram   Kind = "telegram"
+	KindDiscord    Kind = "discord"
+	KindServerChan Kind = "serverchan"
+)
+
+// Kinds 是支持的渠道类型，按界面展示顺序排列
+var Kinds = []Kind{KindWebhook, KindTelegram, KindDiscord, KindServerChan}
+
+// Event 是触发推送的事件
+type Event string
+
+const (
+	EventCompleted  Event = "completed"
+	EventFailed     Event = "failed"
+	EventRegression Event = "regression"
+)
+
+// Events 是可订阅的事件，按界面展示顺序排列
+var Events = []Event{EventCompleted, EventFailed, EventRegression}
+
+// 各平台的消息长度上限
+const (
+	discordContentLimit = 2000
+	telegramTextLimit   = 4096
+)
+
+// 测试中替换为本地服务器
+var (
+	telegramAPIBase   = "https://api.telegram.org"
+	serverChanAPIBase = "https://sctapi.ftqq.com"
+)
+
+// Channel 是一个推送渠道
+type Channel struct {
+	Name    string `json:"name"`
+	Kind    Kind   `json:"kind"`
+	Enabled bool   `json:"enabled"`
+	// URL 用于 Webhook 与 Discord
+	URL string `json:"url,omitempty"`
+	// Token 为 Telegram 机器人令牌或 Server酱 SendKey
+	Token  string  `json:"token,omitempty"`
+	ChatID string  `json:"chat_id,omitempty"`
+	Events []Event `json:"events"`
+}
+
+// Validate 检查渠道必填项
+func (c Channel) Validate() error {
+	if strings.TrimSpace(c.Name) == "" {
+		return errors.New("channel name is empty")
+	}
+	switch c.Kind {
+	case KindWebhook, KindDiscord:
+		u, err := url.Parse(strings.TrimSpace(c.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid webhook URL %q", c.URL)
+		}
+	case KindTelegram:
+		if strings.TrimSpace(c.Token) == "" || strings.TrimSpace(c.ChatID) == "" {
+			return errors.New("telegram requires a bot token and chat ID")
+		}
+	case KindServerChan:
+		if strings.TrimSpace(c.Token) == "" {
+			return errors.New("server chan requires a SendKey")
+		}
+	default:
+		return fmt.Errorf("unknown channel kind %q", c.Kind)
+	}
+	return nil
+}
+
+// Wants 报告渠道是否订阅了事件
+func (c Channel) Wants(event Event) bool {
+	if !c.Enabled {
+		return false
+	}
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Message 是一条推送内容
+type Message struct {
+	Event   Event     `json:"event"`
+	Title   string    `json:"title"`
+	Summary string    `json:"summary"`
+	Host    string    `json:"host,omitempty"`
+	Time    time.Time `json:"time"`
+	// Markdown 为 Markdown 格式的结果表格，可为空
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// Dispatch 把消息发送到所有订阅了该事件的渠道，返回合并后的错误
+func Dispatch(ctx context.Context, client *http.Client, channels []Channel, msg Message) error {
+	var errs []error
+	for _, channel := range channels {
+		if !channel.Wants(msg.Event) {
+			continue
+		}
+		if err := Send(ctx, client, channel, msg); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", channel.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Send 把消息发送到单个渠道，不检查事件订阅
+func Send(ctx context.Context, client *http.Client, channel Channel, msg Message) error {
+	if err := channel.Validate(); err != nil {
+		return err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var req *http.Request
+	var err error
+	switch channel.Kind {
+	case KindWebhook:
+		req, err = jsonRequest(ctx, strings.TrimSpace(channel.URL), msg)
+	case KindDiscord:
+		req, err = jsonRequest(ctx, strings.TrimSpace(channel.URL), map[string]string{
+			"content": plainText(msg, discordContentLimit),
+		})
+	case KindTelegram:
+		endpoint := fmt.Sprintf("%s/bot%s/sendMessage", telegramAPIBase, strings.TrimSpace(channel.Token))
+		req, err = jsonRequest(ctx, endpoint, map[string]any{
+			"chat_id":                  strings.TrimSpace(channel.ChatID),
+			"text":                     telegramText(msg),
+			"parse_mode":               "HTML",
+			"disable_web_page_preview": true,
+		})
+	case KindServerChan:
+		endpoint := fmt.Sprintf("%s/%s.send", serverChanAPIBase, url.PathEscape(strings.TrimSpace(channel.Token)))
+		form := url.Values{"title": {msg.Title}, "desp": {markdownBody(msg)}}
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+		if err == nil {
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		}
+	}
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	// Server酱 出错时仍返回 200，以 code 表示结果
+	if channel.Kind == KindServerChan {
+		var result struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &result) == nil && result.Code != 0 {
+			return fmt.Errorf("server chan error %d: %s", result.Code, result.Message)
+		}
+	}
+	return nil
+}
+
+func jsonRequest(ctx context.Context, endpoint string, payload any) (*http.Request, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return req, nil
+}
+
+func markdownBody(msg Message) string {
+	body := msg.Summary
+	if msg.Markdown != "" {
+		body += "\n\n" + msg.Markdown
+	}
+	return body
+}
+
+// plainText 返回标题、摘要与代码块包裹的表格，超出 limit 时截断表格
+func plainText(msg Message, limit int) string {
+	head := "**" + msg.Title + "**\n" + msg.Summary
+	if msg.Markdown == "" {
+		return truncate(head, limit)
+	}
+	const fenceOpen, fenceClose = "\n```\n", "\n```"
+	room := limit - len(head) - len(fenceOpen) - len(fenceClose)
+	if room <= 0 {
+		return truncate(head, limit)
+	}
+	return head + fenceOpen + truncate(msg.Markdown, room) + fenceClose
+}
+
+func telegramText(msg Message) string {
+	head := "<b>" + html.EscapeString(msg.Title) + "</b>\n" + html.EscapeString(msg.Summary)
+	if msg.Markdown == "" {
+		return truncate(head, telegramTextLimit)
+	}
+	const preOpen, preClose = "\n<pre>", "</pre>"
+	room := telegramTextLimit - len(head) - len(preOpen) - len(preClose)
+	if room <= 0 {
+		return truncate(head, telegramTextLimit)
+	}
+	// 先截断再转义会让转义后的长度超限，这里按转义后的长度逐行保留
+	var table strings.Builder
+	for _, line := range strings.SplitAfter(msg.Markdown, "\n") {
+		escaped := html.EscapeString(line)
+		if table.Len()+len(escaped) > room {
+			break
+		}
+		table.WriteString(escaped)
+	}
+	return head + preOpen + strings.TrimRight(table.String(), "\n") + preClose
+}
+
+// truncate 按字节截断到 limit 以内并保持 UTF-8 完整，截断时以 … 结尾
+func truncate(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	const ellipsis = "…"
+	cut := limit - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if cut < 0 {
+		cut = 0
+	}
+	return text[:cut] + ellipsis
+}
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type capturedRequest struct {
+	path, contentType, body string
+}
+
+func captureServer(t *testing.T, reply string) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var got []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, capturedRequest{r.URL.Path, r.Header.Get("Content-Type"), string(body)})
+		io.WriteString(w, reply)
+	}))
+	t.Cleanup(server.Close)
+	return server, &got
+}
+
+func testMessage() Message {
+	return Message{
+		Event:    EventCompleted,
+		Title:    "GOECS test completed",
+		Summary:  "took 21 min · CPU 1234",
+		Host:     "vm",
+		Time:     time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC),
+		Markdown: "| Test | Score |\n| --- | --- |\n| <1 thread> | 1234 |",
+	}
+}
+
+func TestSendFormatsEachChannelKind(t *testing.T) {
+	server, got := captureServer(t, `{"ok":true,"code":0}`)
+	telegramAPIBase, serverChanAPIBase = server.URL, server.URL
+	t.Cleanup(func() { telegramAPIBase, serverChanAPIBase = "https://api.telegram.org", "https://sctapi.ftqq.com" })
+
+	channels := []Channel{
+		{Name: "hook", Kind: KindWebhook, URL: server.URL + "/hook"},
+		{Name: "discord", Kind: KindDiscord, URL: server.URL + "/discord"},
+		{Name: "tg", Kind: KindTelegram, Token: "123:abc", ChatID: "42"},
+		{Name: "sc", Kind: KindServerChan, Token: "SCT1"},
+	}
+	for _, channel := range channels {
+		if err := Send(context.Background(), server.Client(), channel, testMessage()); err != nil {
+			t.Fatalf("Send(%s) error = %v", channel.Name, err)
+		}
+	}
+	if len(*got) != 4 {
+		t.Fatalf("requests = %d, want 4", len(*got))
+	}
+
+	var hook Message
+	if err := json.Unmarshal([]byte((*got)[0].body), &hook); err != nil || hook.Event != EventCompleted || hook.Markdown == "" {
+		t.Fatalf("webhook payload = %s (%v)", (*got)[0].body, err)
+	}
+	var discord map[string]string
+	_ = json.Unmarshal([]byte((*got)[1].body), &discord)
+	if !strings.HasPrefix(discord["content"], "**GOECS test completed**") || !strings.Contains(discord["content"], "```\n| Test") {
+		t.Fatalf("discord content = %q", discord["content"])
+	}
+	var telegram map[string]any
+	_ = json.Unmarshal([]byte((*got)[2].body), &telegram)
+	if (*got)[2].path != "/bot123:abc/sendMessage" || telegram["chat_id"] != "42" || !strings.Contains(telegram["text"].(string), "&lt;1 thread&gt;") {
+		t.Fatalf("telegram request = %+v", (*got)[2])
+	}
+	if (*got)[3].path != "/SCT1.send" || !strings.Contains((*got)[3].body, "desp=took+21+min") {
+		t.Fatalf("server chan request = %+v", (*got)[3])
+	}
+}
+
+func TestSendReportsServiceErrors(t *testing.T) {
+	server, _ := captureServer(t, `{"code":40001,"message":"bad key"}`)
+	serverChanAPIBase = server.URL
+	t.Cleanup(func() { serverChanAPIBase = "https://sctapi.ftqq.com" })
+	err := Send(context.Background(), server.Client(), Channel{Name: "sc", Kind: KindServerChan, Token: "x"}, testMessage())
+	if err == nil || !strings.Contains(err.Error(), "bad key") {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "nope", http.StatusForbidden)
+	}))
+	defer failing.Close()
+	err = Send(context.Background(), failing.Client(), Channel{Name: "hook", Kind: KindWebhook, URL: failing.URL}, testMessage())
+	if err == nil || !strings.Contains(err.Error(), "403") {
+		t.Fatalf("Send() error = %v", err)
+	}
+}
+
+func TestDispatchHonoursEventsAndEnabled(t *testing.T) {
+	server, got := captureServer(t, "ok")
+	channels := []Channel{
+		{Name: "done", Kind: KindWebhook, URL: server.URL + "/done", Enabled: true, Events: []Event{EventCompleted}},
+		{Name: "fail", Kind: KindWebhook, URL: server.URL + "/fail", Enabled: true, Events: []Event{EventFailed, EventRegression}},
+		{Name: "off", Kind: KindWebhook, URL: server.URL + "/off", Events: []Event{EventCompleted}},
+	}
+	if err := Dispatch(context.Background(), server.Client(), channels, testMessage()); err != nil {
+		t.Fatal(err)
+	}
+	if len(*got) != 1 || (*got)[0].path != "/done" {
+		t.Fatalf("requests = %+v", *got)
+	}
+}
+
+func TestTruncateKeepsUTF8AndLimits(t *testing.T) {
+	long := strings.Repeat("测", 1000)
+	msg := Message{Title: "t", Summary: "s", Markdown: long}
+	if text := plainText(msg, discordContentLimit); len(text) > discordContentLimit || !strings.HasSuffix(text, "…\n```") {
+		t.Fatalf("discord text len = %d", len(text))
+	}
+	msg.Markdown = strings.Repeat("<a>\n", 2000)
+	if text := telegramText(msg); len(text) > telegramTextLimit || !strings.HasSuffix(text, "</pre>") {
+		t.Fatalf("telegram text len = %d", len(text))
+	}
+}
+
+func TestStoreRoundTripAndValidation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "notifiers.json")
+	if channels, err := Load(path); err != nil || len(channels) != 0 {
+		t.Fatalf("Load(missing) = %v, %v", channels, err)
+	}
+	if err := Save(path, []Channel{{Name: "tg", Kind: KindTelegram, Token: "t"}}); err == nil {
+		t.Fatal("Save() accepted telegram channel without chat ID")
+	}
+	want := []Channel{{Name: "hook", Kind: KindWebhook, URL: "https://example.com/x", Enabled: true, Events: []Event{EventFailed}}}
+	if err := Save(path, want); err != nil {
+		t.Fatal(err)
+	}
+	channels, err := Load(path)
+	if err != nil || len(channels) != 1 || !channels[0].Wants(EventFailed) || channels[0].Wants(EventCompleted) {
+		t.Fatalf("Load() = %+v, %v", channels, err)
+	}
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Load 读取渠道配置文件，文件不存在时返回空列表
+func Load(path string) ([]Channel, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var channels []Channel
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return channels, nil
+}
+
+// Save 校验并保存渠道配置。文件包含令牌，仅当前用户可读。
+func Save(path string, channels []Channel) error {
+	for _, channel := range channels {
+		if err := channel.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", channel.Name, err)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(channels, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,204 @@
+// Package paste 把测试结果上传到 goecs 使用的结果分享服务或通用的 pastebin 接口。
+// 与 goecs 内置上传的请求格式一致，但使用调用方传入的 HTTP 客户端，便于走界面配置的代理。
+package paste
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+	"github.com/oneclickvirt/security/network"
+)
+
+// MaxSize 是分享服务接受的最大结果大小
+const MaxSize = 25 << 10
+
+// ErrTooLarge 表示去掉颜色后的结果仍超过 MaxSize
+var ErrTooLarge = errors.New("result exceeds 25KB upload limit")
+
+// 测试中替换为本地服务器
+var (
+	uploadURLs = []string{
+		"http://hpaste.spiritlhl.net/api/UL/upload",
+		"https://paste.spiritlhl.net/api/UL/upload",
+	}
+	httpShowURL  = "http://hpaste.spiritlhl.net/#/show/"
+	httpsShowURL = "https://paste.spiritlhl.net/#/show/"
+)
+
+// Link 是上传后的查看地址
+type Link struct {
+	HTTP  string
+	HTTPS string
+}
+
+// Upload 去掉 ANSI 颜色后上传测试结果，主地址失败时改用备用地址。client 为 nil 时使用 http.DefaultClient。
+func Upload(ctx context.Context, client *http.Client, name, output string) (Link, error) {
+	content := results.StripANSI(output)
+	if len(content) > MaxSize {
+		return Link{}, ErrTooLarge
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var errs []error
+	for _, endpoint := range uploadURLs {
+		id, err := post(ctx, client, endpoint, name, content)
+		if err == nil {
+			return Link{HTTP: httpShowURL + id, HTTPS: httpsShowURL + id}, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return Link{}, errors.Join(errs...)
+}
+
+// Message 按 goecs 上传成功时的格式生成输出
+func (l Link) Message(language string) string {
+	if language == "en" {
+		return fmt.Sprintf("Upload successfully!\nHttp URL:  %s\nHttps URL: %s\nEach Test Benchmark: https://bash.spiritlhl.net/ecsguide\n", l.HTTP, l.HTTPS)
+	}
+	return fmt.Sprintf("上传成功!\nHttp URL:  %s\nHttps URL: %s\n每项测试基准见: https://bash.spiritlhl.net/ecsguide\n", l.HTTP, l.HTTPS)
+}
+
+func post(ctx context.Context, client *http.Client, endpoint, name, content string) (string, error) {
+	req, err := multipartRequest(ctx, endpoint, "file", name, content)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", network.SecurityUploadToken)
+	id, err := send(client, req)
+	if err != nil {
+		return "", err
+	}
+	// 服务可能返回完整的查看地址，只取最后一段作为编号
+	if strings.Contains(id, "show") {
+		id = id[strings.LastIndex(id, "/")+1:]
+	}
+	return id, nil
+}
+
+func multipartRequest(ctx context.Context, endpoint, field, name, content string) (*http.Request, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile(field, name)
+	if err != nil {
+		return nil, err
+	}
+	io.WriteString(part, content)
+	if err := form.Close(); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	return req, nil
+}
+
+// send 发送请求并返回去掉首尾空白的响应内容，非 2xx 或内容为空时返回错误
+func send(client *http.Client, req *http.Request) (string, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	text := strings.TrimSpace(string(data))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || text == "" {
+		return "", fmt.Errorf("upload to %s failed: %s", req.URL.Redacted(), resp.Status)
+	}
+	return text, nil
+}
+
+// 分享服务类型
+const (
+	// KindECS 是 goecs 的结果分享服务，限制 25KB
+	KindECS = "ecs"
+	// KindGeneric 是通用 pastebin 接口：POST 内容，响应为分享地址
+	KindGeneric = "generic"
+)
+
+// Service 是分享结果使用的服务
+type Service struct {
+	Kind string
+	// URL 为通用接口地址
+	URL string
+	// Field 非空时以 multipart 表单的该字段上传，否则以 text/plain 作为请求体
+	Field string
+}
+
+// Validate 检查通用接口的地址
+func (s Service) Validate() error {
+	if s.Kind != KindGeneric {
+		return nil
+	}
+	u, err := url.Parse(strings.TrimSpace(s.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid paste endpoint %q", s.URL)
+	}
+	return nil
+}
+
+// Share 上传 content 并返回分享地址。goecs 服务返回 HTTPS 查看地址；通用接口的响应可以是地址本身，
+// 也可以是含 url 或 link 字段的 JSON。
+func Share(ctx context.Context, client *http.Client, service Service, name, content string) (string, error) {
+	if service.Kind != KindGeneric {
+		link, err := Upload(ctx, client, name, content)
+		return link.HTTPS, err
+	}
+	if err := service.Validate(); err != nil {
+		return "", err
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	endpoint := strings.TrimSpace(service.URL)
+	var req *http.Request
+	var err error
+	if field := strings.TrimSpace(service.Field); field != "" {
+		req, err = multipartRequest(ctx, endpoint, field, name, content)
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(content))
+		if req != nil {
+			req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	text, err := send(client, req)
+	if err != nil {
+		return "", err
+	}
+	return shareURL(text)
+}
+
+// shareURL 从通用接口的响应中取出分享地址
+func shareURL(text string) (string, error) {
+	var fields map[string]any
+	if json.Unmarshal([]byte(text), &fields) == nil {
+		for _, key := range []string{"url", "link", "share_url"} {
+			if value, ok := fields[key].(string); ok && value != "" {
+				text = value
+				break
+			}
+		}
+	}
+	text = strings.TrimSpace(strings.SplitN(text, "\n", 2)[0])
+	if u, err := url.Parse(text); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("paste endpoint returned no link: %q", text)
+	}
+	return text, nil
+}
@@ -0,0 +1,94 @@
+package paste
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestUploadFallsBackToBackupEndpoint(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/primary" {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		file, header, err := r.FormFile("file")
+		if err != nil || header.Filename != "goecs.txt" {
+			http.Error(w, "bad form", http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+		got = string(data)
+		io.WriteString(w, "https://paste.spiritlhl.net/#/show/abc123\n")
+	}))
+	defer server.Close()
+	old := uploadURLs
+	uploadURLs = []string{server.URL + "/primary", server.URL + "/backup"}
+	t.Cleanup(func() { uploadURLs = old })
+
+	link, err := Upload(context.Background(), server.Client(), "goecs.txt", "\x1b[32mCPU\x1b[0m 1234\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "CPU 1234\n" {
+		t.Fatalf("uploaded %q, want colours stripped", got)
+	}
+	if link.HTTPS != "https://paste.spiritlhl.net/#/show/abc123" || !strings.Contains(link.Message("en"), "Http URL:  http://hpaste.spiritlhl.net/#/show/abc123") {
+		t.Fatalf("link = %+v", link)
+	}
+}
+
+func TestUploadRejectsOversizedResult(t *testing.T) {
+	if _, err := Upload(context.Background(), nil, "goecs.txt", strings.Repeat("x", MaxSize+1)); err != ErrTooLarge {
+		t.Fatalf("Upload() error = %v, want ErrTooLarge", err)
+	}
+}
+
+func TestShareWithGenericEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/raw":
+			data, _ := io.ReadAll(r.Body)
+			if r.Header.Get("Content-Type") != "text/plain; charset=utf-8" || string(data) != "result" {
+				http.Error(w, "bad body", http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, "https://paste.example/abc\n")
+		case "/form":
+			file, _, err := r.FormFile("content")
+			if err != nil {
+				http.Error(w, "bad form", http.StatusBadRequest)
+				return
+			}
+			file.Close()
+			io.WriteString(w, `{"key":"abc","url":"https://paste.example/form"}`)
+		default:
+			io.WriteString(w, "stored")
+		}
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		service Service
+		want    string
+	}{
+		{Service{Kind: KindGeneric, URL: server.URL + "/raw"}, "https://paste.example/abc"},
+		{Service{Kind: KindGeneric, URL: server.URL + "/form", Field: "content"}, "https://paste.example/form"},
+	}
+	for _, c := range cases {
+		got, err := Share(context.Background(), server.Client(), c.service, "goecs.md", "result")
+		if err != nil || got != c.want {
+			t.Errorf("Share(%+v) = %q, %v; want %q", c.service, got, err, c.want)
+		}
+	}
+	if _, err := Share(context.Background(), server.Client(), Service{Kind: KindGeneric, URL: server.URL + "/other"}, "goecs.md", "result"); err == nil {
+		t.Error("Share() should fail when the response has no link")
+	}
+	if err := (Service{Kind: KindGeneric, URL: "paste.example"}).Validate(); err == nil {
+		t.Error("Validate() should reject an endpoint without scheme")
+	}
+}
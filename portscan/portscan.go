@@ -0,0 +1,104 @@
+// Package portscan 解析端口列表，从本机检测目标的 TCP 端口是否可达，用于发现服务商过滤的端口。
+package portscan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// DefaultPorts 是默认检测的常用端口：SSH、HTTP、HTTPS 与远程桌面
+var DefaultPorts = []int{22, 80, 443, 3389}
+
+// MaxPorts 是一次最多检测的端口数
+const MaxPorts = 1024
+
+// services 是常见端口对应的服务名
+var services = map[int]string{
+	21: "ftp", 22: "ssh", 23: "telnet", 25: "smtp", 53: "dns", 80: "http", 110: "pop3", 143: "imap",
+	443: "https", 445: "smb", 465: "smtps", 587: "submission", 993: "imaps", 995: "pop3s",
+	1080: "socks", 1433: "mssql", 3306: "mysql", 3389: "rdp", 5432: "postgresql", 5900: "vnc",
+	6379: "redis", 8080: "http-alt", 8443: "https-alt", 27017: "mongodb",
+}
+
+// Service 返回端口常见的服务名，未知时为空
+func Service(port int) string {
+	return services[port]
+}
+
+// ParsePorts 解析端口列表，端口之间可用逗号、空格或换行分隔，支持 8000-8010 这样的范围；
+// 结果去重并按端口号排序，为空时返回 DefaultPorts
+func ParsePorts(text string) ([]int, error) {
+	seen := map[int]bool{}
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == ';' || r == ' ' || r == '\t' || r == '\n' || r == '\r' }) {
+		low, high, isRange := strings.Cut(field, "-")
+		first, err := parsePort(low)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parsePort(high); err != nil {
+				return nil, err
+			}
+			if last < first {
+				return nil, fmt.Errorf("invalid port range %q", field)
+			}
+		}
+		if last-first+1 > MaxPorts {
+			return nil, fmt.Errorf("too many ports (at most %d)", MaxPorts)
+		}
+		for port := first; port <= last; port++ {
+			seen[port] = true
+		}
+		if len(seen) > MaxPorts {
+			return nil, fmt.Errorf("too many ports (at most %d)", MaxPorts)
+		}
+	}
+	if len(seen) == 0 {
+		return slices.Clone(DefaultPorts), nil
+	}
+	ports := make([]int, 0, len(seen))
+	for port := range seen {
+		ports = append(ports, port)
+	}
+	slices.Sort(ports)
+	return ports, nil
+}
+
+func parsePort(text string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(text))
+	if err != nil || port <= 0 || port > 65535 {
+		return 0, fmt.Errorf("invalid port %q", text)
+	}
+	return port, nil
+}
+
+// Check 以 TCP 连接检测 host 的一个端口：连接成功为 open，对方拒绝为 closed，超时或其他错误为 filtered
+func Check(ctx context.Context, dialer *net.Dialer, host string, port int) results.PortResult {
+	result := results.PortResult{Port: port, Service: Service(port)}
+	started := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err == nil {
+		conn.Close()
+		result.Status = results.PortOpen
+		result.LatencyMs = float64(time.Since(started).Microseconds()) / 1000
+		return result
+	}
+	result.Error = err.Error()
+	// Windows 上拒绝连接的错误码为 WSAECONNREFUSED，不等同于 syscall.ECONNREFUSED，按错误信息识别
+	if errors.Is(err, syscall.ECONNREFUSED) || strings.Contains(err.Error(), "refused") {
+		result.Status = results.PortClosed
+	} else {
+		result.Status = results.PortFiltered
+	}
+	return result
+}
@@ -0,0 +1,58 @@
+package portscan
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestParsePorts(t *testing.T) {
+	ports, err := ParsePorts("443, 22\n8000-8002;22")
+	if err != nil || !slices.Equal(ports, []int{22, 443, 8000, 8001, 8002}) {
+		t.Fatalf("ParsePorts() = %v, %v", ports, err)
+	}
+	ports, err = ParsePorts("  ")
+	if err != nil || !slices.Equal(ports, DefaultPorts) {
+		t.Fatalf("ParsePorts(empty) = %v, %v", ports, err)
+	}
+	ports[0] = 1
+	if DefaultPorts[0] != 22 {
+		t.Fatal("ParsePorts returned DefaultPorts without copying")
+	}
+	for _, text := range []string{"0", "65536", "ssh", "90-80", "1-2000"} {
+		if _, err := ParsePorts(text); err == nil {
+			t.Errorf("ParsePorts(%q) should fail", text)
+		}
+	}
+}
+
+func TestCheckOpenAndClosed(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	dialer := &net.Dialer{Timeout: 2 * time.Second}
+
+	result := Check(context.Background(), dialer, "127.0.0.1", port)
+	if result.Status != results.PortOpen || result.Port != port || result.Error != "" {
+		t.Fatalf("open port = %+v", result)
+	}
+
+	listener.Close()
+	result = Check(context.Background(), dialer, "127.0.0.1", port)
+	if result.Status != results.PortClosed || !strings.Contains(result.Error, "refused") {
+		t.Fatalf("closed port = %+v", result)
+	}
+}
+
+func TestService(t *testing.T) {
+	if Service(3389) != "rdp" || Service(22) != "ssh" || Service(12345) != "" {
+		t.Fatal("unexpected service names")
+	}
+}
@@ -0,0 +1,142 @@
+// Package proxy 保存界面发起的出站 HTTP 请求所用的代理（HTTP、HTTPS 或 SOCKS5），
+// 支持按组件（发布包下载、结果上传、消息推送）覆盖全局设置。测试流量本身不经过代理。
+package proxy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Component 是发起出站请求的界面组件
+type Component string
+
+const (
+	// Downloads 为 goecs 发布包与界面更新的下载
+	Downloads Component = "downloads"
+	// Uploads 为测试结果上传
+	Uploads Component = "uploads"
+	// Notifications 为消息推送渠道
+	Notifications Component = "notifications"
+)
+
+// Components 是可单独设置代理的组件，按界面展示顺序排列
+var Components = []Component{Downloads, Uploads, Notifications}
+
+// Direct 用于组件覆盖，表示该组件不使用代理
+const Direct = "direct"
+
+// Settings 是代理设置。全局代理为空时沿用系统环境变量（HTTP_PROXY 等）。
+type Settings struct {
+	URL string `json:"url,omitempty"`
+	// Overrides 按组件覆盖全局代理，值为代理地址或 Direct
+	Overrides map[Component]string `json:"overrides,omitempty"`
+}
+
+// Parse 校验代理地址，支持 http、https、socks5 与 socks5h
+func Parse(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q (use http, https or socks5)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("proxy address has no host")
+	}
+	return u, nil
+}
+
+// Validate 检查全局代理与各组件覆盖
+func (s Settings) Validate() error {
+	if s.URL != "" {
+		if _, err := Parse(s.URL); err != nil {
+			return err
+		}
+	}
+	for component, value := range s.Overrides {
+		if value = strings.TrimSpace(value); value == "" || value == Direct {
+			continue
+		}
+		if _, err := Parse(value); err != nil {
+			return fmt.Errorf("%s: %w", component, err)
+		}
+	}
+	return nil
+}
+
+// For 返回组件实际使用的代理地址；空字符串表示不指定（沿用环境变量），Direct 表示直连
+func (s Settings) For(component Component) string {
+	if value := strings.TrimSpace(s.Overrides[component]); value != "" {
+		return value
+	}
+	return strings.TrimSpace(s.URL)
+}
+
+// Configured 报告组件是否显式设置了代理或直连
+func (s Settings) Configured(component Component) bool {
+	return s.For(component) != ""
+}
+
+// Client 返回组件使用的 HTTP 客户端，timeout 为 0 时不限时
+func (s Settings) Client(component Component, timeout time.Duration) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	switch value := s.For(component); value {
+	case "":
+		transport.Proxy = http.ProxyFromEnvironment
+	case Direct:
+		transport.Proxy = nil
+	default:
+		u, err := Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}, nil
+}
+
+// Load 读取代理设置，文件不存在时返回空设置
+func Load(path string) (Settings, error) {
+	var settings Settings
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return settings, nil
+	}
+	if err != nil {
+		return settings, err
+	}
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return Settings{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return settings, nil
+}
+
+// Save 校验并保存代理设置。代理地址可能包含密码，仅当前用户可读。
+func Save(path string, settings Settings) error {
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+	for component, value := range settings.Overrides {
+		if strings.TrimSpace(value) == "" {
+			delete(settings.Overrides, component)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(settings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
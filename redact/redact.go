@@ -0,0 +1,134 @@
+// Package redact 在分享测试结果前隐去能识别服务器的信息。
+package redact
+
+import (
+	"net/netip"
+	"regexp"
+	"strings"
+)
+
+var (
+	ipv4Pattern = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+	// ipv6Pattern 粗略匹配含至少两个冒号的十六进制串，再交给 netip 校验
+	ipv6Pattern = regexp.MustCompile(`(?i)\b[0-9a-f]{0,4}(?::[0-9a-f]{0,4}){2,7}\b`)
+)
+
+// IPs 隐去公网 IP：IPv4 保留前三段（1.2.3.*），IPv6 保留前两组（2001:db8:*）。
+// 内网、回环等地址不能识别服务器，原样保留。
+func IPs(text string) string {
+	text = ipv4Pattern.ReplaceAllStringFunc(text, func(s string) string {
+		addr, err := netip.ParseAddr(s)
+		if err != nil || !public(addr) {
+			return s
+		}
+		return s[:strings.LastIndexByte(s, '.')] + ".*"
+	})
+	return ipv6Pattern.ReplaceAllStringFunc(text, func(s string) string {
+		addr, err := netip.ParseAddr(s)
+		if err != nil || !addr.Is6() || !public(addr) {
+			return s
+		}
+		groups := strings.SplitN(addr.StringExpanded(), ":", 3)
+		return strings.TrimLeft(groups[0], "0") + ":" + trimGroup(groups[1]) + ":*"
+	})
+}
+
+func trimGroup(group string) string {
+	if trimmed := strings.TrimLeft(group, "0"); trimmed != "" {
+		return trimmed
+	}
+	return "0"
+}
+
+func public(addr netip.Addr) bool {
+	return addr.IsGlobalUnicast() && !addr.IsPrivate()
+}
+
+// Mask 替换被隐去的主机名与组织名
+const Mask = "***"
+
+var (
+	// keyValuePattern 匹配 "键 : 值" 形式的行，键中不含冒号
+	keyValuePattern = regexp.MustCompile(`^(\s*)([^:：]*[^\s:：])(\s*[:：]\s*)(\S.*?)(\s*)$`)
+	asnPattern      = regexp.MustCompile(`^(AS\d+)\b`)
+	hostnameKeys    = []string{"主机名", "hostname", "host name", "host", "rdns", "ptr"}
+	orgKeys         = []string{"组织", "organization", "org", "isp"}
+)
+
+// Redactor 隐去公网 IP、主机名以及 ASN 的组织名，可在多段文本间复用
+type Redactor struct {
+	hosts []*regexp.Regexp
+}
+
+// New 创建 Redactor。hosts 为需要一并隐去的主机名，如本机名与远程目标地址；IP、过短的名称与 localhost 会被忽略。
+func New(hosts ...string) *Redactor {
+	r := &Redactor{}
+	for _, host := range hosts {
+		host = strings.TrimSpace(host)
+		if _, err := netip.ParseAddr(host); err == nil || len(host) < 3 || strings.EqualFold(host, "localhost") {
+			continue
+		}
+		r.hosts = append(r.hosts, regexp.MustCompile(`(?i)(^|[^\w.-])`+regexp.QuoteMeta(host)+`($|[^\w.-])`))
+	}
+	return r
+}
+
+// Text 是 New(hosts...).Text(text) 的简写
+func Text(text string, hosts ...string) string {
+	return New(hosts...).Text(text)
+}
+
+// Text 按行隐去 "键 : 值" 中的主机名与组织名，再隐去指定的主机名与公网 IP
+func (r *Redactor) Text(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		body := strings.TrimSuffix(line, "\r")
+		m := keyValuePattern.FindStringSubmatch(body)
+		if m == nil {
+			continue
+		}
+		if value := Value(m[2], m[4]); value != m[4] {
+			lines[i] = m[1] + m[2] + m[3] + value + m[5] + line[len(body):]
+		}
+	}
+	text = strings.Join(lines, "\n")
+	for _, host := range r.hosts {
+		text = host.ReplaceAllString(text, "${1}"+Mask+"${2}")
+	}
+	return IPs(text)
+}
+
+// Value 按字段名隐去单个值：主机名整体隐去，ASN 保留 AS 号、隐去组织名（AS906 ***），组织与运营商整体隐去
+func Value(key, value string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	switch {
+	case value == "":
+		return value
+	case hasKeySuffix(key, hostnameKeys), hasKeySuffix(key, orgKeys):
+		return Mask
+	case strings.HasSuffix(key, "asn"):
+		if m := asnPattern.FindString(value); m != "" && m != value {
+			return m + " " + Mask
+		}
+	}
+	return value
+}
+
+// hasKeySuffix 判断键名是否以 suffixes 之一结尾，且前面是空白或键名开头，避免 "Post" 之类误判为 "host"
+func hasKeySuffix(key string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if rest, ok := strings.CutSuffix(key, suffix); ok && (rest == "" || strings.HasSuffix(rest, " ") || !isASCII(suffix)) {
+			return true
+		}
+	}
+	return false
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,39 @@
+package redact
+
+import "testing"
+
+func TestIPsMasksPublicAddresses(t *testing.T) {
+	cases := map[string]string{
+		" IPV4 ASN : AS13335 1.1.1.1 ":            " IPV4 ASN : AS13335 1.1.1.* ",
+		"IPV6: 2606:4700:4700::1111":              "IPV6: 2606:4700:*",
+		"内网 192.168.1.10 回环 127.0.0.1":            "内网 192.168.1.10 回环 127.0.0.1",
+		"版本 0.1.171 时间 12:30:45 延迟 10.5 ms":       "版本 0.1.171 时间 12:30:45 延迟 10.5 ms",
+		"fe80::1 与 fd00::1 不变":                    "fe80::1 与 fd00::1 不变",
+		"2001:0db8:0000:0000:0000:0000:0000:0001": "2001:db8:*",
+	}
+	for in, want := range cases {
+		if got := IPs(in); got != want {
+			t.Errorf("IPs(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestTextMasksHostnamesAndOrganizations(t *testing.T) {
+	in := " IPV4 ASN            : AS906 DMIT Cloud Services\n" +
+		"ASN滥用得分(越低越好):   0.0041 (Low) [A]\n" +
+		"主机名: vps-1\r\n" +
+		" Organization : DMIT, Inc.\n" +
+		"Post: 15\n" +
+		"ssh root@vps-1.example.com -p 22 from 203.0.113.7\n" +
+		"goecs on my-vps-10 finished\n"
+	want := " IPV4 ASN            : AS906 ***\n" +
+		"ASN滥用得分(越低越好):   0.0041 (Low) [A]\n" +
+		"主机名: ***\r\n" +
+		" Organization : ***\n" +
+		"Post: 15\n" +
+		"ssh root@*** -p 22 from 203.0.113.*\n" +
+		"goecs on my-vps-10 finished\n"
+	if got := Text(in, "vps-1.example.com", "203.0.113.7", ""); got != want {
+		t.Fatalf("Text() =\n%q\nwant\n%q", got, want)
+	}
+}
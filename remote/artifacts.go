@@ -0,0 +1,75 @@
+package remote
+
+import (
+	"path"
+	"slices"
+	"sort"
+)
+
+const (
+	// MaxArtifactSize 是单个取回文件的大小上限，更大的文件跳过
+	MaxArtifactSize = 8 << 20
+	// MaxArtifacts 是一次运行最多取回的文件数
+	MaxArtifacts = 20
+)
+
+// Artifact 是运行后从远程目标取回的文件
+type Artifact struct {
+	Name string
+	Data []byte
+}
+
+// SnapshotDir 记录目录中普通文件的大小与修改时间，作为运行前的基准；目录不存在时返回空集合
+func SnapshotDir(s *SFTP, dir string) map[string]FileInfo {
+	snapshot := map[string]FileInfo{}
+	items, err := s.ReadDir(dir)
+	if err != nil {
+		return snapshot
+	}
+	for _, item := range items {
+		if item.IsRegular() {
+			snapshot[item.Name] = item
+		}
+	}
+	return snapshot
+}
+
+// FetchArtifacts 取回 dir 中相对 before 新建或改动过的普通文件（按名称排序，跳过 skip 中的名称）。
+// 超过 MaxArtifactSize 或 MaxArtifacts 的文件记入 skipped，不中断其余文件
+func FetchArtifacts(s *SFTP, dir string, before map[string]FileInfo, skip []string) (artifacts []Artifact, skipped []string, err error) {
+	items, err := s.ReadDir(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].Name < items[j].Name })
+	for _, item := range items {
+		if !item.IsRegular() || slices.Contains(skip, item.Name) {
+			continue
+		}
+		if old, ok := before[item.Name]; ok && old.Size == item.Size && old.ModTime.Equal(item.ModTime) {
+			continue
+		}
+		if item.Size > MaxArtifactSize || len(artifacts) >= MaxArtifacts {
+			skipped = append(skipped, item.Name)
+			continue
+		}
+		data, err := s.ReadFile(path.Join(dir, item.Name), MaxArtifactSize)
+		if err != nil {
+			skipped = append(skipped, item.Name)
+			continue
+		}
+		artifacts = append(artifacts, Artifact{Name: item.Name, Data: data})
+	}
+	return artifacts, skipped, nil
+}
+
+// RemoveArtifacts 从 dir 中删除已取回的文件，返回第一个错误
+func RemoveArtifacts(s *SFTP, dir string, artifacts []Artifact) error {
+	var first error
+	for _, artifact := range artifacts {
+		if err := s.Remove(path.Join(dir, artifact.Name)); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
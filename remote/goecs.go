@@ -0,0 +1,157 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// DefaultWorkDir 是远程主机上存放 goecs 的目录（相对登录用户的家目录）
+	DefaultWorkDir = ".goecs-gui"
+	releaseBaseURL = "https://github.com/oneclickvirt/ecs/releases/download"
+)
+
+// ReleaseAsset 根据 `uname -sm` 的输出选择 goecs 发布包名称
+func ReleaseAsset(uname string) (string, error) {
+	fields := strings.Fields(strings.ToLower(uname))
+	if len(fields) < 2 {
+		return "", fmt.Errorf("unexpected uname output %q", strings.TrimSpace(uname))
+	}
+	osName, machine := fields[0], fields[1]
+	arch := ""
+	switch machine {
+	case "x86_64", "amd64", "x64":
+		arch = "amd64"
+	case "i386", "i686":
+		arch = "386"
+	case "aarch64", "arm64", "armv8", "armv8l":
+		arch = "arm64"
+	case "arm", "armv7l", "armv6l":
+		arch = "arm"
+	case "mips", "mipsle", "s390x", "riscv64":
+		arch = machine
+	}
+	switch osName {
+	case "linux":
+	case "freebsd":
+		if arch != "amd64" && arch != "386" && arch != "arm64" {
+			arch = ""
+		}
+	case "darwin":
+		if arch != "amd64" && arch != "arm64" {
+			arch = ""
+		}
+	default:
+		return "", fmt.Errorf("unsupported remote system %q", fields[0])
+	}
+	if arch == "" {
+		return "", fmt.Errorf("unsupported remote architecture %q on %s", machine, osName)
+	}
+	return fmt.Sprintf("goecs_%s_%s.zip", osName, arch), nil
+}
+
+// InstallCommand 生成在远程主机上下载并解压指定版本 goecs 的 shell 命令
+func InstallCommand(version, asset, workDir string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	url := fmt.Sprintf("%s/v%s/%s", releaseBaseURL, version, asset)
+	return strings.Join([]string{
+		"set -e",
+		fmt.Sprintf("mkdir -p %s && cd %s", Quote(workDir), Quote(workDir)),
+		fmt.Sprintf(`if command -v curl >/dev/null 2>&1; then curl -fsSL -o goecs.zip %s; else wget -qO goecs.zip %s; fi`, Quote(url), Quote(url)),
+		`if command -v unzip >/dev/null 2>&1; then unzip -o -q goecs.zip goecs; elif command -v busybox >/dev/null 2>&1; then busybox unzip -o goecs.zip goecs; else python3 -m zipfile -e goecs.zip .; fi`,
+		"rm -f goecs.zip && chmod +x goecs",
+	}, "\n")
+}
+
+// Fetcher 在本机下载并校验指定版本的发布包，返回解压出的 goecs 路径
+type Fetcher func(ctx context.Context, version, asset string) (string, error)
+
+// Prepare 确保远程主机上存在 goecs（Windows 主机见 prepareWindows）：localBinary 非空时上传本地文件；否则按远程架构选择发布包，
+// fetch 非空时在本机下载校验后上传，失败或未提供时由远程主机直接下载。返回远程可执行文件的绝对路径。
+func Prepare(ctx context.Context, client *Client, version, localBinary string, fetch Fetcher, output func(string)) (string, error) {
+	emit := func(text string) {
+		if output != nil {
+			output(text)
+		}
+	}
+	if client.target.IsWindows() {
+		return prepareWindows(ctx, client, version, localBinary, fetch, emit)
+	}
+
+	home, err := client.Output(ctx, `printf %s "$HOME"`)
+	if err != nil {
+		return "", fmt.Errorf("resolve remote home: %w", err)
+	}
+	workDir := strings.TrimRight(strings.TrimSpace(home), "/") + "/" + DefaultWorkDir
+	binary := workDir + "/goecs"
+	if _, err := client.Output(ctx, "mkdir -p "+Quote(workDir)); err != nil {
+		return "", fmt.Errorf("create remote work dir: %w", err)
+	}
+
+	if localBinary = strings.TrimSpace(localBinary); localBinary != "" {
+		return binary, upload(ctx, client, localBinary, binary, emit)
+	}
+
+	uname, err := client.Output(ctx, "uname -sm")
+	if err != nil {
+		return "", fmt.Errorf("detect remote system: %w", err)
+	}
+	asset, err := ReleaseAsset(uname)
+	if err != nil {
+		return "", err
+	}
+	if fetch != nil {
+		emit(fmt.Sprintf("fetch %s %s\n", asset, version))
+		local, err := fetch(ctx, version, asset)
+		if err == nil {
+			return binary, upload(ctx, client, local, binary, emit)
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		emit(fmt.Sprintf("local download failed: %v\n", err))
+	}
+	emit(fmt.Sprintf("download %s %s -> %s\n", asset, version, binary))
+	if err := client.Run(ctx, InstallCommand(version, asset, workDir), false, output); err != nil {
+		return "", fmt.Errorf("install goecs on remote host: %w", err)
+	}
+	return binary, nil
+}
+
+func upload(ctx context.Context, client *Client, local, binary string, emit func(string)) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	emit(fmt.Sprintf("upload %s -> %s\n", local, binary))
+	return client.Upload(ctx, f, binary, 0o755)
+}
+
+// Command 组合在远程工作目录中执行 goecs 的命令行
+func Command(binary string, args []string) string {
+	return CommandWithEnv(binary, nil, args)
+}
+
+// CommandWithEnv 与 Command 相同，另经 env 为 goecs 设置环境变量，env 中每项为 KEY=VALUE
+func CommandWithEnv(binary string, env, args []string) string {
+	dir := binary
+	if i := strings.LastIndex(binary, "/"); i > 0 {
+		dir = binary[:i]
+	}
+	parts := make([]string, 0, len(env)+len(args)+4)
+	parts = append(parts, "cd", Quote(dir), "&&")
+	if len(env) > 0 {
+		parts = append(parts, "env")
+		for _, kv := range env {
+			parts = append(parts, Quote(kv))
+		}
+	}
+	parts = append(parts, Quote(binary))
+	for _, arg := range args {
+		parts = append(parts, Quote(arg))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,137 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReleaseAsset(t *testing.T) {
+	cases := map[string]string{
+		"Linux x86_64\n":  "goecs_linux_amd64.zip",
+		"Linux aarch64":   "goecs_linux_arm64.zip",
+		"Linux armv7l":    "goecs_linux_arm.zip",
+		"Linux riscv64":   "goecs_linux_riscv64.zip",
+		"FreeBSD amd64":   "goecs_freebsd_amd64.zip",
+		"Darwin arm64":    "goecs_darwin_arm64.zip",
+		"Linux sparc64":   "",
+		"SunOS i86pc":     "",
+		"garbage":         "",
+		"FreeBSD riscv64": "",
+	}
+	for uname, want := range cases {
+		got, err := ReleaseAsset(uname)
+		if want == "" {
+			if err == nil {
+				t.Fatalf("ReleaseAsset(%q) = %q, want error", uname, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Fatalf("ReleaseAsset(%q) = %q, %v; want %q", uname, got, err, want)
+		}
+	}
+}
+
+func TestQuoteAndCommand(t *testing.T) {
+	cases := map[string]string{
+		"":            "''",
+		"-menu=false": "-menu=false",
+		"/root/a b":   "'/root/a b'",
+		"it's":        `'it'"'"'s'`,
+		"$HOME":       "'$HOME'",
+	}
+	for in, want := range cases {
+		if got := Quote(in); got != want {
+			t.Fatalf("Quote(%q) = %q, want %q", in, got, want)
+		}
+	}
+	got := Command("/root/.goecs-gui/goecs", []string{"-menu=false", "-diskp", "/data dir"})
+	want := "cd /root/.goecs-gui && /root/.goecs-gui/goecs -menu=false -diskp '/data dir'"
+	if got != want {
+		t.Fatalf("Command() = %q, want %q", got, want)
+	}
+	got = CommandWithEnv("/root/.goecs-gui/goecs", []string{"GOMAXPROCS=2", "NOTE=a b"}, []string{"-menu=false"})
+	want = "cd /root/.goecs-gui && env GOMAXPROCS=2 'NOTE=a b' /root/.goecs-gui/goecs -menu=false"
+	if got != want {
+		t.Fatalf("CommandWithEnv() = %q, want %q", got, want)
+	}
+}
+
+func TestPrepareUploadsFetchedBinaryOrFallsBack(t *testing.T) {
+	var (
+		server   *testServer
+		commands []string
+	)
+	server = startTestServer(t, "secret", func(command string, stdin io.Reader, out io.Writer) uint32 {
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		commands = append(commands, command)
+		switch {
+		case strings.HasPrefix(command, "printf"):
+			io.WriteString(out, "/root")
+		case command == "uname -sm":
+			io.WriteString(out, "Linux aarch64\n")
+		case strings.HasPrefix(command, "cat > "):
+			data, _ := io.ReadAll(stdin)
+			server.uploads[command] = string(data)
+		}
+		return 0
+	})
+	client, err := Dial(context.Background(), server.target(t, "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	local := filepath.Join(t.TempDir(), "goecs")
+	writeFile(t, local, "verified")
+	var fetched string
+	fetch := func(_ context.Context, version, asset string) (string, error) {
+		fetched = version + " " + asset
+		return local, nil
+	}
+	binary, err := Prepare(context.Background(), client, "0.1.171", "", fetch, nil)
+	if err != nil || binary != "/root/.goecs-gui/goecs" || fetched != "0.1.171 goecs_linux_arm64.zip" {
+		t.Fatalf("Prepare() = %q, %v; fetched %q", binary, err, fetched)
+	}
+	server.mu.Lock()
+	uploaded := server.uploads["cat > /root/.goecs-gui/goecs && chmod 755 /root/.goecs-gui/goecs"]
+	server.mu.Unlock()
+	if uploaded != "verified" {
+		t.Fatalf("uploads = %#v", server.uploads)
+	}
+
+	// 本机下载失败时改为远程下载
+	var output strings.Builder
+	fetch = func(context.Context, string, string) (string, error) { return "", errors.New("offline") }
+	if _, err := Prepare(context.Background(), client, "0.1.171", "", fetch, func(s string) { output.WriteString(s) }); err != nil {
+		t.Fatal(err)
+	}
+	server.mu.Lock()
+	last := commands[len(commands)-1]
+	server.mu.Unlock()
+	if !strings.Contains(output.String(), "local download failed: offline") || !strings.Contains(last, "releases/download/v0.1.171/goecs_linux_arm64.zip") {
+		t.Fatalf("output %q, last command %q", output.String(), last)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
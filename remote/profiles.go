@@ -0,0 +1,324 @@
+package remote
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	// AuthPassword 表示使用密码认证
+	AuthPassword = "password"
+	// AuthKey 表示使用私钥认证
+	AuthKey = "key"
+
+	profilesFormatVersion = 1
+	kdfArgon2id           = "argon2id"
+	// 跳板机链的最大深度，防止配置成环
+	maxJumpDepth = 4
+)
+
+var (
+	// ErrWrongMasterPassword 表示主密码错误或文件被篡改
+	ErrWrongMasterPassword = errors.New("wrong master password")
+	// ErrProfileNotFound 表示指定名称的主机配置不存在
+	ErrProfileNotFound = errors.New("host profile not found")
+	// ErrProfileExists 表示新增或重命名的配置与已有配置重名
+	ErrProfileExists = errors.New("host profile already exists")
+	// ErrJumpLoop 表示跳板机引用形成了环或层级过深
+	ErrJumpLoop = errors.New("jump host chain is too deep or loops")
+)
+
+// Profile 是一条保存的主机连接配置
+type Profile struct {
+	Name          string `json:"name"`
+	Host          string `json:"host"`
+	Port          int    `json:"port,omitempty"`
+	User          string `json:"user"`
+	Auth          string `json:"auth"`
+	Password      string `json:"password,omitempty"`
+	KeyPath       string `json:"key_path,omitempty"`
+	KeyPassphrase string `json:"key_passphrase,omitempty"`
+	// Jump 为另一条配置的名称，经由该主机跳转连接
+	Jump string `json:"jump,omitempty"`
+	// Proxy 为连接该主机所用的 SOCKS5 代理，与 Jump 互斥
+	Proxy string `json:"proxy,omitempty"`
+	// OS 为 OSWindows 时该主机是 Windows 服务器，为空时按类 Unix 系统处理
+	OS string `json:"os,omitempty"`
+	// SudoPassword 只在用户选择保存时写入，与其他凭据一样加密保存
+	SudoPassword string `json:"sudo_password,omitempty"`
+	// 服务商、地区、价格与购买日期，与 Labels 中的自由标签一起作为主机的标签，见 Tags
+	Provider  string   `json:"provider,omitempty"`
+	Region    string   `json:"region,omitempty"`
+	Price     string   `json:"price,omitempty"`
+	Purchased string   `json:"purchased,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Notes     string   `json:"notes,omitempty"`
+	// MonthlyCost 为每月费用，用于在运行对比中计算性价比，0 表示未填写
+	MonthlyCost float64 `json:"monthly_cost,omitempty"`
+}
+
+// Tags 返回主机的全部标签：服务商、地区、价格、购买日期与自由标签，去掉空白与重复项
+func (p Profile) Tags() []string {
+	var tags []string
+	for _, tag := range append([]string{p.Provider, p.Region, p.Price, p.Purchased}, p.Labels...) {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// Target 把配置转换为连接目标（不解析跳板机）
+func (p Profile) Target() Target {
+	target := Target{Host: p.Host, Port: p.Port, User: p.User, KeyPath: p.KeyPath, KeyPassphrase: p.KeyPassphrase, SudoPassword: p.SudoPassword, Proxy: p.Proxy, OS: p.OS}
+	if p.Auth == AuthPassword || p.Auth == "" {
+		target.Password = p.Password
+	}
+	if p.Auth == AuthPassword {
+		target.KeyPath, target.KeyPassphrase = "", ""
+	}
+	return target
+}
+
+// sealedProfiles 是磁盘上的加密文件格式
+type sealedProfiles struct {
+	Version int    `json:"version"`
+	KDF     string `json:"kdf"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// ProfileStore 是用主密码加密保存的主机配置集合，可被多个 goroutine 并发使用
+type ProfileStore struct {
+	path     string
+	salt     []byte
+	key      []byte
+	profiles map[string]Profile
+	mu       sync.Mutex
+}
+
+// OpenProfiles 用主密码打开（不存在时创建）加密的主机配置文件
+func OpenProfiles(path, masterPassword string) (*ProfileStore, error) {
+	if masterPassword == "" {
+		return nil, errors.New("master password is empty")
+	}
+	store := &ProfileStore{path: path, profiles: map[string]Profile{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		store.salt = make([]byte, 16)
+		if _, err := rand.Read(store.salt); err != nil {
+			return nil, err
+		}
+		store.key = deriveProfileKey(masterPassword, store.salt)
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var sealed sealedProfiles
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, fmt.Errorf("decode host profiles: %w", err)
+	}
+	if sealed.Version != profilesFormatVersion || sealed.KDF != kdfArgon2id {
+		return nil, fmt.Errorf("unsupported host profile format %d/%s", sealed.Version, sealed.KDF)
+	}
+	store.salt = sealed.Salt
+	store.key = deriveProfileKey(masterPassword, sealed.Salt)
+	gcm, err := newProfileCipher(store.key)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := gcm.Open(nil, sealed.Nonce, sealed.Data, nil)
+	if err != nil {
+		return nil, ErrWrongMasterPassword
+	}
+	var list []Profile
+	if err := json.Unmarshal(plain, &list); err != nil {
+		return nil, fmt.Errorf("decode host profiles: %w", err)
+	}
+	for _, p := range list {
+		store.profiles[p.Name] = p
+	}
+	return store, nil
+}
+
+// ProfilesExist 判断加密配置文件是否已存在（用于区分“设置主密码”与“解锁”）
+func ProfilesExist(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func deriveProfileKey(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, 1, 64*1024, 4, 32)
+}
+
+func newProfileCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// List 返回按名称排序的全部配置
+func (s *ProfileStore) List() []Profile {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := make([]Profile, 0, len(s.profiles))
+	for _, p := range s.profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Get 按名称查找配置
+func (s *ProfileStore) Get(name string) (Profile, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.profiles[name]
+	return p, ok
+}
+
+// Put 新增或替换配置；oldName 为空时新增，与新名称不同时视为重命名，新名称已被其他配置使用时返回 ErrProfileExists。
+// 修改先作用于副本，保存成功后才替换内存中的配置
+func (s *ProfileStore) Put(oldName string, profile Profile) error {
+	profile.Name = strings.TrimSpace(profile.Name)
+	if profile.Name == "" {
+		return errors.New("profile name is empty")
+	}
+	if profile.Jump == profile.Name {
+		return ErrJumpLoop
+	}
+	if profile.Jump != "" && profile.Proxy != "" {
+		return ErrProxyWithJump
+	}
+	if err := profile.Target().Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[profile.Name]; ok && oldName != profile.Name {
+		return fmt.Errorf("%w: %s", ErrProfileExists, profile.Name)
+	}
+	profiles := maps.Clone(s.profiles)
+	if oldName != "" && oldName != profile.Name {
+		delete(profiles, oldName)
+		for name, p := range profiles {
+			if p.Jump == oldName {
+				p.Jump = profile.Name
+				profiles[name] = p
+			}
+		}
+	}
+	profiles[profile.Name] = profile
+	return s.replaceLocked(profiles)
+}
+
+// Delete 删除配置，并清除其他配置对它的跳板机引用
+func (s *ProfileStore) Delete(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.profiles[name]; !ok {
+		return ErrProfileNotFound
+	}
+	profiles := maps.Clone(s.profiles)
+	delete(profiles, name)
+	for other, p := range profiles {
+		if p.Jump == name {
+			p.Jump = ""
+			profiles[other] = p
+		}
+	}
+	return s.replaceLocked(profiles)
+}
+
+// Resolve 返回可直接连接的目标，按 Jump 递归解析跳板机
+func (s *ProfileStore) Resolve(name, knownHostsFile string) (Target, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.resolveLocked(name, knownHostsFile, 0)
+}
+
+func (s *ProfileStore) resolveLocked(name, knownHostsFile string, depth int) (Target, error) {
+	if depth > maxJumpDepth {
+		return Target{}, ErrJumpLoop
+	}
+	p, ok := s.profiles[name]
+	if !ok {
+		return Target{}, fmt.Errorf("%w: %s", ErrProfileNotFound, name)
+	}
+	target := p.Target()
+	target.KnownHostsFile = knownHostsFile
+	if p.Jump != "" {
+		jump, err := s.resolveLocked(p.Jump, knownHostsFile, depth+1)
+		if err != nil {
+			return Target{}, err
+		}
+		target.Jump = &jump
+	}
+	return target, nil
+}
+
+// replaceLocked 保存 profiles，成功后替换内存中的配置；保存失败时保持原样
+func (s *ProfileStore) replaceLocked(profiles map[string]Profile) error {
+	if err := s.saveLocked(profiles); err != nil {
+		return err
+	}
+	s.profiles = profiles
+	return nil
+}
+
+func (s *ProfileStore) saveLocked(profiles map[string]Profile) error {
+	list := make([]Profile, 0, len(profiles))
+	for _, p := range profiles {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	plain, err := json.Marshal(list)
+	if err != nil {
+		return err
+	}
+	gcm, err := newProfileCipher(s.key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(sealedProfiles{
+		Version: profilesFormatVersion,
+		KDF:     kdfArgon2id,
+		Salt:    s.salt,
+		Nonce:   nonce,
+		Data:    gcm.Seal(nil, nonce, plain, nil),
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
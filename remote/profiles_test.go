@@ -0,0 +1,160 @@
+package remote
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestProfileStoreEncryptsAndResolvesJumpHosts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts.enc")
+	store, err := OpenProfiles(path, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("", Profile{Name: "bastion", Host: "10.0.0.1", User: "ops", Auth: AuthKey, KeyPath: "/keys/id"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("", Profile{Name: "vps", Host: "192.168.1.9", Port: 2222, User: "root", Auth: AuthPassword, Password: "hunter2", Jump: "bastion"}); err != nil {
+		t.Fatal(err)
+	}
+
+	raw := readFile(t, path)
+	if strings.Contains(raw, "hunter2") || strings.Contains(raw, "192.168.1.9") {
+		t.Fatal("profiles file should not contain plaintext credentials")
+	}
+	if _, err := OpenProfiles(path, "wrong"); !errors.Is(err, ErrWrongMasterPassword) {
+		t.Fatalf("OpenProfiles(wrong) error = %v", err)
+	}
+
+	reopened, err := OpenProfiles(path, "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.List(); len(got) != 2 || got[0].Name != "bastion" {
+		t.Fatalf("List() = %#v", got)
+	}
+	target, err := reopened.Resolve("vps", "/tmp/known_hosts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if target.Address() != "192.168.1.9:2222" || target.Password != "hunter2" || target.Jump == nil {
+		t.Fatalf("target = %#v", target)
+	}
+	if target.Jump.KeyPath != "/keys/id" || target.Jump.Password != "" || target.Jump.KnownHostsFile != "/tmp/known_hosts" {
+		t.Fatalf("jump = %#v", target.Jump)
+	}
+
+	// 重命名跳板机会同步更新引用；删除后引用被清除
+	bastion, _ := reopened.Get("bastion")
+	bastion.Name = "jump"
+	if err := reopened.Put("bastion", bastion); err != nil {
+		t.Fatal(err)
+	}
+	if vps, _ := reopened.Get("vps"); vps.Jump != "jump" {
+		t.Fatalf("vps.Jump = %q after rename", vps.Jump)
+	}
+	if err := reopened.Delete("jump"); err != nil {
+		t.Fatal(err)
+	}
+	if vps, _ := reopened.Get("vps"); vps.Jump != "" {
+		t.Fatalf("vps.Jump = %q after delete", vps.Jump)
+	}
+	if err := reopened.Delete("missing"); !errors.Is(err, ErrProfileNotFound) {
+		t.Fatalf("Delete(missing) error = %v", err)
+	}
+}
+
+func TestProfileStoreRejectsJumpLoops(t *testing.T) {
+	store, err := OpenProfiles(filepath.Join(t.TempDir(), "hosts.enc"), "m")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("", Profile{Name: "a", Host: "a", User: "u", Password: "p", Jump: "a"}); !errors.Is(err, ErrJumpLoop) {
+		t.Fatalf("self jump error = %v", err)
+	}
+	store.Put("", Profile{Name: "a", Host: "a", User: "u", Password: "p", Jump: "b"})
+	store.Put("", Profile{Name: "b", Host: "b", User: "u", Password: "p", Jump: "a"})
+	if _, err := store.Resolve("a", ""); !errors.Is(err, ErrJumpLoop) {
+		t.Fatalf("Resolve(loop) error = %v", err)
+	}
+	if info, err := os.Stat(store.path); err != nil || info.Mode().Perm() != 0o600 {
+		t.Fatalf("profiles file mode = %v, %v", info, err)
+	}
+}
+
+func TestProfileTags(t *testing.T) {
+	p := Profile{Provider: "Vultr", Region: " Tokyo ", Price: "$5/mo", Labels: []string{"tokyo", "Vultr", "", "bench"}}
+	if got := strings.Join(p.Tags(), ","); got != "Vultr,Tokyo,$5/mo,tokyo,bench" {
+		t.Fatalf("Tags() = %q", got)
+	}
+	if (Profile{}).Tags() != nil {
+		t.Fatal("a profile without tags should have none")
+	}
+}
+
+func TestProfileRejectsProxyWithJump(t *testing.T) {
+	store, err := OpenProfiles(filepath.Join(t.TempDir(), "hosts.enc"), "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile := Profile{Name: "vps", Host: "203.0.113.7", User: "root", Password: "x", Jump: "bastion", Proxy: "socks5://127.0.0.1:1080"}
+	if err := store.Put("", profile); !errors.Is(err, ErrProxyWithJump) {
+		t.Fatalf("Put() error = %v, want ErrProxyWithJump", err)
+	}
+	profile.Jump = ""
+	if err := store.Put("", profile); err != nil {
+		t.Fatal(err)
+	}
+	target, err := store.Resolve("vps", "")
+	if err != nil || target.Proxy != profile.Proxy {
+		t.Fatalf("Resolve() = %+v, %v", target, err)
+	}
+}
+
+func TestProfileStoreRejectsNameCollisions(t *testing.T) {
+	dir := t.TempDir()
+	store, err := OpenProfiles(filepath.Join(dir, "hosts.enc"), "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store.Put("", Profile{Name: "a", Host: "10.0.0.1", User: "u", Password: "p"})
+	store.Put("", Profile{Name: "b", Host: "10.0.0.2", User: "u", Password: "p"})
+	store.Put("", Profile{Name: "c", Host: "10.0.0.3", User: "u", Password: "p", Jump: "a"})
+	if err := store.Put("", Profile{Name: "b", Host: "10.0.0.9", User: "u", Password: "p"}); !errors.Is(err, ErrProfileExists) {
+		t.Fatalf("adding an existing name: error = %v", err)
+	}
+	if err := store.Put("a", Profile{Name: "b", Host: "10.0.0.1", User: "u", Password: "p"}); !errors.Is(err, ErrProfileExists) {
+		t.Fatalf("renaming onto an existing name: error = %v", err)
+	}
+	if b, _ := store.Get("b"); b.Host != "10.0.0.2" {
+		t.Fatalf("b = %+v, the other profile should be untouched", b)
+	}
+	if c, _ := store.Get("c"); c.Jump != "a" {
+		t.Fatalf("c.Jump = %q, jump references should be untouched", c.Jump)
+	}
+	if err := store.Put("b", Profile{Name: "b", Host: "10.0.0.20", User: "u", Password: "p"}); err != nil {
+		t.Fatalf("replacing a profile under its own name: %v", err)
+	}
+
+	// 保存失败时内存中的配置保持原样
+	blocker := filepath.Join(dir, "file")
+	if err := os.WriteFile(blocker, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	store.path = filepath.Join(blocker, "hosts.enc")
+	if err := store.Put("a", Profile{Name: "d", Host: "10.0.0.1", User: "u", Password: "p"}); err == nil {
+		t.Fatal("saving under a file should fail")
+	}
+	if _, ok := store.Get("d"); ok {
+		t.Fatal("a failed save should not add the profile")
+	}
+	if c, _ := store.Get("c"); c.Jump != "a" {
+		t.Fatalf("c.Jump = %q after a failed save", c.Jump)
+	}
+	if err := store.Delete("a"); err == nil || len(store.List()) != 3 {
+		t.Fatalf("Delete() error = %v, %d profiles after a failed save", err, len(store.List()))
+	}
+}
@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// ErrFileTooLarge 表示远程文件超过读取上限
+var ErrFileTooLarge = errors.New("remote file is too large")
+
+// FileInfo 是远程目录中的一项
+type FileInfo struct {
+	Name    string
+	Size    int64
+	Mode    os.FileMode
+	ModTime time.Time
+}
+
+// IsRegular 报告该项是否为普通文件
+func (f FileInfo) IsRegular() bool {
+	return f.Mode.IsRegular()
+}
+
+// SFTP 是经 SSH 连接打开的 SFTP 会话，只提供取回运行产物与上传可执行文件用到的操作，可被多个 goroutine 使用
+type SFTP struct {
+	client *sftp.Client
+}
+
+// SFTP 在已有的 SSH 连接上打开 SFTP 子系统
+func (c *Client) SFTP() (*SFTP, error) {
+	client, err := sftp.NewClient(c.conn)
+	if err != nil {
+		return nil, fmt.Errorf("start sftp subsystem: %w", err)
+	}
+	return &SFTP{client: client}, nil
+}
+
+// Close 结束 SFTP 会话
+func (s *SFTP) Close() error {
+	return s.client.Close()
+}
+
+// ReadDir 列出目录中的各项，不含 . 与 ..
+func (s *SFTP) ReadDir(path string) ([]FileInfo, error) {
+	entries, err := s.client.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]FileInfo, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Name() == "." || entry.Name() == ".." {
+			continue
+		}
+		items = append(items, FileInfo{Name: entry.Name(), Size: entry.Size(), Mode: entry.Mode(), ModTime: entry.ModTime()})
+	}
+	return items, nil
+}
+
+// ReadFile 读取远程文件，超过 limit 字节时返回 ErrFileTooLarge
+func (s *SFTP) ReadFile(path string, limit int64) ([]byte, error) {
+	f, err := s.client.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	content, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(content)) > limit {
+		return nil, ErrFileTooLarge
+	}
+	return content, nil
+}
+
+// WriteFile 把 r 的内容写入远程文件，文件已存在时覆盖
+func (s *SFTP) WriteFile(path string, r io.Reader) error {
+	f, err := s.client.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC)
+	if err != nil {
+		return err
+	}
+	if _, err := f.ReadFrom(r); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Remove 删除远程文件
+func (s *SFTP) Remove(path string) error {
+	return s.client.Remove(path)
+}
@@ -0,0 +1,97 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/sftp"
+)
+
+// sftpPipe 把测试 SSH 服务端子系统的输入输出包装为 sftp 服务端需要的 io.ReadWriteCloser
+type sftpPipe struct {
+	io.Reader
+	io.Writer
+}
+
+func (sftpPipe) Close() error { return nil }
+
+// serveSFTP 在本机文件系统上提供 sftp 子系统，直到客户端关闭会话
+func serveSFTP(t *testing.T, stdin io.Reader, out io.Writer) {
+	server, err := sftp.NewServer(sftpPipe{stdin, out})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	_ = server.Serve()
+}
+
+func TestFetchArtifactsOverSFTP(t *testing.T) {
+	dir := t.TempDir()
+	put := func(name, data string, mtime int64) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, time.Unix(mtime, 0), time.Unix(mtime, 0)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	put("goecs", "binary", 100)
+	put("goecs.txt", "old result", 100)
+	if err := os.Mkdir(filepath.Join(dir, "cache"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	server := startTestServer(t, "secret", func(command string, stdin io.Reader, out io.Writer) uint32 {
+		if command == "subsystem:sftp" {
+			serveSFTP(t, stdin, out)
+		}
+		return 0
+	})
+	client, err := Dial(context.Background(), server.target(t, "secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	s, err := client.SFTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	before := SnapshotDir(s, dir)
+	if len(before) != 2 || !before["goecs"].ModTime.Equal(time.Unix(100, 0)) {
+		t.Fatalf("snapshot = %+v", before)
+	}
+	large := strings.Repeat("x", 100<<10+10)
+	put("goecs.txt", "new result", 200)
+	put("geekbench.json", large, 200)
+	put("goecs.pid", "42", 200)
+
+	artifacts, skipped, err := FetchArtifacts(s, dir, before, []string{"goecs.pid"})
+	if err != nil || len(skipped) != 0 {
+		t.Fatalf("skipped = %v, err = %v", skipped, err)
+	}
+	if len(artifacts) != 2 || artifacts[0].Name != "geekbench.json" || string(artifacts[0].Data) != large || string(artifacts[1].Data) != "new result" {
+		t.Fatalf("artifacts = %d %v", len(artifacts), artifacts)
+	}
+	if err := RemoveArtifacts(s, dir, artifacts); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "goecs.txt")); !os.IsNotExist(err) {
+		t.Fatalf("stat goecs.txt = %v, fetched files should be removed", err)
+	}
+	if _, err := s.ReadFile(filepath.Join(dir, "goecs"), 3); err != ErrFileTooLarge {
+		t.Fatalf("err = %v, want ErrFileTooLarge", err)
+	}
+	if data, err := s.ReadFile(filepath.Join(dir, "goecs"), 6); err != nil || string(data) != "binary" {
+		t.Fatalf("ReadFile() at the limit = %q, %v", data, err)
+	}
+	if _, err := s.ReadDir(filepath.Join(dir, "missing")); err == nil {
+		t.Fatal("listing a missing directory should fail")
+	}
+}
@@ -0,0 +1,74 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// defaultSOCKSPort 是代理地址未写端口时使用的 SOCKS5 端口
+const defaultSOCKSPort = "1080"
+
+// ErrProxyWithJump 表示同一台主机同时设置了跳板机与代理；代理应设置在最外层的跳板机上
+var ErrProxyWithJump = errors.New("a host cannot use both a jump host and a proxy; set the proxy on the jump host")
+
+// ParseProxy 校验 SSH 连接使用的代理地址，支持 socks5://（本机解析目标主机名）与
+// socks5h://（由代理解析），可带用户名与密码
+func ParseProxy(raw string) (*url.URL, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "socks5" && u.Scheme != "socks5h" {
+		return nil, fmt.Errorf("unsupported ssh proxy scheme %q (use socks5 or socks5h)", u.Scheme)
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("proxy address has no host")
+	}
+	return u, nil
+}
+
+// dialSOCKS5 经 SOCKS5 代理连接 address，连接代理与握手共同受 dialer.Timeout 与 ctx 约束
+func dialSOCKS5(ctx context.Context, dialer *net.Dialer, proxyURL, address string) (net.Conn, error) {
+	u, err := ParseProxy(proxyURL)
+	if err != nil {
+		return nil, err
+	}
+	if host, port, err := net.SplitHostPort(address); err != nil {
+		return nil, err
+	} else if u.Scheme == "socks5" && net.ParseIP(host) == nil {
+		addrs, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		address = net.JoinHostPort(addrs[0].IP.String(), port)
+	}
+	proxyAddr := u.Host
+	if u.Port() == "" {
+		proxyAddr = net.JoinHostPort(u.Hostname(), defaultSOCKSPort)
+	}
+	var auth *proxy.Auth
+	if u.User != nil && u.User.Username() != "" {
+		password, _ := u.User.Password()
+		auth = &proxy.Auth{User: u.User.Username(), Password: password}
+	}
+	socks, err := proxy.SOCKS5("tcp", proxyAddr, auth, dialer)
+	if err != nil {
+		return nil, err
+	}
+	if dialer.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, dialer.Timeout)
+		defer cancel()
+	}
+	conn, err := socks.(proxy.ContextDialer).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("socks5 proxy %s: %w", proxyAddr, err)
+	}
+	return conn, nil
+}
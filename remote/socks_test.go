@@ -0,0 +1,37 @@
+package remote
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseProxy(t *testing.T) {
+	for _, raw := range []string{"socks5://127.0.0.1:1080", " socks5h://u:p@proxy.example:1080 "} {
+		if _, err := ParseProxy(raw); err != nil {
+			t.Errorf("ParseProxy(%q) error = %v", raw, err)
+		}
+	}
+	for _, raw := range []string{"http://127.0.0.1:8080", "socks5://", "::"} {
+		if _, err := ParseProxy(raw); err == nil {
+			t.Errorf("ParseProxy(%q) should fail", raw)
+		}
+	}
+}
+
+func TestDialSOCKS5ReportsUnreachableProxy(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+
+	dialer := &net.Dialer{Timeout: 2 * time.Second}
+	_, err = dialSOCKS5(context.Background(), dialer, "socks5h://"+addr, "203.0.113.7:22")
+	if err == nil || !strings.Contains(err.Error(), "socks5 proxy "+addr) {
+		t.Fatalf("dialSOCKS5() error = %v", err)
+	}
+}
@@ -0,0 +1,419 @@
+// Package remote 通过 SSH 在远程主机上运行 goecs：建立连接、上传或下载二进制、
+// 执行命令并把远程输出实时回传给调用方。
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+
+	"github.com/oneclickvirt/ecs-gui/applog"
+)
+
+var logger = applog.For("ssh")
+
+const (
+	// DefaultPort 是 SSH 默认端口
+	DefaultPort = 22
+	// DefaultDialTimeout 是建立 TCP 连接与 SSH 握手的默认超时
+	DefaultDialTimeout = 15 * time.Second
+)
+
+var (
+	// ErrHostKeyMismatch 表示远程主机公钥与 known_hosts 中记录的不一致
+	ErrHostKeyMismatch = errors.New("remote host key mismatch")
+	// ErrNoAuth 表示既没有密码也没有私钥
+	ErrNoAuth = errors.New("no ssh password or private key provided")
+)
+
+// Target 描述一台远程主机及其认证方式
+type Target struct {
+	Host          string
+	Port          int
+	User          string
+	Password      string
+	KeyPath       string
+	KeyPassphrase string
+	// KnownHostsFile 为空时不校验主机公钥；否则首次连接时记录公钥，之后严格校验
+	KnownHostsFile string
+	DialTimeout    time.Duration
+	// Jump 非空时先连接跳板机，再经由跳板机转发到目标主机
+	Jump *Target
+	// Proxy 为 SOCKS5 代理地址（见 ParseProxy），非空时经代理连接；与 Jump 互斥，需要时设置在跳板机上
+	Proxy string
+	// OS 为 OSWindows 时按 Windows 主机部署与运行 goecs，为空时按类 Unix 系统处理
+	OS string
+	// SudoPassword 是非 root 用户运行需要 root 的测试时交给 sudo 的密码，为空时运行前询问
+	SudoPassword string
+}
+
+// Address 返回 host:port
+func (t Target) Address() string {
+	port := t.Port
+	if port <= 0 {
+		port = DefaultPort
+	}
+	return net.JoinHostPort(strings.TrimSpace(t.Host), strconv.Itoa(port))
+}
+
+// Validate 检查必填字段
+func (t Target) Validate() error {
+	if strings.TrimSpace(t.Host) == "" {
+		return errors.New("remote host is empty")
+	}
+	if strings.TrimSpace(t.User) == "" {
+		return errors.New("remote user is empty")
+	}
+	if t.Port < 0 || t.Port > 65535 {
+		return fmt.Errorf("invalid remote port %d", t.Port)
+	}
+	if t.Password == "" && strings.TrimSpace(t.KeyPath) == "" {
+		return ErrNoAuth
+	}
+	if t.Proxy != "" {
+		if t.Jump != nil {
+			return ErrProxyWithJump
+		}
+		if _, err := ParseProxy(t.Proxy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Target) authMethods() ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	if keyPath := strings.TrimSpace(t.KeyPath); keyPath != "" {
+		data, err := os.ReadFile(keyPath)
+		if err != nil {
+			return nil, fmt.Errorf("read private key: %w", err)
+		}
+		var signer ssh.Signer
+		if t.KeyPassphrase != "" {
+			signer, err = ssh.ParsePrivateKeyWithPassphrase(data, []byte(t.KeyPassphrase))
+		} else {
+			signer, err = ssh.ParsePrivateKey(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse private key: %w", err)
+		}
+		methods = append(methods, ssh.PublicKeys(signer))
+	}
+	if t.Password != "" {
+		password := t.Password
+		methods = append(methods,
+			ssh.Password(password),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = password
+				}
+				return answers, nil
+			}),
+		)
+	}
+	if len(methods) == 0 {
+		return nil, ErrNoAuth
+	}
+	return methods, nil
+}
+
+// hostKeyCallback 实现“首次信任”：未知主机写入 known_hosts，已知主机公钥变化时拒绝连接
+func (t Target) hostKeyCallback() (ssh.HostKeyCallback, error) {
+	path := strings.TrimSpace(t.KnownHostsFile)
+	if path == "" {
+		return ssh.InsecureIgnoreHostKey(), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+	check, err := knownhosts.New(path)
+	if err != nil {
+		return nil, err
+	}
+	var mu sync.Mutex
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		err := check(hostname, remote, key)
+		var keyErr *knownhosts.KeyError
+		if err == nil || !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			return fmt.Errorf("%w: %s (%s)", ErrHostKeyMismatch, hostname, ssh.FingerprintSHA256(key))
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		out, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o600)
+		if err != nil {
+			return err
+		}
+		defer out.Close()
+		_, err = fmt.Fprintln(out, knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key))
+		return err
+	}, nil
+}
+
+// Client 是一条已建立的 SSH 连接
+type Client struct {
+	target Target
+	conn   *ssh.Client
+	jump   *Client
+}
+
+// Dial 连接远程主机，ctx 取消时中止握手
+func Dial(ctx context.Context, target Target) (*Client, error) {
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	auth, err := target.authMethods()
+	if err != nil {
+		return nil, err
+	}
+	hostKey, err := target.hostKeyCallback()
+	if err != nil {
+		return nil, err
+	}
+	timeout := target.DialTimeout
+	if timeout <= 0 {
+		timeout = DefaultDialTimeout
+	}
+	config := &ssh.ClientConfig{
+		User:            target.User,
+		Auth:            auth,
+		HostKeyCallback: hostKey,
+		Timeout:         timeout,
+	}
+
+	var jump *Client
+	var rawConn net.Conn
+	if target.Jump != nil {
+		if jump, err = Dial(ctx, *target.Jump); err != nil {
+			return nil, fmt.Errorf("jump host %s: %w", target.Jump.Address(), err)
+		}
+		rawConn, err = jump.conn.DialContext(ctx, "tcp", target.Address())
+	} else {
+		dialer := net.Dialer{Timeout: timeout}
+		if target.Proxy != "" {
+			rawConn, err = dialSOCKS5(ctx, &dialer, target.Proxy, target.Address())
+		} else {
+			rawConn, err = dialer.DialContext(ctx, "tcp", target.Address())
+		}
+	}
+	if err != nil {
+		if jump != nil {
+			jump.Close()
+		}
+		logger.Warn("dial failed", "addr", target.Address(), "err", err)
+		return nil, err
+	}
+	stop := context.AfterFunc(ctx, func() { rawConn.Close() })
+	defer stop()
+	rawConn.SetDeadline(time.Now().Add(timeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(rawConn, target.Address(), config)
+	if err != nil {
+		rawConn.Close()
+		if jump != nil {
+			jump.Close()
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		logger.Warn("handshake failed", "addr", target.Address(), "user", target.User, "err", err)
+		return nil, err
+	}
+	rawConn.SetDeadline(time.Time{})
+	logger.Info("connected", "addr", target.Address(), "user", target.User, "jump", target.Jump != nil, "proxy", target.Proxy != "")
+	return &Client{target: target, conn: ssh.NewClient(sshConn, chans, reqs), jump: jump}, nil
+}
+
+// Probe 连接并认证后立即断开，返回所用时间，用于运行前检查主机是否可达
+func Probe(ctx context.Context, target Target) (time.Duration, error) {
+	started := time.Now()
+	client, err := Dial(ctx, target)
+	if err != nil {
+		return 0, err
+	}
+	elapsed := time.Since(started)
+	client.Close()
+	return elapsed, nil
+}
+
+// Target 返回连接所用的目标信息
+func (c *Client) Target() Target {
+	return c.target
+}
+
+// Close 关闭连接（以及经由的跳板机连接）
+func (c *Client) Close() error {
+	err := c.conn.Close()
+	if c.jump != nil {
+		c.jump.Close()
+	}
+	return err
+}
+
+// Output 执行一条短命令并返回合并后的输出
+func (c *Client) Output(ctx context.Context, command string) (string, error) {
+	var buf strings.Builder
+	err := c.Run(ctx, command, false, func(text string) { buf.WriteString(text) })
+	return buf.String(), err
+}
+
+// Run 执行命令并把 stdout/stderr 实时交给 output；pty 为 true 时申请伪终端以保留彩色输出。
+// ctx 取消时先发送 SIGINT，再关闭会话。
+func (c *Client) Run(ctx context.Context, command string, pty bool, output func(string)) error {
+	return c.RunWithInput(ctx, command, pty, nil, output)
+}
+
+// RunWithInput 与 Run 相同，另把 input 中的文本写入命令的标准输入，用于回应交互式提示；input 为 nil 时不接标准输入
+func (c *Client) RunWithInput(ctx context.Context, command string, pty bool, input <-chan string, output func(string)) error {
+	session, err := c.conn.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	if pty {
+		modes := ssh.TerminalModes{ssh.ECHO: 0, ssh.TTY_OP_ISPEED: 14400, ssh.TTY_OP_OSPEED: 14400}
+		if err := session.RequestPty("xterm-256color", 50, 200, modes); err != nil {
+			return fmt.Errorf("request pty: %w", err)
+		}
+	}
+	writer := &callbackWriter{output: output}
+	session.Stdout = writer
+	session.Stderr = writer
+	if input != nil {
+		// 用 StdinPipe 而不是 session.Stdin：后者在 Wait 时会一直等到输入结束
+		stdin, err := session.StdinPipe()
+		if err != nil {
+			return err
+		}
+		stop := make(chan struct{})
+		defer close(stop)
+		go ForwardInput(stdin, input, stop)
+	}
+
+	logger.Debug("run", "addr", c.target.Address(), "pty", pty, "command", command)
+	if err := session.Start(command); err != nil {
+		return err
+	}
+	done := make(chan error, 1)
+	go func() { done <- session.Wait() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			logger.Debug("command exited", "addr", c.target.Address(), "err", err)
+		}
+		return err
+	case <-ctx.Done():
+		logger.Info("interrupting remote command", "addr", c.target.Address())
+		_ = session.Signal(ssh.SIGINT)
+		select {
+		case <-done:
+		case <-time.After(3 * time.Second):
+		}
+		session.Close()
+		return ctx.Err()
+	}
+}
+
+// ForwardInput 把 input 中的文本依次写入 w，直到 stop 关闭或写入失败
+func ForwardInput(w io.Writer, input <-chan string, stop <-chan struct{}) {
+	for {
+		select {
+		case <-stop:
+			return
+		case text := <-input:
+			if _, err := io.WriteString(w, text); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Upload 把 r 的内容写入远程 remotePath 并设置权限，通过 `cat` 传输，不依赖 SFTP/SCP
+func (c *Client) Upload(ctx context.Context, r io.Reader, remotePath string, mode os.FileMode) error {
+	session, err := c.conn.NewSession()
+	if err != nil {
+		return err
+	}
+	defer session.Close()
+
+	stdin, err := session.StdinPipe()
+	if err != nil {
+		return err
+	}
+	var stderr strings.Builder
+	session.Stderr = &stderr
+	quoted := Quote(remotePath)
+	command := fmt.Sprintf("cat > %s && chmod %o %s", quoted, mode.Perm(), quoted)
+	if err := session.Start(command); err != nil {
+		return err
+	}
+	stop := context.AfterFunc(ctx, func() { session.Close() })
+	defer stop()
+
+	if _, err := io.Copy(stdin, r); err != nil {
+		return err
+	}
+	stdin.Close()
+	if err := session.Wait(); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("upload %s: %s", remotePath, msg)
+		}
+		return err
+	}
+	return nil
+}
+
+// Quote 以 POSIX shell 单引号转义参数
+func Quote(s string) string {
+	if s == "" {
+		return "''"
+	}
+	safe := true
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_./=:,+@%", r)) {
+			safe = false
+			break
+		}
+	}
+	if safe {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'"'"'`) + "'"
+}
+
+type callbackWriter struct {
+	mu     sync.Mutex
+	output func(string)
+}
+
+func (w *callbackWriter) Write(p []byte) (int, error) {
+	if w.output != nil && len(p) > 0 {
+		w.mu.Lock()
+		w.output(strings.ReplaceAll(string(p), "\r\n", "\n"))
+		w.mu.Unlock()
+	}
+	return len(p), nil
+}
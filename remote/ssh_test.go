@@ -0,0 +1,337 @@
+package remote
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+// testServer 是一个只支持 exec 与子系统请求的最小 SSH 服务端，handler 决定命令的输出与退出码，
+// 子系统请求的命令为 "subsystem:" 加子系统名
+type testServer struct {
+	addr    string
+	mu      sync.Mutex
+	uploads map[string]string
+}
+
+func startTestServer(t *testing.T, password string, handler func(command string, stdin io.Reader, out io.Writer) uint32) *testServer {
+	t.Helper()
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(_ ssh.ConnMetadata, pass []byte) (*ssh.Permissions, error) {
+			if string(pass) != password {
+				return nil, errors.New("denied")
+			}
+			return nil, nil
+		},
+	}
+	config.AddHostKey(signer)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+	server := &testServer{addr: ln.Addr().String(), uploads: map[string]string{}}
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, config)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+				for newChan := range chans {
+					if newChan.ChannelType() == "direct-tcpip" {
+						go forwardDirectTCPIP(newChan)
+						continue
+					}
+					ch, requests, err := newChan.Accept()
+					if err != nil {
+						continue
+					}
+					go func() {
+						defer ch.Close()
+						for req := range requests {
+							if req.Type != "exec" && req.Type != "subsystem" {
+								req.Reply(req.Type == "pty-req", nil)
+								continue
+							}
+							length := binary.BigEndian.Uint32(req.Payload)
+							command := string(req.Payload[4 : 4+length])
+							if req.Type == "subsystem" {
+								command = "subsystem:" + command
+							}
+							req.Reply(true, nil)
+							code := handler(command, ch, ch)
+							status := make([]byte, 4)
+							binary.BigEndian.PutUint32(status, code)
+							ch.SendRequest("exit-status", false, status)
+							return
+						}
+					}()
+				}
+			}()
+		}
+	}()
+	return server
+}
+
+// forwardDirectTCPIP 让测试服务端充当跳板机，把转发请求连到目标地址
+func forwardDirectTCPIP(newChan ssh.NewChannel) {
+	var payload struct {
+		Host       string
+		Port       uint32
+		OriginHost string
+		OriginPort uint32
+	}
+	if err := ssh.Unmarshal(newChan.ExtraData(), &payload); err != nil {
+		newChan.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	conn, err := net.Dial("tcp", net.JoinHostPort(payload.Host, strconv.Itoa(int(payload.Port))))
+	if err != nil {
+		newChan.Reject(ssh.ConnectionFailed, err.Error())
+		return
+	}
+	ch, requests, err := newChan.Accept()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	go func() {
+		io.Copy(conn, ch)
+		conn.Close()
+	}()
+	io.Copy(ch, conn)
+	ch.Close()
+}
+
+func (s *testServer) target(t *testing.T, password string) Target {
+	host, portText, _ := net.SplitHostPort(s.addr)
+	port, _ := strconv.Atoi(portText)
+	return Target{
+		Host:           host,
+		Port:           port,
+		User:           "root",
+		Password:       password,
+		KnownHostsFile: filepath.Join(t.TempDir(), "known_hosts"),
+	}
+}
+
+func TestClientRunStreamsOutputAndUploads(t *testing.T) {
+	var server *testServer
+	server = startTestServer(t, "secret", func(command string, stdin io.Reader, out io.Writer) uint32 {
+		switch {
+		case strings.HasPrefix(command, "cat > "):
+			data, _ := io.ReadAll(stdin)
+			server.mu.Lock()
+			server.uploads[command] = string(data)
+			server.mu.Unlock()
+			return 0
+		case command == "false":
+			return 1
+		default:
+			io.WriteString(out, "ran: "+command+"\r\n")
+			return 0
+		}
+	})
+	target := server.target(t, "secret")
+
+	client, err := Dial(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer client.Close()
+
+	out, err := client.Output(context.Background(), "uname -sm")
+	if err != nil || out != "ran: uname -sm\n" {
+		t.Fatalf("Output() = %q, %v", out, err)
+	}
+	if err := client.Run(context.Background(), "false", true, nil); err == nil {
+		t.Fatal("Run(false) should report the non-zero exit status")
+	}
+	if err := client.Upload(context.Background(), strings.NewReader("binary"), "/root/.goecs-gui/goecs", 0o755); err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	server.mu.Lock()
+	got := server.uploads["cat > /root/.goecs-gui/goecs && chmod 755 /root/.goecs-gui/goecs"]
+	server.mu.Unlock()
+	if got != "binary" {
+		t.Fatalf("uploads = %#v", server.uploads)
+	}
+
+	// 第二次连接使用首次记录的主机公钥，应当校验通过
+	again, err := Dial(context.Background(), target)
+	if err != nil {
+		t.Fatalf("second Dial() error = %v", err)
+	}
+	again.Close()
+}
+
+func TestDialThroughJumpHost(t *testing.T) {
+	jump := startTestServer(t, "jump-pw", func(string, io.Reader, io.Writer) uint32 { return 0 })
+	dest := startTestServer(t, "dest-pw", func(command string, _ io.Reader, out io.Writer) uint32 {
+		io.WriteString(out, "dest:"+command)
+		return 0
+	})
+	target := dest.target(t, "dest-pw")
+	jumpTarget := jump.target(t, "jump-pw")
+	jumpTarget.KnownHostsFile = target.KnownHostsFile
+	target.Jump = &jumpTarget
+
+	client, err := Dial(context.Background(), target)
+	if err != nil {
+		t.Fatalf("Dial() via jump error = %v", err)
+	}
+	defer client.Close()
+	if out, err := client.Output(context.Background(), "id"); err != nil || out != "dest:id" {
+		t.Fatalf("Output() = %q, %v", out, err)
+	}
+
+	target.Jump.Password = "wrong"
+	if _, err := Dial(context.Background(), target); err == nil || !strings.Contains(err.Error(), "jump host") {
+		t.Fatalf("Dial() with bad jump credentials error = %v", err)
+	}
+}
+
+func TestProbeReportsLatencyOrError(t *testing.T) {
+	server := startTestServer(t, "pw", func(string, io.Reader, io.Writer) uint32 { return 0 })
+	target := server.target(t, "pw")
+	if latency, err := Probe(context.Background(), target); err != nil || latency <= 0 {
+		t.Fatalf("Probe() = %v, %v", latency, err)
+	}
+	target.Password = "wrong"
+	if _, err := Probe(context.Background(), target); err == nil {
+		t.Fatal("Probe() with a wrong password should fail")
+	}
+}
+
+func TestDialRejectsChangedHostKey(t *testing.T) {
+	handler := func(string, io.Reader, io.Writer) uint32 { return 0 }
+	first := startTestServer(t, "pw", handler)
+	target := first.target(t, "pw")
+	client, err := Dial(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.Close()
+
+	// 模拟同一主机更换了主机密钥：把已记录的公钥改写到第二台服务端的地址上
+	second := startTestServer(t, "pw", handler)
+	known := target.KnownHostsFile
+	_, firstPort, _ := net.SplitHostPort(first.addr)
+	_, secondPort, _ := net.SplitHostPort(second.addr)
+	writeFile(t, known, strings.ReplaceAll(readFile(t, known), "]:"+firstPort, "]:"+secondPort))
+	target = second.target(t, "pw")
+	target.KnownHostsFile = known
+
+	if _, err := Dial(context.Background(), target); !errors.Is(err, ErrHostKeyMismatch) {
+		t.Fatalf("Dial() error = %v, want ErrHostKeyMismatch", err)
+	}
+}
+
+func TestTargetValidate(t *testing.T) {
+	cases := []struct {
+		name   string
+		target Target
+		ok     bool
+	}{
+		{"password", Target{Host: "1.2.3.4", User: "root", Password: "x"}, true},
+		{"key", Target{Host: "1.2.3.4", User: "root", KeyPath: "/tmp/id"}, true},
+		{"no host", Target{User: "root", Password: "x"}, false},
+		{"no user", Target{Host: "1.2.3.4", Password: "x"}, false},
+		{"no auth", Target{Host: "1.2.3.4", User: "root"}, false},
+		{"bad port", Target{Host: "1.2.3.4", User: "root", Password: "x", Port: 70000}, false},
+		{"socks proxy", Target{Host: "1.2.3.4", User: "root", Password: "x", Proxy: "socks5h://u:p@127.0.0.1:1080"}, true},
+		{"http proxy", Target{Host: "1.2.3.4", User: "root", Password: "x", Proxy: "http://127.0.0.1:3128"}, false},
+		{"proxy and jump", Target{Host: "1.2.3.4", User: "root", Password: "x", Proxy: "socks5://127.0.0.1", Jump: &Target{}}, false},
+	}
+	for _, tc := range cases {
+		if err := tc.target.Validate(); (err == nil) != tc.ok {
+			t.Fatalf("%s: Validate() error = %v", tc.name, err)
+		}
+	}
+	if got := (Target{Host: "::1"}).Address(); got != "[::1]:22" {
+		t.Fatalf("Address() = %q", got)
+	}
+}
+
+func TestForwardInputStopsOnSignal(t *testing.T) {
+	input := make(chan string, 2)
+	stop := make(chan struct{})
+	reader, writer := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		ForwardInput(writer, input, stop)
+		close(done)
+	}()
+	input <- "yes\n"
+	buf := make([]byte, 8)
+	n, err := reader.Read(buf)
+	if err != nil || string(buf[:n]) != "yes\n" {
+		t.Fatalf("read %q, %v", buf[:n], err)
+	}
+	close(stop)
+	<-done
+}
+
+func TestSudoModeAndCommand(t *testing.T) {
+	var reply string
+	server := startTestServer(t, "secret", func(command string, stdin io.Reader, out io.Writer) uint32 {
+		if command == sudoProbe {
+			io.WriteString(out, reply+"\n")
+		}
+		return 0
+	})
+	target := server.target(t, "secret")
+	client, err := Dial(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if mode, _ := client.SudoMode(context.Background()); mode != SudoNotNeeded {
+		t.Fatalf("root mode = %v", mode)
+	}
+
+	target.User = "ubuntu"
+	client.target = target
+	for out, want := range map[string]SudoMode{"sudo=missing": SudoUnavailable, "sudo=nopasswd": SudoNoPassword, "sudo=password": SudoWithPassword} {
+		reply = out
+		if mode, err := client.SudoMode(context.Background()); err != nil || mode != want {
+			t.Fatalf("%s: mode = %v, %v", out, mode, err)
+		}
+	}
+
+	command := "cd .goecs-gui && ./goecs -l 'zh'"
+	if got := SudoCommand(command, true); got != `sudo -S -p '' sh -c 'cd .goecs-gui && ./goecs -l '"'"'zh'"'"''` {
+		t.Fatalf("SudoCommand = %s", got)
+	}
+	if got := SudoCommand("./goecs", false); got != "sudo -n sh -c ./goecs" {
+		t.Fatalf("SudoCommand = %s", got)
+	}
+}
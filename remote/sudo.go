@@ -0,0 +1,52 @@
+package remote
+
+import (
+	"context"
+	"strings"
+)
+
+// SudoMode 描述非 root 用户在远程主机上取得 root 权限的方式
+type SudoMode int
+
+const (
+	// SudoNotNeeded 表示以 root 登录，不需要 sudo
+	SudoNotNeeded SudoMode = iota
+	// SudoUnavailable 表示远程主机没有 sudo，测试以登录用户运行
+	SudoUnavailable
+	// SudoNoPassword 表示 sudo 不需要密码（NOPASSWD）
+	SudoNoPassword
+	// SudoWithPassword 表示 sudo 需要登录用户的密码
+	SudoWithPassword
+)
+
+// sudoProbe 依次检查 sudo 是否存在、是否无需密码
+const sudoProbe = `command -v sudo >/dev/null 2>&1 || { echo sudo=missing; exit 0; }; if sudo -n true 2>/dev/null; then echo sudo=nopasswd; else echo sudo=password; fi`
+
+// SudoMode 检查登录用户运行需要 root 的测试时应如何使用 sudo；Windows 主机不使用 sudo
+func (c *Client) SudoMode(ctx context.Context) (SudoMode, error) {
+	if strings.TrimSpace(c.target.User) == "root" || c.target.IsWindows() {
+		return SudoNotNeeded, nil
+	}
+	out, err := c.Output(ctx, sudoProbe)
+	if err != nil {
+		return SudoUnavailable, err
+	}
+	switch {
+	case strings.Contains(out, "sudo=nopasswd"):
+		return SudoNoPassword, nil
+	case strings.Contains(out, "sudo=password"):
+		return SudoWithPassword, nil
+	default:
+		return SudoUnavailable, nil
+	}
+}
+
+// SudoCommand 用 sudo 包装 command。withPassword 为 true 时 sudo 从标准输入读取密码（-S）且不显示提示，
+// 调用方需先把密码与换行写入标准输入；为 false 时使用 -n，需要密码时直接失败而不是等待
+func SudoCommand(command string, withPassword bool) string {
+	flags := "-n"
+	if withPassword {
+		flags = "-S -p ''"
+	}
+	return "sudo " + flags + " sh -c " + Quote(command)
+}
@@ -0,0 +1,165 @@
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+)
+
+// OSWindows 是 Target.OS 中表示 Windows 主机的取值，为空时按类 Unix 系统处理。
+// Windows 主机与其他目标一样经 SSH（Windows 自带的 OpenSSH 服务）连接，不支持 WinRM
+const OSWindows = "windows"
+
+// IsWindows 报告目标是否为 Windows 主机（经 Windows 自带的 OpenSSH 服务连接）
+func (t Target) IsWindows() bool {
+	return t.OS == OSWindows
+}
+
+// WindowsReleaseAsset 根据 %PROCESSOR_ARCHITECTURE% 选择 Windows 版 goecs 发布包
+func WindowsReleaseAsset(arch string) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(arch)) {
+	case "AMD64":
+		return "goecs_windows_amd64.zip", nil
+	case "ARM64":
+		return "goecs_windows_arm64.zip", nil
+	case "X86":
+		return "goecs_windows_386.zip", nil
+	}
+	return "", fmt.Errorf("unsupported remote architecture %q on windows", strings.TrimSpace(arch))
+}
+
+// PowerShell 把脚本包装为 powershell -EncodedCommand 命令，默认 shell 为 cmd 或 PowerShell 时都能执行，且无需转义引号
+func PowerShell(script string) string {
+	encoded := utf16.Encode([]rune(script))
+	data := make([]byte, 0, len(encoded)*2)
+	for _, unit := range encoded {
+		data = binary.LittleEndian.AppendUint16(data, unit)
+	}
+	return "powershell -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand " + base64.StdEncoding.EncodeToString(data)
+}
+
+// PSQuote 把字符串转为 PowerShell 单引号字面量
+func PSQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
+
+// SFTPPath 把 Windows 路径（C:\Users\me）转换为 Windows OpenSSH 的 SFTP 路径（/C:/Users/me）
+func SFTPPath(windowsPath string) string {
+	p := strings.ReplaceAll(windowsPath, `\`, "/")
+	if len(p) >= 2 && p[1] == ':' {
+		p = "/" + p
+	}
+	return p
+}
+
+// windowsDir 返回 Windows 路径所在的目录
+func windowsDir(p string) string {
+	if i := strings.LastIndexAny(p, `\/`); i > 0 {
+		return p[:i]
+	}
+	return p
+}
+
+// WindowsInstallScript 生成在 Windows 主机上下载并解压指定版本 goecs 的 PowerShell 脚本
+func WindowsInstallScript(version, asset, workDir string) string {
+	version = strings.TrimPrefix(strings.TrimSpace(version), "v")
+	url := fmt.Sprintf("%s/v%s/%s", releaseBaseURL, version, asset)
+	return strings.Join([]string{
+		"$ErrorActionPreference = 'Stop'",
+		"$ProgressPreference = 'SilentlyContinue'",
+		"[Net.ServicePointManager]::SecurityProtocol = [Net.SecurityProtocolType]::Tls12",
+		"Set-Location -LiteralPath " + PSQuote(workDir),
+		"Invoke-WebRequest -UseBasicParsing -Uri " + PSQuote(url) + " -OutFile goecs.zip",
+		"Expand-Archive -Force -Path goecs.zip -DestinationPath .",
+		"Remove-Item -Force goecs.zip",
+	}, "\n")
+}
+
+// WindowsScript 组合在工作目录中执行 goecs.exe 的 PowerShell 脚本，env 中每项为 KEY=VALUE；
+// 交给 PowerShell 包装后执行，脚本本身可用于在终端中展示
+func WindowsScript(binary string, env, args []string) string {
+	var b strings.Builder
+	b.WriteString("Set-Location -LiteralPath " + PSQuote(windowsDir(binary)) + "; ")
+	for _, kv := range env {
+		if key, value, ok := strings.Cut(kv, "="); ok && key != "" {
+			b.WriteString("${env:" + key + "} = " + PSQuote(value) + "; ")
+		}
+	}
+	b.WriteString("& " + PSQuote(binary))
+	for _, arg := range args {
+		b.WriteString(" " + PSQuote(arg))
+	}
+	b.WriteString("; exit $LASTEXITCODE")
+	return b.String()
+}
+
+// prepareWindows 是 Prepare 在 Windows 主机上的实现：工作目录为 %USERPROFILE%\.goecs-gui，
+// 经 SFTP 上传 goecs.exe，或在远程用 Invoke-WebRequest 下载
+func prepareWindows(ctx context.Context, client *Client, version, localBinary string, fetch Fetcher, emit func(string)) (string, error) {
+	out, err := client.Output(ctx, PowerShell(`Write-Output "$env:USERPROFILE|$env:PROCESSOR_ARCHITECTURE"`))
+	if err != nil {
+		return "", fmt.Errorf("resolve remote home: %w", err)
+	}
+	home, arch, ok := strings.Cut(strings.TrimSpace(out), "|")
+	if !ok || strings.TrimSpace(home) == "" {
+		return "", fmt.Errorf("unexpected output from windows host %q", strings.TrimSpace(out))
+	}
+	workDir := strings.TrimRight(strings.TrimSpace(home), `\`) + `\` + DefaultWorkDir
+	binary := workDir + `\goecs.exe`
+	mkdir := "New-Item -ItemType Directory -Force -Path " + PSQuote(workDir) + " | Out-Null"
+	if _, err := client.Output(ctx, PowerShell(mkdir)); err != nil {
+		return "", fmt.Errorf("create remote work dir: %w", err)
+	}
+
+	if localBinary = strings.TrimSpace(localBinary); localBinary != "" {
+		return binary, uploadSFTP(ctx, client, localBinary, binary, emit)
+	}
+	asset, err := WindowsReleaseAsset(arch)
+	if err != nil {
+		return "", err
+	}
+	if fetch != nil {
+		emit(fmt.Sprintf("fetch %s %s\n", asset, version))
+		local, err := fetch(ctx, version, asset)
+		if err == nil {
+			return binary, uploadSFTP(ctx, client, local, binary, emit)
+		}
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		emit(fmt.Sprintf("local download failed: %v\n", err))
+	}
+	emit(fmt.Sprintf("download %s %s -> %s\n", asset, version, binary))
+	if err := client.Run(ctx, PowerShell(WindowsInstallScript(version, asset, workDir)), false, emit); err != nil {
+		return "", fmt.Errorf("install goecs on remote host: %w", err)
+	}
+	return binary, nil
+}
+
+// uploadSFTP 经 SFTP 上传本地文件，用于没有 cat 与 chmod 的 Windows 主机
+func uploadSFTP(ctx context.Context, client *Client, local, binary string, emit func(string)) error {
+	f, err := os.Open(local)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	s, err := client.SFTP()
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+	stop := context.AfterFunc(ctx, func() { s.Close() })
+	defer stop()
+	emit(fmt.Sprintf("upload %s -> %s\n", local, binary))
+	if err := s.WriteFile(SFTPPath(binary), f); err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		return err
+	}
+	return nil
+}
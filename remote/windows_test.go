@@ -0,0 +1,115 @@
+package remote
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/pkg/sftp"
+)
+
+// decodePowerShell 还原 PowerShell 包装前的脚本
+func decodePowerShell(t *testing.T, command string) string {
+	t.Helper()
+	prefix := "powershell -NoProfile -NonInteractive -ExecutionPolicy Bypass -EncodedCommand "
+	if !strings.HasPrefix(command, prefix) {
+		t.Fatalf("command %q is not wrapped for powershell", command)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(command, prefix))
+	if err != nil {
+		t.Fatal(err)
+	}
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = binary.LittleEndian.Uint16(data[2*i:])
+	}
+	return string(utf16.Decode(units))
+}
+
+func TestWindowsScriptQuotesArguments(t *testing.T) {
+	script := WindowsScript(`C:\Users\o'neil\.goecs-gui\goecs.exe`, []string{"LANG=en"}, []string{"-l", "en", "-menu=false"})
+	want := `Set-Location -LiteralPath 'C:\Users\o''neil\.goecs-gui'; ${env:LANG} = 'en'; & 'C:\Users\o''neil\.goecs-gui\goecs.exe' '-l' 'en' '-menu=false'; exit $LASTEXITCODE`
+	if script != want {
+		t.Fatalf("WindowsScript() =\n%s\nwant\n%s", script, want)
+	}
+	if got := decodePowerShell(t, PowerShell("Write-Output 'ä'")); got != "Write-Output 'ä'" {
+		t.Fatalf("PowerShell() round trip = %q", got)
+	}
+	if got := SFTPPath(`C:\Users\me\.goecs-gui`); got != "/C:/Users/me/.goecs-gui" {
+		t.Fatalf("SFTPPath() = %q", got)
+	}
+	for arch, want := range map[string]string{"AMD64": "goecs_windows_amd64.zip", "arm64": "goecs_windows_arm64.zip", "x86": "goecs_windows_386.zip"} {
+		if got, err := WindowsReleaseAsset(arch); err != nil || got != want {
+			t.Fatalf("WindowsReleaseAsset(%s) = %q, %v", arch, got, err)
+		}
+	}
+	if _, err := WindowsReleaseAsset("IA64"); err == nil {
+		t.Fatal("IA64 should be rejected")
+	}
+}
+
+func TestPrepareWindowsUploadsOverSFTP(t *testing.T) {
+	// Windows 的 SFTP 路径形如 /C:/Users/bob，用内存文件系统代替本机目录
+	handlers := sftp.InMemHandler()
+	var scripts []string
+	server := startTestServer(t, "secret", func(command string, stdin io.Reader, out io.Writer) uint32 {
+		if command == "subsystem:sftp" {
+			_ = sftp.NewRequestServer(sftpPipe{stdin, out}, handlers).Serve()
+			return 0
+		}
+		script := decodePowerShell(t, command)
+		scripts = append(scripts, script)
+		if strings.Contains(script, "USERPROFILE") {
+			io.WriteString(out, "C:\\Users\\bob|AMD64\r\n")
+		}
+		return 0
+	})
+	target := server.target(t, "secret")
+	target.OS, target.User = OSWindows, "bob"
+	client, err := Dial(context.Background(), target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	// 目录由 Prepare 的 PowerShell 脚本创建，这里在内存文件系统中预先建好
+	s, err := client.SFTP()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if err := s.client.MkdirAll("/C:/Users/bob/.goecs-gui"); err != nil {
+		t.Fatal(err)
+	}
+
+	local := filepath.Join(t.TempDir(), "goecs.exe")
+	if err := os.WriteFile(local, []byte(strings.Repeat("MZ", 64<<10)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var asset string
+	fetch := func(_ context.Context, _, name string) (string, error) {
+		asset = name
+		return local, nil
+	}
+	binary, err := Prepare(context.Background(), client, "0.1.0", "", fetch, nil)
+	if err != nil {
+		t.Fatalf("Prepare() error = %v", err)
+	}
+	if binary != `C:\Users\bob\.goecs-gui\goecs.exe` || asset != "goecs_windows_amd64.zip" {
+		t.Fatalf("binary = %q, asset = %q", binary, asset)
+	}
+	if len(scripts) != 2 || !strings.Contains(scripts[1], `New-Item -ItemType Directory -Force -Path 'C:\Users\bob\.goecs-gui'`) {
+		t.Fatalf("scripts = %q", scripts)
+	}
+	if got, err := s.ReadFile("/C:/Users/bob/.goecs-gui/goecs.exe", 1<<20); err != nil || len(got) != 128<<10 {
+		t.Fatalf("uploaded %d bytes, %v; want %d", len(got), err, 128<<10)
+	}
+	if mode, err := client.SudoMode(context.Background()); err != nil || mode != SudoNotNeeded {
+		t.Fatalf("SudoMode() = %v, %v; windows hosts do not use sudo", mode, err)
+	}
+}
@@ -0,0 +1,205 @@
+package results
+
+import "strings"
+
+// Metric 是可用于跨运行比较的单个数值指标
+type Metric struct {
+	Section        Section `json:"section"`
+	Item           string  `json:"item"`
+	Name           string  `json:"name"`
+	Unit           string  `json:"unit"`
+	Value          float64 `json:"value"`
+	HigherIsBetter bool    `json:"higher_is_better"`
+}
+
+// Key 返回用于在不同运行之间对齐指标的键
+func (m Metric) Key() string {
+	return string(m.Section) + "|" + m.Item + "|" + m.Name
+}
+
+// Metrics 提取报告中可比较的数值指标（CPU 得分、内存带宽、硬盘速度与 IOPS、网络吞吐与延迟、GPU 基准）
+func Metrics(report *Report) []Metric {
+	if report == nil {
+		return nil
+	}
+	var metrics []Metric
+	for _, s := range report.CPU {
+		metrics = append(metrics, Metric{Section: SectionCPU, Item: s.Label, Name: "score", Value: s.Score, HigherIsBetter: true})
+	}
+	for _, m := range report.Memory {
+		metrics = append(metrics, Metric{Section: SectionMemory, Item: m.Label, Name: "bandwidth", Unit: "MB/s", Value: m.MBps, HigherIsBetter: true})
+	}
+	for _, d := range report.Disk {
+		item := strings.TrimSpace(d.Path + " " + d.Block)
+		metrics = append(metrics,
+			Metric{Section: SectionDisk, Item: item, Name: "read", Unit: "MB/s", Value: d.Read.MBps, HigherIsBetter: true},
+			Metric{Section: SectionDisk, Item: item, Name: "write", Unit: "MB/s", Value: d.Write.MBps, HigherIsBetter: true},
+		)
+		if d.Total.IOPS > 0 {
+			metrics = append(metrics, Metric{Section: SectionDisk, Item: item, Name: "iops", Unit: "IOPS", Value: d.Total.IOPS, HigherIsBetter: true})
+		}
+	}
+	for _, s := range report.Speed {
+		metrics = append(metrics,
+			Metric{Section: SectionSpeed, Item: s.Node, Name: "download", Unit: "Mbps", Value: s.DownloadMbps, HigherIsBetter: true},
+			Metric{Section: SectionSpeed, Item: s.Node, Name: "upload", Unit: "Mbps", Value: s.UploadMbps, HigherIsBetter: true},
+			Metric{Section: SectionSpeed, Item: s.Node, Name: "latency", Unit: "ms", Value: s.LatencyMs},
+		)
+	}
+	for _, b := range report.GPUBench {
+		metrics = append(metrics, Metric{Section: SectionGPU, Item: b.Device, Name: "sha256", Unit: "H/s", Value: b.HashesPerSec, HigherIsBetter: true})
+	}
+	return metrics
+}
+
+// ComparisonValue 是某次运行中一个指标的取值，OK 为 false 表示该运行缺少此指标
+type ComparisonValue struct {
+	Value float64
+	OK    bool
+}
+
+// ComparisonRow 是比较表中的一行，Values 与传入的报告一一对应
+type ComparisonRow struct {
+	Section        Section
+	Item           string
+	Name           string
+	Unit           string
+	HigherIsBetter bool
+	Values         []ComparisonValue
+}
+
+// Delta 返回第 i 次运行相对第一次运行的百分比变化
+func (r ComparisonRow) Delta(i int) (float64, bool) {
+	if i <= 0 || i >= len(r.Values) || !r.Values[0].OK || !r.Values[i].OK || r.Values[0].Value == 0 {
+		return 0, false
+	}
+	return (r.Values[i].Value - r.Values[0].Value) / r.Values[0].Value * 100, true
+}
+
+// Regressed 判断第 i 次运行相对基准是否变差超过 threshold 百分比
+func (r ComparisonRow) Regressed(i int, threshold float64) bool {
+	delta, ok := r.Delta(i)
+	if !ok {
+		return false
+	}
+	if r.HigherIsBetter {
+		return delta < -threshold
+	}
+	return delta > threshold
+}
+
+// Improved 判断第 i 次运行相对基准是否变好超过 threshold 百分比
+func (r ComparisonRow) Improved(i int, threshold float64) bool {
+	delta, ok := r.Delta(i)
+	if !ok {
+		return false
+	}
+	if r.HigherIsBetter {
+		return delta > threshold
+	}
+	return delta < -threshold
+}
+
+// Regressions 返回 current 相对 baseline 变差超过 threshold 百分比的指标
+func Regressions(baseline, current *Report, threshold float64) []ComparisonRow {
+	var rows []ComparisonRow
+	for _, row := range Compare(baseline, current) {
+		if row.Regressed(1, threshold) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// Compare 按指标对齐多个报告，第一个报告作为比较基准；行顺序按指标首次出现的顺序
+func Compare(reports ...*Report) []ComparisonRow {
+	metrics := make([][]Metric, len(reports))
+	for i, report := range reports {
+		metrics[i] = Metrics(report)
+	}
+	return compareMetrics(metrics)
+}
+
+// compareMetrics 按键对齐每次运行的指标，metrics[i] 为第 i 次运行的指标
+func compareMetrics(metrics [][]Metric) []ComparisonRow {
+	var rows []ComparisonRow
+	index := map[string]int{}
+	for i, list := range metrics {
+		for _, metric := range list {
+			pos, ok := index[metric.Key()]
+			if !ok {
+				pos = len(rows)
+				index[metric.Key()] = pos
+				rows = append(rows, ComparisonRow{
+					Section:        metric.Section,
+					Item:           metric.Item,
+					Name:           metric.Name,
+					Unit:           metric.Unit,
+					HigherIsBetter: metric.HigherIsBetter,
+					Values:         make([]ComparisonValue, len(metrics)),
+				})
+			}
+			rows[pos].Values[i] = ComparisonValue{Value: metric.Value, OK: true}
+		}
+	}
+	return rows
+}
+
+// ValueMetrics 返回报告按月费 monthlyCost 折算的性价比指标：每单位月费的 CPU 得分、硬盘读写 GB/s 与最高下载 Mbps；月费未知时返回 nil
+func ValueMetrics(report *Report, monthlyCost float64) []Metric {
+	if report == nil || monthlyCost <= 0 {
+		return nil
+	}
+	var metrics []Metric
+	for _, metric := range Metrics(report) {
+		switch {
+		case metric.Section == SectionCPU:
+			metric.Value /= monthlyCost
+		case metric.Section == SectionDisk && (metric.Name == "read" || metric.Name == "write"):
+			metric.Unit, metric.Value = "GB/s", metric.Value/1000/monthlyCost
+		default:
+			continue
+		}
+		metric.Name += "_per_cost"
+		metrics = append(metrics, metric)
+	}
+	// 各次运行的测速节点往往不同，只比较最高下载速度
+	best := 0.0
+	for _, s := range report.Speed {
+		best = max(best, s.DownloadMbps)
+	}
+	if best > 0 {
+		metrics = append(metrics, Metric{Section: SectionSpeed, Name: "download_per_cost", Unit: "Mbps", Value: best / monthlyCost, HigherIsBetter: true})
+	}
+	return metrics
+}
+
+// CompareValue 按月费对齐多次运行的性价比指标，costs 与 reports 一一对应；所有运行都没有月费时返回 nil
+func CompareValue(reports []*Report, costs []float64) []ComparisonRow {
+	metrics := make([][]Metric, len(reports))
+	found := false
+	for i, report := range reports {
+		if i < len(costs) {
+			metrics[i] = ValueMetrics(report, costs[i])
+			found = found || len(metrics[i]) > 0
+		}
+	}
+	if !found {
+		return nil
+	}
+	return compareMetrics(metrics)
+}
+
+// Best 返回该行中取值最好的运行下标，没有取值时返回 -1
+func (r ComparisonRow) Best() int {
+	best := -1
+	for i, value := range r.Values {
+		if !value.OK {
+			continue
+		}
+		if best < 0 || (r.HigherIsBetter && value.Value > r.Values[best].Value) || (!r.HigherIsBetter && value.Value < r.Values[best].Value) {
+			best = i
+		}
+	}
+	return best
+}
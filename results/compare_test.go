@@ -0,0 +1,137 @@
+package results
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareAlignsMetricsAndComputesDeltas(t *testing.T) {
+	base := &Report{
+		CPU:   []CPUScore{{Label: "1 线程测试(单核)得分", Score: 1000}},
+		Speed: []SpeedResult{{Node: "Speedtest.net", DownloadMbps: 1000, UploadMbps: 500, LatencyMs: 10}},
+	}
+	other := &Report{
+		CPU:   []CPUScore{{Label: "1 线程测试(单核)得分", Score: 800}},
+		Speed: []SpeedResult{{Node: "Speedtest.net", DownloadMbps: 1200, UploadMbps: 500, LatencyMs: 20}},
+		Disk:  []DiskResult{{Path: "/root", Block: "4k", Read: DiskMetric{MBps: 90}}},
+	}
+
+	rows := Compare(base, other)
+	byKey := map[string]ComparisonRow{}
+	for _, row := range rows {
+		byKey[string(row.Section)+"|"+row.Name] = row
+	}
+
+	cpu := byKey["cpu|score"]
+	if delta, ok := cpu.Delta(1); !ok || math.Abs(delta+20) > 1e-9 || !cpu.Regressed(1, 5) {
+		t.Fatalf("cpu delta = %v, %v; row = %#v", delta, ok, cpu)
+	}
+	download := byKey["speed|download"]
+	if !download.Improved(1, 5) || download.Regressed(1, 5) {
+		t.Fatalf("download row = %#v", download)
+	}
+	latency := byKey["speed|latency"]
+	if !latency.Regressed(1, 5) {
+		t.Fatalf("higher latency should be a regression: %#v", latency)
+	}
+	read := byKey["disk|read"]
+	if read.Values[0].OK || !read.Values[1].OK {
+		t.Fatalf("disk row should be missing from baseline: %#v", read)
+	}
+	if _, ok := read.Delta(1); ok {
+		t.Fatal("delta should be unavailable when baseline is missing")
+	}
+}
+
+func TestRegressionsFlagDiskIOPS(t *testing.T) {
+	base := &Report{Disk: []DiskResult{{Path: "/root", Block: "4k", Read: DiskMetric{MBps: 100}, Total: DiskMetric{IOPS: 50000}}}}
+	current := &Report{Disk: []DiskResult{{Path: "/root", Block: "4k", Read: DiskMetric{MBps: 95}, Total: DiskMetric{IOPS: 35000}}}}
+	rows := Regressions(base, current, 20)
+	if len(rows) != 1 || rows[0].Name != "iops" || rows[0].Unit != "IOPS" {
+		t.Fatalf("Regressions() = %#v, want only the IOPS drop", rows)
+	}
+	if rows := Regressions(base, current, 40); len(rows) != 0 {
+		t.Fatalf("Regressions(40%%) = %#v", rows)
+	}
+}
+
+func TestSummarizePicksHeadlineMetrics(t *testing.T) {
+	report := &Report{
+		CPU:    []CPUScore{{Label: "single", Score: 1200}, {Label: "multi", Score: 4000}},
+		Memory: []MemoryResult{{Label: "read", MBps: 30000}},
+		Disk:   []DiskResult{{Block: "4k", Read: DiskMetric{MBps: 80}, Write: DiskMetric{MBps: 60}}},
+		Speed:  []SpeedResult{{DownloadMbps: 900, UploadMbps: 100}, {DownloadMbps: 300, UploadMbps: 800}},
+	}
+	got := Summarize(report)
+	want := Headline{CPUScore: 1200, MemoryMBps: 30000, DiskReadMBps: 80, DiskWriteMBps: 60, DownloadMbps: 900, UploadMbps: 800}
+	if got != want {
+		t.Fatalf("Summarize() = %#v, want %#v", got, want)
+	}
+	if (Summarize(nil) != Headline{}) {
+		t.Fatal("Summarize(nil) should be empty")
+	}
+}
+
+func TestTrendValue(t *testing.T) {
+	report := Parse(sampleOutput)
+	cases := map[TrendMetric]float64{TrendCPU: 1234, TrendDiskIOPS: 45300, TrendDownload: 9032.29, TrendUpload: 8975.45}
+	for metric, want := range cases {
+		if got, ok := TrendValue(report, metric); !ok || got != want {
+			t.Errorf("TrendValue(%s) = %v, %v; want %v", metric, got, ok, want)
+		}
+	}
+	if _, ok := TrendValue(&Report{}, TrendCPU); ok {
+		t.Error("TrendValue() reported a missing metric")
+	}
+}
+
+func TestAnomalies(t *testing.T) {
+	flags := Anomalies([]float64{1000, 1010, 990, 1005, 620, 1002})
+	for i, want := range []bool{false, false, false, false, true, false} {
+		if flags[i] != want {
+			t.Fatalf("Anomalies() = %v", flags)
+		}
+	}
+	// 非常稳定的序列中 3% 的波动不算异常
+	for _, flagged := range Anomalies([]float64{100, 100, 100, 103, 100}) {
+		if flagged {
+			t.Fatal("small deviation in a flat series flagged")
+		}
+	}
+	if flags := Anomalies([]float64{1, 100, 1}); flags[1] {
+		t.Fatal("series shorter than the minimum should not be flagged")
+	}
+}
+
+func TestCompareValueRanksByMonthlyCost(t *testing.T) {
+	cheap := &Report{
+		CPU:   []CPUScore{{Label: "multi", Score: 4000}},
+		Disk:  []DiskResult{{Path: "/root", Block: "1m", Read: DiskMetric{MBps: 2000}, Write: DiskMetric{MBps: 1000}, Total: DiskMetric{IOPS: 9000}}},
+		Speed: []SpeedResult{{Node: "a", DownloadMbps: 500}, {Node: "b", DownloadMbps: 900}},
+	}
+	pricey := &Report{
+		CPU:   []CPUScore{{Label: "multi", Score: 6000}},
+		Speed: []SpeedResult{{Node: "c", DownloadMbps: 2000}},
+	}
+	if rows := CompareValue([]*Report{cheap, pricey}, []float64{0, 0}); rows != nil {
+		t.Fatalf("rows without costs = %#v", rows)
+	}
+	rows := CompareValue([]*Report{cheap, pricey, cheap}, []float64{4, 20})
+	byKey := map[string]ComparisonRow{}
+	for _, row := range rows {
+		byKey[string(row.Section)+"|"+row.Name] = row
+	}
+	if len(rows) != 4 || byKey["disk|iops_per_cost"].Values != nil {
+		t.Fatalf("rows = %#v", rows)
+	}
+	cpu := byKey["cpu|score_per_cost"]
+	if cpu.Values[0].Value != 1000 || cpu.Values[1].Value != 300 || cpu.Values[2].OK || cpu.Best() != 0 {
+		t.Fatalf("cpu row = %#v", cpu)
+	}
+	if read := byKey["disk|read_per_cost"]; read.Unit != "GB/s" || read.Values[0].Value != 0.5 || read.Values[1].OK {
+		t.Fatalf("read row = %#v", read)
+	}
+	if download := byKey["speed|download_per_cost"]; download.Values[0].Value != 225 || download.Values[1].Value != 100 || download.Best() != 0 {
+		t.Fatalf("download row = %#v", download)
+	}
+}
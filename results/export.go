@@ -0,0 +1,369 @@
+package results
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/width"
+)
+
+// Format 是结构化结果的导出格式
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+	FormatPDF      Format = "pdf"
+)
+
+// Extension 返回导出格式对应的文件扩展名
+func (f Format) Extension() string {
+	switch f {
+	case FormatJSON:
+		return ".json"
+	case FormatCSV:
+		return ".csv"
+	case FormatHTML:
+		return ".html"
+	case FormatPDF:
+		return ".pdf"
+	}
+	return ".md"
+}
+
+// Encode 将结果序列化为指定格式
+func Encode(report *Report, format Format) ([]byte, error) {
+	if report == nil {
+		report = &Report{}
+	}
+	switch format {
+	case FormatJSON:
+		return EncodeJSON(report)
+	case FormatCSV:
+		return EncodeCSV(report)
+	case FormatMarkdown:
+		return []byte(EncodeMarkdown(report)), nil
+	case FormatHTML:
+		return EncodeHTML(report, HTMLOptions{})
+	case FormatPDF:
+		return EncodePDF(report, PDFOptions{})
+	}
+	return nil, fmt.Errorf("unsupported export format %q", format)
+}
+
+// EncodeJSON 以缩进 JSON 输出
+func EncodeJSON(report *Report) ([]byte, error) {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// csvRecord 是扁平化后的一条记录：分区、项目、指标、数值、单位
+type csvRecord [5]string
+
+func flattenReport(report *Report) []csvRecord {
+	var records []csvRecord
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+	for _, s := range report.CPU {
+		records = append(records, csvRecord{string(SectionCPU), s.Label, "score", num(s.Score), ""})
+	}
+	for _, m := range report.Memory {
+		records = append(records, csvRecord{string(SectionMemory), m.Label, "bandwidth", num(m.MBps), "MB/s"})
+	}
+	for _, d := range report.Disk {
+		item := strings.TrimSpace(d.Path + " " + d.Block)
+		for _, metric := range []struct {
+			name string
+			m    DiskMetric
+		}{{"read", d.Read}, {"write", d.Write}, {"total", d.Total}} {
+			records = append(records,
+				csvRecord{string(SectionDisk), item, metric.name + "_speed", num(metric.m.MBps), "MB/s"},
+				csvRecord{string(SectionDisk), item, metric.name + "_iops", num(metric.m.IOPS), "IOPS"},
+			)
+		}
+	}
+	for _, s := range report.Speed {
+		records = append(records,
+			csvRecord{string(SectionSpeed), s.Node, "upload", num(s.UploadMbps), "Mbps"},
+			csvRecord{string(SectionSpeed), s.Node, "download", num(s.DownloadMbps), "Mbps"},
+			csvRecord{string(SectionSpeed), s.Node, "latency", num(s.LatencyMs), "ms"},
+		)
+		if s.PacketLoss != "" {
+			records = append(records, csvRecord{string(SectionSpeed), s.Node, "packet_loss", s.PacketLoss, ""})
+		}
+	}
+	for _, f := range report.IPQuality {
+		records = append(records, csvRecord{string(SectionIPQuality), f.Name, "value", f.Value, ""})
+	}
+	for _, u := range report.Unlock {
+		records = append(records, csvRecord{string(SectionUnlock), u.Platform, "status", u.Status, ""})
+		if u.Region != "" {
+			records = append(records, csvRecord{string(SectionUnlock), u.Platform, "region", u.Region, ""})
+		}
+	}
+	for _, r := range report.Backtrace {
+		for _, line := range r.Lines {
+			records = append(records, csvRecord{string(SectionBacktrace), r.Destination, string(line.Tier), line.Name, ""})
+		}
+	}
+	for _, l := range report.Latency {
+		item := l.Target + " (" + l.Protocol + ")"
+		records = append(records,
+			csvRecord{string(SectionLatency), item, "min", num(l.MinMs), "ms"},
+			csvRecord{string(SectionLatency), item, "avg", num(l.AvgMs), "ms"},
+			csvRecord{string(SectionLatency), item, "max", num(l.MaxMs), "ms"},
+			csvRecord{string(SectionLatency), item, "loss", num(l.LossPercent()), "%"},
+		)
+	}
+	if scan := report.PortScan; scan != nil {
+		if scan.ICMP != nil {
+			records = append(records, csvRecord{string(SectionPorts), scan.Target, "icmp_loss", num(scan.ICMP.LossPercent()), "%"})
+		}
+		for _, p := range scan.Ports {
+			records = append(records, csvRecord{string(SectionPorts), scan.Target + ":" + strconv.Itoa(p.Port), "status", p.Status, ""})
+		}
+	}
+	if monitor := report.Monitor; monitor != nil {
+		for _, h := range monitor.Hops {
+			item := strconv.Itoa(h.Hop) + " " + h.Host
+			if h.Host == "" {
+				item += "???"
+			}
+			records = append(records,
+				csvRecord{string(SectionMonitor), item, "loss", num(h.LossPercent()), "%"},
+				csvRecord{string(SectionMonitor), item, "avg", num(h.AvgMs), "ms"},
+				csvRecord{string(SectionMonitor), item, "worst", num(h.WorstMs), "ms"},
+			)
+		}
+	}
+	for _, g := range report.GPU {
+		records = append(records, csvRecord{string(SectionGPU), g.Model, "memory", g.Memory, ""}, csvRecord{string(SectionGPU), g.Model, "driver", g.Driver, ""})
+	}
+	for _, b := range report.GPUBench {
+		records = append(records, csvRecord{string(SectionGPU), b.Device, "sha256", num(b.HashesPerSec), "H/s"})
+	}
+	return records
+}
+
+// EncodeCSV 以 section,item,metric,value,unit 五列输出，便于表格软件筛选
+func EncodeCSV(report *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write([]string{"section", "item", "metric", "value", "unit"}); err != nil {
+		return nil, err
+	}
+	for _, record := range flattenReport(report) {
+		if err := w.Write(record[:]); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+// resultTable 是导出为表格的一个分区
+type resultTable struct {
+	title   string
+	headers []string
+	rows    [][]string
+}
+
+// resultTables 把报告整理为 Markdown 与文本导出共用的表格，空分区会被跳过
+func resultTables(report *Report) []resultTable {
+	var tables []resultTable
+	num := func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) }
+	if len(report.CPU) > 0 {
+		rows := make([][]string, 0, len(report.CPU))
+		for _, s := range report.CPU {
+			rows = append(rows, []string{s.Label, num(s.Score)})
+		}
+		tables = append(tables, resultTable{"CPU", []string{"Item", "Score"}, rows})
+	}
+	if len(report.Memory) > 0 {
+		rows := make([][]string, 0, len(report.Memory))
+		for _, m := range report.Memory {
+			rows = append(rows, []string{m.Label, num(m.MBps) + " MB/s"})
+		}
+		tables = append(tables, resultTable{"Memory", []string{"Item", "Bandwidth"}, rows})
+	}
+	if len(report.Disk) > 0 {
+		metric := func(m DiskMetric) string {
+			return fmt.Sprintf("%s MB/s (%s IOPS)", num(m.MBps), strconv.FormatFloat(m.IOPS, 'f', 0, 64))
+		}
+		rows := make([][]string, 0, len(report.Disk))
+		for _, d := range report.Disk {
+			rows = append(rows, []string{d.Path, d.Block, metric(d.Read), metric(d.Write), metric(d.Total)})
+		}
+		title := "Disk"
+		if report.DiskShrunkFrom != "" {
+			title += fmt.Sprintf(" (test file %s, shrunk from %s for low free space)", report.DiskFileSize, report.DiskShrunkFrom)
+		} else if report.DiskFileSize != "" {
+			title += fmt.Sprintf(" (test file %s)", report.DiskFileSize)
+		}
+		tables = append(tables, resultTable{title, []string{"Path", "Block", "Read", "Write", "Total"}, rows})
+	}
+	if len(report.Speed) > 0 {
+		rows := make([][]string, 0, len(report.Speed))
+		for _, s := range report.Speed {
+			rows = append(rows, []string{s.Node, num(s.UploadMbps) + " Mbps", num(s.DownloadMbps) + " Mbps", num(s.LatencyMs) + " ms", s.PacketLoss})
+		}
+		tables = append(tables, resultTable{"Speed", []string{"Node", "Upload", "Download", "Latency", "Loss"}, rows})
+	}
+	if len(report.IPQuality) > 0 {
+		rows := make([][]string, 0, len(report.IPQuality))
+		for _, f := range report.IPQuality {
+			rows = append(rows, []string{f.Name, f.Value})
+		}
+		tables = append(tables, resultTable{"IP Quality", []string{"Field", "Value"}, rows})
+	}
+	if len(report.Unlock) > 0 {
+		rows := make([][]string, 0, len(report.Unlock))
+		for _, u := range report.Unlock {
+			rows = append(rows, []string{u.Platform, u.Status, u.Region})
+		}
+		tables = append(tables, resultTable{"Unlock", []string{"Platform", "Status", "Region"}, rows})
+	}
+	if len(report.Backtrace) > 0 {
+		rows := make([][]string, 0, len(report.Backtrace))
+		for _, r := range report.Backtrace {
+			names := make([]string, 0, len(r.Lines))
+			for _, line := range r.Lines {
+				names = append(names, line.Name)
+			}
+			if len(names) == 0 {
+				names = append(names, r.Error)
+			}
+			rows = append(rows, []string{r.Destination, r.Target, strings.Join(names, " / ")})
+		}
+		tables = append(tables, resultTable{"Backtrace", []string{"Destination", "Target", "Routes"}, rows})
+	}
+	if len(report.Latency) > 0 {
+		rows := make([][]string, 0, len(report.Latency))
+		for _, l := range report.Latency {
+			row := []string{l.Target, strings.ToUpper(l.Protocol), num(l.MinMs), num(l.AvgMs), num(l.MaxMs), strconv.FormatFloat(l.LossPercent(), 'f', 1, 64) + "%"}
+			if l.Received == 0 {
+				row[2], row[3], row[4] = "-", "-", "-"
+			}
+			rows = append(rows, row)
+		}
+		tables = append(tables, resultTable{"Latency", []string{"Target", "Protocol", "Min (ms)", "Avg (ms)", "Max (ms)", "Loss"}, rows})
+	}
+	if scan := report.PortScan; scan != nil {
+		rows := make([][]string, 0, len(scan.Ports)+1)
+		if scan.ICMP != nil {
+			status := "reachable"
+			if scan.ICMP.Received == 0 {
+				status = "unreachable"
+			}
+			rows = append(rows, []string{"ICMP", "", status, ""})
+		}
+		for _, p := range scan.Ports {
+			latency := "-"
+			if p.Status == PortOpen {
+				latency = num(p.LatencyMs)
+			}
+			rows = append(rows, []string{strconv.Itoa(p.Port), p.Service, p.Status, latency})
+		}
+		tables = append(tables, resultTable{"Ports (" + scan.Target + ")", []string{"Port", "Service", "Status", "Connect (ms)"}, rows})
+	}
+	if monitor := report.Monitor; monitor != nil {
+		rows := make([][]string, 0, len(monitor.Hops))
+		for _, h := range monitor.Hops {
+			host, last, avg, best, worst := h.Host, "-", "-", "-", "-"
+			if host == "" {
+				host = "???"
+			}
+			if h.Received > 0 {
+				last, avg, best, worst = num(h.LastMs), num(h.AvgMs), num(h.BestMs), num(h.WorstMs)
+			}
+			rows = append(rows, []string{strconv.Itoa(h.Hop), host, fmt.Sprintf("%.1f%%", h.LossPercent()), strconv.Itoa(h.Sent), last, avg, best, worst})
+		}
+		title := fmt.Sprintf("Route monitor (%s, %d rounds)", monitor.Target, monitor.Rounds)
+		tables = append(tables, resultTable{title, []string{"Hop", "Host", "Loss", "Sent", "Last (ms)", "Avg (ms)", "Best (ms)", "Worst (ms)"}, rows})
+	}
+	if len(report.Annotations) > 0 {
+		rows := make([][]string, 0, len(report.Annotations))
+		for _, a := range report.Annotations {
+			rows = append(rows, []string{strconv.Itoa(a.Line), strings.TrimSpace(a.Text), a.Note})
+		}
+		tables = append(tables, resultTable{"Notes", []string{"Line", "Output", "Note"}, rows})
+	}
+	return tables
+}
+
+// EncodeMarkdown 输出适合直接贴到论坛的 Markdown 表格
+func EncodeMarkdown(report *Report) string {
+	var b strings.Builder
+	b.WriteString("# GOECS Result\n")
+	for _, table := range resultTables(report) {
+		writeMarkdownTable(&b, table.title, table.headers, table.rows)
+	}
+	return b.String()
+}
+
+// EncodeText 输出按显示宽度对齐的纯文本表格，用于等宽字体下的展示与截图
+func EncodeText(report *Report) string {
+	var b strings.Builder
+	b.WriteString("GOECS Result\n")
+	for _, table := range resultTables(report) {
+		fmt.Fprintf(&b, "\n== %s ==\n", table.title)
+		widths := make([]int, len(table.headers))
+		for _, row := range append([][]string{table.headers}, table.rows...) {
+			for i, cell := range row {
+				if i < len(widths) {
+					widths[i] = max(widths[i], displayWidth(cell))
+				}
+			}
+		}
+		for _, row := range append([][]string{table.headers}, table.rows...) {
+			var line strings.Builder
+			for i, cell := range row {
+				if i >= len(widths) {
+					break
+				}
+				line.WriteString(cell)
+				if i < len(row)-1 {
+					line.WriteString(strings.Repeat(" ", widths[i]-displayWidth(cell)+2))
+				}
+			}
+			b.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+		}
+	}
+	return b.String()
+}
+
+// displayWidth 返回等宽字体下的显示列数，全角字符按 2 列计
+func displayWidth(s string) int {
+	cols := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			cols += 2
+		default:
+			cols++
+		}
+	}
+	return cols
+}
+
+func writeMarkdownTable(b *strings.Builder, title string, headers []string, rows [][]string) {
+	escape := func(s string) string { return strings.ReplaceAll(s, "|", `\|`) }
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	b.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(headers)) + "\n")
+	for _, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = escape(cell)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
@@ -0,0 +1,256 @@
+package results
+
+import (
+	"compress/zlib"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestEncodeFormats(t *testing.T) {
+	report := Parse(sampleOutput)
+
+	data, err := Encode(report, FormatJSON)
+	if err != nil {
+		t.Fatalf("Encode(json) error = %v", err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("json round trip: %v", err)
+	}
+	if len(decoded.Speed) != 1 || decoded.Speed[0].Node != "Speedtest.net" {
+		t.Fatalf("decoded speed = %#v", decoded.Speed)
+	}
+
+	data, err = Encode(report, FormatCSV)
+	if err != nil {
+		t.Fatalf("Encode(csv) error = %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	if err != nil {
+		t.Fatalf("csv parse: %v", err)
+	}
+	if records[0][0] != "section" || len(records) < 10 {
+		t.Fatalf("csv records = %v", records)
+	}
+
+	data, err = Encode(report, FormatMarkdown)
+	if err != nil {
+		t.Fatalf("Encode(markdown) error = %v", err)
+	}
+	md := string(data)
+	for _, want := range []string{"# GOECS Result", "## CPU", "| Speedtest.net | 8975.45 Mbps |", "## Unlock"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestEncodeRejectsUnknownFormat(t *testing.T) {
+	if _, err := Encode(&Report{}, Format("xml")); err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+}
+
+func TestEncodeLatency(t *testing.T) {
+	report := &Report{Latency: []LatencyResult{
+		{Target: "1.1.1.1", Protocol: "icmp", Sent: 4, Received: 3, MinMs: 1.2, AvgMs: 2.5, MaxMs: 4},
+		{Target: "example.com:443", Protocol: "tcp", Sent: 4, Error: "connection refused"},
+	}}
+	if report.Empty() {
+		t.Fatal("report with latency results is empty")
+	}
+	md := EncodeMarkdown(report)
+	for _, want := range []string{"## Latency", "| 1.1.1.1 | ICMP | 1.20 | 2.50 | 4.00 | 25.0% |", "| example.com:443 | TCP | - | - | - | 100.0% |"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	data, err := EncodeCSV(report)
+	if err != nil || !strings.Contains(string(data), "latency,1.1.1.1 (icmp),loss,25,%") {
+		t.Fatalf("csv = %s, %v", data, err)
+	}
+}
+
+func TestEncodePortScan(t *testing.T) {
+	report := &Report{PortScan: &PortScan{
+		Target: "203.0.113.7",
+		ICMP:   &LatencyResult{Target: "203.0.113.7", Protocol: "icmp", Sent: 3},
+		Ports: []PortResult{
+			{Port: 22, Service: "ssh", Status: PortOpen, LatencyMs: 12.5},
+			{Port: 25, Service: "smtp", Status: PortFiltered, Error: "i/o timeout"},
+		},
+	}}
+	if report.Empty() {
+		t.Fatal("report with a port scan is empty")
+	}
+	if blocked := report.PortScan.Blocked(); len(blocked) != 1 || blocked[0] != 25 {
+		t.Fatalf("Blocked() = %v", blocked)
+	}
+	md := EncodeMarkdown(report)
+	for _, want := range []string{"## Ports (203.0.113.7)", "| ICMP |  | unreachable |  |", "| 22 | ssh | open | 12.50 |", "| 25 | smtp | filtered | - |"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	data, err := EncodeCSV(report)
+	if err != nil || !strings.Contains(string(data), "ports,203.0.113.7:25,status,filtered,") || !strings.Contains(string(data), "ports,203.0.113.7,icmp_loss,100,%") {
+		t.Fatalf("csv = %s, %v", data, err)
+	}
+}
+
+func TestEncodeRouteMonitor(t *testing.T) {
+	hop := MonitorHop{Hop: 3, Host: "172.16.0.1"}
+	for _, rtt := range []float64{10, 30, 20} {
+		hop.Record(rtt, true)
+	}
+	hop.Record(0, false)
+	if hop.BestMs != 10 || hop.WorstMs != 30 || hop.AvgMs != 20 || hop.LastMs != 20 || hop.LossPercent() != 25 {
+		t.Fatalf("hop = %+v", hop)
+	}
+	report := &Report{Monitor: &RouteMonitor{Target: "203.0.113.7", Rounds: 4, Hops: []MonitorHop{{Hop: 1, Sent: 4}, hop}}}
+	if report.Empty() {
+		t.Fatal("report with a route monitor is empty")
+	}
+	md := EncodeMarkdown(report)
+	for _, want := range []string{"## Route monitor (203.0.113.7, 4 rounds)", "| 1 | ??? | 100.0% | 4 | - | - | - | - |", "| 3 | 172.16.0.1 | 25.0% | 4 | 20.00 | 20.00 | 10.00 | 30.00 |"} {
+		if !strings.Contains(md, want) {
+			t.Fatalf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	data, err := EncodeCSV(report)
+	if err != nil || !strings.Contains(string(data), "monitor,3 172.16.0.1,loss,25,%") {
+		t.Fatalf("csv = %s, %v", data, err)
+	}
+}
+
+func TestEncodeAnnotations(t *testing.T) {
+	report := &Report{
+		CPU:         []CPUScore{{Label: "1 thread", Score: 1000}},
+		Annotations: []Annotation{{Line: 42, Text: "  上海电信 10.5 Mbps", Note: "this node looks throttled | retry"}},
+	}
+	md := EncodeMarkdown(report)
+	if !strings.Contains(md, "## Notes") || !strings.Contains(md, "| 42 | 上海电信 10.5 Mbps | this node looks throttled \\| retry |") {
+		t.Fatalf("markdown missing annotation:\n%s", md)
+	}
+	data, err := EncodeHTML(report, HTMLOptions{})
+	if err != nil || !strings.Contains(string(data), "this node looks throttled") {
+		t.Fatalf("html missing annotation: %v", err)
+	}
+}
+
+func TestEncodeTextAlignsColumns(t *testing.T) {
+	report := &Report{Unlock: []UnlockResult{{Platform: "Netflix", Status: "解锁", Region: "US"}, {Platform: "TikTok", Status: "No", Region: "-"}}}
+	want := "GOECS Result\n\n== Unlock ==\n" +
+		"Platform  Status  Region\n" +
+		"Netflix   解锁    US\n" +
+		"TikTok    No      -\n"
+	if got := EncodeText(report); got != want {
+		t.Fatalf("EncodeText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestEncodeHTML(t *testing.T) {
+	report := Parse(sampleOutput)
+	report.System = append(report.System, InfoField{Name: "主机名", Value: "<vps>"})
+	data, err := EncodeHTML(report, HTMLOptions{Title: "vps-1", Log: "\x1b[32mCPU\x1b[0m 1234"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	html := string(data)
+	for _, want := range []string{"<title>vps-1</title>", "<h2>CPU</h2>", "<svg ", `class="s1"`, "&lt;vps&gt;", "<details>", "<pre>CPU 1234</pre>"} {
+		if !strings.Contains(html, want) {
+			t.Fatalf("html missing %q:\n%s", want, html)
+		}
+	}
+	if strings.Contains(html, "src=") || strings.Contains(html, "href=") || strings.Contains(html, "<script") {
+		t.Fatal("html report should be self-contained")
+	}
+}
+
+func TestEncodePDF(t *testing.T) {
+	old := systemPDFFonts
+	t.Cleanup(func() { systemPDFFonts = old })
+	systemPDFFonts = func() [][]byte { return nil }
+
+	report := Parse(sampleOutput)
+	report.System = append(report.System, InfoField{Name: "主机名", Value: "vps-1"})
+	for i := range 120 {
+		report.Speed = append(report.Speed, SpeedResult{Node: fmt.Sprintf("node-%d", i), DownloadMbps: float64(i)})
+	}
+	data, err := Encode(report, FormatPDF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf := string(data)
+	if !strings.HasPrefix(pdf, "%PDF-1.4") || !strings.HasSuffix(pdf, "%%EOF\n") {
+		t.Fatal("not a PDF file")
+	}
+	pages := strings.Count(pdf, "/Type /Page ")
+	if pages < 3 {
+		t.Fatalf("pages = %d, want the long speed table split across pages", pages)
+	}
+	if strings.Contains(pdf, "STSong") || !strings.Contains(pdf, "/Subtype /Type3") {
+		t.Fatal("text should use embedded Type3 glyphs instead of a reader-provided font")
+	}
+
+	// xref 中的每个偏移都应指向对应的对象
+	xref := pdf[strings.LastIndex(pdf, "\nxref\n")+1:]
+	lines := strings.Split(xref, "\n")
+	size, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i, line := range lines[3 : 2+size] {
+		offset, err := strconv.Atoi(strings.Fields(line)[0])
+		if err != nil || !strings.HasPrefix(pdf[offset:], fmt.Sprintf("%d 0 obj", i+1)) {
+			t.Fatalf("xref entry %d = %q does not point to its object", i+1, line)
+		}
+	}
+
+	var streams []string
+	for rest := pdf; ; {
+		i := strings.Index(rest, ">>\nstream\n")
+		if i < 0 {
+			break
+		}
+		rest = rest[i+len(">>\nstream\n"):]
+		zr, err := zlib.NewReader(strings.NewReader(rest))
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(zr)
+		streams = append(streams, string(content))
+	}
+	if first := streams[0]; !strings.Contains(first, "Tj") || !strings.Contains(first, " re f") {
+		t.Fatalf("first page content = %q", first)
+	}
+	all := strings.Join(streams, "\n")
+	// ToUnicode 把字形映射回字符，复制与搜索得到原文
+	for _, r := range "主机名vps-1CPU" {
+		if !strings.Contains(all, "> "+pdfHex(string(r))+"\n") {
+			t.Fatalf("no ToUnicode mapping for %q", r)
+		}
+	}
+}
+
+func TestPDFFontSetEmbedsOutlines(t *testing.T) {
+	set := newPDFFontSet([][]byte{[]byte("not a font")})
+	a := set.glyph('A')
+	if a.advance <= 0 || !strings.Contains(set.fonts[0][a.code].path, " l\n") {
+		t.Fatalf("glyph A = %+v, %q", a, set.fonts[0][a.code].path)
+	}
+	if set.glyph('A') != a {
+		t.Fatal("a glyph should be embedded once")
+	}
+	for r := rune(0x4E00); len(set.fonts) < 2; r++ {
+		set.glyph(r)
+	}
+	if len(set.fonts[0]) != pdfGlyphsPerFont {
+		t.Fatalf("first font has %d glyphs, want %d before starting another", len(set.fonts[0]), pdfGlyphsPerFont)
+	}
+	if show := set.show("A\u4e00", 8); !strings.Contains(show, "/F0 8 Tf <") {
+		t.Fatalf("show = %q", show)
+	}
+}
@@ -0,0 +1,46 @@
+package results
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/gpu"
+)
+
+// GPUInfo 是 GPU 信息阶段列出的一块显卡，Memory 与 Driver 保留输出中的原文
+type GPUInfo struct {
+	Model  string `json:"model"`
+	Memory string `json:"memory,omitempty"`
+	Driver string `json:"driver,omitempty"`
+}
+
+// GPUBench 是 hashcat 在一个计算设备上的基准成绩
+type GPUBench struct {
+	Device       string  `json:"device"`
+	Rate         string  `json:"rate"`
+	HashesPerSec float64 `json:"hashes_per_sec"`
+}
+
+var gpuIndexPattern = regexp.MustCompile(`^GPU\s*\d+$`)
+
+// parseGPULine 解析 GPU 信息阶段的输出："GPU 0: 型号" 开始一块显卡，其后的显存与驱动版本属于该显卡，
+// 以 hashcat 开头的行为基准成绩
+func parseGPULine(report *Report, line string) {
+	m := keyValuePattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	key, value := strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	switch {
+	case gpuIndexPattern.MatchString(key):
+		report.GPU = append(report.GPU, GPUInfo{Model: value})
+	case strings.HasPrefix(key, "hashcat"):
+		device := strings.TrimSpace(strings.TrimPrefix(key, "hashcat"))
+		report.GPUBench = append(report.GPUBench, GPUBench{Device: device, Rate: value, HashesPerSec: gpu.ParseRate(value)})
+	case len(report.GPU) == 0:
+	case key == "显存" || key == "VRAM":
+		report.GPU[len(report.GPU)-1].Memory = value
+	case key == "驱动版本" || key == "Driver":
+		report.GPU[len(report.GPU)-1].Driver = value
+	}
+}
@@ -0,0 +1,197 @@
+package results
+
+import (
+	"bytes"
+	_ "embed"
+	"html/template"
+	"strconv"
+	"time"
+)
+
+//go:embed report.html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("report").Parse(htmlTemplateText))
+
+// HTMLOptions 是 HTML 报告中结构化结果以外的内容
+type HTMLOptions struct {
+	// Title 为空时使用 "GOECS Result"
+	Title string
+	// Generated 为零值时使用当前时间
+	Generated time.Time
+	// Log 为原始输出，放在可折叠的区域中，为空时不显示
+	Log string
+}
+
+// 柱状图的尺寸（像素）
+const (
+	chartLabelWidth = 200
+	chartBarWidth   = 440
+	chartValueWidth = 110
+	chartBarHeight  = 16
+	chartRowGap     = 8
+)
+
+// htmlChart 是一个横向柱状图，每行有一到两个数值
+type htmlChart struct {
+	Title     string
+	Series    []string
+	Rows      []htmlChartRow
+	Width     int
+	Height    int
+	BarX      int
+	BarHeight int
+}
+
+type htmlChartRow struct {
+	Label  string
+	Y      int
+	Values []htmlChartValue
+}
+
+type htmlChartValue struct {
+	Text   string
+	Y      int
+	Width  float64
+	TextX  float64
+	Series int
+}
+
+type htmlSection struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+	Chart   *htmlChart
+}
+
+// EncodeHTML 生成自包含的 HTML 报告：系统信息、各分区表格、内嵌 SVG 柱状图以及可折叠的原始输出。
+// 报告不引用任何外部资源，可以直接归档或发送。
+func EncodeHTML(report *Report, opts HTMLOptions) ([]byte, error) {
+	if report == nil {
+		report = &Report{}
+	}
+	if opts.Title == "" {
+		opts.Title = "GOECS Result"
+	}
+	if opts.Generated.IsZero() {
+		opts.Generated = time.Now()
+	}
+	charts := htmlCharts(report)
+	var sections []htmlSection
+	for _, table := range resultTables(report) {
+		sections = append(sections, htmlSection{Title: table.title, Headers: table.headers, Rows: table.rows, Chart: charts[table.title]})
+	}
+	var headline *Headline
+	if h := Summarize(report); h != (Headline{}) {
+		headline = &h
+	}
+	data := struct {
+		Title     string
+		Generated string
+		System    []InfoField
+		Headline  *Headline
+		Sections  []htmlSection
+		Log       string
+	}{opts.Title, opts.Generated.Format("2006-01-02 15:04:05 MST"), report.System, headline, sections, StripANSI(opts.Log)}
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resultChart 是一个分区的柱状图数据，每个标签有一到两个数值，Series 为各数值的名称
+type resultChart struct {
+	series []string
+	labels []string
+	values [][]float64
+	unit   string
+}
+
+// resultCharts 整理有可比数值的分区，HTML 与 PDF 报告共用，键为分区标题
+func resultCharts(report *Report) map[string]resultChart {
+	charts := map[string]resultChart{}
+	add := func(title string, series []string, labels []string, values [][]float64, unit string) {
+		if len(labels) > 0 {
+			charts[title] = resultChart{series, labels, values, unit}
+		}
+	}
+	var labels []string
+	var values [][]float64
+	for _, s := range report.CPU {
+		labels, values = append(labels, s.Label), append(values, []float64{s.Score})
+	}
+	add("CPU", nil, labels, values, "")
+
+	labels, values = nil, nil
+	for _, m := range report.Memory {
+		labels, values = append(labels, m.Label), append(values, []float64{m.MBps})
+	}
+	add("Memory", nil, labels, values, " MB/s")
+
+	labels, values = nil, nil
+	for _, d := range report.Disk {
+		labels, values = append(labels, d.Path+" "+d.Block), append(values, []float64{d.Read.MBps, d.Write.MBps})
+	}
+	add("Disk", []string{"Read", "Write"}, labels, values, " MB/s")
+
+	labels, values = nil, nil
+	for _, s := range report.Speed {
+		labels, values = append(labels, s.Node), append(values, []float64{s.UploadMbps, s.DownloadMbps})
+	}
+	add("Speed", []string{"Upload", "Download"}, labels, values, " Mbps")
+
+	labels, values = nil, nil
+	for _, l := range report.Latency {
+		if l.Received > 0 {
+			labels, values = append(labels, l.Target), append(values, []float64{l.AvgMs})
+		}
+	}
+	add("Latency", nil, labels, values, " ms")
+	return charts
+}
+
+// htmlCharts 为有可比数值的分区生成柱状图，键为分区标题
+func htmlCharts(report *Report) map[string]*htmlChart {
+	charts := map[string]*htmlChart{}
+	for title, chart := range resultCharts(report) {
+		unit := chart.unit
+		charts[title] = newHTMLChart(title, chart.series, chart.labels, chart.values, func(v float64) string {
+			return strconv.FormatFloat(v, 'f', 2, 64) + unit
+		})
+	}
+	return charts
+}
+
+// newHTMLChart 按所有数值中的最大值缩放柱长
+func newHTMLChart(title string, series, labels []string, values [][]float64, format func(float64) string) *htmlChart {
+	peak := 0.0
+	for _, row := range values {
+		for _, v := range row {
+			peak = max(peak, v)
+		}
+	}
+	chart := &htmlChart{
+		Title:     title,
+		Series:    series,
+		Width:     chartLabelWidth + chartBarWidth + chartValueWidth,
+		BarX:      chartLabelWidth,
+		BarHeight: chartBarHeight - 2,
+	}
+	y := chartRowGap
+	for i, label := range labels {
+		row := htmlChartRow{Label: label, Y: y + chartBarHeight*len(values[i])/2 + 4}
+		for series, v := range values[i] {
+			width := 0.0
+			if peak > 0 {
+				width = v / peak * chartBarWidth
+			}
+			row.Values = append(row.Values, htmlChartValue{Text: format(v), Y: y, Width: width, TextX: chartLabelWidth + width + 6, Series: series})
+			y += chartBarHeight
+		}
+		chart.Rows = append(chart.Rows, row)
+		y += chartRowGap
+	}
+	chart.Height = y
+	return chart
+}
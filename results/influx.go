@@ -0,0 +1,40 @@
+package results
+
+import (
+	"bufio"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	influxMeasurementEscaper = strings.NewReplacer(",", `\,`, " ", `\ `)
+	influxTagEscaper         = strings.NewReplacer(",", `\,`, "=", `\=`, " ", `\ `)
+)
+
+// WriteInflux 以 InfluxDB 行协议写出各主机的指标：measurement 为指标名，host 与其他标签为 tag，
+// 数值写在 value 字段；Time 非零时附带秒级时间戳（写入时需使用 precision=s）
+func WriteInflux(w io.Writer, snapshots []Snapshot) error {
+	out := bufio.NewWriter(w)
+	for _, snapshot := range snapshots {
+		timestamp := ""
+		if !snapshot.Time.IsZero() {
+			timestamp = " " + strconv.FormatInt(snapshot.Time.Unix(), 10)
+		}
+		for _, gauge := range Gauges(snapshot.Report) {
+			tags := append([]Label{{"host", snapshot.Host}}, gauge.Labels...)
+			// 行协议建议按键排序 tag，空值的 tag 不允许写入
+			sort.SliceStable(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+			out.WriteString(influxMeasurementEscaper.Replace(gauge.Name))
+			for _, tag := range tags {
+				if tag.Value == "" {
+					continue
+				}
+				out.WriteString("," + tag.Name + "=" + influxTagEscaper.Replace(tag.Value))
+			}
+			out.WriteString(" value=" + strconv.FormatFloat(gauge.Value, 'g', -1, 64) + timestamp + "\n")
+		}
+	}
+	return out.Flush()
+}
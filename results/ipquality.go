@@ -0,0 +1,191 @@
+package results
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DatabaseScore 是单个数据库给出的分值
+type DatabaseScore struct {
+	Database string  `json:"database"`
+	Score    float64 `json:"score"`
+}
+
+// IPRiskScore 是一项 0-100 的评分在各数据库中的取值
+type IPRiskScore struct {
+	Name           string          `json:"name"`
+	HigherIsBetter bool            `json:"higher_is_better"`
+	Scores         []DatabaseScore `json:"scores"`
+}
+
+// IPBlacklist 汇总黑名单网站记录与 DNS 黑名单检查结果
+type IPBlacklist struct {
+	Harmless   int `json:"harmless"`
+	Malicious  int `json:"malicious"`
+	Suspicious int `json:"suspicious"`
+	NoRecord   int `json:"no_record"`
+	DNSTotal   int `json:"dns_total"`
+	DNSListed  int `json:"dns_listed"`
+}
+
+// IPQualitySummary 是 IP 质量检测的汇总
+type IPQualitySummary struct {
+	Scores      []IPRiskScore `json:"scores"`
+	Blacklist   IPBlacklist   `json:"blacklist"`
+	UsageType   string        `json:"usage_type,omitempty"`
+	CompanyType string        `json:"company_type,omitempty"`
+	ASN         string        `json:"asn,omitempty"`
+	IPType      string        `json:"ip_type,omitempty"`
+	// Points 为 0-100 的综合分，Grade 为对应的 A-F 等级
+	Points float64 `json:"points"`
+	Grade  string  `json:"grade"`
+}
+
+var (
+	// ipValueCodes 匹配 "值 [数据库编码...]"，如 "15 [E]"、"hosting [0 7 9 A]"
+	ipValueCodes    = regexp.MustCompile(`([^\[\]]+?)\s*\[([0-9A-Za-z ]+)\]`)
+	blacklistCounts = regexp.MustCompile(`(无害记录数|恶意记录数|可疑记录数|无记录数|Harmless\w*|Malicious\w*|Suspicious\w*|No\s*Records?)\s*[:：]\s*(\d+)`)
+	dnsCounts       = regexp.MustCompile(`(\d+)\s*\((Total_Check|Clean|Blacklisted|Other)\)`)
+)
+
+// AnalyzeIPQuality 汇总 IP 质量字段。同名字段（IPv4 与 IPv6 各一份）只取第一次出现的。
+func AnalyzeIPQuality(report *Report) IPQualitySummary {
+	var summary IPQualitySummary
+	if report == nil {
+		return summary
+	}
+	summary.ASN, summary.IPType = report.ASN, report.IPType
+	seen := map[string]bool{}
+	for _, field := range report.IPQuality {
+		line := field.Name + ": " + field.Value
+		for _, m := range blacklistCounts.FindAllStringSubmatch(line, -1) {
+			count, _ := strconv.Atoi(m[2])
+			switch key := strings.ToLower(m[1]); {
+			case key == "无害记录数" || strings.HasPrefix(key, "harmless"):
+				summary.Blacklist.Harmless = count
+			case key == "恶意记录数" || strings.HasPrefix(key, "malicious"):
+				summary.Blacklist.Malicious = count
+			case key == "可疑记录数" || strings.HasPrefix(key, "suspicious"):
+				summary.Blacklist.Suspicious = count
+			default:
+				summary.Blacklist.NoRecord = count
+			}
+		}
+		for _, m := range dnsCounts.FindAllStringSubmatch(field.Value, -1) {
+			count, _ := strconv.Atoi(m[1])
+			switch m[2] {
+			case "Total_Check":
+				summary.Blacklist.DNSTotal = count
+			case "Blacklisted":
+				summary.Blacklist.DNSListed = count
+			}
+		}
+		if seen[field.Name] {
+			continue
+		}
+		seen[field.Name] = true
+		name := strings.ToLower(field.Name)
+		switch {
+		case strings.Contains(name, "使用类型") || strings.Contains(name, "usage"):
+			summary.UsageType = majorityValue(field.Value)
+		case strings.Contains(name, "公司类型") || strings.Contains(name, "company type"):
+			summary.CompanyType = majorityValue(field.Value)
+		case isRiskScoreName(name):
+			if score, ok := riskScore(field, report.IPDatabases); ok {
+				summary.Scores = append(summary.Scores, score)
+			}
+		}
+	}
+	summary.Points = ipQualityPoints(summary)
+	summary.Grade = ipQualityGrade(summary.Points)
+	return summary
+}
+
+// isRiskScoreName 判断字段是否为 0-100（或 0-1）的评分；社区投票等计数字段不算
+func isRiskScoreName(name string) bool {
+	return strings.Contains(name, "得分") || strings.Contains(name, "score") ||
+		strings.Contains(name, "声誉") || strings.Contains(name, "reputation") ||
+		strings.Contains(name, "风险") || strings.Contains(name, "risk")
+}
+
+func riskScore(field IPQualityField, databases map[string]string) (IPRiskScore, bool) {
+	name := strings.ToLower(field.Name)
+	score := IPRiskScore{
+		Name:           field.Name,
+		HigherIsBetter: strings.Contains(name, "越高越好") || strings.Contains(name, "higher") || strings.Contains(name, "声誉") || strings.Contains(name, "信任") || strings.Contains(name, "trust"),
+	}
+	for _, m := range ipValueCodes.FindAllStringSubmatch(field.Value, -1) {
+		value, err := strconv.ParseFloat(strings.Fields(m[1])[0], 64)
+		if err != nil {
+			continue
+		}
+		// ASN/公司滥用得分为 0-1 的小数，换算为百分制
+		if value <= 1 && (strings.Contains(name, "asn") || strings.Contains(name, "公司") || strings.Contains(name, "company")) {
+			value *= 100
+		}
+		for _, code := range strings.Fields(m[2]) {
+			database := code
+			if label, ok := databases[code]; ok {
+				database = label
+			}
+			score.Scores = append(score.Scores, DatabaseScore{Database: database, Score: math.Min(math.Max(value, 0), 100)})
+		}
+	}
+	return score, len(score.Scores) > 0
+}
+
+// majorityValue 返回被最多数据库认定的取值，如 "hosting [0 7 9 A] business [8]" 返回 hosting
+func majorityValue(value string) string {
+	best, bestCount := "", 0
+	for _, m := range ipValueCodes.FindAllStringSubmatch(value, -1) {
+		if count := len(strings.Fields(m[2])); count > bestCount {
+			best, bestCount = strings.TrimSpace(m[1]), count
+		}
+	}
+	if best == "" {
+		return strings.TrimSpace(value)
+	}
+	return best
+}
+
+// ipQualityPoints 计算综合分：从 100 分开始，按“越低越好”的风险评分均值扣至多 50 分，
+// 恶意记录每条扣 10 分、可疑记录每条扣 5 分（合计至多 30 分），
+// DNS 黑名单按列入比例扣至多 20 分。“越高越好”的评分各库含义不一且常以 0 表示无数据，不计入。
+func ipQualityPoints(s IPQualitySummary) float64 {
+	points := 100.0
+	var risk float64
+	var count int
+	for _, score := range s.Scores {
+		if score.HigherIsBetter {
+			continue
+		}
+		for _, db := range score.Scores {
+			risk += db.Score
+			count++
+		}
+	}
+	if count > 0 {
+		points -= risk / float64(count) * 0.5
+	}
+	points -= math.Min(float64(s.Blacklist.Malicious*10+s.Blacklist.Suspicious*5), 30)
+	if s.Blacklist.DNSTotal > 0 {
+		points -= math.Min(float64(s.Blacklist.DNSListed)/float64(s.Blacklist.DNSTotal)*200, 20)
+	}
+	return math.Max(points, 0)
+}
+
+func ipQualityGrade(points float64) string {
+	switch {
+	case points >= 90:
+		return "A"
+	case points >= 80:
+		return "B"
+	case points >= 70:
+		return "C"
+	case points >= 60:
+		return "D"
+	}
+	return "F"
+}
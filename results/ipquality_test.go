@@ -0,0 +1,58 @@
+package results
+
+import (
+	"math"
+	"testing"
+)
+
+const ipQualityOutput = "" +
+	"---------------------系统基础信息---------------------\n" +
+	" ASN                  : AS906 DMIT Cloud Services\n" +
+	"---------------------IP质量检测---------------------\n" +
+	"以下为各数据库编码，输出结果后将自带数据库来源对应的编码\n" +
+	"ipinfo数据库  [0] | scamalytics数据库 [1] | abuseipdb数据库   [3] | ipdata数据库      [8] | ipqualityscore数据库 [E]\n" +
+	"IPV4:\n" +
+	"声誉(越高越好):          0 [8]\n" +
+	"欺诈得分(越低越好):      10 [1] 30 [E]\n" +
+	"滥用得分(越低越好):      0 [3]\n" +
+	"ASN滥用得分(越低越好):   0.0041 (Low) [A]\n" +
+	"社区投票-恶意:           0 [2]\n" +
+	"无害记录数:     0 [2]  恶意记录数:     1 [2]  可疑记录数:     0 [2]  无记录数:     94 [2]\n" +
+	"使用类型:                hosting [0 7 9 A] business [8]\n" +
+	"公司类型:                hosting [0 A]\n" +
+	"DNS-黑名单: 313(Total_Check) 300(Clean) 10(Blacklisted) 3(Other)\n" +
+	"IP类型: 原生IP\n" +
+	"IPV6:\n" +
+	"欺诈得分(越低越好):      99 [1]\n"
+
+func TestAnalyzeIPQuality(t *testing.T) {
+	report := Parse(ipQualityOutput)
+	if report.ASN != "AS906 DMIT Cloud Services" || report.IPType != "native" || report.IPDatabases["E"] != "ipqualityscore" {
+		t.Fatalf("report = asn %q type %q databases %v", report.ASN, report.IPType, report.IPDatabases)
+	}
+	if len(report.System) != 1 || report.System[0] != (InfoField{Name: "ASN", Value: "AS906 DMIT Cloud Services"}) {
+		t.Fatalf("system = %+v", report.System)
+	}
+	s := AnalyzeIPQuality(report)
+	if len(s.Scores) != 4 {
+		t.Fatalf("scores = %+v", s.Scores)
+	}
+	fraud := s.Scores[1]
+	if fraud.HigherIsBetter || len(fraud.Scores) != 2 || fraud.Scores[0] != (DatabaseScore{"scamalytics", 10}) || fraud.Scores[1].Score != 30 {
+		t.Fatalf("fraud = %+v, want the IPv4 values only", fraud)
+	}
+	if !s.Scores[0].HigherIsBetter || math.Abs(s.Scores[3].Scores[0].Score-0.41) > 1e-9 {
+		t.Fatalf("reputation/asn = %+v %+v", s.Scores[0], s.Scores[3])
+	}
+	want := IPBlacklist{Harmless: 0, Malicious: 1, Suspicious: 0, NoRecord: 94, DNSTotal: 313, DNSListed: 10}
+	if s.Blacklist != want || s.UsageType != "hosting" || s.CompanyType != "hosting" {
+		t.Fatalf("summary = %+v", s)
+	}
+	// 风险均值 (10+30+0+0.41)/4≈10.1 扣 5.05，恶意记录扣 10，DNS 10/313 扣 6.39
+	if s.Grade != "C" || s.Points < 78 || s.Points > 79 {
+		t.Fatalf("points = %.2f grade %s", s.Points, s.Grade)
+	}
+	if empty := AnalyzeIPQuality(&Report{}); empty.Grade != "A" || len(empty.Scores) != 0 {
+		t.Fatalf("empty summary = %+v", empty)
+	}
+}
@@ -0,0 +1,24 @@
+package results
+
+// SectionLatency 是 GUI 延迟工具的结果，不对应 ecs 输出中的分区
+const SectionLatency Section = "latency"
+
+// LatencyResult 是对一个目标多次 ICMP 或 TCP 探测的汇总，时间单位为毫秒
+type LatencyResult struct {
+	Target   string  `json:"target"`
+	Protocol string  `json:"protocol"` // icmp 或 tcp
+	Sent     int     `json:"sent"`
+	Received int     `json:"received"`
+	MinMs    float64 `json:"min_ms"`
+	AvgMs    float64 `json:"avg_ms"`
+	MaxMs    float64 `json:"max_ms"`
+	Error    string  `json:"error,omitempty"`
+}
+
+// LossPercent 返回丢包率（0-100），未发送任何探测时为 100
+func (r LatencyResult) LossPercent() float64 {
+	if r.Sent <= 0 {
+		return 100
+	}
+	return float64(r.Sent-r.Received) * 100 / float64(r.Sent)
+}
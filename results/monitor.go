@@ -0,0 +1,48 @@
+package results
+
+// SectionMonitor 是 GUI 路由监测工具的结果，不对应 ecs 输出中的分区
+const SectionMonitor Section = "monitor"
+
+// MonitorHop 是路由监测中一跳的累计结果，Host 为空表示该跳不回应探测
+type MonitorHop struct {
+	Hop      int     `json:"hop"`
+	Host     string  `json:"host,omitempty"`
+	Sent     int     `json:"sent"`
+	Received int     `json:"received"`
+	LastMs   float64 `json:"last_ms"`
+	BestMs   float64 `json:"best_ms"`
+	AvgMs    float64 `json:"avg_ms"`
+	WorstMs  float64 `json:"worst_ms"`
+}
+
+// LossPercent 返回丢包率（0-100），未发送任何探测时为 0
+func (h MonitorHop) LossPercent() float64 {
+	if h.Sent <= 0 {
+		return 0
+	}
+	return float64(h.Sent-h.Received) * 100 / float64(h.Sent)
+}
+
+// Record 累计一次探测：ok 为假表示丢包
+func (h *MonitorHop) Record(rtt float64, ok bool) {
+	h.Sent++
+	if !ok {
+		return
+	}
+	h.Received++
+	h.LastMs = rtt
+	if h.Received == 1 {
+		h.BestMs, h.WorstMs, h.AvgMs = rtt, rtt, rtt
+		return
+	}
+	h.BestMs = min(h.BestMs, rtt)
+	h.WorstMs = max(h.WorstMs, rtt)
+	h.AvgMs += (rtt - h.AvgMs) / float64(h.Received)
+}
+
+// RouteMonitor 是路由监测工具对一个目标持续探测的结果，Rounds 为已完成的轮数
+type RouteMonitor struct {
+	Target string       `json:"target"`
+	Rounds int          `json:"rounds"`
+	Hops   []MonitorHop `json:"hops"`
+}
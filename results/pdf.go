@@ -0,0 +1,318 @@
+package results
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+)
+
+// PDF 页面（A4，单位为点）与排版参数
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 40.0
+	pdfFooter     = 24.0
+	pdfTableFont  = 8.0
+	pdfRowHeight  = 14.0
+	pdfCellPad    = 4.0
+	// 柱状图的标签列与柱区宽度，每个数值一根柱
+	pdfChartLabel = 170.0
+	pdfChartBars  = 270.0
+	pdfBarHeight  = 9.0
+)
+
+// pdfSeriesColors 是柱状图各数值的填充色（RGB）
+var pdfSeriesColors = [][3]float64{{0.25, 0.5, 0.85}, {0.95, 0.55, 0.2}}
+
+// PDFOptions 是 PDF 报告中结构化结果以外的内容
+type PDFOptions struct {
+	// Title 为空时使用 "GOECS Result"
+	Title string
+	// Generated 为零值时使用当前时间
+	Generated time.Time
+	// Fonts 是依次查找字形的字体文件（TrueType、OpenType 或 TTC），为空时使用本机的中文字体；
+	// 都没有的字符使用内置的 Go 字体
+	Fonts [][]byte
+}
+
+// EncodePDF 生成分页的 PDF 报告：系统信息、各分区的柱状图与表格。
+// 只嵌入用到的字形的轮廓，阅读器不需要安装中文字体，报告通常只有几十到一两百 KB。
+func EncodePDF(report *Report, opts PDFOptions) ([]byte, error) {
+	if report == nil {
+		report = &Report{}
+	}
+	if opts.Title == "" {
+		opts.Title = "GOECS Result"
+	}
+	if opts.Generated.IsZero() {
+		opts.Generated = time.Now()
+	}
+	if opts.Fonts == nil {
+		opts.Fonts = systemPDFFonts()
+	}
+	doc := &pdfDoc{fonts: newPDFFontSet(opts.Fonts)}
+	doc.newPage()
+	doc.text(pdfMargin, doc.y+18, 18, opts.Title)
+	doc.y += 26
+	doc.gray(0.4)
+	doc.text(pdfMargin, doc.y+10, 9, opts.Generated.Format("2006-01-02 15:04:05 MST"))
+	doc.gray(0)
+	doc.y += 20
+
+	if len(report.System) > 0 {
+		rows := make([][]string, 0, len(report.System))
+		for _, field := range report.System {
+			rows = append(rows, []string{field.Name, field.Value})
+		}
+		doc.table("System", nil, rows)
+	}
+	charts := resultCharts(report)
+	for _, table := range resultTables(report) {
+		if chart, ok := charts[table.title]; ok {
+			doc.chart(table.title, chart)
+		}
+		doc.table(table.title, table.headers, table.rows)
+	}
+	return doc.bytes(opts.Title)
+}
+
+// pdfDoc 逐页生成内容流，y 为当前位置到页面顶端的距离
+type pdfDoc struct {
+	fonts *pdfFontSet
+	pages []*bytes.Buffer
+	page  *bytes.Buffer
+	y     float64
+}
+
+func (d *pdfDoc) newPage() {
+	d.page = &bytes.Buffer{}
+	d.pages = append(d.pages, d.page)
+	d.y = pdfMargin
+}
+
+// ensure 在当前页剩余空间不足 height 时换页，返回是否换了页
+func (d *pdfDoc) ensure(height float64) bool {
+	if d.y+height <= pdfPageHeight-pdfMargin-pdfFooter {
+		return false
+	}
+	d.newPage()
+	return true
+}
+
+// text 在 (x, baseline) 处写一行文字，baseline 为基线到页面顶端的距离
+func (d *pdfDoc) text(x, baseline, size float64, s string) {
+	fmt.Fprintf(d.page, "BT %s %s Td %s ET\n", pdfNum(x), pdfNum(pdfPageHeight-baseline), d.fonts.show(s, size))
+}
+
+// rect 填充矩形，y 为矩形顶边到页面顶端的距离
+func (d *pdfDoc) rect(x, y, w, h float64, color [3]float64) {
+	fmt.Fprintf(d.page, "%s %s %s rg %s %s %s %s re f 0 g\n",
+		pdfNum(color[0]), pdfNum(color[1]), pdfNum(color[2]), pdfNum(x), pdfNum(pdfPageHeight-y-h), pdfNum(w), pdfNum(h))
+}
+
+func (d *pdfDoc) hline(x1, x2, y float64) {
+	fmt.Fprintf(d.page, "0.8 G 0.5 w %s %s m %s %s l S 0 G\n", pdfNum(x1), pdfNum(pdfPageHeight-y), pdfNum(x2), pdfNum(pdfPageHeight-y))
+}
+
+// gray 设置之后文字的灰度，0 为黑色
+func (d *pdfDoc) gray(level float64) {
+	fmt.Fprintf(d.page, "%s g\n", pdfNum(level))
+}
+
+func (d *pdfDoc) heading(title string, next float64) {
+	d.ensure(28 + next)
+	d.text(pdfMargin, d.y+16, 13, title)
+	d.y += 24
+}
+
+// chart 绘制横向柱状图，按所有数值中的最大值缩放柱长
+func (d *pdfDoc) chart(title string, chart resultChart) {
+	peak := 0.0
+	for _, row := range chart.values {
+		for _, v := range row {
+			peak = max(peak, v)
+		}
+	}
+	d.heading(title, pdfBarHeight*2)
+	if len(chart.series) > 0 {
+		x := pdfMargin + pdfChartLabel
+		for i, name := range chart.series {
+			d.rect(x, d.y+1, 8, 8, pdfSeriesColors[i%len(pdfSeriesColors)])
+			d.text(x+11, d.y+8, pdfTableFont, name)
+			x += 11 + d.width(name, pdfTableFont) + 14
+		}
+		d.y += 14
+	}
+	for i, label := range chart.labels {
+		height := pdfBarHeight * float64(len(chart.values[i]))
+		d.ensure(height + 4)
+		d.text(pdfMargin, d.y+height/2+3, pdfTableFont, d.fit(label, pdfTableFont, pdfChartLabel-pdfCellPad))
+		for series, v := range chart.values[i] {
+			width := 0.0
+			if peak > 0 {
+				width = v / peak * pdfChartBars
+			}
+			y := d.y + float64(series)*pdfBarHeight
+			d.rect(pdfMargin+pdfChartLabel, y, width, pdfBarHeight-1.5, pdfSeriesColors[series%len(pdfSeriesColors)])
+			d.text(pdfMargin+pdfChartLabel+width+4, y+pdfBarHeight-2, 7, strconv.FormatFloat(v, 'f', 2, 64)+chart.unit)
+		}
+		d.y += height + 4
+	}
+	d.y += 6
+}
+
+// table 绘制表格，列宽按内容分配，超出页宽的单元格截断；换页时重复表头
+func (d *pdfDoc) table(title string, headers []string, rows [][]string) {
+	columns := len(headers)
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	widths := make([]float64, columns)
+	for _, row := range append([][]string{headers}, rows...) {
+		for i, cell := range row {
+			widths[i] = max(widths[i], d.width(cell, pdfTableFont)+2*pdfCellPad)
+		}
+	}
+	total, avail := 0.0, pdfPageWidth-2*pdfMargin
+	for _, w := range widths {
+		total += w
+	}
+	for i := range widths {
+		if total > avail {
+			widths[i] *= avail / total
+		} else if i == columns-1 {
+			widths[i] += avail - total
+		}
+	}
+	drawRow := func(row []string, header bool) {
+		if header {
+			d.rect(pdfMargin, d.y, avail, pdfRowHeight, [3]float64{0.92, 0.92, 0.92})
+		}
+		x := pdfMargin
+		for i, cell := range row {
+			d.text(x+pdfCellPad, d.y+pdfRowHeight-4, pdfTableFont, d.fit(cell, pdfTableFont, widths[i]-2*pdfCellPad))
+			x += widths[i]
+		}
+		d.y += pdfRowHeight
+		d.hline(pdfMargin, pdfMargin+avail, d.y)
+	}
+
+	d.heading(title, 2*pdfRowHeight)
+	if len(headers) > 0 {
+		drawRow(headers, true)
+	}
+	for _, row := range rows {
+		if d.ensure(pdfRowHeight) && len(headers) > 0 {
+			drawRow(headers, true)
+		}
+		drawRow(row, false)
+	}
+	d.y += 12
+}
+
+// bytes 组装 PDF 文件：目录、页树与文档信息之后，每页依次为页面对象与压缩后的内容流，最后是嵌入的字体
+func (d *pdfDoc) bytes(title string) ([]byte, error) {
+	// 页脚也要用到字形，先写完各页再确定字体对象
+	for i, page := range d.pages {
+		footer := fmt.Sprintf("%d / %d", i+1, len(d.pages))
+		fmt.Fprintf(page, "0.5 g BT %s %s Td %s ET 0 g\n", pdfNum((pdfPageWidth-d.width(footer, 8))/2), pdfNum(pdfMargin/2), d.fonts.show(footer, 8))
+	}
+	const firstPage = 4
+	fontObjects := make([]int, len(d.fonts.fonts))
+	next := firstPage + 2*len(d.pages)
+	var fontRefs strings.Builder
+	for i, procs := range d.fonts.fonts {
+		fontObjects[i] = next
+		fmt.Fprintf(&fontRefs, " /F%d %d 0 R", i, next)
+		next += 2 + len(procs)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	var offsets []int
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object(fmt.Sprintf("<< /Title %s /Producer (goecs-gui) >>", pdfUTF16(title)))
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font <<%s >> >> /Contents %d 0 R >>",
+			pdfNum(pdfPageWidth), pdfNum(pdfPageHeight), fontRefs.String(), firstPage+2*i+1))
+		object(pdfStream(page.String()))
+	}
+	for i := range d.fonts.fonts {
+		for _, body := range d.fonts.objects(i, fontObjects[i]) {
+			object(body)
+		}
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 3 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return buf.Bytes(), nil
+}
+
+// pdfStream 返回 zlib 压缩的流对象
+func pdfStream(content string) string {
+	var stream bytes.Buffer
+	zw := zlib.NewWriter(&stream)
+	_, _ = zw.Write([]byte(content))
+	_ = zw.Close()
+	return fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", stream.Len(), stream.String())
+}
+
+// width 返回文字在给定字号下的宽度
+func (d *pdfDoc) width(s string, size float64) float64 {
+	return d.fonts.width(s, size)
+}
+
+// fit 把文字截断到 width 以内，截断时以 ... 结尾
+func (d *pdfDoc) fit(s string, size, width float64) string {
+	if d.width(s, size) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && d.width(string(runes)+"...", size) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+// pdfHex 返回 UTF-16BE 编码的十六进制字符串
+func pdfHex(s string) string {
+	var b strings.Builder
+	b.WriteByte('<')
+	for _, unit := range utf16.Encode([]rune(s)) {
+		fmt.Fprintf(&b, "%04X", unit)
+	}
+	b.WriteByte('>')
+	return b.String()
+}
+
+// pdfUTF16 返回文档信息中使用的 UTF-16BE 字符串
+func pdfUTF16(s string) string {
+	return "<FEFF" + strings.TrimPrefix(pdfHex(s), "<")
+}
+
+// pdfNum 以最多两位小数输出坐标与尺寸
+func pdfNum(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 2, 64)
+	s = strings.TrimSuffix(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
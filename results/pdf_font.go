@@ -0,0 +1,265 @@
+package results
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// pdfGlyphsPerFont 是一个 Type3 字体可容纳的字形数（单字节编码）
+const pdfGlyphsPerFont = 256
+
+// pdfSystemFontPaths 是各平台常见的中文字体，导出时使用找到的第一个
+var pdfSystemFontPaths = map[string][]string{
+	"windows": {
+		`$WINDIR\Fonts\msyh.ttc`,
+		`$WINDIR\Fonts\msyh.ttf`,
+		`$WINDIR\Fonts\simhei.ttf`,
+		`$WINDIR\Fonts\simsun.ttc`,
+		`$WINDIR\Fonts\Deng.ttf`,
+	},
+	"darwin": {
+		"/System/Library/Fonts/PingFang.ttc",
+		"/System/Library/Fonts/Hiragino Sans GB.ttc",
+		"/System/Library/Fonts/STHeiti Light.ttc",
+		"/System/Library/Fonts/Supplemental/Arial Unicode.ttf",
+		"/Library/Fonts/Arial Unicode.ttf",
+	},
+	"android": {
+		"/system/fonts/NotoSansCJK-Regular.ttc",
+		"/system/fonts/NotoSansSC-Regular.otf",
+		"/system/fonts/DroidSansFallback.ttf",
+	},
+	"linux": {
+		"/usr/share/fonts/opentype/noto/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/google-noto-cjk/NotoSansCJK-Regular.ttc",
+		"/usr/share/fonts/truetype/wqy/wqy-microhei.ttc",
+		"/usr/share/fonts/wqy-microhei/wqy-microhei.ttc",
+		"/usr/share/fonts/truetype/wqy/wqy-zenhei.ttc",
+		"/usr/share/fonts/wenquanyi/wqy-zenhei/wqy-zenhei.ttc",
+		"/usr/share/fonts/truetype/droid/DroidSansFallbackFull.ttf",
+		"/usr/share/fonts/truetype/arphic/uming.ttc",
+	},
+}
+
+// systemPDFFonts 返回本机的中文字体：FYNE_FONT 指定的字体（界面显示中文时常用）与常见路径中找到的第一个，测试中可替换
+var systemPDFFonts = func() [][]byte {
+	paths, ok := pdfSystemFontPaths[runtime.GOOS]
+	if !ok {
+		paths = pdfSystemFontPaths["linux"]
+	}
+	var fonts [][]byte
+	if custom := os.Getenv("FYNE_FONT"); custom != "" {
+		// FYNE_FONT 可能只是西文字体，之后仍查找中文字体兜底
+		if data, err := os.ReadFile(custom); err == nil {
+			fonts = append(fonts, data)
+		}
+	}
+	for _, path := range paths {
+		if data, err := os.ReadFile(filepath.FromSlash(os.ExpandEnv(path))); err == nil {
+			return append(fonts, data)
+		}
+	}
+	return fonts
+}
+
+// pdfGlyph 是一个已嵌入的字形：所在的 Type3 字体与其中的编码，advance 为 1000 单位的字宽
+type pdfGlyph struct {
+	font    int
+	code    byte
+	advance float64
+}
+
+// pdfGlyphProc 是嵌入字形的绘制过程
+type pdfGlyphProc struct {
+	r       rune
+	advance float64
+	bbox    [4]float64
+	path    string
+}
+
+// pdfFontSet 从字体文件中取出用到的字符的轮廓，作为 Type3 字体嵌入 PDF，阅读器无需安装任何字体。
+// 字符按 sources 的顺序查找字形，都没有时使用最后一个字体的缺字符号；每个 Type3 字体最多 256 个字形，用满后另开一个
+type pdfFontSet struct {
+	sources []*sfnt.Font
+	buf     sfnt.Buffer
+	glyphs  map[rune]pdfGlyph
+	fonts   [][]pdfGlyphProc
+}
+
+// newPDFFontSet 解析字体文件（TrueType、OpenType 或 TTC 中的第一个字体），无法解析的文件被忽略，
+// 最后总是加入内置的 Go 字体
+func newPDFFontSet(files [][]byte) *pdfFontSet {
+	set := &pdfFontSet{glyphs: map[rune]pdfGlyph{}}
+	for _, data := range append(files, goregular.TTF) {
+		collection, err := sfnt.ParseCollection(data)
+		if err != nil || collection.NumFonts() == 0 {
+			continue
+		}
+		if f, err := collection.Font(0); err == nil {
+			set.sources = append(set.sources, f)
+		}
+	}
+	return set
+}
+
+// glyph 返回字符对应的已嵌入字形，首次使用时取出轮廓
+func (s *pdfFontSet) glyph(r rune) pdfGlyph {
+	if g, ok := s.glyphs[r]; ok {
+		return g
+	}
+	proc := s.load(r)
+	if len(s.fonts) == 0 || len(s.fonts[len(s.fonts)-1]) == pdfGlyphsPerFont {
+		s.fonts = append(s.fonts, nil)
+	}
+	last := len(s.fonts) - 1
+	g := pdfGlyph{font: last, code: byte(len(s.fonts[last])), advance: proc.advance}
+	s.fonts[last] = append(s.fonts[last], proc)
+	s.glyphs[r] = g
+	return g
+}
+
+// load 在第一个含有该字符的字体中取出轮廓，换算为 1000 单位、Y 轴向上的 PDF 路径
+func (s *pdfFontSet) load(r rune) pdfGlyphProc {
+	for i, f := range s.sources {
+		index, err := f.GlyphIndex(&s.buf, r)
+		if err != nil || (index == 0 && i < len(s.sources)-1) {
+			continue
+		}
+		upem := fixed.Int26_6(f.UnitsPerEm()) << 6
+		segments, err := f.LoadGlyph(&s.buf, index, upem, nil)
+		if err != nil {
+			continue
+		}
+		advance, err := f.GlyphAdvance(&s.buf, index, upem, font.HintingNone)
+		if err != nil {
+			continue
+		}
+		scale := 1000 / float64(f.UnitsPerEm())
+		return pdfGlyphProc{r: r, advance: float64(advance) / 64 * scale}.trace(segments, scale)
+	}
+	return pdfGlyphProc{r: r, advance: 500}
+}
+
+// trace 把轮廓转成路径并计算包围盒，二次曲线转为三次曲线
+func (p pdfGlyphProc) trace(segments sfnt.Segments, scale float64) pdfGlyphProc {
+	var b strings.Builder
+	first := true
+	point := func(v fixed.Point26_6) (float64, float64) {
+		x, y := float64(v.X)/64*scale, -float64(v.Y)/64*scale
+		if first {
+			p.bbox = [4]float64{x, y, x, y}
+			first = false
+		}
+		p.bbox = [4]float64{min(p.bbox[0], x), min(p.bbox[1], y), max(p.bbox[2], x), max(p.bbox[3], y)}
+		return x, y
+	}
+	var cx, cy float64
+	for _, seg := range segments {
+		switch seg.Op {
+		case sfnt.SegmentOpMoveTo:
+			cx, cy = point(seg.Args[0])
+			fmt.Fprintf(&b, "%s %s m\n", pdfNum(cx), pdfNum(cy))
+		case sfnt.SegmentOpLineTo:
+			cx, cy = point(seg.Args[0])
+			fmt.Fprintf(&b, "%s %s l\n", pdfNum(cx), pdfNum(cy))
+		case sfnt.SegmentOpQuadTo:
+			qx, qy := point(seg.Args[0])
+			x, y := point(seg.Args[1])
+			fmt.Fprintf(&b, "%s %s %s %s %s %s c\n",
+				pdfNum(cx+(qx-cx)*2/3), pdfNum(cy+(qy-cy)*2/3), pdfNum(x+(qx-x)*2/3), pdfNum(y+(qy-y)*2/3), pdfNum(x), pdfNum(y))
+			cx, cy = x, y
+		case sfnt.SegmentOpCubeTo:
+			x1, y1 := point(seg.Args[0])
+			x2, y2 := point(seg.Args[1])
+			cx, cy = point(seg.Args[2])
+			fmt.Fprintf(&b, "%s %s %s %s %s %s c\n", pdfNum(x1), pdfNum(y1), pdfNum(x2), pdfNum(y2), pdfNum(cx), pdfNum(cy))
+		}
+	}
+	if b.Len() > 0 {
+		b.WriteString("f\n")
+	}
+	p.path = b.String()
+	return p
+}
+
+// width 返回文字在给定字号下的宽度
+func (s *pdfFontSet) width(text string, size float64) float64 {
+	total := 0.0
+	for _, r := range text {
+		total += s.glyph(r).advance
+	}
+	return total * size / 1000
+}
+
+// show 返回绘制文字的文本操作：按字形所在的字体分段切换字体，每段以十六进制的单字节编码输出
+func (s *pdfFontSet) show(text string, size float64) string {
+	var b strings.Builder
+	current := -1
+	for _, r := range text {
+		g := s.glyph(r)
+		if g.font != current {
+			if current >= 0 {
+				b.WriteString("> Tj ")
+			}
+			fmt.Fprintf(&b, "/F%d %s Tf <", g.font, pdfNum(size))
+			current = g.font
+		}
+		fmt.Fprintf(&b, "%02X", g.code)
+	}
+	if current >= 0 {
+		b.WriteString("> Tj")
+	}
+	return b.String()
+}
+
+// objects 返回第 i 个 Type3 字体的对象：字体字典、ToUnicode 映射与各字形的绘制过程，first 为字体字典的对象号
+func (s *pdfFontSet) objects(i, first int) []string {
+	procs := s.fonts[i]
+	var names, widths, charProcs strings.Builder
+	bbox := [4]float64{}
+	for code, proc := range procs {
+		fmt.Fprintf(&names, " /g%d", code)
+		fmt.Fprintf(&widths, " %s", pdfNum(proc.advance))
+		fmt.Fprintf(&charProcs, " /g%d %d 0 R", code, first+2+code)
+		bbox = [4]float64{min(bbox[0], proc.bbox[0]), min(bbox[1], proc.bbox[1]), max(bbox[2], proc.bbox[2]), max(bbox[3], proc.bbox[3])}
+	}
+	objects := []string{
+		fmt.Sprintf("<< /Type /Font /Subtype /Type3 /FontBBox [%s %s %s %s] /FontMatrix [0.001 0 0 0.001 0 0] /CharProcs <<%s >> "+
+			"/Encoding << /Type /Encoding /Differences [0%s] >> /FirstChar 0 /LastChar %d /Widths [%s ] /ToUnicode %d 0 R /Resources << >> >>",
+			pdfNum(bbox[0]), pdfNum(bbox[1]), pdfNum(bbox[2]), pdfNum(bbox[3]), charProcs.String(), names.String(), len(procs)-1, widths.String(), first+1),
+		pdfStream(toUnicodeCMap(procs)),
+	}
+	for _, proc := range procs {
+		// d1 表示字形不自带颜色，使用文字当前的填充色
+		objects = append(objects, pdfStream(fmt.Sprintf("%s 0 %s %s %s %s d1\n%s",
+			pdfNum(proc.advance), pdfNum(proc.bbox[0]), pdfNum(proc.bbox[1]), pdfNum(proc.bbox[2]), pdfNum(proc.bbox[3]), proc.path)))
+	}
+	return objects
+}
+
+// toUnicodeCMap 把单字节编码映射回字符，阅读器据此复制与搜索文字
+func toUnicodeCMap(procs []pdfGlyphProc) string {
+	var b strings.Builder
+	b.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n" +
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n" +
+		"/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n1 begincodespacerange\n<00> <FF>\nendcodespacerange\n")
+	for start := 0; start < len(procs); start += 100 {
+		chunk := procs[start:min(start+100, len(procs))]
+		fmt.Fprintf(&b, "%d beginbfchar\n", len(chunk))
+		for i, proc := range chunk {
+			fmt.Fprintf(&b, "<%02X> %s\n", start+i, pdfHex(string(proc.r)))
+		}
+		b.WriteString("endbfchar\n")
+	}
+	b.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	return b.String()
+}
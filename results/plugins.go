@@ -0,0 +1,138 @@
+package results
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// LineParser 解析一个分区内的输出行。每次 Parse 遇到该分区都会新建实例，实例可以保存跨行状态
+type LineParser interface {
+	ParseLine(report *Report, line string)
+}
+
+// LineParserFunc 把无状态的解析函数适配为 LineParser
+type LineParserFunc func(report *Report, line string)
+
+// ParseLine 调用 f
+func (f LineParserFunc) ParseLine(report *Report, line string) {
+	f(report, line)
+}
+
+// SectionPlugin 描述 ecs 输出中的一个分区：如何从标题识别，以及如何解析其中的行
+type SectionPlugin struct {
+	Section Section
+	// Match 判断标题（已去掉两侧的 '-'）是否属于该分区
+	Match func(title string) bool
+	// New 创建行解析器；为 nil 表示只识别分区而不解析内容（如邮件端口）
+	New func() LineParser
+}
+
+// RawSection 是没有对应插件的分区，保留原始行，结果面板以原文卡片展示
+type RawSection struct {
+	Title string   `json:"title"`
+	Lines []string `json:"lines,omitempty"`
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   []SectionPlugin
+)
+
+// Register 注册一个分区插件。标题按注册顺序匹配，内置分区先于后注册的插件；
+// 分区名为空、缺少 Match 或分区已注册时 panic
+func Register(plugin SectionPlugin) {
+	if plugin.Section == SectionNone || plugin.Match == nil {
+		panic("results: plugin needs a section and a title matcher")
+	}
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	for _, existing := range plugins {
+		if existing.Section == plugin.Section {
+			panic(fmt.Sprintf("results: section %q registered twice", plugin.Section))
+		}
+	}
+	plugins = append(plugins, plugin)
+}
+
+// lookupPlugin 返回与标题匹配的插件
+func lookupPlugin(title string) (SectionPlugin, bool) {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	for _, plugin := range plugins {
+		if plugin.Match(title) {
+			return plugin, true
+		}
+	}
+	return SectionPlugin{}, false
+}
+
+// titleContains 匹配包含任一关键字的标题
+func titleContains(keywords ...string) func(string) bool {
+	return func(title string) bool {
+		for _, keyword := range keywords {
+			if strings.Contains(title, keyword) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func stateless(f func(*Report, string)) func() LineParser {
+	return func() LineParser { return LineParserFunc(f) }
+}
+
+func init() {
+	for _, plugin := range []SectionPlugin{
+		{Section: SectionBasic, Match: titleContains("系统基础信息", "System-Basic"), New: stateless(parseBasicLine)},
+		{Section: SectionCPU, Match: titleContains("CPU"), New: stateless(parseCPULine)},
+		{Section: SectionMemory, Match: titleContains("内存", "Memory"), New: stateless(parseMemoryLine)},
+		{Section: SectionDisk, Match: titleContains("硬盘", "Disk"), New: stateless(parseDiskLine)},
+		{Section: SectionUnlock, Match: titleContains("解锁", "Unlock"), New: func() LineParser { return &unlockParser{} }},
+		{Section: SectionIPQuality, Match: titleContains("IP质量", "IP-Quality"), New: stateless(parseIPQualityLine)},
+		{Section: SectionEmail, Match: titleContains("邮件", "Email")},
+		{Section: SectionBacktrace, Match: titleContains("回程线路", "Backtrace"), New: stateless(parseBacktraceLine)},
+		{Section: SectionRoute, Match: titleContains("路由", "NextTrace"), New: func() LineParser { return &routeParser{} }},
+		{Section: SectionPing, Match: titleContains("PING")},
+		{Section: SectionSpeed, Match: titleContains("测速", "Speed"), New: stateless(parseSpeedLine)},
+		{Section: SectionGPU, Match: titleContains("GPU信息", "GPU-Info"), New: stateless(parseGPULine)},
+	} {
+		Register(plugin)
+	}
+}
+
+// sectionTitle 从标题行（如 "----CPU测试-通过sysbench测试----"）取出标题，ok 为 false 表示不是标题行
+func sectionTitle(line string) (string, bool) {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, "---") || !strings.HasSuffix(trimmed, "---") {
+		return "", false
+	}
+	return strings.Trim(trimmed, "-"), true
+}
+
+// sectionState 是 Parse 中当前分区的解析状态
+type sectionState struct {
+	section Section
+	parser  LineParser
+	raw     *RawSection
+}
+
+// feed 把一行交给当前分区；插件 panic 时记录日志，并把该分区剩余的行改为原文保存
+func (s *sectionState) feed(report *Report, line string) {
+	if s.raw != nil {
+		s.raw.Lines = append(s.raw.Lines, line)
+		return
+	}
+	if s.parser == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Warn("section parser panicked", "section", s.section, "panic", r)
+			report.Raw = append(report.Raw, RawSection{Title: string(s.section), Lines: []string{line}})
+			s.parser, s.raw = nil, &report.Raw[len(report.Raw)-1]
+		}
+	}()
+	s.parser.ParseLine(report, line)
+}
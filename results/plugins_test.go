@@ -0,0 +1,74 @@
+package results
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnknownSectionBecomesRawCard(t *testing.T) {
+	output := strings.Join([]string{
+		"---------------------CPU测试--通过sysbench测试-------------------------",
+		"1 线程测试(单核)得分:          1234",
+		"----------------------GPU测试----------------------",
+		"GPU Model: NVIDIA T4",
+		"CUDA: 12.4",
+		"---------------------------------------------------",
+		"ignored trailing line",
+	}, "\n")
+	report := Parse(output)
+	if len(report.CPU) != 1 {
+		t.Fatalf("known sections should still parse, CPU = %+v", report.CPU)
+	}
+	if len(report.Raw) != 1 {
+		t.Fatalf("Raw = %+v, want one section", report.Raw)
+	}
+	raw := report.Raw[0]
+	if raw.Title != "GPU测试" || strings.Join(raw.Lines, "|") != "GPU Model: NVIDIA T4|CUDA: 12.4" {
+		t.Fatalf("raw section = %+v", raw)
+	}
+	if report.Empty() {
+		t.Fatal("a report with raw sections is not empty")
+	}
+}
+
+type gpuParser struct{ lines int }
+
+func (p *gpuParser) ParseLine(report *Report, line string) {
+	p.lines++
+	report.System = append(report.System, InfoField{Name: "gpu", Value: line})
+	if p.lines == 2 {
+		panic("unexpected format")
+	}
+}
+
+func TestRegisteredPluginParsesAndDegradesOnPanic(t *testing.T) {
+	Register(SectionPlugin{
+		Section: "test_accelerator",
+		Match:   titleContains("Accelerator"),
+		New:     func() LineParser { return &gpuParser{} },
+	})
+	report := Parse(strings.Join([]string{
+		"-----------Accelerator-----------",
+		"first",
+		"second",
+		"third",
+		"-----------Accelerator-----------",
+		"again",
+	}, "\n"))
+	if len(report.System) != 3 || report.System[2].Value != "again" {
+		t.Fatalf("plugin lines = %+v, want a fresh parser per section", report.System)
+	}
+	if len(report.Raw) != 1 || report.Raw[0].Title != "test_accelerator" || strings.Join(report.Raw[0].Lines, "|") != "second|third" {
+		t.Fatalf("Raw = %+v, want the rest of the panicking section", report.Raw)
+	}
+	if section, ok := DetectSection("-----------Accelerator-----------"); !ok || section != "test_accelerator" {
+		t.Fatalf("DetectSection() = %q, %v", section, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("registering a section twice should panic")
+		}
+	}()
+	Register(SectionPlugin{Section: SectionCPU, Match: titleContains("x")})
+}
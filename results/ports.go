@@ -0,0 +1,39 @@
+package results
+
+// SectionPorts 是 GUI 端口检测工具的结果，不对应 ecs 输出中的分区
+const SectionPorts Section = "ports"
+
+// 端口检测的状态：open 为连接成功；closed 为对方拒绝连接（端口可达但没有服务）；
+// filtered 为连接超时或被丢弃，通常说明端口被防火墙或服务商过滤
+const (
+	PortOpen     = "open"
+	PortClosed   = "closed"
+	PortFiltered = "filtered"
+)
+
+// PortResult 是对一个 TCP 端口的检测结果
+type PortResult struct {
+	Port      int     `json:"port"`
+	Service   string  `json:"service,omitempty"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// PortScan 是端口检测工具对一个目标的检测结果，ICMP 为 ping 的汇总，未检测时为 nil
+type PortScan struct {
+	Target string         `json:"target"`
+	ICMP   *LatencyResult `json:"icmp,omitempty"`
+	Ports  []PortResult   `json:"ports"`
+}
+
+// Blocked 返回被过滤的端口
+func (s *PortScan) Blocked() []int {
+	var blocked []int
+	for _, port := range s.Ports {
+		if port.Status == PortFiltered {
+			blocked = append(blocked, port.Port)
+		}
+	}
+	return blocked
+}
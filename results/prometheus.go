@@ -0,0 +1,137 @@
+package results
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Label 是监控指标的一个标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Gauge 是导出给监控系统的一个指标值
+type Gauge struct {
+	Name   string
+	Labels []Label
+	Value  float64
+}
+
+// gaugeHelp 是各指标的说明，顺序即 Prometheus 输出中的顺序
+var gaugeHelp = []struct{ name, help string }{
+	{"ecs_last_run_timestamp_seconds", "Finish time of the run the other metrics come from."},
+	{"ecs_cpu_score", "CPU benchmark score."},
+	{"ecs_memory_bandwidth_mbps", "Memory bandwidth in MB/s."},
+	{"ecs_disk_throughput_mbps", "Disk throughput in MB/s."},
+	{"ecs_disk_iops", "Disk IOPS."},
+	{"ecs_net_download_mbps", "Speed test download in Mbps."},
+	{"ecs_net_upload_mbps", "Speed test upload in Mbps."},
+	{"ecs_net_latency_ms", "Speed test latency in milliseconds."},
+	{"ecs_ip_risk_score", "IP risk score (0-100) reported by each database."},
+	{"ecs_ip_quality_points", "Overall IP quality points (0-100, higher is better)."},
+}
+
+// Gauges 把报告转为监控指标，不含主机标签
+func Gauges(report *Report) []Gauge {
+	if report == nil {
+		return nil
+	}
+	var gauges []Gauge
+	add := func(name string, value float64, labels ...Label) {
+		gauges = append(gauges, Gauge{Name: name, Labels: labels, Value: value})
+	}
+	for _, s := range report.CPU {
+		add("ecs_cpu_score", s.Score, Label{"test", s.Label})
+	}
+	for _, m := range report.Memory {
+		add("ecs_memory_bandwidth_mbps", m.MBps, Label{"test", m.Label})
+	}
+	for _, d := range report.Disk {
+		for _, op := range []struct {
+			name   string
+			metric DiskMetric
+		}{{"read", d.Read}, {"write", d.Write}, {"total", d.Total}} {
+			labels := []Label{{"path", d.Path}, {"block", d.Block}, {"op", op.name}}
+			if op.metric.MBps > 0 {
+				add("ecs_disk_throughput_mbps", op.metric.MBps, labels...)
+			}
+			if op.metric.IOPS > 0 {
+				add("ecs_disk_iops", op.metric.IOPS, labels...)
+			}
+		}
+	}
+	for _, s := range report.Speed {
+		add("ecs_net_download_mbps", s.DownloadMbps, Label{"node", s.Node})
+		add("ecs_net_upload_mbps", s.UploadMbps, Label{"node", s.Node})
+		if s.LatencyMs > 0 {
+			add("ecs_net_latency_ms", s.LatencyMs, Label{"node", s.Node})
+		}
+	}
+	if len(report.IPQuality) > 0 {
+		summary := AnalyzeIPQuality(report)
+		for _, score := range summary.Scores {
+			for _, db := range score.Scores {
+				add("ecs_ip_risk_score", db.Score, Label{"name", score.Name}, Label{"database", db.Database})
+			}
+		}
+		add("ecs_ip_quality_points", summary.Points)
+	}
+	return gauges
+}
+
+// Snapshot 是一台主机最近一次运行的结果
+type Snapshot struct {
+	Host   string
+	Time   time.Time
+	Report *Report
+}
+
+// WritePrometheus 以 Prometheus 文本格式写出各主机的指标，每个指标都带 host 标签
+func WritePrometheus(w io.Writer, snapshots []Snapshot) error {
+	byName := map[string][]string{}
+	for _, snapshot := range snapshots {
+		host := Label{"host", snapshot.Host}
+		if !snapshot.Time.IsZero() {
+			name := "ecs_last_run_timestamp_seconds"
+			byName[name] = append(byName[name], promSample(name, []Label{host}, float64(snapshot.Time.Unix())))
+		}
+		for _, gauge := range Gauges(snapshot.Report) {
+			byName[gauge.Name] = append(byName[gauge.Name], promSample(gauge.Name, append([]Label{host}, gauge.Labels...), gauge.Value))
+		}
+	}
+	out := bufio.NewWriter(w)
+	for _, metric := range gaugeHelp {
+		samples := byName[metric.name]
+		if len(samples) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "# HELP %s %s\n# TYPE %s gauge\n", metric.name, metric.help, metric.name)
+		for _, sample := range samples {
+			out.WriteString(sample)
+		}
+	}
+	return out.Flush()
+}
+
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func promSample(name string, labels []Label, value float64) string {
+	var b strings.Builder
+	b.WriteString(name)
+	b.WriteByte('{')
+	for i, label := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `%s="%s"`, label.Name, promLabelEscaper.Replace(label.Value))
+	}
+	b.WriteString("} ")
+	b.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+	b.WriteByte('\n')
+	return b.String()
+}
@@ -0,0 +1,39 @@
+package results
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheusLabelsByHost(t *testing.T) {
+	report := &Report{
+		CPU:   []CPUScore{{Label: `1 "core"`, Score: 1234}},
+		Disk:  []DiskResult{{Path: "/root", Block: "4k", Read: DiskMetric{MBps: 100, IOPS: 25000}}},
+		Speed: []SpeedResult{{Node: "Speedtest.net", DownloadMbps: 940.5, UploadMbps: 500}},
+	}
+	var out strings.Builder
+	err := WritePrometheus(&out, []Snapshot{
+		{Host: "a", Time: time.Unix(1700000000, 0), Report: report},
+		{Host: "b", Report: &Report{CPU: []CPUScore{{Label: "1", Score: 10}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := out.String()
+	for _, want := range []string{
+		"# TYPE ecs_cpu_score gauge\n",
+		`ecs_cpu_score{host="a",test="1 \"core\""} 1234` + "\n",
+		`ecs_cpu_score{host="b",test="1"} 10` + "\n",
+		`ecs_disk_iops{host="a",path="/root",block="4k",op="read"} 25000` + "\n",
+		`ecs_net_download_mbps{host="a",node="Speedtest.net"} 940.5` + "\n",
+		`ecs_last_run_timestamp_seconds{host="a"} 1.7e+09` + "\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("output missing %q:\n%s", want, text)
+		}
+	}
+	if strings.Count(text, "# HELP ecs_cpu_score") != 1 || strings.Contains(text, `ecs_last_run_timestamp_seconds{host="b"}`) {
+		t.Fatalf("unexpected output:\n%s", text)
+	}
+}
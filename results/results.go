@@ -0,0 +1,385 @@
+// Package results 将 ecs 文本输出解析为结构化结果，供结果面板、导出和历史记录使用。
+package results
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/applog"
+)
+
+var logger = applog.For("parser")
+
+// Section 标识 ecs 输出中的测试分区
+type Section string
+
+const (
+	SectionNone      Section = ""
+	SectionBasic     Section = "basic"
+	SectionCPU       Section = "cpu"
+	SectionMemory    Section = "memory"
+	SectionDisk      Section = "disk"
+	SectionUnlock    Section = "unlock"
+	SectionIPQuality Section = "ip_quality"
+	SectionEmail     Section = "email"
+	SectionBacktrace Section = "backtrace"
+	SectionRoute     Section = "route"
+	SectionPing      Section = "ping"
+	SectionSpeed     Section = "speed"
+	SectionGPU       Section = "gpu"
+)
+
+// CPUScore 是一次 CPU 跑分结果
+type CPUScore struct {
+	Label   string  `json:"label"`
+	Threads int     `json:"threads,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+// MemoryResult 是一项内存带宽结果，统一换算为 MB/s
+type MemoryResult struct {
+	Label string  `json:"label"`
+	MBps  float64 `json:"mbps"`
+}
+
+// DiskMetric 是硬盘单个方向的速度与 IOPS
+type DiskMetric struct {
+	MBps float64 `json:"mbps"`
+	IOPS float64 `json:"iops,omitempty"`
+}
+
+// DiskResult 是硬盘测试的一行结果
+type DiskResult struct {
+	Path  string     `json:"path,omitempty"`
+	Block string     `json:"block,omitempty"`
+	Read  DiskMetric `json:"read"`
+	Write DiskMetric `json:"write"`
+	Total DiskMetric `json:"total"`
+}
+
+// SpeedResult 是一个测速节点的结果
+type SpeedResult struct {
+	Node         string  `json:"node"`
+	UploadMbps   float64 `json:"upload_mbps"`
+	DownloadMbps float64 `json:"download_mbps"`
+	LatencyMs    float64 `json:"latency_ms"`
+	PacketLoss   string  `json:"packet_loss,omitempty"`
+}
+
+// IPQualityField 是 IP 质量检测中的一个字段
+type IPQualityField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// InfoField 是系统基础信息中的一项，如 "CPU 型号: AMD EPYC 7763"
+type InfoField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// UnlockResult 是一个平台的解锁检测结果
+type UnlockResult struct {
+	Platform string `json:"platform"`
+	Status   string `json:"status"`
+	Region   string `json:"region,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	// Group 为输出中的地区分组（如 Multination、Hong Kong），Stack 为 IPV4 或 IPV6
+	Group string `json:"group,omitempty"`
+	Stack string `json:"stack,omitempty"`
+}
+
+// Report 是从一次完整输出中解析出的结构化结果
+type Report struct {
+	CPU       []CPUScore       `json:"cpu,omitempty"`
+	Memory    []MemoryResult   `json:"memory,omitempty"`
+	Disk      []DiskResult     `json:"disk,omitempty"`
+	Speed     []SpeedResult    `json:"speed,omitempty"`
+	IPQuality []IPQualityField `json:"ip_quality,omitempty"`
+	Unlock    []UnlockResult   `json:"unlock,omitempty"`
+	// Backtrace 为三网回程线路识别结果，Routes 为逐跳路由追踪
+	Backtrace []BacktraceResult `json:"backtrace,omitempty"`
+	Routes    []RoutePath       `json:"routes,omitempty"`
+	// IPDatabases 是 IP 质量检测输出开头的数据库编码说明，如 "8" -> "ipdata"
+	IPDatabases map[string]string `json:"ip_databases,omitempty"`
+	// System 为系统基础信息中的各项，按输出顺序排列
+	System []InfoField `json:"system,omitempty"`
+	// ASN 取自系统基础信息，IPType 为 native（原生）或 broadcast（广播），未识别时为空
+	ASN    string `json:"asn,omitempty"`
+	IPType string `json:"ip_type,omitempty"`
+	// DiskFileSize 为 GUI 自行调用 fio 时的测试文件大小，如 "512M"；安全模式因剩余空间不足缩小时 DiskShrunkFrom 为原大小
+	DiskFileSize   string `json:"disk_file_size,omitempty"`
+	DiskShrunkFrom string `json:"disk_shrunk_from,omitempty"`
+	// Latency 来自延迟工具页，导出时与本次报告合并
+	Latency []LatencyResult `json:"latency,omitempty"`
+	// PortScan 来自端口检测工具页，导出时与本次报告合并
+	PortScan *PortScan `json:"port_scan,omitempty"`
+	// Monitor 来自路由监测工具页，导出时与本次报告合并
+	Monitor *RouteMonitor `json:"route_monitor,omitempty"`
+	// GeekbenchLink 为 Geekbench 结果页，GeekbenchClaim 为把结果加入账号的认领链接
+	GeekbenchLink  string `json:"geekbench_link,omitempty"`
+	GeekbenchClaim string `json:"geekbench_claim,omitempty"`
+	// GPU 为 GPU 信息阶段列出的显卡，GPUBench 为 hashcat 基准成绩
+	GPU      []GPUInfo  `json:"gpu,omitempty"`
+	GPUBench []GPUBench `json:"gpu_bench,omitempty"`
+	// Raw 是没有对应插件的分区，保留原文
+	Raw []RawSection `json:"raw,omitempty"`
+	// Annotations 是用户在终端输出中添加的书签与批注，导出时与本次报告合并
+	Annotations []Annotation `json:"annotations,omitempty"`
+}
+
+// Annotation 是终端输出中一行的书签，Line 从 1 开始计数
+type Annotation struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+	Note string `json:"note,omitempty"`
+}
+
+// Empty 判断是否未解析到任何结果
+func (r *Report) Empty() bool {
+	return r == nil || len(r.CPU)+len(r.Memory)+len(r.Disk)+len(r.Speed)+len(r.IPQuality)+len(r.Unlock)+len(r.Backtrace)+len(r.Routes)+len(r.Latency)+len(r.GPU)+len(r.Raw) == 0 && r.PortScan == nil && r.Monitor == nil
+}
+
+var (
+	ansiPattern      = regexp.MustCompile(`\x1B\[[0-9;:]*[A-Za-z]`)
+	cpuThreadPattern = regexp.MustCompile(`^(\d+)\s*(?:Thread\(s\) Test|线程测试\S*得分)\s*[:：]\s*([\d.]+)`)
+	cpuScorePattern  = regexp.MustCompile(`^(Single-Core Score|Multi-Core Score|单核得分|多核得分)\s*[:：]\s*([\d.]+)`)
+	memoryPattern    = regexp.MustCompile(`^(.+?)\s*[:：]\s*([\d.]+)\s*([MG]B/s)`)
+	streamPattern    = regexp.MustCompile(`^(Copy|Scale|Add|Triad)\s*:\s+([\d.]+)`)
+	diskSizePattern  = regexp.MustCompile(`^\s*(?:测试文件大小|Test file size)\s*:\s*(\d+[KMGT]?)(?:.*?(?:已由|shrunk from)\s*(\d+[KMGT]?))?`)
+	diskMetric       = regexp.MustCompile(`([\d.]+)\s*([KMG]B/s)\s*[(\[]\s*([\d.]+)\s*([KM]?)`)
+	speedPattern     = regexp.MustCompile(`^(.+?)\s+([\d.]+)\s*Mbps\s+([\d.]+)\s*Mbps\s+([\d.]+)\s*ms(?:\s+(\S+))?`)
+	unlockPattern    = regexp.MustCompile(`^(.+?)\s+(YES|NO|Banned|Failed|N/A|Restricted|Rate Limited|Error|TIMEOUT|Unknown|CDN Relay)\b(.*)$`)
+	unlockRegion     = regexp.MustCompile(`\(Region:\s*([^)]+)\)`)
+	unlockHeader     = regexp.MustCompile(`^=+\[\s*(.+?)\s*\]=+$`)
+	keyValuePattern  = regexp.MustCompile(`^(.+?)\s*[:：]\s*(.+)$`)
+	ipDatabaseLegend = regexp.MustCompile(`([A-Za-z0-9.\-]+?)\s*(?:数据库|[Dd]atabase)\s*\[([0-9A-Za-z])\]`)
+	ipTypePattern    = regexp.MustCompile(`(?i)(原生|广播|native|broadcast)\s*IP`)
+)
+
+// StripANSI 移除 ANSI 转义序列
+func StripANSI(text string) string {
+	return ansiPattern.ReplaceAllString(text, "")
+}
+
+// DetectSection 根据居中标题行（如 "----CPU测试-通过sysbench测试----"）识别分区，
+// ok 为 false 表示该行不是标题行；未注册的标题返回 SectionNone
+func DetectSection(line string) (Section, bool) {
+	title, ok := sectionTitle(line)
+	if !ok {
+		return SectionNone, false
+	}
+	plugin, _ := lookupPlugin(title)
+	return plugin.Section, true
+}
+
+// Parse 解析 ecs 的完整文本输出，无法识别的行会被忽略；没有插件的分区原样保存在 Raw 中
+func Parse(output string) *Report {
+	report := &Report{}
+	var state sectionState
+	var sections []Section
+	for _, raw := range strings.Split(StripANSI(output), "\n") {
+		line := strings.TrimSpace(strings.TrimRight(raw, "\r"))
+		if line == "" {
+			continue
+		}
+		if title, ok := sectionTitle(line); ok {
+			state = sectionState{}
+			if plugin, found := lookupPlugin(title); found {
+				state.section = plugin.Section
+				if plugin.New != nil {
+					state.parser = plugin.New()
+				}
+				sections = append(sections, plugin.Section)
+			} else if title != "" {
+				report.Raw = append(report.Raw, RawSection{Title: title})
+				state.raw = &report.Raw[len(report.Raw)-1]
+			}
+			continue
+		}
+		if report.IPType == "" {
+			report.IPType = detectIPType(line)
+		}
+		state.feed(report, line)
+	}
+	logger.Debug("parsed output", "bytes", len(output), "sections", sections, "raw", len(report.Raw),
+		"cpu", len(report.CPU), "disk", len(report.Disk), "speed", len(report.Speed))
+	return report
+}
+
+func parseCPULine(report *Report, line string) {
+	if link, ok := strings.CutPrefix(line, "Link:"); ok && strings.Contains(link, "geekbench.com") {
+		report.GeekbenchLink = strings.TrimSpace(link)
+		return
+	}
+	if link, ok := strings.CutPrefix(line, "Claim:"); ok {
+		report.GeekbenchClaim = strings.TrimSpace(link)
+		return
+	}
+	if m := cpuThreadPattern.FindStringSubmatch(line); m != nil {
+		threads, _ := strconv.Atoi(m[1])
+		report.CPU = append(report.CPU, CPUScore{Label: strings.TrimSpace(strings.SplitN(line, ":", 2)[0]), Threads: threads, Score: parseFloat(m[2])})
+		return
+	}
+	if m := cpuScorePattern.FindStringSubmatch(line); m != nil {
+		report.CPU = append(report.CPU, CPUScore{Label: m[1], Score: parseFloat(m[2])})
+	}
+}
+
+func parseMemoryLine(report *Report, line string) {
+	if m := streamPattern.FindStringSubmatch(line); m != nil {
+		report.Memory = append(report.Memory, MemoryResult{Label: m[1], MBps: parseFloat(m[2])})
+		return
+	}
+	if m := memoryPattern.FindStringSubmatch(line); m != nil {
+		report.Memory = append(report.Memory, MemoryResult{Label: m[1], MBps: toMBps(parseFloat(m[2]), m[3])})
+	}
+}
+
+func parseDiskLine(report *Report, line string) {
+	if m := diskSizePattern.FindStringSubmatch(line); m != nil {
+		report.DiskFileSize, report.DiskShrunkFrom = m[1], m[2]
+		return
+	}
+	locs := diskMetric.FindAllStringSubmatchIndex(line, -1)
+	if len(locs) == 0 {
+		return
+	}
+	metrics := make([]DiskMetric, 0, len(locs))
+	for _, loc := range locs {
+		value := parseFloat(line[loc[2]:loc[3]])
+		unit := line[loc[4]:loc[5]]
+		iops := parseFloat(line[loc[6]:loc[7]])
+		switch line[loc[8]:loc[9]] {
+		case "K":
+			iops *= 1000
+		case "M":
+			iops *= 1000000
+		}
+		metrics = append(metrics, DiskMetric{MBps: toMBps(value, unit), IOPS: iops})
+	}
+	result := DiskResult{}
+	prefix := strings.Fields(line[:locs[0][0]])
+	if len(prefix) > 0 {
+		result.Block = prefix[len(prefix)-1]
+	}
+	if len(prefix) > 1 {
+		result.Path = strings.Join(prefix[:len(prefix)-1], " ")
+	}
+	result.Read = metrics[0]
+	if len(metrics) > 1 {
+		result.Write = metrics[1]
+	}
+	if len(metrics) > 2 {
+		result.Total = metrics[2]
+	} else {
+		result.Total = DiskMetric{MBps: result.Read.MBps + result.Write.MBps, IOPS: result.Read.IOPS + result.Write.IOPS}
+	}
+	report.Disk = append(report.Disk, result)
+}
+
+func parseSpeedLine(report *Report, line string) {
+	m := speedPattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	report.Speed = append(report.Speed, SpeedResult{
+		Node:         strings.TrimSpace(m[1]),
+		UploadMbps:   parseFloat(m[2]),
+		DownloadMbps: parseFloat(m[3]),
+		LatencyMs:    parseFloat(m[4]),
+		PacketLoss:   m[5],
+	})
+}
+
+func parseBasicLine(report *Report, line string) {
+	m := keyValuePattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	key, value := strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	report.System = append(report.System, InfoField{Name: key, Value: value})
+	if report.ASN == "" && strings.HasSuffix(key, "ASN") && strings.HasPrefix(value, "AS") {
+		report.ASN = value
+	}
+}
+
+// detectIPType 识别 "原生IP"/"广播IP" 标注
+func detectIPType(line string) string {
+	m := ipTypePattern.FindStringSubmatch(line)
+	if m == nil {
+		return ""
+	}
+	switch strings.ToLower(m[1]) {
+	case "原生", "native":
+		return "native"
+	}
+	return "broadcast"
+}
+
+func parseIPQualityLine(report *Report, line string) {
+	if legend := ipDatabaseLegend.FindAllStringSubmatch(line, -1); len(legend) > 0 && !strings.ContainsAny(line, ":：") {
+		if report.IPDatabases == nil {
+			report.IPDatabases = map[string]string{}
+		}
+		for _, m := range legend {
+			report.IPDatabases[m[2]] = m[1]
+		}
+		return
+	}
+	m := keyValuePattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	report.IPQuality = append(report.IPQuality, IPQualityField{Name: strings.TrimSpace(m[1]), Value: strings.TrimSpace(m[2])})
+}
+
+// unlockParser 记录解锁输出中当前所在的分组标题，如 "====[ IPV4 Hong Kong ]===="
+type unlockParser struct {
+	group, stack string
+}
+
+func (p *unlockParser) ParseLine(report *Report, line string) {
+	if m := unlockHeader.FindStringSubmatch(line); m != nil {
+		title := m[1]
+		// 不带 IP 版本的标题是分组内的小分区，沿用当前 IP 版本
+		if stack, rest, ok := strings.Cut(title, " "); ok && (stack == "IPV4" || stack == "IPV6") {
+			p.stack, title = stack, strings.TrimSpace(rest)
+		} else if title == "IPV4" || title == "IPV6" {
+			p.stack, title = title, ""
+		}
+		p.group = title
+		return
+	}
+	m := unlockPattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	result := UnlockResult{
+		Platform: strings.TrimSuffix(strings.TrimSpace(m[1]), ":"),
+		Status:   m[2],
+		Detail:   strings.TrimSpace(m[3]),
+		Group:    p.group,
+		Stack:    p.stack,
+	}
+	if region := unlockRegion.FindStringSubmatch(m[3]); region != nil {
+		result.Region = strings.TrimSpace(region[1])
+	}
+	report.Unlock = append(report.Unlock, result)
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return v
+}
+
+func toMBps(value float64, unit string) float64 {
+	switch unit {
+	case "GB/s":
+		return value * 1024
+	case "KB/s":
+		return value / 1024
+	}
+	return value
+}
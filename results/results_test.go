@@ -0,0 +1,239 @@
+package results
+
+import (
+	"strings"
+	"testing"
+)
+
+const sampleOutput = "" +
+	"---------------------CPU测试-通过sysbench测试---------------------\n" +
+	"1 线程测试(单核)得分:      1234\n" +
+	"4 线程测试(多核)得分:      4567.5\n" +
+	"---------------------内存测试-通过stream测试---------------------\n" +
+	"Copy:           23456.7     0.012     0.011     0.013\n" +
+	"Memory Copy Speed (MEMCPY)   :   1.50 GB/s \n" +
+	"---------------------硬盘测试-通过fio测试---------------------\n" +
+	"测试路径      块大小   读测试(IOPS)            写测试(IOPS)            总和(IOPS)\n" +
+	"/root         4k       90.50 MB/s(22.6K)       90.74 MB/s(22.7K)       181.24 MB/s(45.3K)\n" +
+	"---------------------跨国平台解锁---------------------\n" +
+	"Netflix                   \x1b[32mYES (Region: US)\x1b[0m\n" +
+	"TikTok                    Failed (Network Error)\n" +
+	"---------------------IP质量检测---------------------\n" +
+	"声誉(越高越好):          0 [8]\n" +
+	"---------------------就近节点测速---------------------\n" +
+	" 位置            上传速度        下载速度        延迟            丢包率\n" +
+	" Speedtest.net   8975.45 Mbps    9032.29 Mbps    0.55 ms         0.0%\n" +
+	"------------------------------------------------------------\n" +
+	"Copy:           1.0\n"
+
+func TestParseExtractsSections(t *testing.T) {
+	report := Parse(sampleOutput)
+
+	if len(report.CPU) != 2 || report.CPU[0].Threads != 1 || report.CPU[0].Score != 1234 || report.CPU[1].Score != 4567.5 {
+		t.Fatalf("CPU = %#v", report.CPU)
+	}
+	if len(report.Memory) != 2 || report.Memory[0].MBps != 23456.7 || report.Memory[1].MBps != 1536 {
+		t.Fatalf("Memory = %#v", report.Memory)
+	}
+	if len(report.Disk) != 1 {
+		t.Fatalf("Disk = %#v", report.Disk)
+	}
+	disk := report.Disk[0]
+	if disk.Path != "/root" || disk.Block != "4k" || disk.Read.IOPS != 22600 || disk.Total.MBps != 181.24 {
+		t.Fatalf("Disk[0] = %#v", disk)
+	}
+	if len(report.Unlock) != 2 || report.Unlock[0].Status != "YES" || report.Unlock[0].Region != "US" || report.Unlock[1].Status != "Failed" {
+		t.Fatalf("Unlock = %#v", report.Unlock)
+	}
+	if len(report.IPQuality) != 1 || report.IPQuality[0].Value != "0 [8]" {
+		t.Fatalf("IPQuality = %#v", report.IPQuality)
+	}
+	if len(report.Speed) != 1 || report.Speed[0].DownloadMbps != 9032.29 || report.Speed[0].PacketLoss != "0.0%" {
+		t.Fatalf("Speed = %#v", report.Speed)
+	}
+}
+
+func TestParseDiskFileSize(t *testing.T) {
+	report := Parse("---------------------硬盘测试-通过fio测试---------------------\n" +
+		" 测试文件大小: 1G（剩余空间不足，已由 2G 缩小）\n" +
+		"/root         4k       90.50 MB/s(22.6K)       90.74 MB/s(22.7K)       181.24 MB/s(45.3K)\n")
+	if report.DiskFileSize != "1G" || report.DiskShrunkFrom != "2G" || len(report.Disk) != 1 {
+		t.Fatalf("report = %#v", report)
+	}
+	out, err := Encode(report, FormatMarkdown)
+	if err != nil || !strings.Contains(string(out), "Disk (test file 1G, shrunk from 2G for low free space)") {
+		t.Fatalf("Encode() = %s, %v", out, err)
+	}
+
+	report = Parse("------Disk-Test--fio-Method------\n Test file size: 512M\n")
+	if report.DiskFileSize != "512M" || report.DiskShrunkFrom != "" {
+		t.Fatalf("report = %#v", report)
+	}
+}
+
+func TestParseGeekbenchLinks(t *testing.T) {
+	report := Parse("------CPU-Test--geekbench-Method------\n" +
+		"Geekbench 6.3.0 Tryout\n" +
+		"Single-Core Score: 1234\n" +
+		"Multi-Core Score: 4567\n" +
+		"Link: https://browser.geekbench.com/v6/cpu/1\n" +
+		"Claim: https://browser.geekbench.com/v6/cpu/1/claim?key=2\n")
+	if len(report.CPU) != 2 || report.GeekbenchLink != "https://browser.geekbench.com/v6/cpu/1" || report.GeekbenchClaim != "https://browser.geekbench.com/v6/cpu/1/claim?key=2" {
+		t.Fatalf("report = %#v", report)
+	}
+}
+
+func TestParseIgnoresUnknownOutput(t *testing.T) {
+	if report := Parse("hello\nworld\n"); !report.Empty() {
+		t.Fatalf("Parse() = %#v, want empty", report)
+	}
+}
+
+func TestDetectSection(t *testing.T) {
+	tests := map[string]Section{
+		"------CPU-Test--geekbench-Method------": SectionCPU,
+		"-----Cross-Border-Platform-Unlock-----": SectionUnlock,
+		"-----------------------------------":    SectionNone,
+	}
+	for line, want := range tests {
+		got, ok := DetectSection(line)
+		if !ok || got != want {
+			t.Fatalf("DetectSection(%q) = %q, %v; want %q", line, got, ok, want)
+		}
+	}
+	if _, ok := DetectSection("Netflix YES"); ok {
+		t.Fatal("plain line detected as section title")
+	}
+}
+
+func TestSpeedStreamParsesChunkedOutput(t *testing.T) {
+	var stream SpeedStream
+	var got []SpeedResult
+	// 逐字节送入，模拟输出在任意位置被切断
+	for i := 0; i < len(sampleOutput); i++ {
+		got = append(got, stream.Feed(sampleOutput[i:i+1])...)
+	}
+	if len(got) != 1 || got[0].Node != "Speedtest.net" || got[0].DownloadMbps != 9032.29 {
+		t.Fatalf("Feed() = %#v", got)
+	}
+
+	stream.Reset()
+	got = stream.Feed("-----就近节点测速-----\n 联通上海  10.00 Mbps  20.00 Mbps  5 ms\r 联通上海  100.00 Mbps  200.00 Mbps  5.1 ms  0.0%\n 电信")
+	if len(got) != 1 || got[0].UploadMbps != 100 || got[0].LatencyMs != 5.1 {
+		t.Fatalf("Feed() with carriage returns = %#v", got)
+	}
+	if got := stream.Feed("广州  50 Mbps  60 Mbps  9 ms\n"); len(got) != 1 || got[0].Node != "电信广州" {
+		t.Fatalf("Feed() continuation = %#v", got)
+	}
+}
+
+func TestParseUnlockGroupsAndStacks(t *testing.T) {
+	output := "---------------------跨国平台解锁---------------------\n" +
+		"============[ IPV4 Multination ]============\n" +
+		"Netflix                   YES (Region: US)\n" +
+		"=====[ Sport ]=====\n" +
+		"Dazn                      NO\n" +
+		"============[ IPV6 Multination ]============\n" +
+		"Netflix                   N/A (No IPv6 Support)\n" +
+		"============[ Hong Kong ]============\n" +
+		"Now E                     Failed (Network Error)\n"
+	got := Parse(output).Unlock
+	want := []struct{ platform, group, stack string }{
+		{"Netflix", "Multination", "IPV4"},
+		{"Dazn", "Sport", "IPV4"},
+		{"Netflix", "Multination", "IPV6"},
+		{"Now E", "Hong Kong", "IPV6"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Unlock = %#v", got)
+	}
+	for i, w := range want {
+		if got[i].Platform != w.platform || got[i].Group != w.group || got[i].Stack != w.stack {
+			t.Errorf("Unlock[%d] = %#v, want %+v", i, got[i], w)
+		}
+	}
+}
+
+func TestParseBacktraceAndRoutes(t *testing.T) {
+	output := "---------------------上游及回程线路检测---------------------\n" +
+		"北京电信v4 219.141.140.10           电信163    [普通线路] 电信CN2GT  [优质线路] \n" +
+		"上海移动v4 211.136.112.200          移动CMIN2  [精品线路] \n" +
+		"广州联通v4 210.21.196.6  检测不到已知线路的ASN\n" +
+		"---------------------三网回程路由检测---------------------\n" +
+		"广州电信 - ICMP v4 - traceroute to 58.60.188.222, 30 hops max, 52 byte packets\n" +
+		"0.52 ms      AS906      [DMIT]             美国, 加利福尼亚, 洛杉矶\n" +
+		"*\n" +
+		"160.01 ms    AS4809     [CN2-BACKBONE]     中国, 广东, 广州\n" +
+		"165.10 ms    *          *                  *\n"
+	report := Parse(output)
+	if len(report.Backtrace) != 3 {
+		t.Fatalf("Backtrace = %#v", report.Backtrace)
+	}
+	bj := report.Backtrace[0]
+	if bj.Destination != "北京电信v4" || bj.Target != "219.141.140.10" || len(bj.Lines) != 2 || bj.Lines[1] != (RouteLine{"电信CN2GT", RouteTierQuality}) || bj.BestRouteTier() != RouteTierQuality {
+		t.Fatalf("Backtrace[0] = %#v", bj)
+	}
+	if report.Backtrace[1].BestRouteTier() != RouteTierPremium || report.Backtrace[2].Error != "检测不到已知线路的ASN" || report.Backtrace[2].BestRouteTier() != "" {
+		t.Fatalf("Backtrace = %#v", report.Backtrace)
+	}
+	if len(report.Routes) != 1 || report.Routes[0].Destination != "广州电信 v4" || report.Routes[0].Target != "58.60.188.222" {
+		t.Fatalf("Routes = %#v", report.Routes)
+	}
+	hops := report.Routes[0].Hops
+	if len(hops) != 2 || hops[0].Owner != "DMIT" || hops[0].Tier != RouteTierOrdinary || hops[1].ASN != "AS4809" || hops[1].Tier != RouteTierPremium || hops[1].RTT != "160.01ms" {
+		t.Fatalf("hops = %#v", hops)
+	}
+}
+
+func TestParseVirtAndCaveats(t *testing.T) {
+	cases := map[string]Virt{
+		"KVM":                            VirtKVM,
+		"OpenVZ (Virutozzo)":             VirtOpenVZ,
+		"LXC (Based on libvirt)":         VirtLXC,
+		"Microsoft Hyper-V":              VirtHyperV,
+		"Windows Subsystem for Linux":    VirtWSL,
+		"Dedicated (No visible signage)": VirtDedicated,
+		"":                               VirtUnknown,
+		"Bochs":                          VirtOther,
+	}
+	for value, want := range cases {
+		if got := ParseVirt(value); got != want {
+			t.Errorf("ParseVirt(%q) = %q, want %q", value, got, want)
+		}
+	}
+
+	report := Parse("-----------系统基础信息-----------\n 虚拟化架构          : OpenVZ (Virutozzo)\n")
+	if report.Virt() != VirtOpenVZ {
+		t.Fatalf("Virt = %q, System = %#v", report.Virt(), report.System)
+	}
+	disk := CaveatsFor(report.Caveats(), SectionDisk)
+	if len(disk) != 1 || disk[0].Key != "openvz_disk" {
+		t.Fatalf("disk caveats = %#v", disk)
+	}
+	if got := CaveatsFor(report.Caveats(), SectionSpeed); len(got) != 0 {
+		t.Fatalf("speed caveats = %#v", got)
+	}
+	if got := Parse("-----------系统基础信息-----------\n VM Type             : KVM\n").Caveats(); len(got) != 0 {
+		t.Fatalf("KVM caveats = %#v", got)
+	}
+}
+
+func TestParseGPUSection(t *testing.T) {
+	report := Parse(`-----------GPU信息-----------
+GPU 0               : Tesla T4
+ 显存               : 15360 MiB
+ 驱动版本            : 535.104.05
+GPU 1               : NVIDIA L4
+hashcat SHA-256 基准（-m 1400）
+hashcat #1          : 5.22 GH/s
+`)
+	if len(report.GPU) != 2 || report.GPU[0] != (GPUInfo{Model: "Tesla T4", Memory: "15360 MiB", Driver: "535.104.05"}) || report.GPU[1].Model != "NVIDIA L4" {
+		t.Fatalf("GPU = %#v", report.GPU)
+	}
+	if len(report.GPUBench) != 1 || report.GPUBench[0].Device != "#1" || report.GPUBench[0].HashesPerSec != 5.22e9 {
+		t.Fatalf("GPUBench = %#v", report.GPUBench)
+	}
+	if report.Empty() {
+		t.Fatal("a report with only GPU results should not be empty")
+	}
+}
@@ -0,0 +1,135 @@
+package results
+
+import (
+	"regexp"
+	"strings"
+)
+
+// RouteTier 是回程线路的等级
+type RouteTier string
+
+const (
+	RouteTierPremium  RouteTier = "premium"  // 精品线路，如 CN2 GIA、CMIN2、CTGNET
+	RouteTierQuality  RouteTier = "quality"  // 优质线路，如 CN2 GT、联通 9929
+	RouteTierOrdinary RouteTier = "ordinary" // 普通线路，如 163、4837、CMI
+)
+
+// RouteLine 是回程检测识别出的一条线路
+type RouteLine struct {
+	Name string    `json:"name"`
+	Tier RouteTier `json:"tier"`
+}
+
+// BacktraceResult 是回程线路检测中一个目的地的结果，如 "北京电信v4"
+type BacktraceResult struct {
+	Destination string      `json:"destination"`
+	Target      string      `json:"target"`
+	Lines       []RouteLine `json:"lines,omitempty"`
+	// Error 为未识别出线路时的提示，如 "检测不到已知线路的ASN"
+	Error string `json:"error,omitempty"`
+}
+
+// RouteHop 是路由追踪中的一跳
+type RouteHop struct {
+	RTT      string    `json:"rtt,omitempty"`
+	ASN      string    `json:"asn,omitempty"`
+	Owner    string    `json:"owner,omitempty"`
+	Location string    `json:"location,omitempty"`
+	Tier     RouteTier `json:"tier"`
+}
+
+// RoutePath 是路由追踪中一个目的地的路径
+type RoutePath struct {
+	Destination string     `json:"destination"`
+	Target      string     `json:"target"`
+	Hops        []RouteHop `json:"hops,omitempty"`
+}
+
+var (
+	backtracePattern = regexp.MustCompile(`^(\S+v[46])\s+(\S+)\s+(.+)$`)
+	routeLinePattern = regexp.MustCompile(`(\S+)\s*\[(精品线路|优质线路|普通线路)\]`)
+	routeTitle       = regexp.MustCompile(`^(.+?)\s+-\s+(?:ICMP|TCP|UDP)\s+(v[46])\s+-`)
+	routeTarget      = regexp.MustCompile(`traceroute to (\S+?),`)
+	routeHopPattern  = regexp.MustCompile(`^([\d.]+\s*ms|\*)\s+(AS\d+|\*)\s+(\[[^\]]*\]|\*)\s*(.*)$`)
+)
+
+var routeTierNames = map[string]RouteTier{"精品线路": RouteTierPremium, "优质线路": RouteTierQuality, "普通线路": RouteTierOrdinary}
+
+// premiumASNs 与 nt3 高亮的骨干网一致：CN2、9929、CMIN2、CTGNET 以及联通 10099
+var premiumASNs = map[string]bool{"AS4809": true, "AS9929": true, "AS58807": true, "AS23764": true, "AS10099": true}
+
+func parseBacktraceLine(report *Report, line string) {
+	m := backtracePattern.FindStringSubmatch(line)
+	if m == nil {
+		return
+	}
+	result := BacktraceResult{Destination: m[1], Target: m[2]}
+	for _, route := range routeLinePattern.FindAllStringSubmatch(m[3], -1) {
+		result.Lines = append(result.Lines, RouteLine{Name: route[1], Tier: routeTierNames[route[2]]})
+	}
+	if len(result.Lines) == 0 {
+		result.Error = strings.TrimSpace(m[3])
+	}
+	report.Backtrace = append(report.Backtrace, result)
+}
+
+// routeParser 记录路由追踪输出中尚未出现 "traceroute to" 的目的地标题
+type routeParser struct {
+	title string
+}
+
+func (p *routeParser) ParseLine(report *Report, line string) {
+	if m := routeTitle.FindStringSubmatch(line); m != nil {
+		p.title = m[1] + " " + m[2]
+	}
+	if m := routeTarget.FindStringSubmatch(line); m != nil {
+		report.Routes = append(report.Routes, RoutePath{Destination: p.title, Target: m[1]})
+		p.title = ""
+		return
+	}
+	if len(report.Routes) == 0 {
+		return
+	}
+	m := routeHopPattern.FindStringSubmatch(line)
+	if m == nil || m[2] == "*" && m[3] == "*" {
+		return
+	}
+	hop := RouteHop{RTT: strings.ReplaceAll(m[1], " ", ""), Tier: RouteTierOrdinary}
+	if m[2] != "*" {
+		hop.ASN = m[2]
+	}
+	if m[3] != "*" {
+		hop.Owner = strings.Trim(m[3], "[]")
+	}
+	if location := strings.TrimSpace(m[4]); location != "*" {
+		hop.Location = location
+	}
+	if premiumASNs[hop.ASN] || hop.Owner == "CTG-CN" || hop.Owner == "CMIN2-NET" {
+		hop.Tier = RouteTierPremium
+	}
+	path := &report.Routes[len(report.Routes)-1]
+	path.Hops = append(path.Hops, hop)
+}
+
+// BestRouteTier 返回目的地识别出的最好线路等级，未识别时为空
+func (b BacktraceResult) BestRouteTier() RouteTier {
+	best := RouteTier("")
+	for _, line := range b.Lines {
+		if routeTierRank(line.Tier) > routeTierRank(best) {
+			best = line.Tier
+		}
+	}
+	return best
+}
+
+func routeTierRank(tier RouteTier) int {
+	switch tier {
+	case RouteTierPremium:
+		return 3
+	case RouteTierQuality:
+		return 2
+	case RouteTierOrdinary:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,49 @@
+package results
+
+import "strings"
+
+// SpeedStream 从分块到达的实时输出中逐行解析测速结果，用于测试进行中绘制速度曲线。
+// 不完整的末行会保留到下一次 Feed；零值即可使用，不可并发调用。
+type SpeedStream struct {
+	partial string
+	section Section
+}
+
+// Feed 追加一段输出，返回其中新完成的测速结果
+func (s *SpeedStream) Feed(chunk string) []SpeedResult {
+	text := s.partial + chunk
+	end := strings.LastIndexByte(text, '\n')
+	if end < 0 {
+		s.partial = text
+		return nil
+	}
+	s.partial = text[end+1:]
+	var found []SpeedResult
+	for _, raw := range strings.Split(StripANSI(text[:end]), "\n") {
+		line := strings.TrimRight(raw, "\r")
+		// 测速工具用 \r 原地刷新进度，终端最终显示的是最后一段
+		if i := strings.LastIndexByte(line, '\r'); i >= 0 {
+			line = line[i+1:]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if next, ok := DetectSection(line); ok {
+			s.section = next
+			continue
+		}
+		if s.section != SectionSpeed {
+			continue
+		}
+		var report Report
+		parseSpeedLine(&report, line)
+		found = append(found, report.Speed...)
+	}
+	return found
+}
+
+// Reset 清除未完成的行与当前分区
+func (s *SpeedStream) Reset() {
+	*s = SpeedStream{}
+}
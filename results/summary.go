@@ -0,0 +1,36 @@
+package results
+
+// Headline 是一次运行的关键指标摘要，用于批量测试汇总表等只需一行展示的场景。
+// 各字段为 0 表示报告中缺少对应结果。
+type Headline struct {
+	CPUScore      float64 `json:"cpu_score"`
+	MemoryMBps    float64 `json:"memory_mbps"`
+	DiskReadMBps  float64 `json:"disk_read_mbps"`
+	DiskWriteMBps float64 `json:"disk_write_mbps"`
+	DownloadMbps  float64 `json:"download_mbps"`
+	UploadMbps    float64 `json:"upload_mbps"`
+}
+
+// Summarize 提取报告的关键指标：CPU/内存/硬盘取第一项（通常为单核与 4K 结果），
+// 网络取所有节点中的最大值
+func Summarize(report *Report) Headline {
+	var h Headline
+	if report == nil {
+		return h
+	}
+	if len(report.CPU) > 0 {
+		h.CPUScore = report.CPU[0].Score
+	}
+	if len(report.Memory) > 0 {
+		h.MemoryMBps = report.Memory[0].MBps
+	}
+	if len(report.Disk) > 0 {
+		h.DiskReadMBps = report.Disk[0].Read.MBps
+		h.DiskWriteMBps = report.Disk[0].Write.MBps
+	}
+	for _, s := range report.Speed {
+		h.DownloadMbps = max(h.DownloadMbps, s.DownloadMbps)
+		h.UploadMbps = max(h.UploadMbps, s.UploadMbps)
+	}
+	return h
+}
@@ -0,0 +1,78 @@
+package results
+
+import (
+	"math"
+	"sort"
+)
+
+// TrendMetric 是历史趋势图中的一项指标
+type TrendMetric string
+
+const (
+	TrendCPU      TrendMetric = "cpu"
+	TrendDiskIOPS TrendMetric = "disk_iops"
+	TrendDownload TrendMetric = "download"
+	TrendUpload   TrendMetric = "upload"
+)
+
+// TrendMetrics 是趋势页展示的指标，按展示顺序排列
+var TrendMetrics = []TrendMetric{TrendCPU, TrendDiskIOPS, TrendDownload, TrendUpload}
+
+// TrendValue 提取报告中的趋势指标：CPU 与硬盘取第一项（通常为单核与 4K 结果），
+// 网络取所有节点中的最大值。报告缺少该指标时 ok 为 false。
+func TrendValue(report *Report, metric TrendMetric) (float64, bool) {
+	if report == nil {
+		return 0, false
+	}
+	var value float64
+	switch metric {
+	case TrendCPU:
+		if len(report.CPU) > 0 {
+			value = report.CPU[0].Score
+		}
+	case TrendDiskIOPS:
+		if len(report.Disk) > 0 {
+			value = report.Disk[0].Total.IOPS
+		}
+	case TrendDownload, TrendUpload:
+		h := Summarize(report)
+		value = h.DownloadMbps
+		if metric == TrendUpload {
+			value = h.UploadMbps
+		}
+	}
+	return value, value > 0
+}
+
+// 少于该数量的数据点不做异常判断
+const minAnomalySamples = 4
+
+// Anomalies 标记序列中的离群点：与中位数的偏差超过 3 倍标准化绝对中位差，
+// 且至少偏离中位数 15%（避免非常稳定的序列把微小波动标为异常）
+func Anomalies(values []float64) []bool {
+	flags := make([]bool, len(values))
+	if len(values) < minAnomalySamples {
+		return flags
+	}
+	center := median(values)
+	deviations := make([]float64, len(values))
+	for i, v := range values {
+		deviations[i] = math.Abs(v - center)
+	}
+	// 1.4826 使绝对中位差在正态分布下与标准差一致
+	limit := max(3*1.4826*median(deviations), 0.15*math.Abs(center))
+	for i, d := range deviations {
+		flags[i] = d > limit
+	}
+	return flags
+}
+
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,124 @@
+package results
+
+import (
+	"slices"
+	"strings"
+)
+
+// Virt 是基础信息中虚拟化架构（VM Type）归一化后的类型
+type Virt string
+
+const (
+	VirtUnknown   Virt = ""
+	VirtDedicated Virt = "dedicated"
+	VirtKVM       Virt = "kvm"
+	VirtXen       Virt = "xen"
+	VirtVMware    Virt = "vmware"
+	VirtHyperV    Virt = "hyperv"
+	VirtQEMU      Virt = "qemu"
+	VirtOpenVZ    Virt = "openvz"
+	VirtLXC       Virt = "lxc"
+	VirtDocker    Virt = "docker"
+	VirtWSL       Virt = "wsl"
+	VirtOther     Virt = "other"
+)
+
+// virtFieldNames 是 ecs 基础信息中虚拟化架构一项的名称（中英文输出）
+var virtFieldNames = []string{"VM Type", "虚拟化架构"}
+
+// Caveat 是某种虚拟化下测试结果需要留意的事项，Key 用于界面翻译，Sections 为受影响的分区
+type Caveat struct {
+	Key      string    `json:"key"`
+	Sections []Section `json:"sections"`
+}
+
+// virtCaveats 按虚拟化类型列出注意事项；KVM、Xen 等完整虚拟化与独立服务器没有额外事项
+var virtCaveats = map[Virt][]Caveat{
+	VirtOpenVZ: {
+		{Key: "openvz_disk", Sections: []Section{SectionDisk}},
+		{Key: "openvz_kernel", Sections: []Section{SectionBasic, SectionMemory}},
+	},
+	VirtLXC: {
+		{Key: "lxc_sysctl", Sections: []Section{SectionBasic}},
+		{Key: "lxc_disk", Sections: []Section{SectionDisk}},
+	},
+	VirtDocker: {
+		{Key: "docker_hardware", Sections: []Section{SectionBasic, SectionCPU, SectionMemory, SectionDisk}},
+	},
+	VirtHyperV: {
+		{Key: "hyperv_disk", Sections: []Section{SectionDisk}},
+	},
+	VirtWSL: {
+		{Key: "wsl_network", Sections: []Section{SectionSpeed, SectionPing}},
+		{Key: "wsl_disk", Sections: []Section{SectionDisk}},
+	},
+	VirtQEMU: {
+		{Key: "qemu_cpu", Sections: []Section{SectionCPU}},
+	},
+}
+
+// ParseVirt 把 ecs 输出的虚拟化架构归一化，如 "OpenVZ (Virutozzo)"、"LXC (Based on libvirt)"
+func ParseVirt(value string) Virt {
+	v := strings.ToLower(strings.TrimSpace(value))
+	contains := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(v, w) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case v == "":
+		return VirtUnknown
+	case contains("openvz", "virtuozzo", "virutozzo"):
+		return VirtOpenVZ
+	case contains("lxc"):
+		return VirtLXC
+	case contains("docker", "podman"):
+		return VirtDocker
+	case contains("windows subsystem for linux", "wsl"):
+		return VirtWSL
+	case contains("hyper-v"):
+		return VirtHyperV
+	case contains("kvm"):
+		return VirtKVM
+	case contains("xen"):
+		return VirtXen
+	case contains("vmware"):
+		return VirtVMware
+	case contains("qemu"):
+		return VirtQEMU
+	case contains("dedicated", "physical", "独立服务器"):
+		return VirtDedicated
+	}
+	return VirtOther
+}
+
+// Virt 返回基础信息中的虚拟化类型，未测基础信息时为 VirtUnknown
+func (r *Report) Virt() Virt {
+	for _, field := range r.System {
+		for _, name := range virtFieldNames {
+			if field.Name == name {
+				return ParseVirt(field.Value)
+			}
+		}
+	}
+	return VirtUnknown
+}
+
+// Caveats 返回报告所在虚拟化类型下需要留意的事项
+func (r *Report) Caveats() []Caveat {
+	return virtCaveats[r.Virt()]
+}
+
+// CaveatsFor 返回影响 sections 中任一分区的注意事项
+func CaveatsFor(caveats []Caveat, sections ...Section) []Caveat {
+	var out []Caveat
+	for _, caveat := range caveats {
+		if slices.ContainsFunc(sections, func(s Section) bool { return slices.Contains(caveat.Sections, s) }) {
+			out = append(out, caveat)
+		}
+	}
+	return out
+}
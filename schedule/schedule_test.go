@@ -0,0 +1,97 @@
+package schedule
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/sink"
+)
+
+func TestSpecNext(t *testing.T) {
+	// 2026-03-04 是周三
+	base := time.Date(2026, 3, 4, 10, 17, 30, 0, time.UTC)
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"0 3 * * 0", time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)},
+		{"0 3 * * sun", time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)},
+		{"0 */6 * * *", time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 4, 10, 30, 0, 0, time.UTC)},
+		{"30 2 1 * *", time.Date(2026, 4, 1, 2, 30, 0, 0, time.UTC)},
+		{"0 0 1 * 5", time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2026, 3, 5, 9, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"@every 6h", base.Add(6 * time.Hour)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+	for _, tc := range cases {
+		spec, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("Parse(%q) error = %v", tc.expr, err)
+		}
+		if got := spec.Next(base); !got.Equal(tc.want) {
+			t.Errorf("Parse(%q).Next() = %v, want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "0 25 * * *", "5-1 * * * *", "*/0 * * * *", "@every 10s", "@every soon"} {
+		if _, err := Parse(expr); err == nil {
+			t.Errorf("Parse(%q) accepted", expr)
+		}
+	}
+}
+
+func TestJobNextSkipsMissedRuns(t *testing.T) {
+	now := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	job := Job{Spec: "@every 6h", LastRun: now.Add(-10 * time.Hour)}
+	if next, _ := job.Next(now); !next.Equal(now.Add(6 * time.Hour)) {
+		t.Fatalf("Next() = %v, want missed run skipped", next)
+	}
+	job.LastRun = now.Add(time.Hour)
+	if next, _ := job.Next(now); !next.Equal(now.Add(7 * time.Hour)) {
+		t.Fatalf("Next() = %v, want computed from last run", next)
+	}
+}
+
+func TestStorePersistsJobs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "schedules.json")
+	store, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put(Job{Name: "bad", Spec: "nope", Tests: []string{"cpu"}}); err == nil {
+		t.Fatal("Put() accepted an invalid spec")
+	}
+	if _, err := store.Put(Job{Name: "bad", Spec: "@daily", Tests: []string{"cpu"}, Sink: &sink.Target{Kind: sink.KindInfluxDB, URL: "influx:8086"}}); err == nil {
+		t.Fatal("Put() accepted an invalid sink URL")
+	}
+	pushgateway := &sink.Target{Kind: sink.KindPushgateway, URL: "http://pushgateway:9091"}
+	job, err := store.Put(Job{Name: "weekly", Spec: "0 3 * * 0", Tests: []string{"basic", "cpu"}, Enabled: true, Sink: pushgateway})
+	if err != nil || job.ID == "" {
+		t.Fatalf("Put() = %+v, %v", job, err)
+	}
+	ran := time.Date(2026, 3, 8, 3, 0, 0, 0, time.UTC)
+	if err := store.MarkRun(job.ID, ran); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jobs := reopened.List()
+	if len(jobs) != 1 || jobs[0].Name != "weekly" || !jobs[0].LastRun.Equal(ran) || len(jobs[0].Tests) != 2 || jobs[0].Sink == nil || *jobs[0].Sink != *pushgateway {
+		t.Fatalf("List() = %+v", jobs)
+	}
+	if err := reopened.Delete(job.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := reopened.Delete(job.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Delete(missing) error = %v", err)
+	}
+}
@@ -0,0 +1,172 @@
+// Package schedule 解析类 cron 的定时表达式，并把定时测试任务保存到本地 JSON 文件。
+package schedule
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec 是解析后的定时表达式。支持标准 5 段 cron（分 时 日 月 周）、
+// @hourly/@daily/@weekly/@monthly 以及 "@every 6h" 形式的固定间隔。
+type Spec struct {
+	expr  string
+	every time.Duration
+
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+}
+
+var macros = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+}
+
+var (
+	monthNames = map[string]int{"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6, "jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12}
+	dayNames   = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+)
+
+// Parse 解析定时表达式
+func Parse(expr string) (Spec, error) {
+	expr = strings.TrimSpace(expr)
+	spec := Spec{expr: expr}
+	if rest, ok := strings.CutPrefix(expr, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil {
+			return Spec{}, err
+		}
+		if every < time.Minute {
+			return Spec{}, errors.New("interval must be at least 1m")
+		}
+		spec.every = every
+		return spec, nil
+	}
+	if macro, ok := macros[strings.ToLower(expr)]; ok {
+		expr = macro
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return Spec{}, fmt.Errorf("expected 5 fields (minute hour day month weekday), got %d", len(fields))
+	}
+	var err error
+	if spec.minute, err = parseField(fields[0], 0, 59, nil); err != nil {
+		return Spec{}, fmt.Errorf("minute: %w", err)
+	}
+	if spec.hour, err = parseField(fields[1], 0, 23, nil); err != nil {
+		return Spec{}, fmt.Errorf("hour: %w", err)
+	}
+	if spec.dom, err = parseField(fields[2], 1, 31, nil); err != nil {
+		return Spec{}, fmt.Errorf("day: %w", err)
+	}
+	if spec.month, err = parseField(fields[3], 1, 12, monthNames); err != nil {
+		return Spec{}, fmt.Errorf("month: %w", err)
+	}
+	if spec.dow, err = parseField(fields[4], 0, 7, dayNames); err != nil {
+		return Spec{}, fmt.Errorf("weekday: %w", err)
+	}
+	// 7 与 0 都表示周日
+	if spec.dow&(1<<7) != 0 {
+		spec.dow |= 1
+	}
+	spec.domStar = strings.HasPrefix(fields[2], "*")
+	spec.dowStar = strings.HasPrefix(fields[4], "*")
+	return spec, nil
+}
+
+// parseField 解析一段 cron 字段（逗号分隔的 *、a、a-b，可带 /step），返回取值位图
+func parseField(field string, low, high int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepText, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			value, err := strconv.Atoi(stepText)
+			if err != nil || value <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepText)
+			}
+			step = value
+		}
+		start, end := low, high
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if start, err = parseValue(first, low, high, names); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = parseValue(last, low, high, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = high
+			}
+			if end < start {
+				return 0, fmt.Errorf("invalid range %q", rangePart)
+			}
+		}
+		for value := start; value <= end; value += step {
+			bits |= 1 << value
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(text string, low, high int, names map[string]int) (int, error) {
+	if value, ok := names[strings.ToLower(text)]; ok {
+		return value, nil
+	}
+	value, err := strconv.Atoi(text)
+	if err != nil || value < low || value > high {
+		return 0, fmt.Errorf("value %q out of range %d-%d", text, low, high)
+	}
+	return value, nil
+}
+
+// String 返回原始表达式
+func (s Spec) String() string {
+	return s.expr
+}
+
+// Next 返回 after 之后的下一次触发时间（按 after 所在时区计算）；没有可能的触发时间时返回零值
+func (s Spec) Next(after time.Time) time.Time {
+	if s.every > 0 {
+		return after.Add(s.every)
+	}
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute()+1, 0, 0, after.Location())
+	// 最多向后查找约 5 年，防止 2 月 30 日这类永不触发的表达式死循环
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, t.Location())
+		case !s.dayMatches(t):
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// dayMatches 按 cron 规则匹配日期：日与周都有限定时满足其一即可，否则两者都需满足
+func (s Spec) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
@@ -0,0 +1,177 @@
+package schedule
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/sink"
+)
+
+// ErrNotFound 表示指定的定时任务不存在
+var ErrNotFound = errors.New("scheduled job not found")
+
+// Job 是一条定时测试任务
+type Job struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Spec string `json:"spec"`
+	// Host 为保存的 SSH 主机名称，空表示在本机运行
+	Host    string   `json:"host,omitempty"`
+	Tests   []string `json:"tests"`
+	Enabled bool     `json:"enabled"`
+	// NotifyRegression 为真时，与同一主机的上一次结果相比指标下降超过阈值则发送通知
+	NotifyRegression bool `json:"notify_regression,omitempty"`
+	// Sink 非空时，运行完成后把结构化指标推送到 InfluxDB 或 Pushgateway
+	Sink    *sink.Target `json:"sink,omitempty"`
+	LastRun time.Time    `json:"last_run,omitzero"`
+}
+
+// Next 返回任务在 after 之后的下一次触发时间。
+// 应用未运行期间错过的触发不会补跑：上次运行早于 after 时从 after 开始计算。
+func (j Job) Next(after time.Time) (time.Time, error) {
+	spec, err := Parse(j.Spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if j.LastRun.After(after) {
+		after = j.LastRun
+	}
+	return spec.Next(after), nil
+}
+
+// Store 是保存在单个 JSON 文件中的定时任务列表，可被多个 goroutine 并发使用
+type Store struct {
+	path string
+	mu   sync.Mutex
+	jobs []Job
+}
+
+// Open 读取任务文件，文件不存在时返回空列表
+func Open(path string) (*Store, error) {
+	store := &Store{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return store, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &store.jobs); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return store, nil
+}
+
+// List 返回按名称排序的任务副本
+func (s *Store) List() []Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	jobs := make([]Job, len(s.jobs))
+	for i, job := range s.jobs {
+		job.Tests = append([]string(nil), job.Tests...)
+		if job.Sink != nil {
+			target := *job.Sink
+			job.Sink = &target
+		}
+		jobs[i] = job
+	}
+	sort.SliceStable(jobs, func(i, j int) bool { return jobs[i].Name < jobs[j].Name })
+	return jobs
+}
+
+// Get 按 ID 查找任务
+func (s *Store) Get(id string) (Job, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, job := range s.jobs {
+		if job.ID == id {
+			return job, true
+		}
+	}
+	return Job{}, false
+}
+
+// Put 新增或更新任务（按 ID 匹配，ID 为空时生成新 ID），保存前校验表达式
+func (s *Store) Put(job Job) (Job, error) {
+	job.Name = strings.TrimSpace(job.Name)
+	job.Spec = strings.TrimSpace(job.Spec)
+	if job.Name == "" {
+		return Job{}, errors.New("job name is empty")
+	}
+	if _, err := Parse(job.Spec); err != nil {
+		return Job{}, err
+	}
+	if len(job.Tests) == 0 {
+		return Job{}, errors.New("no tests selected")
+	}
+	if job.Sink != nil {
+		if err := job.Sink.Validate(); err != nil {
+			return Job{}, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job.ID == "" {
+		job.ID = fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	replaced := false
+	for i := range s.jobs {
+		if s.jobs[i].ID == job.ID {
+			s.jobs[i] = job
+			replaced = true
+		}
+	}
+	if !replaced {
+		s.jobs = append(s.jobs, job)
+	}
+	return job, s.saveLocked()
+}
+
+// Delete 删除任务
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.jobs {
+		if s.jobs[i].ID == id {
+			s.jobs = append(s.jobs[:i], s.jobs[i+1:]...)
+			return s.saveLocked()
+		}
+	}
+	return ErrNotFound
+}
+
+// MarkRun 记录任务的运行时间
+func (s *Store) MarkRun(id string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := range s.jobs {
+		if s.jobs[i].ID == id {
+			s.jobs[i].LastRun = at
+			return s.saveLocked()
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *Store) saveLocked() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s.jobs, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
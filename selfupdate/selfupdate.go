@@ -0,0 +1,375 @@
+// Package selfupdate 检查 ecs-gui 的 GitHub 发布，下载并校验本平台的安装包后暂存，
+// 在程序退出时替换当前可执行文件（macOS 为整个 .app 包），下次启动即为新版本。
+package selfupdate
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/childproc"
+	"github.com/oneclickvirt/ecs-gui/internal/ghrelease"
+)
+
+// Repo 是 ecs-gui 发布所在的 GitHub 仓库
+const Repo = "oneclickvirt/ecs-gui"
+
+const (
+	releaseLimit = 20
+	stateFile    = "staged.json"
+	payloadDir   = "payload"
+	appName      = "ecs-gui"
+)
+
+// ErrUnsupported 表示本平台没有可自动安装的发布包，只能打开发布页面手动下载
+var ErrUnsupported = errors.New("no installable release asset for this platform")
+
+// Update 是比当前版本新的发布
+type Update struct {
+	Version string
+	// Notes 为当前版本之后各发布的说明（Markdown），新版本在前
+	Notes string
+	URL   string
+	// Asset 为本平台的安装包，为空时不支持自动安装
+	Asset   string
+	release ghrelease.Release
+}
+
+// Check 返回比 current 新的最新正式发布，已是最新时返回 nil。
+// 按日期命名的自动构建与预发布不参与比较。client 为 nil 时使用 http.DefaultClient。
+func Check(ctx context.Context, client *http.Client, current string) (*Update, error) {
+	return check(ctx, client, current, runtime.GOOS, runtime.GOARCH)
+}
+
+func check(ctx context.Context, client *http.Client, current, goos, goarch string) (*Update, error) {
+	list, err := ghrelease.List(ctx, client, Repo, releaseLimit)
+	if err != nil {
+		return nil, err
+	}
+	list = slices.DeleteFunc(list, func(r ghrelease.Release) bool {
+		return r.Prerelease || !ghrelease.IsVersion(r.Tag) || ghrelease.CompareVersions(r.Tag, current) <= 0
+	})
+	if len(list) == 0 {
+		return nil, nil
+	}
+	slices.SortStableFunc(list, func(a, b ghrelease.Release) int { return ghrelease.CompareVersions(b.Tag, a.Tag) })
+	notes := make([]string, 0, len(list))
+	for _, release := range list {
+		body := strings.TrimSpace(release.Body)
+		if body == "" {
+			body = release.Name
+		}
+		notes = append(notes, "## v"+release.Version()+"\n\n"+body)
+	}
+	latest := list[0]
+	return &Update{
+		Version: latest.Version(),
+		Notes:   strings.Join(notes, "\n\n"),
+		URL:     latest.HTMLURL,
+		Asset:   platformAsset(latest, goos, goarch),
+		release: latest,
+	}, nil
+}
+
+// platformAsset 按 CI 的命名 ecs-gui-<平台>-<架构>-<标签>.<扩展名> 查找本平台的安装包
+func platformAsset(release ghrelease.Release, goos, goarch string) string {
+	var platform, ext string
+	switch goos {
+	case "windows":
+		platform, ext = "windows", ".exe"
+	case "darwin":
+		platform, ext = "macos", ".tar.gz"
+	case "linux":
+		platform, ext = "linux", ".tar.xz"
+	default:
+		return ""
+	}
+	prefix := fmt.Sprintf("%s-%s-%s-", appName, platform, goarch)
+	for _, asset := range release.Assets {
+		if strings.HasPrefix(asset.Name, prefix) && strings.HasSuffix(asset.Name, ext) {
+			return asset.Name
+		}
+	}
+	return ""
+}
+
+// Staged 是已下载、等待退出时安装的更新
+type Staged struct {
+	Version string `json:"version"`
+	// Path 为解压出的可执行文件，macOS 为 ecs-gui.app 目录
+	Path string `json:"path"`
+	// Error 记录上次退出时安装失败的原因
+	Error string `json:"error,omitempty"`
+}
+
+// Stage 下载并校验更新的安装包，解压到 dir 并记录为待安装
+func Stage(ctx context.Context, client *http.Client, dir string, update *Update) (Staged, error) {
+	if update == nil || update.Asset == "" {
+		return Staged{}, ErrUnsupported
+	}
+	payload := filepath.Join(dir, payloadDir)
+	if err := os.RemoveAll(payload); err != nil {
+		return Staged{}, err
+	}
+	if err := os.MkdirAll(payload, 0o755); err != nil {
+		return Staged{}, err
+	}
+	archive := filepath.Join(dir, update.Asset)
+	defer os.Remove(archive)
+	f, err := os.Create(archive)
+	if err != nil {
+		return Staged{}, err
+	}
+	err = ghrelease.Download(ctx, client, update.release, update.Asset, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return Staged{}, err
+	}
+
+	staged := Staged{Version: update.Version}
+	switch {
+	case strings.HasSuffix(update.Asset, ".exe"):
+		staged.Path = filepath.Join(payload, appName+".exe")
+		err = os.Rename(archive, staged.Path)
+	case strings.HasSuffix(update.Asset, ".tar.gz"):
+		if err = extractTarGz(archive, payload); err == nil {
+			staged.Path, err = findPayload(payload, func(path string, d fs.DirEntry) bool {
+				return d.IsDir() && strings.HasSuffix(d.Name(), ".app")
+			})
+		}
+	case strings.HasSuffix(update.Asset, ".tar.xz"):
+		// 标准库不支持 xz，Linux 上交给系统 tar 解压
+		if out, tarErr := childproc.CombinedOutput(exec.CommandContext(ctx, "tar", "-xJf", archive, "-C", payload)); tarErr != nil {
+			err = fmt.Errorf("tar: %w: %s", tarErr, strings.TrimSpace(string(out)))
+		} else {
+			staged.Path, err = findPayload(payload, func(path string, d fs.DirEntry) bool {
+				info, infoErr := d.Info()
+				return infoErr == nil && info.Mode().IsRegular() && d.Name() == appName && info.Mode()&0o111 != 0
+			})
+		}
+	default:
+		err = ErrUnsupported
+	}
+	if err != nil {
+		return Staged{}, err
+	}
+	return staged, saveState(dir, staged)
+}
+
+func findPayload(root string, match func(path string, d fs.DirEntry) bool) (string, error) {
+	var found string
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if match(path, d) {
+			found = path
+			return fs.SkipAll
+		}
+		return nil
+	})
+	if err == nil && found == "" {
+		err = fmt.Errorf("%s not found in release archive", appName)
+	}
+	return found, err
+}
+
+// extractTarGz 把 .tar.gz 解压到 dest，拒绝越出 dest 的路径与链接
+func extractTarGz(archive, dest string) error {
+	f, err := os.Open(archive)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("unsafe path %q in archive", header.Name)
+		}
+		target := filepath.Join(dest, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(target, 0o755)
+		case tar.TypeReg:
+			err = writeFile(target, reader, header.FileInfo().Mode().Perm())
+		case tar.TypeSymlink:
+			if link := filepath.FromSlash(header.Linkname); filepath.IsAbs(link) || !filepath.IsLocal(filepath.Join(filepath.Dir(name), link)) {
+				return fmt.Errorf("unsafe link %q in archive", header.Name)
+			}
+			if err = os.MkdirAll(filepath.Dir(target), 0o755); err == nil {
+				err = os.Symlink(header.Linkname, target)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func writeFile(path string, r io.Reader, mode fs.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func saveState(dir string, staged Staged) error {
+	data, err := json.MarshalIndent(staged, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, stateFile), data, 0o600)
+}
+
+// Pending 返回 dir 中等待安装的更新
+func Pending(dir string) (Staged, bool) {
+	data, err := os.ReadFile(filepath.Join(dir, stateFile))
+	if err != nil {
+		return Staged{}, false
+	}
+	var staged Staged
+	if json.Unmarshal(data, &staged) != nil || staged.Path == "" {
+		return Staged{}, false
+	}
+	if _, err := os.Stat(staged.Path); err != nil {
+		return Staged{}, false
+	}
+	return staged, true
+}
+
+// Discard 删除暂存的更新
+func Discard(dir string) error {
+	return os.RemoveAll(dir)
+}
+
+// InstallTarget 返回更新时要替换的路径：macOS 上为可执行文件所在的 .app 包，其余为可执行文件本身
+func InstallTarget(exe string) string {
+	if resolved, err := filepath.EvalSymlinks(exe); err == nil {
+		exe = resolved
+	}
+	if i := strings.Index(exe, ".app"+string(filepath.Separator)+"Contents"+string(filepath.Separator)); i >= 0 {
+		return exe[:i+len(".app")]
+	}
+	return exe
+}
+
+// Apply 用 dir 中暂存的更新替换 exe 所在的程序，应在程序退出前调用。
+// 旧版本改名为 .old 留到下次启动时由 Cleanup 删除（Windows 无法删除正在运行的程序）；
+// 失败时保留暂存内容并记录原因。
+func Apply(dir, exe string) error {
+	staged, ok := Pending(dir)
+	if !ok {
+		return nil
+	}
+	if err := replace(InstallTarget(exe), staged.Path); err != nil {
+		staged.Error = err.Error()
+		_ = saveState(dir, staged)
+		return err
+	}
+	return Discard(dir)
+}
+
+// Cleanup 删除上次更新留下的旧版本
+func Cleanup(exe string) {
+	_ = os.RemoveAll(InstallTarget(exe) + ".old")
+}
+
+// replace 先把新版本复制到目标旁边，再交换改名，任何一步失败都保留原程序
+func replace(target, src string) error {
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	next, old := target+".new", target+".old"
+	_ = os.RemoveAll(next)
+	_ = os.RemoveAll(old)
+	if err := copyTree(src, next); err != nil {
+		os.RemoveAll(next)
+		return err
+	}
+	if !info.IsDir() {
+		if err := os.Chmod(next, info.Mode().Perm()|0o111); err != nil {
+			os.RemoveAll(next)
+			return err
+		}
+	}
+	if err := os.Rename(target, old); err != nil {
+		os.RemoveAll(next)
+		return err
+	}
+	if err := os.Rename(next, target); err != nil {
+		_ = os.Rename(old, target)
+		return err
+	}
+	return nil
+}
+
+// copyTree 复制文件或目录（保留权限与符号链接），暂存目录与安装位置可能不在同一文件系统
+func copyTree(src, dest string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dest, rel)
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm()|0o700)
+		default:
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			return writeFile(target, f, info.Mode().Perm())
+		}
+	})
+}
@@ -0,0 +1,177 @@
+package selfupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/internal/ghrelease"
+)
+
+func tarGzWith(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	w := tar.NewWriter(gz)
+	for name, content := range files {
+		w.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		w.Write([]byte(content))
+	}
+	w.Close()
+	gz.Close()
+	return buf.Bytes()
+}
+
+// startReleaseServer 模拟 ecs-gui 的发布列表，assets 为各发布包的内容
+func startReleaseServer(t *testing.T, assets map[string][]byte) {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/repos/oneclickvirt/ecs-gui/releases" {
+			var files []ghrelease.Asset
+			for name, data := range assets {
+				sum := sha256.Sum256(data)
+				files = append(files, ghrelease.Asset{Name: name, URL: server.URL + "/dl/" + name, Digest: "sha256:" + hex.EncodeToString(sum[:])})
+			}
+			json.NewEncoder(w).Encode([]ghrelease.Release{
+				{Tag: "v20260301-101010", Body: "nightly"},
+				{Tag: "v0.2.0", Body: "- big feature", HTMLURL: "https://example.test/v0.2.0", Assets: files},
+				{Tag: "v0.3.0-rc1", Prerelease: true},
+				{Tag: "v0.1.175", Name: "Release v0.1.175"},
+				{Tag: "v0.1.174", Body: "current"},
+			})
+			return
+		}
+		if data, ok := assets[strings.TrimPrefix(r.URL.Path, "/dl/")]; ok {
+			w.Write(data)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+	old := ghrelease.APIBaseURL
+	ghrelease.APIBaseURL = server.URL
+	t.Cleanup(func() { ghrelease.APIBaseURL = old })
+}
+
+func TestCheckCollectsNewerReleaseNotes(t *testing.T) {
+	startReleaseServer(t, map[string][]byte{
+		"ecs-gui-windows-amd64-v0.2.0.exe":    []byte("exe"),
+		"ecs-gui-macos-arm64-v0.2.0.tar.gz":   []byte("app"),
+		"ecs-gui-linux-amd64-v0.2.0.tar.xz":   []byte("xz"),
+		"ecs-gui-android-arm64-v0.2.0.apk":    []byte("apk"),
+		"ecs-gui-windows-arm64-v0.2.0.exe.gz": []byte("other"),
+	})
+	update, err := check(context.Background(), nil, "0.1.174", "darwin", "arm64")
+	if err != nil || update == nil {
+		t.Fatalf("check() = %+v, %v", update, err)
+	}
+	if update.Version != "0.2.0" || update.URL != "https://example.test/v0.2.0" || update.Asset != "ecs-gui-macos-arm64-v0.2.0.tar.gz" {
+		t.Fatalf("update = %+v", update)
+	}
+	if want := "## v0.2.0\n\n- big feature\n\n## v0.1.175\n\nRelease v0.1.175"; update.Notes != want {
+		t.Fatalf("notes = %q, want %q", update.Notes, want)
+	}
+	if update, _ := check(context.Background(), nil, "0.1.174", "windows", "arm64"); update.Asset != "" {
+		t.Fatalf("windows/arm64 asset = %q, want none", update.Asset)
+	}
+	if update, err := check(context.Background(), nil, "v0.2.0", "linux", "amd64"); update != nil || err != nil {
+		t.Fatalf("up to date: %+v, %v", update, err)
+	}
+}
+
+func TestStageAndApplyReplacesExecutable(t *testing.T) {
+	startReleaseServer(t, map[string][]byte{"ecs-gui-windows-amd64-v0.2.0.exe": []byte("new build")})
+	update, err := check(context.Background(), nil, "0.1.174", "windows", "amd64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "update")
+	staged, err := Stage(context.Background(), nil, dir, update)
+	if err != nil || staged.Version != "0.2.0" {
+		t.Fatalf("Stage() = %+v, %v", staged, err)
+	}
+	if pending, ok := Pending(dir); !ok || pending.Path != staged.Path {
+		t.Fatalf("Pending() = %+v, %v", pending, ok)
+	}
+
+	exe := filepath.Join(t.TempDir(), "ecs-gui.exe")
+	os.WriteFile(exe, []byte("old build"), 0o755)
+	if err := Apply(dir, exe); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(exe); string(data) != "new build" {
+		t.Fatalf("exe = %q", data)
+	}
+	if _, ok := Pending(dir); ok {
+		t.Fatal("applied update is still pending")
+	}
+	Cleanup(exe)
+	if _, err := os.Stat(exe + ".old"); !os.IsNotExist(err) {
+		t.Fatalf("old build not cleaned up: %v", err)
+	}
+}
+
+func TestApplyRecordsFailure(t *testing.T) {
+	dir := t.TempDir()
+	payload := filepath.Join(dir, "payload", "ecs-gui")
+	os.MkdirAll(filepath.Dir(payload), 0o755)
+	os.WriteFile(payload, []byte("new"), 0o755)
+	if err := saveState(dir, Staged{Version: "0.2.0", Path: payload}); err != nil {
+		t.Fatal(err)
+	}
+	if err := Apply(dir, filepath.Join(t.TempDir(), "missing", "ecs-gui")); err == nil {
+		t.Fatal("Apply() should fail when the executable is missing")
+	}
+	if staged, ok := Pending(dir); !ok || staged.Error == "" {
+		t.Fatalf("Pending() after failure = %+v, %v", staged, ok)
+	}
+}
+
+func TestStageExtractsMacBundle(t *testing.T) {
+	startReleaseServer(t, map[string][]byte{"ecs-gui-macos-arm64-v0.2.0.tar.gz": tarGzWith(t, map[string]string{
+		"ecs-gui.app/Contents/MacOS/ecs-gui": "binary",
+		"ecs-gui.app/Contents/Info.plist":    "plist",
+	})})
+	update, err := check(context.Background(), nil, "0.1.174", "darwin", "arm64")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	staged, err := Stage(context.Background(), nil, dir, update)
+	if err != nil || filepath.Base(staged.Path) != "ecs-gui.app" {
+		t.Fatalf("Stage() = %+v, %v", staged, err)
+	}
+
+	bundle := filepath.Join(t.TempDir(), "ecs-gui.app")
+	exe := filepath.Join(bundle, "Contents", "MacOS", "ecs-gui")
+	os.MkdirAll(filepath.Dir(exe), 0o755)
+	os.WriteFile(exe, []byte("old"), 0o755)
+	if got := InstallTarget(exe); got != bundle {
+		t.Fatalf("InstallTarget() = %q, want %q", got, bundle)
+	}
+	if err := Apply(dir, exe); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(bundle, "Contents", "Info.plist")); string(data) != "plist" {
+		t.Fatalf("bundle not replaced: %q", data)
+	}
+}
+
+func TestExtractTarGzRejectsEscapingPaths(t *testing.T) {
+	archive := filepath.Join(t.TempDir(), "bad.tar.gz")
+	os.WriteFile(archive, tarGzWith(t, map[string]string{"../evil": "x"}), 0o600)
+	if err := extractTarGz(archive, t.TempDir()); err == nil {
+		t.Fatal("extractTarGz() accepted a path outside the destination")
+	}
+}
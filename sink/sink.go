@@ -0,0 +1,133 @@
+// Package sink 把完成运行的结构化指标推送到 InfluxDB（行协议）或 Prometheus Pushgateway，用于长期保存与绘图。
+package sink
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// Kind 是推送目的地类型
+type Kind string
+
+const (
+	KindInfluxDB    Kind = "influxdb"
+	KindPushgateway Kind = "pushgateway"
+)
+
+// Kinds 是支持的类型，按界面展示顺序排列
+var Kinds = []Kind{KindInfluxDB, KindPushgateway}
+
+// DefaultJob 是 Pushgateway 分组的默认 job 名
+const DefaultJob = "goecs"
+
+// Target 是一个推送目的地
+type Target struct {
+	Kind Kind `json:"kind"`
+	// URL 对 InfluxDB 为完整的写入地址（v2 的 /api/v2/write?org=&bucket= 或 v1 的 /write?db=），
+	// 对 Pushgateway 为服务地址，如 http://pushgateway:9091
+	URL string `json:"url"`
+	// Token 非空时随请求发送：InfluxDB 为 "Token <令牌>"，Pushgateway 为 Bearer 令牌
+	Token string `json:"token,omitempty"`
+	// Job 为 Pushgateway 的 job 分组名，为空时使用 DefaultJob
+	Job string `json:"job,omitempty"`
+}
+
+// Validate 检查类型与地址
+func (t Target) Validate() error {
+	switch t.Kind {
+	case KindInfluxDB, KindPushgateway:
+	default:
+		return fmt.Errorf("unknown sink kind %q", t.Kind)
+	}
+	u, err := url.Parse(strings.TrimSpace(t.URL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid %s URL %q", t.Kind, t.URL)
+	}
+	return nil
+}
+
+// Push 推送一台主机的一次结果；报告没有可导出的指标时不发送请求
+func Push(ctx context.Context, client *http.Client, target Target, snapshot results.Snapshot) error {
+	if err := target.Validate(); err != nil {
+		return err
+	}
+	if len(results.Gauges(snapshot.Report)) == 0 {
+		return nil
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var (
+		body     bytes.Buffer
+		method   = http.MethodPost
+		endpoint = strings.TrimSpace(target.URL)
+		auth     string
+		ctype    string
+	)
+	switch target.Kind {
+	case KindInfluxDB:
+		if err := results.WriteInflux(&body, []results.Snapshot{snapshot}); err != nil {
+			return err
+		}
+		u, _ := url.Parse(endpoint)
+		query := u.Query()
+		if query.Get("precision") == "" {
+			query.Set("precision", "s")
+			u.RawQuery = query.Encode()
+		}
+		endpoint, ctype = u.String(), "text/plain; charset=utf-8"
+		if target.Token != "" {
+			auth = "Token " + target.Token
+		}
+	case KindPushgateway:
+		// Pushgateway 不接受样本时间戳，运行时间由 ecs_last_run_timestamp_seconds 表示
+		if err := results.WritePrometheus(&body, []results.Snapshot{snapshot}); err != nil {
+			return err
+		}
+		method, ctype = http.MethodPut, "text/plain; version=0.0.4"
+		endpoint = PushgatewayURL(endpoint, target.Job, snapshot.Host)
+		if target.Token != "" {
+			auth = "Bearer " + target.Token
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", ctype)
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if msg := strings.TrimSpace(string(detail)); msg != "" {
+			return fmt.Errorf("%s: %s: %s", target.Kind, resp.Status, msg)
+		}
+		return fmt.Errorf("%s: %s", target.Kind, resp.Status)
+	}
+	return nil
+}
+
+// PushgatewayURL 返回按 job 与 host 分组的推送地址；host 以 base64 编码，允许包含斜杠等字符
+func PushgatewayURL(base, job, host string) string {
+	if strings.TrimSpace(job) == "" {
+		job = DefaultJob
+	}
+	if host == "" {
+		host = "unknown"
+	}
+	return strings.TrimRight(base, "/") + "/metrics/job/" + url.PathEscape(job) + "/host@base64/" + base64.RawURLEncoding.EncodeToString([]byte(host))
+}
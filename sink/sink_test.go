@@ -0,0 +1,89 @@
+package sink
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+type capturedRequest struct {
+	method, path, query, auth, body string
+}
+
+func captureServer(t *testing.T, status int) (*httptest.Server, *[]capturedRequest) {
+	t.Helper()
+	var got []capturedRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, capturedRequest{r.Method, r.URL.EscapedPath(), r.URL.RawQuery, r.Header.Get("Authorization"), string(body)})
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server, &got
+}
+
+var testSnapshot = results.Snapshot{
+	Host:   "vps 1",
+	Time:   time.Unix(1700000000, 0),
+	Report: &results.Report{CPU: []results.CPUScore{{Label: "1 线程", Score: 1234}}, Disk: []results.DiskResult{{Path: "/root", Block: "4k", Total: results.DiskMetric{IOPS: 5e4}}}},
+}
+
+func TestPushInfluxLineProtocol(t *testing.T) {
+	server, got := captureServer(t, http.StatusNoContent)
+	target := Target{Kind: KindInfluxDB, URL: server.URL + "/api/v2/write?org=o&bucket=b", Token: "tok"}
+	if err := Push(context.Background(), nil, target, testSnapshot); err != nil {
+		t.Fatal(err)
+	}
+	if len(*got) != 1 {
+		t.Fatalf("requests = %+v", *got)
+	}
+	req := (*got)[0]
+	if req.method != http.MethodPost || req.path != "/api/v2/write" || !strings.Contains(req.query, "precision=s") || req.auth != "Token tok" {
+		t.Fatalf("request = %+v", req)
+	}
+	for _, want := range []string{
+		`ecs_cpu_score,host=vps\ 1,test=1\ 线程 value=1234 1700000000`,
+		`ecs_disk_iops,block=4k,host=vps\ 1,op=total,path=/root value=50000 1700000000`,
+	} {
+		if !strings.Contains(req.body, want+"\n") {
+			t.Errorf("body missing %q:\n%s", want, req.body)
+		}
+	}
+}
+
+func TestPushPushgatewayGroupsByHost(t *testing.T) {
+	server, got := captureServer(t, http.StatusOK)
+	target := Target{Kind: KindPushgateway, URL: server.URL + "/", Token: "tok"}
+	if err := Push(context.Background(), nil, target, testSnapshot); err != nil {
+		t.Fatal(err)
+	}
+	req := (*got)[0]
+	if req.method != http.MethodPut || req.path != "/metrics/job/goecs/host@base64/dnBzIDE" || req.auth != "Bearer tok" {
+		t.Fatalf("request = %+v", req)
+	}
+	if !strings.Contains(req.body, `ecs_cpu_score{host="vps 1",test="1 线程"} 1234`) {
+		t.Fatalf("body = %s", req.body)
+	}
+}
+
+func TestPushReportsErrorsAndSkipsEmptyReports(t *testing.T) {
+	server, got := captureServer(t, http.StatusBadRequest)
+	target := Target{Kind: KindInfluxDB, URL: server.URL + "/write?db=ecs"}
+	if err := Push(context.Background(), nil, target, results.Snapshot{Host: "a", Report: &results.Report{}}); err != nil || len(*got) != 0 {
+		t.Fatalf("empty report: err = %v, requests = %d", err, len(*got))
+	}
+	if err := Push(context.Background(), nil, target, testSnapshot); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("Push() error = %v, want HTTP 400", err)
+	}
+	for _, bad := range []Target{{Kind: "graphite", URL: server.URL}, {Kind: KindPushgateway, URL: "pushgateway:9091"}} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Validate(%+v) accepted", bad)
+		}
+	}
+}
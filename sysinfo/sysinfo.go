@@ -0,0 +1,212 @@
+// Package sysinfo 采集测试目标的基础信息（CPU、内存、硬盘、虚拟化、系统、内核、TCP 拥塞控制与队列调度），
+// 本机通过 gopsutil 读取，SSH 目标通过 Script 输出的 key=value 行解析。
+package sysinfo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+	"github.com/shirou/gopsutil/v4/disk"
+	"github.com/shirou/gopsutil/v4/host"
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// Info 是一台主机的基础信息，未能读取的字段为空或 0
+type Info struct {
+	Hostname string
+	CPUModel string
+	Cores    int
+	Memory   uint64
+	// DiskTotal 与 DiskUsed 为系统盘（/ 或 Windows 系统盘）的容量
+	DiskTotal uint64
+	DiskUsed  uint64
+	// Virtualization 为虚拟化类型，物理机为 none
+	Virtualization string
+	OS             string
+	Kernel         string
+	Arch           string
+	TCPCongestion  string
+	// TCPAvailable 为内核当前可用的拥塞控制算法（空格分隔），Qdisc 为默认队列调度算法
+	TCPAvailable string
+	Qdisc        string
+}
+
+// Local 读取本机信息，单项失败不影响其他字段
+func Local(ctx context.Context) Info {
+	var info Info
+	info.Hostname, _ = os.Hostname()
+	if cpus, err := cpu.InfoWithContext(ctx); err == nil && len(cpus) > 0 {
+		info.CPUModel = strings.TrimSpace(cpus[0].ModelName)
+	}
+	info.Cores, _ = cpu.CountsWithContext(ctx, true)
+	if vm, err := mem.VirtualMemoryWithContext(ctx); err == nil {
+		info.Memory = vm.Total
+	}
+	if usage, err := disk.UsageWithContext(ctx, systemRoot()); err == nil {
+		info.DiskTotal, info.DiskUsed = usage.Total, usage.Used
+	}
+	if h, err := host.InfoWithContext(ctx); err == nil {
+		info.OS = strings.TrimSpace(h.Platform + " " + h.PlatformVersion)
+		info.Kernel = h.KernelVersion
+		info.Arch = h.KernelArch
+		info.Virtualization = "none"
+		if h.VirtualizationRole == "guest" && h.VirtualizationSystem != "" {
+			info.Virtualization = h.VirtualizationSystem
+		}
+	}
+	if runtime.GOOS == "linux" {
+		if data, err := os.ReadFile("/proc/sys/net/ipv4/tcp_congestion_control"); err == nil {
+			info.TCPCongestion = strings.TrimSpace(string(data))
+		}
+		if data, err := os.ReadFile("/proc/sys/net/ipv4/tcp_available_congestion_control"); err == nil {
+			info.TCPAvailable = strings.TrimSpace(string(data))
+		}
+		if data, err := os.ReadFile("/proc/sys/net/core/default_qdisc"); err == nil {
+			info.Qdisc = strings.TrimSpace(string(data))
+		}
+	}
+	return info
+}
+
+func systemRoot() string {
+	if runtime.GOOS == "windows" {
+		if drive := os.Getenv("SystemDrive"); drive != "" {
+			return drive + `\`
+		}
+		return `C:\`
+	}
+	return "/"
+}
+
+// Script 是在 SSH 目标上执行的 POSIX shell 脚本，每行输出一个 key=value
+const Script = `echo "host=$(hostname 2>/dev/null)"
+echo "cpu=$(awk -F: '/^(model name|Hardware|cpu model)/{sub(/^[ \t]+/, "", $2); print $2; exit}' /proc/cpuinfo 2>/dev/null)"
+echo "cores=$(nproc 2>/dev/null || getconf _NPROCESSORS_ONLN 2>/dev/null)"
+echo "mem=$(awk '/^MemTotal:/{printf "%.0f", $2 * 1024}' /proc/meminfo 2>/dev/null)"
+echo "disk=$(df -Pk / 2>/dev/null | awk 'NR==2{printf "%.0f %.0f", $2 * 1024, $3 * 1024}')"
+echo "virt=$(systemd-detect-virt 2>/dev/null || virt-what 2>/dev/null | head -n 1)"
+echo "os=$( (. /etc/os-release 2>/dev/null && echo "$PRETTY_NAME") || uname -s)"
+echo "kernel=$(uname -r)"
+echo "arch=$(uname -m)"
+echo "tcpcc=$(cat /proc/sys/net/ipv4/tcp_congestion_control 2>/dev/null)"
+echo "tcpccs=$(cat /proc/sys/net/ipv4/tcp_available_congestion_control 2>/dev/null)"
+echo "qdisc=$(cat /proc/sys/net/core/default_qdisc 2>/dev/null)"
+`
+
+// WindowsScript 是在 Windows 目标上执行的 PowerShell 脚本，输出格式与 Script 相同
+const WindowsScript = `$cs = Get-CimInstance Win32_ComputerSystem
+$os = Get-CimInstance Win32_OperatingSystem
+$cpu = Get-CimInstance Win32_Processor | Select-Object -First 1
+$disk = Get-CimInstance Win32_LogicalDisk -Filter "DeviceID='$env:SystemDrive'"
+"host=$env:COMPUTERNAME"
+"cpu=$($cpu.Name)"
+"cores=$env:NUMBER_OF_PROCESSORS"
+"mem=$($cs.TotalPhysicalMemory)"
+if ($disk) { "disk=$($disk.Size) $($disk.Size - $disk.FreeSpace)" }
+if ($cs.Model -match 'Virtual|VMware|KVM|HVM|Xen|QEMU') { "virt=$($cs.Model)" } else { "virt=none" }
+"os=$($os.Caption)"
+"kernel=$($os.Version)"
+"arch=$env:PROCESSOR_ARCHITECTURE"
+`
+
+// ParseScript 解析 Script 或 WindowsScript 的输出，未知的键被忽略
+func ParseScript(output string) Info {
+	var info Info
+	for _, line := range strings.Split(output, "\n") {
+		key, value, ok := strings.Cut(strings.TrimRight(line, "\r"), "=")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch key {
+		case "host":
+			info.Hostname = value
+		case "cpu":
+			info.CPUModel = value
+		case "cores":
+			info.Cores, _ = strconv.Atoi(value)
+		case "mem":
+			info.Memory, _ = strconv.ParseUint(value, 10, 64)
+		case "disk":
+			if fields := strings.Fields(value); len(fields) == 2 {
+				info.DiskTotal, _ = strconv.ParseUint(fields[0], 10, 64)
+				info.DiskUsed, _ = strconv.ParseUint(fields[1], 10, 64)
+			}
+		case "virt":
+			info.Virtualization = value
+		case "os":
+			info.OS = value
+		case "kernel":
+			info.Kernel = value
+		case "arch":
+			info.Arch = value
+		case "tcpcc":
+			info.TCPCongestion = value
+		case "tcpccs":
+			info.TCPAvailable = value
+		case "qdisc":
+			info.Qdisc = value
+		}
+	}
+	return info
+}
+
+// BBRConfigPath 是启用 BBR 时写入的 sysctl 配置文件，删除后重启即可还原
+const BBRConfigPath = "/etc/sysctl.d/99-ecs-gui-bbr.conf"
+
+// EnableBBRScript 在 Linux 目标上以 root 运行：加载 tcp_bbr 模块并设为开机加载，写入 BBRConfigPath
+// 把默认队列调度设为 fq、拥塞控制设为 bbr 并立即生效；输出 before= 与 after= 两行记录修改前后的取值
+const EnableBBRScript = `set -e
+echo "before=$(cat /proc/sys/net/ipv4/tcp_congestion_control) $(cat /proc/sys/net/core/default_qdisc)"
+modprobe tcp_bbr 2>/dev/null || true
+if [ -d /etc/modules-load.d ]; then echo tcp_bbr > /etc/modules-load.d/ecs-gui-bbr.conf; fi
+printf 'net.core.default_qdisc=fq\nnet.ipv4.tcp_congestion_control=bbr\n' > ` + BBRConfigPath + `
+sysctl -p ` + BBRConfigPath + `
+echo "after=$(cat /proc/sys/net/ipv4/tcp_congestion_control) $(cat /proc/sys/net/core/default_qdisc)"
+`
+
+// BBREnabled 判断当前拥塞控制算法是否为 BBR（包括 bbr2、bbr3 等）
+func (i Info) BBREnabled() bool {
+	return strings.HasPrefix(i.TCPCongestion, "bbr")
+}
+
+// BBRSupported 判断能否在该主机上启用 BBR：可用算法中已有 bbr，或 Linux 内核不低于 4.9（tcp_bbr 模块尚未加载）；
+// 读不到拥塞控制算法时（如 Windows）视为不支持
+func (i Info) BBRSupported() bool {
+	if i.TCPCongestion == "" {
+		return false
+	}
+	for _, name := range strings.Fields(i.TCPAvailable) {
+		if name == "bbr" {
+			return true
+		}
+	}
+	var major, minor int
+	if _, err := fmt.Sscanf(i.Kernel, "%d.%d", &major, &minor); err != nil {
+		return false
+	}
+	return major > 4 || major == 4 && minor >= 9
+}
+
+// FormatBytes 以 1024 为进制输出 "7.75 GiB" 这类容量，0 输出空字符串
+func FormatBytes(n uint64) string {
+	if n == 0 {
+		return ""
+	}
+	units := []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+	value := float64(n)
+	unit := 0
+	for value >= 1024 && unit < len(units)-1 {
+		value /= 1024
+		unit++
+	}
+	if unit == 0 {
+		return fmt.Sprintf("%d B", n)
+	}
+	return fmt.Sprintf("%.2f %s", value, units[unit])
+}
@@ -0,0 +1,70 @@
+package sysinfo
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"testing"
+)
+
+func TestParseScript(t *testing.T) {
+	info := ParseScript("host=vps-1\r\ncpu=AMD EPYC 7763 64-Core Processor\ncores=4\nmem=8221798400\n" +
+		"disk=42140479488 12884901888\nvirt=kvm\nos=Debian GNU/Linux 12 (bookworm)\nkernel=6.1.0-18-amd64\n" +
+		"arch=x86_64\ntcpcc=bbr\ntcpccs=reno cubic bbr\nqdisc=fq\nnoise\nunknown=1\n")
+	want := Info{
+		Hostname: "vps-1", CPUModel: "AMD EPYC 7763 64-Core Processor", Cores: 4, Memory: 8221798400,
+		DiskTotal: 42140479488, DiskUsed: 12884901888, Virtualization: "kvm",
+		OS: "Debian GNU/Linux 12 (bookworm)", Kernel: "6.1.0-18-amd64", Arch: "x86_64", TCPCongestion: "bbr",
+		TCPAvailable: "reno cubic bbr", Qdisc: "fq",
+	}
+	if info != want {
+		t.Fatalf("ParseScript() = %+v", info)
+	}
+}
+
+func TestScriptRunsLocally(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("script targets Linux hosts")
+	}
+	out, err := exec.Command("sh", "-c", Script).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := ParseScript(string(out))
+	if info.Cores <= 0 || info.Memory == 0 || info.Kernel == "" {
+		t.Fatalf("ParseScript(local) = %+v\n%s", info, out)
+	}
+	local := Local(context.Background())
+	if local.Kernel != info.Kernel || local.Cores <= 0 {
+		t.Fatalf("Local() = %+v, script = %+v", local, info)
+	}
+}
+
+func TestBBRSupport(t *testing.T) {
+	cases := []struct {
+		info      Info
+		supported bool
+	}{
+		{Info{Kernel: "6.1.0-18-amd64", TCPCongestion: "cubic", TCPAvailable: "reno cubic"}, true},
+		{Info{Kernel: "4.4.0-210-generic", TCPCongestion: "cubic", TCPAvailable: "reno cubic"}, false},
+		{Info{Kernel: "4.4.0-210-generic", TCPCongestion: "cubic", TCPAvailable: "reno cubic bbr"}, true},
+		{Info{Kernel: "10.0.20348", TCPCongestion: ""}, false},
+	}
+	for _, c := range cases {
+		if got := c.info.BBRSupported(); got != c.supported {
+			t.Errorf("BBRSupported(%+v) = %v", c.info, got)
+		}
+	}
+	if !(Info{TCPCongestion: "bbr2"}).BBREnabled() || (Info{TCPCongestion: "cubic"}).BBREnabled() {
+		t.Fatal("unexpected BBREnabled result")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	cases := map[uint64]string{0: "", 512: "512 B", 1536: "1.50 KiB", 8 << 30: "8.00 GiB"}
+	for n, want := range cases {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
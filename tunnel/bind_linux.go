@@ -0,0 +1,14 @@
+//go:build linux
+
+package tunnel
+
+import "syscall"
+
+// bindControl 在连接前设置 SO_BINDTODEVICE；没有 CAP_NET_RAW 时失败会被忽略，仍依靠源地址选路
+func bindControl(name string) func(network, address string, conn syscall.RawConn) error {
+	return func(network, address string, conn syscall.RawConn) error {
+		return conn.Control(func(fd uintptr) {
+			_ = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, name)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package tunnel
+
+import "syscall"
+
+// bindControl 在其他系统上只依靠源地址选路
+func bindControl(string) func(network, address string, conn syscall.RawConn) error {
+	return nil
+}
@@ -0,0 +1,156 @@
+// Package tunnel 把网络测试绑定到指定网卡（如 WireGuard 隧道），用于与默认路由的结果对比。
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Interface 是一个可用于绑定的网卡
+type Interface struct {
+	Name  string
+	Addrs []net.IP
+}
+
+// Label 返回 "名称 (地址)"，用于选择框
+func (i Interface) Label() string {
+	if len(i.Addrs) == 0 {
+		return i.Name
+	}
+	return fmt.Sprintf("%s (%s)", i.Name, i.Addrs[0])
+}
+
+// Interfaces 列出已启用、非回环且有单播地址的网卡
+func Interfaces() ([]Interface, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var list []Interface
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		if ips := addrsOf(iface); len(ips) > 0 {
+			list = append(list, Interface{Name: iface.Name, Addrs: ips})
+		}
+	}
+	return list, nil
+}
+
+// addrsOf 返回网卡的单播地址，IPv4 在前，跳过链路本地地址
+func addrsOf(iface net.Interface) []net.IP {
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil
+	}
+	var v4, v6 []net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() || ipNet.IP.IsLoopback() {
+			continue
+		}
+		if ipNet.IP.To4() != nil {
+			v4 = append(v4, ipNet.IP)
+		} else {
+			v6 = append(v6, ipNet.IP)
+		}
+	}
+	return append(v4, v6...)
+}
+
+// LocalIP 返回网卡 name 的首选地址（优先 IPv4）
+func LocalIP(name string) (net.IP, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if iface.Flags&net.FlagUp == 0 {
+		return nil, fmt.Errorf("interface %s is down", name)
+	}
+	ips := addrsOf(*iface)
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("interface %s has no usable address", name)
+	}
+	return ips[0], nil
+}
+
+// Dialer 返回从网卡 name 发起连接的 Dialer：源地址设为网卡地址，Linux 上同时绑定设备，
+// 使策略路由（如 wg-quick 的 fwmark 规则）之外的流量也走该网卡
+func Dialer(name string, timeout time.Duration) (*net.Dialer, error) {
+	ip, err := LocalIP(name)
+	if err != nil {
+		return nil, err
+	}
+	return &net.Dialer{
+		Timeout:   timeout,
+		LocalAddr: &net.TCPAddr{IP: ip},
+		Control:   bindControl(name),
+	}, nil
+}
+
+// HTTPClient 返回使用 dialer 且不走代理的 HTTP 客户端；dialer 为 nil 时使用默认路由
+func HTTPClient(dialer *net.Dialer, timeout time.Duration) *http.Client {
+	if dialer == nil {
+		dialer = &net.Dialer{Timeout: 10 * time.Second}
+	}
+	transport := &http.Transport{
+		DialContext:         dialer.DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		DisableKeepAlives:   true,
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
+
+// Download 下载 url 并返回平均速率（Mbps），最多读取 limit 字节
+func Download(ctx context.Context, client *http.Client, url string, limit int64) (float64, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return 0, err
+	}
+	started := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("download: %s", resp.Status)
+	}
+	n, err := io.Copy(io.Discard, io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, errors.New("download: empty response")
+	}
+	seconds := time.Since(started).Seconds()
+	return float64(n) * 8 / 1e6 / seconds, nil
+}
+
+// Row 是对比表中的一项，两列都有值时 Overhead 为隧道相对默认路由的损耗百分比
+type Row struct {
+	Metric  string  `json:"metric"`
+	Unit    string  `json:"unit"`
+	Default float64 `json:"default"`
+	Bound   float64 `json:"bound"`
+	// LowerBetter 为真表示数值越小越好（如延迟），损耗按增加量计算
+	LowerBetter bool   `json:"lower_better,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// Overhead 返回隧道相对默认路由变差的百分比，负数表示更好；缺少任一结果时 ok 为假
+func (r Row) Overhead() (percent float64, ok bool) {
+	if r.Default <= 0 || r.Bound <= 0 {
+		return 0, false
+	}
+	if r.LowerBetter {
+		return (r.Bound - r.Default) / r.Default * 100, true
+	}
+	return (r.Default - r.Bound) / r.Default * 100, true
+}
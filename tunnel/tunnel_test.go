@@ -0,0 +1,63 @@
+package tunnel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRowOverhead(t *testing.T) {
+	cases := []struct {
+		row  Row
+		want float64
+		ok   bool
+	}{
+		{Row{Default: 100, Bound: 80}, 20, true},
+		{Row{Default: 10, Bound: 15, LowerBetter: true}, 50, true},
+		{Row{Default: 10, Bound: 8, LowerBetter: true}, -20, true},
+		{Row{Default: 100}, 0, false},
+	}
+	for _, c := range cases {
+		got, ok := c.row.Overhead()
+		if ok != c.ok || got != c.want {
+			t.Errorf("Overhead(%+v) = %v, %v; want %v, %v", c.row, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestDownloadMeasuresThroughput(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(strings.Repeat("x", 1<<20)))
+	}))
+	defer server.Close()
+	client := HTTPClient(nil, 5*time.Second)
+	mbps, err := Download(context.Background(), client, server.URL, 1<<20)
+	if err != nil || mbps <= 0 {
+		t.Fatalf("Download = %v, %v", mbps, err)
+	}
+	if _, err := Download(context.Background(), client, server.URL+"/missing", 1<<20); err == nil {
+		t.Fatal("Download should fail on 404")
+	}
+}
+
+func TestDialerRejectsUnknownInterface(t *testing.T) {
+	if _, err := Dialer("no-such-iface0", time.Second); err == nil {
+		t.Fatal("Dialer should fail for a missing interface")
+	}
+	interfaces, err := Interfaces()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, iface := range interfaces {
+		if len(iface.Addrs) == 0 || iface.Addrs[0].IsLoopback() {
+			t.Fatalf("Interfaces() returned unusable %+v", iface)
+		}
+	}
+}
@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+)
+
+// ansiColorPrefix 是终端配色在主题中使用的颜色名前缀，
+// CustomTheme 根据该前缀把 ANSI 颜色解析为具体的 color.Color。
+const ansiColorPrefix = "ansi:"
+
+// ansiColorKind 描述前景/背景色的来源
+type ansiColorKind uint8
+
+const (
+	ansiColorDefault ansiColorKind = iota
+	ansiColorIndexed               // 0-255 调色板
+	ansiColorRGB                   // 24 位真彩色
+)
+
+type ansiColor struct {
+	Kind  ansiColorKind
+	Index uint8
+	R     uint8
+	G     uint8
+	B     uint8
+}
+
+// ansiStyle 是一段输出在 SGR 序列作用下的样式
+type ansiStyle struct {
+	FG        ansiColor
+	BG        ansiColor
+	Bold      bool
+	Faint     bool
+	Italic    bool
+	Underline bool
+	Inverse   bool
+}
+
+// ansiSegment 是一段连续且样式相同的文本
+type ansiSegment struct {
+	Text  string
+	Style ansiStyle
+}
+
+// ansiParser 逐块解析带 ANSI 转义序列的文本。
+// 解析状态会跨块保留，因此颜色在多次 AppendText 之间保持连续。
+type ansiParser struct {
+	style   ansiStyle
+	partial string // 被截断在块尾的不完整转义序列
+}
+
+// Parse 将文本拆分为带样式的片段，非 SGR 的 CSI 序列会被丢弃
+func (p *ansiParser) Parse(text string) []ansiSegment {
+	if p.partial != "" {
+		text = p.partial + text
+		p.partial = ""
+	}
+	var segments []ansiSegment
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() == 0 {
+			return
+		}
+		segments = appendANSISegment(segments, ansiSegment{Text: buf.String(), Style: p.style})
+		buf.Reset()
+	}
+	for i := 0; i < len(text); {
+		if text[i] != 0x1b {
+			buf.WriteByte(text[i])
+			i++
+			continue
+		}
+		if i+1 >= len(text) {
+			p.partial = text[i:]
+			break
+		}
+		if text[i+1] != '[' {
+			// 非 CSI 的转义序列（如 ESC c），直接跳过两个字节
+			i += 2
+			continue
+		}
+		end := i + 2
+		for end < len(text) && !isCSIFinalByte(text[end]) {
+			end++
+		}
+		if end >= len(text) {
+			p.partial = text[i:]
+			break
+		}
+		if text[end] == 'm' {
+			flush()
+			p.style = applySGR(p.style, text[i+2:end])
+		}
+		i = end + 1
+	}
+	flush()
+	return segments
+}
+
+// Reset 清空解析状态
+func (p *ansiParser) Reset() {
+	p.style = ansiStyle{}
+	p.partial = ""
+}
+
+func isCSIFinalByte(b byte) bool {
+	return b >= 0x40 && b <= 0x7e
+}
+
+func appendANSISegment(segments []ansiSegment, seg ansiSegment) []ansiSegment {
+	if seg.Text == "" {
+		return segments
+	}
+	if n := len(segments); n > 0 && segments[n-1].Style == seg.Style {
+		segments[n-1].Text += seg.Text
+		return segments
+	}
+	return append(segments, seg)
+}
+
+// parseANSI 一次性解析完整文本
+func parseANSI(text string) []ansiSegment {
+	var p ansiParser
+	return p.Parse(text)
+}
+
+// joinANSIText 拼接片段中的纯文本
+func joinANSIText(segments []ansiSegment) string {
+	var b strings.Builder
+	for _, seg := range segments {
+		b.WriteString(seg.Text)
+	}
+	return b.String()
+}
+
+// applySGR 根据 SGR 参数（例如 "1;31"）更新样式
+func applySGR(style ansiStyle, params string) ansiStyle {
+	if params == "" {
+		return ansiStyle{}
+	}
+	codes := strings.Split(strings.ReplaceAll(params, ":", ";"), ";")
+	for i := 0; i < len(codes); i++ {
+		code, err := strconv.Atoi(codes[i])
+		if err != nil {
+			if codes[i] == "" {
+				code = 0
+			} else {
+				continue
+			}
+		}
+		switch {
+		case code == 0:
+			style = ansiStyle{}
+		case code == 1:
+			style.Bold = true
+		case code == 2:
+			style.Faint = true
+		case code == 3:
+			style.Italic = true
+		case code == 4:
+			style.Underline = true
+		case code == 7:
+			style.Inverse = true
+		case code == 22:
+			style.Bold = false
+			style.Faint = false
+		case code == 23:
+			style.Italic = false
+		case code == 24:
+			style.Underline = false
+		case code == 27:
+			style.Inverse = false
+		case code >= 30 && code <= 37:
+			style.FG = ansiColor{Kind: ansiColorIndexed, Index: uint8(code - 30)}
+		case code == 39:
+			style.FG = ansiColor{}
+		case code >= 40 && code <= 47:
+			style.BG = ansiColor{Kind: ansiColorIndexed, Index: uint8(code - 40)}
+		case code == 49:
+			style.BG = ansiColor{}
+		case code >= 90 && code <= 97:
+			style.FG = ansiColor{Kind: ansiColorIndexed, Index: uint8(code - 90 + 8)}
+		case code >= 100 && code <= 107:
+			style.BG = ansiColor{Kind: ansiColorIndexed, Index: uint8(code - 100 + 8)}
+		case code == 38 || code == 48:
+			c, consumed := parseExtendedColor(codes[i+1:])
+			i += consumed
+			if code == 38 {
+				style.FG = c
+			} else {
+				style.BG = c
+			}
+		}
+	}
+	return style
+}
+
+// parseExtendedColor 解析 38/48 后的 "5;n" 或 "2;r;g;b"
+func parseExtendedColor(args []string) (ansiColor, int) {
+	if len(args) == 0 {
+		return ansiColor{}, 0
+	}
+	atoi := func(s string) uint8 {
+		v, _ := strconv.Atoi(s)
+		if v < 0 {
+			v = 0
+		}
+		if v > 255 {
+			v = 255
+		}
+		return uint8(v)
+	}
+	switch args[0] {
+	case "5":
+		if len(args) < 2 {
+			return ansiColor{}, len(args)
+		}
+		return ansiColor{Kind: ansiColorIndexed, Index: atoi(args[1])}, 2
+	case "2":
+		if len(args) < 4 {
+			return ansiColor{}, len(args)
+		}
+		return ansiColor{Kind: ansiColorRGB, R: atoi(args[1]), G: atoi(args[2]), B: atoi(args[3])}, 4
+	}
+	return ansiColor{}, 1
+}
+
+// effectiveForeground 返回实际用于绘制文字的颜色（考虑反显）
+func (s ansiStyle) effectiveForeground() ansiColor {
+	if s.Inverse && s.BG.Kind != ansiColorDefault {
+		return s.BG
+	}
+	return s.FG
+}
+
+// ColorName 返回供 RichText 使用的主题颜色名
+func (c ansiColor) ColorName() fyne.ThemeColorName {
+	switch c.Kind {
+	case ansiColorIndexed:
+		return fyne.ThemeColorName(fmt.Sprintf("%s%d", ansiColorPrefix, c.Index))
+	case ansiColorRGB:
+		return fyne.ThemeColorName(fmt.Sprintf("%s#%02x%02x%02x", ansiColorPrefix, c.R, c.G, c.B))
+	}
+	return theme.ColorNameForeground
+}
+
+// 标准 16 色，分别针对深色与浅色背景调整了亮度以保证可读性
+var (
+	ansiPaletteDark = [16]color.NRGBA{
+		{0x4c, 0x4c, 0x4c, 0xff}, {0xf1, 0x4c, 0x4c, 0xff}, {0x23, 0xd1, 0x8b, 0xff}, {0xf5, 0xf5, 0x43, 0xff},
+		{0x3b, 0x8e, 0xea, 0xff}, {0xd6, 0x70, 0xd6, 0xff}, {0x29, 0xb8, 0xdb, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+		{0x66, 0x66, 0x66, 0xff}, {0xf1, 0x4c, 0x4c, 0xff}, {0x23, 0xd1, 0x8b, 0xff}, {0xf5, 0xf5, 0x43, 0xff},
+		{0x3b, 0x8e, 0xea, 0xff}, {0xd6, 0x70, 0xd6, 0xff}, {0x29, 0xb8, 0xdb, 0xff}, {0xff, 0xff, 0xff, 0xff},
+	}
+	ansiPaletteLight = [16]color.NRGBA{
+		{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x31, 0x31, 0xff}, {0x00, 0xbc, 0x00, 0xff}, {0x94, 0x98, 0x00, 0xff},
+		{0x04, 0x51, 0xa5, 0xff}, {0xbc, 0x05, 0xbc, 0xff}, {0x05, 0x98, 0xbc, 0xff}, {0x55, 0x55, 0x55, 0xff},
+		{0x66, 0x66, 0x66, 0xff}, {0xcd, 0x31, 0x31, 0xff}, {0x14, 0xce, 0x14, 0xff}, {0xb5, 0xba, 0x00, 0xff},
+		{0x04, 0x51, 0xa5, 0xff}, {0xbc, 0x05, 0xbc, 0xff}, {0x05, 0x98, 0xbc, 0xff}, {0xa5, 0xa5, 0xa5, 0xff},
+	}
+)
+
+// ansiThemeColor 解析 ansiColorPrefix 开头的颜色名，ok 为 false 表示不是终端颜色
+func ansiThemeColor(name fyne.ThemeColorName, variant fyne.ThemeVariant) (color.Color, bool) {
+	raw := string(name)
+	if !strings.HasPrefix(raw, ansiColorPrefix) {
+		return nil, false
+	}
+	raw = strings.TrimPrefix(raw, ansiColorPrefix)
+	if strings.HasPrefix(raw, "#") && len(raw) == 7 {
+		v, err := strconv.ParseUint(raw[1:], 16, 32)
+		if err != nil {
+			return nil, false
+		}
+		return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, true
+	}
+	idx, err := strconv.Atoi(raw)
+	if err != nil || idx < 0 || idx > 255 {
+		return nil, false
+	}
+	return ansiIndexedColor(uint8(idx), variant), true
+}
+
+func ansiIndexedColor(idx uint8, variant fyne.ThemeVariant) color.NRGBA {
+	if idx < 16 {
+		if variant == theme.VariantDark {
+			return ansiPaletteDark[idx]
+		}
+		return ansiPaletteLight[idx]
+	}
+	if idx < 232 {
+		// 6x6x6 色立方
+		levels := [6]uint8{0, 95, 135, 175, 215, 255}
+		n := idx - 16
+		return color.NRGBA{R: levels[n/36], G: levels[(n/6)%6], B: levels[n%6], A: 0xff}
+	}
+	gray := 8 + (idx-232)*10
+	return color.NRGBA{R: gray, G: gray, B: gray, A: 0xff}
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/theme"
+)
+
+func TestParseANSIAppliesSGRStyles(t *testing.T) {
+	segments := parseANSI("plain \x1b[1;31mred bold\x1b[0m done \x1b[38;5;208morange\x1b[38;2;1;2;3mrgb\x1b[m")
+	if len(segments) != 5 {
+		t.Fatalf("segments = %#v, want 5", segments)
+	}
+	if segments[0].Text != "plain " || segments[0].Style != (ansiStyle{}) {
+		t.Fatalf("segment 0 = %#v", segments[0])
+	}
+	red := segments[1]
+	if red.Text != "red bold" || !red.Style.Bold || red.Style.FG != (ansiColor{Kind: ansiColorIndexed, Index: 1}) {
+		t.Fatalf("segment 1 = %#v", red)
+	}
+	if segments[2].Style != (ansiStyle{}) {
+		t.Fatalf("reset was not applied: %#v", segments[2])
+	}
+	if segments[3].Style.FG != (ansiColor{Kind: ansiColorIndexed, Index: 208}) {
+		t.Fatalf("256 color = %#v", segments[3].Style.FG)
+	}
+	if segments[4].Style.FG != (ansiColor{Kind: ansiColorRGB, R: 1, G: 2, B: 3}) {
+		t.Fatalf("rgb color = %#v", segments[4].Style.FG)
+	}
+}
+
+func TestANSIParserKeepsStateAcrossChunks(t *testing.T) {
+	var p ansiParser
+	first := p.Parse("\x1b[32mok\x1b[")
+	second := p.Parse("1mbold\x1b[K line")
+	if len(first) != 1 || first[0].Text != "ok" {
+		t.Fatalf("first chunk = %#v", first)
+	}
+	if len(second) != 1 || second[0].Text != "bold line" {
+		t.Fatalf("second chunk = %#v", second)
+	}
+	if !second[0].Style.Bold || second[0].Style.FG.Index != 2 {
+		t.Fatalf("style not carried across chunks: %#v", second[0].Style)
+	}
+}
+
+func TestANSIThemeColorResolvesNames(t *testing.T) {
+	c, ok := ansiThemeColor(ansiColor{Kind: ansiColorRGB, R: 0x12, G: 0x34, B: 0x56}.ColorName(), theme.VariantDark)
+	if !ok || c != (color.NRGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xff}) {
+		t.Fatalf("rgb color = %#v, %v", c, ok)
+	}
+	c, ok = ansiThemeColor(ansiColor{Kind: ansiColorIndexed, Index: 1}.ColorName(), theme.VariantLight)
+	if !ok || c != ansiPaletteLight[1] {
+		t.Fatalf("indexed color = %#v, %v", c, ok)
+	}
+	if _, ok := ansiThemeColor(theme.ColorNameForeground, theme.VariantLight); ok {
+		t.Fatal("foreground should not resolve as ANSI color")
+	}
+}
+
+func TestTerminalGetTextStripsANSI(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("\x1b[31mFAIL\x1b[0m ok\n")
+	if got := ui.Terminal.GetText(); got != "FAIL ok\n" {
+		t.Fatalf("GetText() = %q", got)
+	}
+	if got := ui.Terminal.GetRawText(); got != "\x1b[31mFAIL\x1b[0m ok\n" {
+		t.Fatalf("GetRawText() = %q", got)
+	}
+}
@@ -0,0 +1,434 @@
+package ui
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	apiEnabledPreferenceKey = "api_enabled"
+	apiAddressPreferenceKey = "api_address"
+	apiTokenPreferenceKey   = "api_token"
+	defaultAPIAddress       = "127.0.0.1:8686"
+	// apiRunLimit 是保留的 API 运行记录数，超出后丢弃最早的记录
+	apiRunLimit = 20
+)
+
+// apiRun 是通过本地 API 启动的一次运行，实现 runObserver 以记录输出并通知日志订阅者
+type apiRun struct {
+	id      string
+	tests   []string
+	started time.Time
+
+	mu       sync.Mutex
+	log      strings.Builder
+	status   string // running、done、failed 或 stopped
+	finished time.Time
+	report   *StructuredRunResult
+	changed  chan struct{}
+}
+
+func newAPIRun(id string, tests []string) *apiRun {
+	return &apiRun{id: id, tests: tests, started: time.Now(), status: "running", changed: make(chan struct{})}
+}
+
+func (r *apiRun) Output(text string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.log.WriteString(text)
+	r.notifyLocked()
+}
+
+func (r *apiRun) Finish(status string, report *StructuredRunResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.status = strings.TrimPrefix(status, "status.")
+	if r.status == "" {
+		r.status = "failed"
+	}
+	r.finished = time.Now()
+	r.report = report
+	r.notifyLocked()
+}
+
+func (r *apiRun) notifyLocked() {
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// logSince 返回 offset 字节之后的输出、当前状态与下次变化的通知通道
+func (r *apiRun) logSince(offset int) (chunk, status string, changed <-chan struct{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	text := r.log.String()
+	if offset < len(text) {
+		chunk = text[max(offset, 0):]
+	}
+	return chunk, r.status, r.changed
+}
+
+// apiRunView 是 GET /runs 与 GET /runs/{id} 返回的运行信息
+type apiRunView struct {
+	ID         string               `json:"id"`
+	Status     string               `json:"status"`
+	Tests      []string             `json:"tests,omitempty"`
+	StartedAt  time.Time            `json:"started_at"`
+	FinishedAt *time.Time           `json:"finished_at,omitempty"`
+	Report     *StructuredRunResult `json:"report,omitempty"`
+	Results    *results.Report      `json:"results,omitempty"`
+}
+
+// view 生成运行信息；withResults 为真时附带从输出解析的分类结果
+func (r *apiRun) view(withResults bool) apiRunView {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	view := apiRunView{ID: r.id, Status: r.status, Tests: r.tests, StartedAt: r.started, Report: r.report}
+	if !r.finished.IsZero() {
+		finished := r.finished
+		view.FinishedAt = &finished
+	}
+	if withResults {
+		view.Results = results.Parse(results.StripANSI(r.log.String()))
+	}
+	return view
+}
+
+// apiServer 是本地 HTTP API：POST /runs 启动测试，GET /runs/{id} 返回状态与结构化结果，
+// GET /runs/{id}/log 返回输出，请求 text/event-stream 时以 SSE 持续推送；
+// 开启指标导出时 GET /metrics 以 Prometheus 格式返回各主机最近一次的结果
+type apiServer struct {
+	// start 在界面中启动一次运行，运行输出与结束状态写入 run
+	start func(run *apiRun) error
+
+	// 以下字段由 mu 保护；token 与 metrics 可在服务运行中经 configure 更换
+	mu    sync.Mutex
+	token string
+	// metrics 为 nil 时 /metrics 返回 404
+	metrics func() []results.Snapshot
+	runs    []*apiRun
+	nextID  int
+	server  *http.Server
+	address string
+}
+
+// configure 更换访问令牌与指标来源，对正在服务的监听立即生效
+func (s *apiServer) configure(token string, metrics func() []results.Snapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token, s.metrics = token, metrics
+}
+
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /runs", s.createRun)
+	mux.HandleFunc("GET /runs", s.listRuns)
+	mux.HandleFunc("GET /runs/{id}", s.getRun)
+	mux.HandleFunc("GET /runs/{id}/log", s.getRunLog)
+	mux.HandleFunc("GET /metrics", s.getMetrics)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !s.authorized(r) {
+			writeAPIError(w, http.StatusUnauthorized, errors.New("missing or invalid token"))
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorized 校验 Authorization: Bearer 令牌；浏览器 EventSource 无法设置请求头，也接受 token 查询参数
+func (s *apiServer) authorized(r *http.Request) bool {
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		token = r.URL.Query().Get("token")
+	}
+	s.mu.Lock()
+	want := s.token
+	s.mu.Unlock()
+	return want != "" && subtle.ConstantTimeCompare([]byte(token), []byte(want)) == 1
+}
+
+func (s *apiServer) createRun(w http.ResponseWriter, r *http.Request) {
+	var request struct {
+		Tests []string `json:"tests"`
+	}
+	if err := json.NewDecoder(io.LimitReader(r.Body, 1<<16)).Decode(&request); err != nil && !errors.Is(err, io.EOF) {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.nextID++
+	run := newAPIRun(strconv.Itoa(s.nextID), request.Tests)
+	s.mu.Unlock()
+
+	if err := s.start(run); err != nil {
+		status := http.StatusBadRequest
+		switch {
+		case errors.Is(err, errRunInProgress):
+			status = http.StatusConflict
+		case errors.Is(err, errRunNeedsPrivilege):
+			status = http.StatusForbidden
+		}
+		writeAPIError(w, status, err)
+		return
+	}
+
+	s.mu.Lock()
+	s.runs = append(s.runs, run)
+	if len(s.runs) > apiRunLimit {
+		s.runs = s.runs[len(s.runs)-apiRunLimit:]
+	}
+	s.mu.Unlock()
+
+	w.Header().Set("Location", "/runs/"+run.id)
+	writeAPIJSON(w, http.StatusAccepted, run.view(false))
+}
+
+func (s *apiServer) listRuns(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	runs := append([]*apiRun(nil), s.runs...)
+	s.mu.Unlock()
+	views := make([]apiRunView, 0, len(runs))
+	for i := len(runs) - 1; i >= 0; i-- {
+		views = append(views, runs[i].view(false))
+	}
+	writeAPIJSON(w, http.StatusOK, views)
+}
+
+func (s *apiServer) lookup(w http.ResponseWriter, r *http.Request) *apiRun {
+	id := r.PathValue("id")
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, run := range s.runs {
+		if run.id == id {
+			return run
+		}
+	}
+	writeAPIError(w, http.StatusNotFound, fmt.Errorf("run %q not found", id))
+	return nil
+}
+
+func (s *apiServer) getRun(w http.ResponseWriter, r *http.Request) {
+	if run := s.lookup(w, r); run != nil {
+		writeAPIJSON(w, http.StatusOK, run.view(true))
+	}
+}
+
+// getRunLog 返回运行输出。请求 text/event-stream 时先推送已有输出，再推送新输出，
+// 结束时发送 end 事件；事件 id 为输出的字节偏移，断线重连时按 Last-Event-ID 续传。
+func (s *apiServer) getRunLog(w http.ResponseWriter, r *http.Request) {
+	run := s.lookup(w, r)
+	if run == nil {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok || !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		text, _, _ := run.logSince(0)
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = io.WriteString(w, text)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	offset, _ := strconv.Atoi(r.Header.Get("Last-Event-ID"))
+	for {
+		chunk, status, changed := run.logSince(offset)
+		if chunk != "" {
+			offset += len(chunk)
+			fmt.Fprintf(w, "id: %d\nevent: output\n", offset)
+			for _, line := range strings.Split(chunk, "\n") {
+				fmt.Fprintf(w, "data: %s\n", line)
+			}
+			_, _ = io.WriteString(w, "\n")
+		}
+		if status != "running" {
+			fmt.Fprintf(w, "event: end\ndata: %s\n\n", status)
+			flusher.Flush()
+			return
+		}
+		flusher.Flush()
+		select {
+		case <-changed:
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func writeAPIJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(value)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeAPIJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// listen 在 address 上开始服务；已在该地址服务时保持不变，否则先关闭之前的监听
+func (s *apiServer) listen(address string) error {
+	s.mu.Lock()
+	serving := s.server != nil && s.address == address
+	s.mu.Unlock()
+	if serving {
+		return nil
+	}
+	s.close()
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: s.handler(), ReadHeaderTimeout: 10 * time.Second}
+	s.mu.Lock()
+	s.server, s.address = server, address
+	s.mu.Unlock()
+	go func() { _ = server.Serve(listener) }()
+	return nil
+}
+
+func (s *apiServer) close() {
+	s.mu.Lock()
+	server := s.server
+	s.server = nil
+	s.mu.Unlock()
+	if server != nil {
+		_ = server.Close()
+	}
+}
+
+// apiToken 返回访问令牌，首次使用时随机生成并保存
+func (ui *TestUI) apiToken(regenerate bool) string {
+	prefs := ui.App.Preferences()
+	if token := prefs.String(apiTokenPreferenceKey); token != "" && !regenerate {
+		return token
+	}
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	token := hex.EncodeToString(buf)
+	prefs.SetString(apiTokenPreferenceKey, token)
+	return token
+}
+
+// applyAPISetting 按偏好设置启动、重启或停止本地 API
+func (ui *TestUI) applyAPISetting() error {
+	prefs := ui.App.Preferences()
+	if !prefs.Bool(apiEnabledPreferenceKey) {
+		if ui.api != nil {
+			ui.api.close()
+		}
+		return nil
+	}
+	if ui.api == nil {
+		ui.api = &apiServer{start: ui.startAPIRun}
+	}
+	var metrics func() []results.Snapshot
+	if prefs.Bool(apiMetricsPreferenceKey) {
+		metrics = ui.metricsSnapshots
+	}
+	ui.api.configure(ui.apiToken(false), metrics)
+	return ui.api.listen(prefs.StringWithFallback(apiAddressPreferenceKey, defaultAPIAddress))
+}
+
+// startAPIRun 使用当前表单配置启动一次运行；run.tests 非空时只运行这些测试项，界面中的勾选不变
+func (ui *TestUI) startAPIRun(run *apiRun) error {
+	var (
+		form         executionForm
+		target       *remote.Target
+		targetErr    error
+		remoteBinary string
+	)
+	uiDoAndWait(func() {
+		form = ui.currentExecutionForm()
+		target, targetErr = ui.remoteTarget()
+		remoteBinary = strings.TrimSpace(ui.RemoteBinaryEntry.Text)
+	})
+	if len(run.tests) > 0 {
+		if err := form.selectTests(run.tests); err != nil {
+			return err
+		}
+	}
+	if targetErr != nil {
+		return targetErr
+	}
+	return ui.startBackgroundRun(form, target, remoteBinary, run)
+}
+
+// createAPIRow 生成配置页中的本地 API 开关与设置按钮
+func (ui *TestUI) createAPIRow() fyne.CanvasObject {
+	prefs := ui.App.Preferences()
+	ui.APICheck = widget.NewCheck(ui.tr("check.api"), nil)
+	ui.APICheck.SetChecked(prefs.Bool(apiEnabledPreferenceKey))
+	ui.APICheck.OnChanged = func(on bool) {
+		prefs.SetBool(apiEnabledPreferenceKey, on)
+		if err := ui.applyAPISetting(); err != nil {
+			dialog.ShowError(errors.Join(errors.New(ui.tr("api.start_failed")), err), ui.Window)
+			ui.APICheck.SetChecked(false)
+		}
+	}
+	settings := widget.NewButton(ui.tr("api.settings"), ui.showAPISettings)
+	return container.NewBorder(nil, nil, nil, settings, ui.APICheck)
+}
+
+// showAPISettings 编辑监听地址并查看、复制或重新生成访问令牌
+func (ui *TestUI) showAPISettings() {
+	prefs := ui.App.Preferences()
+	address := widget.NewEntry()
+	address.SetText(prefs.StringWithFallback(apiAddressPreferenceKey, defaultAPIAddress))
+	token := widget.NewEntry()
+	token.SetText(ui.apiToken(false))
+	token.Disable()
+	tokenRow := container.NewBorder(nil, nil, nil,
+		container.NewHBox(
+			widget.NewButton(ui.tr("button.copy"), func() { ui.App.Clipboard().SetContent(token.Text) }),
+			widget.NewButton(ui.tr("api.regenerate"), func() { token.SetText(ui.apiToken(true)) }),
+		),
+		token,
+	)
+	metrics := widget.NewCheck(ui.tr("api.metrics"), nil)
+	metrics.SetChecked(prefs.Bool(apiMetricsPreferenceKey))
+	hint := widget.NewLabel(ui.tr("api.hint") + "\n" + ui.tr("api.metrics_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("api.address"), address),
+		widget.NewFormItem(ui.tr("api.token"), tokenRow),
+		widget.NewFormItem("", metrics),
+		widget.NewFormItem("", hint),
+	}
+	form := dialog.NewForm(ui.tr("api.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		value := strings.TrimSpace(address.Text)
+		if _, _, err := net.SplitHostPort(value); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		prefs.SetString(apiAddressPreferenceKey, value)
+		prefs.SetBool(apiMetricsPreferenceKey, metrics.Checked)
+		if err := ui.applyAPISetting(); err != nil {
+			dialog.ShowError(errors.Join(errors.New(ui.tr("api.start_failed")), err), ui.Window)
+		}
+	}, ui.Window)
+	form.Resize(fyne.NewSize(560, 0))
+	form.Show()
+}
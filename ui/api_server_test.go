@@ -0,0 +1,226 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func newTestAPIServer(t *testing.T, start func(*apiRun) error) (*httptest.Server, *apiServer) {
+	t.Helper()
+	api := &apiServer{token: "secret", start: start}
+	server := httptest.NewServer(api.handler())
+	t.Cleanup(server.Close)
+	return server, api
+}
+
+func apiRequest(t *testing.T, method, url, body string, header map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer secret")
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func TestAPIServerRunLifecycle(t *testing.T) {
+	var started *apiRun
+	server, _ := newTestAPIServer(t, func(run *apiRun) error {
+		started = run
+		return nil
+	})
+
+	resp := apiRequest(t, http.MethodPost, server.URL+"/runs", `{"tests":["cpu"]}`, nil)
+	if resp.StatusCode != http.StatusAccepted || resp.Header.Get("Location") != "/runs/1" {
+		t.Fatalf("POST /runs = %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+	if started == nil || len(started.tests) != 1 || started.tests[0] != "cpu" {
+		t.Fatalf("start called with %+v", started)
+	}
+
+	started.Output("CPU 测试\n单核得分: 1234\n")
+	started.Finish("status.done", &StructuredRunResult{Status: "ok"})
+
+	resp = apiRequest(t, http.MethodGet, server.URL+"/runs/1", "", nil)
+	var view apiRunView
+	if err := json.NewDecoder(resp.Body).Decode(&view); err != nil {
+		t.Fatal(err)
+	}
+	if view.Status != "done" || view.FinishedAt == nil || view.Report == nil || view.Results == nil {
+		t.Fatalf("GET /runs/1 = %+v", view)
+	}
+
+	resp = apiRequest(t, http.MethodGet, server.URL+"/runs/1/log", "", nil)
+	if data, _ := io.ReadAll(resp.Body); !strings.Contains(string(data), "1234") {
+		t.Fatalf("log = %q", data)
+	}
+
+	if resp := apiRequest(t, http.MethodGet, server.URL+"/runs/9", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("unknown run status = %d", resp.StatusCode)
+	}
+}
+
+func TestAPIServerStreamsLogAsSSE(t *testing.T) {
+	runs := make(chan *apiRun, 1)
+	server, _ := newTestAPIServer(t, func(run *apiRun) error {
+		runs <- run
+		return nil
+	})
+	apiRequest(t, http.MethodPost, server.URL+"/runs", "", nil)
+	run := <-runs
+	run.Output("first\n")
+
+	resp := apiRequest(t, http.MethodGet, server.URL+"/runs/1/log", "", map[string]string{"Accept": "text/event-stream"})
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		t.Fatalf("content type = %q", resp.Header.Get("Content-Type"))
+	}
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() string {
+		var event strings.Builder
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("read event: %v (got %q)", err, event.String())
+			}
+			if line == "\n" {
+				return event.String()
+			}
+			event.WriteString(line)
+		}
+	}
+
+	if event := readEvent(); !strings.Contains(event, "data: first\n") {
+		t.Fatalf("first event = %q", event)
+	}
+	run.Output("second\n")
+	if event := readEvent(); !strings.Contains(event, "data: second\n") || !strings.Contains(event, "id: 13\n") {
+		t.Fatalf("second event = %q", event)
+	}
+	run.Finish("status.stopped", nil)
+	if event := readEvent(); event != "event: end\ndata: stopped\n" {
+		t.Fatalf("end event = %q", event)
+	}
+}
+
+func TestAPIServerRejectsUnauthorizedAndBusy(t *testing.T) {
+	server, api := newTestAPIServer(t, func(*apiRun) error { return errRunInProgress })
+
+	resp := apiRequest(t, http.MethodGet, server.URL+"/runs", "", map[string]string{"Authorization": "Bearer wrong"})
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("wrong token status = %d", resp.StatusCode)
+	}
+	if resp := apiRequest(t, http.MethodPost, server.URL+"/runs", "", nil); resp.StatusCode != http.StatusConflict {
+		t.Fatalf("busy status = %d", resp.StatusCode)
+	}
+	api.start = func(*apiRun) error { return errors.New("unknown test") }
+	if resp := apiRequest(t, http.MethodPost, server.URL+"/runs", `{"tests":["gpu"]}`, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("invalid request status = %d", resp.StatusCode)
+	}
+	resp = apiRequest(t, http.MethodGet, server.URL+"/runs?token=secret", "", map[string]string{"Authorization": ""})
+	var views []apiRunView
+	if err := json.NewDecoder(resp.Body).Decode(&views); err != nil || len(views) != 0 {
+		t.Fatalf("runs = %v, %v", views, err)
+	}
+}
+
+func TestStartAPIRunValidatesRequest(t *testing.T) {
+	ui := newTestUIForTest(t)
+	if err := ui.startAPIRun(newAPIRun("1", []string{"gpu"})); err == nil || !strings.Contains(err.Error(), "gpu") {
+		t.Fatalf("unknown test error = %v", err)
+	}
+	ui.Mu.Lock()
+	ui.IsRunning = true
+	ui.Mu.Unlock()
+	if err := ui.startAPIRun(newAPIRun("2", []string{"basic"})); !errors.Is(err, errRunInProgress) {
+		t.Fatalf("busy error = %v", err)
+	}
+	ui.Mu.Lock()
+	ui.IsRunning = false
+	ui.Mu.Unlock()
+}
+
+func TestApplyAPISettingStartsAndStopsServer(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	prefs := ui.App.Preferences()
+	prefs.SetString(apiAddressPreferenceKey, "127.0.0.1:0")
+	prefs.SetBool(apiEnabledPreferenceKey, true)
+	if err := ui.applyAPISetting(); err != nil {
+		t.Fatalf("applyAPISetting() error = %v", err)
+	}
+	t.Cleanup(ui.api.close)
+	if ui.api.server == nil || ui.api.token == "" || ui.api.token != prefs.String(apiTokenPreferenceKey) {
+		t.Fatal("server not started with a persisted token")
+	}
+
+	// 重新生成令牌后无需重启监听即对运行中的服务生效
+	server := ui.api.server
+	token := ui.apiToken(true)
+	if err := ui.applyAPISetting(); err != nil || ui.api.server != server {
+		t.Fatalf("applyAPISetting() = %v, server restarted = %v", err, ui.api.server != server)
+	}
+	request := httptest.NewRequest(http.MethodGet, "/runs", nil)
+	request.Header.Set("Authorization", "Bearer "+token)
+	if !ui.api.authorized(request) {
+		t.Fatal("the regenerated token was not applied")
+	}
+
+	prefs.SetBool(apiEnabledPreferenceKey, false)
+	if err := ui.applyAPISetting(); err != nil || ui.api.server != nil {
+		t.Fatalf("server not stopped: %v", err)
+	}
+}
+
+func TestAPIServerServesLatestMetricsPerHost(t *testing.T) {
+	server, api := newTestAPIServer(t, nil)
+	if resp := apiRequest(t, http.MethodGet, server.URL+"/metrics", "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("GET /metrics disabled = %d, want 404", resp.StatusCode)
+	}
+
+	store, err := history.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	for i, run := range []struct {
+		host, status string
+		score        float64
+	}{{"vps-a", "done", 900}, {"vps-a", "done", 1000}, {"vps-a", "failed", 5}, {"vps-b", "done", 500}} {
+		_, err := store.Save(history.Run{
+			Summary: history.Summary{StartedAt: base.Add(time.Duration(i) * time.Hour), Host: run.host, Status: run.status},
+			Results: &results.Report{CPU: []results.CPUScore{{Label: "1", Score: run.score}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	api.configure("secret", func() []results.Snapshot { return latestHostSnapshots(store) })
+
+	resp := apiRequest(t, http.MethodGet, server.URL+"/metrics", "", nil)
+	body, _ := io.ReadAll(resp.Body)
+	text := string(body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/plain") {
+		t.Fatalf("GET /metrics = %d %q", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(text, `ecs_cpu_score{host="vps-a",test="1"} 1000`) || !strings.Contains(text, `ecs_cpu_score{host="vps-b",test="1"} 500`) || strings.Count(text, "ecs_cpu_score{") != 2 {
+		t.Fatalf("metrics = %s", text)
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/oneclickvirt/ecs-gui/applog"
+)
+
+const (
+	appLogDirName         = "logs"
+	logLevelPreferenceKey = "log_level"
+)
+
+var (
+	uiLog     = applog.For("ui")
+	runnerLog = applog.For("runner")
+)
+
+// setupAppLog 把应用日志写入应用数据目录，并恢复上次选择的记录级别
+func (ui *TestUI) setupAppLog() {
+	if level, ok := applog.ParseLevel(ui.App.Preferences().StringWithFallback(logLevelPreferenceKey, "info")); ok {
+		applog.SetLevel(level)
+	}
+	if err := applog.Setup(ui.appDataDir(appLogDirName)); err != nil {
+		uiLog.Warn("log file unavailable", "err", err)
+	}
+}
+
+// showDebugLog 打开调试日志窗口：查看最近的日志、调整记录级别并复制内容用于反馈问题。
+// 窗口不在界面中显示入口，只能通过快捷键打开。
+func (ui *TestUI) showDebugLog() {
+	if ui.debugLogWindow != nil {
+		ui.debugLogWindow.RequestFocus()
+		return
+	}
+	view := widget.NewMultiLineEntry()
+	view.TextStyle = fyne.TextStyle{Monospace: true}
+	view.Wrapping = fyne.TextWrapOff
+	refresh := func() {
+		lines := applog.Recent()
+		view.SetText(strings.Join(lines, "\n"))
+		view.CursorRow = len(lines)
+	}
+
+	levels := widget.NewSelect(applog.Levels, func(name string) {
+		if level, ok := applog.ParseLevel(name); ok {
+			applog.SetLevel(level)
+			ui.App.Preferences().SetString(logLevelPreferenceKey, name)
+		}
+	})
+	levels.SetSelected(strings.ToLower(applog.Level().String()))
+	refreshButton := widget.NewButtonWithIcon(ui.tr("debug_log.refresh"), theme.ViewRefreshIcon(), refresh)
+	copyButton := widget.NewButtonWithIcon(ui.tr("button.copy"), theme.ContentCopyIcon(), func() {
+		ui.App.Clipboard().SetContent(view.Text)
+	})
+	path := applog.Path()
+	if path == "" {
+		path = ui.tr("debug_log.memory_only")
+	}
+	pathLabel := widget.NewLabel(path)
+	pathLabel.Truncation = fyne.TextTruncateEllipsis
+	header := container.NewBorder(nil, nil,
+		container.NewHBox(widget.NewLabel(ui.tr("debug_log.level")), levels), container.NewHBox(refreshButton, copyButton),
+		container.NewHBox(layout.NewSpacer(), pathLabel))
+
+	win := ui.App.NewWindow(ui.tr("debug_log.title"))
+	win.SetContent(container.NewBorder(header, nil, nil, nil, view))
+	win.Resize(fyne.NewSize(900, 560))
+	win.SetOnClosed(func() { ui.debugLogWindow = nil })
+	ui.debugLogWindow = win
+	refresh()
+	win.Show()
+}
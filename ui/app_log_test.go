@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/applog"
+)
+
+func TestAppLogWritesToDataDirAndDebugWindow(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	t.Cleanup(applog.Close)
+	if got, want := applog.Path(), filepath.Join(ui.appDataDir(appLogDirName), applog.FileName); got != want {
+		t.Fatalf("log path = %q, want %q", got, want)
+	}
+
+	uiLog.Info("debug window test", "marker", 42)
+	ui.showDebugLog()
+	t.Cleanup(func() {
+		if ui.debugLogWindow != nil {
+			ui.debugLogWindow.Close()
+		}
+	})
+	if ui.debugLogWindow == nil {
+		t.Fatal("debug log window should be open")
+	}
+	if got := ui.debugLogWindow.Content(); got == nil {
+		t.Fatal("debug log window has no content")
+	}
+	lines := applog.Recent()
+	if len(lines) == 0 || !strings.Contains(lines[len(lines)-1], "marker=42") {
+		t.Fatalf("recent log lines = %q", lines)
+	}
+}
+
+func TestDebugLogLevelIsRestoredFromPreferences(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	t.Cleanup(func() {
+		applog.SetLevel(slog.LevelInfo)
+		applog.Close()
+	})
+	ui.App.Preferences().SetString(logLevelPreferenceKey, "debug")
+	ui.setupAppLog()
+	if applog.Level() != slog.LevelDebug {
+		t.Fatalf("level = %v, want debug", applog.Level())
+	}
+}
@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/oneclickvirt/ecs-gui/internal/appmeta"
+)
+
+// appDataDir 返回应用数据目录下的子目录，优先使用 Fyne 提供的应用存储根目录
+func (ui *TestUI) appDataDir(sub string) string {
+	if ui.App != nil {
+		if root := ui.App.Storage().RootURI(); root != nil && root.Scheme() == "file" && root.Path() != "" {
+			return filepath.Join(root.Path(), sub)
+		}
+	}
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, appmeta.AppName, sub)
+	}
+	return filepath.Join(os.TempDir(), appmeta.AppName, sub)
+}
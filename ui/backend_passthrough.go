@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// backendEnvKey 是允许的环境变量名
+var backendEnvKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// backendReservedFlags 是界面必须自己控制的 goecs 参数：交互菜单会让后台运行一直等待输入
+var backendReservedFlags = []string{"menu"}
+
+// parseBackendEnv 解析传给 ecs 后端的环境变量，每行一个 KEY=VALUE，空行与 # 开头的行忽略，
+// 同名变量只能出现一次
+func parseBackendEnv(text string) ([]string, error) {
+	var env []string
+	seen := map[string]bool{}
+	for i, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !backendEnvKey.MatchString(key) {
+			return nil, fmt.Errorf("line %d: want KEY=VALUE, got %q", i+1, line)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("line %d: %s is set more than once", i+1, key)
+		}
+		seen[key] = true
+		env = append(env, key+"="+strings.TrimSpace(value))
+	}
+	return env, nil
+}
+
+// parseBackendFlags 按 shell 的规则拆分附加的命令行参数，支持单双引号与反斜杠转义，
+// 第一个参数必须是以 - 开头的参数名
+func parseBackendFlags(text string) ([]string, error) {
+	var (
+		args    []string
+		current strings.Builder
+		quote   rune
+		escaped bool
+		inArg   bool
+	)
+	for _, r := range text {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inArg = true, true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote, inArg = r, true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape in %q", strings.TrimSpace(text))
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		return nil, fmt.Errorf("%q is not a flag, flags start with -", args[0])
+	}
+	for _, arg := range args {
+		if !strings.HasPrefix(arg, "-") {
+			continue
+		}
+		name, _, _ := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		for _, reserved := range backendReservedFlags {
+			if name == reserved {
+				return nil, fmt.Errorf("-%s is controlled by the GUI", name)
+			}
+		}
+	}
+	return args, nil
+}
+
+// withBackendFlags 在界面生成的参数之后追加附加参数，goecs 对重复的参数以后出现的为准
+func withBackendFlags(args []string, config ExecutionConfig) []string {
+	return append(args, config.BackendFlags...)
+}
+
+// localExecutionRunner 通过 Run.Stdin 把界面输入交给本机运行中启动的外部工具。
+// 本机测试在进程内调用 ecs，没有命令行也没有独立的进程环境，附加的环境变量与参数只对 SSH 远程与容器运行生效
+type localExecutionRunner struct {
+	executionRunner
+}
+
+func (runner localExecutionRunner) Run(run *Run) executionOutcome {
+	if len(run.Config.BackendEnv) > 0 || len(run.Config.BackendFlags) > 0 {
+		run.Output(pickLanguage(run.Config.Language,
+			"附加的环境变量与参数只对 SSH 远程与容器运行生效，本机运行已忽略\n",
+			"Extra environment variables and flags only apply to SSH and container runs and were ignored for this local run\n"))
+	}
+	applyMemorySafety(run, localAvailableMemory(run.Context))
+	stdin, closeStdin := inputPipe(run.Input())
+	defer closeStdin()
+	if stdin != nil {
+		run.stdin = stdin
+	}
+	return runner.executionRunner.Run(run)
+}
+
+// backendPassthroughApplies 报告当前目标是否使用附加的环境变量与参数：只有 SSH 远程与容器目标支持
+func (ui *TestUI) backendPassthroughApplies() bool {
+	return ui.remoteEnabled() || ui.dockerTarget != nil
+}
+
+// setBackendPassthrough 保存附加的环境变量与参数，无效的内容按未设置处理
+func (ui *TestUI) setBackendPassthrough(env, flags string) {
+	if _, err := parseBackendEnv(env); err != nil {
+		env = ""
+	}
+	if _, err := parseBackendFlags(flags); err != nil {
+		flags = ""
+	}
+	ui.backendEnv, ui.backendFlags = strings.TrimSpace(env), strings.TrimSpace(flags)
+	ui.refreshBackendSummary()
+}
+
+// backendSummary 返回高级设置卡片中的摘要
+func (ui *TestUI) backendSummary() string {
+	env, _ := parseBackendEnv(ui.backendEnv)
+	flags, _ := parseBackendFlags(ui.backendFlags)
+	if len(env) == 0 && len(flags) == 0 {
+		return ui.tr("backend.none")
+	}
+	return fmt.Sprintf(ui.tr("backend.summary"), len(env), len(flags))
+}
+
+func (ui *TestUI) refreshBackendSummary() {
+	if ui.backendSummaryLabel != nil {
+		ui.backendSummaryLabel.SetText(ui.backendSummary())
+	}
+}
+
+// createAdvancedContent 创建高级设置：附加环境变量与命令行参数的摘要和编辑按钮，以及自动回应规则
+func (ui *TestUI) createAdvancedContent() fyne.CanvasObject {
+	ui.backendSummaryLabel = widget.NewLabel(ui.backendSummary())
+	ui.backendSummaryLabel.Truncation = fyne.TextTruncateEllipsis
+	edit := widget.NewButtonWithIcon(ui.tr("backend.edit"), theme.DocumentCreateIcon(), ui.showBackendPassthrough)
+	replies := widget.NewButtonWithIcon(ui.tr("expect.title"), theme.MailReplyIcon(), ui.showExpectRules)
+	return container.NewBorder(nil, nil, nil, container.NewHBox(edit, replies), ui.backendSummaryLabel)
+}
+
+// showBackendPassthrough 编辑传给 ecs 后端的环境变量与附加参数，用于界面尚未封装的选项
+func (ui *TestUI) showBackendPassthrough() {
+	env := widget.NewMultiLineEntry()
+	env.SetPlaceHolder("HTTP_PROXY=http://127.0.0.1:8080\nNO_PROXY=localhost")
+	env.SetText(ui.backendEnv)
+	env.SetMinRowsVisible(5)
+	env.Validator = func(text string) error {
+		_, err := parseBackendEnv(text)
+		return err
+	}
+	flags := widget.NewEntry()
+	flags.SetPlaceHolder(`-spnum 3 -diskp "/data dir"`)
+	flags.SetText(ui.backendFlags)
+	flags.Validator = func(text string) error {
+		_, err := parseBackendFlags(text)
+		return err
+	}
+	hintText := ui.tr("backend.hint")
+	if !ui.backendPassthroughApplies() {
+		// 本机目标不使用这两项，仍可查看已保存的内容，切换到远程或容器目标后再编辑
+		env.Disable()
+		flags.Disable()
+		hintText = ui.tr("backend.local") + "\n" + hintText
+	}
+	hint := widget.NewLabel(hintText)
+	hint.Wrapping = fyne.TextWrapWord
+	items := []*widget.FormItem{
+		{Text: ui.tr("backend.env"), Widget: env},
+		{Text: ui.tr("backend.flags"), Widget: flags},
+		{Widget: hint},
+	}
+	form := dialog.NewForm(ui.tr("backend.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		ui.setBackendPassthrough(env.Text, flags.Text)
+	}, ui.Window)
+	form.Resize(fyne.NewSize(560, 0))
+	form.Show()
+}
@@ -0,0 +1,95 @@
+package ui
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseBackendEnv(t *testing.T) {
+	env, err := parseBackendEnv("# proxy for downloads\nHTTP_PROXY = http://127.0.0.1:8080\n\nNO_PROXY=localhost\nEMPTY=\n")
+	if err != nil || !slices.Equal(env, []string{"HTTP_PROXY=http://127.0.0.1:8080", "NO_PROXY=localhost", "EMPTY="}) {
+		t.Fatalf("env = %v, err = %v", env, err)
+	}
+	for _, bad := range []string{"NOVALUE", "1ABC=x", "A-B=x", "A=1\nA=2"} {
+		if _, err := parseBackendEnv(bad); err == nil {
+			t.Fatalf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestParseBackendFlags(t *testing.T) {
+	args, err := parseBackendFlags(`-spnum 3 -diskp "/data dir" --note='it is' -x\ y`)
+	if err != nil || !slices.Equal(args, []string{"-spnum", "3", "-diskp", "/data dir", "--note=it is", "-x y"}) {
+		t.Fatalf("args = %q, err = %v", args, err)
+	}
+	if args, err := parseBackendFlags("  "); err != nil || len(args) != 0 {
+		t.Fatalf("empty flags = %q, %v", args, err)
+	}
+	for _, bad := range []string{`-diskp "/data`, "spnum 3", "-menu=true", "--menu"} {
+		if _, err := parseBackendFlags(bad); err == nil {
+			t.Fatalf("%q should be rejected", bad)
+		}
+	}
+}
+
+func TestBackendPassthroughReachesConfig(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.setBackendPassthrough("NO_PROXY=localhost", `-spnum 3`)
+	if ui.backendSummaryLabel == nil || ui.backendSummaryLabel.Text != ui.backendSummary() || ui.backendSummary() == ui.tr("backend.none") {
+		t.Fatal("the advanced card should summarise the settings")
+	}
+	state := ui.snapshotUIState()
+	if state.entries["backendEnv"] != "NO_PROXY=localhost" || state.entries["backendFlags"] != "-spnum 3" {
+		t.Fatalf("entries = %v", state.entries)
+	}
+	config := ui.collectExecutionConfig()
+	if !slices.Equal(config.BackendEnv, []string{"NO_PROXY=localhost"}) || !slices.Equal(config.BackendFlags, []string{"-spnum", "3"}) {
+		t.Fatalf("config env = %v, flags = %v", config.BackendEnv, config.BackendFlags)
+	}
+	args := withBackendFlags(goecsRemoteArgs(config), config)
+	if !strings.HasSuffix(strings.Join(args, " "), " -spnum 3") {
+		t.Fatalf("extra flags should come last: %v", args)
+	}
+
+	ui.setBackendPassthrough("BAD", "-menu")
+	if ui.backendEnv != "" || ui.backendFlags != "" {
+		t.Fatal("invalid settings should be dropped")
+	}
+}
+
+// envRunner 记录运行时进程中 ECS_GUI_ENV 的值
+type envRunner struct{ seen *string }
+
+func (r envRunner) Run(run *Run) executionOutcome {
+	*r.seen = os.Getenv("ECS_GUI_ENV")
+	return executionOutcome{}
+}
+
+func TestLocalRunnerIgnoresBackendPassthrough(t *testing.T) {
+	os.Unsetenv("ECS_GUI_ENV")
+	var output, seen string
+	config := ExecutionConfig{BackendEnv: []string{"ECS_GUI_ENV=1"}, BackendFlags: []string{"-spnum", "3"}}
+	run := newRun(nil, config, func(text string) { output += text }, nil)
+	(localExecutionRunner{envRunner{&seen}}).Run(run)
+	if seen != "" {
+		t.Fatal("local runs must not change the GUI process environment")
+	}
+	if !strings.Contains(output, "ignored") {
+		t.Fatalf("output = %q, the run should say the settings were ignored", output)
+	}
+}
+
+func TestBackendPassthroughDisabledForLocalTarget(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	if ui.backendPassthroughApplies() {
+		t.Fatal("the local target does not use the backend settings")
+	}
+	ui.RemoteEnableCheck.SetChecked(true)
+	if !ui.backendPassthroughApplies() {
+		t.Fatal("SSH targets use the backend settings")
+	}
+}
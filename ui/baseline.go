@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// regressionThresholdPreferenceKey 保存相对基准的回退阈值（百分比）
+const regressionThresholdPreferenceKey = "regression_threshold"
+
+const defaultRegressionThreshold = 20.0
+
+// regressionThreshold 返回相对基准判定为回退的百分比阈值
+func (ui *TestUI) regressionThreshold() float64 {
+	if ui.App == nil {
+		return defaultRegressionThreshold
+	}
+	value := ui.App.Preferences().FloatWithFallback(regressionThresholdPreferenceKey, defaultRegressionThreshold)
+	if value <= 0 || value >= 100 {
+		return defaultRegressionThreshold
+	}
+	return value
+}
+
+// baselineRegressions 比较 current 与主机 host 的基准，返回基准记录与变差超过 threshold 的指标；
+// 主机没有基准时返回 history.ErrNotFound
+func baselineRegressions(store *history.Store, host string, current *results.Report, threshold float64) (history.Run, []results.ComparisonRow, error) {
+	baseline, err := store.Baseline(host)
+	if err != nil {
+		return history.Run{}, nil, err
+	}
+	report := baseline.Results
+	if report == nil {
+		report = results.Parse(baseline.Output)
+	}
+	return baseline, results.Regressions(report, current, threshold), nil
+}
+
+// baselineRegressionText 生成写入终端的回退列表
+func (ui *TestUI) baselineRegressionText(host string, baseline history.Run, rows []results.ComparisonRow, threshold float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "\n⚠ "+ui.tr("baseline.regression_header")+"\n", host, baseline.StartedAt.Local().Format("2006-01-02 15:04"), len(rows), threshold)
+	for _, row := range rows {
+		delta, _ := row.Delta(1)
+		fmt.Fprintf(&b, "  [%s] %s · %s: %s → %s %s (%+.1f%%)\n",
+			ui.tr("results.tab."+string(row.Section)), row.Item, ui.tr("compare.metric."+row.Name),
+			formatResultNumber(row.Values[0].Value), formatResultNumber(row.Values[1].Value), row.Unit, delta)
+	}
+	return b.String()
+}
+
+// checkBaselineRegression 在运行完成后与主机的基准比较，有回退时写入 output 并发送桌面通知，返回回退的指标数
+func (ui *TestUI) checkBaselineRegression(host string, current *results.Report, output func(string)) int {
+	store := ui.historyStoreOrOpen()
+	if store == nil || current == nil {
+		return 0
+	}
+	threshold := ui.regressionThreshold()
+	baseline, rows, err := baselineRegressions(store, host, current, threshold)
+	if err != nil || len(rows) == 0 {
+		return 0
+	}
+	if output != nil {
+		output(ui.baselineRegressionText(host, baseline, rows, threshold))
+	}
+	ui.sendNotification(fmt.Sprintf(ui.tr("baseline.regression_title"), host), fmt.Sprintf(ui.tr("baseline.regression_body"), len(rows), threshold))
+	return len(rows)
+}
+
+// showBaselineDialog 把选中的历史记录设为（或取消）其主机的基准，并设置回退阈值
+func (ui *TestUI) showBaselineDialog() {
+	run, ok := ui.loadSelectedHistory()
+	if !ok {
+		return
+	}
+	if run.Results == nil && len(results.Metrics(results.Parse(run.Output))) == 0 {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("baseline.no_results"), ui.Window)
+		return
+	}
+	host := run.Host
+	if host == "" {
+		host = "-"
+	}
+	mark := widget.NewCheck(fmt.Sprintf(ui.tr("baseline.mark"), host), nil)
+	mark.SetChecked(true)
+	threshold := widget.NewEntry()
+	threshold.SetText(strconv.FormatFloat(ui.regressionThreshold(), 'f', -1, 64))
+	threshold.Validator = func(text string) error {
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), 64)
+		if err != nil || value <= 0 || value >= 100 {
+			return fmt.Errorf("%s", ui.tr("baseline.threshold_invalid"))
+		}
+		return nil
+	}
+	items := []*widget.FormItem{
+		{Text: "", Widget: mark},
+		{Text: ui.tr("baseline.threshold"), Widget: threshold, HintText: ui.tr("baseline.hint")},
+	}
+	form := dialog.NewForm(ui.tr("baseline.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		value, _ := strconv.ParseFloat(strings.TrimSpace(threshold.Text), 64)
+		ui.App.Preferences().SetFloat(regressionThresholdPreferenceKey, value)
+		store := ui.historyStoreOrOpen()
+		if store == nil {
+			return
+		}
+		if mark.Checked != run.Baseline {
+			if err := store.SetBaseline(run.ID, mark.Checked); err != nil {
+				dialog.ShowError(err, ui.Window)
+				return
+			}
+		}
+		ui.reloadHistoryList()
+	}, ui.Window)
+	form.Resize(fyne.NewSize(480, 0))
+	form.Show()
+}
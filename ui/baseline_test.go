@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/notify"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestBaselineRegressionFlagsDropsBeyondThreshold(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		t.Fatal("history store unavailable")
+	}
+	baseline, err := store.Save(history.Run{
+		Summary: history.Summary{StartedAt: time.Now().Add(-time.Hour), Host: "vps", Status: "done"},
+		Results: &results.Report{Disk: []results.DiskResult{{Path: "/root", Block: "4k", Total: results.DiskMetric{IOPS: 40000}}}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := &results.Report{Disk: []results.DiskResult{{Path: "/root", Block: "4k", Total: results.DiskMetric{IOPS: 28000}}}}
+	if count := ui.checkBaselineRegression("vps", current, nil); count != 0 {
+		t.Fatalf("regressions without a baseline = %d", count)
+	}
+	if err := store.SetBaseline(baseline.ID, true); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	if count := ui.checkBaselineRegression("vps", current, func(s string) { out.WriteString(s) }); count != 1 {
+		t.Fatalf("regressions = %d, want the IOPS drop", count)
+	}
+	if !strings.Contains(out.String(), "40000") || !strings.Contains(out.String(), "-30.0%") {
+		t.Fatalf("output = %q", out.String())
+	}
+	ui.App.Preferences().SetFloat(regressionThresholdPreferenceKey, 35)
+	if count := ui.checkBaselineRegression("vps", current, nil); count != 0 {
+		t.Fatalf("regressions at 35%% = %d, want 0", count)
+	}
+
+	channels := []notify.Channel{{Name: "hook", Kind: notify.KindWebhook, URL: "https://example.com", Enabled: true, Events: []notify.Event{notify.EventRegression}}}
+	ui.App.Preferences().SetFloat(regressionThresholdPreferenceKey, 20)
+	msgs := ui.runNotificationMessages("vps", "status.done", time.Minute, current, nil, channels)
+	if len(msgs) != 2 || msgs[1].Event != notify.EventRegression || msgs[1].Summary != fmt.Sprintf(ui.tr("baseline.regression_body"), 1, 20.0) {
+		t.Fatalf("messages = %+v", msgs)
+	}
+
+	ui.reloadHistoryList()
+	if len(ui.historyItems) != 1 || !strings.Contains(ui.historyItemText(ui.historyItems[0]), "★") {
+		t.Fatalf("history items = %+v", ui.historyItems)
+	}
+}
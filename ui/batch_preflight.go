@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// batchProbeTimeout 是批量测试前检查单台主机的时限
+const batchProbeTimeout = 10 * time.Second
+
+// batchHostProbe 检查一台主机能否连接，测试中可替换
+var batchHostProbe = remote.Probe
+
+// batchProbe 是批量测试前对一台主机的连接检查结果
+type batchProbe struct {
+	name    string
+	target  remote.Target
+	latency time.Duration
+	err     error
+}
+
+// probeBatchHosts 并发检查全部主机（同时最多 maxBatchConcurrency 台），结果按输入顺序返回
+func probeBatchHosts(ctx context.Context, hosts []batchProbe, probe func(context.Context, remote.Target) (time.Duration, error)) []batchProbe {
+	results := make([]batchProbe, len(hosts))
+	slots := make(chan struct{}, maxBatchConcurrency)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			slots <- struct{}{}
+			defer func() { <-slots }()
+			probeCtx, cancel := context.WithTimeout(ctx, batchProbeTimeout)
+			defer cancel()
+			host.latency, host.err = probe(probeCtx, host.target)
+			results[i] = host
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// unreachableCount 返回检查失败的主机数
+func unreachableCount(probes []batchProbe) int {
+	n := 0
+	for _, p := range probes {
+		if p.err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// preflightBatch 在后台检查全部主机；都能连接时直接开始批量测试，否则列出结果供排除不可达的主机
+func (ui *TestUI) preflightBatch(hosts []batchProbe, concurrency int) {
+	ctx, cancel := context.WithCancel(context.Background())
+	bar := widget.NewProgressBarInfinite()
+	progress := dialog.NewCustom(ui.tr("batch.preflight_title"), ui.tr("compare.cancel"),
+		container.NewVBox(widget.NewLabel(fmt.Sprintf(ui.tr("batch.preflight_checking"), len(hosts))), bar), ui.Window)
+	progress.SetOnClosed(cancel)
+	progress.Show()
+	go func() {
+		probes := probeBatchHosts(ctx, hosts, batchHostProbe)
+		ui.runOnUI(func() {
+			if ctx.Err() != nil {
+				return
+			}
+			bar.Stop()
+			progress.Hide()
+			if unreachableCount(probes) == 0 {
+				ui.launchBatch(probes, concurrency)
+				return
+			}
+			ui.showPreflightResults(probes, concurrency)
+		})
+	}()
+}
+
+// showPreflightResults 列出每台主机的检查结果，默认只勾选可以连接的主机
+func (ui *TestUI) showPreflightResults(probes []batchProbe, concurrency int) {
+	labels := make([]string, len(probes))
+	byLabel := make(map[string]batchProbe, len(probes))
+	var selected []string
+	for i, p := range probes {
+		if p.err != nil {
+			labels[i] = fmt.Sprintf(ui.tr("batch.preflight_failed"), p.name, p.err)
+		} else {
+			labels[i] = fmt.Sprintf(ui.tr("batch.preflight_ok"), p.name, p.latency.Milliseconds())
+			selected = append(selected, labels[i])
+		}
+		byLabel[labels[i]] = p
+	}
+	group := widget.NewCheckGroup(labels, nil)
+	group.SetSelected(selected)
+	summary := widget.NewLabel(fmt.Sprintf(ui.tr("batch.preflight_summary"), unreachableCount(probes), len(probes)))
+	summary.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(group)
+	scroll.SetMinSize(fyne.NewSize(520, 260))
+
+	dialog.ShowCustomConfirm(ui.tr("batch.preflight_title"), ui.tr("batch.start"), ui.tr("compare.cancel"),
+		container.NewBorder(summary, nil, nil, nil, scroll), func(ok bool) {
+			if !ok {
+				return
+			}
+			var chosen []batchProbe
+			for _, label := range labels {
+				if slices.Contains(group.Selected, label) {
+					chosen = append(chosen, byLabel[label])
+				}
+			}
+			if len(chosen) == 0 {
+				dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("batch.pick_hosts"), ui.Window)
+				return
+			}
+			ui.launchBatch(chosen, concurrency)
+		}, ui.Window)
+}
+
+// launchBatch 打开批量测试窗口并在所选主机上开始执行
+func (ui *TestUI) launchBatch(hosts []batchProbe, concurrency int) {
+	run := ui.newBatchRun(concurrency)
+	for _, host := range hosts {
+		run.addHost(host.name, host.target)
+	}
+	run.showWindow()
+	go run.execute()
+}
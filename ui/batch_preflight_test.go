@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func TestProbeBatchHostsKeepsOrderAndErrors(t *testing.T) {
+	var running, peak atomic.Int32
+	probe := func(ctx context.Context, target remote.Target) (time.Duration, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			old := peak.Load()
+			if n <= old || peak.CompareAndSwap(old, n) {
+				break
+			}
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			t.Error("probe should run with a deadline")
+		}
+		time.Sleep(5 * time.Millisecond)
+		if target.Host == "10.0.0.2" {
+			return 0, errors.New("connection refused")
+		}
+		return 42 * time.Millisecond, nil
+	}
+	var hosts []batchProbe
+	for _, host := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		hosts = append(hosts, batchProbe{name: host, target: remote.Target{Host: host}})
+	}
+	probes := probeBatchHosts(context.Background(), hosts, probe)
+	for i, p := range probes {
+		if p.name != hosts[i].name {
+			t.Fatalf("probes[%d] = %s, want input order", i, p.name)
+		}
+	}
+	if probes[0].latency != 42*time.Millisecond || probes[1].err == nil || probes[2].err != nil {
+		t.Fatalf("probes = %+v", probes)
+	}
+	if got := unreachableCount(probes); got != 1 {
+		t.Fatalf("unreachableCount() = %d, want 1", got)
+	}
+	if peak.Load() < 2 {
+		t.Fatalf("probes ran one at a time (peak %d), want parallel checks", peak.Load())
+	}
+}
@@ -0,0 +1,407 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	defaultBatchConcurrency = 5
+	maxBatchConcurrency     = 20
+)
+
+// batchHost 是批量测试中的一台主机及其独立的终端与进度
+type batchHost struct {
+	name     string
+	target   remote.Target
+	terminal *TerminalOutput
+	progress *widget.ProgressBar
+	status   *widget.Label
+
+	// 以下字段由 batchRun.mu 保护
+	output    strings.Builder
+	statusKey string
+	started   time.Time
+	finished  time.Time
+	report    *results.Report
+	err       error
+}
+
+// batchRun 在多台 SSH 主机上并发执行同一组测试
+type batchRun struct {
+	ui          *TestUI
+	hosts       []*batchHost
+	config      ExecutionConfig
+	concurrency int
+	runner      func(remote.Target) executionRunner
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	mu     sync.Mutex
+
+	// 仅在 UI 线程访问
+	summary    *widget.Table
+	overall    *widget.Label
+	stopButton *widget.Button
+}
+
+// showBatchDialog 选择多台已保存的主机并发起批量测试
+func (ui *TestUI) showBatchDialog() {
+	if ui.hostProfiles == nil {
+		ui.unlockHostProfiles(ui.showBatchDialog)
+		return
+	}
+	profiles := ui.hostProfiles.List()
+	if len(profiles) == 0 {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("batch.no_hosts"), ui.Window)
+		return
+	}
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+	group := widget.NewCheckGroup(names, nil)
+	concurrency := widget.NewEntry()
+	concurrency.SetText(strconv.Itoa(defaultBatchConcurrency))
+	selectAll := widget.NewButton(ui.tr("button.select_all"), func() { group.SetSelected(names) })
+
+	scroll := container.NewVScroll(group)
+	scroll.SetMinSize(fyne.NewSize(420, 260))
+	content := container.NewBorder(
+		container.NewHBox(selectAll, layout.NewSpacer(), widget.NewLabel(ui.tr("batch.concurrency")), concurrency),
+		nil, nil, nil, scroll,
+	)
+	dialog.ShowCustomConfirm(ui.tr("batch.title"), ui.tr("batch.start"), ui.tr("compare.cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		if len(group.Selected) == 0 {
+			dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("batch.pick_hosts"), ui.Window)
+			return
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(concurrency.Text))
+		if err != nil || n <= 0 {
+			n = defaultBatchConcurrency
+		}
+		ui.startBatch(group.Selected, n)
+	}, ui.Window)
+}
+
+// startBatch 解析主机配置，先检查各主机能否连接，再打开批量测试窗口并开始执行
+func (ui *TestUI) startBatch(names []string, concurrency int) {
+	if !ui.hasSelectedTests() {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_tests"), ui.Window)
+		return
+	}
+	hosts := make([]batchProbe, 0, len(names))
+	for _, name := range names {
+		target, err := ui.hostProfiles.Resolve(name, ui.knownHostsPath())
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		hosts = append(hosts, batchProbe{name: name, target: target})
+	}
+	ui.preflightBatch(hosts, concurrency)
+}
+
+func (ui *TestUI) newBatchRun(concurrency int) *batchRun {
+	config := ui.collectExecutionConfig()
+	config.Remote = nil
+	config.Docker = nil
+	config.RemoteBinary = strings.TrimSpace(ui.RemoteBinaryEntry.Text)
+	ctx, cancel := context.WithCancel(context.Background())
+	run := &batchRun{
+		ui:          ui,
+		config:      config,
+		concurrency: min(max(concurrency, 1), maxBatchConcurrency),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+	run.runner = func(target remote.Target) executionRunner {
+		return newRemoteRunner(target, run.config)
+	}
+	return run
+}
+
+func (b *batchRun) addHost(name string, target remote.Target) {
+	terminal := NewTerminalOutput()
+	terminal.Translate = b.ui.tr
+	terminal.IPActions = b.ui.ipMenuItems
+	b.ui.applyTerminalFont(terminal)
+	if redact := b.ui.redactor(target.Host); redact != nil {
+		terminal.SetRedact(redact)
+	}
+	b.hosts = append(b.hosts, &batchHost{
+		name:      name,
+		target:    target,
+		terminal:  terminal,
+		progress:  widget.NewProgressBar(),
+		status:    widget.NewLabel(b.ui.tr("batch.queued")),
+		statusKey: "batch.queued",
+	})
+}
+
+// showWindow 创建批量测试窗口：汇总页 + 每台主机一个终端页
+func (b *batchRun) showWindow() {
+	ui := b.ui
+	b.overall = widget.NewLabel("")
+	b.summary = widget.NewTable(
+		func() (int, int) { return len(b.hosts) + 1, len(batchSummaryColumns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			if id.Row == 0 {
+				label.SetText(ui.tr(batchSummaryColumns[id.Col]))
+				return
+			}
+			label.SetText(b.summaryCell(id.Row-1, id.Col))
+		},
+	)
+	b.summary.SetColumnWidth(0, 180)
+	for col := 1; col < len(batchSummaryColumns); col++ {
+		b.summary.SetColumnWidth(col, 110)
+	}
+
+	b.stopButton = widget.NewButtonWithIcon(ui.tr("button.stop"), theme.MediaStopIcon(), b.stop)
+	exportButton := widget.NewButtonWithIcon(ui.tr("batch.export"), theme.DownloadIcon(), func() {
+		ui.saveExportFile("goecs-batch.md", []byte(ui.redactExport(b.summaryMarkdown(), b.hostNames()...)))
+	})
+	summaryTab := container.NewTabItemWithIcon(ui.tr("batch.summary"), theme.ListIcon(),
+		container.NewBorder(container.NewHBox(b.overall, layout.NewSpacer(), exportButton, b.stopButton), nil, nil, nil, b.summary))
+
+	tabs := container.NewAppTabs(summaryTab)
+	for _, host := range b.hosts {
+		page := container.NewBorder(
+			container.NewBorder(nil, nil, host.status, nil, host.progress),
+			nil, nil, nil,
+			newTerminalFollower(host.terminal, ui.tr).Content(),
+		)
+		tabs.Append(container.NewTabItemWithIcon(host.name, theme.ComputerIcon(), page))
+	}
+	tabs.SetTabLocation(container.TabLocationLeading)
+
+	win := ui.App.NewWindow(ui.tr("batch.title"))
+	win.SetContent(tabs)
+	win.Resize(fyne.NewSize(1100, 720))
+	win.SetOnClosed(func() {
+		b.cancel()
+		for _, host := range b.hosts {
+			host.terminal.Destroy()
+		}
+	})
+	b.updateOverall()
+	win.Show()
+}
+
+func (b *batchRun) stop() {
+	b.cancel()
+	b.ui.runOnUI(func() {
+		b.stopButton.Disable()
+		b.overall.SetText(b.ui.tr("status.stopping"))
+	})
+}
+
+// execute 以有限并发在所有主机上运行测试，全部结束后刷新汇总
+func (b *batchRun) execute() {
+	sem := make(chan struct{}, b.concurrency)
+	var wg sync.WaitGroup
+	for _, host := range b.hosts {
+		wg.Add(1)
+		go func(host *batchHost) {
+			defer wg.Done()
+			select {
+			case sem <- struct{}{}:
+			case <-b.ctx.Done():
+				b.finishHost(host, nil, b.ctx.Err())
+				return
+			}
+			defer func() { <-sem }()
+			b.runHost(host)
+		}(host)
+	}
+	wg.Wait()
+	b.ui.runOnUI(func() {
+		b.stopButton.Disable()
+		b.updateOverall()
+		b.summary.Refresh()
+	})
+}
+
+func (b *batchRun) runHost(host *batchHost) {
+	b.mu.Lock()
+	config := b.config
+	target := host.target
+	config.Remote = &target
+	run := newRun(b.ctx, config, func(text string) {
+		b.mu.Lock()
+		host.output.WriteString(text)
+		b.mu.Unlock()
+		host.terminal.AppendText(text)
+	}, func(update ProgressUpdate) {
+		b.ui.runOnUI(func() {
+			host.progress.SetValue(update.Fraction)
+			host.status.SetText(b.ui.tr(update.ItemKey))
+		})
+	})
+	run.Label = host.name
+	host.started = run.Started
+	host.statusKey = "status.running"
+	b.mu.Unlock()
+	b.refreshHost(host)
+
+	outcome := executeWithRunner(b.runner(target), run)
+	b.finishHost(host, run, outcome.Err)
+}
+
+// finishHost 记录单台主机的结果并写入历史记录，run 为 nil 表示该主机在开始前就被取消
+func (b *batchRun) finishHost(host *batchHost, run *Run, err error) {
+	b.mu.Lock()
+	host.finished = time.Now()
+	host.err = err
+	switch {
+	case err == nil:
+		host.statusKey = "status.done"
+	case errors.Is(err, context.Canceled):
+		host.statusKey = "status.stopped"
+	default:
+		host.statusKey = "status.failed"
+	}
+	output := results.StripANSI(host.output.String())
+	host.report = results.Parse(output)
+	finished, statusKey := host.finished, host.statusKey
+	b.mu.Unlock()
+
+	if err != nil {
+		host.terminal.AppendText(fmt.Sprintf("\n%s%s\n", b.ui.tr("log.error_prefix"), b.ui.friendlyErrorMessage(err)))
+	}
+	if run != nil && strings.TrimSpace(output) != "" {
+		b.ui.saveHistoryRun(run.Record(statusKey, finished, output, host.report))
+	}
+	b.refreshHost(host)
+}
+
+func (b *batchRun) refreshHost(host *batchHost) {
+	b.mu.Lock()
+	statusKey := host.statusKey
+	b.mu.Unlock()
+	b.ui.runOnUI(func() {
+		if b.summary == nil {
+			return
+		}
+		host.status.SetText(b.ui.tr(statusKey))
+		if statusKey == "status.done" {
+			host.progress.SetValue(1)
+		}
+		b.updateOverall()
+		b.summary.Refresh()
+	})
+}
+
+func (b *batchRun) updateOverall() {
+	b.mu.Lock()
+	done := 0
+	for _, host := range b.hosts {
+		if !host.finished.IsZero() {
+			done++
+		}
+	}
+	b.mu.Unlock()
+	b.overall.SetText(fmt.Sprintf(b.ui.tr("batch.overall"), done, len(b.hosts), b.concurrency))
+}
+
+var batchSummaryColumns = []string{
+	"batch.col.host", "batch.col.status", "batch.col.duration", "batch.col.cpu", "batch.col.memory",
+	"batch.col.disk_read", "batch.col.disk_write", "batch.col.download", "batch.col.upload",
+}
+
+// summaryCell 返回汇总表第 row 台主机第 col 列的文本
+func (b *batchRun) summaryCell(row, col int) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if row >= len(b.hosts) {
+		return ""
+	}
+	host := b.hosts[row]
+	headline := results.Summarize(host.report)
+	number := func(v float64) string {
+		if v == 0 {
+			return "-"
+		}
+		return formatResultNumber(v)
+	}
+	switch col {
+	case 0:
+		return host.name
+	case 1:
+		return b.ui.tr(host.statusKey)
+	case 2:
+		if host.started.IsZero() {
+			return "-"
+		}
+		end := host.finished
+		if end.IsZero() {
+			end = time.Now()
+		}
+		return formatHumanDuration(end.Sub(host.started), b.ui.uiLang)
+	case 3:
+		return number(headline.CPUScore)
+	case 4:
+		return number(headline.MemoryMBps)
+	case 5:
+		return number(headline.DiskReadMBps)
+	case 6:
+		return number(headline.DiskWriteMBps)
+	case 7:
+		return number(headline.DownloadMbps)
+	case 8:
+		return number(headline.UploadMbps)
+	}
+	return ""
+}
+
+// hostNames 返回各主机的名称与地址，隐私模式下导出汇总时一并隐去
+func (b *batchRun) hostNames() []string {
+	var names []string
+	for _, host := range b.hosts {
+		names = append(names, host.name, host.target.Host)
+	}
+	return names
+}
+
+// summaryMarkdown 把汇总表导出为 Markdown
+func (b *batchRun) summaryMarkdown() string {
+	var sb strings.Builder
+	sb.WriteString("# GOECS Batch Summary\n\n|")
+	for _, key := range batchSummaryColumns {
+		sb.WriteString(" " + b.ui.tr(key) + " |")
+	}
+	sb.WriteString("\n|")
+	for range batchSummaryColumns {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for row := range b.hosts {
+		sb.WriteString("|")
+		for col := range batchSummaryColumns {
+			sb.WriteString(" " + b.summaryCell(row, col) + " |")
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+type fakeRemoteRunner struct {
+	output string
+	err    error
+}
+
+func (f fakeRemoteRunner) Run(run *Run) executionOutcome {
+	run.Output(f.output)
+	return executionOutcome{Err: f.err}
+}
+
+func TestBatchRunSummarizesEveryHost(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	run := ui.newBatchRun(2)
+	run.runner = func(target remote.Target) executionRunner {
+		if target.Host == "10.0.0.2" {
+			return fakeRemoteRunner{output: "boom\n", err: errors.New("ssh: unable to authenticate")}
+		}
+		return fakeRemoteRunner{output: "-----------就近节点测速-----------\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n"}
+	}
+	run.addHost("alpha", remote.Target{Host: "10.0.0.1"})
+	run.addHost("beta", remote.Target{Host: "10.0.0.2"})
+	run.showWindow()
+	run.execute()
+
+	if got := run.summaryCell(0, 1); got != ui.tr("status.done") {
+		t.Fatalf("alpha status = %q", got)
+	}
+	if got := run.summaryCell(0, 7); got != formatResultNumber(200.25) {
+		t.Fatalf("alpha download = %q", got)
+	}
+	if got := run.summaryCell(1, 1); got != ui.tr("status.failed") {
+		t.Fatalf("beta status = %q", got)
+	}
+	if md := run.summaryMarkdown(); !strings.Contains(md, "| alpha |") || !strings.Contains(md, "| beta |") {
+		t.Fatalf("summary markdown = %q", md)
+	}
+	if len(ui.historyItems) != 2 {
+		t.Fatalf("history items = %d, want one per host", len(ui.historyItems))
+	}
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/sysinfo"
+)
+
+// bbrTimeout 是连接远程目标并启用 BBR 的总超时
+const bbrTimeout = 30 * time.Second
+
+var errSudoRequired = errors.New("root or passwordless sudo is required")
+
+// enableRemoteBBR 通过 SSH 以 root 运行 sysinfo.EnableBBRScript 并返回输出，测试中替换；
+// sudo 需要密码时调用 password 取得，取不到时失败
+var enableRemoteBBR = func(ctx context.Context, target remote.Target, password func() string) (string, error) {
+	client, err := remote.Dial(ctx, target)
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+	mode, err := client.SudoMode(ctx)
+	if err != nil {
+		return "", err
+	}
+	command := sysinfo.EnableBBRScript
+	var input chan string
+	switch mode {
+	case remote.SudoNoPassword:
+		command = remote.SudoCommand(command, false)
+	case remote.SudoWithPassword:
+		secret := target.SudoPassword
+		if secret == "" {
+			secret = password()
+		}
+		if secret == "" {
+			return "", errSudoRequired
+		}
+		input = make(chan string, 1)
+		input <- secret + "\n"
+		command = remote.SudoCommand(command, true)
+	case remote.SudoUnavailable:
+		return "", errSudoRequired
+	}
+	var out strings.Builder
+	err = client.RunWithInput(ctx, command, false, input, func(text string) { out.WriteString(text) })
+	return out.String(), err
+}
+
+// parseBBRChange 取出 EnableBBRScript 输出中修改前后的 "拥塞控制 队列调度"
+func parseBBRChange(output string) (before, after string) {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if value, ok := strings.CutPrefix(line, "before="); ok {
+			before = value
+		} else if value, ok := strings.CutPrefix(line, "after="); ok {
+			after = value
+		}
+	}
+	return before, after
+}
+
+// confirmEnableBBR 列出将在 SSH 目标上做的修改并请用户确认，确认后启用 BBR、记录日志并刷新信息卡片
+func (ui *TestUI) confirmEnableBBR() {
+	card := ui.systemInfo
+	if card == nil || card.remote == nil || card.info == nil {
+		return
+	}
+	target := *card.remote
+	ui.withSavedSudoPassword(&target)
+	current := strings.TrimSpace(card.info.TCPCongestion + " " + card.info.Qdisc)
+	message := fmt.Sprintf(ui.tr("bbr.confirm"), target.User, target.Address(), current, sysinfo.BBRConfigPath)
+	dialog.ShowConfirm(ui.tr("bbr.title"), message, func(ok bool) {
+		if ok {
+			ui.enableBBR(target)
+		}
+	}, ui.Window)
+}
+
+// enableBBR 在后台启用 BBR，结束后弹出修改前后的取值并重新采集目标信息
+func (ui *TestUI) enableBBR(target remote.Target) {
+	card := ui.systemInfo
+	card.bbr.Disable()
+	card.status.SetText(ui.tr("bbr.running"))
+	uiLog.Info("enabling BBR", "host", target.Address(), "user", target.User)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), bbrTimeout)
+		defer cancel()
+		out, err := enableRemoteBBR(ctx, target, func() string { return ui.askSudoPassword(ctx, target) })
+		before, after := parseBBRChange(out)
+		if err != nil {
+			uiLog.Warn("enable BBR failed", "host", target.Address(), "err", err, "output", strings.TrimSpace(out))
+		} else {
+			uiLog.Info("enabled BBR", "host", target.Address(), "before", before, "after", after, "config", sysinfo.BBRConfigPath)
+		}
+		ui.runOnUI(func() {
+			if err != nil {
+				card.status.SetText(ui.tr("bbr.failed") + " " + err.Error())
+				card.updateBBRButton()
+				dialog.ShowError(fmt.Errorf("%w\n%s", err, strings.TrimSpace(out)), ui.Window)
+				return
+			}
+			dialog.ShowInformation(ui.tr("bbr.title"), fmt.Sprintf(ui.tr("bbr.done"), before, after, sysinfo.BBRConfigPath), ui.Window)
+			ui.refreshSystemInfo()
+		})
+	}()
+}
@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/sysinfo"
+)
+
+func TestEnableBBROnRemoteTarget(t *testing.T) {
+	oldRemote, oldEnable := collectRemoteInfo, enableRemoteBBR
+	t.Cleanup(func() { collectRemoteInfo, enableRemoteBBR = oldRemote, oldEnable })
+	info := sysinfo.Info{Hostname: "vps-1", Kernel: "5.10.0-28-amd64", TCPCongestion: "cubic", TCPAvailable: "reno cubic", Qdisc: "fq_codel"}
+	collectRemoteInfo = func(ctx context.Context, target remote.Target) (sysinfo.Info, error) {
+		return info, nil
+	}
+	var enabledOn string
+	enableRemoteBBR = func(ctx context.Context, target remote.Target, password func() string) (string, error) {
+		enabledOn = target.Address()
+		info.TCPCongestion, info.Qdisc = "bbr", "fq"
+		return "before=cubic fq_codel\nnet.core.default_qdisc = fq\nafter=bbr fq\n", nil
+	}
+
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	card := ui.systemInfo
+	if card.bbr.Visible() {
+		t.Fatal("BBR button should be hidden for the local machine")
+	}
+	ui.RemoteEnableCheck.SetChecked(true)
+	ui.RemoteHostEntry.SetText("203.0.113.10")
+	ui.RemotePasswordEntry.SetText("secret")
+	card.refresh.OnTapped()
+	waitSystemInfo(t, card)
+	if !card.bbr.Visible() || card.bbr.Disabled() || card.values[9].Text != "fq_codel" {
+		t.Fatalf("bbr button visible %v disabled %v, qdisc %q", card.bbr.Visible(), card.bbr.Disabled(), card.values[9].Text)
+	}
+
+	first := card.done
+	ui.enableBBR(*card.remote)
+	// enableBBR 结束后在界面线程重新采集，卡片字段只在界面更新中读取，避免与 show 竞争
+	var refreshed chan struct{}
+	deadline := time.Now().Add(5 * time.Second)
+	for refreshed == nil {
+		if time.Now().After(deadline) {
+			t.Fatal("card not refreshed after enabling BBR")
+		}
+		time.Sleep(5 * time.Millisecond)
+		uiDoAndWait(func() {
+			if card.done != first {
+				refreshed = card.done
+			}
+		})
+	}
+	select {
+	case <-refreshed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("system info collection did not finish")
+	}
+	var cc string
+	var disabled bool
+	uiDoAndWait(func() { cc, disabled = card.values[8].Text, card.bbr.Disabled() })
+	if cc != "bbr" || enabledOn != "203.0.113.10:22" || !disabled {
+		t.Fatalf("tcp cc %q, enabled on %q, button disabled %v", cc, enabledOn, disabled)
+	}
+}
+
+func TestParseBBRChange(t *testing.T) {
+	before, after := parseBBRChange("before=cubic fq_codel\r\nnet.ipv4.tcp_congestion_control = bbr\r\nafter=bbr fq\r\n")
+	if before != "cubic fq_codel" || after != "bbr fq" {
+		t.Fatalf("parseBBRChange() = %q, %q", before, after)
+	}
+}
@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"os"
+	"runtime"
+
+	"github.com/oneclickvirt/ecs-gui/childproc"
+)
+
+// processScope 记录一次本机测试或一个阶段开始时已有的子进程。goecs 在进程内派生 fio、dd 等工具，
+// 之后新出现且未经 childproc 登记（工具页的 ping、容器的 docker exec 等）的子进程即属于这次测试
+type processScope struct {
+	before map[int]bool
+}
+
+// newProcessScope 记下当前已有的子进程
+func newProcessScope() *processScope {
+	scope := &processScope{before: map[int]bool{}}
+	for _, pid := range childProcessIDs(os.Getpid()) {
+		scope.before[pid] = true
+	}
+	return scope
+}
+
+// processes 返回范围开始后派生、属于这次测试的子进程
+func (s *processScope) processes() []int {
+	var pids []int
+	for _, pid := range childProcessIDs(os.Getpid()) {
+		if !s.before[pid] && !childproc.Owned(pid) {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
+
+// signal 通知这次测试派生的外部工具退出，返回成功发送信号的进程数；scope 为 nil（非本机测试）时不做任何事。
+// force 为 false 时发送 SIGINT 让其自行收尾；为 true 或在 Windows 上（不支持 SIGINT）直接结束进程。
+func (s *processScope) signal(force bool) int {
+	if s == nil {
+		return 0
+	}
+	sent := 0
+	for _, pid := range s.processes() {
+		proc, err := os.FindProcess(pid)
+		if err != nil {
+			continue
+		}
+		if force || runtime.GOOS == "windows" {
+			err = proc.Kill()
+		} else {
+			err = proc.Signal(os.Interrupt)
+		}
+		if err == nil {
+			sent++
+		}
+	}
+	return sent
+}
@@ -0,0 +1,42 @@
+//go:build linux
+
+package ui
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// childProcessIDs 通过 /proc 找出父进程为 ppid 的所有进程
+func childProcessIDs(ppid int) []int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+		data, err := os.ReadFile("/proc/" + entry.Name() + "/stat")
+		if err != nil {
+			continue
+		}
+		// 第二列 comm 可能包含空格和括号，从最后一个 ')' 之后开始解析
+		stat := string(data)
+		end := strings.LastIndexByte(stat, ')')
+		if end < 0 {
+			continue
+		}
+		fields := strings.Fields(stat[end+1:])
+		if len(fields) < 2 {
+			continue
+		}
+		if parent, err := strconv.Atoi(fields[1]); err == nil && parent == ppid {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/childproc"
+)
+
+func TestProcessScopeSignalsOnlyRunProcesses(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("linux /proc only")
+	}
+	start := func(register bool) (*exec.Cmd, chan error) {
+		cmd := exec.Command("sleep", "30")
+		var err error
+		if register {
+			err = childproc.Start(cmd)
+		} else {
+			err = cmd.Start()
+		}
+		if err != nil {
+			t.Skip("sleep unavailable:", err)
+		}
+		done := make(chan error, 1)
+		go func() {
+			if register {
+				done <- childproc.Wait(cmd)
+			} else {
+				done <- cmd.Wait()
+			}
+		}()
+		t.Cleanup(func() { cmd.Process.Kill() })
+		return cmd, done
+	}
+
+	// 测试开始前已在运行的进程与工具页登记的进程都不属于这次测试
+	before, beforeDone := start(false)
+	scope := newProcessScope()
+	tool, toolDone := start(true)
+	run, runDone := start(false)
+
+	if pids := scope.processes(); !slices.Equal(pids, []int{run.Process.Pid}) {
+		t.Fatalf("processes() = %v, want only %d (unrelated %d, %d)", pids, run.Process.Pid, before.Process.Pid, tool.Process.Pid)
+	}
+	if sent := scope.signal(false); sent != 1 {
+		t.Fatalf("signal() sent %d signals, want 1", sent)
+	}
+	select {
+	case <-runDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("run child did not exit after SIGINT")
+	}
+	select {
+	case <-beforeDone:
+		t.Fatal("a child started before the run was signalled")
+	case <-toolDone:
+		t.Fatal("a registered tool process was signalled")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if (*processScope)(nil).signal(true) != 0 {
+		t.Fatal("a nil scope should not signal anything")
+	}
+}
+
+func TestStopTestsEscalatesToForceStop(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.IsRunning = true
+	ui.CancelCtx, ui.CancelFn = context.WithCancel(context.Background())
+	ui.StopButton.Enable()
+
+	ui.stopTests()
+	if ui.CancelCtx.Err() == nil {
+		t.Fatal("first stop should cancel the run context")
+	}
+	if ui.StopButton.Disabled() || ui.StopButton.Text != ui.tr("button.force_stop") {
+		t.Fatalf("stop button after graceful stop = %q disabled=%v", ui.StopButton.Text, ui.StopButton.Disabled())
+	}
+	if ui.StatusLabel.Text != ui.tr("status.stopping") {
+		t.Fatalf("status = %q", ui.StatusLabel.Text)
+	}
+
+	ui.stopTests()
+	if !ui.StopButton.Disabled() {
+		t.Fatal("force stop should disable the stop button")
+	}
+
+	ui.resetUIState()
+	if ui.stopping || ui.StopButton.Text != ui.tr("button.stop") {
+		t.Fatalf("reset left stopping=%v text=%q", ui.stopping, ui.StopButton.Text)
+	}
+	if ui.StatusLabel.Text != ui.tr("status.stopped") {
+		t.Fatalf("status after reset = %q", ui.StatusLabel.Text)
+	}
+}
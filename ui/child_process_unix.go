@@ -0,0 +1,24 @@
+//go:build !linux && !windows
+
+package ui
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// childProcessIDs 借助 pgrep 找出父进程为 ppid 的所有进程；移动平台等没有 pgrep 时返回空
+func childProcessIDs(ppid int) []int {
+	out, err := exec.Command("pgrep", "-P", strconv.Itoa(ppid)).Output()
+	if err != nil {
+		return nil
+	}
+	var pids []int
+	for _, field := range strings.Fields(string(out)) {
+		if pid, err := strconv.Atoi(field); err == nil {
+			pids = append(pids, pid)
+		}
+	}
+	return pids
+}
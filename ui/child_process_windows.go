@@ -0,0 +1,28 @@
+//go:build windows
+
+package ui
+
+import (
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+// childProcessIDs 通过进程快照找出父进程为 ppid 的所有进程
+func childProcessIDs(ppid int) []int {
+	snapshot, err := windows.CreateToolhelp32Snapshot(windows.TH32CS_SNAPPROCESS, 0)
+	if err != nil {
+		return nil
+	}
+	defer windows.CloseHandle(snapshot)
+
+	var entry windows.ProcessEntry32
+	entry.Size = uint32(unsafe.Sizeof(entry))
+	var pids []int
+	for err = windows.Process32First(snapshot, &entry); err == nil; err = windows.Process32Next(snapshot, &entry) {
+		if int(entry.ParentProcessID) == ppid {
+			pids = append(pids, int(entry.ProcessID))
+		}
+	}
+	return pids
+}
@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	minCompareRuns = 2
+	maxCompareRuns = 4
+	// compareThresholdPercent 是判定为明显变化（着色）的百分比阈值
+	compareThresholdPercent = 5.0
+)
+
+// comparisonCell 是比较表中的一个单元格
+type comparisonCell struct {
+	text       string
+	importance widget.Importance
+}
+
+// showCompareDialog 让用户从历史记录中勾选 2-4 次运行进行比较
+func (ui *TestUI) showCompareDialog() {
+	if len(ui.historyItems) < minCompareRuns {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("compare.need_runs"), ui.Window)
+		return
+	}
+	labels := make([]string, 0, len(ui.historyItems))
+	idByLabel := make(map[string]string, len(ui.historyItems))
+	for _, item := range ui.historyItems {
+		label := ui.historyItemText(item)
+		labels = append(labels, label)
+		idByLabel[label] = item.ID
+	}
+	group := widget.NewCheckGroup(labels, nil)
+	content := container.NewVScroll(group)
+	content.SetMinSize(fyne.NewSize(520, 320))
+
+	dialog.ShowCustomConfirm(ui.tr("compare.title"), ui.tr("compare.run"), ui.tr("compare.cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		if len(group.Selected) < minCompareRuns || len(group.Selected) > maxCompareRuns {
+			dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("compare.pick_range"), ui.Window)
+			return
+		}
+		store := ui.historyStoreOrOpen()
+		if store == nil {
+			return
+		}
+		// 按列表顺序（新到旧）反转，使最早的运行作为比较基准
+		var runs []history.Run
+		for i := len(labels) - 1; i >= 0; i-- {
+			if !containsString(group.Selected, labels[i]) {
+				continue
+			}
+			run, err := store.Load(idByLabel[labels[i]])
+			if err != nil {
+				dialog.ShowError(err, ui.Window)
+				return
+			}
+			runs = append(runs, run)
+		}
+		ui.showComparisonWindow(runs)
+	}, ui.Window)
+}
+
+// showComparisonWindow 在新窗口中展示多次运行的指标对比
+func (ui *TestUI) showComparisonWindow(runs []history.Run) {
+	cells := ui.buildComparisonCells(runs)
+	if len(cells) <= 1 {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("compare.no_metrics"), ui.Window)
+		return
+	}
+	columns := len(cells[0])
+	table := widget.NewTable(
+		func() (int, int) { return len(cells), columns },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			cell := cells[id.Row][id.Col]
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			label.Importance = cell.importance
+			label.SetText(cell.text)
+		},
+	)
+	table.SetColumnWidth(0, 320)
+	for col := 1; col < columns; col++ {
+		table.SetColumnWidth(col, 200)
+	}
+
+	legend := widget.NewLabel(fmt.Sprintf(ui.tr("compare.legend"), compareThresholdPercent))
+	win := ui.App.NewWindow(ui.tr("compare.title"))
+	win.SetContent(container.NewBorder(legend, nil, nil, nil, table))
+	win.Resize(fyne.NewSize(float32(320+200*(columns-1)+40), 560))
+	win.Show()
+}
+
+// buildComparisonCells 生成比较表单元格，第一行为表头，第一列为指标名
+func (ui *TestUI) buildComparisonCells(runs []history.Run) [][]comparisonCell {
+	reports := make([]*results.Report, len(runs))
+	header := []comparisonCell{{text: ui.tr("compare.metric")}}
+	for i, run := range runs {
+		reports[i] = run.Results
+		if reports[i] == nil {
+			reports[i] = results.Parse(run.Output)
+		}
+		title := run.StartedAt.Local().Format("01-02 15:04")
+		if run.Label != "" {
+			title = run.Label
+		} else if run.Host != "" {
+			title += " " + run.Host
+		}
+		if len(run.Tags) > 0 {
+			title += " " + formatTags(run.Tags)
+		}
+		if i == 0 {
+			title += " " + ui.tr("compare.baseline")
+		}
+		header = append(header, comparisonCell{text: title})
+	}
+
+	cells := [][]comparisonCell{header}
+	for _, row := range results.Compare(reports...) {
+		line := []comparisonCell{{text: fmt.Sprintf("[%s] %s · %s", ui.tr("results.tab."+string(row.Section)), row.Item, ui.tr("compare.metric."+row.Name))}}
+		for i, value := range row.Values {
+			if !value.OK {
+				line = append(line, comparisonCell{text: "-"})
+				continue
+			}
+			text := formatResultNumber(value.Value)
+			if row.Unit != "" {
+				text += " " + row.Unit
+			}
+			cell := comparisonCell{text: text}
+			if delta, ok := row.Delta(i); ok {
+				cell.text += fmt.Sprintf(" (%+.1f%%)", delta)
+				switch {
+				case row.Regressed(i, compareThresholdPercent):
+					cell.importance = widget.DangerImportance
+				case row.Improved(i, compareThresholdPercent):
+					cell.importance = widget.SuccessImportance
+				}
+			}
+			line = append(line, cell)
+		}
+		cells = append(cells, line)
+	}
+	return append(cells, ui.valueComparisonCells(runs, reports)...)
+}
+
+// valueComparisonCells 生成月费与性价比行，每行中性价比最高的运行标 ★；月费取主机管理中的当前值，没有时取运行时记录的值
+func (ui *TestUI) valueComparisonCells(runs []history.Run, reports []*results.Report) [][]comparisonCell {
+	costs := make([]float64, len(runs))
+	costRow := []comparisonCell{{text: ui.tr("value.monthly_cost")}}
+	for i, run := range runs {
+		costs[i] = run.MonthlyCost
+		if p, ok := ui.hostProfileByHost(run.Host); ok && p.MonthlyCost > 0 {
+			costs[i] = p.MonthlyCost
+		}
+		text := "-"
+		if costs[i] > 0 {
+			text = formatResultNumber(costs[i])
+		}
+		costRow = append(costRow, comparisonCell{text: text})
+	}
+	rows := results.CompareValue(reports, costs)
+	if len(rows) == 0 {
+		return nil
+	}
+	cells := [][]comparisonCell{costRow}
+	for _, row := range rows {
+		title := fmt.Sprintf("[%s] %s", ui.tr("results.tab."+string(row.Section)), ui.tr("value."+row.Name))
+		if row.Item != "" {
+			title = fmt.Sprintf("[%s] %s · %s", ui.tr("results.tab."+string(row.Section)), row.Item, ui.tr("value."+row.Name))
+		}
+		line := []comparisonCell{{text: title}}
+		best := row.Best()
+		for i, value := range row.Values {
+			if !value.OK {
+				line = append(line, comparisonCell{text: "-"})
+				continue
+			}
+			cell := comparisonCell{text: formatResultNumber(value.Value)}
+			if row.Unit != "" {
+				cell.text += " " + row.Unit
+			}
+			if i == best && len(runs) > 1 {
+				cell.text += " ★"
+				cell.importance = widget.SuccessImportance
+			}
+			line = append(line, cell)
+		}
+		cells = append(cells, line)
+	}
+	return cells
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestBuildComparisonCellsMarksRegressions(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	runs := []history.Run{
+		{Results: &results.Report{CPU: []results.CPUScore{{Label: "1 线程测试(单核)得分", Score: 1000}}}},
+		{Results: &results.Report{CPU: []results.CPUScore{{Label: "1 线程测试(单核)得分", Score: 700}}}},
+		{Results: &results.Report{CPU: []results.CPUScore{{Label: "1 线程测试(单核)得分", Score: 1200}}}},
+	}
+	cells := ui.buildComparisonCells(runs)
+	if len(cells) != 2 || len(cells[0]) != 4 {
+		t.Fatalf("cells shape = %d x %d, want 2 x 4", len(cells), len(cells[0]))
+	}
+	row := cells[1]
+	if row[1].importance != widget.MediumImportance {
+		t.Fatalf("baseline cell importance = %v", row[1].importance)
+	}
+	if row[2].importance != widget.DangerImportance || !strings.Contains(row[2].text, "-30.0%") {
+		t.Fatalf("regressed cell = %#v", row[2])
+	}
+	if row[3].importance != widget.SuccessImportance || !strings.Contains(row[3].text, "+20.0%") {
+		t.Fatalf("improved cell = %#v", row[3])
+	}
+}
+
+func TestBuildComparisonCellsAddsPricePerformance(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	store, err := remote.OpenProfiles(ui.hostProfilesPath(), "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Put("", remote.Profile{Name: "b", Host: "10.0.0.2", User: "root", Auth: remote.AuthPassword, Password: "p", MonthlyCost: 20}); err != nil {
+		t.Fatal(err)
+	}
+	ui.hostProfiles = store
+	runs := []history.Run{
+		{Summary: history.Summary{Host: "10.0.0.1", MonthlyCost: 5}, Results: &results.Report{CPU: []results.CPUScore{{Label: "multi", Score: 4000}}}},
+		{Summary: history.Summary{Host: "10.0.0.2", MonthlyCost: 10}, Results: &results.Report{CPU: []results.CPUScore{{Label: "multi", Score: 6000}}}},
+	}
+	cells := ui.buildComparisonCells(runs)
+	if len(cells) != 4 {
+		t.Fatalf("cells = %#v, want score, cost and score per cost rows", cells)
+	}
+	if cost := cells[2]; cost[1].text != "5" || cost[2].text != "20" {
+		t.Fatalf("the current host profile cost should win, row = %#v", cost)
+	}
+	value := cells[3]
+	if value[1].text != "800 ★" || value[1].importance != widget.SuccessImportance || value[2].text != "300" {
+		t.Fatalf("value row = %#v", value)
+	}
+
+	runs[0].MonthlyCost = 0
+	ui.hostProfiles = nil
+	runs[1].MonthlyCost = 0
+	if cells := ui.buildComparisonCells(runs); len(cells) != 2 {
+		t.Fatalf("runs without costs should not add value rows, cells = %#v", cells)
+	}
+}
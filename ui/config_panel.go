@@ -1,12 +1,16 @@
 package ui
 
 import (
+	"strings"
+
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/diskbench"
+	"github.com/oneclickvirt/ecs-gui/geekbench"
 )
 
 func (ui *TestUI) newIconCard(title, subtitle string, icon fyne.Resource, body fyne.CanvasObject) fyne.CanvasObject {
@@ -14,6 +18,9 @@ func (ui *TestUI) newIconCard(title, subtitle string, icon fyne.Resource, body f
 		widget.NewIcon(icon),
 		widget.NewLabelWithStyle(title, fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 	)
+	if subtitle == "" {
+		return widget.NewCard("", "", container.NewVBox(head, body))
+	}
 	return widget.NewCard("", "", container.NewVBox(
 		head,
 		widget.NewLabel(subtitle),
@@ -73,8 +80,20 @@ func (ui *TestUI) createOptionsPanel() fyne.CanvasObject {
 	ui.PingCheck = widget.NewCheck(ui.tr("check.ping"), nil)
 	ui.PingCheck.Checked = false
 
+	// GPU 基准只在收集 GPU 信息时运行
+	ui.GPUBenchCheck = widget.NewCheck(ui.tr("check.gpu_bench"), nil)
+	ui.GPUBenchCheck.Disable()
+	ui.GPUCheck = widget.NewCheck(ui.tr("check.gpu"), func(checked bool) {
+		if checked {
+			ui.GPUBenchCheck.Enable()
+		} else {
+			ui.GPUBenchCheck.Disable()
+		}
+	})
+
 	ui.LogCheck = widget.NewCheck(ui.tr("check.log"), ui.onLogCheckChanged)
 	ui.LogCheck.Checked = false
+	ui.LogKeepANSICheck, ui.LogAutoSaveCheck = ui.createLogPreferenceChecks()
 
 	ui.testChecks = []*widget.Check{
 		ui.BasicCheck,
@@ -99,7 +118,11 @@ func (ui *TestUI) createOptionsPanel() fyne.CanvasObject {
 		ui.setAllChecks(false)
 	})
 
-	buttonRow := container.NewHBox(selectAllBtn, deselectAllBtn)
+	// 离线/内网模式：一键关闭所有需要公网的测试；网络测试强度用于按流量计费或正在提供服务的机器
+	ui.OfflineModeCheck = widget.NewCheck(ui.tr("check.offline_mode"), ui.setOfflineMode)
+	ui.NetworkLimitButton = widget.NewButtonWithIcon(ui.networkLimitsSummary(), theme.MediaFastForwardIcon(), ui.showNetworkLimits)
+
+	buttonRow := container.NewHBox(selectAllBtn, deselectAllBtn, ui.OfflineModeCheck, ui.NetworkLimitButton)
 
 	// 测试项目分组
 	basicTests := ui.newIconCard(ui.tr("tests.basic.title"), ui.tr("tests.basic.sub"), theme.SettingsIcon(), container.NewVBox(
@@ -107,6 +130,8 @@ func (ui *TestUI) createOptionsPanel() fyne.CanvasObject {
 		ui.CpuCheck,
 		ui.MemoryCheck,
 		ui.DiskCheck,
+		ui.GPUCheck,
+		ui.GPUBenchCheck,
 	))
 
 	networkTests := ui.newIconCard(ui.tr("tests.network.title"), ui.tr("tests.network.sub"), theme.SearchIcon(), container.NewVBox(
@@ -136,6 +161,7 @@ func (ui *TestUI) createOptionsPanel() fyne.CanvasObject {
 
 	// === 配置选项 ===
 	configSection := ui.createConfigSection()
+	remoteSection := ui.createRemoteSection()
 
 	// 整合所有内容
 	allContent := container.NewVBox(
@@ -143,6 +169,8 @@ func (ui *TestUI) createOptionsPanel() fyne.CanvasObject {
 		widget.NewSeparator(),
 		testsSection,
 		widget.NewSeparator(),
+		remoteSection,
+		widget.NewSeparator(),
 		configSection,
 	)
 
@@ -153,59 +181,50 @@ func (ui *TestUI) createOptionsPanel() fyne.CanvasObject {
 func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 	// 语言选择
 	ui.LanguageSelect = widget.NewSelect(
-		[]string{"中文", "English"},
+		languageLabels(),
 		func(value string) {
-			lang := langZH
-			if value == "English" {
-				lang = langEN
-			}
-
+			setting := languageSettingByLabel(value)
+			lang := resolveLanguage(setting)
 			if lang == ui.uiLang {
+				ui.saveLanguageSetting(setting)
 				return
 			}
 
 			if ui.isRunning() {
 				dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.running_no_switch"), ui.Window)
-				if ui.uiLang == langEN {
-					ui.LanguageSelect.SetSelected("English")
-				} else {
-					ui.LanguageSelect.SetSelected("中文")
-				}
+				ui.LanguageSelect.SetSelected(languageLabel(ui.languageSetting))
 				return
 			}
 
-			state := ui.snapshotUIState()
-			if ui.Terminal != nil {
-				ui.Terminal.Destroy()
-			}
+			ui.saveLanguageSetting(setting)
 
+			state := ui.snapshotUIState()
 			ui.uiLang = lang
-			ui.buildUI()
-			ui.restoreUIState(state)
-			ui.Window.SetContent(ui.createRootContent())
-			ui.Window.SetTitle(ui.tr("app.title"))
+			ui.rebuildUI(state)
 		},
 	)
-	if ui.uiLang == langEN {
-		ui.LanguageSelect.SetSelected("English")
-	} else {
-		ui.LanguageSelect.SetSelected("中文")
-	}
+	ui.LanguageSelect.SetSelected(languageLabel(ui.languageSetting))
 
 	ui.ThemeSelect = widget.NewSelect(
-		[]string{ui.tr("theme.light"), ui.tr("theme.dark")},
+		[]string{ui.tr("theme.light"), ui.tr("theme.dark"), ui.tr("theme.system")},
 		func(value string) {
 			ui.applyThemeMode(ui.themeModeByLabel(value))
 		},
 	)
 	ui.ThemeSelect.SetSelected(ui.themeLabelByMode(ui.themeMode))
+	ui.BufferSizeSelect = ui.createTerminalBufferSelect()
+	ui.SchemeSelect = ui.createTerminalSchemeSelect()
+	ui.FontSizeSelect = ui.createTerminalFontSizeSelect()
 
 	// CPU 配置
 	ui.CpuMethodSelect = widget.NewSelect(
 		[]string{"sysbench", "geekbench", "winsat"},
-		func(value string) {},
+		func(value string) { ui.refreshGeekbenchVersion() },
 	)
 	ui.CpuMethodSelect.Selected = "sysbench"
+	ui.GeekbenchVersionSelect = widget.NewSelect(geekbenchVersionOptions(), func(value string) {})
+	ui.GeekbenchVersionSelect.Selected = geekbench.Versions[0]
+	ui.refreshGeekbenchVersion()
 
 	ui.ThreadModeSelect = widget.NewSelect(
 		[]string{"single", "multi"},
@@ -234,6 +253,20 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 	ui.DiskMultiCheck.Checked = false
 	ui.AutoDiskMethodCheck = widget.NewCheck(ui.tr("check.auto_disk"), nil)
 	ui.AutoDiskMethodCheck.Checked = true
+	ui.DiskBlockSizesEntry = widget.NewEntry()
+	ui.DiskBlockSizesEntry.SetPlaceHolder(strings.Join(diskbench.DefaultBlockSizes, ","))
+	ui.DiskBlockSizesEntry.Validator = func(text string) error {
+		_, err := diskbench.ParseBlockSizes(text)
+		return err
+	}
+	ui.DiskFileSizeEntry = widget.NewEntry()
+	ui.DiskFileSizeEntry.SetPlaceHolder(ui.tr("placeholder.disk_file_size"))
+	ui.DiskFileSizeEntry.Validator = func(text string) error {
+		_, err := diskbench.ParseSize(text)
+		return err
+	}
+	ui.DiskSafeModeCheck = widget.NewCheck(ui.tr("check.disk_safe_mode"), nil)
+	ui.DiskSafeModeCheck.Checked = true
 
 	// Deep mode is always explicit. Potentially destructive or long-running
 	// targets stay empty until the user enters them.
@@ -268,6 +301,8 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 	ui.SpNumEntry = widget.NewEntry()
 	ui.SpNumEntry.SetText("2")
 	ui.SpNumEntry.SetPlaceHolder(ui.tr("placeholder.sp_num"))
+	ui.SpeedNodesButton = widget.NewButtonWithIcon(ui.speedNodesSummary(), theme.ListIcon(), ui.showSpeedNodePicker)
+	ui.IperfButton = widget.NewButtonWithIcon(ui.iperfTargetsSummary(), theme.UploadIcon(), ui.showIperfTargets)
 	ui.OutputWidthEntry = widget.NewEntry()
 	ui.OutputWidthEntry.SetText("82")
 	ui.OutputWidthEntry.SetPlaceHolder(ui.tr("placeholder.output_width"))
@@ -339,8 +374,8 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 		container.NewGridWithColumns(2,
 			widget.NewLabel(ui.tr("label.language")),
 			ui.LanguageSelect,
-			widget.NewLabel(ui.tr("label.theme")),
-			ui.ThemeSelect,
+			widget.NewLabel(ui.tr("label.terminal_buffer")),
+			ui.BufferSizeSelect,
 			widget.NewLabel(ui.tr("label.output_width")),
 			ui.OutputWidthEntry,
 			widget.NewLabel(ui.tr("label.output_file")),
@@ -348,24 +383,45 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 			widget.NewLabel(ui.tr("label.json_path")),
 			ui.JSONPathEntry,
 			widget.NewLabel(ui.tr("label.max_duration")),
-			ui.MaxDurationEntry,
+			container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.HistoryIcon(), ui.showStageTimeouts), ui.MaxDurationEntry),
 			widget.NewLabel(ui.tr("label.hardware_budget")),
 			ui.HardwareBudgetEntry,
 		),
-		ui.DataOfflineCheck,
-		ui.PrivacyModeCheck,
-		ui.LogCheck,
-		ui.ResultUploadCheck,
-		ui.AnalyzeResultCheck,
+		container.NewGridWithColumns(2, ui.DataOfflineCheck, ui.PrivacyModeCheck),
+		container.NewGridWithColumns(2, ui.LogCheck, ui.createUpdateRow()),
+		container.NewGridWithColumns(2,
+			container.NewBorder(nil, nil, nil, widget.NewButton(ui.tr("notify.channels"), ui.showNotifyChannels), ui.createNotifyCheck()),
+			ui.createTrayCheck(),
+		),
+		container.NewGridWithColumns(2, ui.LogKeepANSICheck, ui.LogAutoSaveCheck),
+		container.NewGridWithColumns(2, ui.ResultUploadCheck, ui.AnalyzeResultCheck),
+		ui.createAPIRow(),
+		container.NewGridWithColumns(4,
+			widget.NewButtonWithIcon(ui.tr("settings.import"), theme.FolderOpenIcon(), ui.importSettings),
+			widget.NewButtonWithIcon(ui.tr("settings.export"), theme.DocumentSaveIcon(), ui.exportSettings),
+			widget.NewButtonWithIcon(ui.tr("proxy.settings"), theme.ComputerIcon(), ui.showProxySettings),
+			widget.NewButtonWithIcon(ui.tr("mirror.title"), theme.DownloadIcon(), ui.showMirrorManager),
+		),
+	)
+
+	appearanceContent := container.NewGridWithColumns(2,
+		widget.NewLabel(ui.tr("label.theme")),
+		container.NewBorder(nil, nil, nil, widget.NewButton(ui.tr("shortcuts.title"), ui.showShortcutSettings), ui.ThemeSelect),
+		widget.NewLabel(ui.tr("label.terminal_scheme")),
+		ui.SchemeSelect,
+		widget.NewLabel(ui.tr("label.terminal_font")),
+		ui.createTerminalFontSelect(),
+		widget.NewLabel(ui.tr("label.terminal_font_size")),
+		ui.FontSizeSelect,
 	)
 
 	chinaContent := container.NewVBox(
 		ui.ChinaModeCheck,
 	)
 
-	cpuContent := container.NewGridWithColumns(2,
+	cpuContent := container.New(layout.NewFormLayout(),
 		widget.NewLabel(ui.tr("label.cpu_method")),
-		ui.CpuMethodSelect,
+		container.NewGridWithColumns(2, ui.CpuMethodSelect, ui.GeekbenchVersionSelect),
 		widget.NewLabel(ui.tr("label.thread_mode")),
 		ui.ThreadModeSelect,
 	)
@@ -375,15 +431,18 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 		ui.MemoryMethodSelect,
 	)
 
+	diskPathBrowse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), ui.browseDiskPath)
 	diskContent := container.NewVBox(
 		container.NewGridWithColumns(2,
 			widget.NewLabel(ui.tr("label.disk_method")),
 			ui.DiskMethodSelect,
 			widget.NewLabel(ui.tr("label.disk_path")),
-			ui.DiskPathEntry,
+			container.NewBorder(nil, nil, nil, diskPathBrowse, ui.DiskPathEntry),
+			widget.NewLabel(ui.tr("label.disk_fio")),
+			container.NewGridWithColumns(2, ui.DiskBlockSizesEntry, ui.DiskFileSizeEntry),
 		),
 		ui.DiskMultiCheck,
-		ui.AutoDiskMethodCheck,
+		container.NewGridWithColumns(2, ui.AutoDiskMethodCheck, ui.DiskSafeModeCheck),
 	)
 
 	deepContent := container.NewVBox(
@@ -408,9 +467,9 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 	)
 
 	speedContent := container.NewVBox(
-		container.NewGridWithColumns(2,
+		container.New(layout.NewFormLayout(),
 			widget.NewLabel(ui.tr("label.sp_num")),
-			ui.SpNumEntry,
+			container.NewBorder(nil, nil, nil, container.NewHBox(ui.SpeedNodesButton, ui.IperfButton), ui.SpNumEntry),
 		),
 	)
 
@@ -446,9 +505,12 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 		ui.PingWebCheck,
 	)
 
+	advancedContent := ui.createAdvancedContent()
+
 	if isMobilePlatform() {
 		acc := widget.NewAccordion(
 			widget.NewAccordionItem(ui.tr("config.general.title"), generalContent),
+			widget.NewAccordionItem(ui.tr("config.appearance.title"), appearanceContent),
 			widget.NewAccordionItem(ui.tr("config.china.title"), chinaContent),
 			widget.NewAccordionItem(ui.tr("config.cpu.title"), cpuContent),
 			widget.NewAccordionItem(ui.tr("config.mem.title"), memoryContent),
@@ -458,6 +520,7 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 			widget.NewAccordionItem(ui.tr("config.route.title"), routeContent),
 			widget.NewAccordionItem(ui.tr("config.speed.title"), speedContent),
 			widget.NewAccordionItem(ui.tr("config.ping.title"), pingContent),
+			widget.NewAccordionItem(ui.tr("config.advanced.title"), advancedContent),
 		)
 		acc.MultiOpen = false
 		acc.Open(0)
@@ -465,6 +528,7 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 	}
 
 	generalCard := ui.newIconCard(ui.tr("config.general.title"), ui.tr("config.general.sub"), theme.SettingsIcon(), generalContent)
+	appearanceCard := ui.newIconCard(ui.tr("config.appearance.title"), ui.tr("config.appearance.sub"), theme.ColorPaletteIcon(), appearanceContent)
 	chinaCard := ui.newIconCard(ui.tr("config.china.title"), ui.tr("config.china.sub"), theme.InfoIcon(), chinaContent)
 	cpuCard := ui.newIconCard(ui.tr("config.cpu.title"), ui.tr("config.cpu.sub"), theme.SettingsIcon(), cpuContent)
 	memoryCard := ui.newIconCard(ui.tr("config.mem.title"), ui.tr("config.mem.sub"), theme.SettingsIcon(), memoryContent)
@@ -474,12 +538,13 @@ func (ui *TestUI) createConfigSection() fyne.CanvasObject {
 	routeCard := ui.newIconCard(ui.tr("config.route.title"), ui.tr("config.route.sub"), theme.SearchIcon(), routeContent)
 	speedCard := ui.newIconCard(ui.tr("config.speed.title"), ui.tr("config.speed.sub"), theme.DownloadIcon(), speedContent)
 	pingCard := ui.newIconCard(ui.tr("config.ping.title"), ui.tr("config.ping.sub"), theme.InfoIcon(), pingContent)
+	advancedCard := ui.newIconCard(ui.tr("config.advanced.title"), "", theme.SettingsIcon(), advancedContent)
 
 	configGrid := container.NewVBox(
-		container.NewGridWithColumns(2, generalCard, unlockCard),
+		container.NewGridWithColumns(2, generalCard, container.NewVBox(unlockCard, appearanceCard)),
 		container.NewGridWithColumns(2, cpuCard, memoryCard),
 		container.NewGridWithColumns(2, diskCard, deepCard),
-		container.NewGridWithColumns(2, routeCard, pingCard),
+		container.NewGridWithColumns(2, container.NewVBox(routeCard, advancedCard), pingCard),
 		container.NewGridWithColumns(2, chinaCard, speedCard),
 	)
 
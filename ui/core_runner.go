@@ -1,6 +1,18 @@
 package ui
 
-import ecsapi "github.com/oneclickvirt/ecs/api"
+import (
+	"context"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/diskbench"
+	"github.com/oneclickvirt/ecs-gui/geekbench"
+	ecsapi "github.com/oneclickvirt/ecs/api"
+	"github.com/oneclickvirt/fio"
+	speedtestmodel "github.com/oneclickvirt/speedtest/model"
+	"github.com/oneclickvirt/speedtest/sp"
+)
 
 type CoreRunner interface {
 	CpuTest(language, method, threadMode string) (string, string)
@@ -12,6 +24,9 @@ type CoreRunner interface {
 	SpeedTestShowHead(language string)
 	SpeedTestNearby()
 	SpeedTestCustom(platform, operator string, num int, language string)
+	SpeedTestServers(ids []string, offline bool, language string)
+	CustomDiskTest(ctx context.Context, language string, opts diskbench.Options) (string, error)
+	GeekbenchTest(ctx context.Context, version string, stdin io.Reader) (string, error)
 	NewConfig(version string) *ecsapi.Config
 	HandleUploadResults(config *ecsapi.Config, output string)
 	SetIPv4Address(ipv4 string)
@@ -32,6 +47,25 @@ func (ecsCoreRunner) DiskTest(language, method, path string, multi, auto bool) (
 	return ecsapi.DiskTest(language, method, path, multi, auto)
 }
 
+// CustomDiskTest 使用与 ecs 相同的 fio（系统自带或内置）按自定义块大小与文件大小测试
+func (ecsCoreRunner) CustomDiskTest(ctx context.Context, language string, opts diskbench.Options) (string, error) {
+	command, tempFile, err := fio.GetFIO()
+	defer fio.CleanFio(tempFile)
+	if err != nil {
+		return "", err
+	}
+	return diskbench.Run(ctx, strings.Fields(command), language, opts)
+}
+
+// GeekbenchTest 运行指定主版本的 Geekbench，输出比 ecs 多一行认领链接
+func (ecsCoreRunner) GeekbenchTest(ctx context.Context, version string, stdin io.Reader) (string, error) {
+	result, err := geekbench.Run(ctx, version, stdin)
+	if err != nil {
+		return "", err
+	}
+	return geekbench.Render(result), nil
+}
+
 func (ecsCoreRunner) MediaTest(language, region, ipVersion string, showIP bool) string {
 	return ecsapi.MediaTest(language, region, ipVersion, showIP)
 }
@@ -56,6 +90,28 @@ func (ecsCoreRunner) SpeedTestCustom(platform, operator string, num int, languag
 	ecsapi.SpeedTestCustom(platform, operator, num, language)
 }
 
+// SpeedTestServers 测试指定 ID 的服务器；列表中找不到的 ID 只能由官方客户端按 ID 获取
+func (ecsCoreRunner) SpeedTestServers(ids []string, offline bool, language string) {
+	registry, _ := loadSpeedServers(context.Background(), offline)
+	servers := make([]speedtestmodel.ServerMetadata, 0, len(ids))
+	for _, id := range ids {
+		server := speedtestmodel.ServerMetadata{ID: id}
+		for _, candidate := range registry {
+			if candidate.ID == id {
+				server = candidate
+				break
+			}
+		}
+		servers = append(servers, server)
+	}
+	// 与 goecs 相同：Windows 或官方客户端不可用时使用 speedtest-go
+	if runtime.GOOS == "windows" || sp.OfficialAvailableTest() != nil {
+		sp.RegistrySpeedTest(servers, language)
+	} else {
+		sp.OfficialRegistrySpeedTest(servers, language)
+	}
+}
+
 func (ecsCoreRunner) NewConfig(version string) *ecsapi.Config {
 	return ecsapi.NewConfig(version)
 }
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"github.com/oneclickvirt/ecs-gui/diskbench"
+)
+
+// checkDiskSpace 检查测试目录所在磁盘的剩余空间，测试中替换
+var checkDiskSpace = diskbench.CheckSpace
+
+// diskBenchOptions 返回本次硬盘测试的 fio 参数；custom 表示块大小或文件大小不是默认值，
+// 需要由 GUI 自行调用 fio，而不是使用 ecs 的固定参数
+func diskBenchOptions(config ExecutionConfig) (opts diskbench.Options, custom bool) {
+	opts = diskbench.Options{Path: config.DiskPath, BlockSizes: config.DiskBlockSizes, Size: config.DiskFileSize}
+	custom = config.DiskMethod == "fio" &&
+		(config.DiskFileSize > 0 || (len(config.DiskBlockSizes) > 0 && !slices.Equal(config.DiskBlockSizes, diskbench.DefaultBlockSizes)))
+	return opts, custom
+}
+
+// diskSpaceWarning 在安全模式下检查本机硬盘测试是否会写满磁盘，返回包装了 ErrNearlyFull 的错误；
+// 无法读取磁盘容量时不拦截，交给测试本身报告
+func diskSpaceWarning(config ExecutionConfig) error {
+	if !config.DiskSafeMode || !config.local() || !config.SelectedOptions["disk"] {
+		return nil
+	}
+	opts, _ := diskBenchOptions(config)
+	if _, err := checkDiskSpace(opts); errors.Is(err, diskbench.ErrNearlyFull) {
+		return err
+	}
+	return nil
+}
+
+// diskSafetySize 在安全模式下为本机 fio 测试缩小测试文件：空间足够时返回 0；原大小会写满磁盘但缩小后放得下时
+// 返回缩小后的大小；缩小后仍放不下或方法不是 fio（无法缩小）时返回 diskSpaceWarning 的错误
+func diskSafetySize(config ExecutionConfig) (int64, error) {
+	err := diskSpaceWarning(config)
+	if err == nil || config.DiskMethod != "fio" {
+		return 0, err
+	}
+	opts, _ := diskBenchOptions(config)
+	space, _ := checkDiskSpace(opts)
+	if size := diskbench.Shrink(space, opts); size > 0 {
+		return size, nil
+	}
+	return 0, err
+}
+
+// confirmDiskSpace 在安全模式下测试目录所在磁盘连缩小后的测试文件都放不下时先确认；确认后本次运行不再跳过硬盘测试，取消时调用 cancel
+func (ui *TestUI) confirmDiskSpace(config ExecutionConfig, next func(ExecutionConfig), cancel func()) {
+	_, err := diskSafetySize(config)
+	if err == nil {
+		next(config)
+		return
+	}
+	dialog.ShowConfirm(ui.tr("dialog.disk_full_title"), fmt.Sprintf(ui.tr("dialog.disk_full_body"), err), func(ok bool) {
+		if !ok {
+			cancel()
+			return
+		}
+		config.DiskSafeMode = false
+		next(config)
+	}, ui.Window)
+}
+
+// browseDiskPath 选择硬盘测试目录（挂载点）
+func (ui *TestUI) browseDiskPath() {
+	dialog.ShowFolderOpen(func(dir fyne.ListableURI, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		if dir != nil {
+			ui.DiskPathEntry.SetText(dir.Path())
+		}
+	}, ui.Window)
+}
@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/diskbench"
+)
+
+func TestBuildExecutionConfigParsesDiskOptions(t *testing.T) {
+	form := executionForm{
+		checks:     map[string]bool{"disk": true},
+		selections: map[string]string{"diskMethod": "fio"},
+		entries:    map[string]string{"diskPath": "/data", "diskBlockSizes": "4K, 1m", "diskFileSize": "512M"},
+	}
+	config := buildExecutionConfig(form)
+	if fmt.Sprint(config.DiskBlockSizes) != "[4k 1m]" || config.DiskFileSize != 512<<20 {
+		t.Fatalf("disk options = %v, %d", config.DiskBlockSizes, config.DiskFileSize)
+	}
+	if !config.DiskSafeMode {
+		t.Fatal("safe mode should default to on when the settings predate it")
+	}
+	opts, custom := diskBenchOptions(config)
+	if !custom || opts.Path != "/data" {
+		t.Fatalf("diskBenchOptions() = %+v, %v", opts, custom)
+	}
+
+	form.checks["diskSafeMode"] = false
+	form.entries["diskBlockSizes"] = "4k,64k,512k,1m"
+	form.entries["diskFileSize"] = "huge"
+	config = buildExecutionConfig(form)
+	if config.DiskSafeMode || config.DiskFileSize != 0 {
+		t.Fatalf("config = %+v", config)
+	}
+	if _, custom := diskBenchOptions(config); custom {
+		t.Fatal("default block sizes should use the ecs disk test")
+	}
+}
+
+func TestDiskSpaceWarningOnlyInSafeMode(t *testing.T) {
+	old := checkDiskSpace
+	t.Cleanup(func() { checkDiskSpace = old })
+	checkDiskSpace = func(opts diskbench.Options) (diskbench.Space, error) {
+		return diskbench.Space{Path: opts.Path}, fmt.Errorf("%w: %s", diskbench.ErrNearlyFull, opts.Path)
+	}
+
+	config := ExecutionConfig{SelectedOptions: map[string]bool{"disk": true}, DiskPath: "/data", DiskSafeMode: true}
+	if err := diskSpaceWarning(config); !errors.Is(err, diskbench.ErrNearlyFull) {
+		t.Fatalf("diskSpaceWarning() = %v", err)
+	}
+	config.DiskSafeMode = false
+	if err := diskSpaceWarning(config); err != nil {
+		t.Fatalf("diskSpaceWarning(unsafe) = %v", err)
+	}
+	config.DiskSafeMode = true
+	config.SelectedOptions["disk"] = false
+	if err := diskSpaceWarning(config); err != nil {
+		t.Fatalf("diskSpaceWarning(disk not selected) = %v", err)
+	}
+
+	checkDiskSpace = func(diskbench.Options) (diskbench.Space, error) { return diskbench.Space{}, errors.New("no such path") }
+	config.SelectedOptions["disk"] = true
+	if err := diskSpaceWarning(config); err != nil {
+		t.Fatalf("diskSpaceWarning(stat error) = %v", err)
+	}
+}
+
+func TestDiskSafetySizeShrinksFio(t *testing.T) {
+	old := checkDiskSpace
+	t.Cleanup(func() { checkDiskSpace = old })
+	space := diskbench.Space{Path: "/data", Total: 20 << 30, Free: 3 << 30}
+	checkDiskSpace = func(diskbench.Options) (diskbench.Space, error) {
+		return space, diskbench.ErrNearlyFull
+	}
+
+	config := ExecutionConfig{SelectedOptions: map[string]bool{"disk": true}, DiskPath: "/data", DiskSafeMode: true, DiskMethod: "fio", DiskFileSize: 2 << 30}
+	if size, err := diskSafetySize(config); err != nil || size != 1<<30 {
+		t.Fatalf("diskSafetySize() = %d, %v", size, err)
+	}
+	config.DiskMethod = "dd"
+	if size, err := diskSafetySize(config); !errors.Is(err, diskbench.ErrNearlyFull) || size != 0 {
+		t.Fatalf("diskSafetySize(dd) = %d, %v", size, err)
+	}
+	config.DiskMethod = "fio"
+	space.Free = 2<<30 + 8<<20
+	if size, err := diskSafetySize(config); !errors.Is(err, diskbench.ErrNearlyFull) || size != 0 {
+		t.Fatalf("diskSafetySize(full) = %d, %v", size, err)
+	}
+}
+
+func TestDiskConfigInputsPersist(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.DiskBlockSizesEntry.SetText("8k")
+	ui.DiskFileSizeEntry.SetText("1G")
+	ui.DiskSafeModeCheck.SetChecked(false)
+	state := ui.snapshotUIState()
+
+	other := newTestUIForTest(t)
+	other.restoreUIState(state)
+	if other.DiskBlockSizesEntry.Text != "8k" || other.DiskFileSizeEntry.Text != "1G" || other.DiskSafeModeCheck.Checked {
+		t.Fatalf("restored = %q %q %v", other.DiskBlockSizesEntry.Text, other.DiskFileSizeEntry.Text, other.DiskSafeModeCheck.Checked)
+	}
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/docker"
+	"github.com/oneclickvirt/ecs-gui/ecsbin"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+const dockerTargetPreferenceKey = "docker_target"
+
+// dockerExecutionRunner 通过 docker exec 在容器中运行 goecs，并把输出回传到终端
+type dockerExecutionRunner struct {
+	target      docker.Target
+	client      docker.Client
+	localBinary string
+	version     string
+	fetch       remote.Fetcher
+}
+
+func newDockerRunner(target docker.Target, config ExecutionConfig) dockerExecutionRunner {
+	runner := dockerExecutionRunner{
+		target:      target,
+		client:      docker.NewClient(target),
+		localBinary: config.RemoteBinary,
+		version:     config.RemoteVersion,
+	}
+	if runner.version == "" {
+		runner.version = ecsVersion
+	}
+	if dir := config.RemoteCache; dir != "" {
+		runner.fetch = func(ctx context.Context, version, asset string) (string, error) {
+			cache := ecsbin.Cache{Dir: dir, Client: downloadHTTPClient(config.Proxy, config.Mirror)}
+			return fetchECSBinary(ctx, cache, version, asset)
+		}
+	}
+	return runner
+}
+
+func (runner dockerExecutionRunner) Run(run *Run) executionOutcome {
+	ctx, config, emit := run.Context, run.Config, run.Output
+	// 与 SSH 远程测试相同，只有文本输出，按分区标题推进阶段
+	steps := append([]string{"progress.docker_prepare"}, outputStageSteps(config)...)
+	tracker := newProgressTracker(run.Progress, steps)
+
+	tracker.start("progress.docker_prepare")
+	emit(fmt.Sprintf("docker exec %s\n", runner.target.Label()))
+	binary, err := docker.Prepare(ctx, runner.client, runner.target.Container, runner.version, runner.localBinary, runner.fetch, emit)
+	if err != nil {
+		return executionOutcome{Err: err}
+	}
+	tracker.finish("progress.docker_prepare")
+
+	args := append([]string{binary}, withBackendFlags(goecsRemoteArgs(config), config)...)
+	if len(config.BackendEnv) > 0 {
+		args = append(append([]string{"env"}, config.BackendEnv...), args...)
+	}
+	emit("$ " + strings.Join(args, " ") + "\n")
+	watcher := newStageWatcher(tracker)
+	err = runner.client.RunWithInput(ctx, runner.target.Container, docker.WorkDir, args, run.Input(), func(text string) {
+		watcher.Observe(text)
+		emit(text)
+	})
+	watcher.Close()
+	return executionOutcome{Err: err}
+}
+
+// setDockerTarget 设置（或清除）测试使用的容器并保存；设置后不再使用 SSH 远程目标
+func (ui *TestUI) setDockerTarget(target *docker.Target) {
+	ui.dockerTarget = target
+	if ui.App != nil {
+		value := ""
+		if target != nil {
+			data, _ := json.Marshal(target)
+			value = string(data)
+		}
+		ui.App.Preferences().SetString(dockerTargetPreferenceKey, value)
+	}
+	if target != nil && ui.RemoteEnableCheck != nil {
+		ui.RemoteEnableCheck.SetChecked(false)
+	}
+	if ui.dockerLabel == nil || ui.dockerRow == nil {
+		return
+	}
+	if target == nil {
+		ui.dockerRow.Hide()
+		return
+	}
+	ui.dockerLabel.SetText(fmt.Sprintf(ui.tr("docker.active"), target.Label()))
+	ui.dockerRow.Show()
+}
+
+// loadDockerTarget 读取上次保存的容器目标
+func (ui *TestUI) loadDockerTarget() *docker.Target {
+	var target docker.Target
+	if err := json.Unmarshal([]byte(ui.App.Preferences().String(dockerTargetPreferenceKey)), &target); err != nil || target.Validate() != nil {
+		return nil
+	}
+	return &target
+}
+
+// createDockerRow 创建远程卡片中的容器目标提示行，未选择容器时隐藏
+func (ui *TestUI) createDockerRow() fyne.CanvasObject {
+	ui.dockerLabel = widget.NewLabel("")
+	ui.dockerRow = container.NewHBox(
+		widget.NewIcon(theme.StorageIcon()),
+		ui.dockerLabel,
+		widget.NewButtonWithIcon("", theme.CancelIcon(), func() { ui.setDockerTarget(nil) }),
+	)
+	ui.setDockerTarget(ui.loadDockerTarget())
+	return ui.dockerRow
+}
+
+// showDockerDialog 选择 Docker 主机与运行中的容器，作为测试目标
+func (ui *TestUI) showDockerDialog() {
+	hostEntry := widget.NewEntry()
+	hostEntry.SetPlaceHolder(ui.tr("placeholder.docker_host"))
+	containerSelect := widget.NewSelectEntry(nil)
+	containerSelect.SetPlaceHolder(ui.tr("placeholder.docker_container"))
+	if ui.dockerTarget != nil {
+		hostEntry.SetText(ui.dockerTarget.Host)
+		containerSelect.SetText(ui.dockerTarget.Container)
+	}
+	status := widget.NewLabel("")
+	status.Wrapping = fyne.TextWrapWord
+
+	var refresh *widget.Button
+	refresh = widget.NewButtonWithIcon(ui.tr("docker.refresh"), theme.ViewRefreshIcon(), func() {
+		refresh.Disable()
+		status.SetText(ui.tr("docker.listing"))
+		client := docker.Client{Host: strings.TrimSpace(hostEntry.Text)}
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
+			defer cancel()
+			containers, err := client.Containers(ctx)
+			uiDo(func() {
+				refresh.Enable()
+				if err != nil {
+					status.SetText(ui.friendlyErrorMessage(err))
+					return
+				}
+				names := make([]string, len(containers))
+				for i, c := range containers {
+					names[i] = c.Name
+				}
+				containerSelect.SetOptions(names)
+				if len(names) > 0 && containerSelect.Text == "" {
+					containerSelect.SetText(names[0])
+				}
+				status.SetText(fmt.Sprintf(ui.tr("docker.found"), len(names)))
+			})
+		}()
+	})
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("label.docker_host"), hostEntry),
+		widget.NewFormItem(ui.tr("label.docker_container"), container.NewBorder(nil, nil, nil, refresh, containerSelect)),
+		widget.NewFormItem("", status),
+	}
+	form := dialog.NewForm(ui.tr("docker.title"), ui.tr("hosts.ok"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		target := docker.Target{Container: strings.TrimSpace(containerSelect.Text), Host: strings.TrimSpace(hostEntry.Text)}
+		if err := target.Validate(); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		ui.setDockerTarget(&target)
+	}, ui.Window)
+	form.Resize(fyne.NewSize(520, 0))
+	form.Show()
+}
@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/docker"
+)
+
+func TestDockerTargetReplacesRemoteTarget(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.setDockerTarget(&docker.Target{Container: "web", Host: "ssh://root@vps.example.com"})
+
+	config := ui.collectExecutionConfig()
+	if config.Docker == nil || config.Remote != nil || config.local() {
+		t.Fatalf("config docker = %+v, remote = %+v", config.Docker, config.Remote)
+	}
+	if got := runHost(config); got != "web@vps.example.com" {
+		t.Fatalf("runHost = %q", got)
+	}
+	if _, ok := executionRunnerFor(config).(dockerExecutionRunner); !ok {
+		t.Fatalf("runner = %T, want dockerExecutionRunner", executionRunnerFor(config))
+	}
+	if got := ui.loadDockerTarget(); got == nil || got.Container != "web" {
+		t.Fatalf("saved target = %+v", got)
+	}
+
+	ui.RemoteEnableCheck.SetChecked(true)
+	if ui.dockerTarget != nil || ui.loadDockerTarget() != nil {
+		t.Fatal("enabling SSH remote should clear the container target")
+	}
+}
+
+func TestDockerRunnerStreamsOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake docker script needs a POSIX shell")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+case "$*" in
+  "exec -t "*) printf '%s\n' '--------------------- 基础信息查询 ---------------------' ' CPU 型号 : Test CPU' ;;
+esac
+`
+	binary := filepath.Join(dir, "docker")
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := ExecutionConfig{
+		SelectedOptions: map[string]bool{"basic": true},
+		Language:        "zh",
+		Docker:          &docker.Target{Container: "web"},
+		RemoteBinary:    "/opt/goecs",
+	}
+	runner := newDockerRunner(*config.Docker, config)
+	runner.client.Binary = binary
+
+	var out strings.Builder
+	var steps []string
+	outcome := runner.Run(newRun(context.Background(), config, func(s string) { out.WriteString(s) }, func(u ProgressUpdate) {
+		if !u.Done {
+			steps = append(steps, u.ItemKey)
+		}
+	}))
+	if outcome.Err != nil {
+		t.Fatal(outcome.Err)
+	}
+	if !strings.Contains(out.String(), "docker cp /opt/goecs web:"+docker.WorkDir+"/goecs") || !strings.Contains(out.String(), "CPU 型号 : Test CPU") {
+		t.Fatalf("output = %q", out.String())
+	}
+	if len(steps) == 0 || steps[0] != "progress.docker_prepare" {
+		t.Fatalf("steps = %v", steps)
+	}
+}
@@ -0,0 +1,306 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/ecsbin"
+	"github.com/oneclickvirt/ecs-gui/internal/ghrelease"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+const (
+	// ecsVersionPreferenceKey 记录固定使用的 goecs 版本，为空时使用内置的 ecsVersion
+	ecsVersionPreferenceKey = "ecs_backend_version"
+	// ecsCacheDir 是应用数据目录下缓存已校验 goecs 的子目录
+	ecsCacheDir      = "ecs"
+	ecsReleaseLimit  = 20
+	ecsListTimeout   = 20 * time.Second
+	ecsFetchTimeout  = 10 * time.Minute
+	ecsDefaultTarget = "linux/amd64"
+)
+
+var (
+	// listECSReleases 与 fetchECSBinary 访问 GitHub Releases，测试中替换
+	listECSReleases = func(ctx context.Context, client *http.Client) ([]ghrelease.Release, error) {
+		return ecsbin.Releases(ctx, client, ecsReleaseLimit)
+	}
+	fetchECSBinary = func(ctx context.Context, cache ecsbin.Cache, version, asset string) (string, error) {
+		return cache.Fetch(ctx, version, asset)
+	}
+)
+
+// ecsPlatforms 是版本管理中可预先下载的平台，与 goecs 发布包一致
+var ecsPlatforms = []string{
+	"linux/amd64", "linux/arm64", "linux/386", "linux/arm", "linux/riscv64",
+	"darwin/amd64", "darwin/arm64", "freebsd/amd64", "windows/amd64", "windows/arm64",
+}
+
+// newRemoteRunner 按配置中的版本与缓存目录创建远程执行后端
+func newRemoteRunner(target remote.Target, config ExecutionConfig) remoteExecutionRunner {
+	runner := remoteExecutionRunner{target: target, localBinary: config.RemoteBinary, version: config.RemoteVersion}
+	if runner.version == "" {
+		runner.version = ecsVersion
+	}
+	if dir := config.RemoteCache; dir != "" {
+		runner.fetch = func(ctx context.Context, version, asset string) (string, error) {
+			cache := ecsbin.Cache{Dir: dir, Client: downloadHTTPClient(config.Proxy, config.Mirror)}
+			return fetchECSBinary(ctx, cache, version, asset)
+		}
+	}
+	return runner
+}
+
+// applyECSBackend 填入固定的 goecs 版本与本机缓存目录
+func (ui *TestUI) applyECSBackend(config *ExecutionConfig) {
+	config.RemoteVersion = ui.ecsBackendVersion()
+	config.RemoteCache = ui.ecsCache().Dir
+}
+
+// ecsBackendVersion 返回远程测试使用的 goecs 版本（不带 v 前缀）
+func (ui *TestUI) ecsBackendVersion() string {
+	if pinned := ghrelease.NormalizeVersion(ui.App.Preferences().String(ecsVersionPreferenceKey)); pinned != "" {
+		return pinned
+	}
+	return ghrelease.NormalizeVersion(ecsVersion)
+}
+
+func (ui *TestUI) ecsCache() ecsbin.Cache {
+	return ecsbin.Cache{Dir: ui.appDataDir(ecsCacheDir), Client: ui.downloadClient()}
+}
+
+// ecsManager 是 goecs 版本管理对话框：列出发布与本机缓存，下载校验、固定或回退版本
+type ecsManager struct {
+	ui       *TestUI
+	list     *widget.List
+	current  *widget.Label
+	status   *widget.Label
+	platform *widget.Select
+	versions []string
+	releases map[string]ghrelease.Release
+	cached   map[string][]string
+	selected string
+	// done 在最近一次后台操作的结果处理完后关闭
+	done chan struct{}
+}
+
+// showECSManager 打开 goecs 版本管理对话框
+func (ui *TestUI) showECSManager() {
+	m := ui.newECSManager()
+	d := dialog.NewCustom(ui.tr("ecs.title"), ui.tr("hosts.close"), m.content(), ui.Window)
+	d.Resize(fyne.NewSize(640, 460))
+	d.Show()
+	m.load()
+}
+
+func (ui *TestUI) newECSManager() *ecsManager {
+	m := &ecsManager{
+		ui:       ui,
+		current:  widget.NewLabel(""),
+		status:   widget.NewLabel(""),
+		releases: map[string]ghrelease.Release{},
+	}
+	m.status.Wrapping = fyne.TextWrapWord
+	m.list = widget.NewList(
+		func() int { return len(m.versions) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(m.rowText(m.versions[id]))
+		},
+	)
+	m.list.OnSelected = func(id widget.ListItemID) { m.selected = m.versions[id] }
+	m.list.OnUnselected = func(widget.ListItemID) { m.selected = "" }
+	m.platform = widget.NewSelect(ecsPlatforms, func(string) { m.list.Refresh() })
+	local := runtime.GOOS + "/" + runtime.GOARCH
+	if !slices.Contains(ecsPlatforms, local) {
+		local = ecsDefaultTarget
+	}
+	m.platform.SetSelected(local)
+	m.reload()
+	return m
+}
+
+func (m *ecsManager) content() fyne.CanvasObject {
+	ui := m.ui
+	hint := widget.NewLabel(ui.tr("ecs.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	top := container.NewVBox(
+		hint,
+		m.current,
+		container.NewBorder(nil, nil, widget.NewLabel(ui.tr("ecs.platform")), nil, m.platform),
+	)
+	actions := container.NewHBox(
+		widget.NewButtonWithIcon(ui.tr("sysinfo.refresh"), theme.ViewRefreshIcon(), m.load),
+		widget.NewButtonWithIcon(ui.tr("history.delete"), theme.DeleteIcon(), m.removeSelected),
+		layout.NewSpacer(),
+		widget.NewButtonWithIcon(ui.tr("ecs.reset"), theme.HistoryIcon(), func() { m.pin("") }),
+		widget.NewButtonWithIcon(ui.tr("ecs.download"), theme.DownloadIcon(), m.downloadSelected),
+		widget.NewButtonWithIcon(ui.tr("ecs.use"), theme.ConfirmIcon(), func() {
+			if m.requireSelection() {
+				m.pin(m.selected)
+			}
+		}),
+	)
+	return container.NewBorder(top, container.NewVBox(m.status, actions), nil, nil, m.list)
+}
+
+// asset 返回所选平台的发布包名称
+func (m *ecsManager) asset() (string, error) {
+	goos, goarch, _ := strings.Cut(m.platform.Selected, "/")
+	return ecsbin.AssetFor(goos, goarch)
+}
+
+func (m *ecsManager) rowText(version string) string {
+	text := "v" + version
+	if release, ok := m.releases[version]; ok && !release.Published.IsZero() {
+		text += "  " + release.Published.Local().Format("2006-01-02")
+	}
+	var tags []string
+	if version == ghrelease.NormalizeVersion(ecsVersion) {
+		tags = append(tags, m.ui.tr("ecs.default"))
+	}
+	if version == m.ui.ecsBackendVersion() {
+		tags = append(tags, m.ui.tr("ecs.in_use"))
+	}
+	if asset, err := m.asset(); err == nil && slices.Contains(m.cached[version], asset) {
+		tags = append(tags, m.ui.tr("ecs.cached"))
+	} else if len(m.cached[version]) > 0 {
+		tags = append(tags, fmt.Sprintf(m.ui.tr("ecs.cached_other"), len(m.cached[version])))
+	}
+	if len(tags) > 0 {
+		text += "  [" + strings.Join(tags, ", ") + "]"
+	}
+	return text
+}
+
+// reload 合并发布列表、本机缓存、内置版本与固定版本，按版本从新到旧显示
+func (m *ecsManager) reload() {
+	m.cached = map[string][]string{}
+	entries, _ := m.ui.ecsCache().Entries()
+	for _, entry := range entries {
+		m.cached[entry.Version] = append(m.cached[entry.Version], entry.Asset)
+	}
+	versions := []string{ghrelease.NormalizeVersion(ecsVersion), m.ui.ecsBackendVersion()}
+	for version := range m.releases {
+		versions = append(versions, version)
+	}
+	for version := range m.cached {
+		versions = append(versions, version)
+	}
+	slices.SortFunc(versions, func(a, b string) int { return ghrelease.CompareVersions(b, a) })
+	m.versions = slices.Compact(versions)
+
+	current := m.ui.ecsBackendVersion()
+	if m.ui.App.Preferences().String(ecsVersionPreferenceKey) == "" {
+		m.current.SetText(fmt.Sprintf(m.ui.tr("ecs.current_default"), current))
+	} else {
+		m.current.SetText(fmt.Sprintf(m.ui.tr("ecs.current_pinned"), current))
+	}
+	if !slices.Contains(m.versions, m.selected) {
+		m.selected = ""
+		m.list.UnselectAll()
+	}
+	m.list.Refresh()
+}
+
+// background 在后台执行 work，完成后在界面线程调用 finish
+func (m *ecsManager) background(work func() error, finish func(error)) {
+	done := make(chan struct{})
+	m.done = done
+	go func() {
+		err := work()
+		m.ui.runOnUI(func() {
+			defer close(done)
+			finish(err)
+			m.reload()
+		})
+	}()
+}
+
+// load 获取发布列表；失败时仍显示本机缓存的版本
+func (m *ecsManager) load() {
+	m.status.SetText(m.ui.tr("ecs.loading"))
+	var releases []ghrelease.Release
+	m.background(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), ecsListTimeout)
+		defer cancel()
+		var err error
+		releases, err = listECSReleases(ctx, m.ui.ecsCache().Client)
+		return err
+	}, func(err error) {
+		if err != nil {
+			m.status.SetText(m.ui.tr("ecs.list_failed") + " " + err.Error())
+			return
+		}
+		for _, release := range releases {
+			m.releases[release.Version()] = release
+		}
+		m.status.SetText("")
+	})
+}
+
+func (m *ecsManager) requireSelection() bool {
+	if m.selected == "" {
+		dialog.ShowInformation(m.ui.tr("dialog.hint"), m.ui.tr("ecs.select_first"), m.ui.Window)
+		return false
+	}
+	return true
+}
+
+// downloadSelected 下载所选版本在所选平台的发布包并校验 SHA-256
+func (m *ecsManager) downloadSelected() {
+	if !m.requireSelection() {
+		return
+	}
+	asset, err := m.asset()
+	if err != nil {
+		m.status.SetText(m.ui.tr("ecs.download_failed") + " " + err.Error())
+		return
+	}
+	version := m.selected
+	m.status.SetText(fmt.Sprintf(m.ui.tr("ecs.downloading"), version, asset))
+	var path string
+	m.background(func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), ecsFetchTimeout)
+		defer cancel()
+		var err error
+		path, err = fetchECSBinary(ctx, m.ui.ecsCache(), version, asset)
+		return err
+	}, func(err error) {
+		if err != nil {
+			m.status.SetText(m.ui.tr("ecs.download_failed") + " " + err.Error())
+			return
+		}
+		m.status.SetText(fmt.Sprintf(m.ui.tr("ecs.downloaded"), path))
+	})
+}
+
+// pin 固定远程测试使用的版本，version 为空时恢复内置版本；固定旧版本即为回退
+func (m *ecsManager) pin(version string) {
+	if version == ghrelease.NormalizeVersion(ecsVersion) {
+		version = ""
+	}
+	m.ui.App.Preferences().SetString(ecsVersionPreferenceKey, version)
+	m.reload()
+}
+
+func (m *ecsManager) removeSelected() {
+	if !m.requireSelection() {
+		return
+	}
+	if err := m.ui.ecsCache().Remove(m.selected); err != nil {
+		m.status.SetText(err.Error())
+	}
+	m.reload()
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/ecsbin"
+	"github.com/oneclickvirt/ecs-gui/internal/ghrelease"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func waitECSManager(t *testing.T, m *ecsManager) {
+	t.Helper()
+	select {
+	case <-m.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("goecs manager operation did not finish")
+	}
+}
+
+func TestECSManagerDownloadsPinsAndRollsBack(t *testing.T) {
+	oldList, oldFetch := listECSReleases, fetchECSBinary
+	t.Cleanup(func() { listECSReleases, fetchECSBinary = oldList, oldFetch })
+	listErr := error(nil)
+	listECSReleases = func(context.Context, *http.Client) ([]ghrelease.Release, error) {
+		return []ghrelease.Release{{Tag: "v9.0.1"}, {Tag: "v0.1.1"}}, listErr
+	}
+	var fetched []string
+	fetchECSBinary = func(_ context.Context, cache ecsbin.Cache, version, asset string) (string, error) {
+		fetched = append(fetched, version+" "+asset)
+		return cache.Path(version, asset), nil
+	}
+
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	defaultVersion := ghrelease.NormalizeVersion(ecsVersion)
+	m := ui.newECSManager()
+	m.load()
+	waitECSManager(t, m)
+	if len(m.versions) != 3 || m.versions[0] != "9.0.1" || !slices.Contains(m.versions, defaultVersion) {
+		t.Fatalf("versions = %v", m.versions)
+	}
+
+	m.platform.SetSelected("linux/arm64")
+	m.list.Select(0)
+	m.downloadSelected()
+	waitECSManager(t, m)
+	if len(fetched) != 1 || fetched[0] != "9.0.1 goecs_linux_arm64.zip" || !strings.Contains(m.status.Text, "goecs_linux_arm64") {
+		t.Fatalf("fetched %v, status %q", fetched, m.status.Text)
+	}
+
+	// 固定新版本后，远程运行使用该版本并通过本机缓存获取
+	m.pin("9.0.1")
+	config := ui.collectExecutionConfig()
+	runner := newRemoteRunner(remote.Target{Host: "203.0.113.10"}, config)
+	if config.RemoteVersion != "9.0.1" || runner.version != "9.0.1" || !strings.Contains(m.current.Text, "pinned") {
+		t.Fatalf("pinned: config %q, runner %q, label %q", config.RemoteVersion, runner.version, m.current.Text)
+	}
+	if _, err := runner.fetch(context.Background(), "9.0.1", "goecs_linux_amd64.zip"); err != nil || fetched[1] != "9.0.1 goecs_linux_amd64.zip" {
+		t.Fatalf("runner fetch: %v, fetched %v", err, fetched)
+	}
+
+	// 回退到旧版本，再恢复内置版本
+	m.pin("0.1.1")
+	if ui.ecsBackendVersion() != "0.1.1" {
+		t.Fatalf("rollback version = %q", ui.ecsBackendVersion())
+	}
+	m.pin("")
+	if ui.ecsBackendVersion() != defaultVersion || !strings.Contains(m.current.Text, "built-in") {
+		t.Fatalf("reset: version %q, label %q", ui.ecsBackendVersion(), m.current.Text)
+	}
+
+	// 无法访问 GitHub 时保留已知版本并提示
+	listErr = errors.New("offline")
+	m.load()
+	waitECSManager(t, m)
+	if !strings.Contains(m.status.Text, "offline") || !slices.Contains(m.versions, defaultVersion) {
+		t.Fatalf("offline: status %q, versions %v", m.status.Text, m.versions)
+	}
+}
+
+func TestRemoteRunnerWithoutCacheDownloadsOnHost(t *testing.T) {
+	runner := newRemoteRunner(remote.Target{Host: "203.0.113.10"}, ExecutionConfig{RemoteBinary: "/tmp/goecs"})
+	if runner.fetch != nil || runner.version != ecsVersion || runner.localBinary != "/tmp/goecs" {
+		t.Fatalf("runner = %+v", runner)
+	}
+}
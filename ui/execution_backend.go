@@ -1,7 +1,5 @@
 package ui
 
-import "context"
-
 // executionOutcome is the only result crossing the execution/UI boundary.
 // A structured build fills Report from the ecs API; the legacy build returns
 // an explicitly partial compatibility report.
@@ -11,13 +9,14 @@ type executionOutcome struct {
 	Structured bool
 }
 
+// executionRunner 执行一次运行：取消与暂停来自 run.Context，输出与进度经 run 发出
 type executionRunner interface {
-	Run(context.Context, ExecutionConfig, func(string), func(ProgressUpdate)) executionOutcome
+	Run(run *Run) executionOutcome
 }
 
-func executeWithRunner(ctx context.Context, runner executionRunner, config ExecutionConfig, output func(string), progress func(ProgressUpdate)) executionOutcome {
+func executeWithRunner(runner executionRunner, run *Run) executionOutcome {
 	if runner == nil {
 		return executionOutcome{Err: errExecutionRunnerUnavailable}
 	}
-	return runner.Run(ctx, config, output, progress)
+	return runner.Run(run)
 }
@@ -2,19 +2,18 @@
 
 package ui
 
-import "context"
-
 type legacyExecutionRunner struct{}
 
 func newExecutionRunner() executionRunner {
 	return legacyExecutionRunner{}
 }
 
-func (legacyExecutionRunner) Run(ctx context.Context, config ExecutionConfig, output func(string), progress func(ProgressUpdate)) executionOutcome {
-	executor := NewCommandExecutor(output)
-	executor.SetProgressCallback(progress)
-	executor.SetContext(ctx)
-	err := executor.Execute(config)
+func (legacyExecutionRunner) Run(run *Run) executionOutcome {
+	executor := NewCommandExecutor(run.Output)
+	executor.SetProgressCallback(run.Progress)
+	executor.SetContext(run.Context)
+	executor.SetInput(run.Stdin())
+	err := executor.Execute(run.Config)
 	report, _ := executor.StructuredResult()
 	return executionOutcome{Err: err, Report: report, Structured: false}
 }
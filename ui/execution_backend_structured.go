@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/oneclickvirt/ecs-gui/proxy"
 	ecsapi "github.com/oneclickvirt/ecs/api"
 )
 
@@ -30,19 +31,20 @@ func newExecutionRunner() executionRunner {
 	}}
 }
 
-func (runner structuredExecutionRunner) Run(ctx context.Context, config ExecutionConfig, output func(string), progress func(ProgressUpdate)) executionOutcome {
-	if ctx == nil {
-		ctx = context.Background()
-	}
+func (runner structuredExecutionRunner) Run(run *Run) executionOutcome {
+	ctx, config, output := run.Context, run.Config, run.Output
 	if err := ctx.Err(); err != nil {
 		return executionOutcome{Err: err, Structured: true}
 	}
 	if runner.api.checkPublicAccess == nil || runner.api.runAllTests == nil {
 		return executionOutcome{Err: errExecutionRunnerUnavailable, Structured: true}
 	}
-	tracker := newProgressTracker(progress, nil)
+	tracker := newProgressTracker(run.Progress, nil)
 	tracker.start("progress.precheck")
-	preCheck := runner.api.checkPublicAccess(3 * time.Second)
+	var preCheck ecsapi.NetCheckResult
+	if !config.OfflineMode {
+		preCheck = runner.api.checkPublicAccess(3 * time.Second)
+	}
 	if err := ctx.Err(); err != nil {
 		return executionOutcome{Err: err, Structured: true}
 	}
@@ -50,12 +52,15 @@ func (runner structuredExecutionRunner) Run(ctx context.Context, config Executio
 	tracker.finish("progress.precheck")
 
 	apiConfig := structuredAPIConfig(config)
+	pause := pauseGateFrom(ctx)
 	observer := func(event ecsapi.ProgressEvent) {
 		key, ok := sectionProgressKeys[event.Section]
 		if !ok {
 			return
 		}
 		if event.Phase == ecsapi.ProgressStarted {
+			// 阶段开始前是唯一的暂停点，取消后由 API 自行感知 ctx
+			_ = pause.Wait(ctx)
 			tracker.start(key)
 			return
 		}
@@ -71,26 +76,59 @@ func (runner structuredExecutionRunner) Run(ctx context.Context, config Executio
 	if err != nil {
 		return executionOutcome{Err: err, Structured: true}
 	}
-	if output != nil {
-		text := result.Output
-		if text == "" && report.Text != "" {
-			text = report.Text
-		}
-		if text != "" {
-			output(text)
-		}
+	text := result.Output
+	if text == "" {
+		text = report.Text
+	}
+	if text != "" {
+		output(text)
+	}
+	// goecs 不支持自定义 iperf3 目标、隧道对比与 GPU 基准，在其结果之后由界面自行运行并并入报告
+	if preCheck.Connected && len(config.IperfTargets) > 0 && ctx.Err() == nil {
+		tracker.start("progress.iperf3")
+		var text strings.Builder
+		section, component := runTimedStage(ctx, config, "progress.iperf3", &text, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+			return runIperfStage(ctx, &text, config.IperfTargets, config.Language, config.OutputWidth)
+		})
+		mergeStageReport(report, section, component)
+		output(text.String())
+		tracker.finish("progress.iperf3")
+	}
+	if preCheck.Connected && config.TunnelInterface != "" && ctx.Err() == nil {
+		tracker.start("progress.tunnel")
+		var text strings.Builder
+		section, component := runTimedStage(ctx, config, "progress.tunnel", &text, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+			return runTunnelStage(ctx, &text, config.TunnelInterface, config.IperfTargets, config.Language, config.OutputWidth)
+		})
+		mergeStageReport(report, section, component)
+		output(text.String())
+		tracker.finish("progress.tunnel")
+	}
+	if config.GPUInfo && ctx.Err() == nil {
+		tracker.start("progress.gpu")
+		var text strings.Builder
+		section, component := runTimedStage(ctx, config, "progress.gpu", &text, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+			return runGPUStage(ctx, &text, config.GPUBenchmark, config.Language, config.OutputWidth)
+		})
+		mergeStageReport(report, section, component)
+		output(text.String())
+		tracker.finish("progress.gpu")
 	}
 	var finalizeErr error
 	if runner.api.finalize != nil {
 		finalized, err := runner.api.finalize(finalizeCtx, preCheck, apiConfig, result)
 		finalizeErr = err
-		if output != nil && (finalized.HTTPURL != "" || finalized.HTTPSURL != "") {
+		if finalized.HTTPURL != "" || finalized.HTTPSURL != "" {
 			output(fmt.Sprintf("Http URL:  %s\nHttps URL: %s\n", finalized.HTTPURL, finalized.HTTPSURL))
 		}
 	}
-	if progress != nil {
-		progressFromStructuredReport(progress, *report)
+	if config.EnableUpload && !config.PrivacyMode && config.Proxy.Configured(proxy.Uploads) && preCheck.Connected && ctx.Err() == nil {
+		tracker.start("progress.upload")
+		message := uploadThroughProxy(ctx, config.Proxy, config.Language, config.FilePath, text)
+		output(message)
+		tracker.finish("progress.upload")
 	}
+	progressFromStructuredReport(run.Progress, *report)
 	tracker.finish("progress.finish")
 	return executionOutcome{Err: finalizeErr, Report: report, Structured: true}
 }
@@ -139,7 +177,7 @@ func structuredAPIConfig(config ExecutionConfig) *ecsapi.Config {
 	apiConfig := ecsapi.NewConfig(ecsVersion)
 	apiConfig.MenuMode = false
 	apiConfig.Language = config.Language
-	apiConfig.CpuTestMethod = config.CpuMethod
+	apiConfig.CpuTestMethod = effectiveCpuMethod(config)
 	apiConfig.CpuTestThreadMode = config.ThreadMode
 	apiConfig.MemoryTestMethod = config.MemoryMethod
 	apiConfig.DiskTestMethod = config.DiskMethod
@@ -153,7 +191,8 @@ func structuredAPIConfig(config ExecutionConfig) *ecsapi.Config {
 	apiConfig.BasicStatus = selected["basic"]
 	apiConfig.CpuTestStatus = selected["cpu"]
 	apiConfig.MemoryTestStatus = selected["memory"]
-	apiConfig.DiskTestStatus = selected["disk"]
+	// 安全模式下磁盘几乎写满时不运行硬盘测试（图形界面中确认后会关闭本次运行的安全模式）
+	apiConfig.DiskTestStatus = selected["disk"] && diskSpaceWarning(config) == nil
 	apiConfig.UtTestStatus = selected["unlock"] && !config.ChinaModeEnabled
 	apiConfig.SecurityTestStatus = selected["security"]
 	apiConfig.EmailTestStatus = selected["email"]
@@ -177,7 +216,8 @@ func structuredAPIConfig(config ExecutionConfig) *ecsapi.Config {
 	apiConfig.UnlockTestConcurrency = config.UnlockConcurrency
 	apiConfig.EnableLogger = config.LogEnabled
 	apiConfig.FilePath = config.FilePath
-	apiConfig.EnableUpload = config.EnableUpload && !config.PrivacyMode
+	// 上传单独设置了代理时由界面在汇总后上传
+	apiConfig.EnableUpload = config.EnableUpload && !config.PrivacyMode && !config.Proxy.Configured(proxy.Uploads)
 	apiConfig.AnalyzeResult = config.AnalyzeResult
 	apiConfig.PrivacyMode = config.PrivacyMode
 	apiConfig.DataOffline = config.DataOffline
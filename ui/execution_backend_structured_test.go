@@ -4,11 +4,13 @@ package ui
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/oneclickvirt/ecs-gui/diskbench"
 	ecsapi "github.com/oneclickvirt/ecs/api"
 )
 
@@ -37,14 +39,14 @@ func TestStructuredExecutionRunnerUsesOfflineFixtureAndSingleAPICall(t *testing.
 
 	var output string
 	var progressUpdates []ProgressUpdate
-	outcome := runner.Run(ctx, ExecutionConfig{
+	outcome := runner.Run(newRun(ctx, ExecutionConfig{
 		Language: "zh", SelectedOptions: map[string]bool{"basic": true},
 		CpuMethod: "sysbench", ThreadMode: "multi", MemoryMethod: "stream",
 		DiskMethod: "fio", DeepMode: true,
 		DeepDiskPaths: "/mnt/a,/mnt/b", DeepSMARTDevices: "/dev/sda",
 		DeepBurnDuration: 45 * time.Second, DeepGPUDevice: "gpu0",
 		PingSortOrder: "name", PingScope: "international", TCPSortOrder: "latency",
-	}, func(value string) { output += value }, func(update ProgressUpdate) { progressUpdates = append(progressUpdates, update) })
+	}, func(value string) { output += value }, func(update ProgressUpdate) { progressUpdates = append(progressUpdates, update) }))
 	if outcome.Err != nil || outcome.Report == nil {
 		t.Fatalf("unexpected outcome: %#v", outcome)
 	}
@@ -143,7 +145,7 @@ func TestStructuredExecutionRunnerHonorsCanceledContextBeforePrecheck(t *testing
 			return nil
 		},
 	}}
-	outcome := runner.Run(ctx, ExecutionConfig{}, nil, nil)
+	outcome := runner.Run(newRun(ctx, ExecutionConfig{}, nil, nil))
 	if outcome.Err != context.Canceled || checkCalls != 0 {
 		t.Fatalf("unexpected canceled outcome: %#v calls=%d", outcome, checkCalls)
 	}
@@ -171,10 +173,26 @@ func TestStructuredExecutionRunnerFinalizesFilesAndUpload(t *testing.T) {
 		},
 	}}
 	var output string
-	outcome := runner.Run(context.Background(), ExecutionConfig{
+	outcome := runner.Run(newRun(context.Background(), ExecutionConfig{
 		SelectedOptions: map[string]bool{}, FilePath: "result.txt", EnableUpload: true,
-	}, func(value string) { output += value }, nil)
+	}, func(value string) { output += value }, nil))
 	if outcome.Err != nil || finalizeCalls != 1 || !strings.Contains(output, "https://example.test/result") {
 		t.Fatalf("unexpected finalize outcome: %#v calls=%d output=%q", outcome, finalizeCalls, output)
 	}
 }
+
+func TestStructuredAPIConfigSkipsDiskTestOnNearlyFullDisk(t *testing.T) {
+	old := checkDiskSpace
+	t.Cleanup(func() { checkDiskSpace = old })
+	checkDiskSpace = func(opts diskbench.Options) (diskbench.Space, error) {
+		return diskbench.Space{}, fmt.Errorf("%w: %s", diskbench.ErrNearlyFull, opts.Path)
+	}
+	config := ExecutionConfig{SelectedOptions: map[string]bool{"disk": true}, DiskSafeMode: true}
+	if structuredAPIConfig(config).DiskTestStatus {
+		t.Fatal("disk test should be skipped on a nearly-full disk in safe mode")
+	}
+	config.DiskSafeMode = false
+	if !structuredAPIConfig(config).DiskTestStatus {
+		t.Fatal("disk test should run when safe mode is off")
+	}
+}
@@ -12,15 +12,11 @@ type fakeExecutionRunner struct {
 	result executionOutcome
 }
 
-func (fake *fakeExecutionRunner) Run(ctx context.Context, _ ExecutionConfig, output func(string), progress func(ProgressUpdate)) executionOutcome {
+func (fake *fakeExecutionRunner) Run(run *Run) executionOutcome {
 	fake.calls++
-	fake.gotCtx = ctx
-	if output != nil {
-		output("fixture output\n")
-	}
-	if progress != nil {
-		progress(ProgressUpdate{ItemKey: "progress.finish", Current: 1, Total: 1, Fraction: 1})
-	}
+	fake.gotCtx = run.Context
+	run.Output("fixture output\n")
+	run.Progress(ProgressUpdate{ItemKey: "progress.finish", Current: 1, Total: 1, Fraction: 1})
 	return fake.result
 }
 
@@ -30,7 +26,7 @@ func TestExecuteWithRunnerInvokesSingleRunnerAndPropagatesContext(t *testing.T)
 	fake := &fakeExecutionRunner{result: executionOutcome{Report: &StructuredRunResult{SchemaVersion: "goecs.report/v1", Status: "partial"}, Structured: true}}
 	var output string
 	var progress ProgressUpdate
-	got := executeWithRunner(ctx, fake, ExecutionConfig{}, func(value string) { output += value }, func(value ProgressUpdate) { progress = value })
+	got := executeWithRunner(fake, newRun(ctx, ExecutionConfig{}, func(value string) { output += value }, func(value ProgressUpdate) { progress = value }))
 	if fake.calls != 1 {
 		t.Fatalf("runner calls = %d, want 1", fake.calls)
 	}
@@ -43,7 +39,7 @@ func TestExecuteWithRunnerInvokesSingleRunnerAndPropagatesContext(t *testing.T)
 }
 
 func TestExecuteWithRunnerRejectsNilRunner(t *testing.T) {
-	got := executeWithRunner(context.Background(), nil, ExecutionConfig{}, nil, nil)
+	got := executeWithRunner(nil, newRun(context.Background(), ExecutionConfig{}, nil, nil))
 	if !errors.Is(got.Err, errExecutionRunnerUnavailable) {
 		t.Fatalf("error = %v, want runner unavailable", got.Err)
 	}
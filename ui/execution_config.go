@@ -0,0 +1,269 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/diskbench"
+	"github.com/oneclickvirt/ecs-gui/geekbench"
+	"github.com/oneclickvirt/ecs-gui/iperf"
+)
+
+// testOptionKeys 是可选测试项的键，顺序与界面一致
+var testOptionKeys = []string{"basic", "cpu", "memory", "disk", "unlock", "security", "email", "backtrace", "nt3", "speed", "ping"}
+
+// executionForm 是生成 ExecutionConfig 所需的表单内容，图形界面与无界面模式共用同一套解析规则
+type executionForm struct {
+	language   string // zh 或 en
+	preset     string
+	checks     map[string]bool
+	selections map[string]string
+	entries    map[string]string
+}
+
+func (f executionForm) selection(key, fallback string) string {
+	if value := strings.TrimSpace(f.selections[key]); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// lowerSelection 与 selectedOrDefault 相同：去掉空白并转为小写
+func (f executionForm) lowerSelection(key, fallback string) string {
+	return strings.ToLower(f.selection(key, fallback))
+}
+
+func (f executionForm) entry(key string) string {
+	return strings.TrimSpace(f.entries[key])
+}
+
+// selectTests 只选中给定的测试项（不区分大小写），遇到未知测试项时返回错误
+func (f *executionForm) selectTests(tests []string) error {
+	known := make(map[string]bool, len(testOptionKeys))
+	for _, key := range testOptionKeys {
+		known[key] = true
+	}
+	selected := make(map[string]bool, len(tests))
+	for _, key := range tests {
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !known[key] {
+			return fmt.Errorf("unknown test %q (available: %s)", key, strings.Join(testOptionKeys, ","))
+		}
+		selected[key] = true
+	}
+	for _, key := range testOptionKeys {
+		f.checks[key] = selected[key]
+	}
+	f.preset = "custom"
+	return nil
+}
+
+// hasTests 与 hasSelectedTests 相同：TGDC、网站延迟与 GPU 信息可以单独运行；离线模式下只计入本地测试
+func (f executionForm) hasTests() bool {
+	offline := f.checks["offlineMode"]
+	for _, key := range testOptionKeys {
+		if f.checks[key] && !(offline && offlineOption(key)) {
+			return true
+		}
+	}
+	return f.checks["gpu"] || !offline && (f.checks["pingTgdc"] || f.checks["pingWeb"])
+}
+
+func (ui *TestUI) currentExecutionForm() executionForm {
+	return executionForm{
+		language:   ui.selectedLanguageCode(),
+		preset:     ui.selectedPresetKey,
+		checks:     ui.formChecks(),
+		selections: ui.formSelections(),
+		entries:    ui.formEntries(),
+	}
+}
+
+func (ui *TestUI) collectExecutionConfig() ExecutionConfig {
+	config := buildExecutionConfig(ui.currentExecutionForm())
+
+	// 远程目标无效时由 startTests 提前提示，这里只在有效时填入
+	config.GeekbenchAccepted = ui.geekbenchLicenseAccepted(config.GeekbenchVersion)
+	config.Remote, _ = ui.remoteTarget()
+	ui.withSavedSudoPassword(config.Remote)
+	if config.Remote == nil && ui.dockerTarget != nil {
+		target := *ui.dockerTarget
+		config.Docker = &target
+	}
+	if !config.local() {
+		config.RemoteBinary = strings.TrimSpace(ui.RemoteBinaryEntry.Text)
+	}
+	ui.applyECSBackend(&config)
+	config.Proxy = ui.proxySettings()
+	config.Mirror = ui.mirrorSettings()
+	config.Expect = ui.expectSettings()
+	return config
+}
+
+// buildExecutionConfig 按表单内容生成执行配置，缺失或无效的值使用默认值；不包含远程目标
+func buildExecutionConfig(form executionForm) ExecutionConfig {
+	language := "zh"
+	if form.language == langEN {
+		language = "en"
+	}
+
+	spNum := 2
+	if parsed, err := strconv.Atoi(form.entries["spNum"]); err == nil && parsed > 0 {
+		spNum = parsed
+	}
+	if spNum > 20 {
+		spNum = 20
+	}
+
+	outputWidth := 82
+	if parsed, err := strconv.Atoi(form.entries["outputWidth"]); err == nil && parsed >= 60 {
+		outputWidth = parsed
+	}
+	if outputWidth > 120 {
+		outputWidth = 120
+	}
+
+	unlockRegion := form.selections["unlockRegion"]
+	if unlockRegion == "" {
+		unlockRegion = "0"
+	}
+	unlockConcurrency := 20
+	if parsed, err := strconv.Atoi(form.entry("unlockConcurrency")); err == nil && parsed > 0 {
+		unlockConcurrency = parsed
+	}
+	if unlockConcurrency > 100 {
+		unlockConcurrency = 100
+	}
+	filePath := form.entries["outputFile"]
+	if filePath == "" {
+		filePath = "goecs.md"
+	}
+	deepMode := form.checks["deepMode"]
+	deepDiskPaths, deepSMARTDevices, deepGPUDevice := "", "", ""
+	deepBurnDuration := time.Duration(0)
+	if deepMode {
+		deepDiskPaths = form.entry("deepDiskPaths")
+		deepSMARTDevices = form.entry("deepSMART")
+		deepGPUDevice = form.entry("deepGPU")
+		if parsed, err := time.ParseDuration(form.entry("deepBurn")); err == nil && parsed > 0 {
+			deepBurnDuration = parsed
+		}
+	}
+	maxDuration := 15 * time.Minute
+	if parsed, err := time.ParseDuration(form.entry("maxDuration")); err == nil && parsed > 0 && parsed <= 15*time.Minute {
+		maxDuration = parsed
+	}
+	hardwareBudgetLimit := min(2*time.Minute, maxDuration)
+	if deepMode {
+		hardwareBudgetLimit = maxDuration
+	}
+	hardwareBudget := hardwareBudgetLimit
+	if parsed, err := time.ParseDuration(form.entry("hardwareBudget")); err == nil && parsed > 0 && parsed <= hardwareBudgetLimit {
+		hardwareBudget = parsed
+	}
+	privacyMode := form.checks["privacyMode"]
+	speedGroups, speedServerIDs := parseSpeedNodes(form.entries["speedNodes"])
+	// 设置文件被手动改坏时只跳过无效的行，编辑对话框保存前已校验
+	iperfTargets, _ := iperf.ParseTargets(form.entries["iperfTargets"])
+	stageTimeouts, _ := parseStageTimeouts(form.entries["stageTimeouts"])
+	stageRetries, retryBackoff := parseStageRetries(form.entries["stageRetries"], form.entries["retryBackoff"])
+	// 与阶段超时一样，设置文件中无效的内容按未设置处理
+	backendEnv, _ := parseBackendEnv(form.entries["backendEnv"])
+	backendFlags, _ := parseBackendFlags(form.entries["backendFlags"])
+	networkLoad := networkLoadFull
+	if form.entries["networkLoad"] == networkLoadLight {
+		networkLoad = networkLoadLight
+	}
+
+	// 自定义 fio 参数无效时退回默认值，界面上的输入框保存前已校验
+	diskBlockSizes, err := diskbench.ParseBlockSizes(form.entries["diskBlockSizes"])
+	if err != nil {
+		diskBlockSizes = nil
+	}
+	diskFileSize, _ := diskbench.ParseSize(form.entries["diskFileSize"])
+	// 旧版设置文件没有安全模式选项，默认开启
+	diskSafeMode, ok := form.checks["diskSafeMode"]
+	diskSafeMode = diskSafeMode || !ok
+
+	geekbenchVersion := form.selection("geekbenchVersion", geekbench.Versions[0])
+	if !slices.Contains(geekbenchVersionOptions(), geekbenchVersion) {
+		geekbenchVersion = geekbench.Versions[0]
+	}
+
+	selected := make(map[string]bool, len(testOptionKeys))
+	for _, key := range testOptionKeys {
+		selected[key] = form.checks[key]
+	}
+
+	config := ExecutionConfig{
+		SelectedOptions:   selected,
+		Language:          language,
+		ChinaModeEnabled:  form.checks["chinaMode"],
+		DeepMode:          deepMode,
+		DeepDiskPaths:     deepDiskPaths,
+		DeepSMARTDevices:  deepSMARTDevices,
+		DeepBurnDuration:  deepBurnDuration,
+		DeepGPUDevice:     deepGPUDevice,
+		AutoDiskMethod:    form.checks["autoDisk"],
+		CpuMethod:         form.selection("cpuMethod", "sysbench"),
+		GeekbenchVersion:  geekbenchVersion,
+		ThreadMode:        form.selection("threadMode", "multi"),
+		MemoryMethod:      form.selection("memMethod", "stream"),
+		DiskMethod:        form.selection("diskMethod", "fio"),
+		DiskPath:          form.entries["diskPath"],
+		DiskMulti:         form.checks["diskMulti"],
+		DiskBlockSizes:    diskBlockSizes,
+		DiskFileSize:      diskFileSize,
+		DiskSafeMode:      diskSafeMode,
+		Nt3Location:       form.selection("nt3Loc", "GZ"),
+		Nt3Type:           form.selection("nt3Type", "both"),
+		SpNum:             spNum,
+		SpeedGroups:       speedGroups,
+		SpeedServerIDs:    speedServerIDs,
+		IperfTargets:      iperfTargets,
+		TunnelInterface:   strings.TrimSpace(form.entries["tunnelInterface"]),
+		PingSortOrder:     form.lowerSelection("pingSort", "latency"),
+		PingScope:         form.lowerSelection("pingScope", "auto"),
+		TCPSortOrder:      form.lowerSelection("tcpSort", "name"),
+		PingTgdc:          form.checks["pingTgdc"],
+		PingWeb:           form.checks["pingWeb"],
+		UnlockRegion:      unlockRegion,
+		UnlockIpVersion:   form.selection("unlockIpVer", "auto"),
+		UnlockShowIP:      form.checks["unlockShowIP"],
+		UnlockInterface:   form.entry("unlockInterface"),
+		UnlockDNS:         form.entry("unlockDNS"),
+		UnlockHTTPProxy:   form.entry("unlockHTTPProxy"),
+		UnlockSOCKSProxy:  form.entry("unlockSOCKSProxy"),
+		UnlockConcurrency: unlockConcurrency,
+		EnableUpload:      form.checks["resultUpload"] && !privacyMode,
+		AnalyzeResult:     form.checks["analysis"],
+		FilePath:          filePath,
+		JSONPath:          form.entry("jsonPath"),
+		OutputWidth:       outputWidth,
+		MaxDuration:       maxDuration,
+		HardwareBudget:    hardwareBudget,
+		DataOffline:       form.checks["dataOffline"],
+		PrivacyMode:       privacyMode,
+		OfflineMode:       form.checks["offlineMode"],
+		PresetKey:         form.preset,
+		LogEnabled:        form.checks["enableLog"],
+		RemoteCleanup:     form.checks["remoteClean"],
+		StageTimeouts:     stageTimeouts,
+		StageRetries:      stageRetries,
+		RetryBackoff:      retryBackoff,
+		NetworkLoad:       networkLoad,
+		BandwidthCap:      parseBandwidthCap(form.entries["bandwidthCap"]),
+		BackendEnv:        backendEnv,
+		BackendFlags:      backendFlags,
+		GPUInfo:           form.checks["gpu"],
+		GPUBenchmark:      form.checks["gpu"] && form.checks["gpuBench"],
+	}
+	config.applyNetworkLimits()
+	if config.OfflineMode {
+		config.applyOfflineMode()
+	}
+	return config
+}
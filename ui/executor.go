@@ -2,6 +2,7 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -9,6 +10,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	unlockexecutor "github.com/oneclickvirt/UnlockTests/executor"
@@ -18,7 +20,9 @@ import (
 	"github.com/oneclickvirt/basics/utils"
 	cputestmodel "github.com/oneclickvirt/cputest/model"
 	disktestmodel "github.com/oneclickvirt/disktest/disk"
+	"github.com/oneclickvirt/ecs-gui/diskbench"
 	"github.com/oneclickvirt/ecs-gui/internal/appmeta"
+	"github.com/oneclickvirt/ecs-gui/proxy"
 	gostunmodel "github.com/oneclickvirt/gostun/model"
 	memorytestmodel "github.com/oneclickvirt/memorytest/memory"
 	nt3model "github.com/oneclickvirt/nt3/model"
@@ -38,6 +42,7 @@ type CommandExecutor struct {
 	progressCallback func(ProgressUpdate)
 	core             CoreRunner
 	ctx              context.Context
+	input            io.Reader
 	cancel           context.CancelFunc
 	structuredMu     sync.RWMutex
 	structuredResult *StructuredRunResult
@@ -55,6 +60,11 @@ func (e *CommandExecutor) SetContext(ctx context.Context) {
 	e.ctx = ctx
 }
 
+// SetInput 设置测试中启动的外部工具读取的标准输入，为 nil 时不提供输入
+func (e *CommandExecutor) SetInput(input io.Reader) {
+	e.input = input
+}
+
 func (e *CommandExecutor) SetProgressCallback(callback func(ProgressUpdate)) {
 	e.progressCallback = callback
 }
@@ -87,10 +97,12 @@ type progressTracker struct {
 	current  int
 	started  map[string]bool
 	finished map[string]bool
+	timedOut map[string]time.Duration
+	retries  map[string]int
 }
 
 func newProgressTracker(callback func(ProgressUpdate), steps []string) *progressTracker {
-	return &progressTracker{callback: callback, steps: steps, started: make(map[string]bool), finished: make(map[string]bool)}
+	return &progressTracker{callback: callback, steps: steps, started: make(map[string]bool), finished: make(map[string]bool), timedOut: make(map[string]time.Duration), retries: make(map[string]int)}
 }
 
 func (t *progressTracker) start(itemKey string) {
@@ -113,6 +125,7 @@ func (t *progressTracker) start(itemKey string) {
 		Current:  t.current + 1,
 		Total:    total,
 		Fraction: float64(t.current) / float64(total),
+		Pending:  t.pending(),
 	})
 }
 
@@ -139,9 +152,31 @@ func (t *progressTracker) finish(itemKey string) {
 		Current:  t.current,
 		Total:    total,
 		Fraction: float64(t.current) / float64(total),
+		Done:     true,
+		Pending:  t.pending(),
 	})
 }
 
+// timeout 把超过时限 limit 的 itemKey 阶段记为超时，进度照常推进
+func (t *progressTracker) timeout(itemKey string, limit time.Duration) {
+	if t == nil {
+		return
+	}
+	t.timedOut[itemKey] = limit
+	t.finish(itemKey)
+}
+
+// pending 返回计划中尚未开始的阶段
+func (t *progressTracker) pending() []string {
+	var keys []string
+	for _, key := range t.steps {
+		if !t.started[key] && !t.finished[key] {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
 func (t *progressTracker) run(itemKey string, fn func() error) error {
 	t.start(itemKey)
 	if err := fn(); err != nil {
@@ -208,6 +243,15 @@ func buildProgressSteps(config ExecutionConfig, connected bool) []string {
 	if connected && selected["speed"] {
 		steps = append(steps, "progress.speed")
 	}
+	if connected && len(config.IperfTargets) > 0 {
+		steps = append(steps, "progress.iperf3")
+	}
+	if connected && config.TunnelInterface != "" {
+		steps = append(steps, "progress.tunnel")
+	}
+	if config.GPUInfo {
+		steps = append(steps, "progress.gpu")
+	}
 	if config.AnalyzeResult {
 		steps = append(steps, "progress.summary")
 	}
@@ -277,8 +321,11 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 		})
 	}
 
-	// 检查网络连接
-	preCheck := utils.CheckPublicAccess(3 * time.Second)
+	// 检查网络连接，离线模式下按无公网处理
+	var preCheck utils.NetCheckResult
+	if !config.OfflineMode {
+		preCheck = utils.CheckPublicAccess(3 * time.Second)
+	}
 	effectiveNt3Type = effectiveNT3TypeForStack(effectiveNt3Type, preCheck.StackType)
 	tracker := newProgressTracker(e.progressCallback, buildProgressSteps(config, preCheck.Connected))
 	tracker.finish("progress.precheck")
@@ -288,10 +335,13 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 		wg1, wg2                                       sync.WaitGroup
 		ipv4, ipv6, basicInfo, securityInfo, emailInfo string
 		mediaInfo                                      string
+		unlockRetries                                  int
 		outputMutex                                    sync.Mutex
 		captureMutex                                   sync.Mutex
 		captured                                       strings.Builder
 		captureTruncated                               bool
+		stageSections                                  []StructuredSection
+		stageComponents                                []StructuredComponent
 	)
 	startTime := time.Now()
 	defer func() {
@@ -299,7 +349,11 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 		if ctx == nil {
 			ctx = context.Background()
 		}
-		e.setStructuredResult(buildGUIStructuredReport(config, preCheck.Connected, tracker, runErr, ctx, startTime, time.Now()))
+		report := buildGUIStructuredReport(config, preCheck.Connected, tracker, runErr, ctx, startTime, time.Now())
+		for i := range stageSections {
+			mergeStageReport(&report, stageSections[i], stageComponents[i])
+		}
+		e.setStructuredResult(report)
 	}()
 	captureLimit := resultCaptureLimit()
 	appendCaptured := func(text string) {
@@ -423,8 +477,12 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	os.Stdout = w
 	os.Stderr = w
 
-	// 检查取消的辅助函数
+	// 检查取消的辅助函数；同时作为阶段边界，暂停时在此等待
+	pause := pauseGateFrom(e.ctx)
 	checkCancelled := func() bool {
+		if pause.Wait(e.ctx) != nil {
+			return true
+		}
 		select {
 		case <-e.ctx.Done():
 			return true
@@ -433,6 +491,24 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 		}
 	}
 
+	// runStage 在 key 阶段的时限内运行 fn，超时后结束该阶段派生的外部工具、等阶段返回后在终端提示并把阶段记为超时，
+	// 随后继续下一阶段；调用方持有 outputMutex，返回 false 表示整个测试已被取消
+	runStage := func(key string, fn func(ctx context.Context)) bool {
+		tracker.start(key)
+		limit := config.stageTimeout(key)
+		processes := newProcessScope()
+		switch err := runStageWithTimeout(e.ctx, limit, fn, func() { processes.signal(true) }); {
+		case errors.Is(err, errStageTimeout):
+			fmt.Print(stageTimeoutNote(language, limit))
+			tracker.timeout(key, limit)
+		case err != nil:
+			return false
+		default:
+			tracker.finish(key)
+		}
+		return true
+	}
+
 	// 执行测试（参考原goecs.go的runChineseTests和runEnglishTests顺序）
 	// 1. 打印头部和基本信息
 	if checkCancelled() {
@@ -480,17 +556,20 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	}
 
 	if cpuTestStatus {
-		tracker.start("progress.cpu")
 		outputMutex.Lock()
-		realTestMethod, res := e.core.CpuTest(language, config.CpuMethod, config.ThreadMode)
-		if language == "zh" {
-			PrintCenteredTitle(fmt.Sprintf("CPU测试-通过%s测试", realTestMethod), width)
-		} else {
-			PrintCenteredTitle(fmt.Sprintf("CPU-Test--%s-Method", realTestMethod), width)
-		}
-		fmt.Print(res)
+		ok := runStage("progress.cpu", func(context.Context) {
+			realTestMethod, res := e.cpuTest(language, config)
+			if language == "zh" {
+				PrintCenteredTitle(fmt.Sprintf("CPU测试-通过%s测试", realTestMethod), width)
+			} else {
+				PrintCenteredTitle(fmt.Sprintf("CPU-Test--%s-Method", realTestMethod), width)
+			}
+			fmt.Print(res)
+		})
 		outputMutex.Unlock()
-		tracker.finish("progress.cpu")
+		if !ok {
+			return fmt.Errorf("测试已取消")
+		}
 	}
 
 	// 3. 内存测试
@@ -499,17 +578,20 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	}
 
 	if memoryTestStatus {
-		tracker.start("progress.memory")
 		outputMutex.Lock()
-		realTestMethod, res := e.core.MemoryTest(language, config.MemoryMethod)
-		if language == "zh" {
-			PrintCenteredTitle(fmt.Sprintf("内存测试-通过%s测试", realTestMethod), width)
-		} else {
-			PrintCenteredTitle(fmt.Sprintf("Memory-Test--%s-Method", realTestMethod), width)
-		}
-		fmt.Print(res)
+		ok := runStage("progress.memory", func(context.Context) {
+			realTestMethod, res := e.core.MemoryTest(language, config.MemoryMethod)
+			if language == "zh" {
+				PrintCenteredTitle(fmt.Sprintf("内存测试-通过%s测试", realTestMethod), width)
+			} else {
+				PrintCenteredTitle(fmt.Sprintf("Memory-Test--%s-Method", realTestMethod), width)
+			}
+			fmt.Print(res)
+		})
 		outputMutex.Unlock()
-		tracker.finish("progress.memory")
+		if !ok {
+			return fmt.Errorf("测试已取消")
+		}
 	}
 
 	// 4. 磁盘测试
@@ -518,34 +600,70 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	}
 
 	if diskTestStatus {
-		tracker.start("progress.disk")
 		outputMutex.Lock()
-		if config.AutoDiskMethod {
-			realTestMethod, res := e.core.DiskTest(language, config.DiskMethod, config.DiskPath, config.DiskMulti, true)
-			if language == "zh" {
-				PrintCenteredTitle(fmt.Sprintf("硬盘测试-通过%s测试", realTestMethod), width)
-			} else {
-				PrintCenteredTitle(fmt.Sprintf("Disk-Test--%s-Method", realTestMethod), width)
-			}
-			fmt.Print(diskResultText(language, res))
-		} else {
-			if language == "zh" {
-				PrintCenteredTitle("硬盘测试-通过dd测试", width)
-			} else {
-				PrintCenteredTitle("Disk-Test--dd-Method", width)
-			}
-			_, res := e.core.DiskTest(language, "dd", config.DiskPath, config.DiskMulti, false)
-			fmt.Print(diskResultText(language, res))
-			if language == "zh" {
-				PrintCenteredTitle("硬盘测试-通过fio测试", width)
+		ok := runStage("progress.disk", func(ctx context.Context) {
+			diskOptions, customDisk := diskBenchOptions(config)
+			shrunk, err := diskSafetySize(config)
+			if err != nil {
+				if language == "zh" {
+					PrintCenteredTitle("硬盘测试", width)
+					fmt.Printf(" 安全模式：磁盘空间不足，已跳过硬盘测试（%v）\n", err)
+				} else {
+					PrintCenteredTitle("Disk-Test", width)
+					fmt.Printf(" Safe mode: disk test skipped because the disk is nearly full (%v)\n", err)
+				}
+			} else if customDisk || shrunk > 0 {
+				// 安全模式下剩余空间放不下原大小时改用缩小后的测试文件，此时也不再退回会写入固定大小的 dd
+				from := int64(0)
+				if shrunk > 0 {
+					from, diskOptions.Size = diskOptions.FileSize(), shrunk
+				}
+				res, err := e.core.CustomDiskTest(ctx, language, diskOptions)
+				realTestMethod := "fio"
+				if strings.TrimSpace(res) == "" && config.AutoDiskMethod && shrunk == 0 {
+					realTestMethod = "dd"
+					_, res = e.core.DiskTest(language, "dd", config.DiskPath, config.DiskMulti, false)
+				} else if err != nil && res != "" {
+					res += fmt.Sprintf(" %s: %v\n", pickLanguage(language, "部分块大小测试失败", "Some block sizes failed"), err)
+				}
+				if language == "zh" {
+					PrintCenteredTitle(fmt.Sprintf("硬盘测试-通过%s测试", realTestMethod), width)
+				} else {
+					PrintCenteredTitle(fmt.Sprintf("Disk-Test--%s-Method", realTestMethod), width)
+				}
+				fmt.Print(diskResultText(language, res))
+				if realTestMethod == "fio" && strings.TrimSpace(res) != "" {
+					fmt.Print(diskbench.SizeLine(language, diskOptions.FileSize(), from))
+				}
+			} else if config.AutoDiskMethod {
+				realTestMethod, res := e.core.DiskTest(language, config.DiskMethod, config.DiskPath, config.DiskMulti, true)
+				if language == "zh" {
+					PrintCenteredTitle(fmt.Sprintf("硬盘测试-通过%s测试", realTestMethod), width)
+				} else {
+					PrintCenteredTitle(fmt.Sprintf("Disk-Test--%s-Method", realTestMethod), width)
+				}
+				fmt.Print(diskResultText(language, res))
 			} else {
-				PrintCenteredTitle("Disk-Test--fio-Method", width)
+				if language == "zh" {
+					PrintCenteredTitle("硬盘测试-通过dd测试", width)
+				} else {
+					PrintCenteredTitle("Disk-Test--dd-Method", width)
+				}
+				_, res := e.core.DiskTest(language, "dd", config.DiskPath, config.DiskMulti, false)
+				fmt.Print(diskResultText(language, res))
+				if language == "zh" {
+					PrintCenteredTitle("硬盘测试-通过fio测试", width)
+				} else {
+					PrintCenteredTitle("Disk-Test--fio-Method", width)
+				}
+				_, res = e.core.DiskTest(language, "fio", config.DiskPath, config.DiskMulti, false)
+				fmt.Print(diskResultText(language, res))
 			}
-			_, res = e.core.DiskTest(language, "fio", config.DiskPath, config.DiskMulti, false)
-			fmt.Print(diskResultText(language, res))
-		}
+		})
 		outputMutex.Unlock()
-		tracker.finish("progress.disk")
+		if !ok {
+			return fmt.Errorf("测试已取消")
+		}
 	}
 
 	// 5. 启动异步测试（流媒体解锁和邮件端口）
@@ -559,7 +677,9 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 			defer wg1.Done()
 			// 检查取消
 			if !checkCancelled() {
-				mediaInfo = e.core.MediaTest(language, config.UnlockRegion, config.UnlockIpVersion, config.UnlockShowIP)
+				mediaInfo, unlockRetries = retryNetworkStage(e.ctx, config, "progress.unlock", func() string {
+					return e.core.MediaTest(language, config.UnlockRegion, config.UnlockIpVersion, config.UnlockShowIP)
+				}, nil)
 			}
 		}()
 	}
@@ -585,15 +705,16 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 			close(waitDone)
 		}()
 
+		limit, timedOut := config.stageTimeout("progress.unlock"), false
 		select {
 		case <-waitDone:
 			// 正常完成
 		case <-e.ctx.Done():
 			// 被取消
 			return fmt.Errorf("测试已取消")
-		case <-time.After(5 * time.Minute):
+		case <-time.After(limit):
 			// 超时
-			mediaInfo = "\n流媒体测试超时\n"
+			timedOut = true
 		}
 		outputMutex.Lock()
 		if language == "zh" {
@@ -601,9 +722,21 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 		} else {
 			PrintCenteredTitle("Cross-Border-Streaming-Media-Unlock", width)
 		}
-		fmt.Printf("%s", mediaInfo)
+		if timedOut {
+			fmt.Print(stageTimeoutNote(language, limit))
+		} else {
+			fmt.Printf("%s", mediaInfo)
+			if unlockRetries > 0 {
+				fmt.Print(stageRetriedNote(language, unlockRetries))
+			}
+		}
 		outputMutex.Unlock()
-		tracker.finish("progress.unlock")
+		if timedOut {
+			tracker.timeout("progress.unlock", limit)
+		} else {
+			tracker.retries["progress.unlock"] = unlockRetries
+			tracker.finish("progress.unlock")
+		}
 	}
 
 	// 8. 显示IP质量检测结果
@@ -630,15 +763,16 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 			close(waitDone)
 		}()
 
+		limit, timedOut := config.stageTimeout("progress.email"), false
 		select {
 		case <-waitDone:
 			// 正常完成
 		case <-e.ctx.Done():
 			// 被取消
 			return fmt.Errorf("测试已取消")
-		case <-time.After(3 * time.Minute):
+		case <-time.After(limit):
 			// 超时
-			emailInfo = "\n邮件端口测试超时\n"
+			timedOut = true
 		}
 		outputMutex.Lock()
 		if language == "zh" {
@@ -646,9 +780,17 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 		} else {
 			PrintCenteredTitle("Email-Port-Check", width)
 		}
-		fmt.Println(emailInfo)
+		if timedOut {
+			fmt.Print(stageTimeoutNote(language, limit))
+		} else {
+			fmt.Println(emailInfo)
+		}
 		outputMutex.Unlock()
-		tracker.finish("progress.email")
+		if timedOut {
+			tracker.timeout("progress.email", limit)
+		} else {
+			tracker.finish("progress.email")
+		}
 	}
 
 	// 10. 上游及回程线路检测
@@ -657,16 +799,19 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	}
 
 	if backtraceStatus && preCheck.Connected {
-		tracker.start("progress.backtrace")
 		outputMutex.Lock()
-		if language == "zh" {
-			PrintCenteredTitle("上游及回程线路检测", width)
-		} else {
-			PrintCenteredTitle("Upstreams-Backtrace-Check", width)
-		}
-		e.core.UpstreamsCheck(language)
+		ok := runStage("progress.backtrace", func(context.Context) {
+			if language == "zh" {
+				PrintCenteredTitle("上游及回程线路检测", width)
+			} else {
+				PrintCenteredTitle("Upstreams-Backtrace-Check", width)
+			}
+			e.core.UpstreamsCheck(language)
+		})
 		outputMutex.Unlock()
-		tracker.finish("progress.backtrace")
+		if !ok {
+			return fmt.Errorf("测试已取消")
+		}
 	}
 
 	// 11. 三网回程路由检测
@@ -675,16 +820,19 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	}
 
 	if nt3Status && preCheck.Connected {
-		tracker.start("progress.nt3")
 		outputMutex.Lock()
-		if language == "zh" {
-			PrintCenteredTitle("三网回程路由检测", width)
-		} else {
-			PrintCenteredTitle("NextTrace-3Networks-Check", width)
-		}
-		e.core.NextTrace3Check(language, config.Nt3Location, effectiveNt3Type)
+		ok := runStage("progress.nt3", func(context.Context) {
+			if language == "zh" {
+				PrintCenteredTitle("三网回程路由检测", width)
+			} else {
+				PrintCenteredTitle("NextTrace-3Networks-Check", width)
+			}
+			e.core.NextTrace3Check(language, config.Nt3Location, effectiveNt3Type)
+		})
 		outputMutex.Unlock()
-		tracker.finish("progress.nt3")
+		if !ok {
+			return fmt.Errorf("测试已取消")
+		}
 	}
 
 	// 12. PING值测试
@@ -697,80 +845,144 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	}
 
 	if pingTestStatus && preCheck.Connected {
-		tracker.start("progress.ping")
 		outputMutex.Lock()
-
-		// 判断是否为中国模式
-		if chinaModeEnabled {
-			// 中国模式：只测三网PING
-			if language == "zh" {
-				PrintCenteredTitle("PING值检测", width)
+		ok := runStage("progress.ping", func(context.Context) {
+			// 判断是否为中国模式
+			if chinaModeEnabled {
+				// 中国模式：只测三网PING
+				if language == "zh" {
+					PrintCenteredTitle("PING值检测", width)
+				} else {
+					PrintCenteredTitle("PING-Test", width)
+				}
+				pingResult := runPingProfile(config, language)
+				fmt.Println(pingResult)
 			} else {
-				PrintCenteredTitle("PING-Test", width)
+				// 非中国模式：根据配置测试
+				if language == "zh" {
+					PrintCenteredTitle("PING值检测", width)
+				} else {
+					PrintCenteredTitle("PING-Test", width)
+				}
+				pingResult := runPingProfile(config, language)
+				fmt.Println(pingResult)
+
+				// 根据用户配置决定是否测试TGDC和Web
+				if pingTgdc {
+					fmt.Println(pt.TelegramDCTest())
+				}
+				if pingWeb {
+					fmt.Println(pt.WebsiteTest())
+				}
 			}
-			pingResult := runPingProfile(config, language)
-			fmt.Println(pingResult)
-		} else {
-			// 非中国模式：根据配置测试
+		})
+		outputMutex.Unlock()
+		if !ok {
+			return fmt.Errorf("测试已取消")
+		}
+	}
+
+	// 单独的TGDC和Web测试（当pingTestStatus=false但用户单独启用时）
+	if !pingTestStatus && preCheck.Connected && (pingTgdc || pingWeb) {
+		outputMutex.Lock()
+		ok := runStage("progress.ping", func(context.Context) {
 			if language == "zh" {
 				PrintCenteredTitle("PING值检测", width)
 			} else {
 				PrintCenteredTitle("PING-Test", width)
 			}
-			pingResult := runPingProfile(config, language)
-			fmt.Println(pingResult)
 
-			// 根据用户配置决定是否测试TGDC和Web
 			if pingTgdc {
 				fmt.Println(pt.TelegramDCTest())
 			}
 			if pingWeb {
 				fmt.Println(pt.WebsiteTest())
 			}
+		})
+		outputMutex.Unlock()
+		if !ok {
+			return fmt.Errorf("测试已取消")
 		}
+	}
 
-		outputMutex.Unlock()
-		tracker.finish("progress.ping")
+	// 13. 速度测试
+	if checkCancelled() {
+		return fmt.Errorf("测试已取消")
 	}
 
-	// 单独的TGDC和Web测试（当pingTestStatus=false但用户单独启用时）
-	if !pingTestStatus && preCheck.Connected && (pingTgdc || pingWeb) {
-		tracker.start("progress.ping")
+	if speedTestStatus && preCheck.Connected {
 		outputMutex.Lock()
-		if language == "zh" {
-			PrintCenteredTitle("PING值检测", width)
-		} else {
-			PrintCenteredTitle("PING-Test", width)
+		// 阶段超时后尝试仍可能在后台运行，重试次数经原子变量读取
+		var speedRetries atomic.Int32
+		ok := runStage("progress.speed", func(ctx context.Context) {
+			if language == "zh" {
+				PrintCenteredTitle("就近节点测速", width)
+			} else {
+				PrintCenteredTitle("Speed-Test", width)
+			}
+			speedTest := func() {
+				e.core.SpeedTestShowHead(language)
+				runSpeedProfile(e.core, config, language)
+			}
+			if config.StageRetries == 0 {
+				speedTest()
+				return
+			}
+			_, retries := retryNetworkStage(ctx, config, "progress.speed", func() string { return teeStdout(speedTest) }, func(retry int, wait time.Duration) {
+				fmt.Print(stageRetryNote(language, retry, config.StageRetries, wait))
+			})
+			speedRetries.Store(int32(retries))
+		})
+		outputMutex.Unlock()
+		tracker.retries["progress.speed"] = int(speedRetries.Load())
+		if !ok {
+			return fmt.Errorf("测试已取消")
 		}
+	}
 
-		if pingTgdc {
-			fmt.Println(pt.TelegramDCTest())
-		}
-		if pingWeb {
-			fmt.Println(pt.WebsiteTest())
+	// 14. 自定义 iperf3 目标
+	if len(config.IperfTargets) > 0 && preCheck.Connected {
+		if checkCancelled() {
+			return fmt.Errorf("测试已取消")
 		}
-
+		tracker.start("progress.iperf3")
+		outputMutex.Lock()
+		section, component := runTimedStage(e.ctx, config, "progress.iperf3", os.Stdout, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+			return runIperfStage(ctx, os.Stdout, config.IperfTargets, language, width)
+		})
+		stageSections, stageComponents = append(stageSections, section), append(stageComponents, component)
 		outputMutex.Unlock()
-		tracker.finish("progress.ping")
+		tracker.finish("progress.iperf3")
 	}
 
-	// 13. 速度测试
-	if checkCancelled() {
-		return fmt.Errorf("测试已取消")
+	// 15. 隧道对比：默认路由与指定网卡各测一次
+	if config.TunnelInterface != "" && preCheck.Connected {
+		if checkCancelled() {
+			return fmt.Errorf("测试已取消")
+		}
+		tracker.start("progress.tunnel")
+		outputMutex.Lock()
+		section, component := runTimedStage(e.ctx, config, "progress.tunnel", os.Stdout, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+			return runTunnelStage(ctx, os.Stdout, config.TunnelInterface, config.IperfTargets, language, width)
+		})
+		stageSections, stageComponents = append(stageSections, section), append(stageComponents, component)
+		outputMutex.Unlock()
+		tracker.finish("progress.tunnel")
 	}
 
-	if speedTestStatus && preCheck.Connected {
-		tracker.start("progress.speed")
-		outputMutex.Lock()
-		if language == "zh" {
-			PrintCenteredTitle("就近节点测速", width)
-		} else {
-			PrintCenteredTitle("Speed-Test", width)
+	// 16. GPU 信息与可选的 hashcat 基准
+	if config.GPUInfo {
+		if checkCancelled() {
+			return fmt.Errorf("测试已取消")
 		}
-		e.core.SpeedTestShowHead(language)
-		runSpeedProfile(e.core, config, language)
+		tracker.start("progress.gpu")
+		outputMutex.Lock()
+		section, component := runTimedStage(e.ctx, config, "progress.gpu", os.Stdout, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+			return runGPUStage(ctx, os.Stdout, config.GPUBenchmark, language, width)
+		})
+		stageSections, stageComponents = append(stageSections, section), append(stageComponents, component)
 		outputMutex.Unlock()
-		tracker.finish("progress.speed")
+		tracker.finish("progress.gpu")
 	}
 
 	// 打印时间信息
@@ -809,13 +1021,17 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	if config.EnableUpload && preCheck.Connected {
 		tracker.start("progress.upload")
 		outputMutex.Lock()
-		uploadPath := safeUploadFilePath(config.FilePath)
-		uploadConfig := e.core.NewConfig(ecsVersion)
-		uploadConfig.Language = language
-		uploadConfig.FilePath = uploadPath
-		uploadConfig.EnableUpload = true
-		e.core.HandleUploadResults(uploadConfig, capturedOutput())
-		_ = os.Remove(uploadPath)
+		if config.Proxy.Configured(proxy.Uploads) {
+			fmt.Print(uploadThroughProxy(e.ctx, config.Proxy, language, config.FilePath, capturedOutput()))
+		} else {
+			uploadPath := safeUploadFilePath(config.FilePath)
+			uploadConfig := e.core.NewConfig(ecsVersion)
+			uploadConfig.Language = language
+			uploadConfig.FilePath = uploadPath
+			uploadConfig.EnableUpload = true
+			e.core.HandleUploadResults(uploadConfig, capturedOutput())
+			_ = os.Remove(uploadPath)
+		}
 		outputMutex.Unlock()
 		tracker.finish("progress.upload")
 	}
@@ -826,6 +1042,33 @@ func (e *CommandExecutor) Execute(config ExecutionConfig) (runErr error) {
 	return nil
 }
 
+// cpuTest 执行 CPU 测试：选择了 Geekbench 版本时先运行该版本，找不到或失败时交给 ecs 自动选择；
+// 跳过 Geekbench 或未接受许可协议时改用 sysbench
+func (e *CommandExecutor) cpuTest(language string, config ExecutionConfig) (string, string) {
+	method := effectiveCpuMethod(config)
+	note := ""
+	switch {
+	case method == "geekbench":
+		res, err := e.core.GeekbenchTest(e.ctx, config.GeekbenchVersion, e.input)
+		if err == nil {
+			return "geekbench", res
+		}
+		if language == "zh" {
+			note = fmt.Sprintf("Geekbench %s 测试失败（%v），改由 ecs 自动选择\n", config.GeekbenchVersion, err)
+		} else {
+			note = fmt.Sprintf("Geekbench %s test failed (%v), falling back to ecs\n", config.GeekbenchVersion, err)
+		}
+	case config.CpuMethod == "geekbench" && config.GeekbenchVersion != geekbenchSkip:
+		if language == "zh" {
+			note = "未接受 Geekbench 许可协议，改用 sysbench 测试\n"
+		} else {
+			note = "Geekbench license not accepted, using sysbench instead\n"
+		}
+	}
+	realTestMethod, res := e.core.CpuTest(language, method, config.ThreadMode)
+	return realTestMethod, note + res
+}
+
 func diskResultText(language, result string) string {
 	if strings.TrimSpace(result) != "" {
 		return result
@@ -988,10 +1231,30 @@ func setComponentLogging(enabled bool) {
 }
 
 func runSpeedProfile(core CoreRunner, config ExecutionConfig, language string) {
+	if config.lightNetworkTests() {
+		if config.BandwidthCap > 0 {
+			fmt.Print(bandwidthCapNote(language, config.BandwidthCap))
+		}
+		runLightSpeedProfile(core, config, language)
+		return
+	}
 	spNum := config.SpNum
 	if spNum <= 0 {
 		spNum = 2
 	}
+	if len(config.SpeedGroups)+len(config.SpeedServerIDs) > 0 {
+		for _, group := range config.SpeedGroups {
+			if group == "nearby" {
+				core.SpeedTestNearby()
+			} else {
+				core.SpeedTestCustom("net", group, spNum, language)
+			}
+		}
+		if len(config.SpeedServerIDs) > 0 {
+			core.SpeedTestServers(config.SpeedServerIDs, config.DataOffline, language)
+		}
+		return
+	}
 	switch config.PresetKey {
 	case "full":
 		if language == "zh" {
@@ -0,0 +1,188 @@
+package ui
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/expect"
+)
+
+const expectSettingsFileName = "expect.json"
+
+// expectSettings 读取自动回应设置，文件损坏时使用内置规则
+func (ui *TestUI) expectSettings() expect.Settings {
+	settings, _ := expect.Load(ui.appDataDir(expectSettingsFileName))
+	return settings
+}
+
+// expectManager 是自动回应规则对话框：总开关、停用或启用内置规则、管理自定义规则
+type expectManager struct {
+	ui       *TestUI
+	path     string
+	settings expect.Settings
+	list     *widget.List
+	enabled  *widget.Check
+	status   *widget.Label
+	rules    []expect.Rule
+	selected int
+}
+
+// showExpectRules 打开自动回应规则对话框
+func (ui *TestUI) showExpectRules() {
+	m, err := ui.newExpectManager()
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	d := dialog.NewCustom(ui.tr("expect.title"), ui.tr("hosts.close"), m.content(), ui.Window)
+	d.Resize(fyne.NewSize(640, 460))
+	d.Show()
+}
+
+func (ui *TestUI) newExpectManager() (*expectManager, error) {
+	path := ui.appDataDir(expectSettingsFileName)
+	settings, err := expect.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &expectManager{ui: ui, path: path, settings: settings, status: widget.NewLabel(""), selected: -1}
+	m.status.Wrapping = fyne.TextWrapWord
+	m.list = widget.NewList(
+		func() int { return len(m.rules) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(m.rowText(id))
+		},
+	)
+	m.list.OnSelected = func(id widget.ListItemID) { m.selected = id }
+	m.list.OnUnselected = func(widget.ListItemID) { m.selected = -1 }
+	m.enabled = widget.NewCheck(ui.tr("expect.enabled"), nil)
+	m.reload()
+	return m, nil
+}
+
+func (m *expectManager) content() fyne.CanvasObject {
+	ui := m.ui
+	hint := widget.NewLabel(ui.tr("expect.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	actions := container.NewHBox(
+		widget.NewButtonWithIcon(ui.tr("expect.toggle"), theme.ConfirmIcon(), m.toggleSelected),
+		widget.NewButtonWithIcon(ui.tr("hosts.add"), theme.ContentAddIcon(), m.add),
+		widget.NewButtonWithIcon(ui.tr("history.delete"), theme.DeleteIcon(), m.removeSelected),
+		layout.NewSpacer(),
+	)
+	return container.NewBorder(container.NewVBox(hint, m.enabled), container.NewVBox(m.status, actions), nil, nil, m.list)
+}
+
+func (m *expectManager) custom(id int) bool {
+	return id >= len(expect.Builtin)
+}
+
+func (m *expectManager) rowText(id int) string {
+	rule := m.rules[id]
+	reply := rule.Reply
+	if reply == "" {
+		reply = m.ui.tr("expect.enter")
+	}
+	name := rule.Name
+	if !m.custom(id) {
+		name = m.ui.tr("expect.rule." + rule.ID)
+	}
+	text := name + "  → " + reply
+	var tags []string
+	if m.custom(id) {
+		tags = append(tags, m.ui.tr("mirror.custom"))
+	} else if slices.Contains(m.settings.Disabled, rule.ID) {
+		tags = append(tags, m.ui.tr("expect.off"))
+	}
+	if len(tags) > 0 {
+		text += "  [" + strings.Join(tags, ", ") + "]"
+	}
+	return text
+}
+
+// reload 按当前设置刷新规则列表与总开关
+func (m *expectManager) reload() {
+	m.rules = append(slices.Clone(expect.Builtin), m.settings.Custom...)
+	m.enabled.OnChanged, m.enabled.Checked = nil, !m.settings.Off
+	m.enabled.Refresh()
+	m.enabled.OnChanged = func(on bool) {
+		next := m.settings
+		next.Off = !on
+		m.save(next)
+	}
+	m.selected = -1
+	m.list.UnselectAll()
+	m.list.Refresh()
+}
+
+func (m *expectManager) save(next expect.Settings) bool {
+	if err := expect.Save(m.path, next); err != nil {
+		m.status.SetText(err.Error())
+		return false
+	}
+	m.settings, _ = expect.Load(m.path)
+	m.status.SetText("")
+	m.reload()
+	return true
+}
+
+// toggleSelected 停用或重新启用所选内置规则，自定义规则不需要时直接删除
+func (m *expectManager) toggleSelected() {
+	if m.selected < 0 || m.custom(m.selected) {
+		m.status.SetText(m.ui.tr("expect.select_builtin"))
+		return
+	}
+	id := m.rules[m.selected].ID
+	next := m.settings
+	if i := slices.Index(next.Disabled, id); i >= 0 {
+		next.Disabled = slices.Delete(slices.Clone(next.Disabled), i, i+1)
+	} else {
+		next.Disabled = append(slices.Clone(next.Disabled), id)
+	}
+	m.save(next)
+}
+
+// add 添加自定义规则，提示匹配正则时发送回复
+func (m *expectManager) add() {
+	name := widget.NewEntry()
+	pattern := widget.NewEntry()
+	pattern.SetPlaceHolder(`(?i)overwrite .*\[y/n\]`)
+	pattern.Validator = func(text string) error {
+		_, err := regexp.Compile(text)
+		return err
+	}
+	reply := widget.NewEntry()
+	reply.SetPlaceHolder(m.ui.tr("expect.reply_placeholder"))
+	items := []*widget.FormItem{
+		widget.NewFormItem(m.ui.tr("hosts.name"), name),
+		widget.NewFormItem(m.ui.tr("expect.pattern"), pattern),
+		widget.NewFormItem(m.ui.tr("expect.reply"), reply),
+	}
+	dialog.ShowForm(m.ui.tr("hosts.add"), m.ui.tr("hosts.save"), m.ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		next := m.settings
+		next.Custom = append(slices.Clone(next.Custom), expect.Rule{Name: name.Text, Pattern: pattern.Text, Reply: reply.Text})
+		m.save(next)
+	}, m.ui.Window)
+}
+
+// removeSelected 删除所选自定义规则，内置规则只能停用
+func (m *expectManager) removeSelected() {
+	if m.selected < 0 || !m.custom(m.selected) {
+		m.status.SetText(m.ui.tr("expect.select_custom"))
+		return
+	}
+	next := m.settings
+	next.Custom = slices.Delete(slices.Clone(next.Custom), m.selected-len(expect.Builtin), m.selected-len(expect.Builtin)+1)
+	m.save(next)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/expect"
+)
+
+func TestRunAnswersKnownPrompts(t *testing.T) {
+	var output strings.Builder
+	run := newRun(nil, ExecutionConfig{Language: "en"}, func(text string) { output.WriteString(text) }, nil)
+	run.Output("Do you accept the license agreement? [y/N] ")
+	if line := <-run.Input(); line != "y\n" {
+		t.Fatalf("reply = %q", line)
+	}
+	if !strings.Contains(output.String(), "[auto reply] License agreement (y/n)") {
+		t.Fatalf("output = %q, the reply should be noted", output.String())
+	}
+
+	off := newRun(nil, ExecutionConfig{Expect: expect.Settings{Off: true}}, nil, nil)
+	off.Output("Press Enter to continue")
+	select {
+	case line := <-off.Input():
+		t.Fatalf("turned-off rules sent %q", line)
+	default:
+	}
+}
+
+func TestExpectManagerPersistsRules(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	m, err := ui.newExpectManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.selected = 0
+	m.toggleSelected()
+	if !strings.HasSuffix(m.rowText(0), "["+ui.tr("expect.off")+"]") {
+		t.Fatalf("row = %q, the built-in rule should be disabled", m.rowText(0))
+	}
+	if !m.save(expect.Settings{Disabled: m.settings.Disabled, Custom: []expect.Rule{{Name: "token", Pattern: `Token:`, Reply: "abc"}}}) {
+		t.Fatal(m.status.Text)
+	}
+	m.selected = len(expect.Builtin) - 1
+	m.removeSelected()
+	if m.status.Text != ui.tr("expect.select_custom") {
+		t.Fatal("built-in rules cannot be deleted")
+	}
+	if m.save(expect.Settings{Custom: []expect.Rule{{Name: "bad", Pattern: "("}}}) {
+		t.Fatal("an invalid pattern should not be saved")
+	}
+
+	config := ui.collectExecutionConfig()
+	rules := config.Expect.Rules()
+	if len(rules) != len(expect.Builtin) || rules[len(rules)-1].Reply != "abc" {
+		t.Fatalf("rules = %+v, runs should use the saved rules", rules)
+	}
+	m.enabled.SetChecked(false)
+	if !ui.collectExecutionConfig().Expect.Off {
+		t.Fatal("the switch should turn automatic replies off")
+	}
+}
@@ -0,0 +1,97 @@
+package ui
+
+import (
+	"net/url"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/geekbench"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	// geekbenchLicensePreferenceKey 后接主版本号，记录用户已接受该版本的许可协议
+	geekbenchLicensePreferenceKey = "geekbench_license_v"
+	geekbenchSkip                 = "skip"
+)
+
+// geekbenchVersionOptions 是 Geekbench 版本下拉框的选项
+func geekbenchVersionOptions() []string {
+	return append(append([]string(nil), geekbench.Versions...), geekbenchSkip)
+}
+
+// effectiveCpuMethod 返回实际使用的 CPU 测试方式：跳过 Geekbench 或未接受其许可协议时改用 sysbench
+func effectiveCpuMethod(config ExecutionConfig) string {
+	if config.CpuMethod == "geekbench" && (config.GeekbenchVersion == geekbenchSkip || !config.GeekbenchAccepted) {
+		return "sysbench"
+	}
+	return config.CpuMethod
+}
+
+// needsGeekbenchLicense 判断本次运行是否会使用 Geekbench 且尚未接受许可协议
+func needsGeekbenchLicense(config ExecutionConfig) bool {
+	return config.SelectedOptions["cpu"] && config.CpuMethod == "geekbench" &&
+		config.GeekbenchVersion != geekbenchSkip && !config.GeekbenchAccepted
+}
+
+func (ui *TestUI) geekbenchLicenseAccepted(version string) bool {
+	return ui.App.Preferences().Bool(geekbenchLicensePreferenceKey + version)
+}
+
+// refreshGeekbenchVersion 只有 CPU 测试方式为 geekbench 时才能选择版本
+func (ui *TestUI) refreshGeekbenchVersion() {
+	if ui.GeekbenchVersionSelect == nil {
+		return
+	}
+	if ui.CpuMethodSelect.Selected == "geekbench" {
+		ui.GeekbenchVersionSelect.Enable()
+	} else {
+		ui.GeekbenchVersionSelect.Disable()
+	}
+}
+
+// confirmGeekbenchLicense 首次使用所选 Geekbench 版本时显示许可协议（与命令行的提示相同），
+// 接受后记录并以 GeekbenchAccepted 继续；拒绝则调用 cancel 取消本次运行
+func (ui *TestUI) confirmGeekbenchLicense(config ExecutionConfig, next func(ExecutionConfig), cancel func()) {
+	if !needsGeekbenchLicense(config) {
+		next(config)
+		return
+	}
+	eula, _ := url.Parse(geekbench.EULAURL)
+	body := widget.NewLabel(ui.tr("dialog.geekbench_license_body"))
+	body.Wrapping = fyne.TextWrapWord
+	content := container.NewVBox(body, widget.NewHyperlink(geekbench.EULAURL, eula))
+	confirm := dialog.NewCustomConfirm(
+		ui.tr("dialog.geekbench_license_title")+" - Geekbench "+config.GeekbenchVersion,
+		ui.tr("dialog.geekbench_accept"), ui.tr("dialog.geekbench_decline"), content,
+		func(ok bool) {
+			if !ok {
+				cancel()
+				return
+			}
+			ui.App.Preferences().SetBool(geekbenchLicensePreferenceKey+config.GeekbenchVersion, true)
+			config.GeekbenchAccepted = true
+			next(config)
+		}, ui.Window)
+	confirm.Resize(fyne.NewSize(520, 0))
+	confirm.Show()
+}
+
+// geekbenchLinks 返回结果面板 CPU 页下方的 Geekbench 结果与认领链接，没有链接时返回 nil
+func (ui *TestUI) geekbenchLinks(report *results.Report) fyne.CanvasObject {
+	var links []fyne.CanvasObject
+	for _, item := range []struct{ key, link string }{
+		{"results.geekbench_link", report.GeekbenchLink},
+		{"results.geekbench_claim", report.GeekbenchClaim},
+	} {
+		if parsed, err := url.Parse(item.link); err == nil && item.link != "" {
+			links = append(links, widget.NewHyperlink(ui.tr(item.key), parsed))
+		}
+	}
+	if len(links) == 0 {
+		return nil
+	}
+	return container.NewHBox(links...)
+}
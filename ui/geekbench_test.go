@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// geekbenchCore 记录 CPU 测试方式，GeekbenchTest 返回预设结果
+type geekbenchCore struct {
+	CoreRunner
+	err     error
+	methods []string
+}
+
+func (c *geekbenchCore) GeekbenchTest(ctx context.Context, version string, stdin io.Reader) (string, error) {
+	c.methods = append(c.methods, "geekbench"+version)
+	if c.err != nil {
+		return "", c.err
+	}
+	return "Geekbench 5.4.5\nClaim: https://browser.geekbench.com/v5/cpu/1/claim?key=2\n", nil
+}
+
+func (c *geekbenchCore) CpuTest(language, method, threadMode string) (string, string) {
+	c.methods = append(c.methods, method)
+	return method, "1 Thread(s) Test: 100\n"
+}
+
+func TestExecutorCPUTestUsesSelectedGeekbenchVersion(t *testing.T) {
+	config := ExecutionConfig{CpuMethod: "geekbench", GeekbenchVersion: "5", GeekbenchAccepted: true}
+	core := &geekbenchCore{}
+	executor := NewCommandExecutor(nil)
+	executor.SetCoreRunner(core)
+	if method, res := executor.cpuTest("en", config); method != "geekbench" || !strings.Contains(res, "Claim:") {
+		t.Fatalf("cpuTest() = %q, %q", method, res)
+	}
+
+	core = &geekbenchCore{err: errors.New("not found")}
+	executor.SetCoreRunner(core)
+	if method, res := executor.cpuTest("en", config); method != "geekbench" || !strings.Contains(res, "Geekbench 5 test failed") {
+		t.Fatalf("cpuTest(failed) = %q, %q", method, res)
+	}
+	if strings.Join(core.methods, ",") != "geekbench5,geekbench" {
+		t.Fatalf("methods = %v", core.methods)
+	}
+
+	for _, config := range []ExecutionConfig{
+		{CpuMethod: "geekbench", GeekbenchVersion: "6"},
+		{CpuMethod: "geekbench", GeekbenchVersion: geekbenchSkip, GeekbenchAccepted: true},
+	} {
+		core = &geekbenchCore{}
+		executor.SetCoreRunner(core)
+		if method, _ := executor.cpuTest("zh", config); method != "sysbench" || len(core.methods) != 1 {
+			t.Fatalf("cpuTest(%+v) = %q, calls %v", config, method, core.methods)
+		}
+	}
+}
+
+func TestGeekbenchVersionSelectFollowsCPUMethod(t *testing.T) {
+	ui := newTestUIForTest(t)
+	if !ui.GeekbenchVersionSelect.Disabled() {
+		t.Fatal("version select should be disabled for sysbench")
+	}
+	ui.CpuMethodSelect.SetSelected("geekbench")
+	ui.GeekbenchVersionSelect.SetSelected("5")
+	if ui.GeekbenchVersionSelect.Disabled() {
+		t.Fatal("version select should be enabled for geekbench")
+	}
+	config := ui.collectExecutionConfig()
+	if config.GeekbenchVersion != "5" || config.GeekbenchAccepted || effectiveCpuMethod(config) != "sysbench" {
+		t.Fatalf("config = %+v", config)
+	}
+	if config.SelectedOptions["cpu"] = true; !needsGeekbenchLicense(config) {
+		t.Fatal("geekbench run should ask for the license first")
+	}
+	if config.SelectedOptions["cpu"] = false; needsGeekbenchLicense(config) {
+		t.Fatal("license is only needed when the CPU test runs")
+	}
+	ui.App.Preferences().SetBool(geekbenchLicensePreferenceKey+"5", true)
+	if config := ui.collectExecutionConfig(); !config.GeekbenchAccepted || effectiveCpuMethod(config) != "geekbench" {
+		t.Fatalf("config after accepting = %+v", config)
+	}
+
+	other := newTestUIForTest(t)
+	other.restoreUIState(ui.snapshotUIState())
+	if other.GeekbenchVersionSelect.Selected != "5" || other.GeekbenchVersionSelect.Disabled() {
+		t.Fatalf("restored version = %q, disabled %v", other.GeekbenchVersionSelect.Selected, other.GeekbenchVersionSelect.Disabled())
+	}
+}
+
+func TestCPUResultsShowGeekbenchLinks(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	report := &results.Report{
+		CPU:            []results.CPUScore{{Label: "Single-Core Score", Score: 1234}},
+		GeekbenchLink:  "https://browser.geekbench.com/v6/cpu/1",
+		GeekbenchClaim: "https://browser.geekbench.com/v6/cpu/1/claim?key=2",
+	}
+	border, ok := ui.cpuResultsView(report).(*fyne.Container)
+	if !ok {
+		t.Fatalf("cpuResultsView() = %T", ui.cpuResultsView(report))
+	}
+	var links []string
+	for _, obj := range border.Objects {
+		if box, ok := obj.(*fyne.Container); ok {
+			for _, item := range box.Objects {
+				if link, ok := item.(*widget.Hyperlink); ok {
+					links = append(links, link.Text+"="+link.URL.String())
+				}
+			}
+		}
+	}
+	if len(links) != 2 || links[1] != "Claim to my account="+report.GeekbenchClaim {
+		t.Fatalf("links = %v", links)
+	}
+	report.GeekbenchLink, report.GeekbenchClaim = "", ""
+	if _, ok := ui.cpuResultsView(report).(*widget.Table); !ok {
+		t.Fatal("CPU results without links should be a plain table")
+	}
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/gpu"
+)
+
+const gpuComponentSchema = "ecs-gui.gpu/v1"
+
+// GPU 查询与 hashcat 基准使用的命令执行器，测试中替换
+var (
+	gpuRunner     gpu.Runner = gpu.Exec
+	gpuLookPath              = exec.LookPath
+	hashcatBinary            = "hashcat"
+)
+
+// gpuReport 是 GPU 组件的负载
+type gpuReport struct {
+	Devices    []gpu.Device      `json:"devices"`
+	Bench      []gpu.BenchResult `json:"bench,omitempty"`
+	BenchError string            `json:"bench_error,omitempty"`
+}
+
+// runGPUStage 列出本机 GPU 的型号、显存与驱动版本，benchmark 为真时再用 hashcat 运行一次 SHA-256 基准，
+// 结果写到 out，返回报告中的分区与组件；没有找到 GPU 时分区为 unavailable
+func runGPUStage(ctx context.Context, out io.Writer, benchmark bool, language string, width int) (StructuredSection, StructuredComponent) {
+	started := time.Now()
+	section := StructuredSection{Name: "gpu", Enabled: true}
+	component := StructuredComponent{Name: "gpu", SchemaVersion: gpuComponentSchema}
+	fmt.Fprintln(out, centeredTitle(pickLanguage(language, "GPU信息", "GPU-Info"), width))
+	finish := func(status, reason string, report gpuReport) (StructuredSection, StructuredComponent) {
+		section.Status, section.Reason = status, reason
+		component.Status, component.Reason = status, reason
+		component.DurationMS = time.Since(started).Milliseconds()
+		component.Payload, _ = json.Marshal(report)
+		return section, component
+	}
+
+	devices, err := gpu.Detect(ctx, gpuRunner)
+	report := gpuReport{Devices: devices}
+	if err != nil {
+		if ctx.Err() != nil {
+			return finish("canceled", ctx.Err().Error(), report)
+		}
+		fmt.Fprintln(out, pickLanguage(language, "未检测到 GPU", "No GPU detected"))
+		return finish("unavailable", err.Error(), report)
+	}
+	for _, device := range devices {
+		fmt.Fprintf(out, "%-20s: %s\n", fmt.Sprintf("GPU %d", device.Index), device.Model)
+		if device.MemoryMB > 0 {
+			fmt.Fprintf(out, " %-19s: %d MiB\n", pickLanguage(language, "显存", "VRAM"), device.MemoryMB)
+		}
+		if device.Driver != "" {
+			fmt.Fprintf(out, " %-19s: %s\n", pickLanguage(language, "驱动版本", "Driver"), device.Driver)
+		}
+	}
+	if !benchmark || ctx.Err() != nil {
+		return finish("ok", "", report)
+	}
+
+	binary, err := gpuLookPath(hashcatBinary)
+	if err != nil {
+		fmt.Fprintln(out, pickLanguage(language, "未找到 hashcat，跳过 GPU 基准测试", "hashcat not found, skipping the GPU benchmark"))
+		report.BenchError = "hashcat not installed"
+		return finish("partial", report.BenchError, report)
+	}
+	fmt.Fprintf(out, pickLanguage(language, "hashcat SHA-256 基准（-m %d）\n", "hashcat SHA-256 benchmark (-m %d)\n"), gpu.BenchMode)
+	bench, err := gpu.Benchmark(ctx, gpuRunner, binary)
+	if err != nil {
+		if ctx.Err() != nil {
+			return finish("canceled", ctx.Err().Error(), report)
+		}
+		fmt.Fprintf(out, pickLanguage(language, "GPU 基准测试失败：%v\n", "GPU benchmark failed: %v\n"), err)
+		report.BenchError = err.Error()
+		return finish("partial", report.BenchError, report)
+	}
+	report.Bench = bench
+	for _, result := range bench {
+		fmt.Fprintf(out, "%-20s: %s\n", fmt.Sprintf("hashcat #%d", result.Device), gpu.FormatRate(result.HashesPerSec))
+	}
+	return finish("ok", "", report)
+}
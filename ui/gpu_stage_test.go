@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func stubGPUCommands(t *testing.T, hashcat bool) {
+	t.Helper()
+	oldRunner, oldLookPath := gpuRunner, gpuLookPath
+	t.Cleanup(func() { gpuRunner, gpuLookPath = oldRunner, oldLookPath })
+	gpuRunner = func(ctx context.Context, name string, args ...string) (string, error) {
+		switch name {
+		case "nvidia-smi":
+			return "Tesla T4, 15360, 535.104.05\n", nil
+		case "/usr/bin/hashcat":
+			return "1:1400:1590:5001:52.35:5220958432\n", nil
+		}
+		return "", errors.New("not found")
+	}
+	gpuLookPath = func(name string) (string, error) {
+		if hashcat {
+			return "/usr/bin/" + name, nil
+		}
+		return "", errors.New("not found")
+	}
+}
+
+func TestRunGPUStageWithBenchmark(t *testing.T) {
+	stubGPUCommands(t, true)
+	var out strings.Builder
+	section, component := runGPUStage(context.Background(), &out, true, "zh", 82)
+	if section.Status != "ok" || component.Name != "gpu" {
+		t.Fatalf("section = %+v", section)
+	}
+	var payload gpuReport
+	if err := json.Unmarshal(component.Payload, &payload); err != nil || len(payload.Devices) != 1 || len(payload.Bench) != 1 {
+		t.Fatalf("payload = %s, %v", component.Payload, err)
+	}
+
+	report := results.Parse(out.String())
+	if len(report.GPU) != 1 || report.GPU[0].Model != "Tesla T4" || report.GPU[0].Memory != "15360 MiB" || report.GPU[0].Driver != "535.104.05" {
+		t.Fatalf("GPU = %#v; output = %q", report.GPU, out.String())
+	}
+	if len(report.GPUBench) != 1 || report.GPUBench[0].Rate != "5.22 GH/s" {
+		t.Fatalf("GPUBench = %#v", report.GPUBench)
+	}
+}
+
+func TestRunGPUStageWithoutHashcatIsPartial(t *testing.T) {
+	stubGPUCommands(t, false)
+	var out strings.Builder
+	section, _ := runGPUStage(context.Background(), &out, true, "en", 82)
+	if section.Status != "partial" || !strings.Contains(out.String(), "hashcat not found") {
+		t.Fatalf("section = %+v; output = %q", section, out.String())
+	}
+}
+
+func TestGPUOptionsFromForm(t *testing.T) {
+	config := buildExecutionConfig(executionForm{checks: map[string]bool{"gpuBench": true}})
+	if config.GPUInfo || config.GPUBenchmark {
+		t.Fatalf("benchmark without GPU info: %+v", config)
+	}
+	form := executionForm{checks: map[string]bool{"gpu": true, "gpuBench": true}}
+	config = buildExecutionConfig(form)
+	if !config.GPUInfo || !config.GPUBenchmark || !form.hasTests() {
+		t.Fatalf("GPUInfo = %v, GPUBenchmark = %v, hasTests = %v", config.GPUInfo, config.GPUBenchmark, form.hasTests())
+	}
+}
+
+func TestGPUResultsTabShowsCards(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("-----------GPU-Info-----------\nGPU 0               : Tesla T4\n VRAM               : 15360 MiB\nhashcat #1          : 5.22 GH/s\n")
+	ui.refreshParsedResults()
+
+	for i, tab := range parsedResultTabs {
+		if tab.titleKey != "results.tab.gpu" {
+			continue
+		}
+		border, ok := ui.resultsTabs.Items[i+1].Content.(*fyne.Container)
+		if !ok {
+			t.Fatalf("gpu tab content = %T", ui.resultsTabs.Items[i+1].Content)
+		}
+		if len(border.Objects) != 2 {
+			t.Fatalf("gpu tab objects = %d, want cards and benchmark table", len(border.Objects))
+		}
+		return
+	}
+	t.Fatal("no GPU tab")
+}
@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/applog"
+	"github.com/oneclickvirt/ecs-gui/expect"
+	"github.com/oneclickvirt/ecs-gui/internal/appmeta"
+	"github.com/oneclickvirt/ecs-gui/mirror"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// 无界面模式的退出码
+const (
+	HeadlessExitDone    = 0
+	HeadlessExitFailed  = 1
+	HeadlessExitUsage   = 2
+	HeadlessExitPartial = 3
+	HeadlessExitStopped = 130
+)
+
+// HeadlessRemotePasswordEnv 是无界面模式读取 SSH 密码的环境变量，设置文件不保存密码
+const HeadlessRemotePasswordEnv = "ECS_REMOTE_PASSWORD"
+
+// HeadlessOptions 是无界面模式的运行参数
+type HeadlessOptions struct {
+	// SettingsPath 是设置文件：图形界面自动保存的 settings.json 或导出的设置文件，为空时只使用 Tests
+	SettingsPath string
+	// Tests 非空时覆盖设置文件中的测试项，取值见 HeadlessTestKeys
+	Tests []string
+	// Language 为 zh、en 或 auto，为空时使用设置文件中的语言
+	Language string
+	// OutputDir 非空时把解析后的结果写成 results.json、results.csv、results.md、results.html，
+	// 远程运行取回的结果文件写入其中的 remote 目录
+	OutputDir string
+	// DataDir 是 known_hosts 等文件所在的应用数据目录
+	DataDir string
+	Stdout  io.Writer
+	Stderr  io.Writer
+
+	runner executionRunner // 测试时替换执行器
+}
+
+// HeadlessTestKeys 返回可用的测试项
+func HeadlessTestKeys() []string {
+	return append([]string(nil), testOptionKeys...)
+}
+
+// DefaultDataDir 返回图形界面在桌面平台使用的应用数据目录（Fyne 应用存储根目录）
+func DefaultDataDir() string {
+	if dir, err := os.UserConfigDir(); err == nil {
+		return filepath.Join(dir, "fyne", appmeta.AppID)
+	}
+	return filepath.Join(os.TempDir(), appmeta.AppName)
+}
+
+// DefaultSettingsPath 返回图形界面自动保存的设置文件路径
+func DefaultSettingsPath() string {
+	return filepath.Join(DefaultDataDir(), settingsFileName)
+}
+
+// headlessForm 按设置文件与命令行参数生成表单内容
+func headlessForm(opts HeadlessOptions) (executionForm, error) {
+	form := executionForm{
+		language:   langZH,
+		preset:     "custom",
+		checks:     map[string]bool{},
+		selections: map[string]string{},
+		entries:    map[string]string{},
+	}
+	if opts.SettingsPath != "" {
+		data, err := os.ReadFile(opts.SettingsPath)
+		if err != nil {
+			return form, err
+		}
+		profile, err := parseSettingsProfile(data)
+		if err != nil {
+			return form, fmt.Errorf("%s: %w", opts.SettingsPath, err)
+		}
+		if profile.Preset != "" {
+			form.preset = profile.Preset
+		}
+		for key, value := range profile.Checks {
+			form.checks[key] = value
+		}
+		for key, value := range profile.Selections {
+			form.selections[key] = value
+		}
+		for key, value := range profile.Entries {
+			form.entries[key] = value
+		}
+		if setting, ok := profile.Preferences[languagePreferenceKey].(string); ok {
+			form.language = resolveLanguage(setting)
+		}
+	}
+	if opts.Language != "" {
+		setting := normalizeLanguage(opts.Language)
+		if setting == "" {
+			return form, fmt.Errorf("unknown language %q", opts.Language)
+		}
+		form.language = resolveLanguage(setting)
+	}
+	if len(opts.Tests) > 0 {
+		if err := form.selectTests(opts.Tests); err != nil {
+			return form, err
+		}
+	}
+	if !form.hasTests() {
+		return form, errors.New("no tests selected; pass -tests or a settings file")
+	}
+	return form, nil
+}
+
+// headlessRemoteTarget 按表单生成远程目标；密码从环境变量读取，不支持图形界面中的跳板机
+func headlessRemoteTarget(form executionForm, dataDir string) (*remote.Target, error) {
+	if !form.checks["remote"] || form.entry("remoteHost") == "" {
+		return nil, nil
+	}
+	port := remote.DefaultPort
+	if text := form.entry("remotePort"); text != "" {
+		value, err := strconv.Atoi(text)
+		if err != nil || value <= 0 || value > 65535 {
+			return nil, errInvalidRemotePort
+		}
+		port = value
+	}
+	target := &remote.Target{
+		Host:           form.entry("remoteHost"),
+		Port:           port,
+		User:           form.entry("remoteUser"),
+		Password:       os.Getenv(HeadlessRemotePasswordEnv),
+		KeyPath:        form.entry("remoteKeyPath"),
+		KnownHostsFile: filepath.Join(dataDir, "ssh", "known_hosts"),
+	}
+	if form.checks["remoteWin"] {
+		target.OS = remote.OSWindows
+	}
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// lockedWriter 串行化来自执行器多个 goroutine 的输出
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) WriteString(text string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, text)
+}
+
+// RunHeadless 不创建窗口运行一次测试，使用与图形界面相同的执行器、解析器与导出器。
+// 测试输出写到 Stdout，进度与错误写到 Stderr，返回进程退出码。
+func RunHeadless(ctx context.Context, opts HeadlessOptions) int {
+	if opts.Stdout == nil {
+		opts.Stdout = os.Stdout
+	}
+	if opts.Stderr == nil {
+		opts.Stderr = os.Stderr
+	}
+	if opts.DataDir == "" {
+		opts.DataDir = DefaultDataDir()
+	}
+	if err := applog.Setup(filepath.Join(opts.DataDir, appLogDirName)); err == nil {
+		defer applog.Close()
+	}
+	stderr := &lockedWriter{w: opts.Stderr}
+
+	form, err := headlessForm(opts)
+	if err != nil {
+		stderr.WriteString(err.Error() + "\n")
+		return HeadlessExitUsage
+	}
+	config := buildExecutionConfig(form)
+	// 命令行模式没有交互，与 goecs 一样选择 Geekbench 即视为接受许可协议
+	config.GeekbenchAccepted = true
+	if config.Proxy, err = proxy.Load(filepath.Join(opts.DataDir, proxySettingsFileName)); err != nil {
+		stderr.WriteString(err.Error() + "\n")
+		return HeadlessExitUsage
+	}
+	if config.Mirror, err = mirror.Load(filepath.Join(opts.DataDir, mirrorSettingsFileName)); err != nil {
+		stderr.WriteString(err.Error() + "\n")
+		return HeadlessExitUsage
+	}
+	if config.Expect, err = expect.Load(filepath.Join(opts.DataDir, expectSettingsFileName)); err != nil {
+		stderr.WriteString(err.Error() + "\n")
+		return HeadlessExitUsage
+	}
+	if config.Remote, err = headlessRemoteTarget(form, opts.DataDir); err != nil {
+		stderr.WriteString(translate(form.language, "dialog.remote_invalid") + "\n" + err.Error() + "\n")
+		return HeadlessExitUsage
+	}
+	if config.Remote != nil {
+		config.RemoteBinary = form.entry("remoteBinary")
+		config.RemoteCache = filepath.Join(opts.DataDir, ecsCacheDir)
+	}
+	runner := opts.runner
+	if runner == nil {
+		runner = executionRunnerFor(config)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Minute)
+	defer cancel()
+
+	var (
+		outputMu sync.Mutex
+		output   strings.Builder
+	)
+	run := newRun(ctx, config,
+		func(text string) {
+			outputMu.Lock()
+			defer outputMu.Unlock()
+			output.WriteString(text)
+			_, _ = io.WriteString(opts.Stdout, text)
+		},
+		func(update ProgressUpdate) {
+			if update.Done || update.ItemKey == "" {
+				return
+			}
+			text := translate(form.language, update.ItemKey)
+			if update.Total > 0 {
+				text = fmt.Sprintf(translate(form.language, "status.current"), text, update.Current, update.Total)
+			}
+			stderr.WriteString(text + "\n")
+		},
+	)
+	outcome := executeWithRunner(runner, run)
+
+	code := headlessExitCode(ctx, outcome)
+	if outcome.Err != nil {
+		stderr.WriteString(translate(form.language, "log.error_prefix") + outcome.Err.Error() + "\n")
+	}
+	if opts.OutputDir != "" {
+		outputMu.Lock()
+		text := output.String()
+		outputMu.Unlock()
+		err := writeHeadlessResults(opts.OutputDir, text, outcome.Report)
+		if err == nil {
+			err = writeArtifacts(filepath.Join(opts.OutputDir, "remote"), run.Artifacts())
+		}
+		if err != nil {
+			stderr.WriteString(err.Error() + "\n")
+			if code == HeadlessExitDone {
+				code = HeadlessExitFailed
+			}
+		}
+	}
+	return code
+}
+
+// headlessExitCode 与图形界面相同：结构化状态优先，否则按错误与上下文判断
+func headlessExitCode(ctx context.Context, outcome executionOutcome) int {
+	if outcome.Report != nil && outcome.Report.Status != "" {
+		switch outcome.Report.Status {
+		case "timeout", "error":
+			return HeadlessExitFailed
+		case "canceled":
+			return HeadlessExitStopped
+		case "partial", "unavailable":
+			return HeadlessExitPartial
+		}
+		return HeadlessExitDone
+	}
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return HeadlessExitFailed
+	case errors.Is(ctx.Err(), context.Canceled):
+		return HeadlessExitStopped
+	case outcome.Err != nil:
+		return HeadlessExitFailed
+	}
+	return HeadlessExitDone
+}
+
+// writeHeadlessResults 把解析后的结果按 JSON、CSV、Markdown 与 HTML 写入目录，结构化报告另存为 report.json
+func writeHeadlessResults(dir, output string, report *StructuredRunResult) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	parsed := results.Parse(results.StripANSI(output))
+	for _, format := range []results.Format{results.FormatJSON, results.FormatCSV, results.FormatMarkdown} {
+		data, err := results.Encode(parsed, format)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, "results"+format.Extension()), data, 0o644); err != nil {
+			return err
+		}
+	}
+	// HTML 报告附带原始输出，单独生成
+	page, err := results.EncodeHTML(parsed, results.HTMLOptions{Log: output})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, "results"+results.FormatHTML.Extension()), page, 0o644); err != nil {
+		return err
+	}
+	if report == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "report.json"), data, 0o644)
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+type recordingRunner struct {
+	config  ExecutionConfig
+	outcome executionOutcome
+}
+
+func (r *recordingRunner) Run(run *Run) executionOutcome {
+	r.config = run.Config
+	run.Output("CPU 测试\n单核得分: 1234\n")
+	run.Progress(ProgressUpdate{ItemKey: "progress.cpu", Current: 1, Total: 2})
+	return r.outcome
+}
+
+func TestRunHeadlessUsesSettingsFileAndWritesResults(t *testing.T) {
+	dir := t.TempDir()
+	settings := settingsProfile{
+		Version:     settingsProfileVersion,
+		Checks:      map[string]bool{"cpu": true, "memory": true, "chinaMode": true},
+		Entries:     map[string]string{"spNum": "50", "remotePassword": "ignored"},
+		Preferences: map[string]any{languagePreferenceKey: langEN},
+	}
+	data, err := json.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settingsPath := filepath.Join(dir, "settings.json")
+	if err := os.WriteFile(settingsPath, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	runner := &recordingRunner{outcome: executionOutcome{Report: &StructuredRunResult{Status: "partial"}, Structured: true}}
+	var stdout, stderr strings.Builder
+	outDir := filepath.Join(dir, "out")
+	code := RunHeadless(context.Background(), HeadlessOptions{
+		SettingsPath: settingsPath,
+		OutputDir:    outDir,
+		DataDir:      dir,
+		Stdout:       &stdout,
+		Stderr:       &stderr,
+		runner:       runner,
+	})
+	if code != HeadlessExitPartial {
+		t.Fatalf("exit code = %d, want %d; stderr=%s", code, HeadlessExitPartial, stderr.String())
+	}
+	config := runner.config
+	if !config.SelectedOptions["cpu"] || !config.SelectedOptions["memory"] || config.SelectedOptions["disk"] {
+		t.Fatalf("selected options = %v", config.SelectedOptions)
+	}
+	if config.Language != "en" || !config.ChinaModeEnabled || config.SpNum != 20 {
+		t.Fatalf("config = language %q china %v spNum %d", config.Language, config.ChinaModeEnabled, config.SpNum)
+	}
+	if !strings.Contains(stdout.String(), "1234") {
+		t.Fatalf("stdout = %q", stdout.String())
+	}
+	if stderr.Len() == 0 {
+		t.Fatal("progress not written to stderr")
+	}
+	for _, name := range []string{"results.json", "results.csv", "results.md", "results.html", "report.json"} {
+		if _, err := os.Stat(filepath.Join(outDir, name)); err != nil {
+			t.Errorf("%s not written: %v", name, err)
+		}
+	}
+}
+
+func TestRunHeadlessTestsFlagOverridesSettings(t *testing.T) {
+	runner := &recordingRunner{}
+	var stdout, stderr strings.Builder
+	code := RunHeadless(context.Background(), HeadlessOptions{
+		Tests:   []string{"Disk", " speed"},
+		DataDir: t.TempDir(),
+		Stdout:  &stdout,
+		Stderr:  &stderr,
+		runner:  runner,
+	})
+	if code != HeadlessExitDone {
+		t.Fatalf("exit code = %d; stderr=%s", code, stderr.String())
+	}
+	for _, key := range testOptionKeys {
+		want := key == "disk" || key == "speed"
+		if runner.config.SelectedOptions[key] != want {
+			t.Errorf("%s selected = %v, want %v", key, runner.config.SelectedOptions[key], want)
+		}
+	}
+	if runner.config.Language != "zh" || runner.config.SpNum != 2 || runner.config.MaxDuration.Minutes() != 15 {
+		t.Fatalf("defaults not applied: %+v", runner.config)
+	}
+}
+
+func TestRunHeadlessRejectsInvalidInput(t *testing.T) {
+	for name, opts := range map[string]HeadlessOptions{
+		"no tests":      {},
+		"unknown test":  {Tests: []string{"gpu"}},
+		"unknown lang":  {Tests: []string{"cpu"}, Language: "fr"},
+		"missing file":  {SettingsPath: filepath.Join(t.TempDir(), "missing.json")},
+		"remote no key": {SettingsPath: writeHeadlessSettings(t, `{"version":1,"checks":{"cpu":true,"remote":true},"entries":{"remoteHost":"203.0.113.7"}}`)},
+	} {
+		runner := &recordingRunner{}
+		var stderr strings.Builder
+		opts.Stdout, opts.Stderr, opts.DataDir, opts.runner = &strings.Builder{}, &stderr, t.TempDir(), runner
+		if code := RunHeadless(context.Background(), opts); code != HeadlessExitUsage {
+			t.Errorf("%s: exit code = %d, want %d", name, code, HeadlessExitUsage)
+		}
+		if stderr.Len() == 0 {
+			t.Errorf("%s: no error written", name)
+		}
+	}
+}
+
+func TestHeadlessExitCodeFollowsContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if code := headlessExitCode(ctx, executionOutcome{Err: context.Canceled}); code != HeadlessExitStopped {
+		t.Fatalf("canceled exit code = %d", code)
+	}
+	if code := headlessExitCode(context.Background(), executionOutcome{Report: &StructuredRunResult{Status: "timeout"}}); code != HeadlessExitFailed {
+		t.Fatalf("timeout exit code = %d", code)
+	}
+}
+
+func writeHeadlessSettings(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "settings.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// historyStoreOrOpen 懒加载历史记录存储，打开失败时返回 nil
+func (ui *TestUI) historyStoreOrOpen() *history.Store {
+	ui.Mu.Lock()
+	defer ui.Mu.Unlock()
+	if ui.historyStore != nil {
+		return ui.historyStore
+	}
+	store, err := history.Open(ui.appDataDir("history"))
+	if err != nil {
+		return nil
+	}
+	ui.historyStore = store
+	return store
+}
+
+// recordRunHistory 在测试结束后保存本次运行（原始输出 + 解析结果）
+func (ui *TestUI) recordRunHistory(run *Run, statusKey string) {
+	if ui.Terminal == nil {
+		return
+	}
+	output := ui.Terminal.GetText()
+	if strings.TrimSpace(output) == "" {
+		return
+	}
+	ui.Mu.Lock()
+	report := ui.ParsedResults
+	ui.Mu.Unlock()
+
+	if saved, ok := ui.saveHistoryRun(run.Record(statusKey, time.Now(), output, report)); ok {
+		ui.Mu.Lock()
+		ui.resultsRecord = &saved.Summary
+		ui.Mu.Unlock()
+	}
+}
+
+// saveHistoryRun 保存一条运行记录并刷新历史列表，返回带 ID 的记录，可在任意 goroutine 调用
+func (ui *TestUI) saveHistoryRun(run history.Run) (history.Run, bool) {
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return history.Run{}, false
+	}
+	if p, ok := ui.hostProfileByHost(run.Host); ok {
+		if len(run.Tags) == 0 {
+			run.Tags = p.Tags()
+		}
+		if run.MonthlyCost == 0 {
+			run.MonthlyCost = p.MonthlyCost
+		}
+	}
+	saved, err := store.Save(run)
+	if err != nil {
+		return history.Run{}, false
+	}
+	ui.runOnUI(ui.reloadHistoryList)
+	return saved, true
+}
+
+// createHistoryTab 创建历史记录页：列表 + 对比/重新打开/导出/删除
+func (ui *TestUI) createHistoryTab() fyne.CanvasObject {
+	ui.historySelected = -1
+	ui.historyList = widget.NewList(
+		func() int { return len(ui.historyItems) },
+		func() fyne.CanvasObject {
+			return container.NewHBox(widget.NewIcon(theme.HistoryIcon()), widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(ui.historyItems) {
+				return
+			}
+			obj.(*fyne.Container).Objects[1].(*widget.Label).SetText(ui.historyItemText(ui.historyItems[id]))
+		},
+	)
+	ui.historyList.OnSelected = func(id widget.ListItemID) { ui.historySelected = id }
+	ui.historyList.OnUnselected = func(widget.ListItemID) { ui.historySelected = -1 }
+
+	openButton := widget.NewButtonWithIcon(ui.tr("history.open"), theme.FolderOpenIcon(), ui.reopenSelectedHistory)
+	exportButton := widget.NewButtonWithIcon(ui.tr("button.export"), theme.DownloadIcon(), nil)
+	exportButton.OnTapped = func() {
+		run, ok := ui.loadSelectedHistory()
+		if !ok {
+			return
+		}
+		ui.showExportMenuFor(exportButton, exportSource{content: run.Output, report: run.Results, artifacts: run.Artifacts})
+	}
+	deleteButton := widget.NewButtonWithIcon(ui.tr("history.delete"), theme.DeleteIcon(), ui.deleteSelectedHistory)
+	refreshButton := widget.NewButtonWithIcon(ui.tr("history.refresh"), theme.ViewRefreshIcon(), ui.reloadHistoryList)
+	compareButton := widget.NewButtonWithIcon(ui.tr("history.compare"), theme.ListIcon(), ui.showCompareDialog)
+	baselineButton := widget.NewButtonWithIcon(ui.tr("history.baseline"), theme.ConfirmIcon(), ui.showBaselineDialog)
+	scheduleButton := widget.NewButtonWithIcon(ui.tr("schedule.title"), theme.HistoryIcon(), ui.showScheduleManager)
+	importButton := widget.NewButtonWithIcon(ui.tr("import.title"), theme.ContentPasteIcon(), ui.showImportResultDialog)
+	watchButton := widget.NewButtonWithIcon(ui.tr("watch.title"), theme.FolderIcon(), ui.showWatchFolderSettings)
+	ui.historyTagSelect = widget.NewSelect(nil, func(value string) {
+		tag := value
+		if value == ui.tr("history.all_tags") {
+			tag = ""
+		}
+		if tag != ui.historyTag {
+			ui.historyTag = tag
+			ui.reloadHistoryList()
+		}
+	})
+	ui.historyTagSelect.PlaceHolder = ui.tr("history.all_tags")
+
+	actions := container.NewHBox(scheduleButton, importButton, watchButton, ui.historyTagSelect, layout.NewSpacer(), refreshButton, compareButton, baselineButton, openButton, exportButton, deleteButton)
+	if isMobilePlatform() {
+		actions = container.NewAdaptiveGrid(2, refreshButton, compareButton, baselineButton, openButton, exportButton, deleteButton, scheduleButton, importButton, watchButton, ui.historyTagSelect)
+	}
+	ui.reloadHistoryList()
+	return container.NewBorder(actions, nil, nil, nil, ui.historyList)
+}
+
+func (ui *TestUI) historyItemText(item history.Summary) string {
+	parts := []string{item.StartedAt.Local().Format("2006-01-02 15:04:05")}
+	if item.Label != "" {
+		parts = append(parts, item.Label)
+	}
+	if item.Host != "" {
+		parts = append(parts, item.Host)
+	}
+	if item.Status != "" {
+		parts = append(parts, ui.tr("status."+item.Status))
+	}
+	if item.Baseline {
+		parts = append(parts, "★ "+ui.tr("history.baseline"))
+	}
+	if len(item.Tags) > 0 {
+		parts = append(parts, formatTags(item.Tags))
+	}
+	if n := len(item.Retests); n > 0 {
+		last := item.Retests[n-1]
+		parts = append(parts, fmt.Sprintf(ui.tr("rerun.retested"), ui.testLabels(last.Tests), last.At.Local().Format("2006-01-02 15:04")))
+	}
+	parts = append(parts, formatHumanDuration(item.Duration, ui.uiLang))
+	return strings.Join(parts, " · ")
+}
+
+// reloadHistoryList 重新读取索引并刷新列表，必须在 UI 线程调用
+func (ui *TestUI) reloadHistoryList() {
+	if ui.historyList == nil {
+		return
+	}
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return
+	}
+	items, err := store.List()
+	if err != nil {
+		return
+	}
+	ui.refreshHistoryTags(history.AllTags(items))
+	ui.historyItems = history.FilterByTag(items, ui.historyTag)
+	ui.historySelected = -1
+	ui.historyList.UnselectAll()
+	ui.historyList.Refresh()
+	ui.reloadTrendHosts()
+}
+
+// refreshHistoryTags 更新标签筛选的选项，当前筛选的标签已不存在时取消筛选
+func (ui *TestUI) refreshHistoryTags(tags []string) {
+	if ui.historyTag != "" && !slices.Contains(tags, ui.historyTag) {
+		ui.historyTag = ""
+	}
+	if ui.historyTagSelect == nil {
+		return
+	}
+	ui.historyTagSelect.Options = append([]string{ui.tr("history.all_tags")}, tags...)
+	if ui.historyTag == "" {
+		ui.historyTagSelect.ClearSelected()
+	} else {
+		ui.historyTagSelect.SetSelected(ui.historyTag)
+	}
+	ui.historyTagSelect.Refresh()
+}
+
+func (ui *TestUI) loadSelectedHistory() (history.Run, bool) {
+	if ui.historySelected < 0 || ui.historySelected >= len(ui.historyItems) {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("history.select_first"), ui.Window)
+		return history.Run{}, false
+	}
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return history.Run{}, false
+	}
+	run, err := store.Load(ui.historyItems[ui.historySelected].ID)
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return history.Run{}, false
+	}
+	return run, true
+}
+
+// reopenSelectedHistory 将选中的历史记录载入结果页
+func (ui *TestUI) reopenSelectedHistory() {
+	if ui.isRunning() {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("history.busy"), ui.Window)
+		return
+	}
+	run, ok := ui.loadSelectedHistory()
+	if !ok {
+		return
+	}
+	ui.loadHistoryRun(run)
+	ui.showResultTab()
+}
+
+func (ui *TestUI) loadHistoryRun(run history.Run) {
+	report := run.Results
+	if report == nil {
+		report = results.Parse(run.Output)
+	}
+	ui.Terminal.SetFullText(run.Output)
+	ui.Mu.Lock()
+	ui.ParsedResults = report
+	ui.StructuredResult = nil
+	ui.resultsRecord = &run.Summary
+	ui.Mu.Unlock()
+	ui.renderParsedResults(report)
+	if ui.StatusLabel != nil {
+		ui.StatusLabel.SetText(fmt.Sprintf(ui.tr("history.viewing"), run.StartedAt.Local().Format("2006-01-02 15:04")))
+	}
+}
+
+func (ui *TestUI) deleteSelectedHistory() {
+	if ui.historySelected < 0 || ui.historySelected >= len(ui.historyItems) {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("history.select_first"), ui.Window)
+		return
+	}
+	item := ui.historyItems[ui.historySelected]
+	dialog.ShowConfirm(ui.tr("history.delete"), ui.tr("history.delete_confirm"), func(ok bool) {
+		if !ok {
+			return
+		}
+		store := ui.historyStoreOrOpen()
+		if store == nil {
+			return
+		}
+		if err := store.Delete(item.ID); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		ui.reloadHistoryList()
+	}, ui.Window)
+}
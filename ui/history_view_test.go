@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRecordRunHistoryAndReopen(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("-----------就近节点测速-----------\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n")
+	ui.refreshParsedResults()
+	current := newRun(nil, ExecutionConfig{PresetKey: "minimal", SelectedOptions: map[string]bool{"speed": true}}, nil, nil)
+	current.Started = time.Now().Add(-time.Minute)
+	ui.recordRunHistory(current, "status.done")
+
+	if len(ui.historyItems) != 1 {
+		t.Fatalf("historyItems = %#v, want 1 run", ui.historyItems)
+	}
+	if got := ui.historyItems[0]; got.Status != "done" || got.Preset != "minimal" || !got.StartedAt.Equal(current.Started) || !slices.Equal(got.Tests, []string{"speed"}) {
+		t.Fatalf("summary = %#v", got)
+	}
+
+	ui.clearResults()
+	ui.historySelected = 0
+	run, ok := ui.loadSelectedHistory()
+	if !ok {
+		t.Fatal("loadSelectedHistory() failed")
+	}
+	ui.loadHistoryRun(run)
+	if ui.ParsedResults == nil || len(ui.ParsedResults.Speed) != 1 {
+		t.Fatalf("ParsedResults after reopen = %#v", ui.ParsedResults)
+	}
+	if ui.Terminal.GetText() != run.Output {
+		t.Fatal("terminal text was not restored from history")
+	}
+}
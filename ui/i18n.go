@@ -25,20 +25,141 @@ var presetDefs = []presetDef{
 }
 
 var i18nText = map[string]map[string]string{
-	"app.title":  {"zh": "融合怪测试 - GUI", "en": "Fusion Monster Test - GUI"},
-	"tab.config": {"zh": "测试选项与配置", "en": "Options & Config"},
-	"tab.launch": {"zh": "启动", "en": "Launch"},
-	"tab.result": {"zh": "测试结果", "en": "Results"},
-	"tab.log":    {"zh": "日志", "en": "Logs"},
+	"app.title":   {"zh": "融合怪测试 - GUI", "en": "Fusion Monster Test - GUI"},
+	"tab.config":  {"zh": "测试选项与配置", "en": "Options & Config"},
+	"tab.launch":  {"zh": "启动", "en": "Launch"},
+	"tab.result":  {"zh": "测试结果", "en": "Results"},
+	"tab.log":     {"zh": "日志", "en": "Logs"},
+	"tab.history": {"zh": "历史记录", "en": "History"},
+	"tab.trends":  {"zh": "趋势", "en": "Trends"},
+
+	"history.open":           {"zh": "打开", "en": "Open"},
+	"history.delete":         {"zh": "删除", "en": "Delete"},
+	"history.refresh":        {"zh": "刷新", "en": "Refresh"},
+	"history.select_first":   {"zh": "请先在列表中选择一条记录。", "en": "Select a run in the list first."},
+	"history.delete_confirm": {"zh": "确定删除这条历史记录吗？", "en": "Delete this run from history?"},
+	"history.busy":           {"zh": "测试运行中，请结束后再打开历史记录。", "en": "A test is running. Open history after it finishes."},
+	"history.viewing":        {"zh": "正在查看历史记录：%s", "en": "Viewing run from %s"},
+	"history.compare":        {"zh": "对比", "en": "Compare"},
+	"history.baseline":       {"zh": "基准", "en": "Baseline"},
+	"history.all_tags":       {"zh": "全部标签", "en": "All tags"},
+
+	"import.title":       {"zh": "导入结果", "en": "Import Result"},
+	"import.run":         {"zh": "导入", "en": "Import"},
+	"import.paste":       {"zh": "从剪贴板粘贴", "en": "Paste from clipboard"},
+	"import.hint":        {"zh": "粘贴 ecs 的文本报告（论坛帖子或在服务器上手动运行的输出），导入后可参与对比与趋势。", "en": "Paste an ecs text report (from a forum post or a manual run on a server); imported runs join comparisons and trends."},
+	"import.placeholder": {"zh": "在此粘贴 ecs 输出", "en": "Paste ecs output here"},
+	"import.host_hint":   {"zh": "可选，用于趋势与基准", "en": "Optional, used for trends and baselines"},
+	"import.label":       {"zh": "备注", "en": "Label"},
+	"import.label_hint":  {"zh": "可选，如帖子标题", "en": "Optional, e.g. the post title"},
+	"import.done":        {"zh": "已导入到历史记录，解析到 %d 项可对比的指标。", "en": "Imported into history with %d comparable metric(s)."},
+
+	"watch.title":    {"zh": "监视文件夹", "en": "Watch Folder"},
+	"watch.folder":   {"zh": "文件夹", "en": "Folder"},
+	"watch.none":     {"zh": "不监视", "en": "Not watching"},
+	"watch.hint":     {"zh": "新出现的 .log/.txt ecs 输出（如用 rsync 从服务器同步）会自动导入历史记录，主机名取自输出或文件名", "en": "New .log/.txt ecs output files (e.g. synced from servers with rsync) are imported into history; the host comes from the output or the file name"},
+	"watch.imported": {"zh": "已从监视文件夹导入 %d 个结果", "en": "Imported %d result(s) from the watched folder"},
+
+	"rerun.menu":         {"zh": "单独重跑“%s”", "en": "Re-run \"%s\" only"},
+	"rerun.tab_title":    {"zh": "重跑 %s", "en": "Re-run %s"},
+	"rerun.retested_at":  {"zh": "已于 %s 重跑", "en": "Re-tested at %s"},
+	"rerun.retested":     {"zh": "↻ %s 重跑于 %s", "en": "↻ %s re-tested at %s"},
+	"rerun.marker":       {"zh": "===== %s 重跑于 %s =====", "en": "===== %s re-tested at %s ====="},
+	"rerun.no_record":    {"zh": "结果面板中的结果尚未保存到历史记录，无法合并重跑结果。请先完成一次测试或从历史记录打开。", "en": "The results shown have not been saved to history, so a re-run cannot be merged. Finish a run or open one from history first."},
+	"rerun.host_changed": {"zh": "该结果来自 %s，而当前设置的目标是 %s。请先切换回原目标再重跑。", "en": "These results are from %s, but the current target is %s. Switch back to the original target before re-running."},
+	"rerun.merge_failed": {"zh": "重跑结果未能合并到原记录：", "en": "Could not merge the re-run into the original record: "},
+
+	"config.advanced.title": {"zh": "高级", "en": "Advanced"},
+
+	"backend.title":   {"zh": "环境变量与附加参数", "en": "Environment and extra flags"},
+	"backend.env":     {"zh": "环境变量", "en": "Environment"},
+	"backend.flags":   {"zh": "附加参数", "en": "Extra flags"},
+	"backend.edit":    {"zh": "编辑", "en": "Edit"},
+	"backend.none":    {"zh": "未设置传给 ecs 后端的环境变量与参数", "en": "No extra environment or flags for the ecs backend"},
+	"backend.summary": {"zh": "%d 个环境变量，%d 个附加参数", "en": "%d environment variable(s), %d extra flag(s)"},
+	"backend.local":   {"zh": "当前是本机测试，以下设置不会生效；启用远程测试或选择容器目标后可编辑。", "en": "The current target is this machine, so these settings have no effect; enable a remote host or pick a container target to edit them."},
+	"backend.hint":    {"zh": "用于界面尚未封装的 goecs 选项。环境变量每行一个 KEY=VALUE；附加参数按 shell 规则书写，追加在界面生成的参数之后（重复的参数以附加的为准）。两者只对 SSH 远程与容器运行生效，本机测试在进程内运行，不使用它们。随设置文件保存，可随设置导入导出。", "en": "For goecs options the GUI does not wrap yet. Environment variables are one KEY=VALUE per line. Extra flags use shell quoting and are appended after the flags the GUI generates (repeated flags take the extra value). Both only apply to SSH and container runs; local tests run in-process and do not use them. Both are kept in the settings file and included in settings export."},
+
+	"stdin.placeholder": {"zh": "后端等待输入时在此回复，回车发送", "en": "Reply here when the backend waits for input, Enter to send"},
+	"stdin.send":        {"zh": "发送", "en": "Send"},
+	"stdin.busy":        {"zh": "进程尚未读取之前的输入，请稍后再发送", "en": "The process has not read the earlier input yet, try again shortly"},
+
+	"expect.title":             {"zh": "自动回应", "en": "Auto replies"},
+	"expect.hint":              {"zh": "后端停在已知的交互式提示上时自动发送回复（经标准输入），无人值守与定时运行不会因此挂起。规则按顺序匹配输出的一行，同一行只回复一次，每次运行最多自动回复 32 次，回复会在终端中注明。", "en": "When the backend stops on a known interactive prompt, a reply is sent to its stdin so unattended and scheduled runs do not hang. Rules are matched in order against each output line, a line is answered once, a run gets at most 32 automatic replies, and each reply is noted in the terminal."},
+	"expect.enabled":           {"zh": "自动回应交互式提示", "en": "Answer interactive prompts automatically"},
+	"expect.toggle":            {"zh": "停用/启用", "en": "Disable/Enable"},
+	"expect.off":               {"zh": "已停用", "en": "disabled"},
+	"expect.enter":             {"zh": "回车", "en": "Enter"},
+	"expect.pattern":           {"zh": "匹配（正则）", "en": "Match (regex)"},
+	"expect.reply":             {"zh": "回复", "en": "Reply"},
+	"expect.reply_placeholder": {"zh": "留空只按回车", "en": "Empty just presses Enter"},
+	"expect.select_builtin":    {"zh": "请先选择一条内置规则，自定义规则可直接删除", "en": "Select a built-in rule first, custom rules can be deleted instead"},
+	"expect.select_custom":     {"zh": "请先选择一条自定义规则，内置规则只能停用", "en": "Select a custom rule first, built-in rules can only be disabled"},
+	"expect.rule.enter":        {"zh": "按回车继续", "en": "Press Enter to continue"},
+	"expect.rule.eula":         {"zh": "许可协议 (y/n)", "en": "License agreement (y/n)"},
+	"expect.rule.continue":     {"zh": "是否继续 (y/n)", "en": "Continue? (y/n)"},
+
+	"sudo.title":         {"zh": "需要 sudo 密码", "en": "sudo password required"},
+	"sudo.hint":          {"zh": "%s@%s 不是 root，部分测试（硬盘、路由追踪等）需要 root 权限。输入该用户的 sudo 密码后以 sudo 运行，密码只在本次运行中使用。", "en": "%s@%s is not root and some tests (disk, route tracing and others) need root. Enter the user's sudo password to run with sudo; it is only used for this run."},
+	"sudo.password":      {"zh": "sudo 密码", "en": "sudo password"},
+	"sudo.remember":      {"zh": "加密保存到主机配置", "en": "Save encrypted in the host profile"},
+	"sudo.remember_hint": {"zh": "仅当主机管理已解锁且保存了该主机与用户时可用", "en": "Available when host profiles are unlocked and this host and user are saved"},
+	"sudo.profile_hint":  {"zh": "非 root 用户运行时交给 sudo，留空则每次运行前询问", "en": "Passed to sudo for non-root users, empty asks before each run"},
+	"sudo.continue":      {"zh": "使用 sudo 运行", "en": "Run with sudo"},
+	"sudo.skip":          {"zh": "不使用 sudo", "en": "Run without sudo"},
+
+	"check.remote_cleanup": {"zh": "取回结果文件后从远程主机删除", "en": "Remove result files from the target after fetching them"},
+	"export.artifacts":     {"zh": "远程结果文件", "en": "Remote result files"},
+
+	"check.remote_windows": {"zh": "Windows 主机（OpenSSH）", "en": "Windows host (OpenSSH)"},
+	"hosts.windows_hint":   {"zh": "经 Windows 自带的 OpenSSH 服务连接，用 PowerShell 部署并运行 goecs.exe；不支持 WinRM，请在主机上启用 OpenSSH", "en": "Connects through the built-in OpenSSH server and deploys and runs goecs.exe with PowerShell; WinRM is not supported, so enable OpenSSH on the host"},
+
+	"baseline.title":             {"zh": "设为基准", "en": "Set baseline"},
+	"baseline.mark":              {"zh": "作为 %s 的基准", "en": "Use as the baseline for %s"},
+	"baseline.threshold":         {"zh": "回退阈值 (%)", "en": "Regression threshold (%)"},
+	"baseline.threshold_invalid": {"zh": "请输入 0 到 100 之间的百分比", "en": "Enter a percentage between 0 and 100"},
+	"baseline.hint":              {"zh": "之后该主机的运行会与基准比较，任一指标变差超过阈值时在终端中列出并发送通知。每台主机只有一条基准。", "en": "Later runs on this host are compared with the baseline; metrics that get worse by more than the threshold are listed in the terminal and notified. Each host has one baseline."},
+	"baseline.no_results":        {"zh": "这条记录没有可比较的指标，不能作为基准。", "en": "This run has no comparable metrics and cannot be a baseline."},
+	"baseline.regression_header": {"zh": "与 %s 的基准（%s）相比，%d 项指标变差超过 %.0f%%：", "en": "Compared with the %s baseline (%s), %d metric(s) got worse by more than %.0f%%:"},
+	"baseline.regression_title":  {"zh": "%s：低于基准", "en": "%s: below baseline"},
+	"baseline.regression_body":   {"zh": "%d 项指标比基准变差超过 %.0f%%，请在历史记录中对比。", "en": "%d metric(s) are more than %.0f%% worse than the baseline; compare them in History."},
+
+	"compare.title":            {"zh": "运行对比", "en": "Compare Runs"},
+	"compare.run":              {"zh": "对比", "en": "Compare"},
+	"compare.cancel":           {"zh": "取消", "en": "Cancel"},
+	"compare.need_runs":        {"zh": "至少需要两条历史记录才能对比。", "en": "At least two runs in history are needed to compare."},
+	"compare.pick_range":       {"zh": "请选择 2 到 4 条记录进行对比。", "en": "Select between 2 and 4 runs to compare."},
+	"compare.no_metrics":       {"zh": "所选记录中没有可对比的指标。", "en": "The selected runs have no comparable metrics."},
+	"compare.legend":           {"zh": "以最早的运行为基准，变化超过 %.0f%% 时标红（退步）或标绿（提升）。", "en": "The earliest run is the baseline; changes beyond %.0f%% are shown in red (regression) or green (improvement)."},
+	"compare.metric":           {"zh": "指标", "en": "Metric"},
+	"compare.baseline":         {"zh": "(基准)", "en": "(baseline)"},
+	"compare.metric.score":     {"zh": "得分", "en": "Score"},
+	"compare.metric.bandwidth": {"zh": "带宽", "en": "Bandwidth"},
+	"compare.metric.read":      {"zh": "读取", "en": "Read"},
+	"compare.metric.write":     {"zh": "写入", "en": "Write"},
+	"compare.metric.download":  {"zh": "下载", "en": "Download"},
+	"compare.metric.upload":    {"zh": "上传", "en": "Upload"},
+	"compare.metric.iops":      {"zh": "IOPS", "en": "IOPS"},
+	"compare.metric.latency":   {"zh": "延迟", "en": "Latency"},
+	"compare.metric.sha256":    {"zh": "SHA-256 速度", "en": "SHA-256 rate"},
+
+	"value.monthly_cost":      {"zh": "月费", "en": "Monthly cost"},
+	"value.score_per_cost":    {"zh": "得分 / 月费", "en": "Score per cost"},
+	"value.read_per_cost":     {"zh": "读取 / 月费", "en": "Read per cost"},
+	"value.write_per_cost":    {"zh": "写入 / 月费", "en": "Write per cost"},
+	"value.download_per_cost": {"zh": "最高下载 / 月费", "en": "Best download per cost"},
 
 	"status.ready":            {"zh": "就绪", "en": "Ready"},
 	"status.running":          {"zh": "测试运行中...", "en": "Running tests..."},
 	"status.executing":        {"zh": "正在执行测试...", "en": "Executing tests..."},
 	"status.stopping":         {"zh": "正在停止...", "en": "Stopping..."},
 	"status.stopped":          {"zh": "测试已停止", "en": "Stopped"},
+	"status.pause_pending":    {"zh": "将在当前阶段结束后暂停...", "en": "Pausing after the current stage..."},
+	"status.paused":           {"zh": "已暂停，点击继续恢复测试", "en": "Paused, click Resume to continue"},
 	"status.failed":           {"zh": "测试失败", "en": "Failed"},
 	"status.done":             {"zh": "测试完成", "en": "Completed"},
 	"status.current":          {"zh": "当前：%s (%d/%d)", "en": "Current: %s (%d/%d)"},
+	"status.eta":              {"zh": "，预计剩余 %s", "en": ", about %s left"},
 	"data.pending":            {"zh": "数据版本：检查中", "en": "Data version: checking"},
 	"data.version":            {"zh": "数据版本：%s · %s", "en": "Data version: %s · %s"},
 	"data.fallback":           {"zh": "（已回退）", "en": "(fallback)"},
@@ -46,20 +167,133 @@ var i18nText = map[string]map[string]string{
 	"data.unavailable":        {"zh": "数据版本：不可用（使用本地结果）", "en": "Data version: unavailable (using local results)"},
 	"result.structured.title": {"zh": "测试概览", "en": "Test Overview"},
 	"result.structured.empty": {"zh": "尚无测试概览。", "en": "No test overview yet."},
+	"results.empty":           {"zh": "暂无该项结果。", "en": "No results for this section yet."},
+	"results.tab.cpu":         {"zh": "CPU", "en": "CPU"},
+	"results.tab.memory":      {"zh": "内存", "en": "Memory"},
+	"results.tab.disk":        {"zh": "硬盘", "en": "Disk"},
+	"results.tab.speed":       {"zh": "网络测速", "en": "Speed"},
+	"results.tab.ip_quality":  {"zh": "IP质量", "en": "IP Quality"},
+	"results.tab.unlock":      {"zh": "流媒体解锁", "en": "Unlock"},
+	"results.tab.route":       {"zh": "回程路由", "en": "Routes"},
+	"results.tab.gpu":         {"zh": "GPU", "en": "GPU"},
+	"results.tab.raw":         {"zh": "其他", "en": "Other"},
+	"results.col.item":        {"zh": "项目", "en": "Item"},
+	"results.col.threads":     {"zh": "线程", "en": "Threads"},
+	"results.col.score":       {"zh": "得分", "en": "Score"},
+	"results.col.bandwidth":   {"zh": "带宽", "en": "Bandwidth"},
+	"results.col.path":        {"zh": "路径", "en": "Path"},
+	"results.col.block":       {"zh": "块大小", "en": "Block"},
+	"results.col.read":        {"zh": "读", "en": "Read"},
+	"results.col.write":       {"zh": "写", "en": "Write"},
+	"results.col.total":       {"zh": "总和", "en": "Total"},
+	"results.disk_size":       {"zh": "测试文件大小：%s", "en": "Test file size: %s"},
+	"results.disk_shrunk":     {"zh": "测试文件大小：%s（安全模式：剩余空间不足，已由 %s 缩小，成绩可能偏高）", "en": "Test file size: %s (safe mode shrank it from %s because of low free space; results may read high)"},
+	"results.col.node":        {"zh": "节点", "en": "Node"},
+	"results.col.upload":      {"zh": "上传", "en": "Upload"},
+	"results.col.download":    {"zh": "下载", "en": "Download"},
+	"results.col.latency":     {"zh": "延迟", "en": "Latency"},
+	"results.col.loss":        {"zh": "丢包", "en": "Loss"},
+	"results.col.value":       {"zh": "值", "en": "Value"},
+	"results.col.platform":    {"zh": "平台", "en": "Platform"},
+	"results.col.status":      {"zh": "状态", "en": "Status"},
+	"results.col.group":       {"zh": "分组", "en": "Group"},
+	"results.col.device":      {"zh": "设备", "en": "Device"},
+	"results.col.sha256":      {"zh": "SHA-256 速度", "en": "SHA-256 rate"},
+	"results.gpu.memory":      {"zh": "显存 %s", "en": "VRAM %s"},
+	"results.gpu.driver":      {"zh": "驱动 %s", "en": "Driver %s"},
+	"status.interrupted":      {"zh": "意外中断", "en": "Interrupted"},
 	"status.partial":          {"zh": "部分完成", "en": "Partially completed"},
 	"status.timeout":          {"zh": "已超时", "en": "Timed out"},
+	"status.imported":         {"zh": "外部导入", "en": "Imported"},
+	"status.log_file":         {"zh": "日志文件", "en": "Log file"},
 	"badge.partial":           {"zh": "[部分完成]", "en": "[PARTIAL]"},
 	"badge.timeout":           {"zh": "[已超时]", "en": "[TIMEOUT]"},
 	"badge.ready":             {"zh": "[就绪]", "en": "[READY]"},
 	"badge.running":           {"zh": "[运行中]", "en": "[RUNNING]"},
 	"badge.stopped":           {"zh": "[已停止]", "en": "[STOPPED]"},
+	"badge.paused":            {"zh": "[已暂停]", "en": "[PAUSED]"},
 	"badge.failed":            {"zh": "[失败]", "en": "[FAILED]"},
 	"badge.done":              {"zh": "[完成]", "en": "[DONE]"},
 
+	"virt.caveat_line":            {"zh": "%s（影响：%s）", "en": "%s (affects: %s)"},
+	"virt.section_sep":            {"zh": "、", "en": ", "},
+	"virt.section.basic":          {"zh": "基础信息", "en": "Basic info"},
+	"virt.section.cpu":            {"zh": "CPU", "en": "CPU"},
+	"virt.section.memory":         {"zh": "内存", "en": "Memory"},
+	"virt.section.disk":           {"zh": "硬盘", "en": "Disk"},
+	"virt.section.speed":          {"zh": "网络测速", "en": "Speed"},
+	"virt.section.ping":           {"zh": "PING 值", "en": "Ping"},
+	"virt.caveat.openvz_disk":     {"zh": "OpenVZ：硬盘结果反映的是宿主机缓存，而非分配给本机的磁盘性能", "en": "OpenVZ: disk results reflect the host cache rather than the disk allocated to this container"},
+	"virt.caveat.openvz_kernel":   {"zh": "OpenVZ：与宿主机共享内核，内核版本、TCP 拥塞控制与内存限额由宿主机决定", "en": "OpenVZ: the kernel is shared with the host, so kernel version, TCP congestion control and memory limits come from the host"},
+	"virt.caveat.lxc_sysctl":      {"zh": "LXC：与宿主机共享内核，部分基于 sysctl 的检查不可用或显示宿主机的值", "en": "LXC: the kernel is shared with the host, so some sysctl-based checks are unavailable or show host values"},
+	"virt.caveat.lxc_disk":        {"zh": "LXC：硬盘测试经宿主机页缓存，结果可能偏高", "en": "LXC: disk tests go through the host page cache and may read high"},
+	"virt.caveat.docker_hardware": {"zh": "Docker：在容器内测试，硬件信息与性能结果可能与宿主机或实际配额不符", "en": "Docker: tests ran inside a container, so hardware info and performance may not match the host or the actual quota"},
+	"virt.caveat.hyperv_disk":     {"zh": "Hyper-V：动态扩展的 VHDX 与宿主机写缓存可能使硬盘结果偏高", "en": "Hyper-V: dynamically expanding VHDX disks and the host write cache may inflate disk results"},
+	"virt.caveat.wsl_network":     {"zh": "WSL：网络经 Windows 的 NAT 转发，测速与延迟包含额外开销", "en": "WSL: traffic is NATed through Windows, so speed and latency include extra overhead"},
+	"virt.caveat.wsl_disk":        {"zh": "WSL：/mnt 下的路径经 Windows 文件系统访问，硬盘结果偏低", "en": "WSL: paths under /mnt go through the Windows file system and read low"},
+	"virt.caveat.qemu_cpu":        {"zh": "QEMU：未使用 KVM 加速的软件模拟，CPU 得分明显偏低", "en": "QEMU: software emulation without KVM acceleration, so CPU scores are much lower"},
+
+	"terminal.follow":           {"zh": "自动滚动", "en": "Auto-scroll"},
+	"terminal.truncated":        {"zh": "已丢弃 %d 行较早的输出，日志不完整", "en": "%d earlier lines were discarded; the log is incomplete"},
+	"terminal.skipped":          {"zh": "输出过快，已跳过约 %d KB 待显示的输出", "en": "Output was too fast; about %d KB of pending output was skipped"},
+	"terminal.waited":           {"zh": "输出过快，已等待界面刷新 %d 次", "en": "Output was too fast; waited for the display %d times"},
+	"terminal.spilled":          {"zh": "%d 行较早的输出已转存到磁盘，导出时会包含", "en": "%d earlier lines were moved to disk and are included in exports"},
+	"terminal.copy":             {"zh": "复制", "en": "Copy"},
+	"terminal.copy_all":         {"zh": "复制全部", "en": "Copy All"},
+	"terminal.select_all":       {"zh": "全选", "en": "Select All"},
+	"ping.running":              {"zh": "正在 ping…", "en": "Pinging…"},
+	"terminal.jump_bottom":      {"zh": "跳到底部（%d 行新输出）", "en": "Jump to bottom (%d new lines)"},
+	"filter.level_all":          {"zh": "全部级别", "en": "All levels"},
+	"filter.level_warnings":     {"zh": "警告及错误", "en": "Warnings + errors"},
+	"filter.level_errors":       {"zh": "仅错误", "en": "Errors only"},
+	"filter.section_all":        {"zh": "全部分区", "en": "All sections"},
+	"filter.section.basic":      {"zh": "基础信息", "en": "Basic info"},
+	"filter.section.cpu":        {"zh": "CPU", "en": "CPU"},
+	"filter.section.memory":     {"zh": "内存", "en": "Memory"},
+	"filter.section.disk":       {"zh": "硬盘", "en": "Disk"},
+	"filter.section.unlock":     {"zh": "流媒体解锁", "en": "Streaming unlock"},
+	"filter.section.ip_quality": {"zh": "IP 质量", "en": "IP quality"},
+	"filter.section.email":      {"zh": "邮件端口", "en": "Email ports"},
+	"filter.section.backtrace":  {"zh": "回程线路", "en": "Backtrace"},
+	"filter.section.route":      {"zh": "路由追踪", "en": "Route trace"},
+	"filter.section.ping":       {"zh": "PING", "en": "Ping"},
+	"filter.section.speed":      {"zh": "测速", "en": "Speed test"},
+	"filter.section.gpu":        {"zh": "GPU", "en": "GPU"},
+	"filter.regex_placeholder":  {"zh": "正则过滤（不区分大小写）", "en": "Regex filter (case-insensitive)"},
+	"fold.collapse_finished":    {"zh": "折叠已完成阶段", "en": "Collapse Finished Stages"},
+	"fold.expand_all":           {"zh": "展开全部阶段", "en": "Expand All Stages"},
+	"fold.auto":                 {"zh": "阶段结束后自动折叠", "en": "Auto-collapse Finished Stages"},
+	"fold.folded":               {"zh": "… 已折叠 %d 行", "en": "… %d lines folded"},
+	"bookmark.add":              {"zh": "添加书签", "en": "Add Bookmark"},
+	"bookmark.remove":           {"zh": "移除书签", "en": "Remove Bookmark"},
+	"bookmark.edit_note":        {"zh": "编辑批注…", "en": "Edit Note…"},
+	"bookmark.note":             {"zh": "批注", "en": "Note"},
+	"bookmark.note_placeholder": {"zh": "例如：该节点疑似限速", "en": "e.g. this node looks throttled"},
+	"bookmark.line":             {"zh": "第 %d 行", "en": "Line %d"},
+	"bookmark.prev":             {"zh": "上一个书签", "en": "Previous Bookmark"},
+	"bookmark.next":             {"zh": "下一个书签", "en": "Next Bookmark"},
+	"bookmark.clear":            {"zh": "清除全部书签", "en": "Clear All Bookmarks"},
+	"gutter.off":                {"zh": "不显示时间", "en": "No Timestamps"},
+	"gutter.clock":              {"zh": "显示时间", "en": "Wall-clock Time"},
+	"gutter.elapsed":            {"zh": "显示耗时", "en": "Elapsed Time"},
+	"filter.status":             {"zh": "显示 %d / %d 行", "en": "%d / %d lines"},
+	"search.placeholder":        {"zh": "搜索输出（回车跳到下一个）", "en": "Search output (Enter for next)"},
+	"search.ignore_case":        {"zh": "忽略大小写", "en": "Ignore case"},
+	"search.regex":              {"zh": "正则", "en": "Regex"},
+	"search.none":               {"zh": "无匹配", "en": "No matches"},
+	"search.invalid":            {"zh": "正则无效", "en": "Invalid regex"},
+
+	"terminal_backpressure.coalesce": {"zh": "合并，跳过较早的输出", "en": "Coalesce, skip older"},
+	"terminal_backpressure.block":    {"zh": "等待，不丢失输出", "en": "Wait, keep everything"},
+
 	"button.start":          {"zh": "开始测试", "en": "Start"},
 	"button.stop":           {"zh": "停止测试", "en": "Stop"},
+	"button.force_stop":     {"zh": "强制停止", "en": "Force stop"},
+	"button.pause":          {"zh": "暂停", "en": "Pause"},
+	"button.resume":         {"zh": "继续", "en": "Resume"},
 	"button.clear":          {"zh": "清空", "en": "Clear"},
 	"button.copy":           {"zh": "复制", "en": "Copy"},
+	"button.save_log":       {"zh": "保存日志", "en": "Save Log"},
 	"button.export":         {"zh": "导出", "en": "Export"},
 	"button.select_all":     {"zh": "全选", "en": "Select All"},
 	"button.deselect_all":   {"zh": "取消全选", "en": "Clear All"},
@@ -67,16 +301,21 @@ var i18nText = map[string]map[string]string{
 	"button.log_clear":      {"zh": "清空日志", "en": "Clear Logs"},
 	"button.log_export":     {"zh": "导出日志", "en": "Export Logs"},
 	"button.open_config":    {"zh": "详细配置", "en": "Config"},
+	"export.raw":            {"zh": "原始输出", "en": "Raw output"},
+	"export.markdown":       {"zh": "Markdown 表格（论坛）", "en": "Markdown tables (forums)"},
+	"export.html":           {"zh": "HTML 报告", "en": "HTML report"},
+	"export.pdf":            {"zh": "PDF 报告", "en": "PDF report"},
+	"export.image":          {"zh": "图片 (PNG)", "en": "Image (PNG)"},
 	"button.share":          {"zh": "分享", "en": "Share"},
 	"button.start_standard": {"zh": "开始精简版", "en": "Start Standard"},
 	"button.start_full":     {"zh": "开始完全体", "en": "Start Full"},
 	"button.start_single":   {"zh": "单项测试", "en": "Single Test"},
 
 	"dialog.no_privilege_title": {"zh": "权限不足", "en": "Insufficient Privileges"},
-	"dialog.no_privilege_body":  {"zh": "以下测试项需要管理员/root权限才能正常运行：\n\n%s\n\n请关闭程序后以管理员（Windows：右键→以管理员身份运行；Linux/macOS：sudo）身份重新启动。", "en": "The following tests require Administrator/root privileges:\n\n%s\n\nPlease close the app and restart it as Administrator (Windows: right-click -> Run as administrator; Linux/macOS: sudo)."},
-	"dialog.uac_started":        {"zh": "已请求管理员权限重新启动。请在系统弹窗中确认，然后在新窗口中重新开始测试。", "en": "Administrator restart requested. Confirm the system prompt, then start the test again in the new window."},
-	"dialog.uac_failed":         {"zh": "无法自动请求管理员权限，请手动以管理员身份重新启动。", "en": "Unable to request Administrator automatically. Please restart manually as Administrator."},
+	"dialog.no_privilege_body":  {"zh": "以下测试项需要管理员/root 权限，以当前权限运行会失败：\n\n%s\n\n可以以管理员身份重新启动程序（Windows 为 UAC，Linux 为 pkexec，macOS 为系统密码框），或去掉这些测试项继续。", "en": "The following tests require Administrator/root privileges and will fail with the current privileges:\n\n%s\n\nRelaunch the app elevated (UAC on Windows, pkexec on Linux, the password prompt on macOS), or continue without these tests."},
+	"dialog.uac_failed":         {"zh": "无法以管理员身份重新启动，请手动以管理员身份运行（Windows：右键→以管理员身份运行；Linux/macOS：sudo）。", "en": "Unable to relaunch elevated. Please restart it manually as Administrator (Windows: right-click -> Run as administrator; Linux/macOS: sudo)."},
 
+	"dialog.close":             {"zh": "关闭", "en": "Close"},
 	"dialog.hint":              {"zh": "提示", "en": "Notice"},
 	"dialog.success":           {"zh": "成功", "en": "Success"},
 	"dialog.no_tests":          {"zh": "请至少选择一项测试！", "en": "Select at least one test item."},
@@ -90,8 +329,12 @@ var i18nText = map[string]map[string]string{
 	"dialog.copy_ok":           {"zh": "测试结果已复制到剪贴板", "en": "Results copied to clipboard."},
 	"dialog.log_export_ok":     {"zh": "日志已成功导出", "en": "Logs exported successfully."},
 	"dialog.running_no_switch": {"zh": "测试运行中，暂不支持切换语言。", "en": "Language switch is disabled while tests are running."},
+	"dialog.remote_invalid":    {"zh": "远程测试配置不完整：", "en": "Remote test settings are incomplete:"},
 	"notify.done_title":        {"zh": "融合怪测试完成", "en": "GOECS test completed"},
-	"notify.done_body":         {"zh": "测试已完成，用时 %s。", "en": "Completed in %s."},
+	"notify.duration":          {"zh": "用时 %s", "en": "took %s"},
+	"notify.disk":              {"zh": "硬盘 读 %.0f / 写 %.0f MB/s", "en": "disk R %.0f / W %.0f MB/s"},
+	"notify.network":           {"zh": "网络 下 %.0f / 上 %.0f Mbps", "en": "net ↓%.0f / ↑%.0f Mbps"},
+	"notify.failed_stages":     {"zh": "%d 个阶段失败：%s", "en": "%d stage(s) failed: %s"},
 	"notify.failed_title":      {"zh": "融合怪测试失败", "en": "GOECS test failed"},
 	"notify.failed_body":       {"zh": "测试未能完成，请查看结果页。", "en": "The test did not complete. Check the results tab."},
 	"notify.stopped_title":     {"zh": "融合怪测试已停止", "en": "GOECS test stopped"},
@@ -131,6 +374,12 @@ var i18nText = map[string]map[string]string{
 	"check.nt3":            {"zh": "三网回程路由检测", "en": "3-Net Route"},
 	"check.speed":          {"zh": "网络测速", "en": "Speed Test"},
 	"check.ping":           {"zh": "三网PING值检测", "en": "3-Net Ping"},
+	"check.gpu":            {"zh": "GPU 信息（仅本机）", "en": "GPU info (local only)"},
+	"check.gpu_bench":      {"zh": "GPU 基准（需要 hashcat）", "en": "GPU benchmark (needs hashcat)"},
+	"check.log_keep_ansi":  {"zh": "保存日志时保留 ANSI 颜色代码", "en": "Keep ANSI color codes in saved logs"},
+	"check.log_auto_save":  {"zh": "测试结束后自动保存日志", "en": "Auto-save log when a run finishes"},
+	"check.notify":         {"zh": "完成时发送桌面通知", "en": "Desktop notifications"},
+	"check.tray":           {"zh": "关闭窗口时最小化到托盘", "en": "Minimize to tray on close"},
 	"check.log":            {"zh": "启用日志记录", "en": "Enable Logging"},
 	"check.disk_multi":     {"zh": "启用多磁盘检测", "en": "Enable Multi-Disk"},
 	"check.auto_disk":      {"zh": "磁盘方法失败自动切换", "en": "Auto Switch Disk Method"},
@@ -143,30 +392,100 @@ var i18nText = map[string]map[string]string{
 	"check.analysis":       {"zh": "测试后结果总结分析", "en": "Post-Test Summary"},
 	"check.data_offline":   {"zh": "仅使用内置数据快照", "en": "Use Embedded Data Only"},
 	"check.privacy_mode":   {"zh": "隐私模式（禁用上传）", "en": "Privacy Mode (Disable Upload)"},
+	"check.offline_mode":   {"zh": "离线/内网模式", "en": "Offline / Intranet Mode"},
+	"check.remote_enable":  {"zh": "在远程主机上运行（SSH）", "en": "Run on a remote host (SSH)"},
 
-	"launch.card.title": {"zh": "快速启动", "en": "Quick Launch"},
-	"launch.card.sub":   {"zh": "直接运行预设或单项测试", "en": "Run presets or a single test directly"},
-	"launch.presets":    {"zh": "常用预设", "en": "Presets"},
-	"launch.single":     {"zh": "单项测试", "en": "Single Tests"},
-	"launch.manage":     {"zh": "配置与结果", "en": "Config & Results"},
-	"single.basic":      {"zh": "基础信息", "en": "Basic"},
-	"single.cpu":        {"zh": "CPU", "en": "CPU"},
-	"single.memory":     {"zh": "内存", "en": "Memory"},
-	"single.disk":       {"zh": "磁盘", "en": "Disk"},
-	"single.unlock":     {"zh": "流媒体解锁", "en": "Unlock"},
-	"single.security":   {"zh": "IP质量", "en": "IP Quality"},
-	"single.email":      {"zh": "邮件端口", "en": "Email"},
-	"single.backtrace":  {"zh": "回程线路", "en": "Backtrace"},
-	"single.nt3":        {"zh": "NT3路由", "en": "NT3"},
-	"single.speed":      {"zh": "测速", "en": "Speed"},
-	"single.ping":       {"zh": "三网PING", "en": "3-Net Ping"},
-	"single.tgdc":       {"zh": "Telegram DC", "en": "Telegram DC"},
-	"single.web":        {"zh": "网站延迟", "en": "Website"},
-	"footer.gui":        {"zh": "GUI项目", "en": "GUI Project"},
-	"footer.upstream":   {"zh": "上游项目", "en": "Upstream"},
-	"footer.guide":      {"zh": "测试基准", "en": "Guide"},
-
-	"config.card.title":    {"zh": "详细配置", "en": "Detailed Config"},
+	"launch.card.title":      {"zh": "快速启动", "en": "Quick Launch"},
+	"launch.card.sub":        {"zh": "直接运行预设或单项测试", "en": "Run presets or a single test directly"},
+	"launch.presets":         {"zh": "常用预设", "en": "Presets"},
+	"launch.single":          {"zh": "单项测试", "en": "Single Tests"},
+	"sidebar.title":          {"zh": "测试清单", "en": "Test Checklist"},
+	"sidebar.subtitle":       {"zh": "勾选要运行的模块", "en": "Pick the modules to run"},
+	"sidebar.preset.minimal": {"zh": "极简", "en": "Minimal"},
+	"sidebar.preset.full":    {"zh": "完全", "en": "Full"},
+	"sidebar.preset.network": {"zh": "仅网络", "en": "Network only"},
+	"sidebar.summary":        {"zh": "已选 %d/%d 项", "en": "%d of %d selected"},
+	"sidebar.start":          {"zh": "运行所选项", "en": "Run selected"},
+	"launch.manage":          {"zh": "配置与结果", "en": "Config & Results"},
+	"single.basic":           {"zh": "基础信息", "en": "Basic"},
+	"single.cpu":             {"zh": "CPU", "en": "CPU"},
+	"single.memory":          {"zh": "内存", "en": "Memory"},
+	"single.disk":            {"zh": "磁盘", "en": "Disk"},
+	"single.unlock":          {"zh": "流媒体解锁", "en": "Unlock"},
+	"single.security":        {"zh": "IP质量", "en": "IP Quality"},
+	"single.email":           {"zh": "邮件端口", "en": "Email"},
+	"single.backtrace":       {"zh": "回程线路", "en": "Backtrace"},
+	"single.nt3":             {"zh": "NT3路由", "en": "NT3"},
+	"single.speed":           {"zh": "测速", "en": "Speed"},
+	"single.ping":            {"zh": "三网PING", "en": "3-Net Ping"},
+	"single.tgdc":            {"zh": "Telegram DC", "en": "Telegram DC"},
+	"single.web":             {"zh": "网站延迟", "en": "Website"},
+	"footer.gui":             {"zh": "GUI项目", "en": "GUI Project"},
+	"footer.upstream":        {"zh": "上游项目", "en": "Upstream"},
+	"footer.guide":           {"zh": "测试基准", "en": "Guide"},
+
+	"config.card.title": {"zh": "详细配置", "en": "Detailed Config"},
+	"remote.card.title": {"zh": "远程测试", "en": "Remote Test"},
+	"remote.card.sub":   {"zh": "通过 SSH 连接 VPS 或用 docker exec 进入容器，自动上传或下载 goecs 并在目标中执行，输出实时回传", "en": "Connect to a VPS over SSH or enter a container with docker exec, upload or download goecs there and stream its output back"},
+
+	"hosts.title":           {"zh": "主机管理", "en": "Hosts"},
+	"docker.title":          {"zh": "Docker 容器", "en": "Docker container"},
+	"docker.active":         {"zh": "在容器 %s 中运行（docker exec）", "en": "Running in container %s (docker exec)"},
+	"docker.refresh":        {"zh": "列出容器", "en": "List containers"},
+	"docker.listing":        {"zh": "正在读取运行中的容器...", "en": "Reading running containers..."},
+	"docker.found":          {"zh": "找到 %d 个运行中的容器", "en": "Found %d running containers"},
+	"hosts.close":           {"zh": "关闭", "en": "Close"},
+	"hosts.add":             {"zh": "新增主机", "en": "Add Host"},
+	"hosts.edit":            {"zh": "编辑主机", "en": "Edit Host"},
+	"hosts.save":            {"zh": "保存", "en": "Save"},
+	"hosts.ok":              {"zh": "确定", "en": "OK"},
+	"hosts.use":             {"zh": "填入表单", "en": "Use"},
+	"hosts.run":             {"zh": "一键测试", "en": "Run Test"},
+	"hosts.name":            {"zh": "名称", "en": "Name"},
+	"hosts.auth":            {"zh": "认证方式", "en": "Auth"},
+	"hosts.auth_password":   {"zh": "密码", "en": "Password"},
+	"hosts.auth_key":        {"zh": "私钥", "en": "Private key"},
+	"hosts.jump":            {"zh": "跳板机", "en": "Jump Host"},
+	"hosts.no_jump":         {"zh": "（直连）", "en": "(direct)"},
+	"hosts.via":             {"zh": "（经由 %s）", "en": " (via %s)"},
+	"hosts.provider":        {"zh": "服务商 / 地区", "en": "Provider / Region"},
+	"hosts.price":           {"zh": "价格 / 购买日期", "en": "Price / Purchased"},
+	"hosts.labels":          {"zh": "标签", "en": "Tags"},
+	"hosts.cost":            {"zh": "月费", "en": "Monthly cost"},
+	"hosts.cost_hint":       {"zh": "只填数字，用于对比中的性价比（每单位月费的得分、硬盘与带宽）", "en": "A number only; used for price-performance (score, disk and bandwidth per unit of cost) when comparing runs"},
+	"hosts.labels_hint":     {"zh": "逗号分隔，如 生产, 大盘鸡", "en": "Comma separated, e.g. prod, storage"},
+	"hosts.notes":           {"zh": "备注", "en": "Notes"},
+	"hosts.jump_active":     {"zh": "经由跳板机 %s@%s 连接", "en": "Connecting via jump host %s@%s"},
+	"hosts.select_first":    {"zh": "请先在列表中选择一台主机。", "en": "Select a host in the list first."},
+	"hosts.delete_confirm":  {"zh": "确定删除主机“%s”吗？", "en": "Delete host \"%s\"?"},
+	"hosts.unlock":          {"zh": "解锁主机列表", "en": "Unlock Hosts"},
+	"hosts.set_master":      {"zh": "设置主密码", "en": "Set Master Password"},
+	"hosts.master":          {"zh": "主密码", "en": "Master password"},
+	"hosts.master_confirm":  {"zh": "确认主密码", "en": "Confirm password"},
+	"hosts.master_mismatch": {"zh": "两次输入的主密码不一致。", "en": "The passwords do not match."},
+
+	"hosts.proxy":        {"zh": "SOCKS5 代理", "en": "SOCKS5 Proxy"},
+	"hosts.proxy_hint":   {"zh": "socks5:// 或 socks5h://（由代理解析主机名），不能与跳板机同时使用，可设置在跳板机上", "en": "socks5:// or socks5h:// (the proxy resolves host names); cannot be combined with a jump host, set it on the jump host instead"},
+	"hosts.proxy_active": {"zh": "经由 SOCKS5 代理 %s 连接", "en": "Connecting via SOCKS5 proxy %s"},
+
+	"batch.title":          {"zh": "批量测试", "en": "Batch Test"},
+	"batch.start":          {"zh": "开始", "en": "Start"},
+	"batch.concurrency":    {"zh": "并发数", "en": "Concurrency"},
+	"batch.no_hosts":       {"zh": "还没有保存的主机，请先在主机管理中添加。", "en": "No saved hosts yet. Add some in the host manager first."},
+	"batch.pick_hosts":     {"zh": "请至少选择一台主机。", "en": "Select at least one host."},
+	"batch.queued":         {"zh": "排队中", "en": "Queued"},
+	"batch.summary":        {"zh": "汇总", "en": "Summary"},
+	"batch.export":         {"zh": "导出汇总", "en": "Export Summary"},
+	"batch.overall":        {"zh": "已完成 %d / %d 台（并发 %d）", "en": "%d of %d hosts finished (concurrency %d)"},
+	"batch.col.host":       {"zh": "主机", "en": "Host"},
+	"batch.col.status":     {"zh": "状态", "en": "Status"},
+	"batch.col.duration":   {"zh": "耗时", "en": "Duration"},
+	"batch.col.cpu":        {"zh": "CPU 得分", "en": "CPU Score"},
+	"batch.col.memory":     {"zh": "内存 MB/s", "en": "Memory MB/s"},
+	"batch.col.disk_read":  {"zh": "硬盘读 MB/s", "en": "Disk Read MB/s"},
+	"batch.col.disk_write": {"zh": "硬盘写 MB/s", "en": "Disk Write MB/s"},
+	"batch.col.download":   {"zh": "下载 Mbps", "en": "Download Mbps"},
+	"batch.col.upload":     {"zh": "上传 Mbps", "en": "Upload Mbps"},
 	"config.card.sub":      {"zh": "按功能分组管理测试参数", "en": "Grouped by capability"},
 	"config.general.title": {"zh": "通用", "en": "General"},
 	"config.general.sub":   {"zh": "语言、日志与结果", "en": "Language, logs and results"},
@@ -177,7 +496,7 @@ var i18nText = map[string]map[string]string{
 	"config.mem.title":     {"zh": "内存", "en": "Memory"},
 	"config.mem.sub":       {"zh": "测试方法", "en": "Test method"},
 	"config.disk.title":    {"zh": "磁盘", "en": "Disk"},
-	"config.disk.sub":      {"zh": "路径与多盘检测", "en": "Path and multi-disk"},
+	"config.disk.sub":      {"zh": "路径、fio 参数与多盘检测", "en": "Path, fio options and multi-disk"},
 	"config.deep.title":    {"zh": "深度测试", "en": "Deep Tests"},
 	"config.deep.sub":      {"zh": "仅运行明确启用并填写目标的高负载项目", "en": "High-load tests run only with explicit targets"},
 	"config.unlock.title":  {"zh": "流媒体解锁", "en": "Streaming Unlock"},
@@ -189,8 +508,16 @@ var i18nText = map[string]map[string]string{
 	"config.ping.title":    {"zh": "PING 扩展", "en": "Ping Extensions"},
 	"config.ping.sub":      {"zh": "排序、目标与附加探针", "en": "Order, targets, and probes"},
 
+	"batch.preflight_title":    {"zh": "连接检查", "en": "Connectivity Check"},
+	"batch.preflight_checking": {"zh": "正在并发检查 %d 台主机能否连接…", "en": "Checking whether %d hosts are reachable…"},
+	"batch.preflight_ok":       {"zh": "%s — %d ms", "en": "%s — %d ms"},
+	"batch.preflight_failed":   {"zh": "%s — 无法连接：%v", "en": "%s — unreachable: %v"},
+	"batch.preflight_summary":  {"zh": "%d/%d 台主机无法连接，已默认取消勾选。确认要测试的主机后开始。", "en": "%d of %d hosts are unreachable and were unticked. Confirm the hosts to test, then start."},
+
 	"label.language":           {"zh": "语言", "en": "Language"},
 	"label.theme":              {"zh": "主题", "en": "Theme"},
+	"label.terminal_buffer":    {"zh": "终端缓冲", "en": "Terminal buffer"},
+	"terminal_buffer.spill":    {"zh": "不限（超出部分写入磁盘）", "en": "Unlimited (spill to disk)"},
 	"label.cpu_method":         {"zh": "测试方法", "en": "Method"},
 	"label.memory_method":      {"zh": "内存方法", "en": "Memory Method"},
 	"label.disk_method":        {"zh": "磁盘方法", "en": "Disk Method"},
@@ -206,6 +533,15 @@ var i18nText = map[string]map[string]string{
 	"label.ping_sort":          {"zh": "Ping 排序", "en": "Ping Order"},
 	"label.ping_scope":         {"zh": "Ping 目标", "en": "Ping Targets"},
 	"label.tcp_sort":           {"zh": "TCP 排序", "en": "TCP Order"},
+	"label.remote_host":        {"zh": "主机", "en": "Host"},
+	"label.remote_port":        {"zh": "端口", "en": "Port"},
+	"label.remote_user":        {"zh": "用户名", "en": "User"},
+	"label.remote_password":    {"zh": "密码", "en": "Password"},
+	"label.remote_key":         {"zh": "私钥文件", "en": "Private Key"},
+	"label.remote_passphrase":  {"zh": "私钥密码", "en": "Key Passphrase"},
+	"label.remote_binary":      {"zh": "本地 goecs（可选）", "en": "Local goecs (optional)"},
+	"label.docker_host":        {"zh": "DOCKER_HOST", "en": "DOCKER_HOST"},
+	"label.docker_container":   {"zh": "容器", "en": "Container"},
 	"label.unlock_region":      {"zh": "测试地区", "en": "Region"},
 	"label.unlock_ip_ver":      {"zh": "IP 版本", "en": "IP Version"},
 	"label.unlock_interface":   {"zh": "源接口或 IP", "en": "Source Interface or IP"},
@@ -219,56 +555,443 @@ var i18nText = map[string]map[string]string{
 	"label.max_duration":       {"zh": "全局截止时间", "en": "Global Deadline"},
 	"label.hardware_budget":    {"zh": "硬件阶段预算", "en": "Hardware Budget"},
 
-	"placeholder.disk_path":          {"zh": "/tmp 或留空自动检测", "en": "/tmp or empty for auto"},
-	"placeholder.deep_disk_paths":    {"zh": "留空关闭；多个目录用逗号分隔", "en": "Empty disables; comma-separated directories"},
-	"placeholder.deep_smart_devices": {"zh": "留空关闭；多个设备用逗号分隔", "en": "Empty disables; comma-separated devices"},
-	"placeholder.deep_burn_duration": {"zh": "留空关闭，例如 30s 或 2m", "en": "Empty disables; for example 30s or 2m"},
-	"placeholder.deep_gpu_device":    {"zh": "留空关闭；填写 GPU 设备选择器", "en": "Empty disables; enter a GPU selector"},
-	"placeholder.sp_num":             {"zh": "每运营商测速节点数", "en": "Nodes per ISP"},
-	"placeholder.output_width":       {"zh": "建议 80-100", "en": "80-100 recommended"},
-	"placeholder.output_file":        {"zh": "goecs.md", "en": "goecs.md"},
-	"placeholder.json_path":          {"zh": "留空关闭，例如 goecs.json", "en": "Empty disables, e.g. goecs.json"},
-	"placeholder.max_duration":       {"zh": "最长 15m", "en": "Up to 15m"},
-	"placeholder.hardware_budget":    {"zh": "标准模式最长 2m", "en": "Up to 2m in standard mode"},
-	"placeholder.unlock_interface":   {"zh": "留空使用默认路由", "en": "Empty uses the default route"},
-	"placeholder.unlock_dns":         {"zh": "留空使用系统 DNS，多个用逗号分隔", "en": "Empty uses system DNS; comma-separated"},
-	"placeholder.unlock_http_proxy":  {"zh": "留空关闭", "en": "Empty disables"},
-	"placeholder.unlock_socks_proxy": {"zh": "留空关闭", "en": "Empty disables"},
-	"placeholder.unlock_concurrency": {"zh": "1-100，默认 20", "en": "1-100, default 20"},
-	"placeholder.log_viewer":         {"zh": "日志内容将在测试运行时显示...", "en": "Logs will appear while tests run..."},
-	"theme.light":                    {"zh": "浅色", "en": "Light"},
-	"theme.dark":                     {"zh": "深色", "en": "Dark"},
-	"progress.idle":                  {"zh": "等待开始", "en": "Waiting"},
-	"progress.precheck":              {"zh": "网络连通性检查", "en": "Network pre-check"},
-	"progress.basic_security":        {"zh": "基础信息与 IP 质量", "en": "Basic info and IP quality"},
-	"progress.cpu":                   {"zh": "CPU 性能测试", "en": "CPU benchmark"},
-	"progress.memory":                {"zh": "内存性能测试", "en": "Memory benchmark"},
-	"progress.disk":                  {"zh": "磁盘性能测试", "en": "Disk benchmark"},
-	"progress.deep_hardware":         {"zh": "深度硬件测试", "en": "Deep hardware tests"},
-	"progress.unlock":                {"zh": "流媒体解锁测试", "en": "Streaming unlock"},
-	"progress.ip_quality":            {"zh": "IP 质量结果整理", "en": "IP quality output"},
-	"progress.email":                 {"zh": "邮件端口检测", "en": "Email port check"},
-	"progress.backtrace":             {"zh": "上游及回程线路", "en": "Upstream and backtrace"},
-	"progress.nt3":                   {"zh": "三网回程路由", "en": "3-net route trace"},
-	"progress.ping":                  {"zh": "PING 延迟测试", "en": "Ping latency test"},
-	"progress.tgdc":                  {"zh": "Telegram DC 延迟测试", "en": "Telegram DC latency test"},
-	"progress.web":                   {"zh": "网站 TCP 延迟测试", "en": "Website TCP latency test"},
-	"progress.nat":                   {"zh": "NAT 行为测试", "en": "NAT behavior test"},
-	"progress.tcp":                   {"zh": "TCP 握手测试", "en": "TCP handshake test"},
-	"progress.speed":                 {"zh": "网络测速", "en": "Speed test"},
-	"progress.summary":               {"zh": "结果摘要", "en": "Result summary"},
-	"progress.upload":                {"zh": "结果上传与分享", "en": "Result upload and sharing"},
-	"progress.finish":                {"zh": "收尾处理", "en": "Finishing"},
-	"log.empty":                      {"zh": "暂无日志内容\n\n日志将在测试运行时自动更新。", "en": "No logs yet.\n\nLogs update automatically while tests run."},
-	"log.not_found":                  {"zh": "日志文件 ecs.log 不存在\n\n可能测试未生成日志文件，或文件已被删除。", "en": "Log file ecs.log not found.\n\nNo log generated yet or file was removed."},
-	"log.read_failed":                {"zh": "无法读取日志文件: ", "en": "Cannot read log file: "},
-	"log.interrupted":                {"zh": "\n\n========== 测试被用户中断 ==========\n", "en": "\n\n========== Interrupted by user ==========\n"},
-	"log.interrupted_short":          {"zh": "\n测试被用户中断\n", "en": "\nTest interrupted by user\n"},
-	"log.error_prefix":               {"zh": "\n错误: ", "en": "\nError: "},
-	"log.fatal_prefix":               {"zh": "\n严重错误: ", "en": "\nFatal error: "},
-	"error.cancelled":                {"zh": "测试已取消。", "en": "The test was cancelled."},
-	"error.permission":               {"zh": "权限不足，请以管理员/root 权限重新启动后再运行该测试。", "en": "Insufficient privileges. Restart as Administrator/root and try again."},
-	"error.network":                  {"zh": "网络连接不可用或目标服务暂时无法访问，请稍后重试。", "en": "Network is unavailable or the remote service cannot be reached. Please retry later."},
-	"error.timeout":                  {"zh": "测试等待超时，部分网络测试可能受线路或目标服务影响。", "en": "The test timed out. Some network checks may be affected by the route or remote service."},
-	"error.generic":                  {"zh": "测试未能完成，请查看上方输出和日志获取更多线索。", "en": "The test could not be completed. Check the output and logs for details."},
+	"placeholder.disk_path":           {"zh": "/tmp 或留空自动检测", "en": "/tmp or empty for auto"},
+	"placeholder.deep_disk_paths":     {"zh": "留空关闭；多个目录用逗号分隔", "en": "Empty disables; comma-separated directories"},
+	"placeholder.deep_smart_devices":  {"zh": "留空关闭；多个设备用逗号分隔", "en": "Empty disables; comma-separated devices"},
+	"placeholder.deep_burn_duration":  {"zh": "留空关闭，例如 30s 或 2m", "en": "Empty disables; for example 30s or 2m"},
+	"placeholder.deep_gpu_device":     {"zh": "留空关闭；填写 GPU 设备选择器", "en": "Empty disables; enter a GPU selector"},
+	"placeholder.sp_num":              {"zh": "每运营商测速节点数", "en": "Nodes per ISP"},
+	"placeholder.output_width":        {"zh": "建议 80-100", "en": "80-100 recommended"},
+	"placeholder.output_file":         {"zh": "goecs.md", "en": "goecs.md"},
+	"placeholder.json_path":           {"zh": "留空关闭，例如 goecs.json", "en": "Empty disables, e.g. goecs.json"},
+	"placeholder.max_duration":        {"zh": "最长 15m", "en": "Up to 15m"},
+	"placeholder.hardware_budget":     {"zh": "标准模式最长 2m", "en": "Up to 2m in standard mode"},
+	"placeholder.unlock_interface":    {"zh": "留空使用默认路由", "en": "Empty uses the default route"},
+	"placeholder.unlock_dns":          {"zh": "留空使用系统 DNS，多个用逗号分隔", "en": "Empty uses system DNS; comma-separated"},
+	"placeholder.unlock_http_proxy":   {"zh": "留空关闭", "en": "Empty disables"},
+	"placeholder.unlock_socks_proxy":  {"zh": "留空关闭", "en": "Empty disables"},
+	"placeholder.unlock_concurrency":  {"zh": "1-100，默认 20", "en": "1-100, default 20"},
+	"placeholder.remote_host":         {"zh": "IP 或域名", "en": "IP or hostname"},
+	"placeholder.remote_password":     {"zh": "使用私钥时可留空", "en": "Leave empty when using a key"},
+	"placeholder.remote_key":          {"zh": "例如 ~/.ssh/id_ed25519", "en": "e.g. ~/.ssh/id_ed25519"},
+	"placeholder.remote_passphrase":   {"zh": "私钥未加密时留空", "en": "Leave empty for unencrypted keys"},
+	"placeholder.remote_binary":       {"zh": "留空则自动获取对应架构的已校验发布包", "en": "Leave empty to fetch the verified release for the host architecture"},
+	"placeholder.docker_host":         {"zh": "留空使用本机 Docker，或 ssh://root@host、tcp://host:2376", "en": "Empty for local Docker, or ssh://root@host, tcp://host:2376"},
+	"placeholder.docker_container":    {"zh": "容器名称或 ID", "en": "Container name or ID"},
+	"placeholder.log_viewer":          {"zh": "日志内容将在测试运行时显示...", "en": "Logs will appear while tests run..."},
+	"theme.light":                     {"zh": "浅色", "en": "Light"},
+	"theme.system":                    {"zh": "跟随系统", "en": "System"},
+	"scheme.default":                  {"zh": "跟随主题", "en": "Follow theme"},
+	"scheme.solarized_dark":           {"zh": "Solarized 深色", "en": "Solarized Dark"},
+	"scheme.solarized_light":          {"zh": "Solarized 浅色", "en": "Solarized Light"},
+	"scheme.dracula":                  {"zh": "Dracula", "en": "Dracula"},
+	"scheme.green":                    {"zh": "经典黑底绿字", "en": "Classic green on black"},
+	"label.terminal_scheme":           {"zh": "终端配色", "en": "Terminal colors"},
+	"check.api":                       {"zh": "启用本地 HTTP API", "en": "Enable Local HTTP API"},
+	"api.settings":                    {"zh": "API 设置", "en": "API Settings"},
+	"api.title":                       {"zh": "本地 HTTP API", "en": "Local HTTP API"},
+	"api.address":                     {"zh": "监听地址", "en": "Listen Address"},
+	"api.token":                       {"zh": "访问令牌", "en": "Access Token"},
+	"api.regenerate":                  {"zh": "重新生成", "en": "Regenerate"},
+	"api.metrics":                     {"zh": "提供 Prometheus 指标 (GET /metrics)", "en": "Expose Prometheus metrics (GET /metrics)"},
+	"api.metrics_hint":                {"zh": "/metrics 按主机导出历史记录中最近一次完成的结果（CPU 得分、硬盘 IOPS、网速、IP 风险评分等），抓取时在 Prometheus 的 authorization 中填写令牌。", "en": "/metrics exports each host's latest completed run from history (CPU score, disk IOPS, network speed, IP risk scores and more); set the token as the scrape job's authorization credentials."},
+	"api.start_failed":                {"zh": "无法启动本地 API", "en": "Cannot start the local API"},
+	"api.hint":                        {"zh": "请求需携带 Authorization: Bearer <令牌>。接口：POST /runs 启动测试（可选 {\"tests\": [\"cpu\"]}），GET /runs/{id} 获取结果，GET /runs/{id}/log 获取输出（Accept: text/event-stream 时以 SSE 推送）。", "en": "Send Authorization: Bearer <token>. Endpoints: POST /runs starts a run (optional {\"tests\": [\"cpu\"]}), GET /runs/{id} returns results, GET /runs/{id}/log returns output (streamed as SSE with Accept: text/event-stream)."},
+	"schedule.title":                  {"zh": "定时任务", "en": "Schedules"},
+	"schedule.local":                  {"zh": "本机", "en": "This machine"},
+	"schedule.next":                   {"zh": "下次 %s", "en": "next %s"},
+	"schedule.disabled":               {"zh": "已停用", "en": "disabled"},
+	"schedule.spec":                   {"zh": "执行时间", "en": "Schedule"},
+	"schedule.spec_hint":              {"zh": "cron 格式：分 时 日 月 周，例如 0 3 * * 0 为每周日 03:00；也可使用 @daily、@weekly 或 @every 6h。应用未运行时错过的任务不会补跑。", "en": "Cron format: minute hour day month weekday, e.g. 0 3 * * 0 for Sundays at 03:00; @daily, @weekly and @every 6h also work. Runs missed while the app is closed are skipped."},
+	"schedule.target":                 {"zh": "目标主机", "en": "Target"},
+	"schedule.tests":                  {"zh": "测试项", "en": "Tests"},
+	"schedule.enabled":                {"zh": "启用", "en": "Enabled"},
+	"schedule.notify_regression":      {"zh": "结果比上一次下降超过 %.0f%% 时通知", "en": "Notify when results drop more than %.0f%% from the previous run"},
+	"schedule.sink":                   {"zh": "推送指标", "en": "Push metrics"},
+	"schedule.sink_off":               {"zh": "不推送", "en": "Off"},
+	"schedule.sink_hint":              {"zh": "完成后把结果推送到 InfluxDB（填写完整写入地址，v1 为 /write?db=库名）或 Pushgateway（填写服务地址，按 host 分组）。", "en": "After a successful run, push the results to InfluxDB (full write URL; v1 uses /write?db=name) or a Pushgateway (server URL; grouped by host)."},
+	"schedule.sink_token":             {"zh": "推送令牌", "en": "Push token"},
+	"schedule.sink_token_placeholder": {"zh": "可选，InfluxDB API Token 或 Bearer 令牌", "en": "Optional InfluxDB API token or bearer token"},
+	"schedule.sink_failed_title":      {"zh": "定时任务 %s：指标推送失败", "en": "Schedule %s: metrics push failed"},
+	"schedule.regression_title":       {"zh": "定时任务 %s：性能下降", "en": "Schedule %s: performance regression"},
+	"schedule.regression_body":        {"zh": "%d 项指标比上一次下降超过 %.0f%%，请在历史记录中对比。", "en": "%d metric(s) dropped more than %.0f%% since the previous run; compare them in History."},
+	"schedule.failed_title":           {"zh": "定时任务 %s 未能运行", "en": "Schedule %s could not run"},
+	"schedule.hosts_locked":           {"zh": "主机列表未解锁，请先在主机管理中输入主密码", "en": "Saved hosts are locked; unlock them in the host manager first"},
+	"schedule.open_failed":            {"zh": "无法读取定时任务", "en": "Cannot read scheduled jobs"},
+	"tray.quick_test":                 {"zh": "运行快速测试", "en": "Run quick test"},
+	"tray.show":                       {"zh": "显示窗口", "en": "Show window"},
+	"tray.last_result":                {"zh": "最近一次结果", "en": "Last result summary"},
+	"tray.no_result":                  {"zh": "暂无测试记录", "en": "No test runs yet"},
+	"tray.quit":                       {"zh": "退出", "en": "Quit"},
+	"notify.channels":                 {"zh": "推送渠道", "en": "Push channels"},
+	"notify.kind":                     {"zh": "类型", "en": "Type"},
+	"notify.kind.webhook":             {"zh": "通用 Webhook", "en": "Generic webhook"},
+	"notify.kind.telegram":            {"zh": "Telegram 机器人", "en": "Telegram bot"},
+	"notify.kind.discord":             {"zh": "Discord Webhook", "en": "Discord webhook"},
+	"notify.kind.serverchan":          {"zh": "Server酱", "en": "Server Chan"},
+	"notify.token":                    {"zh": "令牌 / SendKey", "en": "Token / SendKey"},
+	"notify.events":                   {"zh": "推送事件", "en": "Events"},
+	"notify.event.completed":          {"zh": "完成", "en": "Completed"},
+	"notify.event.failed":             {"zh": "失败", "en": "Failed"},
+	"notify.event.regression":         {"zh": "性能下降", "en": "Regression"},
+	"notify.regression_title":         {"zh": "%s：性能下降", "en": "%s: performance regression"},
+	"notify.push_failed":              {"zh": "\n推送通知失败：%v\n", "en": "\nFailed to push notification: %v\n"},
+	"notify.channel_test":             {"zh": "发送测试", "en": "Send test"},
+	"notify.channel_test_body":        {"zh": "这是一条测试消息。", "en": "This is a test message."},
+	"notify.channel_test_ok":          {"zh": "测试消息已发送。", "en": "Test message sent."},
+	"chart.speed.title":               {"zh": "实时测速", "en": "Live speed"},
+	"chart.speed.down":                {"zh": "↓下载", "en": "↓down"},
+	"chart.speed.up":                  {"zh": "↑上传", "en": "↑up"},
+	"chart.speed.peak":                {"zh": "峰值 %.0f Mbps", "en": "Peak %.0f Mbps"},
+	"trends.host":                     {"zh": "主机", "en": "Host"},
+	"trends.pick_host":                {"zh": "选择主机", "en": "Select a host"},
+	"trends.empty":                    {"zh": "该主机还没有可用于绘制趋势的完成记录。", "en": "No completed runs with metrics for this host yet."},
+	"trends.metric.cpu":               {"zh": "CPU 得分", "en": "CPU score"},
+	"trends.metric.disk_iops":         {"zh": "硬盘 IOPS（4K 总和）", "en": "Disk IOPS (4K total)"},
+	"trends.metric.download":          {"zh": "下载速度 (Mbps)", "en": "Download (Mbps)"},
+	"trends.metric.upload":            {"zh": "上传速度 (Mbps)", "en": "Upload (Mbps)"},
+	"ipq.title":                       {"zh": "IP 质量评级", "en": "IP quality grade"},
+	"ipq.subtitle":                    {"zh": "综合分按风险评分、黑名单记录与 DNS 黑名单计算，仅供参考", "en": "Combined from risk scores, blacklist records and DNS blacklists; for reference only"},
+	"ipq.copy":                        {"zh": "复制摘要", "en": "Copy summary"},
+	"ipq.usage":                       {"zh": "使用类型 %s", "en": "usage %s"},
+	"ipq.company":                     {"zh": "公司类型 %s", "en": "company %s"},
+	"ipq.type.native":                 {"zh": "原生 IP", "en": "native IP"},
+	"ipq.type.broadcast":              {"zh": "广播 IP", "en": "broadcast IP"},
+	"ipq.blacklist":                   {"zh": "黑名单：恶意 %d · 可疑 %d · 无害 %d · 无记录 %d；DNS 黑名单 %d/%d", "en": "Blacklists: malicious %d · suspicious %d · harmless %d · no record %d; DNSBL %d/%d"},
+	"ipq.summary_grade":               {"zh": "IP 质量：%s（%.0f/100）", "en": "IP quality: %s (%.0f/100)"},
+	"route.backtrace":                 {"zh": "三网回程线路", "en": "Carrier return routes"},
+	"route.paths":                     {"zh": "路由路径（连续同网络的跳已合并）", "en": "Route paths (consecutive hops in the same network merged)"},
+	"route.no_hops":                   {"zh": "没有可用的路由节点", "en": "No route hops"},
+	"route.tier.premium":              {"zh": "精品线路", "en": "Premium"},
+	"route.tier.quality":              {"zh": "优质线路", "en": "Quality"},
+	"route.tier.ordinary":             {"zh": "普通线路", "en": "Ordinary"},
+	"speed.nodes.title":               {"zh": "选择测速节点", "en": "Choose speedtest nodes"},
+	"speed.nodes.default":             {"zh": "默认节点（按预设）", "en": "Default (per preset)"},
+	"speed.nodes.summary":             {"zh": "%d 个分组 · %d 个服务器", "en": "%d groups · %d servers"},
+	"speed.nodes.groups":              {"zh": "节点分组（每组测试“测速节点数”个节点）", "en": "Node groups (each tests \"Speed Nodes\" servers)"},
+	"speed.nodes.search":              {"zh": "搜索 ID、名称、城市或提供商", "en": "Search ID, name, city or provider"},
+	"speed.nodes.all":                 {"zh": "全部", "en": "All"},
+	"speed.nodes.loading":             {"zh": "正在加载服务器列表…", "en": "Loading server list…"},
+	"speed.nodes.loaded":              {"zh": "共 %d 个服务器，勾选要测试的服务器", "en": "%d servers; tick the ones to test"},
+	"speed.nodes.manual":              {"zh": "其他服务器 ID，逗号分隔", "en": "Other server IDs, comma separated"},
+	"speed.nodes.hint":                {"zh": "不选择任何节点时按预设测速。仅本机运行生效，远程主机使用 goecs 默认节点。", "en": "With nothing selected the preset's nodes are used. Applies to local runs only; remote hosts use goecs defaults."},
+	"speed.group.nearby":              {"zh": "就近节点", "en": "Nearby"},
+	"speed.group.ct":                  {"zh": "电信", "en": "CN Telecom"},
+	"speed.group.cu":                  {"zh": "联通", "en": "CN Unicom"},
+	"speed.group.cmcc":                {"zh": "移动", "en": "CN Mobile"},
+	"speed.group.global":              {"zh": "全球", "en": "Global"},
+	"speed.group.hk":                  {"zh": "香港", "en": "Hong Kong"},
+	"speed.group.tw":                  {"zh": "台湾", "en": "Taiwan"},
+	"speed.group.jp":                  {"zh": "日本", "en": "Japan"},
+	"speed.group.sg":                  {"zh": "新加坡", "en": "Singapore"},
+	"speed.carrier.ct":                {"zh": "电信", "en": "Telecom"},
+	"speed.carrier.cu":                {"zh": "联通", "en": "Unicom"},
+	"speed.carrier.cmcc":              {"zh": "移动", "en": "Mobile"},
+	"speed.carrier.other":             {"zh": "其他运营商", "en": "Other carriers"},
+	"iperf.title":                     {"zh": "iperf3 服务器与隧道对比", "en": "iperf3 servers and tunnel comparison"},
+	"iperf.targets":                   {"zh": "服务器", "en": "Servers"},
+	"iperf.hint":                      {"zh": "每行一个：主机[:端口] [-t 秒] [-P 并发数] [-R 反向]，默认端口 5201、10 秒。需要本机安装 iperf3，仅本机运行生效。", "en": "One per line: host[:port] [-t seconds] [-P streams] [-R reverse]; defaults are port 5201 and 10 s. Needs a local iperf3 client; local runs only."},
+	"tunnel.interface":                {"zh": "隧道网卡", "en": "Tunnel interface"},
+	"tunnel.hint":                     {"zh": "网络测试后，TCP 延迟、HTTP 下载与 iperf3 目标会经默认路由和所选网卡（如 WireGuard 的 wg0）各测一次并并排显示。仅本机运行生效。", "en": "After the network stages, TCP latency, an HTTP download and the iperf3 targets are measured over the default route and over the selected interface (e.g. WireGuard's wg0) and shown side by side. Local runs only."},
+	"tunnel.off":                      {"zh": "关闭", "en": "Off"},
+	"iperf.button":                    {"zh": "iperf3 (%d)", "en": "iperf3 (%d)"},
+	"network_limit.button":            {"zh": "网络强度：%s", "en": "Network load: %s"},
+	"network_limit.title":             {"zh": "网络测试强度", "en": "Network test load"},
+	"network_limit.load":              {"zh": "强度", "en": "Load"},
+	"network_limit.full":              {"zh": "完整", "en": "Full"},
+	"network_limit.light":             {"zh": "轻量", "en": "Light"},
+	"network_limit.cap":               {"zh": "带宽上限 (Mbps)", "en": "Bandwidth cap (Mbps)"},
+	"network_limit.unlimited":         {"zh": "不限制", "en": "Unlimited"},
+	"network_limit.hint":              {"zh": "轻量：测速只测一个节点，iperf3 单流且最多 5 秒。带宽上限按 iperf3 -b 限速；测速节点无法限速，设置上限后同样只测一个节点。适合按流量计费或正在提供服务的机器。", "en": "Light tests one speedtest node and runs iperf3 with one stream for at most 5 s. The cap is applied to iperf3 with -b; speedtest nodes cannot be throttled, so a cap also limits the speed test to one node. Use it on metered or production servers."},
+	"stage_timeout.title":             {"zh": "阶段超时与重试", "en": "Stage timeouts and retries"},
+	"stage_timeout.none":              {"zh": "不限时", "en": "No limit"},
+	"stage_retry.count":               {"zh": "失败重试次数", "en": "Retries on failure"},
+	"stage_retry.backoff":             {"zh": "首次重试等待", "en": "First retry delay"},
+	"stage_timeout.hint":              {"zh": "如 90s、5m。阶段超过时限后会被结束并在结果中标记为已超时，测试继续下一阶段；留空表示不限时（流媒体解锁与邮件端口检测默认 5m 与 3m）。测速没有结果或流媒体解锁全部检测失败时按重试次数自动重试，每次等待时间加倍，最多 5 次。仅本机运行生效。", "en": "E.g. 90s or 5m. A stage that runs past its limit is stopped, marked as timed out in the results, and the run moves on to the next stage. Leave empty for no limit (streaming unlock and email ports default to 5m and 3m). A speed test without results or an unlock check where every platform failed is retried up to the set number of times (at most 5), doubling the delay each time. Local runs only."},
+	"tab.latency":                     {"zh": "延迟", "en": "Latency"},
+	"latency.placeholder":             {"zh": "每行或用逗号分隔一个目标，如：\n1.1.1.1\nexample.com:443（带端口时测 TCP 连接延迟）", "en": "One target per line or comma separated, e.g.:\n1.1.1.1\nexample.com:443 (with a port the TCP connect time is measured)"},
+	"latency.hint":                    {"zh": "不带端口的目标使用系统 ping（ICMP），带端口的目标测量 TCP 连接耗时。点击表头排序，导出时结果会合并到当前报告。", "en": "Targets without a port use the system ping (ICMP); targets with a port measure the TCP connect time. Click a column header to sort; exports include these results along with the current report."},
+	"latency.count":                   {"zh": "每个目标探测次数", "en": "Probes per target"},
+	"latency.run":                     {"zh": "开始探测", "en": "Run"},
+	"latency.stop":                    {"zh": "停止", "en": "Stop"},
+	"latency.no_targets":              {"zh": "请先输入至少一个目标", "en": "Enter at least one target first"},
+	"latency.bad_count":               {"zh": "探测次数必须在 1-%d 之间", "en": "Probe count must be between 1 and %d"},
+	"latency.progress":                {"zh": "已完成 %d/%d", "en": "Finished %d/%d"},
+	"latency.stopped":                 {"zh": "已停止，完成 %d/%d", "en": "Stopped after %d/%d"},
+	"latency.col.target":              {"zh": "目标", "en": "Target"},
+	"latency.col.protocol":            {"zh": "协议", "en": "Protocol"},
+	"latency.col.min":                 {"zh": "最小 (ms)", "en": "Min (ms)"},
+	"latency.col.avg":                 {"zh": "平均 (ms)", "en": "Avg (ms)"},
+	"latency.col.max":                 {"zh": "最大 (ms)", "en": "Max (ms)"},
+	"latency.col.loss":                {"zh": "丢包", "en": "Loss"},
+	"tab.ports":                       {"zh": "端口", "en": "Ports"},
+	"ports.host":                      {"zh": "目标", "en": "Target"},
+	"ports.list":                      {"zh": "端口", "en": "Ports"},
+	"ports.host_placeholder":          {"zh": "IP 或域名，如 203.0.113.10", "en": "IP or host name, e.g. 203.0.113.10"},
+	"ports.list_placeholder":          {"zh": "留空检测 22,80,443,3389；可写 25,8000-8010", "en": "Empty checks 22,80,443,3389; e.g. 25,8000-8010"},
+	"ports.hint":                      {"zh": "从本机连接目标的各个 TCP 端口并 ping 目标：拒绝连接说明端口可达但没有服务，超时通常说明端口被防火墙或服务商过滤。导出时结果会合并到当前报告。", "en": "Connects to each TCP port of the target from this machine and pings it. A refused connection means the port is reachable but nothing listens; a timeout usually means a firewall or the provider filters the port. Exports include these results along with the current report."},
+	"ports.col.port":                  {"zh": "端口", "en": "Port"},
+	"ports.col.service":               {"zh": "服务", "en": "Service"},
+	"ports.col.status":                {"zh": "状态", "en": "Status"},
+	"ports.col.time":                  {"zh": "耗时 (ms)", "en": "Time (ms)"},
+	"ports.status.open":               {"zh": "开放", "en": "Open"},
+	"ports.status.closed":             {"zh": "可达，无服务（拒绝连接）", "en": "Reachable, nothing listening (refused)"},
+	"ports.status.filtered":           {"zh": "被过滤（连接超时）", "en": "Filtered (timed out)"},
+	"ports.icmp_reachable":            {"zh": "可达（丢包 %s%%）", "en": "Reachable (%s%% loss)"},
+	"ports.icmp_unreachable":          {"zh": "不可达或屏蔽了 ICMP", "en": "Unreachable or ICMP blocked"},
+	"ports.no_host":                   {"zh": "请先输入目标", "en": "Enter a target first"},
+	"ports.host_with_port":            {"zh": "目标 %q 不应带端口，请在端口一栏填写", "en": "Target %q should not include a port; list ports in the Ports field"},
+	"ports.summary_ok":                {"zh": "检测完成，没有发现被过滤的端口", "en": "Done, no filtered ports found"},
+	"ports.summary_blocked":           {"zh": "检测完成，%d 个端口被过滤：%s", "en": "Done, %d port(s) filtered: %s"},
+	"tab.monitor":                     {"zh": "路由监测", "en": "Route monitor"},
+	"monitor.host":                    {"zh": "目标", "en": "Target"},
+	"monitor.minutes":                 {"zh": "时长（分钟）", "en": "Minutes"},
+	"monitor.host_placeholder":        {"zh": "IP 或域名，如 203.0.113.10", "en": "IP or host name, e.g. 203.0.113.10"},
+	"monitor.hint":                    {"zh": "先逐跳发现到目标的路径，再在设定的时长内每秒 ping 每一跳，持续累计丢包与延迟，用于排查晚高峰等时段的间歇丢包。中间跳常对 ICMP 限速，只有丢包延续到后面各跳时才说明线路有问题。导出时结果会合并到当前报告。", "en": "Discovers the path to the target hop by hop, then pings every hop once per second for the chosen duration and keeps accumulating loss and latency, to catch intermittent loss (e.g. at peak hours) that a one-shot trace misses. Intermediate routers often rate-limit ICMP; loss only matters when it carries on to the following hops. Exports include these results along with the current report."},
+	"monitor.no_host":                 {"zh": "请先输入目标", "en": "Enter a target first"},
+	"monitor.bad_minutes":             {"zh": "时长必须在 1-%d 分钟之间", "en": "Duration must be between 1 and %d minutes"},
+	"monitor.discovering":             {"zh": "正在发现路径…", "en": "Discovering the path…"},
+	"monitor.no_route":                {"zh": "没有任何一跳回应探测，目标可能不可达或屏蔽了 ICMP", "en": "No hop replied; the target may be unreachable or block ICMP"},
+	"monitor.progress":                {"zh": "已完成 %d 轮，剩余 %s", "en": "%d rounds done, %s left"},
+	"monitor.finished":                {"zh": "监测结束，共 %d 轮", "en": "Finished after %d rounds"},
+	"monitor.stopped":                 {"zh": "已停止，共 %d 轮", "en": "Stopped after %d rounds"},
+	"monitor.col.hop":                 {"zh": "跳", "en": "Hop"},
+	"monitor.col.host":                {"zh": "地址", "en": "Host"},
+	"monitor.col.loss":                {"zh": "丢包", "en": "Loss"},
+	"monitor.col.sent":                {"zh": "发送", "en": "Sent"},
+	"monitor.col.last":                {"zh": "最近 (ms)", "en": "Last (ms)"},
+	"monitor.col.avg":                 {"zh": "平均 (ms)", "en": "Avg (ms)"},
+	"monitor.col.best":                {"zh": "最好 (ms)", "en": "Best (ms)"},
+	"monitor.col.worst":               {"zh": "最差 (ms)", "en": "Worst (ms)"},
+	"label.disk_fio":                  {"zh": "fio 块大小/文件大小", "en": "fio Blocks/File Size"},
+	"placeholder.disk_file_size":      {"zh": "自动", "en": "auto"},
+	"check.disk_safe_mode":            {"zh": "安全模式（磁盘将满时不写入）", "en": "Safe Mode (skip nearly-full disks)"},
+	"dialog.disk_full_title":          {"zh": "磁盘空间不足", "en": "Disk Nearly Full"},
+	"dialog.disk_full_body":           {"zh": "硬盘测试会在测试目录写入测试文件，但该磁盘几乎已满：\n%v\n\n仍要写入并继续测试吗？", "en": "The disk test writes a test file to the test path, but that disk is nearly full:\n%v\n\nWrite anyway and continue?"},
+	"dialog.geekbench_license_title":  {"zh": "Geekbench 许可协议", "en": "Geekbench License"},
+	"dialog.geekbench_license_body":   {"zh": "Geekbench 由 Primate Labs 提供。Tryout 版本会把测试结果上传到 Geekbench Browser 并公开展示，结果页中包含 CPU 型号、内存与系统信息。\n\n继续即表示你接受 Primate Labs 最终用户许可协议。", "en": "Geekbench is provided by Primate Labs. The Tryout edition uploads results to the Geekbench Browser where they are publicly visible, including the CPU model, memory and system information.\n\nBy continuing you accept the Primate Labs End User License Agreement."},
+	"dialog.geekbench_accept":         {"zh": "接受并继续", "en": "Accept and continue"},
+	"dialog.geekbench_decline":        {"zh": "不接受", "en": "Decline"},
+	"results.geekbench_link":          {"zh": "Geekbench 结果页", "en": "Geekbench result"},
+	"results.geekbench_claim":         {"zh": "认领到我的账号", "en": "Claim to my account"},
+	"sysinfo.title":                   {"zh": "目标系统", "en": "Target System"},
+	"sysinfo.local":                   {"zh": "本机", "en": "Local machine"},
+	"sysinfo.remote_pending":          {"zh": "远程目标：点击刷新通过 SSH 采集", "en": "Remote target: click Refresh to collect over SSH"},
+	"sysinfo.refresh":                 {"zh": "刷新", "en": "Refresh"},
+	"sysinfo.collecting":              {"zh": "正在采集…", "en": "Collecting…"},
+	"sysinfo.failed":                  {"zh": "采集失败：", "en": "Collection failed:"},
+	"sysinfo.updated":                 {"zh": "更新于 %s", "en": "Updated at %s"},
+	"sysinfo.host":                    {"zh": "主机名", "en": "Hostname"},
+	"sysinfo.cpu":                     {"zh": "CPU 型号", "en": "CPU model"},
+	"sysinfo.cores":                   {"zh": "CPU 核心数", "en": "CPU cores"},
+	"sysinfo.memory":                  {"zh": "内存", "en": "Memory"},
+	"sysinfo.disk":                    {"zh": "系统盘（已用/总量）", "en": "System disk (used/total)"},
+	"sysinfo.virt":                    {"zh": "虚拟化", "en": "Virtualization"},
+	"sysinfo.os":                      {"zh": "操作系统", "en": "OS"},
+	"sysinfo.kernel":                  {"zh": "内核", "en": "Kernel"},
+	"sysinfo.tcp_cc":                  {"zh": "TCP 拥塞控制", "en": "TCP congestion control"},
+	"sysinfo.qdisc":                   {"zh": "队列调度", "en": "Queue discipline"},
+	"bbr.enable":                      {"zh": "启用 BBR…", "en": "Enable BBR…"},
+	"bbr.title":                       {"zh": "启用 BBR", "en": "Enable BBR"},
+	"bbr.confirm":                     {"zh": "将以 root 身份在 %s@%s 上执行以下修改（当前：%s）：\n\n• 加载 tcp_bbr 内核模块并设为开机加载\n• 写入 %s，把默认队列调度设为 fq、TCP 拥塞控制设为 bbr\n• 用 sysctl 立即生效\n\n修改会影响该主机上的所有 TCP 连接，删除上述文件并重启即可还原。是否继续？", "en": "The following changes will be made as root on %s@%s (currently: %s):\n\n• load the tcp_bbr kernel module and load it at boot\n• write %s to set the default qdisc to fq and TCP congestion control to bbr\n• apply it immediately with sysctl\n\nThis affects every TCP connection on the host; delete that file and reboot to revert. Continue?"},
+	"bbr.running":                     {"zh": "正在启用 BBR…", "en": "Enabling BBR…"},
+	"bbr.failed":                      {"zh": "启用 BBR 失败：", "en": "Enabling BBR failed:"},
+	"bbr.done":                        {"zh": "已启用 BBR：%s → %s\n配置已写入 %s，修改已记录到程序日志。", "en": "BBR enabled: %s → %s\nThe settings were written to %s and the change was recorded in the app log."},
+	"button.cancel":                   {"zh": "取消", "en": "Cancel"},
+	"button.relaunch_elevated":        {"zh": "以管理员身份重新启动", "en": "Relaunch as administrator"},
+	"button.skip_privileged":          {"zh": "去掉这些测试继续", "en": "Continue without them"},
+	"ecs.title":                       {"zh": "goecs 版本", "en": "goecs Versions"},
+	"ecs.hint":                        {"zh": "未指定本地 goecs 时，远程测试会在本机下载所选版本、校验 SHA-256 并缓存后上传到目标；本机无法下载时改由远程主机下载。", "en": "When no local goecs is set, remote runs download the selected release on this computer, verify its SHA-256, cache it and upload it to the target; if that fails the remote host downloads it instead."},
+	"ecs.platform":                    {"zh": "平台", "en": "Platform"},
+	"ecs.current_default":             {"zh": "远程测试使用 goecs v%s（内置版本）", "en": "Remote runs use goecs v%s (built-in version)"},
+	"ecs.current_pinned":              {"zh": "远程测试固定使用 goecs v%s", "en": "Remote runs are pinned to goecs v%s"},
+	"ecs.default":                     {"zh": "内置", "en": "built-in"},
+	"ecs.in_use":                      {"zh": "使用中", "en": "in use"},
+	"ecs.cached":                      {"zh": "已缓存", "en": "cached"},
+	"ecs.cached_other":                {"zh": "已缓存 %d 个其他平台", "en": "cached for %d other platform(s)"},
+	"ecs.loading":                     {"zh": "正在获取发布列表…", "en": "Fetching releases…"},
+	"ecs.list_failed":                 {"zh": "获取发布列表失败，仅显示已缓存的版本：", "en": "Could not fetch releases, showing cached versions only:"},
+	"ecs.select_first":                {"zh": "请先选择一个版本", "en": "Select a version first"},
+	"ecs.download":                    {"zh": "下载", "en": "Download"},
+	"ecs.use":                         {"zh": "使用此版本", "en": "Use This Version"},
+	"ecs.reset":                       {"zh": "恢复内置版本", "en": "Use Built-in Version"},
+	"ecs.downloading":                 {"zh": "正在下载 v%s %s…", "en": "Downloading v%s %s…"},
+	"ecs.downloaded":                  {"zh": "已校验并缓存：%s", "en": "Verified and cached: %s"},
+	"setup.title":                     {"zh": "新手向导", "en": "Setup Wizard"},
+	"setup.skip":                      {"zh": "跳过", "en": "Skip"},
+	"setup.back":                      {"zh": "上一步", "en": "Back"},
+	"setup.next":                      {"zh": "下一步", "en": "Next"},
+	"setup.finish":                    {"zh": "完成", "en": "Finish"},
+	"setup.language":                  {"zh": "界面语言", "en": "Language"},
+	"setup.language.hint":             {"zh": "选择界面与测试输出的语言，自动表示按系统区域设置选择。", "en": "Choose the language of the interface and test output; Auto follows the system locale."},
+	"setup.backend":                   {"zh": "goecs 程序", "en": "goecs backend"},
+	"setup.backend.hint":              {"zh": "本机测试使用内置的测试引擎，无需下载。测试远程主机时需要 goecs：可以指定本地已有的 goecs，或在版本管理中预先下载；留空时在首次远程测试时自动下载并校验。", "en": "Local tests use the built-in engine and need no download. Remote hosts need goecs: point to a goecs you already have, or download one in the version manager; leave it empty to download and verify it on the first remote run."},
+	"setup.tests":                     {"zh": "默认测试项", "en": "Default tests"},
+	"setup.tests.hint":                {"zh": "选择每次打开时默认勾选的测试，之后可以在配置页随时修改。没有公网的机器请开启离线/内网模式。", "en": "Choose the tests selected by default; you can change them on the config page at any time. Turn on offline/intranet mode on machines without internet access."},
+	"setup.privacy":                   {"zh": "隐私", "en": "Privacy"},
+	"setup.privacy.hint":              {"zh": "上传结果会把报告发送到公共分享服务并生成链接，默认关闭。隐私模式会在终端与所有导出中隐去公网 IP、主机名与 ASN。", "en": "Uploading sends the report to a public sharing service and returns a link; it is off by default. Privacy mode hides public IPs, host names and ASNs in the terminal and in every export."},
+	"setup.smoke":                     {"zh": "试运行", "en": "Smoke test"},
+	"setup.smoke.hint":                {"zh": "完成后可以在新标签页中运行一次快速测试（基础信息与 CPU，约一分钟、不上传），确认本机环境可用。", "en": "After finishing, a quick test (system info and CPU, about a minute, never uploaded) can run in a new tab to check that this machine is ready."},
+	"setup.smoke.run":                 {"zh": "完成后运行快速测试", "en": "Run a quick test when done"},
+	"ecs.download_failed":             {"zh": "下载失败：", "en": "Download failed:"},
+	"check.update":                    {"zh": "启动时检查更新", "en": "Check for updates at startup"},
+	"update.check_now":                {"zh": "检查更新", "en": "Check Now"},
+	"update.title":                    {"zh": "软件更新", "en": "Software Update"},
+	"update.available":                {"zh": "发现新版本 v%s（当前 v%s）", "en": "Version v%s is available (current v%s)"},
+	"update.latest":                   {"zh": "已是最新版本 v%s", "en": "You are running the latest version v%s"},
+	"update.check_failed":             {"zh": "检查更新失败", "en": "Update check failed"},
+	"update.skip":                     {"zh": "跳过此版本", "en": "Skip This Version"},
+	"update.later":                    {"zh": "稍后", "en": "Later"},
+	"update.install":                  {"zh": "下载并在退出后安装", "en": "Download and Install on Quit"},
+	"update.open_page":                {"zh": "打开发布页面", "en": "Open Release Page"},
+	"update.downloading":              {"zh": "正在下载并校验 v%s…", "en": "Downloading and verifying v%s…"},
+	"update.staged":                   {"zh": "v%s 已下载并校验，将在退出程序时安装，下次启动即为新版本。", "en": "v%s has been downloaded and verified. It will be installed when you quit and used from the next launch."},
+	"update.download_failed":          {"zh": "下载更新失败", "en": "Update download failed"},
+	"update.apply_failed":             {"zh": "上次退出时安装 v%s 失败：%s\n已下载的文件：%s\n\n是否删除该更新？选择否将在下次退出时重试。", "en": "Installing v%s on quit failed: %s\nDownloaded file: %s\n\nDelete this update? Choose No to retry on the next quit."},
+	"proxy.settings":                  {"zh": "网络代理", "en": "Proxy"},
+	"proxy.global":                    {"zh": "全局代理", "en": "Global proxy"},
+	"proxy.component.downloads":       {"zh": "发布包下载", "en": "Backend downloads"},
+	"proxy.component.uploads":         {"zh": "结果上传", "en": "Result uploads"},
+	"proxy.component.notifications":   {"zh": "消息推送", "en": "Notifications"},
+	"proxy.mode.inherit":              {"zh": "跟随全局", "en": "Use global"},
+	"proxy.mode.direct":               {"zh": "直连", "en": "Direct"},
+	"proxy.mode.custom":               {"zh": "单独指定", "en": "Custom"},
+	"proxy.hint":                      {"zh": "支持 http://、https:// 与 socks5:// 地址，可带用户名密码。全局代理留空时使用系统环境变量（HTTP_PROXY 等）。仅作用于界面发起的请求，不影响测试流量与远程主机。", "en": "Supports http://, https:// and socks5:// addresses with optional credentials. An empty global proxy falls back to the HTTP_PROXY environment variables. Only requests made by the GUI itself are proxied; test traffic and remote hosts are not affected."},
+	"placeholder.proxy":               {"zh": "例如 socks5://127.0.0.1:1080", "en": "e.g. socks5://127.0.0.1:1080"},
+	"mirror.title":                    {"zh": "下载镜像", "en": "Mirrors"},
+	"mirror.hint":                     {"zh": "goecs 发布包与界面更新从 GitHub 下载，国内网络可改用镜像。下载内容仍按 GitHub 发布的 SHA-256 校验，镜像失败时自动回退直连。", "en": "goecs releases and GUI updates are downloaded from GitHub; use a mirror where GitHub is slow or blocked. Downloads are still verified against the SHA-256 published on GitHub, and a failing mirror falls back to GitHub directly."},
+	"mirror.mode":                     {"zh": "下载源", "en": "Source"},
+	"mirror.direct":                   {"zh": "直连 GitHub", "en": "GitHub (direct)"},
+	"mirror.auto":                     {"zh": "自动选择（延迟最低）", "en": "Automatic (lowest latency)"},
+	"mirror.custom":                   {"zh": "自定义", "en": "custom"},
+	"mirror.probe":                    {"zh": "测速", "en": "Test Latency"},
+	"mirror.probing":                  {"zh": "正在测速...", "en": "Testing mirrors..."},
+	"mirror.fastest":                  {"zh": "最快的镜像：%s", "en": "Fastest mirror: %s"},
+	"mirror.none_reachable":           {"zh": "没有可用的镜像", "en": "No mirror is reachable"},
+	"mirror.unreachable":              {"zh": "不可用", "en": "unreachable"},
+	"mirror.select_custom":            {"zh": "请先选择一个自定义镜像，内置镜像不可删除", "en": "Select a custom mirror first; built-in mirrors cannot be removed"},
+	"share.service":                   {"zh": "分享服务", "en": "Service"},
+	"share.service.ecs":               {"zh": "goecs 结果分享（限 25KB）", "en": "goecs paste (25KB limit)"},
+	"share.service.generic":           {"zh": "通用 Pastebin 接口", "en": "Generic pastebin API"},
+	"share.field":                     {"zh": "表单字段", "en": "Form field"},
+	"share.format":                    {"zh": "格式", "en": "Format"},
+	"share.format.text":               {"zh": "文本", "en": "Text"},
+	"share.format.markdown":           {"zh": "Markdown 表格", "en": "Markdown tables"},
+	"share.anonymize":                 {"zh": "分享前隐去公网 IP（如 1.2.3.*）", "en": "Mask public IPs before sharing (e.g. 1.2.3.*)"},
+	"placeholder.share_field":         {"zh": "留空则以纯文本作为请求体", "en": "Leave empty to POST the text as the request body"},
+	"privacy.mode":                    {"zh": "隐私模式", "en": "Privacy mode"},
+	"image.source":                    {"zh": "内容", "en": "Content"},
+	"image.source.terminal":           {"zh": "终端输出", "en": "Terminal output"},
+	"image.source.structured":         {"zh": "结构化结果", "en": "Structured results"},
+	"image.redact":                    {"zh": "隐去公网 IP、主机名与 ASN 组织名", "en": "Mask public IPs, hostnames and ASN organizations"},
+	"run_tabs.main":                   {"zh": "当前运行", "en": "Current run"},
+	"split.vertical":                  {"zh": "上下分栏", "en": "Stack vertically"},
+	"split.horizontal":                {"zh": "左右分栏", "en": "Side by side"},
+	"popout.terminal":                 {"zh": "终端", "en": "Terminal"},
+	"popout.results":                  {"zh": "结果面板", "en": "Results panel"},
+	"popout.dock":                     {"zh": "收回到主窗口", "en": "Dock"},
+	"popout.placeholder":              {"zh": "该面板已弹出到独立窗口", "en": "This panel is open in a separate window"},
+	"run_tabs.menu":                   {"zh": "运行标签页", "en": "Run tabs"},
+	"run_tabs.new":                    {"zh": "在新标签页中运行", "en": "Run in new tab"},
+	"run_tabs.title":                  {"zh": "运行 %d · %s", "en": "Run %d · %s"},
+	"run_tabs.close_finished":         {"zh": "关闭已结束的标签页", "en": "Close finished tabs"},
+	"run_tabs.local_busy":             {"zh": "本机已有测试在运行，多个本机测试会互相干扰结果。请等待其结束，或在新标签页中测试远程主机。", "en": "A local test is already running; concurrent local tests would skew each other's results. Wait for it to finish, or run a remote host in a new tab."},
+	"log_drop.unsupported":            {"zh": "%s：只能打开 .log 或 .txt 格式的 ecs 输出", "en": "%s: only .log or .txt ecs output files can be opened"},
+	"queue.title":                     {"zh": "运行队列", "en": "Run queue"},
+	"queue.add":                       {"zh": "加入队列", "en": "Add to queue"},
+	"queue.current_target":            {"zh": "当前目标（%s）", "en": "Current target (%s)"},
+	"queue.preset":                    {"zh": "预设：%s（使用配置页的当前参数）", "en": "Preset: %s (uses the current options)"},
+	"queue.unlock_hosts":              {"zh": "解锁已保存的主机", "en": "Unlock saved hosts"},
+	"queue.concurrency":               {"zh": "同时运行", "en": "Concurrent"},
+	"queue.clear_finished":            {"zh": "清除已结束", "en": "Clear finished"},
+	"queue.summary":                   {"zh": "等待 %d · 运行中 %d · 已结束 %d", "en": "%d pending · %d running · %d finished"},
+	"queue.pending":                   {"zh": "等待中", "en": "Pending"},
+	"queue.cancelled":                 {"zh": "已取消", "en": "Cancelled"},
+	"settings.import":                 {"zh": "导入设置", "en": "Import Settings"},
+	"settings.export":                 {"zh": "导出设置", "en": "Export Settings"},
+	"settings.export_hosts":           {"zh": "是否同时导出已保存的 SSH 主机？需要输入主密码，导出文件不包含密码。", "en": "Also export saved SSH hosts? This needs the master password; passwords are not included in the file."},
+	"settings.invalid":                {"zh": "无法读取设置文件", "en": "Cannot read the settings file"},
+	"settings.imported":               {"zh": "设置已导入。", "en": "Settings imported."},
+	"settings.imported_hosts":         {"zh": "设置已导入，导入了 %d 台主机。", "en": "Settings imported with %d host(s)."},
+	"settings.hosts_skipped":          {"zh": "%d 台主机缺少密码或私钥，已跳过，请在主机管理中重新添加。", "en": "%d host(s) were skipped because they have no password or key; add them again in the host manager."},
+	"config.appearance.title":         {"zh": "外观", "en": "Appearance"},
+	"label.terminal_font":             {"zh": "终端字体", "en": "Terminal font"},
+	"label.terminal_font_size":        {"zh": "终端字号", "en": "Font size"},
+	"font.builtin":                    {"zh": "内置等宽字体", "en": "Built-in monospace"},
+	"font.choose":                     {"zh": "选择字体文件…", "en": "Choose font file…"},
+	"hooks.prefix":                    {"zh": "[钩子] ", "en": "[hook] "},
+	"hooks.load_failed":               {"zh": "[钩子] 部分脚本加载失败：%v", "en": "[hook] Some scripts failed to load: %v"},
+	"hooks.rejected":                  {"zh": "[钩子] 运行被脚本拒绝：%v", "en": "[hook] Run rejected by script: %v"},
+	"hooks.policy_failed":             {"zh": "[钩子] 结果未通过脚本策略：%v", "en": "[hook] Results failed the script policy: %v"},
+	"session.title":                   {"zh": "恢复上次测试", "en": "Restore Last Session"},
+	"session.body":                    {"zh": "上次测试未正常结束，已记录的日志已存入历史记录。\n\n主机：%s\n开始时间：%s\n已完成：%s\n未完成：%s", "en": "The last run did not finish cleanly; the journaled log has been saved to history.\n\nHost: %s\nStarted: %s\nCompleted: %s\nRemaining: %s"},
+	"session.none":                    {"zh": "无", "en": "none"},
+	"session.dismiss":                 {"zh": "忽略", "en": "Dismiss"},
+	"session.open_log":                {"zh": "查看日志", "en": "Open log"},
+	"session.rerun":                   {"zh": "重跑剩余项目", "en": "Rerun remaining"},
+	"debug_log.title":                 {"zh": "调试日志", "en": "Debug Log"},
+	"debug_log.level":                 {"zh": "记录级别", "en": "Level"},
+	"debug_log.refresh":               {"zh": "刷新", "en": "Refresh"},
+	"debug_log.memory_only":           {"zh": "日志文件不可用，仅保留在内存中", "en": "Log file unavailable; keeping records in memory only"},
+	"shortcuts.action.debug_log":      {"zh": "调试日志", "en": "Debug log"},
+	"shortcuts.title":                 {"zh": "快捷键", "en": "Shortcuts"},
+	"shortcuts.reset":                 {"zh": "恢复默认", "en": "Reset"},
+	"shortcuts.hint":                  {"zh": "格式如 Ctrl+Shift+R，Ctrl 在 macOS 上对应 Cmd；留空表示不绑定。", "en": "Use the form Ctrl+Shift+R; Ctrl maps to Cmd on macOS. Leave empty to unbind."},
+	"shortcuts.action.next_bookmark":  {"zh": "下一个书签", "en": "Next bookmark"},
+	"shortcuts.action.prev_bookmark":  {"zh": "上一个书签", "en": "Previous bookmark"},
+	"shortcuts.action.run":            {"zh": "开始测试", "en": "Run"},
+	"shortcuts.action.stop":           {"zh": "停止测试", "en": "Stop"},
+	"shortcuts.action.clear":          {"zh": "清空终端", "en": "Clear terminal"},
+	"shortcuts.action.search":         {"zh": "搜索输出", "en": "Search output"},
+	"shortcuts.action.save_log":       {"zh": "保存日志", "en": "Save log"},
+	"shortcuts.action.next_tab":       {"zh": "切换运行标签页", "en": "Next run tab"},
+	"font.size_auto":                  {"zh": "默认字号", "en": "Default size"},
+	"config.appearance.sub":           {"zh": "主题与终端配色", "en": "Theme and terminal colors"},
+	"theme.dark":                      {"zh": "深色", "en": "Dark"},
+	"progress.idle":                   {"zh": "等待开始", "en": "Waiting"},
+	"progress.precheck":               {"zh": "网络连通性检查", "en": "Network pre-check"},
+	"progress.basic_security":         {"zh": "基础信息与 IP 质量", "en": "Basic info and IP quality"},
+	"progress.cpu":                    {"zh": "CPU 性能测试", "en": "CPU benchmark"},
+	"progress.memory":                 {"zh": "内存性能测试", "en": "Memory benchmark"},
+	"progress.disk":                   {"zh": "磁盘性能测试", "en": "Disk benchmark"},
+	"progress.deep_hardware":          {"zh": "深度硬件测试", "en": "Deep hardware tests"},
+	"progress.unlock":                 {"zh": "流媒体解锁测试", "en": "Streaming unlock"},
+	"progress.ip_quality":             {"zh": "IP 质量结果整理", "en": "IP quality output"},
+	"progress.email":                  {"zh": "邮件端口检测", "en": "Email port check"},
+	"progress.backtrace":              {"zh": "上游及回程线路", "en": "Upstream and backtrace"},
+	"progress.nt3":                    {"zh": "三网回程路由", "en": "3-net route trace"},
+	"progress.ping":                   {"zh": "PING 延迟测试", "en": "Ping latency test"},
+	"progress.tgdc":                   {"zh": "Telegram DC 延迟测试", "en": "Telegram DC latency test"},
+	"progress.web":                    {"zh": "网站 TCP 延迟测试", "en": "Website TCP latency test"},
+	"progress.nat":                    {"zh": "NAT 行为测试", "en": "NAT behavior test"},
+	"progress.tcp":                    {"zh": "TCP 握手测试", "en": "TCP handshake test"},
+	"progress.speed":                  {"zh": "网络测速", "en": "Speed test"},
+	"progress.tunnel":                 {"zh": "隧道对比", "en": "Tunnel comparison"},
+	"progress.iperf3":                 {"zh": "iperf3 测试", "en": "iperf3 test"},
+	"progress.gpu":                    {"zh": "GPU 信息", "en": "GPU info"},
+	"progress.summary":                {"zh": "结果摘要", "en": "Result summary"},
+	"progress.upload":                 {"zh": "结果上传与分享", "en": "Result upload and sharing"},
+	"progress.finish":                 {"zh": "收尾处理", "en": "Finishing"},
+	"progress.remote_connect":         {"zh": "连接远程主机", "en": "Connecting to remote host"},
+	"progress.remote_prepare":         {"zh": "准备远程 goecs", "en": "Preparing goecs on remote host"},
+	"progress.docker_prepare":         {"zh": "准备容器中的 goecs", "en": "Preparing goecs in container"},
+	"log.empty":                       {"zh": "暂无日志内容\n\n日志将在测试运行时自动更新。", "en": "No logs yet.\n\nLogs update automatically while tests run."},
+	"log.not_found":                   {"zh": "日志文件 ecs.log 不存在\n\n可能测试未生成日志文件，或文件已被删除。", "en": "Log file ecs.log not found.\n\nNo log generated yet or file was removed."},
+	"log.read_failed":                 {"zh": "无法读取日志文件: ", "en": "Cannot read log file: "},
+	"log.interrupted":                 {"zh": "\n\n========== 测试被用户中断 ==========\n", "en": "\n\n========== Interrupted by user ==========\n"},
+	"log.autosaved":                   {"zh": "\n日志已自动保存到 %s\n", "en": "\nLog saved to %s\n"},
+	"log.autosave_failed":             {"zh": "\n自动保存日志失败: %v\n", "en": "\nFailed to auto-save log: %v\n"},
+	"log.interrupted_short":           {"zh": "\n测试被用户中断\n", "en": "\nTest interrupted by user\n"},
+	"log.force_stopped":               {"zh": "\n========== 已强制结束子进程 ==========\n", "en": "\n========== Child processes killed ==========\n"},
+	"log.paused":                      {"zh": "\n========== 测试已暂停 ==========\n", "en": "\n========== Paused ==========\n"},
+	"log.resumed":                     {"zh": "========== 继续测试 ==========\n\n", "en": "========== Resumed ==========\n\n"},
+	"log.error_prefix":                {"zh": "\n错误: ", "en": "\nError: "},
+	"log.fatal_prefix":                {"zh": "\n严重错误: ", "en": "\nFatal error: "},
+	"error.cancelled":                 {"zh": "测试已取消。", "en": "The test was cancelled."},
+	"error.remote_auth":               {"zh": "SSH 认证失败，请检查用户名、密码或私钥。", "en": "SSH authentication failed. Check the user, password or private key."},
+	"error.remote_host_key":           {"zh": "远程主机公钥与之前记录的不一致，已中止连接。", "en": "The remote host key changed since the last connection; connection aborted."},
+	"error.permission":                {"zh": "权限不足，请以管理员/root 权限重新启动后再运行该测试。", "en": "Insufficient privileges. Restart as Administrator/root and try again."},
+	"error.network":                   {"zh": "网络连接不可用或目标服务暂时无法访问，请稍后重试。", "en": "Network is unavailable or the remote service cannot be reached. Please retry later."},
+	"error.timeout":                   {"zh": "测试等待超时，部分网络测试可能受线路或目标服务影响。", "en": "The test timed out. Some network checks may be affected by the route or remote service."},
+	"error.generic":                   {"zh": "测试未能完成，请查看上方输出和日志获取更多线索。", "en": "The test could not be completed. Check the output and logs for details."},
 }
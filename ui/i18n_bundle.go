@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2/lang"
+)
+
+const (
+	languagePreferenceKey = "language"
+	// langAuto 表示按系统区域设置自动选择界面语言
+	langAuto = "auto"
+)
+
+// languageOption 是语言选项的设置值与显示名，显示名不随界面语言变化
+type languageOption struct {
+	setting string
+	label   string
+}
+
+var languageOptions = []languageOption{
+	{setting: langAuto, label: "自动 / Auto"},
+	{setting: langZH, label: "中文"},
+	{setting: langEN, label: "English"},
+}
+
+// translationBundles 保存从翻译文件加载的文本，按语言再按键索引，优先于内置文本
+var (
+	translationMu      sync.RWMutex
+	translationBundles = map[string]map[string]string{}
+)
+
+// normalizeLanguage 把 zh-CN、zh_Hans、en-US 等写法归一为内置语言代码，无法识别时返回空串
+func normalizeLanguage(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	switch {
+	case code == langAuto:
+		return langAuto
+	case code == "zh" || strings.HasPrefix(code, "zh-") || strings.HasPrefix(code, "zh_"):
+		return langZH
+	case code == "en" || strings.HasPrefix(code, "en-") || strings.HasPrefix(code, "en_"):
+		return langEN
+	}
+	return ""
+}
+
+// detectSystemLanguage 按系统区域设置选择界面语言：中文环境使用中文，其余使用英文
+func detectSystemLanguage() string {
+	if normalizeLanguage(lang.SystemLocale().LanguageString()) == langZH {
+		return langZH
+	}
+	return langEN
+}
+
+// resolveLanguage 把语言设置解析为实际使用的语言，未设置时使用中文
+func resolveLanguage(setting string) string {
+	switch normalizeLanguage(setting) {
+	case langAuto:
+		return detectSystemLanguage()
+	case langEN:
+		return langEN
+	}
+	return langZH
+}
+
+func languageLabel(setting string) string {
+	for _, option := range languageOptions {
+		if option.setting == setting {
+			return option.label
+		}
+	}
+	return languageOptions[1].label
+}
+
+func languageSettingByLabel(label string) string {
+	for _, option := range languageOptions {
+		if option.label == label {
+			return option.setting
+		}
+	}
+	return langZH
+}
+
+func languageLabels() []string {
+	labels := make([]string, len(languageOptions))
+	for i, option := range languageOptions {
+		labels[i] = option.label
+	}
+	return labels
+}
+
+// loadTranslationBundle 读取一个 JSON 翻译文件（键到文本的映射），文件名决定语言，如 zh-CN.json、en.json
+func loadTranslationBundle(path string) error {
+	code := normalizeLanguage(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	if code == "" || code == langAuto {
+		return fmt.Errorf("unknown language in bundle name %q", filepath.Base(path))
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var texts map[string]string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	translationMu.Lock()
+	defer translationMu.Unlock()
+	bundle := translationBundles[code]
+	if bundle == nil {
+		bundle = make(map[string]string, len(texts))
+		translationBundles[code] = bundle
+	}
+	for key, text := range texts {
+		if text != "" {
+			bundle[key] = text
+		}
+	}
+	return nil
+}
+
+// loadTranslationBundles 加载目录中所有 *.json 翻译文件；无法解析的文件被跳过，缺失的文本继续使用内置翻译
+func loadTranslationBundles(dir string) {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	for _, path := range paths {
+		_ = loadTranslationBundle(path)
+	}
+}
+
+// bundleText 返回翻译文件中的文本
+func bundleText(code, key string) (string, bool) {
+	translationMu.RLock()
+	defer translationMu.RUnlock()
+	text, ok := translationBundles[code][key]
+	return text, ok
+}
+
+// translate 按语言查找文本：翻译文件优先，其次内置文本，缺失时回退到中文，最后返回键名
+func translate(code, key string) string {
+	if text, ok := bundleText(code, key); ok {
+		return text
+	}
+	if table, ok := i18nText[key]; ok {
+		if val, ok := table[code]; ok && val != "" {
+			return val
+		}
+		if val, ok := table[langZH]; ok {
+			return val
+		}
+	}
+	if text, ok := bundleText(langZH, key); ok {
+		return text
+	}
+	return key
+}
+
+// saveLanguageSetting 保存语言设置，auto 表示每次启动时按系统区域设置选择
+func (ui *TestUI) saveLanguageSetting(setting string) {
+	ui.languageSetting = setting
+	if ui.App != nil {
+		ui.App.Preferences().SetString(languagePreferenceKey, setting)
+	}
+}
@@ -0,0 +1,98 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeLanguage(t *testing.T) {
+	cases := map[string]string{
+		"zh": langZH, "zh-CN": langZH, "zh_Hans": langZH, "ZH-tw": langZH,
+		"en": langEN, "en-US": langEN, " en_GB ": langEN,
+		"auto": langAuto, "fr": "", "": "",
+	}
+	for in, want := range cases {
+		if got := normalizeLanguage(in); got != want {
+			t.Errorf("normalizeLanguage(%q) = %q, want %q", in, got, want)
+		}
+	}
+	if got := resolveLanguage(""); got != langZH {
+		t.Fatalf("unset language = %q, want zh", got)
+	}
+	if got := resolveLanguage(langAuto); got != langZH && got != langEN {
+		t.Fatalf("auto language = %q", got)
+	}
+}
+
+func TestI18nTextHasBothLanguages(t *testing.T) {
+	for key, table := range i18nText {
+		if table[langZH] == "" || table[langEN] == "" {
+			t.Errorf("%s is missing a translation: %v", key, table)
+		}
+	}
+}
+
+func TestLoadTranslationBundleOverridesBuiltinText(t *testing.T) {
+	t.Cleanup(func() {
+		translationMu.Lock()
+		translationBundles = map[string]map[string]string{}
+		translationMu.Unlock()
+	})
+	dir := t.TempDir()
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("en-US.json", `{"tab.result": "Output", "custom.key": "Custom"}`)
+	write("zh-CN.json", `{"only.zh": "仅中文"}`)
+	write("fr.json", `{"tab.result": "Résultats"}`)
+	write("broken.json", `{`)
+	loadTranslationBundles(dir)
+
+	if got := translate(langEN, "tab.result"); got != "Output" {
+		t.Fatalf("overridden text = %q", got)
+	}
+	if got := translate(langZH, "tab.result"); got != i18nText["tab.result"][langZH] {
+		t.Fatalf("zh text changed to %q", got)
+	}
+	if got := translate(langEN, "custom.key"); got != "Custom" {
+		t.Fatalf("new key = %q", got)
+	}
+	if got := translate(langEN, "only.zh"); got != "仅中文" {
+		t.Fatalf("fallback to zh bundle = %q", got)
+	}
+	if got := translate(langEN, "missing.key"); got != "missing.key" {
+		t.Fatalf("missing key = %q", got)
+	}
+	if err := loadTranslationBundle(filepath.Join(dir, "fr.json")); err == nil {
+		t.Fatal("unsupported bundle language accepted")
+	}
+}
+
+func TestLanguageSelectPersistsSetting(t *testing.T) {
+	ui := newTestUIForTest(t)
+	if ui.uiLang != langZH || ui.LanguageSelect.Selected != languageLabel(langZH) {
+		t.Fatalf("default language = %q / %q", ui.uiLang, ui.LanguageSelect.Selected)
+	}
+
+	ui.LanguageSelect.SetSelected(languageLabel(langEN))
+	if ui.uiLang != langEN || ui.tr("tab.result") != "Results" {
+		t.Fatalf("switched language = %q", ui.uiLang)
+	}
+	if got := ui.App.Preferences().String(languagePreferenceKey); got != langEN {
+		t.Fatalf("saved language = %q", got)
+	}
+	if ui.LanguageSelect.Selected != languageLabel(langEN) {
+		t.Fatalf("rebuilt select = %q", ui.LanguageSelect.Selected)
+	}
+
+	ui.LanguageSelect.SetSelected(languageLabel(langAuto))
+	if got := ui.App.Preferences().String(languagePreferenceKey); got != langAuto {
+		t.Fatalf("saved language = %q, want auto", got)
+	}
+	if ui.uiLang != detectSystemLanguage() {
+		t.Fatalf("auto language = %q, want %q", ui.uiLang, detectSystemLanguage())
+	}
+}
@@ -0,0 +1,148 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/png"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/software"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	imageRedactPreferenceKey = "image_export_redact"
+	imageSourcePreferenceKey = "image_export_source"
+
+	imageSourceTerminal   = "terminal"
+	imageSourceStructured = "structured"
+
+	// 限制导出图片的行数与列数，避免生成过大的图片
+	imageExportMaxLines = 1000
+	imageExportMaxCols  = 200
+)
+
+// errNoImageContent 表示所选内容为空，无法导出图片
+var errNoImageContent = errors.New("nothing to render")
+
+// showImageExport 选择导出终端输出或结构化结果、是否脱敏后，按当前主题渲染为 PNG 并保存
+func (ui *TestUI) showImageExport(source exportSource) {
+	prefs := ui.App.Preferences()
+	sourceLabels := []string{ui.tr("image.source.terminal"), ui.tr("image.source.structured")}
+	choice := widget.NewRadioGroup(sourceLabels, nil)
+	choice.Required = true
+	if prefs.String(imageSourcePreferenceKey) == imageSourceStructured {
+		choice.SetSelected(sourceLabels[1])
+	} else {
+		choice.SetSelected(sourceLabels[0])
+	}
+	redactCheck := widget.NewCheck(ui.tr("image.redact"), nil)
+	redactCheck.SetChecked(ui.privacyEnabled() || prefs.Bool(imageRedactPreferenceKey))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("image.source"), choice),
+		widget.NewFormItem("", redactCheck),
+	}
+	dialog.ShowForm(ui.tr("export.image"), ui.tr("button.export"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		kind := imageSourceTerminal
+		if choice.Selected == sourceLabels[1] {
+			kind = imageSourceStructured
+		}
+		prefs.SetString(imageSourcePreferenceKey, kind)
+		prefs.SetBool(imageRedactPreferenceKey, redactCheck.Checked)
+		data, err := ui.renderResultImage(source, kind, redactCheck.Checked)
+		if errors.Is(err, errNoImageContent) {
+			dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
+			return
+		}
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		ui.saveExportFile("goecs-result.png", data)
+	}, ui.Window)
+}
+
+// renderResultImage 把终端输出或结构化结果渲染为 PNG，需在 UI 线程调用
+func (ui *TestUI) renderResultImage(source exportSource, kind string, redacted bool) ([]byte, error) {
+	var redactor func(string) string
+	if redacted {
+		redactor = ui.hostRedactor()
+	}
+	text := source.raw
+	if text == "" {
+		text = source.content
+	}
+	if kind == imageSourceStructured {
+		report := source.report
+		if report.Empty() {
+			return nil, errNoImageContent
+		}
+		if redactor != nil {
+			report = redactReport(report, redactor)
+		}
+		text = structuredImageText(report)
+	}
+	if strings.TrimSpace(text) == "" {
+		return nil, errNoImageContent
+	}
+	img := ui.renderTerminalImage(text, redactor, "goecs · "+time.Now().Format("2006-01-02 15:04:05"))
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// structuredImageText 把结构化结果排成文本表格，分区标题加粗着色
+func structuredImageText(report *results.Report) string {
+	lines := strings.Split(strings.TrimRight(results.EncodeText(report), "\n"), "\n")
+	for i, line := range lines {
+		if i == 0 || strings.HasPrefix(line, "== ") {
+			lines[i] = "\x1b[1;36m" + line + "\x1b[0m"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderTerminalImage 用终端组件按当前主题、配色与字体绘制 text（可含 ANSI 颜色），底部右侧加上水印。
+// 超出 imageExportMaxLines 的行会被截去，超出 imageExportMaxCols 的列不会绘制。
+func (ui *TestUI) renderTerminalImage(text string, redactor func(string) string, watermark string) image.Image {
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) > imageExportMaxLines {
+		lines = append(lines[:imageExportMaxLines], "...")
+	}
+	term := NewTerminalOutput()
+	defer term.Destroy()
+	ui.applyTerminalFont(term)
+	term.SetRedact(redactor)
+	term.SetFullText(strings.Join(lines, "\n"))
+	term.sync()
+
+	m := term.body.metrics()
+	body := term.body.MinSize()
+	stamp := canvas.NewText(watermark, theme.Color(theme.ColorNamePlaceHolder))
+	stamp.TextSize = m.text * 0.85
+	content := container.NewBorder(nil, container.NewHBox(layout.NewSpacer(), stamp), nil, nil, term)
+
+	c := software.NewCanvas()
+	c.SetPadded(false)
+	c.SetContent(content)
+	width := min(body.Width, imageExportMaxCols*m.char+2*m.pad)
+	c.Resize(fyne.NewSize(max(width, stamp.MinSize().Width+2*m.pad), body.Height+stamp.MinSize().Height+m.pad))
+	// 视口在布局后才有尺寸，重新计算可见行
+	term.body.Refresh()
+	return c.Capture()
+}
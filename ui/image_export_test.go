@@ -0,0 +1,44 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestRenderResultImage(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	source := exportSource{
+		content: "IPV4 地址: 203.0.113.7\nCPU 1234\n",
+		raw:     "\x1b[32mIPV4 地址: 203.0.113.7\x1b[0m\nCPU 1234\n",
+		report:  &results.Report{CPU: []results.CPUScore{{Label: "Single-Core", Score: 1234}}},
+	}
+	for _, kind := range []string{imageSourceTerminal, imageSourceStructured} {
+		data, err := ui.renderResultImage(source, kind, true)
+		if err != nil {
+			t.Fatalf("%s: %v", kind, err)
+		}
+		img, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("%s: decode: %v", kind, err)
+		}
+		if b := img.Bounds(); b.Dx() < 100 || b.Dy() < 40 {
+			t.Fatalf("%s: image bounds %v", kind, b)
+		}
+	}
+	if _, err := ui.renderResultImage(exportSource{content: "x", report: &results.Report{}}, imageSourceStructured, false); !errors.Is(err, errNoImageContent) {
+		t.Fatalf("empty report error = %v", err)
+	}
+}
+
+func TestStructuredImageTextHighlightsHeadings(t *testing.T) {
+	text := structuredImageText(&results.Report{CPU: []results.CPUScore{{Label: "Single-Core", Score: 1234}}})
+	if !strings.HasPrefix(text, "\x1b[1;36mGOECS Result\x1b[0m") || !strings.Contains(text, "\x1b[1;36m== CPU ==\x1b[0m\nItem") {
+		t.Fatalf("text = %q", text)
+	}
+}
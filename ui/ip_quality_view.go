@@ -0,0 +1,105 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// ipQualityResultsView 上方为 IP 质量汇总面板，下方保留原始字段表
+func (ui *TestUI) ipQualityResultsView(report *results.Report) fyne.CanvasObject {
+	rows := make([][]string, 0, len(report.IPQuality))
+	for _, field := range report.IPQuality {
+		rows = append(rows, []string{field.Name, field.Value})
+	}
+	table := ui.resultTable([]string{ui.tr("results.col.item"), ui.tr("results.col.value")}, rows)
+	if len(rows) == 0 {
+		return table
+	}
+	split := container.NewVSplit(container.NewVScroll(ui.ipQualityDashboard(results.AnalyzeIPQuality(report))), table)
+	split.Offset = 0.6
+	return split
+}
+
+// ipQualityDashboard 显示综合等级、ASN 与类型、黑名单命中以及各数据库的评分条
+func (ui *TestUI) ipQualityDashboard(s results.IPQualitySummary) fyne.CanvasObject {
+	grade := widget.NewRichText(&widget.TextSegment{
+		Text:  fmt.Sprintf("%s  %.0f/100", s.Grade, s.Points),
+		Style: widget.RichTextStyle{SizeName: theme.SizeNameHeadingText, TextStyle: fyne.TextStyle{Bold: true}},
+	})
+	copyButton := widget.NewButtonWithIcon(ui.tr("ipq.copy"), theme.ContentCopyIcon(), func() {
+		ui.App.Clipboard().SetContent(ui.redactExport(ui.ipQualitySummaryText(s)))
+	})
+	info := container.NewVBox()
+	for _, line := range ui.ipQualityInfoLines(s) {
+		label := widget.NewLabel(line)
+		label.Wrapping = fyne.TextWrapWord
+		info.Add(label)
+	}
+
+	gauges := container.NewVBox()
+	for _, score := range s.Scores {
+		rows := container.NewGridWithColumns(2)
+		for _, db := range score.Scores {
+			bar := widget.NewProgressBar()
+			bar.Max = 100
+			bar.SetValue(db.Score)
+			value := db.Score
+			bar.TextFormatter = func() string { return fmt.Sprintf("%.0f", value) }
+			rows.Add(widget.NewLabel(db.Database))
+			rows.Add(bar)
+		}
+		title := widget.NewLabelWithStyle(score.Name, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		gauges.Add(container.NewVBox(title, rows))
+	}
+
+	content := container.NewVBox(container.NewBorder(nil, nil, nil, copyButton, grade), info)
+	if len(gauges.Objects) > 0 {
+		content.Add(widget.NewSeparator())
+		content.Add(gauges)
+	}
+	return widget.NewCard(ui.tr("ipq.title"), ui.tr("ipq.subtitle"), content)
+}
+
+// ipQualityInfoLines 返回 ASN、使用类型/公司类型/原生与广播、黑名单三行说明，缺少的项省略
+func (ui *TestUI) ipQualityInfoLines(s results.IPQualitySummary) []string {
+	var lines []string
+	if s.ASN != "" {
+		lines = append(lines, "ASN: "+s.ASN)
+	}
+	var kinds []string
+	if s.UsageType != "" {
+		kinds = append(kinds, fmt.Sprintf(ui.tr("ipq.usage"), s.UsageType))
+	}
+	if s.CompanyType != "" {
+		kinds = append(kinds, fmt.Sprintf(ui.tr("ipq.company"), s.CompanyType))
+	}
+	if s.IPType != "" {
+		kinds = append(kinds, ui.tr("ipq.type."+s.IPType))
+	}
+	if len(kinds) > 0 {
+		lines = append(lines, strings.Join(kinds, " · "))
+	}
+	b := s.Blacklist
+	lines = append(lines, fmt.Sprintf(ui.tr("ipq.blacklist"), b.Malicious, b.Suspicious, b.Harmless, b.NoRecord, b.DNSListed, b.DNSTotal))
+	return lines
+}
+
+// ipQualitySummaryText 生成可粘贴的纯文本摘要
+func (ui *TestUI) ipQualitySummaryText(s results.IPQualitySummary) string {
+	lines := []string{fmt.Sprintf(ui.tr("ipq.summary_grade"), s.Grade, s.Points)}
+	lines = append(lines, ui.ipQualityInfoLines(s)...)
+	for _, score := range s.Scores {
+		parts := make([]string, 0, len(score.Scores))
+		for _, db := range score.Scores {
+			parts = append(parts, fmt.Sprintf("%s %.0f", db.Database, db.Score))
+		}
+		lines = append(lines, score.Name+": "+strings.Join(parts, ", "))
+	}
+	return strings.Join(lines, "\n")
+}
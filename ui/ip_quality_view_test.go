@@ -0,0 +1,30 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/container"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestIPQualitySummaryText(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	report := results.Parse("" +
+		"---------------------IP质量检测---------------------\n" +
+		"scamalytics数据库 [1] | ipqualityscore数据库 [E]\n" +
+		"欺诈得分(越低越好):      10 [1] 30 [E]\n" +
+		"无害记录数:     0 [2]  恶意记录数:     1 [2]  可疑记录数:     0 [2]  无记录数:     94 [2]\n" +
+		"使用类型:                hosting [0 7 9 A]\n" +
+		"IP类型: 广播IP\n")
+	text := ui.ipQualitySummaryText(results.AnalyzeIPQuality(report))
+	for _, want := range []string{"IP quality: ", "usage hosting · broadcast IP", "malicious 1", "scamalytics 10, ipqualityscore 30"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("summary missing %q:\n%s", want, text)
+		}
+	}
+	if _, ok := ui.ipQualityResultsView(report).(*container.Split); !ok {
+		t.Fatal("IP quality view should show the dashboard above the raw table")
+	}
+}
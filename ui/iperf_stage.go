@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/iperf"
+)
+
+// iperfBinary 是本机 iperf3 客户端，测试中替换
+var iperfBinary = "iperf3"
+
+const iperfComponentSchema = "ecs-gui.iperf3/v1"
+
+// runIperfStage 依次测试各 iperf3 目标并把结果表写到 out，返回报告中的分区与组件；
+// 本机没有 iperf3 时分区为 unavailable
+func runIperfStage(ctx context.Context, out io.Writer, targets []iperf.Target, language string, width int) (StructuredSection, StructuredComponent) {
+	started := time.Now()
+	section := StructuredSection{Name: "iperf3", Enabled: true}
+	component := StructuredComponent{Name: "iperf3", SchemaVersion: iperfComponentSchema}
+	fmt.Fprintln(out, centeredTitle(pickLanguage(language, "iperf3带宽测试", "iPerf3-Bandwidth"), width))
+	binary, err := exec.LookPath(iperfBinary)
+	if err != nil {
+		fmt.Fprintln(out, pickLanguage(language, "未找到 iperf3 客户端，跳过自定义 iperf3 测试", "iperf3 client not found, skipping custom iperf3 targets"))
+		section.Status, section.Reason = "unavailable", "iperf3 not installed"
+		component.Status, component.Reason = section.Status, section.Reason
+		return section, component
+	}
+	fmt.Fprintf(out, "%-28s %-14s %-14s %s\n", pickLanguage(language, "服务器", "Server"), pickLanguage(language, "发送", "Sent"), pickLanguage(language, "接收", "Received"), pickLanguage(language, "重传", "Retr"))
+	results := make([]iperf.Result, 0, len(targets))
+	failed := 0
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+		result, err := iperf.Run(ctx, binary, target)
+		results = append(results, result)
+		label := target.Address()
+		if target.Reverse {
+			label += " (-R)"
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(out, "%-28s %s\n", label, result.Error)
+			continue
+		}
+		fmt.Fprintf(out, "%-28s %-14s %-14s %d\n", label, fmt.Sprintf("%.2f Mbps", result.SentMbps), fmt.Sprintf("%.2f Mbps", result.ReceivedMbps), result.Retransmits)
+	}
+	switch {
+	case ctx.Err() != nil:
+		section.Status, section.Reason = "canceled", ctx.Err().Error()
+	case failed == len(targets):
+		section.Status, section.Reason = "error", "all iperf3 targets failed"
+	case failed > 0:
+		section.Status, section.Reason = "partial", fmt.Sprintf("%d of %d iperf3 targets failed", failed, len(targets))
+	default:
+		section.Status = "ok"
+	}
+	component.Status, component.Reason = section.Status, section.Reason
+	component.DurationMS = time.Since(started).Milliseconds()
+	component.Payload, _ = json.Marshal(results)
+	return section, component
+}
+
+func pickLanguage(language, zh, en string) string {
+	if language == "zh" {
+		return zh
+	}
+	return en
+}
+
+// iperfTargetsSummary 是配置页按钮上显示的目标数量
+func (ui *TestUI) iperfTargetsSummary() string {
+	targets, _ := iperf.ParseTargets(ui.iperfTargets)
+	summary := fmt.Sprintf(ui.tr("iperf.button"), len(targets))
+	if ui.tunnelInterface != "" {
+		summary += " + " + ui.tunnelInterface
+	}
+	return summary
+}
+
+// setIperfTargets 保存 iperf3 目标配置并刷新配置页按钮
+func (ui *TestUI) setIperfTargets(text string) {
+	ui.iperfTargets = strings.TrimSpace(text)
+	if ui.IperfButton != nil {
+		ui.IperfButton.SetText(ui.iperfTargetsSummary())
+	}
+}
+
+// showIperfTargets 编辑 iperf3 目标（每行一个，保存前校验）以及隧道对比使用的网卡
+func (ui *TestUI) showIperfTargets() {
+	entry := widget.NewMultiLineEntry()
+	entry.SetText(ui.iperfTargets)
+	entry.SetPlaceHolder("iperf.example.com\n[2001:db8::1]:5202 -t 20 -P 4 -R")
+	entry.SetMinRowsVisible(8)
+	items := []*widget.FormItem{
+		{Text: ui.tr("iperf.targets"), Widget: entry, HintText: ui.tr("iperf.hint")},
+	}
+	tunnelChoice, tunnelName := ui.tunnelSelect()
+	items = append(items, &widget.FormItem{Text: ui.tr("tunnel.interface"), Widget: tunnelChoice, HintText: ui.tr("tunnel.hint")})
+	form := dialog.NewForm(ui.tr("iperf.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if ok {
+			ui.setIperfTargets(entry.Text)
+			ui.setTunnelInterface(tunnelName())
+		}
+	}, ui.Window)
+	entry.Validator = func(text string) error {
+		_, err := iperf.ParseTargets(text)
+		return err
+	}
+	form.Resize(fyne.NewSize(620, 520))
+	form.Show()
+}
+
+// mergeStageReport 用界面自行运行的阶段（iperf3、隧道对比）的真实结果替换报告中的同名分区，并附加组件
+func mergeStageReport(report *StructuredRunResult, section StructuredSection, component StructuredComponent) {
+	replaced := false
+	for i := range report.Sections {
+		if report.Sections[i].Name == section.Name {
+			report.Sections[i] = section
+			replaced = true
+		}
+	}
+	if !replaced {
+		report.Sections = append(report.Sections, section)
+	}
+	report.Components = append(report.Components, component)
+}
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/iperf"
+)
+
+func TestRunIperfStageReportsPartialFailures(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake iperf3 is a shell script")
+	}
+	dir := t.TempDir()
+	script := `#!/bin/sh
+if [ "$2" = "bad.example.com" ]; then
+  echo '{"error":"unable to connect to server: Connection refused"}'
+  exit 1
+fi
+echo '{"end":{"sum_sent":{"bits_per_second":900000000,"retransmits":3},"sum_received":{"bits_per_second":880000000}}}'
+`
+	binary := filepath.Join(dir, "iperf3")
+	if err := os.WriteFile(binary, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := iperfBinary
+	iperfBinary = binary
+	t.Cleanup(func() { iperfBinary = old })
+
+	targets, err := iperf.ParseTargets("good.example.com -t 5\nbad.example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out strings.Builder
+	section, component := runIperfStage(context.Background(), &out, targets, "en", 82)
+	if section.Status != "partial" || component.Status != "partial" {
+		t.Fatalf("status = %q/%q, want partial", section.Status, component.Status)
+	}
+	if !strings.Contains(out.String(), "iPerf3-Bandwidth") || !strings.Contains(out.String(), "900.00 Mbps") || !strings.Contains(out.String(), "Connection refused") {
+		t.Fatalf("output = %q", out.String())
+	}
+	var results []iperf.Result
+	if err := json.Unmarshal(component.Payload, &results); err != nil || len(results) != 2 {
+		t.Fatalf("payload = %s, %v", component.Payload, err)
+	}
+	if results[0].ReceivedMbps != 880 || results[1].Error == "" {
+		t.Fatalf("results = %+v", results)
+	}
+}
+
+func TestRunIperfStageWithoutClient(t *testing.T) {
+	old := iperfBinary
+	iperfBinary = filepath.Join(t.TempDir(), "missing-iperf3")
+	t.Cleanup(func() { iperfBinary = old })
+
+	var out strings.Builder
+	section, _ := runIperfStage(context.Background(), &out, []iperf.Target{{Host: "a.example.com", Port: 5201}}, "zh", 82)
+	if section.Status != "unavailable" || !strings.Contains(out.String(), "iperf3带宽测试") {
+		t.Fatalf("section = %+v, output = %q", section, out.String())
+	}
+}
+
+func TestMergeStageReportReplacesPlannedSection(t *testing.T) {
+	report := &StructuredRunResult{Sections: []StructuredSection{{Name: "speed", Status: "ok"}, {Name: "iperf3", Status: "pending"}}}
+	mergeStageReport(report, StructuredSection{Name: "iperf3", Status: "ok"}, StructuredComponent{Name: "iperf3"})
+	if len(report.Sections) != 2 || report.Sections[1].Status != "ok" || len(report.Components) != 1 {
+		t.Fatalf("report = %+v", report)
+	}
+}
+
+func TestBuildExecutionConfigParsesIperfTargets(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.setIperfTargets("iperf.example.com:5202 -P 4 -R\nnot a target -x")
+	config := buildExecutionConfig(ui.currentExecutionForm())
+	if len(config.IperfTargets) != 1 || config.IperfTargets[0].Port != 5202 || !config.IperfTargets[0].Reverse {
+		t.Fatalf("IperfTargets = %+v", config.IperfTargets)
+	}
+}
@@ -0,0 +1,334 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/childproc"
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	latencyTargetsPreferenceKey = "latency_targets"
+	latencyConcurrency          = 8
+	latencyDefaultCount         = 4
+	latencyMaxCount             = 50
+	latencyTCPTimeout           = 3 * time.Second
+)
+
+var latencyColumns = []string{"latency.col.target", "latency.col.protocol", "latency.col.min", "latency.col.avg", "latency.col.max", "latency.col.loss"}
+
+// latencyProbe 探测一个目标，测试中替换
+var latencyProbe = func(ctx context.Context, target latency.Target, count int) results.LatencyResult {
+	if target.Port > 0 {
+		return latency.ProbeTCP(ctx, target, count, latencyTCPTimeout)
+	}
+	// 每个探测包约 1 秒，再留出超时包与域名解析的余量
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(count+5)*time.Second)
+	defer cancel()
+	out, err := childproc.CombinedOutput(pingCommand(ctx, target.Host, count))
+	sent, rtts := latency.ParsePingOutput(string(out))
+	result := latency.Summarize(target, max(sent, count), rtts)
+	if len(rtts) == 0 {
+		result.Error = strings.TrimSpace(string(out))
+		if lines := strings.Split(result.Error, "\n"); len(lines) > 0 {
+			result.Error = strings.TrimSpace(lines[len(lines)-1])
+		}
+		if result.Error == "" && err != nil {
+			result.Error = err.Error()
+		}
+	}
+	return result
+}
+
+// latencyTool 是延迟工具页的状态；rows 只在界面线程读写，导出时通过 mu 读取快照
+type latencyTool struct {
+	targets  *widget.Entry
+	count    *widget.Entry
+	status   *widget.Label
+	table    *widget.Table
+	run      *widget.Button
+	stop     *widget.Button
+	cancel   context.CancelFunc
+	done     chan struct{} // 本轮探测结束时关闭
+	sortCol  int
+	sortDesc bool
+
+	mu   sync.Mutex
+	rows []latencyRow
+}
+
+// latencyRow 是矩阵中的一行，done 为假表示仍在探测
+type latencyRow struct {
+	result results.LatencyResult
+	done   bool
+}
+
+// createLatencyTab 创建延迟工具页：粘贴目标列表，并发 ping 后显示可排序的延迟矩阵
+func (ui *TestUI) createLatencyTab() fyne.CanvasObject {
+	tool := &latencyTool{sortCol: 3}
+	ui.latency = tool
+	tool.targets = widget.NewMultiLineEntry()
+	tool.targets.SetPlaceHolder(ui.tr("latency.placeholder"))
+	tool.targets.SetMinRowsVisible(5)
+	if ui.App != nil {
+		tool.targets.SetText(ui.App.Preferences().String(latencyTargetsPreferenceKey))
+	}
+	tool.count = widget.NewEntry()
+	tool.count.SetText(strconv.Itoa(latencyDefaultCount))
+	tool.status = widget.NewLabel(ui.tr("latency.hint"))
+	tool.status.Wrapping = fyne.TextWrapWord
+	tool.run = widget.NewButtonWithIcon(ui.tr("latency.run"), theme.MediaPlayIcon(), ui.startLatencyProbe)
+	tool.run.Importance = widget.HighImportance
+	tool.stop = widget.NewButtonWithIcon(ui.tr("latency.stop"), theme.MediaStopIcon(), ui.stopLatencyProbe)
+	tool.stop.Disable()
+	exportButton := widget.NewButtonWithIcon(ui.tr("button.export"), theme.DocumentSaveIcon(), nil)
+	exportButton.OnTapped = func() { ui.showExportMenu(exportButton) }
+
+	tool.table = widget.NewTable(
+		func() (int, int) { return len(tool.rows) + 1, len(latencyColumns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				text := ui.tr(latencyColumns[id.Col])
+				if id.Col == tool.sortCol {
+					text += map[bool]string{false: " ▲", true: " ▼"}[tool.sortDesc]
+				}
+				label.SetText(text)
+				return
+			}
+			row := tool.rows[id.Row-1]
+			text, importance := ui.latencyCell(row, id.Col)
+			label.Importance = importance
+			label.SetText(text)
+		},
+	)
+	tool.table.SetColumnWidth(0, 260)
+	tool.table.SetColumnWidth(1, 90)
+	for col := 2; col < len(latencyColumns); col++ {
+		tool.table.SetColumnWidth(col, 110)
+	}
+	// 点击表头按该列排序，再次点击切换升降序
+	tool.table.OnSelected = func(id widget.TableCellID) {
+		tool.table.UnselectAll()
+		if id.Row != 0 {
+			return
+		}
+		if tool.sortCol == id.Col {
+			tool.sortDesc = !tool.sortDesc
+		} else {
+			tool.sortCol, tool.sortDesc = id.Col, false
+		}
+		tool.sortRows()
+		tool.table.Refresh()
+	}
+
+	controls := container.NewHBox(widget.NewLabel(ui.tr("latency.count")), container.NewGridWrap(fyne.NewSize(70, tool.count.MinSize().Height), tool.count),
+		layout.NewSpacer(), exportButton, tool.stop, tool.run)
+	top := container.NewVBox(tool.targets, controls, tool.status)
+	return container.NewBorder(top, nil, nil, nil, tool.table)
+}
+
+// latencyCell 返回矩阵单元格的文字与颜色：未完成显示省略号，全部丢包标红
+func (ui *TestUI) latencyCell(row latencyRow, col int) (string, widget.Importance) {
+	r := row.result
+	switch col {
+	case 0:
+		return r.Target, widget.MediumImportance
+	case 1:
+		return strings.ToUpper(r.Protocol), widget.MediumImportance
+	}
+	if !row.done {
+		return "…", widget.LowImportance
+	}
+	if r.Received == 0 {
+		if col == 5 {
+			return "100%", widget.DangerImportance
+		}
+		return "-", widget.LowImportance
+	}
+	switch col {
+	case 2:
+		return fmt.Sprintf("%.2f", r.MinMs), widget.MediumImportance
+	case 3:
+		return fmt.Sprintf("%.2f", r.AvgMs), widget.MediumImportance
+	case 4:
+		return fmt.Sprintf("%.2f", r.MaxMs), widget.MediumImportance
+	}
+	loss := r.LossPercent()
+	importance := widget.SuccessImportance
+	if loss > 0 {
+		importance = widget.WarningImportance
+	}
+	return strconv.FormatFloat(loss, 'f', -1, 64) + "%", importance
+}
+
+// sortRows 按当前列排序；数值列中未完成或无回复的行总排在最后
+func (t *latencyTool) sortRows() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := func(row latencyRow) float64 {
+		r := row.result
+		switch t.sortCol {
+		case 2:
+			return r.MinMs
+		case 3:
+			return r.AvgMs
+		case 4:
+			return r.MaxMs
+		}
+		return r.LossPercent()
+	}
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		a, b := t.rows[i], t.rows[j]
+		switch t.sortCol {
+		case 0, 1:
+			x, y := a.result.Target, b.result.Target
+			if t.sortCol == 1 {
+				x, y = a.result.Protocol+" "+x, b.result.Protocol+" "+y
+			}
+			if t.sortDesc {
+				return x > y
+			}
+			return x < y
+		}
+		aValid, bValid := a.done && (a.result.Received > 0 || t.sortCol == 5), b.done && (b.result.Received > 0 || t.sortCol == 5)
+		if aValid != bValid {
+			return aValid
+		}
+		if t.sortDesc {
+			return key(a) > key(b)
+		}
+		return key(a) < key(b)
+	})
+}
+
+// results 返回已完成的探测结果，供导出合并
+func (t *latencyTool) results() []results.LatencyResult {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var out []results.LatencyResult
+	for _, row := range t.rows {
+		if row.done {
+			out = append(out, row.result)
+		}
+	}
+	return out
+}
+
+// startLatencyProbe 解析目标列表并以有限并发探测，每完成一个目标刷新一次矩阵
+func (ui *TestUI) startLatencyProbe() {
+	tool := ui.latency
+	targets, err := latency.ParseTargets(tool.targets.Text)
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	if len(targets) == 0 {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("latency.no_targets"), ui.Window)
+		return
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(tool.count.Text))
+	if err != nil || count <= 0 || count > latencyMaxCount {
+		dialog.ShowError(fmt.Errorf(ui.tr("latency.bad_count"), latencyMaxCount), ui.Window)
+		return
+	}
+	if ui.App != nil {
+		ui.App.Preferences().SetString(latencyTargetsPreferenceKey, tool.targets.Text)
+	}
+
+	tool.mu.Lock()
+	tool.rows = make([]latencyRow, len(targets))
+	for i, target := range targets {
+		tool.rows[i].result = results.LatencyResult{Target: target.String(), Protocol: target.Protocol()}
+	}
+	tool.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	tool.cancel = cancel
+	tool.run.Disable()
+	tool.stop.Enable()
+	tool.status.SetText(fmt.Sprintf(ui.tr("latency.progress"), 0, len(targets)))
+	tool.table.Refresh()
+
+	done := make(chan struct{})
+	tool.done = done
+	go func() {
+		defer close(done)
+		defer cancel()
+		// 探测并发进行，结果经 channel 逐个交给界面线程
+		finishedResults := make(chan results.LatencyResult)
+		sem := make(chan struct{}, latencyConcurrency)
+		var wg sync.WaitGroup
+		for _, target := range targets {
+			wg.Add(1)
+			go func(target latency.Target) {
+				defer wg.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+				result := latencyProbe(ctx, target, count)
+				if ctx.Err() == nil {
+					finishedResults <- result
+				}
+			}(target)
+		}
+		go func() {
+			wg.Wait()
+			close(finishedResults)
+		}()
+		finished := 0
+		for result := range finishedResults {
+			finished++
+			progress := fmt.Sprintf(ui.tr("latency.progress"), finished, len(targets))
+			ui.runOnUI(func() {
+				tool.mu.Lock()
+				for i := range tool.rows {
+					if !tool.rows[i].done && tool.rows[i].result.Target == result.Target {
+						tool.rows[i] = latencyRow{result: result, done: true}
+						break
+					}
+				}
+				tool.mu.Unlock()
+				tool.sortRows()
+				tool.status.SetText(progress)
+				tool.table.Refresh()
+			})
+		}
+		stopped := ctx.Err() != nil
+		ui.runOnUI(func() {
+			tool.run.Enable()
+			tool.stop.Disable()
+			if stopped {
+				tool.status.SetText(fmt.Sprintf(ui.tr("latency.stopped"), finished, len(targets)))
+			}
+		})
+	}()
+}
+
+func (ui *TestUI) stopLatencyProbe() {
+	if tool := ui.latency; tool != nil && tool.cancel != nil {
+		tool.cancel()
+		tool.stop.Disable()
+	}
+}
@@ -0,0 +1,65 @@
+package ui
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestLatencyToolProbesSortsAndExports(t *testing.T) {
+	old := latencyProbe
+	latencyProbe = func(ctx context.Context, target latency.Target, count int) results.LatencyResult {
+		switch target.Host {
+		case "slow.example.com":
+			return latency.Summarize(target, count, []float64{80, 90})
+		case "down.example.com":
+			result := latency.Summarize(target, count, nil)
+			result.Error = "timeout"
+			return result
+		}
+		return latency.Summarize(target, count, []float64{10, 12})
+	}
+	t.Cleanup(func() { latencyProbe = old })
+
+	ui := newTestUIForTest(t)
+	tool := ui.latency
+	tool.targets.SetText("down.example.com\nslow.example.com, fast.example.com:443")
+	tool.count.SetText("2")
+	ui.startLatencyProbe()
+
+	select {
+	case <-tool.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("latency probe did not finish")
+	}
+	if got := tool.results(); len(got) != 3 {
+		t.Fatalf("results() = %+v", got)
+	}
+	// 默认按平均延迟升序，无回复的目标排在最后
+	want := []string{"fast.example.com:443", "slow.example.com", "down.example.com"}
+	for i, row := range tool.rows {
+		if row.result.Target != want[i] {
+			t.Fatalf("rows[%d] = %q, want %q", i, row.result.Target, want[i])
+		}
+	}
+	if text, importance := ui.latencyCell(tool.rows[2], 5); text != "100%" || importance != widget.DangerImportance {
+		t.Fatalf("loss cell = %q, %v", text, importance)
+	}
+
+	tool.table.OnSelected(widget.TableCellID{Row: 0, Col: 0})
+	if tool.rows[0].result.Target != "down.example.com" || tool.sortCol != 0 {
+		t.Fatalf("rows after sorting by target = %+v", tool.rows)
+	}
+
+	source := ui.currentExportSource()
+	if len(source.report.Latency) != 3 {
+		t.Fatalf("export latency = %+v", source.report.Latency)
+	}
+	if ui.ParsedResults != nil && len(ui.ParsedResults.Latency) != 0 {
+		t.Fatal("export modified the parsed results")
+	}
+}
@@ -0,0 +1,94 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// maxDroppedLogSize 是拖入的日志文件的大小上限，ecs 的完整输出通常只有几百 KB
+const maxDroppedLogSize = 32 << 20
+
+// droppedLogExtensions 是可以拖入窗口查看的输出文件类型
+var droppedLogExtensions = []string{".log", ".txt"}
+
+// setupLogDrop 允许把保存的 ecs 输出文件拖到窗口上，在查看标签页中打开
+func (ui *TestUI) setupLogDrop() {
+	ui.Window.SetOnDropped(func(_ fyne.Position, uris []fyne.URI) {
+		ui.openDroppedLogs(uris)
+	})
+}
+
+// openDroppedLogs 依次打开拖入的文件，跳过不支持的类型并汇总错误
+func (ui *TestUI) openDroppedLogs(uris []fyne.URI) {
+	var failed []string
+	var last *runTab
+	for _, uri := range uris {
+		path := uri.Path()
+		if !containsString(droppedLogExtensions, strings.ToLower(filepath.Ext(path))) {
+			failed = append(failed, fmt.Sprintf(ui.tr("log_drop.unsupported"), filepath.Base(path)))
+			continue
+		}
+		output, err := readDroppedLog(path)
+		if err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %v", filepath.Base(path), err))
+			continue
+		}
+		last = ui.openViewerTab(filepath.Base(path), output)
+	}
+	if last != nil {
+		ui.runTabs.Select(last.item)
+		ui.showResultTab()
+	}
+	if len(failed) > 0 {
+		dialog.ShowInformation(ui.tr("dialog.hint"), strings.Join(failed, "\n"), ui.Window)
+	}
+}
+
+func readDroppedLog(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	if info.Size() > maxDroppedLogSize {
+		return "", fmt.Errorf("file is larger than %d MB", maxDroppedLogSize>>20)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.ReplaceAll(string(data), "\r\n", "\n"), nil
+}
+
+// openViewerTab 在新标签页中只读查看一份保存的输出，并用结果面板展示解析出的结构化结果，需在 UI 线程调用
+func (ui *TestUI) openViewerTab(title, output string) *runTab {
+	ctx, cancel := context.WithCancel(context.Background())
+	tab := &runTab{
+		ui:        ui,
+		title:     title,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		statusKey: "status.log_file",
+		report:    results.Parse(results.StripANSI(output)),
+	}
+	close(tab.done)
+	ui.addRunTab(tab, theme.DocumentIcon(), "")
+	tab.stopButton.Hide()
+	tab.progress.Hide()
+	tab.current.Hide()
+	tab.stdin.content.Hide()
+	tab.terminal.SetFullText(output)
+	for i, result := range parsedResultTabs {
+		tab.results.Items[i].Content = result.build(ui, tab.report)
+	}
+	tab.results.Refresh()
+	return tab
+}
@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/storage"
+)
+
+func TestDroppedLogOpensViewerTab(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	t.Cleanup(ui.closeAllRunTabs)
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "vps.LOG")
+	if err := os.WriteFile(logPath, []byte("-----------就近节点测速-----------\r\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\r\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	otherPath := filepath.Join(dir, "report.pdf")
+	if err := os.WriteFile(otherPath, []byte("%PDF"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	ui.openDroppedLogs([]fyne.URI{storage.NewFileURI(otherPath), storage.NewFileURI(logPath)})
+	if len(ui.extraRuns) != 1 {
+		t.Fatalf("extraRuns = %d, want only the log file", len(ui.extraRuns))
+	}
+	tab := ui.extraRuns[0]
+	if tab.title != "vps.LOG" || tab.currentStatus() != "status.log_file" || !tab.isDone() || ui.runTabs.Selected() != tab.item {
+		t.Fatalf("tab = %q %q", tab.title, tab.currentStatus())
+	}
+	if tab.report == nil || len(tab.report.Speed) != 1 || tab.terminal.GetText() == "" {
+		t.Fatalf("report = %+v", tab.report)
+	}
+	if len(ui.historyItems) != 0 {
+		t.Fatal("viewing a log file should not add a history run")
+	}
+
+	ui.closeFinishedRunTabs()
+	if len(ui.extraRuns) != 0 {
+		t.Fatal("viewer tabs should close with the finished tabs")
+	}
+}
@@ -1,11 +1,13 @@
 package ui
 
 import (
+	"errors"
 	"net/url"
 	"runtime"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
 	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
@@ -14,12 +16,20 @@ import (
 // NewTestUI 创建新的测试UI实例
 func NewTestUI(app fyne.App) *TestUI {
 	themeMode := normalizeThemeMode(app.Preferences().StringWithFallback(themePreferenceKey, themeModeLight))
+	languageSetting := normalizeLanguage(app.Preferences().StringWithFallback(languagePreferenceKey, langZH))
+	if languageSetting == "" {
+		languageSetting = langZH
+	}
 	ui := &TestUI{
-		App:       app,
-		uiLang:    langZH,
-		themeMode: themeMode,
-		Window:    app.NewWindow(""),
+		App:             app,
+		uiLang:          resolveLanguage(languageSetting),
+		languageSetting: languageSetting,
+		themeMode:       themeMode,
+		terminalScheme:  app.Preferences().String(terminalSchemePreferenceKey),
+		Window:          app.NewWindow(""),
 	}
+	ui.setupAppLog()
+	loadTranslationBundles(ui.appDataDir("i18n"))
 	ui.applyThemeMode(themeMode)
 	ui.Window.SetTitle(ui.tr("app.title"))
 
@@ -31,7 +41,15 @@ func NewTestUI(app fyne.App) *TestUI {
 	ui.Window.CenterOnScreen()
 
 	ui.buildUI()
+	ui.loadSettings()
+	ui.restoreInterruptedSession()
 	ui.registerLifecycleHooks()
+	ui.setupTray()
+	ui.Window.SetCloseIntercept(ui.onWindowCloseRequest)
+	ui.setupLogDrop()
+	if err := ui.applyAPISetting(); err != nil {
+		dialog.ShowError(errors.Join(errors.New(ui.tr("api.start_failed")), err), ui.Window)
+	}
 
 	// 设置窗口关闭时的清理操作
 	ui.Window.SetOnClosed(func() {
@@ -39,6 +57,15 @@ func NewTestUI(app fyne.App) *TestUI {
 		if ui.CancelFn != nil {
 			ui.CancelFn()
 		}
+		ui.closeAllRunTabs()
+		ui.closePopouts()
+		ui.saveSplitLayout()
+
+		// 保存当前设置，下次启动时恢复
+		_ = ui.saveSettings()
+		if ui.api != nil {
+			ui.api.close()
+		}
 
 		// 清理 Terminal 资源
 		if ui.Terminal != nil {
@@ -63,12 +90,38 @@ func (ui *TestUI) registerLifecycleHooks() {
 		ui.inBackground = false
 		ui.Mu.Unlock()
 	})
+	ui.App.Lifecycle().SetOnStarted(func() {
+		ui.startScheduler()
+		ui.startFolderWatch()
+		if !ui.remoteEnabled() {
+			ui.refreshSystemInfo()
+		}
+		ui.startupUpdateTasks()
+		ui.showSetupWizardIfFirstRun()
+	})
+	ui.App.Lifecycle().SetOnStopped(func() {
+		ui.stopScheduler()
+		ui.stopFolderWatch()
+		ui.applyStagedUpdate()
+	})
 }
 
 // buildUI 构建用户界面 - 使用Tab切换页面
 func (ui *TestUI) buildUI() {
 	// 创建终端输出组件
 	ui.Terminal = NewTerminalOutput()
+	ui.Terminal.Translate = ui.tr
+	ui.Terminal.IPActions = ui.ipMenuItems
+	ui.Terminal.SetBufferLimit(parseTerminalBuffer(ui.terminalBufferSetting()))
+	ui.Terminal.SetBackpressure(ui.terminalBackpressureSetting() == terminalBackpressureBlock)
+	ui.applyTerminalFont(ui.Terminal)
+	ui.Terminal.SetGutter(ui.terminalGutterSetting())
+	ui.Terminal.Bookmarkable = true
+	ui.setupTerminalFolds(ui.Terminal, func() bool {
+		ui.Mu.Lock()
+		defer ui.Mu.Unlock()
+		return ui.IsRunning
+	})
 
 	// 创建状态栏
 	ui.StatusLabel = widget.NewLabel(ui.tr("status.ready"))
@@ -90,13 +143,26 @@ func (ui *TestUI) buildUI() {
 	launchTab := container.NewTabItem(ui.tr("tab.launch"), ui.createLaunchTab())
 	configTab := container.NewTabItem(ui.tr("tab.config"), ui.createConfigTab())
 	resultTab := container.NewTabItem(ui.tr("tab.result"), ui.createResultTab())
+	historyTab := container.NewTabItem(ui.tr("tab.history"), ui.createHistoryTab())
+	trendsTab := container.NewTabItem(ui.tr("tab.trends"), ui.createTrendsTab())
+	latencyTab := container.NewTabItem(ui.tr("tab.latency"), ui.createLatencyTab())
+	portsTab := container.NewTabItem(ui.tr("tab.ports"), ui.createPortTab())
+	monitorTab := container.NewTabItem(ui.tr("tab.monitor"), ui.createMonitorTab())
 	ui.MainTabs = container.NewAppTabs(
 		launchTab,
 		configTab,
 		resultTab,
+		historyTab,
+		trendsTab,
+		latencyTab,
+		portsTab,
+		monitorTab,
 	)
 
 	ui.Window.SetContent(ui.createRootContent())
+	ui.syncSelectionSidebar()
+	ui.registerShortcuts()
+	ui.registerZoomShortcuts()
 }
 
 func (ui *TestUI) createRootContent() fyne.CanvasObject {
@@ -198,6 +264,7 @@ func (ui *TestUI) createLaunchTab() fyne.CanvasObject {
 	manage := container.NewAdaptiveGrid(optionGridColumns(),
 		widget.NewButtonWithIcon(ui.tr("button.open_config"), theme.SettingsIcon(), ui.showConfigTab),
 		widget.NewButtonWithIcon(ui.tr("tab.result"), theme.DocumentIcon(), ui.showResultTab),
+		widget.NewButtonWithIcon(ui.tr("setup.title"), theme.HelpIcon(), ui.showSetupWizard),
 	)
 
 	content := container.NewVBox(
@@ -211,9 +278,17 @@ func (ui *TestUI) createLaunchTab() fyne.CanvasObject {
 			widget.NewLabelWithStyle(ui.tr("launch.manage"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
 			manage,
 		)),
+		ui.createSystemInfoCard(),
 	)
 
-	return container.NewScroll(container.NewPadded(content))
+	sidebar := ui.createSelectionSidebar()
+	if isMobilePlatform() {
+		return container.NewScroll(container.NewPadded(container.NewVBox(content, sidebar)))
+	}
+	return container.NewBorder(nil, nil, nil,
+		container.NewVScroll(container.NewPadded(sidebar)),
+		container.NewScroll(container.NewPadded(content)),
+	)
 }
 
 // createControlButtons 创建控制按钮
@@ -225,14 +300,30 @@ func (ui *TestUI) createControlButtons() fyne.CanvasObject {
 	ui.StopButton.Disable()
 	ui.StopButton.Importance = widget.MediumImportance
 
+	ui.PauseButton = widget.NewButtonWithIcon(ui.tr("button.pause"), theme.MediaPauseIcon(), ui.togglePause)
+	ui.PauseButton.Disable()
+
 	if isMobilePlatform() {
-		return container.NewPadded(container.NewVBox(ui.StartButton, ui.StopButton))
+		return container.NewPadded(container.NewVBox(ui.StartButton, container.NewGridWithColumns(2, ui.PauseButton, ui.StopButton)))
 	}
 
 	return container.NewPadded(container.NewCenter(
 		container.NewHBox(
 			ui.StartButton,
+			ui.PauseButton,
 			ui.StopButton,
 		),
 	))
 }
+
+// rebuildUI 按当前语言与偏好重建整个界面，并恢复重建前的界面状态
+func (ui *TestUI) rebuildUI(state uiStateSnapshot) {
+	if ui.Terminal != nil {
+		ui.Terminal.Destroy()
+	}
+	ui.buildUI()
+	ui.restoreUIState(state)
+	ui.Window.SetContent(ui.createRootContent())
+	ui.Window.SetTitle(ui.tr("app.title"))
+	ui.setupTray()
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// memoryHeadroomMiB 是内存测试之外给系统与其他进程保留的余量
+const memoryHeadroomMiB = 64
+
+// memoryFootprintMiB 估算各内存测试方法的内存占用：stream 为三个 76 MiB 的数组，
+// dd 在 /dev/shm（tmpfs）中写入 1 GiB 文件，sysbench 只用 1 MiB 的块；auto 通常先用 stream
+var memoryFootprintMiB = map[string]uint64{"auto": 240, "stream": 240, "dd": 1024, "sysbench": 16}
+
+// memoryAvailableScript 在 Linux 目标上输出可用内存与空闲 swap 之和（MiB），
+// 旧内核没有 MemAvailable 时以 MemFree+Buffers+Cached 估算
+const memoryAvailableScript = `awk '/^MemAvailable:/{a=$2} /^(MemFree|Buffers|Cached):/{f+=$2} /^SwapFree:/{s=$2} END{if(!a)a=f; print int((a+s)/1024)}' /proc/meminfo`
+
+// localAvailableMemory 返回本机可用内存与空闲 swap 之和（MiB），读取失败时为 0，测试中替换
+var localAvailableMemory = func(ctx context.Context) uint64 {
+	vm, err := mem.VirtualMemoryWithContext(ctx)
+	if err != nil {
+		return 0
+	}
+	available := vm.Available
+	if swap, err := mem.SwapMemoryWithContext(ctx); err == nil {
+		available += swap.Free
+	}
+	return available >> 20
+}
+
+// parseAvailableMemory 解析 memoryAvailableScript 的输出，无法解析时为 0
+func parseAvailableMemory(output string) uint64 {
+	value, _ := strconv.ParseUint(strings.TrimSpace(output), 10, 64)
+	return value
+}
+
+// memoryPlan 是内存测试前安全检查的结论
+type memoryPlan struct {
+	method string // 实际使用的测试方法
+	skip   bool   // 可用内存连 sysbench 都不够时跳过内存测试
+	note   string // 写入运行日志的说明
+}
+
+// planMemoryTest 按可用内存判断 method 是否可能触发 OOM：不够时改用 sysbench，仍不够则跳过；
+// availableMiB 为 0（未能读取）或方法占用未知（如 winsat）时不做调整
+func planMemoryTest(method string, availableMiB uint64, language string) memoryPlan {
+	plan := memoryPlan{method: method}
+	need, known := memoryFootprintMiB[method]
+	if availableMiB == 0 || !known {
+		return plan
+	}
+	small := memoryFootprintMiB["sysbench"]
+	switch {
+	case need+memoryHeadroomMiB <= availableMiB:
+		plan.note = fmt.Sprintf(pickLanguage(language,
+			"内存余量检查：可用内存（含 swap）%d MiB，%s 约需 %d MiB，按原方法测试\n",
+			"Memory check: %d MiB available (including swap), %s needs about %d MiB, keeping the selected method\n"), availableMiB, method, need)
+	case small+memoryHeadroomMiB <= availableMiB:
+		plan.method = "sysbench"
+		plan.note = fmt.Sprintf(pickLanguage(language,
+			"内存余量检查：可用内存（含 swap）仅 %d MiB，%s 约需 %d MiB，可能触发 OOM，已改用占用更小的 sysbench\n",
+			"Memory check: only %d MiB available (including swap) and %s needs about %d MiB, which could trigger the OOM killer; switched to sysbench, which uses far less memory\n"), availableMiB, method, need)
+	default:
+		plan.skip = true
+		plan.note = fmt.Sprintf(pickLanguage(language,
+			"内存余量检查：可用内存（含 swap）仅 %d MiB，不足以安全运行任何内存测试，已跳过内存测试\n",
+			"Memory check: only %d MiB available (including swap), not enough to run any memory test safely; the memory test was skipped\n"), availableMiB)
+	}
+	return plan
+}
+
+// applyMemorySafety 在运行前按目标的可用内存调整 run.Config 中的内存测试，结论写入运行输出与程序日志
+func applyMemorySafety(run *Run, availableMiB uint64) {
+	config := &run.Config
+	if !config.SelectedOptions["memory"] {
+		return
+	}
+	plan := planMemoryTest(config.MemoryMethod, availableMiB, config.Language)
+	if plan.note == "" {
+		return
+	}
+	run.Output(plan.note)
+	runnerLog.Info("memory safety check", "host", run.Host, "available_mib", availableMiB, "method", config.MemoryMethod, "planned", plan.method, "skip", plan.skip)
+	if plan.skip {
+		config.SelectedOptions = maps.Clone(config.SelectedOptions)
+		config.SelectedOptions["memory"] = false
+		return
+	}
+	config.MemoryMethod = plan.method
+}
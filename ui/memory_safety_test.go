@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestPlanMemoryTest(t *testing.T) {
+	cases := []struct {
+		method, language string
+		available        uint64
+		want             string
+		skip             bool
+		note             string
+	}{
+		{"stream", "en", 2048, "stream", false, "keeping the selected method"},
+		{"dd", "zh", 480, "sysbench", false, "已改用占用更小的 sysbench"},
+		{"stream", "en", 256, "sysbench", false, "switched to sysbench"},
+		{"stream", "en", 60, "stream", true, "the memory test was skipped"},
+		{"stream", "en", 0, "stream", false, ""},
+		{"winsat", "en", 60, "winsat", false, ""},
+	}
+	for _, c := range cases {
+		plan := planMemoryTest(c.method, c.available, c.language)
+		if plan.method != c.want || plan.skip != c.skip || (c.note == "") != (plan.note == "") || !strings.Contains(plan.note, c.note) {
+			t.Errorf("planMemoryTest(%q, %d) = %+v", c.method, c.available, plan)
+		}
+	}
+}
+
+func TestApplyMemorySafetyRecordsDecision(t *testing.T) {
+	selected := map[string]bool{"memory": true, "cpu": true}
+	var out strings.Builder
+	run := newRun(nil, ExecutionConfig{Language: "en", MemoryMethod: "dd", SelectedOptions: selected}, func(text string) { out.WriteString(text) }, nil)
+	applyMemorySafety(run, 400)
+	if run.Config.MemoryMethod != "sysbench" || !strings.Contains(out.String(), "400 MiB") {
+		t.Fatalf("method = %q, output = %q", run.Config.MemoryMethod, out.String())
+	}
+
+	out.Reset()
+	run = newRun(nil, ExecutionConfig{Language: "en", MemoryMethod: "stream", SelectedOptions: selected}, func(text string) { out.WriteString(text) }, nil)
+	applyMemorySafety(run, 32)
+	if run.Config.SelectedOptions["memory"] || !selected["memory"] || !strings.Contains(out.String(), "skipped") {
+		t.Fatalf("selected = %v, original = %v, output = %q", run.Config.SelectedOptions, selected, out.String())
+	}
+	if args := strings.Join(goecsRemoteArgs(run.Config), " "); !strings.Contains(args, "-memory=false") {
+		t.Fatalf("remote args = %s", args)
+	}
+}
+
+func TestMemoryAvailableScriptRunsLocally(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("script targets Linux hosts")
+	}
+	out, err := exec.Command("sh", "-c", memoryAvailableScript).Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parseAvailableMemory(string(out)) == 0 || parseAvailableMemory("not a number") != 0 {
+		t.Fatalf("parseAvailableMemory(%q) = 0", out)
+	}
+}
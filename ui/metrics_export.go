@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"net/http"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// apiMetricsPreferenceKey 为真时本地 API 提供 GET /metrics
+const apiMetricsPreferenceKey = "api_metrics"
+
+// latestHostSnapshots 返回历史记录中每台主机最近一次完成且有结构化结果的运行
+func latestHostSnapshots(store *history.Store) []results.Snapshot {
+	summaries, err := store.List()
+	if err != nil {
+		return nil
+	}
+	seen := map[string]bool{}
+	var snapshots []results.Snapshot
+	// 列表按开始时间倒序，每台主机第一条可用的记录即为最新
+	for _, summary := range summaries {
+		if summary.Host == "" || summary.Status != "done" || seen[summary.Host] {
+			continue
+		}
+		run, err := store.Load(summary.ID)
+		if err != nil || run.Results == nil {
+			continue
+		}
+		seen[summary.Host] = true
+		snapshots = append(snapshots, results.Snapshot{Host: summary.Host, Time: summary.FinishedAt, Report: run.Results})
+	}
+	return snapshots
+}
+
+// metricsSnapshots 是 /metrics 的数据来源，历史记录不可用时为空
+func (ui *TestUI) metricsSnapshots() []results.Snapshot {
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return nil
+	}
+	return latestHostSnapshots(store)
+}
+
+// getMetrics 以 Prometheus 文本格式返回各主机最近一次的结果
+func (s *apiServer) getMetrics(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	metrics := s.metrics
+	s.mu.Unlock()
+	if metrics == nil {
+		http.NotFound(w, nil)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = results.WritePrometheus(w, metrics())
+}
@@ -0,0 +1,232 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/mirror"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+)
+
+const (
+	mirrorSettingsFileName = "mirrors.json"
+	mirrorProbeTimeout     = 15 * time.Second
+)
+
+// probeMirrors 访问各镜像测速，测试中替换
+var probeMirrors = mirror.Probe
+
+// downloadHTTPClient 返回发布包下载所用的客户端：按代理设置连接，GitHub 下载地址按镜像设置改写
+func downloadHTTPClient(proxySettings proxy.Settings, mirrors mirror.Settings) *http.Client {
+	return mirrors.Client(proxyHTTPClient(proxySettings, proxy.Downloads, 0))
+}
+
+// downloadClient 按当前代理与镜像设置访问 GitHub Releases
+func (ui *TestUI) downloadClient() *http.Client {
+	return downloadHTTPClient(ui.proxySettings(), ui.mirrorSettings())
+}
+
+// mirrorSettings 读取镜像设置，文件损坏时直连 GitHub
+func (ui *TestUI) mirrorSettings() mirror.Settings {
+	settings, _ := mirror.Load(ui.appDataDir(mirrorSettingsFileName))
+	return settings
+}
+
+// mirrorManager 是下载镜像对话框：选择直连、自动或指定镜像，测速并管理自定义镜像
+type mirrorManager struct {
+	ui       *TestUI
+	path     string
+	settings mirror.Settings
+	list     *widget.List
+	mode     *widget.Select
+	status   *widget.Label
+	mirrors  []mirror.Mirror
+	latency  map[string]string
+	selected int
+	// done 在最近一次测速的结果处理完后关闭
+	done chan struct{}
+}
+
+// showMirrorManager 打开下载镜像对话框
+func (ui *TestUI) showMirrorManager() {
+	m, err := ui.newMirrorManager()
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	d := dialog.NewCustom(ui.tr("mirror.title"), ui.tr("hosts.close"), m.content(), ui.Window)
+	d.Resize(fyne.NewSize(640, 460))
+	d.Show()
+}
+
+func (ui *TestUI) newMirrorManager() (*mirrorManager, error) {
+	path := ui.appDataDir(mirrorSettingsFileName)
+	settings, err := mirror.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	m := &mirrorManager{ui: ui, path: path, settings: settings, status: widget.NewLabel(""), latency: map[string]string{}, selected: -1}
+	m.status.Wrapping = fyne.TextWrapWord
+	m.list = widget.NewList(
+		func() int { return len(m.mirrors) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			obj.(*widget.Label).SetText(m.rowText(id))
+		},
+	)
+	m.list.OnSelected = func(id widget.ListItemID) { m.selected = id }
+	m.list.OnUnselected = func(widget.ListItemID) { m.selected = -1 }
+	m.mode = widget.NewSelect(nil, nil)
+	m.reload()
+	return m, nil
+}
+
+func (m *mirrorManager) content() fyne.CanvasObject {
+	ui := m.ui
+	hint := widget.NewLabel(ui.tr("mirror.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	top := container.NewVBox(hint, container.NewBorder(nil, nil, widget.NewLabel(ui.tr("mirror.mode")), nil, m.mode))
+	actions := container.NewHBox(
+		widget.NewButtonWithIcon(ui.tr("mirror.probe"), theme.MediaPlayIcon(), func() { m.probe() }),
+		widget.NewButtonWithIcon(ui.tr("hosts.add"), theme.ContentAddIcon(), m.add),
+		widget.NewButtonWithIcon(ui.tr("history.delete"), theme.DeleteIcon(), m.removeSelected),
+		layout.NewSpacer(),
+	)
+	return container.NewBorder(top, container.NewVBox(m.status, actions), nil, nil, m.list)
+}
+
+func (m *mirrorManager) custom(id int) bool {
+	return id >= len(mirror.Builtin)
+}
+
+func (m *mirrorManager) rowText(id int) string {
+	item := m.mirrors[id]
+	text := item.Name + "  " + item.URL
+	var tags []string
+	if latency, ok := m.latency[item.URL]; ok {
+		tags = append(tags, latency)
+	}
+	if m.custom(id) {
+		tags = append(tags, m.ui.tr("mirror.custom"))
+	}
+	if item.URL == m.settings.Mode {
+		tags = append(tags, m.ui.tr("ecs.in_use"))
+	}
+	if len(tags) > 0 {
+		text += "  [" + strings.Join(tags, ", ") + "]"
+	}
+	return text
+}
+
+// reload 按当前设置刷新镜像列表与下载源选项
+func (m *mirrorManager) reload() {
+	m.mirrors = m.settings.Mirrors()
+	labels := []string{m.ui.tr("mirror.direct"), m.ui.tr("mirror.auto")}
+	values := []string{"", mirror.Auto}
+	for _, item := range m.mirrors {
+		labels = append(labels, item.Name)
+		values = append(values, item.URL)
+	}
+	m.mode.OnChanged = nil
+	m.mode.SetOptions(labels)
+	if i := slices.Index(values, m.settings.Mode); i >= 0 {
+		m.mode.SetSelected(labels[i])
+	} else {
+		m.mode.SetSelected(labels[0])
+	}
+	m.mode.OnChanged = func(label string) {
+		if i := slices.Index(labels, label); i >= 0 {
+			next := m.settings
+			next.Mode = values[i]
+			m.save(next)
+		}
+	}
+	m.selected = -1
+	m.list.UnselectAll()
+	m.list.Refresh()
+}
+
+func (m *mirrorManager) save(next mirror.Settings) bool {
+	if err := mirror.Save(m.path, next); err != nil {
+		m.status.SetText(err.Error())
+		return false
+	}
+	m.settings, _ = mirror.Load(m.path)
+	m.status.SetText("")
+	m.reload()
+	return true
+}
+
+// probe 在后台对全部镜像测速，返回的通道在结果显示后关闭
+func (m *mirrorManager) probe() <-chan struct{} {
+	done := make(chan struct{})
+	m.done = done
+	mirrors, client := m.mirrors, proxyHTTPClient(m.ui.proxySettings(), proxy.Downloads, 0)
+	m.status.SetText(m.ui.tr("mirror.probing"))
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), mirrorProbeTimeout)
+		results := probeMirrors(ctx, client, mirrors)
+		cancel()
+		m.ui.runOnUI(func() {
+			defer close(done)
+			for _, result := range results {
+				if result.Err != nil {
+					m.latency[result.Mirror.URL] = m.ui.tr("mirror.unreachable")
+				} else {
+					m.latency[result.Mirror.URL] = fmt.Sprintf("%d ms", result.Latency.Milliseconds())
+				}
+			}
+			if len(results) > 0 && results[0].Err == nil {
+				m.status.SetText(fmt.Sprintf(m.ui.tr("mirror.fastest"), results[0].Mirror.Name))
+			} else {
+				m.status.SetText(m.ui.tr("mirror.none_reachable"))
+			}
+			m.list.Refresh()
+		})
+	}()
+	return done
+}
+
+// add 添加自定义镜像
+func (m *mirrorManager) add() {
+	name := widget.NewEntry()
+	address := widget.NewEntry()
+	address.SetPlaceHolder("https://ghproxy.example.com/")
+	items := []*widget.FormItem{
+		widget.NewFormItem(m.ui.tr("hosts.name"), name),
+		widget.NewFormItem("URL", address),
+	}
+	dialog.ShowForm(m.ui.tr("hosts.add"), m.ui.tr("hosts.save"), m.ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		next := m.settings
+		next.Custom = append(slices.Clone(next.Custom), mirror.Mirror{Name: name.Text, URL: address.Text})
+		m.save(next)
+	}, m.ui.Window)
+}
+
+// removeSelected 删除所选自定义镜像，内置镜像不可删除
+func (m *mirrorManager) removeSelected() {
+	if m.selected < 0 || !m.custom(m.selected) {
+		m.status.SetText(m.ui.tr("mirror.select_custom"))
+		return
+	}
+	removed := m.mirrors[m.selected]
+	next := m.settings
+	next.Custom = slices.Delete(slices.Clone(next.Custom), m.selected-len(mirror.Builtin), m.selected-len(mirror.Builtin)+1)
+	if next.Mode == removed.URL {
+		next.Mode = ""
+	}
+	m.save(next)
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/mirror"
+)
+
+func TestMirrorManagerProbesAndManagesCustomMirrors(t *testing.T) {
+	oldProbe := probeMirrors
+	t.Cleanup(func() { probeMirrors = oldProbe })
+	probeMirrors = func(_ context.Context, _ *http.Client, mirrors []mirror.Mirror) []mirror.Result {
+		results := make([]mirror.Result, len(mirrors))
+		for i, m := range mirrors {
+			results[i] = mirror.Result{Mirror: m, Err: errors.New("timeout")}
+			if m.Name == "mine" {
+				results[i] = mirror.Result{Mirror: m, Latency: 42 * time.Millisecond}
+			}
+		}
+		// 与 mirror.Probe 一致：可用的排在前面
+		for i, r := range results {
+			if r.Err == nil {
+				results[0], results[i] = results[i], results[0]
+			}
+		}
+		return results
+	}
+
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	m, err := ui.newMirrorManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !m.save(mirror.Settings{Custom: []mirror.Mirror{{Name: "mine", URL: "https://gh.example.com"}}}) {
+		t.Fatalf("save failed: %s", m.status.Text)
+	}
+	custom := len(mirror.Builtin)
+	if len(m.mirrors) != custom+1 || m.mirrors[custom].URL != "https://gh.example.com/" {
+		t.Fatalf("mirrors = %+v", m.mirrors)
+	}
+
+	waitClosed(t, m.probe())
+	if !strings.Contains(m.status.Text, "mine") || !strings.Contains(m.rowText(custom), "42 ms") {
+		t.Fatalf("status %q, row %q", m.status.Text, m.rowText(custom))
+	}
+
+	m.mode.SetSelected("mine")
+	if got := ui.mirrorSettings().Mode; got != "https://gh.example.com/" {
+		t.Fatalf("mode = %q", got)
+	}
+
+	m.selected = 0
+	m.removeSelected()
+	if len(ui.mirrorSettings().Custom) != 1 {
+		t.Fatal("built-in mirror should not be removable")
+	}
+	m.selected = custom
+	m.removeSelected()
+	if settings := ui.mirrorSettings(); len(settings.Custom) != 0 || settings.Mode != "" {
+		t.Fatalf("after removing the active mirror: %+v", settings)
+	}
+}
@@ -0,0 +1,282 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/childproc"
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/mtr"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	monitorHostPreferenceKey    = "route_monitor_host"
+	monitorMinutesPreferenceKey = "route_monitor_minutes"
+	monitorDefaultMinutes       = 10
+	monitorMaxMinutes           = 720
+	monitorProbeTimeout         = 2 * time.Second
+)
+
+var monitorColumns = []string{"monitor.col.hop", "monitor.col.host", "monitor.col.loss", "monitor.col.sent", "monitor.col.last", "monitor.col.avg", "monitor.col.best", "monitor.col.worst"}
+
+// monitorInterval 是两轮探测开始之间的间隔，测试中缩短
+var monitorInterval = time.Second
+
+// monitorPing 发送一个限定 TTL 的探测包，测试中替换
+var monitorPing mtr.Pinger = func(ctx context.Context, host string, ttl int) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, monitorProbeTimeout)
+	defer cancel()
+	out, err := childproc.CombinedOutput(pingTTLCommand(ctx, host, ttl))
+	return string(out), err
+}
+
+// monitorTool 是路由监测工具页的状态；report 在后台每轮结束后整体替换，读取时持有 mu
+type monitorTool struct {
+	host    *widget.Entry
+	minutes *widget.Entry
+	status  *widget.Label
+	table   *widget.Table
+	run     *widget.Button
+	stop    *widget.Button
+
+	// 以下字段由 mu 保护
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{} // 本轮监测结束时关闭
+	report *results.RouteMonitor
+}
+
+// createMonitorTab 创建路由监测工具页：在设定的时长内持续探测到目标路径上的每一跳，实时显示各跳的丢包与延迟
+func (ui *TestUI) createMonitorTab() fyne.CanvasObject {
+	tool := &monitorTool{}
+	ui.monitor = tool
+	tool.host = widget.NewEntry()
+	tool.host.SetPlaceHolder(ui.tr("monitor.host_placeholder"))
+	tool.minutes = widget.NewEntry()
+	tool.minutes.SetText(strconv.Itoa(monitorDefaultMinutes))
+	if ui.App != nil {
+		tool.host.SetText(ui.App.Preferences().String(monitorHostPreferenceKey))
+		tool.minutes.SetText(strconv.Itoa(ui.App.Preferences().IntWithFallback(monitorMinutesPreferenceKey, monitorDefaultMinutes)))
+	}
+	tool.status = widget.NewLabel(ui.tr("monitor.hint"))
+	tool.status.Wrapping = fyne.TextWrapWord
+	tool.run = widget.NewButtonWithIcon(ui.tr("latency.run"), theme.MediaPlayIcon(), ui.startRouteMonitor)
+	tool.run.Importance = widget.HighImportance
+	tool.stop = widget.NewButtonWithIcon(ui.tr("latency.stop"), theme.MediaStopIcon(), ui.stopRouteMonitor)
+	tool.stop.Disable()
+	exportButton := widget.NewButtonWithIcon(ui.tr("button.export"), theme.DocumentSaveIcon(), nil)
+	exportButton.OnTapped = func() { ui.showExportMenu(exportButton) }
+
+	tool.table = widget.NewTable(
+		func() (int, int) { return tool.hopCount() + 1, len(monitorColumns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				label.SetText(ui.tr(monitorColumns[id.Col]))
+				return
+			}
+			text, importance := tool.cell(id.Row-1, id.Col)
+			label.Importance = importance
+			label.SetText(text)
+		},
+	)
+	tool.table.SetColumnWidth(0, 50)
+	tool.table.SetColumnWidth(1, 220)
+	for col := 2; col < len(monitorColumns); col++ {
+		tool.table.SetColumnWidth(col, 90)
+	}
+
+	controls := container.NewHBox(widget.NewLabel(ui.tr("monitor.minutes")), container.NewGridWrap(fyne.NewSize(70, tool.minutes.MinSize().Height), tool.minutes),
+		layout.NewSpacer(), exportButton, tool.stop, tool.run)
+	top := container.NewVBox(container.NewBorder(nil, nil, widget.NewLabel(ui.tr("monitor.host")), nil, tool.host), controls, tool.status)
+	return container.NewBorder(top, nil, nil, nil, tool.table)
+}
+
+func (t *monitorTool) hopCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.report == nil {
+		return 0
+	}
+	return len(t.report.Hops)
+}
+
+// cell 返回表格单元格的文字与颜色：不回应的跳显示 ???，有丢包标黄，全部丢包标红
+func (t *monitorTool) cell(row, col int) (string, widget.Importance) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.report == nil || row >= len(t.report.Hops) {
+		return "", widget.MediumImportance
+	}
+	hop := t.report.Hops[row]
+	switch col {
+	case 0:
+		return strconv.Itoa(hop.Hop), widget.MediumImportance
+	case 1:
+		if hop.Host == "" {
+			return "???", widget.LowImportance
+		}
+		return hop.Host, widget.MediumImportance
+	}
+	if hop.Host == "" || hop.Sent == 0 {
+		return "-", widget.LowImportance
+	}
+	switch col {
+	case 2:
+		loss := hop.LossPercent()
+		importance := widget.SuccessImportance
+		switch {
+		case hop.Received == 0:
+			importance = widget.DangerImportance
+		case loss > 0:
+			importance = widget.WarningImportance
+		}
+		return fmt.Sprintf("%.1f%%", loss), importance
+	case 3:
+		return strconv.Itoa(hop.Sent), widget.MediumImportance
+	}
+	if hop.Received == 0 {
+		return "-", widget.LowImportance
+	}
+	value := map[int]float64{4: hop.LastMs, 5: hop.AvgMs, 6: hop.BestMs, 7: hop.WorstMs}[col]
+	return fmt.Sprintf("%.2f", value), widget.MediumImportance
+}
+
+// results 返回至少完成一轮的监测结果，供导出合并；尚未开始时为 nil
+func (t *monitorTool) results() *results.RouteMonitor {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.report == nil || t.report.Rounds == 0 {
+		return nil
+	}
+	report := *t.report
+	report.Hops = slices.Clone(report.Hops)
+	return &report
+}
+
+// startRouteMonitor 检查目标与时长，先发现路径，再每隔 monitorInterval 探测一轮，直到时长用完或手动停止
+func (ui *TestUI) startRouteMonitor() {
+	tool := ui.monitor
+	target, err := latency.ParseTarget(tool.host.Text)
+	if err == nil && target.Port != 0 {
+		err = fmt.Errorf(ui.tr("ports.host_with_port"), tool.host.Text)
+	}
+	if err != nil {
+		if strings.TrimSpace(tool.host.Text) == "" {
+			dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("monitor.no_host"), ui.Window)
+			return
+		}
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	minutes, err := strconv.Atoi(strings.TrimSpace(tool.minutes.Text))
+	if err != nil || minutes <= 0 || minutes > monitorMaxMinutes {
+		dialog.ShowError(fmt.Errorf(ui.tr("monitor.bad_minutes"), monitorMaxMinutes), ui.Window)
+		return
+	}
+	if ui.App != nil {
+		ui.App.Preferences().SetString(monitorHostPreferenceKey, tool.host.Text)
+		ui.App.Preferences().SetInt(monitorMinutesPreferenceKey, minutes)
+	}
+
+	stopCtx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	tool.mu.Lock()
+	tool.report = &results.RouteMonitor{Target: target.Host}
+	tool.cancel, tool.done = cancel, done
+	tool.mu.Unlock()
+	tool.run.Disable()
+	tool.stop.Enable()
+	tool.status.SetText(ui.tr("monitor.discovering"))
+	tool.table.Refresh()
+
+	go func() {
+		defer close(done)
+		defer cancel()
+		deadline := time.Now().Add(time.Duration(minutes) * time.Minute)
+		ctx, cancelRun := context.WithDeadline(stopCtx, deadline)
+		defer cancelRun()
+		finish := func(text string) {
+			ui.runOnUI(func() {
+				tool.run.Enable()
+				tool.stop.Disable()
+				tool.status.SetText(text)
+			})
+		}
+
+		hops, err := mtr.Discover(ctx, monitorPing, target.Host, mtr.MaxHops)
+		if err != nil {
+			if stopCtx.Err() != nil {
+				finish(fmt.Sprintf(ui.tr("monitor.stopped"), 0))
+			} else {
+				finish(ui.tr("monitor.no_route"))
+			}
+			return
+		}
+		rounds := 0
+		for {
+			started := time.Now()
+			mtr.Round(ctx, monitorPing, hops)
+			if ctx.Err() != nil {
+				break
+			}
+			rounds++
+			snapshot := slices.Clone(hops)
+			text := fmt.Sprintf(ui.tr("monitor.progress"), rounds, time.Until(deadline).Round(time.Second))
+			ui.runOnUI(func() {
+				tool.mu.Lock()
+				tool.report.Hops, tool.report.Rounds = snapshot, rounds
+				tool.mu.Unlock()
+				tool.status.SetText(text)
+				tool.table.Refresh()
+			})
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Until(started.Add(monitorInterval))):
+			}
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if stopCtx.Err() != nil {
+			finish(fmt.Sprintf(ui.tr("monitor.stopped"), rounds))
+			return
+		}
+		finish(fmt.Sprintf(ui.tr("monitor.finished"), rounds))
+	}()
+}
+
+// stopRouteMonitor 停止监测并等待后台探测结束，需在 UI 线程调用
+func (ui *TestUI) stopRouteMonitor() {
+	tool := ui.monitor
+	if tool == nil {
+		return
+	}
+	tool.mu.Lock()
+	cancel, done := tool.cancel, tool.done
+	tool.mu.Unlock()
+	if cancel == nil {
+		return
+	}
+	tool.stop.Disable()
+	cancel()
+	<-done
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestRouteMonitorRoundsAndExports(t *testing.T) {
+	oldPing, oldInterval := monitorPing, monitorInterval
+	t.Cleanup(func() { monitorPing, monitorInterval = oldPing, oldInterval })
+	monitorInterval = 10 * time.Millisecond
+	monitorPing = func(ctx context.Context, host string, ttl int) (string, error) {
+		switch {
+		case ttl == 1:
+			return "From 10.0.0.1 icmp_seq=1 Time to live exceeded\n", nil
+		case ttl == 2 || host == "172.16.0.1":
+			return "", errors.New("timeout")
+		case ttl == 0 && host == "10.0.0.1":
+			return "64 bytes from 10.0.0.1: icmp_seq=1 ttl=64 time=1.5 ms\n", nil
+		}
+		return "64 bytes from 203.0.113.7: icmp_seq=1 ttl=57 time=30 ms\n", nil
+	}
+
+	ui := newTestUIForTest(t)
+	tool := ui.monitor
+	tool.host.SetText("203.0.113.7")
+	tool.minutes.SetText("1")
+	ui.startRouteMonitor()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for tool.results() == nil || tool.results().Rounds < 3 {
+		if time.Now().After(deadline) {
+			t.Fatal("route monitor did not finish three rounds")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// stopRouteMonitor 等待后台探测结束后才返回
+	ui.stopRouteMonitor()
+
+	report := tool.results()
+	if len(report.Hops) != 3 || report.Hops[1].Host != "" || report.Hops[2].Host != "203.0.113.7" {
+		t.Fatalf("hops = %+v", report.Hops)
+	}
+	if text, importance := tool.cell(1, 1); text != "???" || importance != widget.LowImportance {
+		t.Fatalf("silent hop cell = %q, %v", text, importance)
+	}
+	if text, importance := tool.cell(2, 2); text != "0.0%" || importance != widget.SuccessImportance {
+		t.Fatalf("target loss cell = %q, %v", text, importance)
+	}
+	if !strings.Contains(tool.status.Text, "已停止") && !strings.Contains(tool.status.Text, "Stopped") {
+		t.Fatalf("status = %q", tool.status.Text)
+	}
+
+	source := ui.currentExportSource()
+	if source.report.Monitor == nil || source.report.Monitor.Target != "203.0.113.7" {
+		t.Fatalf("export monitor = %+v", source.report.Monitor)
+	}
+}
+
+func TestRouteMonitorRejectsBadDuration(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.monitor.host.SetText("203.0.113.7")
+	ui.monitor.minutes.SetText("0")
+	ui.startRouteMonitor()
+	if ui.monitor.done != nil || ui.monitor.report != nil {
+		t.Fatal("invalid duration should be rejected before probing")
+	}
+}
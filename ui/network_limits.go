@@ -0,0 +1,138 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/iperf"
+)
+
+// 网络测试强度：full 按预设运行，light 只测一个测速节点并缩短 iperf3
+const (
+	networkLoadFull  = "full"
+	networkLoadLight = "light"
+	// lightIperfDuration 是轻量模式下每个 iperf3 目标的最长测试时间（秒）
+	lightIperfDuration = 5
+)
+
+// parseBandwidthCap 解析带宽上限（Mbps），无效或留空时为 0，表示不限制
+func parseBandwidthCap(text string) int {
+	limit, err := strconv.Atoi(strings.TrimSuffix(strings.TrimSpace(text), "M"))
+	if err != nil || limit < 0 {
+		return 0
+	}
+	return limit
+}
+
+// lightNetworkTests 返回测速是否只测一个节点：选择了轻量模式，或设置了带宽上限（测速节点无法限速）
+func (config ExecutionConfig) lightNetworkTests() bool {
+	return config.NetworkLoad == networkLoadLight || config.BandwidthCap > 0
+}
+
+// applyNetworkLimits 按网络测试强度与带宽上限调整测速节点数与 iperf3 目标，远程与结构化后端也只测一个节点
+func (config *ExecutionConfig) applyNetworkLimits() {
+	if !config.lightNetworkTests() {
+		return
+	}
+	config.SpNum = 1
+	targets := make([]iperf.Target, 0, len(config.IperfTargets))
+	for _, target := range config.IperfTargets {
+		if config.NetworkLoad == networkLoadLight {
+			target.Duration = min(max(target.Duration, 1), lightIperfDuration)
+			target.Parallel = 1
+		}
+		if config.BandwidthCap > 0 {
+			// iperf3 的 -b 限制的是每条流
+			target.Bitrate = max(config.BandwidthCap/max(target.Parallel, 1), 1)
+		}
+		targets = append(targets, target)
+	}
+	config.IperfTargets = targets
+}
+
+// runLightSpeedProfile 只测一个节点：优先手动选择的第一个节点或分组，否则中文测就近节点、英文测一个国际节点
+func runLightSpeedProfile(core CoreRunner, config ExecutionConfig, language string) {
+	switch {
+	case len(config.SpeedServerIDs) > 0:
+		core.SpeedTestServers(config.SpeedServerIDs[:1], config.DataOffline, language)
+	case len(config.SpeedGroups) > 0 && config.SpeedGroups[0] != "nearby":
+		core.SpeedTestCustom("net", config.SpeedGroups[0], 1, language)
+	case len(config.SpeedGroups) > 0 || language == "zh":
+		core.SpeedTestNearby()
+	default:
+		core.SpeedTestCustom("net", "global", 1, language)
+	}
+}
+
+// bandwidthCapNote 是设置了带宽上限时写在测速结果前的提示
+func bandwidthCapNote(language string, limit int) string {
+	return pickLanguage(language,
+		fmt.Sprintf(" 带宽上限 %d Mbps：测速节点无法限速，只测一个节点；iperf3 按上限限速\n", limit),
+		fmt.Sprintf(" Bandwidth cap %d Mbps: speedtest nodes cannot be throttled, testing one node only; iperf3 is capped\n", limit))
+}
+
+// networkLimitsSummary 返回测试项目区按钮上的简短说明
+func (ui *TestUI) networkLimitsSummary() string {
+	load := ui.tr("network_limit.full")
+	if ui.networkLoad == networkLoadLight {
+		load = ui.tr("network_limit.light")
+	}
+	if limit := parseBandwidthCap(ui.bandwidthCap); limit > 0 {
+		load += fmt.Sprintf(" ≤%d Mbps", limit)
+	}
+	return fmt.Sprintf(ui.tr("network_limit.button"), load)
+}
+
+// setNetworkLimits 保存网络测试强度与带宽上限，并刷新按钮
+func (ui *TestUI) setNetworkLimits(load, bandwidthCap string) {
+	ui.networkLoad = networkLoadFull
+	if load == networkLoadLight {
+		ui.networkLoad = networkLoadLight
+	}
+	ui.bandwidthCap = ""
+	if limit := parseBandwidthCap(bandwidthCap); limit > 0 {
+		ui.bandwidthCap = strconv.Itoa(limit)
+	}
+	if ui.NetworkLimitButton != nil {
+		ui.NetworkLimitButton.SetText(ui.networkLimitsSummary())
+	}
+}
+
+// showNetworkLimits 编辑网络测试强度与带宽上限，用于按流量计费或正在提供服务的机器
+func (ui *TestUI) showNetworkLimits() {
+	labels := []string{ui.tr("network_limit.full"), ui.tr("network_limit.light")}
+	load := widget.NewSelect(labels, nil)
+	load.SetSelected(labels[0])
+	if ui.networkLoad == networkLoadLight {
+		load.SetSelected(labels[1])
+	}
+	capEntry := widget.NewEntry()
+	capEntry.SetPlaceHolder(ui.tr("network_limit.unlimited"))
+	capEntry.SetText(ui.bandwidthCap)
+	capEntry.Validator = func(text string) error {
+		if strings.TrimSpace(text) != "" && parseBandwidthCap(text) == 0 {
+			return fmt.Errorf("invalid bandwidth cap %q", text)
+		}
+		return nil
+	}
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("network_limit.load"), load),
+		{Text: ui.tr("network_limit.cap"), Widget: capEntry, HintText: ui.tr("network_limit.hint")},
+	}
+	form := dialog.NewForm(ui.tr("network_limit.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		selected := networkLoadFull
+		if load.Selected == labels[1] {
+			selected = networkLoadLight
+		}
+		ui.setNetworkLimits(selected, capEntry.Text)
+	}, ui.Window)
+	form.Resize(fyne.NewSize(520, 0))
+	form.Show()
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNetworkLimitsShrinkSpeedAndIperf(t *testing.T) {
+	form := executionForm{checks: map[string]bool{}, entries: map[string]string{
+		"spNum":        "5",
+		"iperfTargets": "10.0.0.2 -t 30 -P 4\n10.0.0.3",
+		"networkLoad":  networkLoadLight,
+	}}
+	config := buildExecutionConfig(form)
+	if config.SpNum != 1 || !config.lightNetworkTests() {
+		t.Fatalf("SpNum = %d, want one node in light mode", config.SpNum)
+	}
+	for _, target := range config.IperfTargets {
+		if target.Duration != lightIperfDuration || target.Parallel != 1 || target.Bitrate != 0 {
+			t.Fatalf("target = %+v", target)
+		}
+	}
+
+	form.entries["networkLoad"], form.entries["bandwidthCap"] = networkLoadFull, "100"
+	config = buildExecutionConfig(form)
+	if config.SpNum != 1 || config.BandwidthCap != 100 {
+		t.Fatalf("a cap should also limit the speed test, config = %+v", config)
+	}
+	if first := config.IperfTargets[0]; first.Duration != 30 || first.Parallel != 4 || first.Bitrate != 25 {
+		t.Fatalf("the cap should be split across streams, target = %+v", first)
+	}
+
+	form.entries["bandwidthCap"] = "lots"
+	if config = buildExecutionConfig(form); config.SpNum != 5 || config.IperfTargets[0].Bitrate != 0 {
+		t.Fatalf("full load without a cap should not change the tests, config = %+v", config)
+	}
+}
+
+func TestLightSpeedProfileTestsOneNode(t *testing.T) {
+	tests := []struct {
+		config   ExecutionConfig
+		language string
+		want     []string
+	}{
+		{ExecutionConfig{NetworkLoad: networkLoadLight, PresetKey: "full"}, "zh", []string{"nearby"}},
+		{ExecutionConfig{NetworkLoad: networkLoadLight, PresetKey: "full"}, "en", []string{"global"}},
+		{ExecutionConfig{BandwidthCap: 50, SpeedGroups: []string{"ct", "cu"}}, "zh", []string{"ct"}},
+		{ExecutionConfig{NetworkLoad: networkLoadLight, SpeedGroups: []string{"nearby"}, SpeedServerIDs: []string{"1", "2"}}, "en", []string{"1"}},
+	}
+	for _, tt := range tests {
+		core := &recordingSpeedCore{}
+		runSpeedProfile(core, tt.config, tt.language)
+		if !slices.Equal(core.calls, tt.want) {
+			t.Fatalf("%+v: calls = %v, want %v", tt.config, core.calls, tt.want)
+		}
+	}
+}
+
+func TestNetworkLimitsFollowSettings(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.setNetworkLimits(networkLoadLight, " 200M ")
+	if entries := ui.formEntries(); entries["networkLoad"] != networkLoadLight || entries["bandwidthCap"] != "200" {
+		t.Fatalf("entries = %q %q", entries["networkLoad"], entries["bandwidthCap"])
+	}
+	if config := ui.collectExecutionConfig(); config.NetworkLoad != networkLoadLight || config.BandwidthCap != 200 {
+		t.Fatalf("config = %q %d", config.NetworkLoad, config.BandwidthCap)
+	}
+	if ui.NetworkLimitButton.Text != "网络强度：轻量 ≤200 Mbps" && ui.NetworkLimitButton.Text != "Network load: Light ≤200 Mbps" {
+		t.Fatalf("button = %q", ui.NetworkLimitButton.Text)
+	}
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// notifyDisabledPreferenceKey 为真时不发送桌面通知；默认发送，因此保存“关闭”而不是“开启”
+const notifyDisabledPreferenceKey = "notify_disabled"
+
+// sendNotification 发送系统通知，用户关闭通知时忽略。
+// Fyne 的通知没有点击回调，点击通知后由系统按默认行为激活应用窗口。
+func (ui *TestUI) sendNotification(title, body string) {
+	if ui.App == nil || ui.App.Preferences().Bool(notifyDisabledPreferenceKey) {
+		return
+	}
+	ui.App.SendNotification(fyne.NewNotification(title, body))
+}
+
+// notifyTestFinished 在运行结束后发送一行摘要：用时、关键指标与失败的阶段
+func (ui *TestUI) notifyTestFinished(statusKey string, duration time.Duration, parsed *results.Report, report *StructuredRunResult) {
+	titleKey := "notify.done_title"
+	switch statusKey {
+	case "status.failed":
+		titleKey = "notify.failed_title"
+	case "status.stopped":
+		titleKey = "notify.stopped_title"
+	}
+	ui.sendNotification(ui.tr(titleKey), ui.notificationSummary(statusKey, duration, parsed, report))
+}
+
+func (ui *TestUI) notificationSummary(statusKey string, duration time.Duration, parsed *results.Report, report *StructuredRunResult) string {
+	parts := []string{fmt.Sprintf(ui.tr("notify.duration"), formatHumanDuration(duration, ui.uiLang))}
+	headline := results.Summarize(parsed)
+	if headline.CPUScore > 0 {
+		parts = append(parts, fmt.Sprintf("CPU %.0f", headline.CPUScore))
+	}
+	if headline.DiskReadMBps > 0 || headline.DiskWriteMBps > 0 {
+		parts = append(parts, fmt.Sprintf(ui.tr("notify.disk"), headline.DiskReadMBps, headline.DiskWriteMBps))
+	}
+	if headline.DownloadMbps > 0 || headline.UploadMbps > 0 {
+		parts = append(parts, fmt.Sprintf(ui.tr("notify.network"), headline.DownloadMbps, headline.UploadMbps))
+	}
+	if failed := failedStageNames(report); len(failed) > 0 {
+		parts = append(parts, fmt.Sprintf(ui.tr("notify.failed_stages"), len(failed), strings.Join(failed, ", ")))
+	} else {
+		switch statusKey {
+		case "status.failed":
+			parts = append(parts, ui.tr("notify.failed_body"))
+		case "status.stopped":
+			parts = append(parts, ui.tr("notify.stopped_body"))
+		}
+	}
+	return strings.Join(parts, " · ")
+}
+
+// failedStageNames 返回结构化报告中失败或超时的阶段
+func failedStageNames(report *StructuredRunResult) []string {
+	if report == nil {
+		return nil
+	}
+	var names []string
+	for _, section := range report.Sections {
+		switch section.Status {
+		case "ok", "skipped", "":
+		default:
+			if section.Enabled {
+				names = append(names, section.Name)
+			}
+		}
+	}
+	return names
+}
+
+func (ui *TestUI) createNotifyCheck() *widget.Check {
+	prefs := ui.App.Preferences()
+	check := widget.NewCheck(ui.tr("check.notify"), nil)
+	check.SetChecked(!prefs.Bool(notifyDisabledPreferenceKey))
+	check.OnChanged = func(on bool) { prefs.SetBool(notifyDisabledPreferenceKey, !on) }
+	return check
+}
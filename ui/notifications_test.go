@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestNotificationSummaryIncludesHeadlineAndFailedStages(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	parsed := &results.Report{
+		CPU:   []results.CPUScore{{Label: "1 thread", Score: 1234}},
+		Disk:  []results.DiskResult{{Read: results.DiskMetric{MBps: 120}, Write: results.DiskMetric{MBps: 80}}},
+		Speed: []results.SpeedResult{{Node: "a", DownloadMbps: 900, UploadMbps: 450}},
+	}
+	report := &StructuredRunResult{Sections: []StructuredSection{
+		{Name: "cpu", Enabled: true, Status: "ok"},
+		{Name: "media", Enabled: true, Status: "failed"},
+		{Name: "speed", Enabled: true, Status: "timeout"},
+		{Name: "ping", Enabled: false, Status: "failed"},
+	}}
+
+	got := ui.notificationSummary("status.failed", 21*time.Minute, parsed, report)
+	want := "took 21 min 0 sec · CPU 1234 · disk R 120 / W 80 MB/s · net ↓900 / ↑450 Mbps · 2 stage(s) failed: media, speed"
+	if got != want {
+		t.Fatalf("summary = %q, want %q", got, want)
+	}
+	if got := ui.notificationSummary("status.stopped", time.Minute, nil, nil); got != "took 1 min 0 sec · "+ui.tr("notify.stopped_body") {
+		t.Fatalf("stopped summary = %q", got)
+	}
+}
+
+func TestNotifyCheckStoresDisabledPreference(t *testing.T) {
+	ui := newTestUIForTest(t)
+	check := ui.createNotifyCheck()
+	if !check.Checked {
+		t.Fatal("notifications should be enabled by default")
+	}
+	check.SetChecked(false)
+	if !ui.App.Preferences().Bool(notifyDisabledPreferenceKey) {
+		t.Fatal("unchecking did not store the disabled preference")
+	}
+	ui.sendNotification("title", "body")
+}
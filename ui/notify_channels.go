@@ -0,0 +1,323 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/notify"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const notifyChannelsFileName = "notifiers.json"
+
+// notifyPushTimeout 是一次运行结束后推送所有渠道的总超时
+const notifyPushTimeout = 30 * time.Second
+
+// notifyRequestTimeout 是单个渠道一次推送的超时
+const notifyRequestTimeout = 15 * time.Second
+
+func (ui *TestUI) notifyHTTPClient() *http.Client {
+	return proxyHTTPClient(ui.proxySettings(), proxy.Notifications, notifyRequestTimeout)
+}
+
+func (ui *TestUI) loadNotifyChannels() ([]notify.Channel, error) {
+	return notify.Load(ui.appDataDir(notifyChannelsFileName))
+}
+
+// runNotificationMessages 按运行结果生成要推送的消息：完成或失败各一条，
+// 完成且有渠道订阅性能回退时，与同一主机的基准（没有基准时为上一次结果）比较后追加回退消息
+func (ui *TestUI) runNotificationMessages(host, statusKey string, duration time.Duration, parsed *results.Report, report *StructuredRunResult, channels []notify.Channel) []notify.Message {
+	var event notify.Event
+	var titleKey string
+	switch statusKey {
+	case "status.done":
+		event, titleKey = notify.EventCompleted, "notify.done_title"
+	case "status.failed":
+		event, titleKey = notify.EventFailed, "notify.failed_title"
+	default:
+		return nil
+	}
+	now := time.Now()
+	markdown := ""
+	if parsed != nil {
+		markdown = results.EncodeMarkdown(parsed)
+	}
+	msgs := []notify.Message{{
+		Event:    event,
+		Title:    ui.tr(titleKey),
+		Summary:  ui.notificationSummary(statusKey, duration, parsed, report),
+		Host:     host,
+		Time:     now,
+		Markdown: markdown,
+	}}
+	if event != notify.EventCompleted || !anyChannelWants(channels, notify.EventRegression) {
+		return msgs
+	}
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return msgs
+	}
+	// 主机有基准时与基准比较，否则与上一次运行比较
+	threshold, bodyKey := scheduleRegressionThreshold, "schedule.regression_body"
+	count, err := regressedMetrics(store, host, threshold)
+	if _, rows, baselineErr := baselineRegressions(store, host, parsed, ui.regressionThreshold()); baselineErr == nil {
+		threshold, bodyKey = ui.regressionThreshold(), "baseline.regression_body"
+		count, err = len(rows), nil
+	}
+	if err == nil && count > 0 {
+		msgs = append(msgs, notify.Message{
+			Event:    notify.EventRegression,
+			Title:    fmt.Sprintf(ui.tr("notify.regression_title"), host),
+			Summary:  fmt.Sprintf(ui.tr(bodyKey), count, threshold),
+			Host:     host,
+			Time:     now,
+			Markdown: markdown,
+		})
+	}
+	return msgs
+}
+
+func anyChannelWants(channels []notify.Channel, event notify.Event) bool {
+	for _, channel := range channels {
+		if channel.Wants(event) {
+			return true
+		}
+	}
+	return false
+}
+
+// pushRunNotification 在后台把运行摘要推送到已配置的渠道，失败时在终端提示
+func (ui *TestUI) pushRunNotification(host, statusKey string, duration time.Duration, parsed *results.Report, report *StructuredRunResult) {
+	channels, err := ui.loadNotifyChannels()
+	if err != nil {
+		ui.Terminal.AppendText(fmt.Sprintf(ui.tr("notify.push_failed"), err))
+		return
+	}
+	if len(channels) == 0 {
+		return
+	}
+	msgs := ui.runNotificationMessages(host, statusKey, duration, parsed, report, channels)
+	if len(msgs) == 0 {
+		return
+	}
+	client := ui.notifyHTTPClient()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notifyPushTimeout)
+		defer cancel()
+		var errs []error
+		for _, msg := range msgs {
+			errs = append(errs, notify.Dispatch(ctx, client, channels, msg))
+		}
+		if err := errors.Join(errs...); err != nil {
+			ui.Terminal.AppendText(fmt.Sprintf(ui.tr("notify.push_failed"), err))
+		}
+	}()
+}
+
+// showNotifyChannels 显示推送渠道列表：新增、编辑、删除与发送测试消息
+func (ui *TestUI) showNotifyChannels() {
+	path := ui.appDataDir(notifyChannelsFileName)
+	channels, err := notify.Load(path)
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(channels) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(channels) {
+				obj.(*widget.Label).SetText(ui.notifyChannelText(channels[id]))
+			}
+		},
+	)
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+	save := func(next []notify.Channel) bool {
+		if err := notify.Save(path, next); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return false
+		}
+		channels = next
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+		return true
+	}
+
+	addButton := widget.NewButtonWithIcon(ui.tr("hosts.add"), theme.ContentAddIcon(), func() {
+		ui.editNotifyChannel(notify.Channel{Kind: notify.KindWebhook, Enabled: true, Events: []notify.Event{notify.EventCompleted, notify.EventFailed}}, func(channel notify.Channel) bool {
+			return save(append(append([]notify.Channel(nil), channels...), channel))
+		})
+	})
+	editButton := widget.NewButtonWithIcon(ui.tr("hosts.edit"), theme.DocumentCreateIcon(), func() {
+		if selected < 0 || selected >= len(channels) {
+			return
+		}
+		index := selected
+		ui.editNotifyChannel(channels[index], func(channel notify.Channel) bool {
+			next := append([]notify.Channel(nil), channels...)
+			next[index] = channel
+			return save(next)
+		})
+	})
+	deleteButton := widget.NewButtonWithIcon(ui.tr("history.delete"), theme.DeleteIcon(), func() {
+		if selected < 0 || selected >= len(channels) {
+			return
+		}
+		next := append(append([]notify.Channel(nil), channels[:selected]...), channels[selected+1:]...)
+		save(next)
+	})
+	testButton := widget.NewButtonWithIcon(ui.tr("notify.channel_test"), theme.MailSendIcon(), func() {
+		if selected < 0 || selected >= len(channels) {
+			return
+		}
+		channel, client := channels[selected], ui.notifyHTTPClient()
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), notifyPushTimeout)
+			defer cancel()
+			err := notify.Send(ctx, client, channel, notify.Message{
+				Event:   notify.EventCompleted,
+				Title:   ui.tr("app.title"),
+				Summary: ui.tr("notify.channel_test_body"),
+				Time:    time.Now(),
+			})
+			uiDo(func() {
+				if err != nil {
+					dialog.ShowError(err, ui.Window)
+					return
+				}
+				dialog.ShowInformation(ui.tr("notify.channels"), ui.tr("notify.channel_test_ok"), ui.Window)
+			})
+		}()
+	})
+	actions := container.NewHBox(addButton, editButton, deleteButton, testButton, layout.NewSpacer())
+	if isMobilePlatform() {
+		actions = container.NewAdaptiveGrid(2, addButton, editButton, deleteButton, testButton)
+	}
+	manager := dialog.NewCustom(ui.tr("notify.channels"), ui.tr("hosts.close"), container.NewBorder(nil, actions, nil, nil, list), ui.Window)
+	manager.Resize(fyne.NewSize(640, 420))
+	manager.Show()
+}
+
+func (ui *TestUI) notifyChannelText(channel notify.Channel) string {
+	events := make([]string, 0, len(channel.Events))
+	for _, event := range channel.Events {
+		events = append(events, ui.tr("notify.event."+string(event)))
+	}
+	parts := []string{channel.Name, ui.tr("notify.kind." + string(channel.Kind)), strings.Join(events, "/")}
+	if !channel.Enabled {
+		parts = append(parts, ui.tr("schedule.disabled"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// editNotifyChannel 新增或编辑推送渠道，onSave 返回 false 时保持表单不变
+func (ui *TestUI) editNotifyChannel(channel notify.Channel, onSave func(notify.Channel) bool) {
+	name := widget.NewEntry()
+	name.SetText(channel.Name)
+	urlEntry := widget.NewEntry()
+	urlEntry.SetText(channel.URL)
+	urlEntry.SetPlaceHolder("https://")
+	token := widget.NewPasswordEntry()
+	token.SetText(channel.Token)
+	chatID := widget.NewEntry()
+	chatID.SetText(channel.ChatID)
+
+	kindLabels := make([]string, len(notify.Kinds))
+	labelToKind := make(map[string]notify.Kind, len(notify.Kinds))
+	for i, kind := range notify.Kinds {
+		kindLabels[i] = ui.tr("notify.kind." + string(kind))
+		labelToKind[kindLabels[i]] = kind
+	}
+	kind := widget.NewSelect(kindLabels, func(label string) {
+		// 只启用所选渠道需要的字段
+		switch labelToKind[label] {
+		case notify.KindWebhook, notify.KindDiscord:
+			urlEntry.Enable()
+			token.Disable()
+			chatID.Disable()
+		case notify.KindTelegram:
+			urlEntry.Disable()
+			token.Enable()
+			chatID.Enable()
+		case notify.KindServerChan:
+			urlEntry.Disable()
+			token.Enable()
+			chatID.Disable()
+		}
+	})
+	kind.SetSelected(ui.tr("notify.kind." + string(channel.Kind)))
+
+	eventLabels := make([]string, len(notify.Events))
+	labelToEvent := make(map[string]notify.Event, len(notify.Events))
+	var chosen []string
+	for i, event := range notify.Events {
+		eventLabels[i] = ui.tr("notify.event." + string(event))
+		labelToEvent[eventLabels[i]] = event
+		for _, e := range channel.Events {
+			if e == event {
+				chosen = append(chosen, eventLabels[i])
+			}
+		}
+	}
+	events := widget.NewCheckGroup(eventLabels, nil)
+	events.Horizontal = true
+	events.SetSelected(chosen)
+	enabled := widget.NewCheck(ui.tr("schedule.enabled"), nil)
+	enabled.SetChecked(channel.Enabled)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("hosts.name"), name),
+		widget.NewFormItem(ui.tr("notify.kind"), kind),
+		widget.NewFormItem("URL", urlEntry),
+		widget.NewFormItem(ui.tr("notify.token"), token),
+		widget.NewFormItem("Chat ID", chatID),
+		widget.NewFormItem(ui.tr("notify.events"), events),
+		widget.NewFormItem("", enabled),
+	}
+	title := ui.tr("hosts.add")
+	if channel.Name != "" {
+		title = ui.tr("hosts.edit")
+	}
+	form := dialog.NewForm(title, ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		channel.Name = strings.TrimSpace(name.Text)
+		channel.Kind = labelToKind[kind.Selected]
+		channel.URL, channel.Token, channel.ChatID = "", "", ""
+		switch channel.Kind {
+		case notify.KindWebhook, notify.KindDiscord:
+			channel.URL = strings.TrimSpace(urlEntry.Text)
+		case notify.KindTelegram:
+			channel.Token, channel.ChatID = strings.TrimSpace(token.Text), strings.TrimSpace(chatID.Text)
+		case notify.KindServerChan:
+			channel.Token = strings.TrimSpace(token.Text)
+		}
+		channel.Events = channel.Events[:0:0]
+		for _, label := range events.Selected {
+			channel.Events = append(channel.Events, labelToEvent[label])
+		}
+		channel.Enabled = enabled.Checked
+		if err := channel.Validate(); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		onSave(channel)
+	}, ui.Window)
+	form.Resize(fyne.NewSize(600, 0))
+	form.Show()
+}
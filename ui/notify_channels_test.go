@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/notify"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestRunNotificationMessages(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	parsed := &results.Report{CPU: []results.CPUScore{{Label: "1 thread", Score: 850}}}
+	completed := []notify.Channel{{Name: "hook", Kind: notify.KindWebhook, URL: "https://example.com", Enabled: true, Events: []notify.Event{notify.EventCompleted}}}
+
+	msgs := ui.runNotificationMessages("vps", "status.done", time.Minute, parsed, nil, completed)
+	if len(msgs) != 1 || msgs[0].Event != notify.EventCompleted || !strings.Contains(msgs[0].Markdown, "850") || msgs[0].Host != "vps" {
+		t.Fatalf("done messages = %+v", msgs)
+	}
+	if msgs := ui.runNotificationMessages("vps", "status.failed", time.Minute, nil, nil, completed); len(msgs) != 1 || msgs[0].Event != notify.EventFailed {
+		t.Fatalf("failed messages = %+v", msgs)
+	}
+	if msgs := ui.runNotificationMessages("vps", "status.stopped", time.Minute, nil, nil, completed); len(msgs) != 0 {
+		t.Fatalf("stopped messages = %+v", msgs)
+	}
+
+	store := ui.historyStoreOrOpen()
+	base := time.Now().Add(-2 * time.Hour)
+	for i, score := range []float64{1000, 850} {
+		if _, err := store.Save(history.Run{
+			Summary: history.Summary{StartedAt: base.Add(time.Duration(i) * time.Hour), Host: "vps", Status: "done"},
+			Results: &results.Report{CPU: []results.CPUScore{{Label: "1 thread", Score: score}}},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	withRegression := append(completed, notify.Channel{Name: "tg", Kind: notify.KindTelegram, Token: "t", ChatID: "1", Enabled: true, Events: []notify.Event{notify.EventRegression}})
+	msgs = ui.runNotificationMessages("vps", "status.done", time.Minute, parsed, nil, withRegression)
+	if len(msgs) != 2 || msgs[1].Event != notify.EventRegression || msgs[1].Title != "vps: performance regression" {
+		t.Fatalf("regression messages = %+v", msgs)
+	}
+}
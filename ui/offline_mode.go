@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"slices"
+
+	"fyne.io/fyne/v2/widget"
+)
+
+// offlineNetworkOptions 是离线模式下禁用的测试项，它们都需要访问公网
+var offlineNetworkOptions = []string{"unlock", "security", "email", "backtrace", "nt3", "speed", "ping"}
+
+// applyOfflineMode 关闭所有需要公网的测试与功能：流媒体、IP 质量、测速、延迟、路由、iperf3、隧道对比与结果上传，
+// 测速节点与 IP 数据只使用内置快照，Geekbench 需要上传成绩所以跳过
+func (config *ExecutionConfig) applyOfflineMode() {
+	for _, key := range offlineNetworkOptions {
+		config.SelectedOptions[key] = false
+	}
+	config.ChinaModeEnabled = false
+	config.PingTgdc, config.PingWeb = false, false
+	config.IperfTargets, config.TunnelInterface = nil, ""
+	config.EnableUpload = false
+	config.DataOffline = true
+	config.StageRetries = 0
+	if config.CpuMethod == "geekbench" {
+		config.GeekbenchVersion = geekbenchSkip
+	}
+}
+
+// offlineNetworkChecks 返回离线模式下取消勾选并禁用的复选框
+func (ui *TestUI) offlineNetworkChecks() []*widget.Check {
+	return []*widget.Check{
+		ui.UnlockCheck, ui.SecurityCheck, ui.EmailCheck, ui.BacktraceCheck, ui.Nt3Check, ui.SpeedCheck, ui.PingCheck,
+		ui.PingTgdcCheck, ui.PingWebCheck, ui.ChinaModeCheck, ui.ResultUploadCheck,
+	}
+}
+
+// offlineModeEnabled 返回是否开启了离线模式
+func (ui *TestUI) offlineModeEnabled() bool {
+	return ui.OfflineModeCheck != nil && ui.OfflineModeCheck.Checked
+}
+
+// setOfflineMode 开启时取消勾选并禁用所有需要公网的选项，关闭时恢复可选但不重新勾选
+func (ui *TestUI) setOfflineMode(enabled bool) {
+	for _, check := range ui.offlineNetworkChecks() {
+		if check == nil {
+			continue
+		}
+		if enabled {
+			check.Checked = false
+			check.Disable()
+		} else {
+			check.Enable()
+		}
+	}
+	ui.refreshAllChecks()
+	ui.refreshSpeedTestChecks()
+}
+
+// clearOfflineChecks 在离线模式下取消预设、全选等操作勾选的网络测试项
+func (ui *TestUI) clearOfflineChecks() {
+	if !ui.offlineModeEnabled() {
+		return
+	}
+	for _, check := range ui.offlineNetworkChecks() {
+		if check != nil {
+			check.Checked = false
+		}
+	}
+}
+
+// offlineOption 返回 key 是否为离线模式下禁用的测试项
+func offlineOption(key string) bool {
+	return slices.Contains(offlineNetworkOptions, key)
+}
@@ -0,0 +1,62 @@
+package ui
+
+import "testing"
+
+func TestOfflineModeKeepsOnlyLocalTests(t *testing.T) {
+	form := executionForm{
+		language: langEN,
+		checks: map[string]bool{
+			"offlineMode": true, "cpu": true, "speed": true, "unlock": true, "security": true,
+			"chinaMode": true, "pingTgdc": true, "resultUpload": true,
+		},
+		selections: map[string]string{"cpuMethod": "geekbench"},
+		entries:    map[string]string{"iperfTargets": "10.0.0.2", "tunnelInterface": "wg0", "stageRetries": "2"},
+	}
+	config := buildExecutionConfig(form)
+	for _, key := range offlineNetworkOptions {
+		if config.SelectedOptions[key] {
+			t.Fatalf("%s should be disabled in offline mode", key)
+		}
+	}
+	if !config.SelectedOptions["cpu"] || !config.DataOffline || config.ChinaModeEnabled || config.PingTgdc || config.EnableUpload {
+		t.Fatalf("config = %+v", config)
+	}
+	if len(config.IperfTargets) != 0 || config.TunnelInterface != "" || config.StageRetries != 0 || config.GeekbenchVersion != geekbenchSkip {
+		t.Fatalf("config = %+v", config)
+	}
+
+	form.checks = map[string]bool{"offlineMode": true, "speed": true, "pingWeb": true}
+	if form.hasTests() {
+		t.Fatal("a form with only network tests has nothing to run offline")
+	}
+	form.checks["offlineMode"] = false
+	if !form.hasTests() || !buildExecutionConfig(form).SelectedOptions["speed"] {
+		t.Fatal("network tests should run when offline mode is off")
+	}
+}
+
+func TestOfflineModeCheckDisablesNetworkOptions(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.applyPreset("full")
+	ui.OfflineModeCheck.SetChecked(true)
+	for _, check := range ui.offlineNetworkChecks() {
+		if check.Checked || !check.Disabled() {
+			t.Fatalf("%q should be unticked and disabled", check.Text)
+		}
+	}
+	if !ui.CpuCheck.Checked || ui.CpuCheck.Disabled() {
+		t.Fatal("local tests should stay selected")
+	}
+	ui.setAllChecks(true)
+	if ui.SpeedCheck.Checked {
+		t.Fatal("select all should not tick network tests in offline mode")
+	}
+	if !ui.collectExecutionConfig().OfflineMode || !ui.formChecks()["offlineMode"] {
+		t.Fatal("offline mode should reach the config and settings")
+	}
+
+	ui.OfflineModeCheck.SetChecked(false)
+	if ui.SpeedCheck.Disabled() || ui.SpeedCheck.Checked {
+		t.Fatal("turning offline mode off should re-enable, not re-tick, network tests")
+	}
+}
@@ -0,0 +1,86 @@
+package ui
+
+import (
+	"context"
+	"sync"
+)
+
+// pauseGate 让测试流水线在阶段之间暂停：Pause 之后，下一次 Wait（阶段边界）会阻塞直到 Resume 或取消。
+// 正在执行的阶段不会被打断。
+type pauseGate struct {
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{}
+	// onHold 在流水线真正停在阶段边界时调用
+	onHold func()
+}
+
+type pauseGateKey struct{}
+
+func newPauseGate(onHold func()) *pauseGate {
+	return &pauseGate{onHold: onHold}
+}
+
+// withPauseGate 把暂停控制挂到执行上下文上，供各执行后端在阶段边界取用
+func withPauseGate(ctx context.Context, gate *pauseGate) context.Context {
+	return context.WithValue(ctx, pauseGateKey{}, gate)
+}
+
+func pauseGateFrom(ctx context.Context) *pauseGate {
+	if ctx == nil {
+		return nil
+	}
+	gate, _ := ctx.Value(pauseGateKey{}).(*pauseGate)
+	return gate
+}
+
+// Pause 请求在下一个阶段边界暂停，已暂停时返回 false
+func (g *pauseGate) Pause() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.paused {
+		return false
+	}
+	g.paused = true
+	g.resume = make(chan struct{})
+	return true
+}
+
+// Resume 解除暂停，已在边界等待的流水线继续执行
+func (g *pauseGate) Resume() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.paused {
+		return
+	}
+	g.paused = false
+	close(g.resume)
+}
+
+func (g *pauseGate) Paused() bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.paused
+}
+
+// Wait 在暂停期间阻塞，ctx 取消时立即返回其错误；gate 为 nil 时不阻塞
+func (g *pauseGate) Wait(ctx context.Context) error {
+	if g == nil {
+		return nil
+	}
+	g.mu.Lock()
+	paused, resume := g.paused, g.resume
+	g.mu.Unlock()
+	if !paused {
+		return nil
+	}
+	if g.onHold != nil {
+		g.onHold()
+	}
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,79 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPauseGateHoldsUntilResumeOrCancel(t *testing.T) {
+	held := make(chan struct{}, 2)
+	gate := newPauseGate(func() { held <- struct{}{} })
+	ctx := withPauseGate(context.Background(), gate)
+	if pauseGateFrom(ctx) != gate {
+		t.Fatal("pauseGateFrom() did not return the attached gate")
+	}
+	if err := gate.Wait(ctx); err != nil {
+		t.Fatalf("Wait() while running = %v", err)
+	}
+
+	if !gate.Pause() || gate.Pause() {
+		t.Fatal("Pause() should succeed once")
+	}
+	done := make(chan error, 1)
+	go func() { done <- gate.Wait(ctx) }()
+	select {
+	case <-held:
+	case <-time.After(2 * time.Second):
+		t.Fatal("onHold was not called")
+	}
+	select {
+	case <-done:
+		t.Fatal("Wait() returned while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+	gate.Resume()
+	if err := <-done; err != nil {
+		t.Fatalf("Wait() after resume = %v", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	gate.Pause()
+	go func() { done <- gate.Wait(cancelCtx) }()
+	<-held
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() after cancel = %v", err)
+	}
+
+	var nilGate *pauseGate
+	if err := nilGate.Wait(ctx); err != nil {
+		t.Fatalf("nil gate Wait() = %v", err)
+	}
+}
+
+func TestTogglePauseUpdatesControls(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.IsRunning = true
+	ui.pauseGate = newPauseGate(ui.onPipelinePaused)
+	ui.PauseButton.Enable()
+
+	ui.togglePause()
+	if !ui.pauseGate.Paused() || ui.PauseButton.Text != ui.tr("button.resume") {
+		t.Fatalf("after pause: paused=%v text=%q", ui.pauseGate.Paused(), ui.PauseButton.Text)
+	}
+	if ui.StatusLabel.Text != ui.tr("status.pause_pending") {
+		t.Fatalf("status = %q", ui.StatusLabel.Text)
+	}
+
+	ui.togglePause()
+	if ui.pauseGate.Paused() || ui.PauseButton.Text != ui.tr("button.pause") {
+		t.Fatalf("after resume: paused=%v text=%q", ui.pauseGate.Paused(), ui.PauseButton.Text)
+	}
+
+	ui.resetUIState()
+	if ui.pauseGate != nil || !ui.PauseButton.Disabled() {
+		t.Fatal("reset should drop the gate and disable the pause button")
+	}
+}
@@ -0,0 +1,42 @@
+//go:build !windows
+
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// pingCommand 构造发送 count 个探测包的 ping 命令
+func pingCommand(ctx context.Context, ip string, count int) *exec.Cmd {
+	countArg := strconv.Itoa(count)
+	if strings.Contains(ip, ":") {
+		if _, err := exec.LookPath("ping6"); err == nil {
+			return exec.CommandContext(ctx, "ping6", "-c", countArg, ip)
+		}
+	}
+	return exec.CommandContext(ctx, "ping", "-c", countArg, ip)
+}
+
+// pingTTLCommand 构造发送一个探测包、限定 TTL 的 ping 命令，ttl 为 0 时不限制；不反查主机名。
+// Linux 用 -t 指定 TTL，macOS 与 FreeBSD 的 ping 用 -m、ping6 用 -h
+func pingTTLCommand(ctx context.Context, ip string, ttl int) *exec.Cmd {
+	name := "ping"
+	if strings.Contains(ip, ":") {
+		if _, err := exec.LookPath("ping6"); err == nil {
+			name = "ping6"
+		}
+	}
+	args := []string{"-n", "-c", "1"}
+	if ttl > 0 {
+		flag := "-t"
+		if runtime.GOOS == "darwin" || runtime.GOOS == "freebsd" {
+			flag = map[string]string{"ping": "-m", "ping6": "-h"}[name]
+		}
+		args = append(args, flag, strconv.Itoa(ttl))
+	}
+	return exec.CommandContext(ctx, name, append(args, ip)...)
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package ui
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// pingCommand 构造发送 count 个探测包的 ping 命令，不弹出控制台窗口
+func pingCommand(ctx context.Context, ip string, count int) *exec.Cmd {
+	cmd := exec.CommandContext(ctx, "ping", "-n", strconv.Itoa(count), ip)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd
+}
+
+// pingTTLCommand 构造发送一个探测包、限定 TTL 的 ping 命令，ttl 为 0 时不限制；不弹出控制台窗口
+func pingTTLCommand(ctx context.Context, ip string, ttl int) *exec.Cmd {
+	args := []string{"-n", "1", "-w", "2000"}
+	if ttl > 0 {
+		args = append(args, "-i", strconv.Itoa(ttl))
+	}
+	cmd := exec.CommandContext(ctx, "ping", append(args, ip)...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true}
+	return cmd
+}
@@ -0,0 +1,137 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 可弹出到独立窗口的面板
+const (
+	popoutTerminal = "terminal"
+	popoutResults  = "results"
+)
+
+var defaultPopoutSize = fyne.NewSize(900, 600)
+
+// popoutPanel 是结果页中可弹出的面板。弹出与收回移动的是同一组控件，
+// 终端缓冲区、滚动位置、搜索与过滤状态因此保持不变；仅在 UI 线程访问。
+type popoutPanel struct {
+	name    string
+	content fyne.CanvasObject
+	slot    *fyne.Container // 停靠位置，弹出后显示占位提示
+	window  fyne.Window
+}
+
+func newPopoutPanel(name string, content fyne.CanvasObject) *popoutPanel {
+	return &popoutPanel{name: name, content: content, slot: container.NewStack(content)}
+}
+
+func (p *popoutPanel) poppedOut() bool {
+	return p.window != nil
+}
+
+// createPopoutPanels 包装终端与结果面板，返回各自的停靠位置
+func (ui *TestUI) createPopoutPanels(terminal, results fyne.CanvasObject) (fyne.CanvasObject, fyne.CanvasObject) {
+	ui.popouts = map[string]*popoutPanel{
+		popoutTerminal: newPopoutPanel(popoutTerminal, terminal),
+		popoutResults:  newPopoutPanel(popoutResults, results),
+	}
+	return ui.popouts[popoutTerminal].slot, ui.popouts[popoutResults].slot
+}
+
+func (ui *TestUI) popoutMenuItems() []*fyne.MenuItem {
+	var items []*fyne.MenuItem
+	for _, name := range []string{popoutTerminal, popoutResults} {
+		item := fyne.NewMenuItem(ui.tr("popout."+name), func() { ui.togglePopout(name) })
+		item.Checked = ui.popouts[name].poppedOut()
+		items = append(items, item)
+	}
+	return items
+}
+
+// togglePopout 弹出面板，已弹出时收回
+func (ui *TestUI) togglePopout(name string) {
+	panel := ui.popouts[name]
+	if panel == nil {
+		return
+	}
+	if panel.poppedOut() {
+		ui.dockPopout(name)
+		return
+	}
+	ui.popOut(name)
+}
+
+// popOut 把面板移入独立窗口，窗口大小沿用上次弹出时的尺寸
+func (ui *TestUI) popOut(name string) {
+	panel := ui.popouts[name]
+	if panel == nil || panel.poppedOut() || ui.App == nil {
+		return
+	}
+	dock := widget.NewButtonWithIcon(ui.tr("popout.dock"), theme.ViewRestoreIcon(), func() { ui.dockPopout(name) })
+	panel.slot.Objects = []fyne.CanvasObject{container.NewCenter(container.NewVBox(
+		widget.NewLabel(ui.tr("popout.placeholder")),
+		container.NewHBox(layout.NewSpacer(), dock, layout.NewSpacer()),
+	))}
+	panel.slot.Refresh()
+
+	win := ui.App.NewWindow(ui.tr("popout." + name))
+	win.SetContent(panel.content)
+	win.Resize(ui.popoutSize(name))
+	win.SetOnClosed(func() { ui.redock(panel) })
+	panel.window = win
+	uiLog.Debug("panel popped out", "panel", name)
+	ui.registerShortcuts()
+	win.Show()
+}
+
+// dockPopout 关闭弹出窗口并把面板放回原位
+func (ui *TestUI) dockPopout(name string) {
+	panel := ui.popouts[name]
+	if panel == nil || !panel.poppedOut() {
+		return
+	}
+	win := panel.window
+	ui.redock(panel)
+	win.Close()
+}
+
+// redock 把面板放回停靠位置并记住窗口尺寸，窗口被用户直接关闭时同样调用
+func (ui *TestUI) redock(panel *popoutPanel) {
+	win := panel.window
+	if win == nil {
+		return
+	}
+	panel.window = nil
+	uiLog.Debug("panel docked", "panel", panel.name)
+	size := win.Canvas().Size()
+	if size.Width > 0 && size.Height > 0 {
+		prefs := ui.App.Preferences()
+		prefs.SetFloat("popout_"+panel.name+"_width", float64(size.Width))
+		prefs.SetFloat("popout_"+panel.name+"_height", float64(size.Height))
+	}
+	// 先让窗口释放内容，避免同一控件同时挂在两个画布上
+	win.SetContent(widget.NewLabel(""))
+	panel.slot.Objects = []fyne.CanvasObject{panel.content}
+	panel.slot.Refresh()
+	ui.registerShortcuts()
+}
+
+func (ui *TestUI) popoutSize(name string) fyne.Size {
+	prefs := ui.App.Preferences()
+	width := prefs.FloatWithFallback("popout_"+name+"_width", float64(defaultPopoutSize.Width))
+	height := prefs.FloatWithFallback("popout_"+name+"_height", float64(defaultPopoutSize.Height))
+	return fyne.NewSize(float32(width), float32(height))
+}
+
+// closePopouts 在主窗口关闭时收回所有弹出窗口
+func (ui *TestUI) closePopouts() {
+	for _, panel := range ui.popouts {
+		if panel.poppedOut() {
+			ui.dockPopout(panel.name)
+		}
+	}
+}
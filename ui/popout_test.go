@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+)
+
+func TestPopoutMovesPanelAndRedocks(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	panel := ui.popouts[popoutTerminal]
+	ui.Terminal.SetFullText("live log")
+
+	ui.togglePopout(popoutTerminal)
+	if !panel.poppedOut() || panel.window.Content() != panel.content {
+		t.Fatal("terminal should move into its own window")
+	}
+	if panel.slot.Objects[0] == panel.content {
+		t.Fatal("the docked slot should show a placeholder while popped out")
+	}
+	if items := ui.popoutMenuItems(); len(items) != 2 || !items[0].Checked {
+		t.Fatal("menu should mark the terminal as popped out")
+	}
+	panel.window.Resize(fyne.NewSize(700, 500))
+
+	ui.togglePopout(popoutTerminal)
+	if panel.poppedOut() || panel.slot.Objects[0] != panel.content {
+		t.Fatal("terminal should return to its docked slot")
+	}
+	if got := ui.Terminal.GetText(); got != "live log" {
+		t.Fatalf("terminal text after redock = %q", got)
+	}
+	if size := ui.popoutSize(popoutTerminal); size.Width != 700 || size.Height != 500 {
+		t.Fatalf("remembered size = %v, want 700x500", size)
+	}
+}
+
+func TestPopoutWindowCloseRedocks(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	panel := ui.popouts[popoutResults]
+
+	ui.popOut(popoutResults)
+	panel.window.Close()
+	if panel.poppedOut() || panel.slot.Objects[0] != panel.content {
+		t.Fatal("closing the pop-out window should dock the results panel")
+	}
+}
@@ -0,0 +1,317 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/portscan"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	portHostPreferenceKey  = "port_scan_host"
+	portListPreferenceKey  = "port_scan_ports"
+	portScanConcurrency    = 16
+	portScanTimeout        = 3 * time.Second
+	portScanICMPProbeCount = 3
+)
+
+var portColumns = []string{"ports.col.port", "ports.col.service", "ports.col.status", "ports.col.time"}
+
+// portCheck 检测一个端口，测试中替换
+var portCheck = func(ctx context.Context, host string, port int) results.PortResult {
+	return portscan.Check(ctx, &net.Dialer{Timeout: portScanTimeout}, host, port)
+}
+
+// portTool 是端口检测工具页的状态；scan 中 Status 为空的端口仍在检测，icmpDone 为假时 ICMP 仍在检测
+type portTool struct {
+	host   *widget.Entry
+	ports  *widget.Entry
+	status *widget.Label
+	table  *widget.Table
+	run    *widget.Button
+	stop   *widget.Button
+	cancel context.CancelFunc
+	done   chan struct{} // 本轮检测结束时关闭
+
+	mu       sync.Mutex
+	scan     *results.PortScan
+	icmpDone bool
+}
+
+// createPortTab 创建端口检测工具页：从本机检测目标的常用端口与 ICMP 是否可达，找出被过滤的端口
+func (ui *TestUI) createPortTab() fyne.CanvasObject {
+	tool := &portTool{}
+	ui.ports = tool
+	tool.host = widget.NewEntry()
+	tool.host.SetPlaceHolder(ui.tr("ports.host_placeholder"))
+	tool.ports = widget.NewEntry()
+	tool.ports.SetPlaceHolder(ui.tr("ports.list_placeholder"))
+	if ui.App != nil {
+		tool.host.SetText(ui.App.Preferences().String(portHostPreferenceKey))
+		tool.ports.SetText(ui.App.Preferences().String(portListPreferenceKey))
+	}
+	tool.status = widget.NewLabel(ui.tr("ports.hint"))
+	tool.status.Wrapping = fyne.TextWrapWord
+	tool.run = widget.NewButtonWithIcon(ui.tr("latency.run"), theme.MediaPlayIcon(), ui.startPortScan)
+	tool.run.Importance = widget.HighImportance
+	tool.stop = widget.NewButtonWithIcon(ui.tr("latency.stop"), theme.MediaStopIcon(), ui.stopPortScan)
+	tool.stop.Disable()
+	exportButton := widget.NewButtonWithIcon(ui.tr("button.export"), theme.DocumentSaveIcon(), nil)
+	exportButton.OnTapped = func() { ui.showExportMenu(exportButton) }
+
+	tool.table = widget.NewTable(
+		func() (int, int) { return tool.rowCount() + 1, len(portColumns) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			label.Importance = widget.MediumImportance
+			if id.Row == 0 {
+				label.SetText(ui.tr(portColumns[id.Col]))
+				return
+			}
+			text, importance := ui.portCell(tool, id.Row-1, id.Col)
+			label.Importance = importance
+			label.SetText(text)
+		},
+	)
+	tool.table.SetColumnWidth(0, 90)
+	tool.table.SetColumnWidth(1, 120)
+	tool.table.SetColumnWidth(2, 260)
+	tool.table.SetColumnWidth(3, 110)
+
+	form := container.NewGridWithColumns(2,
+		container.NewBorder(nil, nil, widget.NewLabel(ui.tr("ports.host")), nil, tool.host),
+		container.NewBorder(nil, nil, widget.NewLabel(ui.tr("ports.list")), nil, tool.ports),
+	)
+	controls := container.NewHBox(layout.NewSpacer(), exportButton, tool.stop, tool.run)
+	top := container.NewVBox(form, controls, tool.status)
+	return container.NewBorder(top, nil, nil, nil, tool.table)
+}
+
+// rowCount 返回表格的数据行数：第一行为 ICMP，其余为各端口
+func (t *portTool) rowCount() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.scan == nil {
+		return 0
+	}
+	return len(t.scan.Ports) + 1
+}
+
+// portCell 返回表格单元格的文字与颜色：开放为绿色，拒绝连接为黄色，被过滤或 ICMP 不可达为红色
+func (ui *TestUI) portCell(tool *portTool, row, col int) (string, widget.Importance) {
+	tool.mu.Lock()
+	defer tool.mu.Unlock()
+	if tool.scan == nil || row > len(tool.scan.Ports) {
+		return "", widget.MediumImportance
+	}
+	if row == 0 {
+		icmp := tool.scan.ICMP
+		switch {
+		case col == 0:
+			return "ICMP", widget.MediumImportance
+		case col == 1:
+			return "ping", widget.MediumImportance
+		case !tool.icmpDone || icmp == nil:
+			return "…", widget.LowImportance
+		case icmp.Received == 0:
+			if col == 2 {
+				return ui.tr("ports.icmp_unreachable"), widget.DangerImportance
+			}
+			return "-", widget.LowImportance
+		case col == 2:
+			return fmt.Sprintf(ui.tr("ports.icmp_reachable"), strconv.FormatFloat(icmp.LossPercent(), 'f', -1, 64)), widget.SuccessImportance
+		}
+		return fmt.Sprintf("%.2f", icmp.AvgMs), widget.MediumImportance
+	}
+	port := tool.scan.Ports[row-1]
+	switch col {
+	case 0:
+		return strconv.Itoa(port.Port), widget.MediumImportance
+	case 1:
+		return port.Service, widget.MediumImportance
+	}
+	switch port.Status {
+	case "":
+		return "…", widget.LowImportance
+	case results.PortOpen:
+		if col == 2 {
+			return ui.tr("ports.status.open"), widget.SuccessImportance
+		}
+		return fmt.Sprintf("%.2f", port.LatencyMs), widget.MediumImportance
+	case results.PortClosed:
+		if col == 2 {
+			return ui.tr("ports.status.closed"), widget.WarningImportance
+		}
+	default:
+		if col == 2 {
+			return ui.tr("ports.status.filtered"), widget.DangerImportance
+		}
+	}
+	return "-", widget.LowImportance
+}
+
+// results 返回已完成的检测结果，供导出合并；尚未检测时为 nil
+func (t *portTool) results() *results.PortScan {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.scan == nil {
+		return nil
+	}
+	scan := results.PortScan{Target: t.scan.Target}
+	if t.icmpDone && t.scan.ICMP != nil {
+		icmp := *t.scan.ICMP
+		scan.ICMP = &icmp
+	}
+	for _, port := range t.scan.Ports {
+		if port.Status != "" {
+			scan.Ports = append(scan.Ports, port)
+		}
+	}
+	if scan.ICMP == nil && len(scan.Ports) == 0 {
+		return nil
+	}
+	return &scan
+}
+
+// startPortScan 检查目标与端口列表，随后并发检测各端口并同时 ping 目标，每完成一项刷新一次表格
+func (ui *TestUI) startPortScan() {
+	tool := ui.ports
+	target, err := latency.ParseTarget(tool.host.Text)
+	if err == nil && target.Port != 0 {
+		err = fmt.Errorf(ui.tr("ports.host_with_port"), tool.host.Text)
+	}
+	if err != nil {
+		if strings.TrimSpace(tool.host.Text) == "" {
+			dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("ports.no_host"), ui.Window)
+			return
+		}
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	ports, err := portscan.ParsePorts(tool.ports.Text)
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	if ui.App != nil {
+		ui.App.Preferences().SetString(portHostPreferenceKey, tool.host.Text)
+		ui.App.Preferences().SetString(portListPreferenceKey, tool.ports.Text)
+	}
+
+	tool.mu.Lock()
+	tool.scan = &results.PortScan{Target: target.Host, Ports: make([]results.PortResult, len(ports))}
+	for i, port := range ports {
+		tool.scan.Ports[i] = results.PortResult{Port: port, Service: portscan.Service(port)}
+	}
+	tool.icmpDone = false
+	tool.mu.Unlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	tool.cancel = cancel
+	tool.run.Disable()
+	tool.stop.Enable()
+	total := len(ports) + 1
+	tool.status.SetText(fmt.Sprintf(ui.tr("latency.progress"), 0, total))
+	tool.table.Refresh()
+
+	done := make(chan struct{})
+	tool.done = done
+	go func() {
+		defer close(done)
+		defer cancel()
+		var finished sync.WaitGroup
+		var mu sync.Mutex
+		count := 0
+		progress := func(update func()) {
+			mu.Lock()
+			count++
+			text := fmt.Sprintf(ui.tr("latency.progress"), count, total)
+			mu.Unlock()
+			ui.runOnUI(func() {
+				tool.mu.Lock()
+				update()
+				tool.mu.Unlock()
+				tool.status.SetText(text)
+				tool.table.Refresh()
+			})
+		}
+		finished.Add(1)
+		go func() {
+			defer finished.Done()
+			icmp := latencyProbe(ctx, target, portScanICMPProbeCount)
+			if ctx.Err() == nil {
+				progress(func() { tool.scan.ICMP, tool.icmpDone = &icmp, true })
+			}
+		}()
+		sem := make(chan struct{}, portScanConcurrency)
+		for i, port := range ports {
+			finished.Add(1)
+			go func() {
+				defer finished.Done()
+				select {
+				case sem <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				defer func() { <-sem }()
+				result := portCheck(ctx, target.Host, port)
+				if ctx.Err() == nil {
+					progress(func() { tool.scan.Ports[i] = result })
+				}
+			}()
+		}
+		finished.Wait()
+		stopped := ctx.Err() != nil
+		ui.runOnUI(func() {
+			tool.run.Enable()
+			tool.stop.Disable()
+			if stopped {
+				mu.Lock()
+				tool.status.SetText(fmt.Sprintf(ui.tr("latency.stopped"), count, total))
+				mu.Unlock()
+				return
+			}
+			tool.status.SetText(ui.portScanSummary(tool.results()))
+		})
+	}()
+}
+
+// portScanSummary 概括检测结果，列出被过滤的端口
+func (ui *TestUI) portScanSummary(scan *results.PortScan) string {
+	if scan == nil {
+		return ""
+	}
+	blocked := scan.Blocked()
+	if len(blocked) == 0 {
+		return ui.tr("ports.summary_ok")
+	}
+	names := make([]string, len(blocked))
+	for i, port := range blocked {
+		names[i] = strconv.Itoa(port)
+	}
+	return fmt.Sprintf(ui.tr("ports.summary_blocked"), len(blocked), strings.Join(names, ", "))
+}
+
+func (ui *TestUI) stopPortScan() {
+	if tool := ui.ports; tool != nil && tool.cancel != nil {
+		tool.cancel()
+		tool.stop.Disable()
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestPortToolScansAndExports(t *testing.T) {
+	oldCheck, oldProbe := portCheck, latencyProbe
+	t.Cleanup(func() { portCheck, latencyProbe = oldCheck, oldProbe })
+	portCheck = func(ctx context.Context, host string, port int) results.PortResult {
+		result := results.PortResult{Port: port, Status: results.PortOpen, LatencyMs: 12}
+		switch port {
+		case 25:
+			result.Status, result.LatencyMs, result.Error = results.PortFiltered, 0, "i/o timeout"
+		case 3389:
+			result.Status, result.LatencyMs, result.Error = results.PortClosed, 0, "connection refused"
+		}
+		return result
+	}
+	latencyProbe = func(ctx context.Context, target latency.Target, count int) results.LatencyResult {
+		return latency.Summarize(target, count, []float64{10, 11, 12})
+	}
+
+	ui := newTestUIForTest(t)
+	tool := ui.ports
+	tool.host.SetText("203.0.113.7")
+	tool.ports.SetText("3389, 25 22")
+	ui.startPortScan()
+
+	select {
+	case <-tool.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("port scan did not finish")
+	}
+	scan := tool.results()
+	if scan == nil || scan.Target != "203.0.113.7" || scan.ICMP == nil || len(scan.Ports) != 3 {
+		t.Fatalf("results() = %+v", scan)
+	}
+	if scan.Ports[0].Port != 22 || scan.Ports[1].Port != 25 || scan.Ports[2].Port != 3389 {
+		t.Fatalf("ports not sorted: %+v", scan.Ports)
+	}
+	if text, importance := ui.portCell(tool, 2, 2); text != ui.tr("ports.status.filtered") || importance != widget.DangerImportance {
+		t.Fatalf("filtered cell = %q, %v", text, importance)
+	}
+	if text, importance := ui.portCell(tool, 0, 2); importance != widget.SuccessImportance || !strings.Contains(text, "0") {
+		t.Fatalf("icmp cell = %q, %v", text, importance)
+	}
+	if !strings.Contains(tool.status.Text, "25") {
+		t.Fatalf("summary = %q, want blocked port 25", tool.status.Text)
+	}
+
+	source := ui.currentExportSource()
+	if source.report.PortScan == nil || len(source.report.PortScan.Blocked()) != 1 {
+		t.Fatalf("export port scan = %+v", source.report.PortScan)
+	}
+}
+
+func TestPortToolRejectsHostWithPort(t *testing.T) {
+	called := false
+	oldCheck := portCheck
+	portCheck = func(ctx context.Context, host string, port int) results.PortResult {
+		called = true
+		return results.PortResult{}
+	}
+	t.Cleanup(func() { portCheck = oldCheck })
+
+	ui := newTestUIForTest(t)
+	ui.ports.host.SetText("example.com:22")
+	ui.startPortScan()
+	if called || ui.ports.results() != nil {
+		t.Fatal("host with a port should be rejected before scanning")
+	}
+}
@@ -0,0 +1,131 @@
+package ui
+
+import (
+	"os"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/redact"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const privacyModePreferenceKey = "privacy_mode"
+
+// privacyEnabled 返回是否开启隐私模式
+func (ui *TestUI) privacyEnabled() bool {
+	return ui.App != nil && ui.App.Preferences().Bool(privacyModePreferenceKey)
+}
+
+// redactor 返回隐私模式的脱敏函数，关闭时返回 nil
+func (ui *TestUI) redactor(hosts ...string) func(string) string {
+	if !ui.privacyEnabled() {
+		return nil
+	}
+	return ui.hostRedactor(hosts...)
+}
+
+// hostRedactor 返回脱敏函数：本机名与最近一次测试的目标主机总会被隐去，hosts 为额外的主机名
+func (ui *TestUI) hostRedactor(hosts ...string) func(string) string {
+	local, _ := os.Hostname()
+	return redact.New(append(hosts, local, ui.lastHost())...).Text
+}
+
+// redactExport 在隐私模式下对导出内容脱敏
+func (ui *TestUI) redactExport(text string, hosts ...string) string {
+	if r := ui.redactor(hosts...); r != nil {
+		return r(text)
+	}
+	return text
+}
+
+// redactSource 在隐私模式下返回脱敏后的导出内容，结构化结果会复制一份再修改
+func (ui *TestUI) redactSource(source exportSource) exportSource {
+	r := ui.redactor()
+	if r == nil {
+		return source
+	}
+	source.content = r(source.content)
+	source.report = redactReport(source.report, r)
+	return source
+}
+
+// redactReport 复制报告并隐去其中的 ASN 组织名、系统信息与 IP 质量字段、目标地址以及原文分区中的 IP 与主机名
+func redactReport(report *results.Report, r func(string) string) *results.Report {
+	if report == nil {
+		return nil
+	}
+	masked := *report
+	masked.ASN = r(redact.Value("ASN", report.ASN))
+	masked.System = append([]results.InfoField(nil), report.System...)
+	for i, field := range masked.System {
+		masked.System[i].Value = r(redact.Value(field.Name, field.Value))
+	}
+	masked.IPQuality = append([]results.IPQualityField(nil), report.IPQuality...)
+	for i, field := range masked.IPQuality {
+		masked.IPQuality[i].Value = r(redact.Value(field.Name, field.Value))
+	}
+	masked.Backtrace = append([]results.BacktraceResult(nil), report.Backtrace...)
+	for i := range masked.Backtrace {
+		masked.Backtrace[i].Target = r(masked.Backtrace[i].Target)
+	}
+	masked.Routes = append([]results.RoutePath(nil), report.Routes...)
+	for i := range masked.Routes {
+		masked.Routes[i].Target = r(masked.Routes[i].Target)
+	}
+	masked.Latency = append([]results.LatencyResult(nil), report.Latency...)
+	for i := range masked.Latency {
+		masked.Latency[i].Target = r(masked.Latency[i].Target)
+	}
+	if report.PortScan != nil {
+		scan := *report.PortScan
+		scan.Target = r(scan.Target)
+		scan.Ports = append([]results.PortResult(nil), scan.Ports...)
+		for i := range scan.Ports {
+			scan.Ports[i].Error = r(scan.Ports[i].Error)
+		}
+		if scan.ICMP != nil {
+			icmp := *scan.ICMP
+			icmp.Target, icmp.Error = r(icmp.Target), r(icmp.Error)
+			scan.ICMP = &icmp
+		}
+		masked.PortScan = &scan
+	}
+	if report.Monitor != nil {
+		monitor := *report.Monitor
+		monitor.Target = r(monitor.Target)
+		monitor.Hops = append([]results.MonitorHop(nil), monitor.Hops...)
+		for i := range monitor.Hops {
+			monitor.Hops[i].Host = r(monitor.Hops[i].Host)
+		}
+		masked.Monitor = &monitor
+	}
+	masked.Raw = append([]results.RawSection(nil), report.Raw...)
+	for i := range masked.Raw {
+		masked.Raw[i].Lines = append([]string(nil), masked.Raw[i].Lines...)
+		for j, line := range masked.Raw[i].Lines {
+			masked.Raw[i].Lines[j] = r(line)
+		}
+	}
+	masked.Annotations = append([]results.Annotation(nil), report.Annotations...)
+	for i := range masked.Annotations {
+		masked.Annotations[i].Text = r(masked.Annotations[i].Text)
+		masked.Annotations[i].Note = r(masked.Annotations[i].Note)
+	}
+	return &masked
+}
+
+// applyPrivacy 按当前设置更新终端的脱敏显示
+func (ui *TestUI) applyPrivacy() {
+	if ui.Terminal != nil {
+		ui.Terminal.SetRedact(ui.redactor())
+	}
+}
+
+// createPrivacyCheck 创建隐私模式开关：开启后终端显示与所有导出都会隐去公网 IP、主机名与 ASN 组织名
+func (ui *TestUI) createPrivacyCheck() *widget.Check {
+	check := widget.NewCheck(ui.tr("privacy.mode"), func(on bool) {
+		ui.App.Preferences().SetBool(privacyModePreferenceKey, on)
+		ui.applyPrivacy()
+	})
+	check.Checked = ui.privacyEnabled()
+	return check
+}
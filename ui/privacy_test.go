@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+	"github.com/oneclickvirt/ecs-gui/redact"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestTerminalRedactMasksDisplayOnly(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.SetFullText("\x1b[32m IPV4 ASN : AS906 DMIT Cloud Services\x1b[0m\n\x1b[1m公网 IP: 203.0.113.7\x1b[0m\nCPU 1234")
+	terminal.SetRedact(redact.New().Text)
+	terminal.sync()
+
+	if got := terminal.line(0).plain; got != " IPV4 ASN : AS906 ***" {
+		t.Fatalf("line 0 = %q", got)
+	}
+	if got := terminal.line(1); got.plain != "公网 IP: 203.0.113.*" || !strings.HasPrefix(got.raw, "\x1b[1m") {
+		t.Fatalf("line 1 = %+v, want masked with colours kept", got)
+	}
+	if !strings.Contains(terminal.GetText(), "203.0.113.7") {
+		t.Fatal("buffer should keep the original output")
+	}
+	terminal.SetRedact(nil)
+	terminal.sync()
+	if got := terminal.line(1).plain; got != "公网 IP: 203.0.113.7" {
+		t.Fatalf("line 1 after disabling = %q", got)
+	}
+}
+
+func TestRedactSourceMasksExports(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	source := exportSource{
+		content: " IPV4 ASN : AS906 DMIT Cloud Services\n IPV4 地址: 203.0.113.7\n",
+		report: &results.Report{
+			ASN:       "AS906 DMIT Cloud Services",
+			IPQuality: []results.IPQualityField{{Name: "组织", Value: "DMIT"}, {Name: "使用类型", Value: "hosting"}},
+			Latency:   []results.LatencyResult{{Target: "203.0.113.9"}},
+			Raw:       []results.RawSection{{Title: "自定义分区", Lines: []string{" 出口 IP: 198.51.100.23"}}},
+		},
+	}
+	if got := ui.redactSource(source); got.content != source.content || got.report != source.report {
+		t.Fatal("exports should be unchanged when privacy mode is off")
+	}
+
+	ui.App.Preferences().SetBool(privacyModePreferenceKey, true)
+	got := ui.redactSource(source)
+	if got.content != " IPV4 ASN : AS906 ***\n IPV4 地址: 203.0.113.*\n" {
+		t.Fatalf("content = %q", got.content)
+	}
+	if got.report.ASN != "AS906 ***" || got.report.IPQuality[0].Value != "***" || got.report.IPQuality[1].Value != "hosting" || got.report.Latency[0].Target != "203.0.113.*" {
+		t.Fatalf("report = %+v", got.report)
+	}
+	if got.report.Raw[0].Lines[0] != " 出口 IP: 198.51.100.*" || got.report.Raw[0].Title != "自定义分区" {
+		t.Fatalf("raw = %+v", got.report.Raw)
+	}
+	if source.report.Raw[0].Lines[0] != " 出口 IP: 198.51.100.23" {
+		t.Fatal("redactSource modified the original raw section")
+	}
+	if source.report.ASN != "AS906 DMIT Cloud Services" || source.report.IPQuality[0].Value != "DMIT" || source.report.Latency[0].Target != "203.0.113.9" {
+		t.Fatal("redactSource modified the original report")
+	}
+}
@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// privilegedTest is a test item that needs Administrator/root privileges on this platform.
+type privilegedTest struct {
+	key string
+	zh  string
+	en  string
+}
+
+// privilegedTestsFor returns the selected tests that need elevated privileges.
+func privilegedTestsFor(config ExecutionConfig) []privilegedTest {
+	var tests []privilegedTest
+	for _, test := range privilegedTests() {
+		if config.SelectedOptions[test.key] {
+			tests = append(tests, test)
+		}
+	}
+	return tests
+}
+
+// needsPrivilege reports whether the given config requires elevated privileges,
+// and returns a human-readable list of affected tests.
+func needsPrivilege(config ExecutionConfig) (needs bool, testsZH string, testsEN string) {
+	tests := privilegedTestsFor(config)
+	if len(tests) == 0 {
+		return false, "", ""
+	}
+	zh := make([]string, 0, len(tests))
+	en := make([]string, 0, len(tests))
+	for _, test := range tests {
+		zh = append(zh, test.zh)
+		en = append(en, test.en)
+	}
+	return true, joinStrings(zh, "、"), joinStrings(en, ", ")
+}
+
+// withoutPrivilegedTests returns a copy of config with the tests that need elevation deselected.
+func withoutPrivilegedTests(config ExecutionConfig) ExecutionConfig {
+	selected := maps.Clone(config.SelectedOptions)
+	for _, test := range privilegedTestsFor(config) {
+		selected[test.key] = false
+	}
+	config.SelectedOptions = selected
+	return config
+}
+
+// confirmPrivileges 在本机运行且缺少管理员/root 权限时说明受影响的测试项，
+// 可以提权重新启动、去掉这些测试项继续，或调用 cancel 取消本次运行
+func (ui *TestUI) confirmPrivileges(config ExecutionConfig, next func(ExecutionConfig), cancel func()) {
+	needs, testsZH, testsEN := needsPrivilege(config)
+	if !config.local() || !needs || isPrivileged() {
+		next(config)
+		return
+	}
+	tests := testsZH
+	if config.Language == "en" {
+		tests = testsEN
+	}
+	body := widget.NewLabel(fmt.Sprintf(ui.tr("dialog.no_privilege_body"), tests))
+	body.Wrapping = fyne.TextWrapWord
+	var prompt dialog.Dialog
+	relaunch := widget.NewButtonWithIcon(ui.tr("button.relaunch_elevated"), theme.ConfirmIcon(), func() {
+		prompt.Hide()
+		cancel()
+		_ = ui.saveSettings()
+		ui.relaunchElevated()
+	})
+	relaunch.Importance = widget.HighImportance
+	reduced := widget.NewButton(ui.tr("button.skip_privileged"), func() {
+		prompt.Hide()
+		config = withoutPrivilegedTests(config)
+		if !slices.Contains(slices.Collect(maps.Values(config.SelectedOptions)), true) && !config.PingTgdc && !config.PingWeb {
+			cancel()
+			dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_tests"), ui.Window)
+			return
+		}
+		next(config)
+	})
+	cancelButton := widget.NewButton(ui.tr("button.cancel"), func() {
+		prompt.Hide()
+		cancel()
+	})
+	content := container.NewVBox(body, container.NewHBox(layout.NewSpacer(), cancelButton, reduced, relaunch))
+	prompt = dialog.NewCustomWithoutButtons(ui.tr("dialog.no_privilege_title"), content, ui.Window)
+	prompt.Resize(fyne.NewSize(560, 0))
+	prompt.Show()
+}
+
+// restartElevated 与 quitApp 可在测试中替换
+var (
+	restartElevated = requestPrivilegeRestart
+	quitApp         = func(app fyne.App) { app.Quit() }
+)
+
+// relaunchElevated 在后台请求提权启动（UAC、pkexec 或 osascript 会等待用户输入密码）。
+// 提权副本启动后退出当前程序，避免留下两个窗口；失败时提示原因并保留当前窗口
+func (ui *TestUI) relaunchElevated() {
+	go func() {
+		err := restartElevated()
+		ui.runOnUI(func() {
+			if err != nil {
+				dialog.ShowInformation(ui.tr("dialog.no_privilege_title"), ui.tr("dialog.uac_failed")+"\n"+err.Error(), ui.Window)
+				return
+			}
+			quitApp(ui.App)
+		})
+	}()
+}
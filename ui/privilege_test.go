@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+)
+
+func TestWithoutPrivilegedTestsKeepsOtherSelections(t *testing.T) {
+	selected := map[string]bool{"basic": true, "cpu": true}
+	for _, test := range privilegedTests() {
+		selected[test.key] = true
+	}
+	config := ExecutionConfig{SelectedOptions: selected}
+	needs, _, en := needsPrivilege(config)
+	if len(privilegedTests()) > 0 && (!needs || en == "") {
+		t.Fatalf("needsPrivilege() = %v, %q", needs, en)
+	}
+
+	reduced := withoutPrivilegedTests(config)
+	if needs, _, _ := needsPrivilege(reduced); needs {
+		t.Fatalf("reduced config still needs privileges: %v", reduced.SelectedOptions)
+	}
+	if !reduced.SelectedOptions["basic"] || !reduced.SelectedOptions["cpu"] {
+		t.Fatalf("reduced config dropped unrelated tests: %v", reduced.SelectedOptions)
+	}
+	for _, test := range privilegedTests() {
+		if !config.SelectedOptions[test.key] {
+			t.Fatal("withoutPrivilegedTests modified the original selection")
+		}
+	}
+}
+
+func TestRelaunchElevatedQuitsOnlyAfterChildStarted(t *testing.T) {
+	ui := newTestUIForTest(t)
+	restoreRestart, restoreQuit := restartElevated, quitApp
+	t.Cleanup(func() { restartElevated, quitApp = restoreRestart, restoreQuit })
+	quit := make(chan struct{}, 1)
+	quitApp = func(fyne.App) { quit <- struct{}{} }
+
+	restartElevated = func() error { return errors.New("cancelled") }
+	ui.relaunchElevated()
+	select {
+	case <-quit:
+		t.Fatal("app quit after a failed relaunch")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	restartElevated = func() error { return nil }
+	ui.relaunchElevated()
+	select {
+	case <-quit:
+	case <-time.After(5 * time.Second):
+		t.Fatal("app did not quit after the elevated copy started")
+	}
+}
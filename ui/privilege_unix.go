@@ -3,10 +3,18 @@
 package ui
 
 import (
+	"errors"
 	"os"
+	"os/exec"
 	"runtime"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
 )
 
+// errNoElevationTool is returned when neither pkexec nor osascript can be used.
+var errNoElevationTool = errors.New("pkexec is not available")
+
 // isPrivileged returns true when running as root (uid 0) on Unix-like systems.
 // On mobile platforms (Android/iOS) this check is skipped (returns true).
 func isPrivileged() bool {
@@ -18,32 +26,57 @@ func isPrivileged() bool {
 	}
 }
 
+// requestPrivilegeRestart relaunches the app as root in the background: osascript on
+// macOS, pkexec on Linux and BSD. It blocks until the password prompt is answered and
+// returns an error when the prompt was cancelled or no launcher is available.
 func requestPrivilegeRestart() error {
-	return os.ErrPermission
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	name, args, err := elevationCommand(runtime.GOOS, exe, os.Args[1:], os.Getenv, exec.LookPath)
+	if err != nil {
+		return err
+	}
+	return exec.Command(name, args...).Run()
 }
 
-// needsPrivilege reports whether the given config requires elevated privileges,
-// and returns a human-readable list of affected tests.
-func needsPrivilege(config ExecutionConfig) (needs bool, testsZH string, testsEN string) {
-	switch runtime.GOOS {
-	case "android", "ios":
-		return false, "", ""
+// elevationCommand builds the launcher command. The elevated copy is started detached
+// so the launcher returns as soon as authentication succeeds.
+func elevationCommand(goos, exe string, args []string, getenv func(string) string, lookPath func(string) (string, error)) (string, []string, error) {
+	quoted := make([]string, 0, len(args)+1)
+	for _, arg := range append([]string{exe}, args...) {
+		quoted = append(quoted, remote.Quote(arg))
 	}
-
-	var zh, en []string
-
-	// Route tracing requires raw ICMP sockets → root or CAP_NET_RAW.
-	if config.SelectedOptions["nt3"] {
-		zh = append(zh, "三网回程路由检测")
-		en = append(en, "3-Net Route Trace")
+	command := strings.Join(quoted, " ")
+	if goos == "darwin" {
+		script := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(command + " >/dev/null 2>&1 &")
+		return "osascript", []string{"-e", `do shell script "` + script + `" with administrator privileges`}, nil
+	}
+	pkexec, err := lookPath("pkexec")
+	if err != nil {
+		return "", nil, errNoElevationTool
 	}
-	if config.SelectedOptions["backtrace"] {
-		zh = append(zh, "上游及回程线路检测")
-		en = append(en, "Upstream & Backtrace")
+	// pkexec clears the environment; keep what a GUI app needs to reach the user's display.
+	launcher := []string{"env"}
+	for _, key := range []string{"DISPLAY", "XAUTHORITY", "WAYLAND_DISPLAY", "XDG_RUNTIME_DIR", "LANG"} {
+		if value := getenv(key); value != "" {
+			launcher = append(launcher, key+"="+value)
+		}
 	}
+	launcher = append(launcher, "sh", "-c", `nohup "$0" "$@" >/dev/null 2>&1 &`, exe)
+	return pkexec, append(launcher, args...), nil
+}
 
-	if len(zh) == 0 {
-		return false, "", ""
+// privilegedTests lists the tests that need root on this platform.
+func privilegedTests() []privilegedTest {
+	switch runtime.GOOS {
+	case "android", "ios":
+		return nil
+	}
+	// Route tracing requires raw ICMP sockets → root or CAP_NET_RAW.
+	return []privilegedTest{
+		{key: "nt3", zh: "三网回程路由检测", en: "3-Net Route Trace"},
+		{key: "backtrace", zh: "上游及回程线路检测", en: "Upstream & Backtrace"},
 	}
-	return true, joinStrings(zh, "、"), joinStrings(en, ", ")
 }
@@ -0,0 +1,35 @@
+//go:build !windows
+
+package ui
+
+import (
+	"errors"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestElevationCommand(t *testing.T) {
+	env := map[string]string{"DISPLAY": ":0", "XAUTHORITY": "/home/u/.Xauthority"}
+	found := func(name string) (string, error) { return "/usr/bin/" + name, nil }
+
+	name, args, err := elevationCommand("linux", "/opt/ecs gui/ecs-gui", []string{"-tests", "cpu"}, func(k string) string { return env[k] }, found)
+	if err != nil || name != "/usr/bin/pkexec" {
+		t.Fatalf("linux: %q %v %v", name, args, err)
+	}
+	if !slices.Contains(args, "DISPLAY=:0") || !slices.Equal(args[len(args)-3:], []string{"/opt/ecs gui/ecs-gui", "-tests", "cpu"}) {
+		t.Fatalf("linux args = %q", args)
+	}
+
+	missing := func(string) (string, error) { return "", exec.ErrNotFound }
+	if _, _, err := elevationCommand("linux", "/usr/bin/ecs-gui", nil, func(string) string { return "" }, missing); !errors.Is(err, errNoElevationTool) {
+		t.Fatalf("linux without pkexec = %v", err)
+	}
+
+	name, args, err = elevationCommand("darwin", `/Applications/ECS "GUI".app/Contents/MacOS/ecs-gui`, nil, nil, missing)
+	script := strings.Join(args, " ")
+	if err != nil || name != "osascript" || !strings.Contains(script, `'/Applications/ECS \"GUI\".app/Contents/MacOS/ecs-gui'`) || !strings.HasSuffix(script, "with administrator privileges") {
+		t.Fatalf("darwin: %q %q %v", name, script, err)
+	}
+}
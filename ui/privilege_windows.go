@@ -4,7 +4,6 @@ package ui
 
 import (
 	"os"
-	"runtime"
 	"strings"
 
 	"golang.org/x/sys/windows"
@@ -64,29 +63,13 @@ func shellArgumentString(args []string) string {
 	return strings.Join(escaped, " ")
 }
 
-// needsPrivilege reports whether the given config requires elevated privileges,
-// and returns a human-readable list of affected tests.
-func needsPrivilege(config ExecutionConfig) (needs bool, testsZH string, testsEN string) {
-	_ = runtime.GOOS // windows
-	var zh, en []string
-
-	// Disk test on Windows always uses winsat which requires Administrator.
-	if config.SelectedOptions["disk"] {
-		zh = append(zh, "磁盘测试 (winsat)")
-		en = append(en, "Disk Test (winsat)")
-	}
-	// Route tracing (nt3 / backtrace) requires raw ICMP sockets → Administrator.
-	if config.SelectedOptions["nt3"] {
-		zh = append(zh, "三网回程路由检测")
-		en = append(en, "3-Net Route Trace")
-	}
-	if config.SelectedOptions["backtrace"] {
-		zh = append(zh, "上游及回程线路检测")
-		en = append(en, "Upstream & Backtrace")
-	}
-
-	if len(zh) == 0 {
-		return false, "", ""
+// privilegedTests lists the tests that need Administrator on Windows.
+func privilegedTests() []privilegedTest {
+	return []privilegedTest{
+		// Disk test on Windows always uses winsat which requires Administrator.
+		{key: "disk", zh: "磁盘测试 (winsat)", en: "Disk Test (winsat)"},
+		// Route tracing (nt3 / backtrace) requires raw ICMP sockets → Administrator.
+		{key: "nt3", zh: "三网回程路由检测", en: "3-Net Route Trace"},
+		{key: "backtrace", zh: "上游及回程线路检测", en: "Upstream & Backtrace"},
 	}
-	return true, joinStrings(zh, "、"), joinStrings(en, ", ")
 }
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/paste"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+)
+
+const (
+	proxySettingsFileName = "proxy.json"
+	resultUploadTimeout   = 30 * time.Second
+)
+
+// 组件覆盖方式：跟随全局、直连或单独指定代理
+const (
+	proxyModeInherit = "inherit"
+	proxyModeDirect  = "direct"
+	proxyModeCustom  = "custom"
+)
+
+// failingTransport 让设置有误的组件请求直接失败，而不是绕过代理直连
+type failingTransport struct{ err error }
+
+func (t failingTransport) RoundTrip(*http.Request) (*http.Response, error) { return nil, t.err }
+
+// proxyHTTPClient 返回组件按代理设置发起请求的客户端，timeout 为 0 时不限时
+func proxyHTTPClient(settings proxy.Settings, component proxy.Component, timeout time.Duration) *http.Client {
+	client, err := settings.Client(component, timeout)
+	if err != nil {
+		return &http.Client{Transport: failingTransport{err}, Timeout: timeout}
+	}
+	return client
+}
+
+// uploadThroughProxy 按代理设置由界面上传结果，返回与 goecs 上传时一致的终端输出。
+// goecs 内置的上传客户端只读取环境变量，无法单独指定代理。
+func uploadThroughProxy(ctx context.Context, settings proxy.Settings, language, filePath, output string) string {
+	name := filepath.Base(filePath)
+	if filePath == "" {
+		name = "goecs.txt"
+	}
+	link, err := paste.Upload(ctx, proxyHTTPClient(settings, proxy.Uploads, resultUploadTimeout), name, output)
+	if err != nil {
+		if language == "en" {
+			return "Upload failed, unable to generate link\n" + err.Error() + "\n"
+		}
+		return "上传失败，无法生成链接\n" + err.Error() + "\n"
+	}
+	return link.Message(language)
+}
+
+// proxySettings 读取代理设置，文件损坏时按未设置处理
+func (ui *TestUI) proxySettings() proxy.Settings {
+	settings, _ := proxy.Load(ui.appDataDir(proxySettingsFileName))
+	return settings
+}
+
+// showProxySettings 编辑全局代理以及下载、上传、推送各自的覆盖方式
+func (ui *TestUI) showProxySettings() {
+	path := ui.appDataDir(proxySettingsFileName)
+	settings, err := proxy.Load(path)
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	global := widget.NewEntry()
+	global.SetPlaceHolder(ui.tr("placeholder.proxy"))
+	global.SetText(settings.URL)
+	items := []*widget.FormItem{widget.NewFormItem(ui.tr("proxy.global"), global)}
+
+	modes := []string{proxyModeInherit, proxyModeDirect, proxyModeCustom}
+	labels := make([]string, len(modes))
+	labelToMode := make(map[string]string, len(modes))
+	for i, mode := range modes {
+		labels[i] = ui.tr("proxy.mode." + mode)
+		labelToMode[labels[i]] = mode
+	}
+	selects := make(map[proxy.Component]*widget.Select, len(proxy.Components))
+	entries := make(map[proxy.Component]*widget.Entry, len(proxy.Components))
+	for _, component := range proxy.Components {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(ui.tr("placeholder.proxy"))
+		mode := widget.NewSelect(labels, func(label string) {
+			if labelToMode[label] == proxyModeCustom {
+				entry.Enable()
+			} else {
+				entry.Disable()
+			}
+		})
+		switch value := strings.TrimSpace(settings.Overrides[component]); value {
+		case "":
+			mode.SetSelected(labels[0])
+		case proxy.Direct:
+			mode.SetSelected(labels[1])
+		default:
+			entry.SetText(value)
+			mode.SetSelected(labels[2])
+		}
+		selects[component], entries[component] = mode, entry
+		items = append(items, widget.NewFormItem(ui.tr("proxy.component."+string(component)),
+			container.NewGridWithColumns(2, mode, entry)))
+	}
+	hint := widget.NewLabel(ui.tr("proxy.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	items = append(items, widget.NewFormItem("", hint))
+
+	form := dialog.NewForm(ui.tr("proxy.settings"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		next := proxy.Settings{URL: strings.TrimSpace(global.Text), Overrides: map[proxy.Component]string{}}
+		for _, component := range proxy.Components {
+			switch labelToMode[selects[component].Selected] {
+			case proxyModeDirect:
+				next.Overrides[component] = proxy.Direct
+			case proxyModeCustom:
+				next.Overrides[component] = strings.TrimSpace(entries[component].Text)
+			}
+		}
+		if err := proxy.Save(path, next); err != nil {
+			dialog.ShowError(err, ui.Window)
+		}
+	}, ui.Window)
+	form.Resize(fyne.NewSize(620, 0))
+	form.Show()
+}
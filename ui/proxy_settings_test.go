@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/ecsbin"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func TestResultUploadGoesThroughConfiguredProxy(t *testing.T) {
+	// 代理直接应答上传请求，结果不会发到真实的分享服务
+	var target string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.URL.String()
+		io.WriteString(w, "abc123")
+	}))
+	defer server.Close()
+
+	settings := proxy.Settings{URL: "socks5://127.0.0.1:1", Overrides: map[proxy.Component]string{proxy.Uploads: server.URL}}
+	message := uploadThroughProxy(context.Background(), settings, "en", "/tmp/goecs.txt", "result")
+	if !strings.Contains(message, "Http URL:  http://hpaste.spiritlhl.net/#/show/abc123") {
+		t.Fatalf("message = %q", message)
+	}
+	if target != "http://hpaste.spiritlhl.net/api/UL/upload" {
+		t.Fatalf("proxy saw %q", target)
+	}
+}
+
+func TestInvalidProxyDoesNotFallBackToDirect(t *testing.T) {
+	client := proxyHTTPClient(proxy.Settings{URL: "gopher://bad"}, proxy.Notifications, 0)
+	if _, err := client.Get("http://127.0.0.1:1/"); err == nil || !strings.Contains(err.Error(), "unsupported proxy scheme") {
+		t.Fatalf("Get() error = %v", err)
+	}
+}
+
+func TestRemoteRunnerFetchesWithDownloadProxy(t *testing.T) {
+	oldFetch := fetchECSBinary
+	t.Cleanup(func() { fetchECSBinary = oldFetch })
+	var transport *http.Transport
+	fetchECSBinary = func(_ context.Context, cache ecsbin.Cache, _, _ string) (string, error) {
+		transport = cache.Client.Transport.(*http.Transport)
+		return "/cache/goecs", nil
+	}
+	config := ExecutionConfig{RemoteCache: t.TempDir(), Proxy: proxy.Settings{URL: "http://127.0.0.1:3128"}}
+	runner := newRemoteRunner(remote.Target{Host: "example.test"}, config)
+	if _, err := runner.fetch(context.Background(), "0.1.171", "goecs_linux_amd64.zip"); err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://github.com/", nil)
+	if got, _ := transport.Proxy(req); got == nil || got.Host != "127.0.0.1:3128" {
+		t.Fatalf("download proxy = %v", got)
+	}
+}
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// artifactSkip 是远程工作目录中不作为产物取回的文件
+var artifactSkip = []string{"goecs", "goecs.exe", "goecs.pid", "goecs.zip"}
+
+// remoteArtifacts 在运行前记录远程工作目录中的文件，运行后经 SFTP 取回新增或改动的文件
+type remoteArtifacts struct {
+	sftp   *remote.SFTP
+	dir    string
+	before map[string]remote.FileInfo
+}
+
+// startRemoteArtifacts 打开 SFTP 并记录 binary 所在目录；SFTP 不可用时只提示，不影响运行
+func startRemoteArtifacts(run *Run, client *remote.Client, binary string) *remoteArtifacts {
+	s, err := client.SFTP()
+	if err != nil {
+		runnerLog.Warn("sftp unavailable", "host", run.Host, "err", err)
+		run.Output(pickLanguage(run.Config.Language,
+			"远程主机不支持 SFTP，运行结束后不取回结果文件\n",
+			"SFTP is not available on the remote host, result files will not be fetched after the run\n"))
+		return nil
+	}
+	if client.Target().IsWindows() {
+		binary = remote.SFTPPath(binary)
+	}
+	dir := path.Dir(binary)
+	return &remoteArtifacts{sftp: s, dir: dir, before: remote.SnapshotDir(s, dir)}
+}
+
+// collect 取回运行中写出的文件并记入 run，config.RemoteCleanup 时随后从远程删除，nil 时什么也不做
+func (a *remoteArtifacts) collect(run *Run) {
+	if a == nil {
+		return
+	}
+	defer a.sftp.Close()
+	language := run.Config.Language
+	fetched, skipped, err := remote.FetchArtifacts(a.sftp, a.dir, a.before, artifactSkip)
+	if err != nil {
+		runnerLog.Warn("fetch artifacts failed", "host", run.Host, "err", err)
+		run.Output(fmt.Sprintf(pickLanguage(language, "取回结果文件失败：%v\n", "Failed to fetch result files: %v\n"), err))
+		return
+	}
+	if len(skipped) > 0 {
+		run.Output(fmt.Sprintf(pickLanguage(language, "以下文件过大或无法读取，未取回：%s\n", "Skipped files that are too large or unreadable: %s\n"), strings.Join(skipped, ", ")))
+	}
+	if len(fetched) == 0 {
+		return
+	}
+	artifacts := make([]history.Artifact, 0, len(fetched))
+	names := make([]string, 0, len(fetched))
+	for _, item := range fetched {
+		artifacts = append(artifacts, history.Artifact{Name: item.Name, Data: item.Data})
+		names = append(names, item.Name)
+	}
+	run.AddArtifacts(artifacts)
+	run.Output(fmt.Sprintf(pickLanguage(language, "已取回 %d 个结果文件：%s\n", "Fetched %d result file(s): %s\n"), len(fetched), strings.Join(names, ", ")))
+	if !run.Config.RemoteCleanup {
+		return
+	}
+	if err := remote.RemoveArtifacts(a.sftp, a.dir, fetched); err != nil {
+		run.Output(fmt.Sprintf(pickLanguage(language, "删除远程结果文件失败：%v\n", "Failed to remove the result files from the target: %v\n"), err))
+		return
+	}
+	run.Output(pickLanguage(language, "已从远程删除取回的文件\n", "Removed the fetched files from the target\n"))
+}
+
+// writeArtifacts 把取回的文件写入 dir，文件名只取最后一段，避免写到目录之外
+func writeArtifacts(dir string, artifacts []history.Artifact) error {
+	if len(artifacts) == 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, artifact := range artifacts {
+		name := filepath.Base(filepath.Clean(artifact.Name))
+		if name == "." || name == ".." || name == string(filepath.Separator) {
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), artifact.Data, 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+)
+
+type artifactRunner struct{}
+
+func (artifactRunner) Run(run *Run) executionOutcome {
+	run.Output("CPU 测试\n单核得分: 1234\n")
+	run.AddArtifacts([]history.Artifact{{Name: "goecs.txt", Data: []byte("result")}})
+	return executionOutcome{}
+}
+
+func TestRunRecordKeepsArtifacts(t *testing.T) {
+	run := newRun(context.Background(), ExecutionConfig{}, func(string) {}, nil)
+	run.AddArtifacts([]history.Artifact{{Name: "goecs.txt", Data: []byte("a")}})
+	run.AddArtifacts([]history.Artifact{{Name: "geekbench.json", Data: []byte("{}")}})
+	record := run.Record("status.completed", time.Now(), "", nil)
+	if len(record.Artifacts) != 2 || record.Artifacts[0].Name != "goecs.txt" || record.Artifacts[1].Name != "geekbench.json" {
+		t.Fatalf("artifacts = %+v", record.Artifacts)
+	}
+}
+
+func TestRemoteCleanupFromForm(t *testing.T) {
+	form := executionForm{checks: map[string]bool{"remoteClean": true}}
+	if !buildExecutionConfig(form).RemoteCleanup {
+		t.Fatal("RemoteCleanup should follow the remoteClean check")
+	}
+	if buildExecutionConfig(executionForm{}).RemoteCleanup {
+		t.Fatal("RemoteCleanup should default to false")
+	}
+}
+
+func TestWriteArtifactsStaysInsideDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "remote")
+	err := writeArtifacts(dir, []history.Artifact{
+		{Name: "goecs.txt", Data: []byte("result")},
+		{Name: "../escape.txt", Data: []byte("x")},
+		{Name: "..", Data: []byte("x")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, "goecs.txt")); err != nil || string(data) != "result" {
+		t.Fatalf("goecs.txt = %q, %v", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "escape.txt")); err != nil {
+		t.Fatalf("escape.txt should be written inside dir: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "escape.txt")); err == nil {
+		t.Fatal("artifact escaped the output directory")
+	}
+}
+
+func TestRunHeadlessWritesRemoteArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	outDir := filepath.Join(dir, "out")
+	var stdout, stderr strings.Builder
+	code := RunHeadless(context.Background(), HeadlessOptions{
+		SettingsPath: writeHeadlessSettings(t, `{"version":1,"checks":{"cpu":true}}`),
+		OutputDir:    outDir,
+		DataDir:      dir,
+		Stdout:       &stdout,
+		Stderr:       &stderr,
+		runner:       artifactRunner{},
+	})
+	if code != HeadlessExitDone {
+		t.Fatalf("exit code = %d; stderr=%s", code, stderr.String())
+	}
+	if data, err := os.ReadFile(filepath.Join(outDir, "remote", "goecs.txt")); err != nil || string(data) != "result" {
+		t.Fatalf("remote/goecs.txt = %q, %v", data, err)
+	}
+}
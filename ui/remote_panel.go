@@ -0,0 +1,156 @@
+package ui
+
+import (
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// createRemoteSection 创建远程测试（SSH）配置卡片
+func (ui *TestUI) createRemoteSection() fyne.CanvasObject {
+	ui.RemoteHostEntry = widget.NewEntry()
+	ui.RemoteHostEntry.SetPlaceHolder(ui.tr("placeholder.remote_host"))
+	ui.RemotePortEntry = widget.NewEntry()
+	ui.RemotePortEntry.SetText(strconv.Itoa(remote.DefaultPort))
+	ui.RemoteUserEntry = widget.NewEntry()
+	ui.RemoteUserEntry.SetText("root")
+	ui.RemotePasswordEntry = widget.NewPasswordEntry()
+	ui.RemotePasswordEntry.SetPlaceHolder(ui.tr("placeholder.remote_password"))
+	ui.RemoteKeyPathEntry = widget.NewEntry()
+	ui.RemoteKeyPathEntry.SetPlaceHolder(ui.tr("placeholder.remote_key"))
+	ui.RemotePassphraseEntry = widget.NewPasswordEntry()
+	ui.RemotePassphraseEntry.SetPlaceHolder(ui.tr("placeholder.remote_passphrase"))
+	ui.RemoteBinaryEntry = widget.NewEntry()
+	ui.RemoteBinaryEntry.SetPlaceHolder(ui.tr("placeholder.remote_binary"))
+
+	ui.RemoteEnableCheck = widget.NewCheck(ui.tr("check.remote_enable"), func(enabled bool) {
+		ui.setRemoteInputsEnabled(enabled)
+		if enabled && ui.dockerTarget != nil {
+			ui.setDockerTarget(nil)
+		}
+		ui.systemInfoTargetChanged()
+	})
+	ui.RemoteCleanupCheck = widget.NewCheck(ui.tr("check.remote_cleanup"), nil)
+	ui.RemoteWindowsCheck = widget.NewCheck(ui.tr("check.remote_windows"), nil)
+	ui.setRemoteInputsEnabled(false)
+
+	keyRow := container.NewBorder(nil, nil, nil,
+		widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() { ui.pickLocalFile(ui.RemoteKeyPathEntry) }),
+		ui.RemoteKeyPathEntry,
+	)
+	binaryRow := container.NewBorder(nil, nil, nil,
+		widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() { ui.pickLocalFile(ui.RemoteBinaryEntry) }),
+		ui.RemoteBinaryEntry,
+	)
+
+	form := container.NewGridWithColumns(2,
+		widget.NewLabel(ui.tr("label.remote_host")), ui.RemoteHostEntry,
+		widget.NewLabel(ui.tr("label.remote_port")), ui.RemotePortEntry,
+		widget.NewLabel(ui.tr("label.remote_user")), ui.RemoteUserEntry,
+		widget.NewLabel(ui.tr("label.remote_password")), ui.RemotePasswordEntry,
+		widget.NewLabel(ui.tr("label.remote_key")), keyRow,
+		widget.NewLabel(ui.tr("label.remote_passphrase")), ui.RemotePassphraseEntry,
+		widget.NewLabel(ui.tr("label.remote_binary")), binaryRow,
+	)
+
+	hostsButton := widget.NewButtonWithIcon(ui.tr("hosts.title"), theme.ComputerIcon(), ui.showHostManager)
+	ecsButton := widget.NewButtonWithIcon(ui.tr("ecs.title"), theme.DownloadIcon(), ui.showECSManager)
+	dockerButton := widget.NewButtonWithIcon(ui.tr("docker.title"), theme.StorageIcon(), ui.showDockerDialog)
+	ui.remoteJumpLabel = widget.NewLabel("")
+	ui.remoteJumpRow = container.NewHBox(
+		widget.NewIcon(theme.NavigateNextIcon()),
+		ui.remoteJumpLabel,
+		widget.NewButtonWithIcon("", theme.CancelIcon(), func() { ui.setRemoteRoute(nil, "") }),
+	)
+	ui.setRemoteRoute(ui.remoteJump, ui.remoteProxy)
+
+	return widget.NewCard(ui.tr("remote.card.title"), ui.tr("remote.card.sub"), container.NewVBox(
+		container.NewHBox(ui.RemoteEnableCheck, layout.NewSpacer(), dockerButton, ecsButton, hostsButton),
+		form,
+		container.NewHBox(ui.RemoteWindowsCheck, ui.RemoteCleanupCheck),
+		ui.remoteJumpRow,
+		ui.createDockerRow(),
+	))
+}
+
+func (ui *TestUI) setRemoteInputsEnabled(enabled bool) {
+	entries := []*widget.Entry{
+		ui.RemoteHostEntry, ui.RemotePortEntry, ui.RemoteUserEntry, ui.RemotePasswordEntry,
+		ui.RemoteKeyPathEntry, ui.RemotePassphraseEntry, ui.RemoteBinaryEntry,
+	}
+	for _, entry := range entries {
+		if entry == nil {
+			continue
+		}
+		if enabled {
+			entry.Enable()
+		} else {
+			entry.Disable()
+		}
+	}
+	for _, check := range []*widget.Check{ui.RemoteCleanupCheck, ui.RemoteWindowsCheck} {
+		if check == nil {
+			continue
+		}
+		if enabled {
+			check.Enable()
+		} else {
+			check.Disable()
+		}
+	}
+}
+
+// pickLocalFile 打开文件选择对话框并把所选路径写入 entry
+func (ui *TestUI) pickLocalFile(entry *widget.Entry) {
+	dialog.ShowFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		defer reader.Close()
+		entry.SetText(reader.URI().Path())
+	}, ui.Window)
+}
+
+func (ui *TestUI) remoteEnabled() bool {
+	return ui.RemoteEnableCheck != nil && ui.RemoteEnableCheck.Checked
+}
+
+// remoteTarget 根据表单生成远程目标；未启用远程测试时返回 nil
+func (ui *TestUI) remoteTarget() (*remote.Target, error) {
+	if !ui.remoteEnabled() {
+		return nil, nil
+	}
+	port := remote.DefaultPort
+	if text := strings.TrimSpace(ui.RemotePortEntry.Text); text != "" {
+		value, err := strconv.Atoi(text)
+		if err != nil || value <= 0 || value > 65535 {
+			return nil, errInvalidRemotePort
+		}
+		port = value
+	}
+	target := &remote.Target{
+		Host:           strings.TrimSpace(ui.RemoteHostEntry.Text),
+		Port:           port,
+		User:           strings.TrimSpace(ui.RemoteUserEntry.Text),
+		Password:       ui.RemotePasswordEntry.Text,
+		KeyPath:        strings.TrimSpace(ui.RemoteKeyPathEntry.Text),
+		KeyPassphrase:  ui.RemotePassphraseEntry.Text,
+		KnownHostsFile: ui.knownHostsPath(),
+		Jump:           ui.remoteJump,
+		Proxy:          ui.remoteProxy,
+	}
+	if ui.RemoteWindowsCheck != nil && ui.RemoteWindowsCheck.Checked {
+		target.OS = remote.OSWindows
+	}
+	if err := target.Validate(); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
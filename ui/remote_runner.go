@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+var errInvalidRemotePort = errors.New("invalid remote port")
+
+// remoteExecutionRunner 通过 SSH 在远程主机上运行 goecs，并把远程输出回传到终端
+type remoteExecutionRunner struct {
+	target      remote.Target
+	localBinary string
+	version     string
+	fetch       remote.Fetcher
+}
+
+// executionRunnerFor 根据配置选择本地、SSH 远程或容器执行后端
+func executionRunnerFor(config ExecutionConfig) executionRunner {
+	if config.Docker != nil {
+		return newDockerRunner(*config.Docker, config)
+	}
+	if config.Remote != nil {
+		return newRemoteRunner(*config.Remote, config)
+	}
+	return localExecutionRunner{newExecutionRunner()}
+}
+
+func (runner remoteExecutionRunner) Run(run *Run) executionOutcome {
+	ctx, config, emit := run.Context, run.Config, run.Output
+	// 远程只有文本输出，按输出中的分区标题推进各测试阶段
+	steps := append([]string{"progress.remote_connect", "progress.remote_prepare"}, outputStageSteps(config)...)
+	tracker := newProgressTracker(run.Progress, steps)
+
+	tracker.start("progress.remote_connect")
+	emit(fmt.Sprintf("ssh %s@%s\n", runner.target.User, runner.target.Address()))
+	client, err := remote.Dial(ctx, runner.target)
+	if err != nil {
+		return executionOutcome{Err: err}
+	}
+	defer client.Close()
+	tracker.finish("progress.remote_connect")
+
+	tracker.start("progress.remote_prepare")
+	binary, err := remote.Prepare(ctx, client, runner.version, runner.localBinary, runner.fetch, emit)
+	if err != nil {
+		return executionOutcome{Err: err}
+	}
+	tracker.finish("progress.remote_prepare")
+
+	if config.SelectedOptions["memory"] && !runner.target.IsWindows() {
+		out, err := client.Output(ctx, memoryAvailableScript)
+		if err != nil {
+			runnerLog.Warn("memory probe failed", "host", runner.target.Host, "err", err)
+		}
+		applyMemorySafety(run, parseAvailableMemory(out))
+		config = run.Config
+	}
+
+	artifacts := startRemoteArtifacts(run, client, binary)
+	args := withBackendFlags(goecsRemoteArgs(config), config)
+	var command, display string
+	if runner.target.IsWindows() {
+		// 终端中显示 PowerShell 脚本本身，而不是编码后的命令
+		display = remote.WindowsScript(binary, config.BackendEnv, args)
+		command = remote.PowerShell(display)
+	} else {
+		command = remote.CommandWithEnv(binary, config.BackendEnv, args)
+		mode, err := client.SudoMode(ctx)
+		if err != nil {
+			runnerLog.Warn("sudo probe failed", "host", runner.target.Host, "err", err)
+		}
+		command = withSudo(run, runner.target, mode, command)
+		display = command
+	}
+	emit("$ " + display + "\n")
+	watcher := newStageWatcher(tracker)
+	err = client.RunWithInput(ctx, command, true, run.Input(), func(text string) {
+		watcher.Observe(text)
+		emit(text)
+	})
+	watcher.Close()
+	artifacts.collect(run)
+	return executionOutcome{Err: err}
+}
+
+// goecsRemoteArgs 把执行配置转换为 goecs 命令行参数
+func goecsRemoteArgs(config ExecutionConfig) []string {
+	flag := func(name string, value bool) string {
+		return "-" + name + "=" + strconv.FormatBool(value)
+	}
+	selected := config.SelectedOptions
+	args := []string{
+		"-menu=false",
+		"-l", config.Language,
+		flag("basic", selected["basic"]),
+		flag("cpu", selected["cpu"]),
+		flag("memory", selected["memory"]),
+		flag("disk", selected["disk"]),
+		flag("ut", selected["unlock"]),
+		flag("security", selected["security"]),
+		flag("email", selected["email"]),
+		flag("backtrace", selected["backtrace"]),
+		flag("nt3", selected["nt3"]),
+		flag("speed", selected["speed"]),
+		flag("ping", selected["ping"]),
+		flag("tgdc", config.PingTgdc),
+		flag("web", config.PingWeb),
+		flag("upload", config.EnableUpload),
+		flag("analysis", config.AnalyzeResult),
+		flag("privacy", config.PrivacyMode),
+		flag("data-offline", config.DataOffline),
+		flag("log", config.LogEnabled),
+		flag("diskmc", config.DiskMulti),
+	}
+	addValue := func(name, value string) {
+		if value != "" {
+			args = append(args, "-"+name, value)
+		}
+	}
+	addValue("cpum", effectiveCpuMethod(config))
+	addValue("cput", config.ThreadMode)
+	addValue("memorym", config.MemoryMethod)
+	if !config.AutoDiskMethod {
+		addValue("diskm", config.DiskMethod)
+	}
+	addValue("diskp", config.DiskPath)
+	addValue("nt3loc", config.Nt3Location)
+	addValue("nt3t", config.Nt3Type)
+	if config.SpNum > 0 {
+		addValue("spnum", strconv.Itoa(config.SpNum))
+	}
+	addValue("ping-sort", config.PingSortOrder)
+	addValue("ping-scope", config.PingScope)
+	addValue("tcp-sort", config.TCPSortOrder)
+	addValue("utregion", config.UnlockRegion)
+	addValue("utipver", config.UnlockIpVersion)
+	addValue("ut-interface", config.UnlockInterface)
+	addValue("ut-dns", config.UnlockDNS)
+	addValue("ut-http-proxy", config.UnlockHTTPProxy)
+	addValue("ut-socks-proxy", config.UnlockSOCKSProxy)
+	if config.UnlockConcurrency > 0 {
+		addValue("ut-concurrency", strconv.Itoa(config.UnlockConcurrency))
+	}
+	if config.DeepMode {
+		args = append(args, "-deep")
+		addValue("deep-disk-paths", config.DeepDiskPaths)
+		addValue("deep-smart-devices", config.DeepSMARTDevices)
+		addValue("deep-gpu-device", config.DeepGPUDevice)
+	}
+	return args
+}
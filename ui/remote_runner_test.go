@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGoecsRemoteArgsMirrorsSelection(t *testing.T) {
+	config := ExecutionConfig{
+		SelectedOptions: map[string]bool{"cpu": true, "speed": true},
+		Language:        "en",
+		CpuMethod:       "sysbench",
+		DiskPath:        "/data dir",
+		SpNum:           3,
+		DeepMode:        true,
+	}
+	args := strings.Join(goecsRemoteArgs(config), " ")
+	for _, want := range []string{"-menu=false", "-l en", "-cpu=true", "-speed=true", "-disk=false", "-ut=false", "-cpum sysbench", "-diskp /data dir", "-spnum 3", "-deep"} {
+		if !strings.Contains(args, want) {
+			t.Fatalf("args %q missing %q", args, want)
+		}
+	}
+}
+
+func TestRemoteTargetFromForm(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+
+	if target, err := ui.remoteTarget(); target != nil || err != nil {
+		t.Fatalf("remote disabled: target = %#v, err = %v", target, err)
+	}
+	if _, ok := executionRunnerFor(ui.collectExecutionConfig()).(remoteExecutionRunner); ok {
+		t.Fatal("local runs should not use the remote runner")
+	}
+
+	ui.RemoteEnableCheck.SetChecked(true)
+	ui.RemoteHostEntry.SetText("203.0.113.10")
+	if _, err := ui.remoteTarget(); err == nil {
+		t.Fatal("missing credentials should be rejected")
+	}
+	ui.RemotePortEntry.SetText("2222")
+	ui.RemotePasswordEntry.SetText("secret")
+	target, err := ui.remoteTarget()
+	if err != nil || target.Address() != "203.0.113.10:2222" || target.User != "root" || target.KnownHostsFile == "" {
+		t.Fatalf("target = %#v, err = %v", target, err)
+	}
+	config := ui.collectExecutionConfig()
+	if _, ok := executionRunnerFor(config).(remoteExecutionRunner); !ok || config.Remote == nil {
+		t.Fatalf("config.Remote = %#v, want remote runner", config.Remote)
+	}
+	if target.IsWindows() {
+		t.Fatal("targets default to unix-like hosts")
+	}
+
+	ui.RemoteWindowsCheck.SetChecked(true)
+	if target, err := ui.remoteTarget(); err != nil || !target.IsWindows() {
+		t.Fatalf("windows target = %#v, err = %v", target, err)
+	}
+	if !ui.formChecks()["remoteWin"] {
+		t.Fatal("the windows check should be saved with the form")
+	}
+}
@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// withSudo 按 mode 用 sudo 包装远程命令。sudo 需要密码时依次使用主机配置中保存的密码、
+// 运行前询问的密码（每次运行只问一次），并在命令开始前写入标准输入；拿不到密码时以登录用户运行
+func withSudo(run *Run, target remote.Target, mode remote.SudoMode, command string) string {
+	language, user := run.Config.Language, target.User
+	switch mode {
+	case remote.SudoNotNeeded:
+		return command
+	case remote.SudoNoPassword:
+		return remote.SudoCommand(command, false)
+	case remote.SudoWithPassword:
+		password := target.SudoPassword
+		if password == "" && run.sudoPassword != nil {
+			password = run.sudoPassword(run.Context, target)
+		}
+		if password != "" && run.SendInput(password) {
+			return remote.SudoCommand(command, true)
+		}
+		run.Output(fmt.Sprintf(pickLanguage(language,
+			"未提供 sudo 密码，以 %s 运行，需要 root 的测试结果可能不完整\n",
+			"No sudo password was given, running as %s; tests that need root may be incomplete\n"), user))
+	default:
+		run.Output(fmt.Sprintf(pickLanguage(language,
+			"远程主机没有 sudo，以 %s 运行，需要 root 的测试结果可能不完整\n",
+			"sudo is not available on the remote host, running as %s; tests that need root may be incomplete\n"), user))
+	}
+	return command
+}
+
+// withSavedSudoPassword 为远程目标填入主机管理中同一主机、同一用户保存的 sudo 密码
+func (ui *TestUI) withSavedSudoPassword(target *remote.Target) {
+	if target == nil || target.SudoPassword != "" {
+		return
+	}
+	if profile, ok := ui.hostProfileByHost(target.Host); ok && profile.User == target.User {
+		target.SudoPassword = profile.SudoPassword
+	}
+}
+
+// askSudoPassword 在运行中询问 target 的 sudo 密码并等待回答，取消或 ctx 结束时返回空字符串，可在任意 goroutine 调用。
+// 密码默认只用于本次运行；主机配置已解锁且该主机已保存时可选择加密保存到主机配置
+func (ui *TestUI) askSudoPassword(ctx context.Context, target remote.Target) string {
+	answer := make(chan string, 1)
+	ui.runOnUI(func() {
+		password := widget.NewPasswordEntry()
+		profile, saved := ui.hostProfileByHost(target.Host)
+		saved = saved && profile.User == target.User
+		remember := widget.NewCheck(ui.tr("sudo.remember"), nil)
+		if !saved {
+			remember.Disable()
+		}
+		hint := widget.NewLabel(fmt.Sprintf(ui.tr("sudo.hint"), target.User, target.Host))
+		hint.Wrapping = fyne.TextWrapWord
+		items := []*widget.FormItem{
+			{Widget: hint},
+			widget.NewFormItem(ui.tr("sudo.password"), password),
+			{Widget: remember, HintText: ui.tr("sudo.remember_hint")},
+		}
+		form := dialog.NewForm(ui.tr("sudo.title"), ui.tr("sudo.continue"), ui.tr("sudo.skip"), items, func(ok bool) {
+			if !ok {
+				answer <- ""
+				return
+			}
+			if remember.Checked && saved && ui.hostProfiles != nil {
+				profile.SudoPassword = password.Text
+				if err := ui.hostProfiles.Put(profile.Name, profile); err != nil {
+					dialog.ShowError(err, ui.Window)
+				}
+			}
+			answer <- password.Text
+		}, ui.Window)
+		form.Resize(fyne.NewSize(460, 0))
+		form.Show()
+		ui.Window.Canvas().Focus(password)
+	})
+	select {
+	case password := <-answer:
+		return password
+	case <-ctx.Done():
+		return ""
+	}
+}
@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func TestWithSudoAsksOnceAndFeedsThePassword(t *testing.T) {
+	asked := 0
+	run := newRun(nil, ExecutionConfig{Language: "en"}, nil, nil)
+	run.sudoPassword = func(_ context.Context, target remote.Target) string {
+		asked++
+		return "pw-" + target.User
+	}
+	target := remote.Target{Host: "10.0.0.1", User: "ubuntu"}
+
+	if got := withSudo(run, target, remote.SudoNotNeeded, "./goecs"); got != "./goecs" {
+		t.Fatalf("root command = %q", got)
+	}
+	if got := withSudo(run, target, remote.SudoNoPassword, "./goecs"); got != remote.SudoCommand("./goecs", false) || asked != 0 {
+		t.Fatalf("NOPASSWD command = %q, asked %d times", got, asked)
+	}
+	if got := withSudo(run, target, remote.SudoWithPassword, "./goecs"); got != remote.SudoCommand("./goecs", true) || asked != 1 {
+		t.Fatalf("sudo command = %q, asked %d times", got, asked)
+	}
+	if line := <-run.Input(); line != "pw-ubuntu\n" {
+		t.Fatalf("stdin = %q, the password should be written first", line)
+	}
+
+	target.SudoPassword = "saved"
+	withSudo(run, target, remote.SudoWithPassword, "./goecs")
+	if line := <-run.Input(); line != "saved\n" || asked != 1 {
+		t.Fatalf("stdin = %q, asked %d times; a saved password should not be asked again", line, asked)
+	}
+}
+
+func TestWithSudoFallsBackWithoutPassword(t *testing.T) {
+	var output strings.Builder
+	run := newRun(nil, ExecutionConfig{Language: "en"}, func(text string) { output.WriteString(text) }, nil)
+	target := remote.Target{Host: "10.0.0.1", User: "ubuntu"}
+	if got := withSudo(run, target, remote.SudoWithPassword, "./goecs"); got != "./goecs" {
+		t.Fatalf("command = %q, runs without a password should not use sudo", got)
+	}
+	withSudo(run, target, remote.SudoUnavailable, "./goecs")
+	if !strings.Contains(output.String(), "No sudo password") || !strings.Contains(output.String(), "sudo is not available") {
+		t.Fatalf("output = %q", output.String())
+	}
+	select {
+	case line := <-run.Input():
+		t.Fatalf("nothing should be written to stdin, got %q", line)
+	default:
+	}
+}
+
+func TestSavedSudoPasswordNeedsMatchingUser(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	store, err := remote.OpenProfiles(filepath.Join(t.TempDir(), "hosts.enc"), "master")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.hostProfiles = store
+	if err := store.Put("", remote.Profile{Name: "vps", Host: "10.0.0.1", User: "ubuntu", Auth: remote.AuthPassword, Password: "ssh", SudoPassword: "pw"}); err != nil {
+		t.Fatal(err)
+	}
+	target := &remote.Target{Host: "10.0.0.1", User: "ubuntu"}
+	ui.withSavedSudoPassword(target)
+	if target.SudoPassword != "pw" {
+		t.Fatal("the saved sudo password should be used")
+	}
+	other := &remote.Target{Host: "10.0.0.1", User: "admin"}
+	ui.withSavedSudoPassword(other)
+	if other.SudoPassword != "" {
+		t.Fatal("a password saved for another user should not be used")
+	}
+	resolved, _ := store.Resolve("vps", "")
+	if resolved.SudoPassword != "pw" {
+		t.Fatal("resolved profiles should carry the sudo password")
+	}
+}
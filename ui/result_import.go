@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// importedStatus 是导入的外部结果在历史记录中的状态
+const importedStatus = "imported"
+
+var errImportNoResults = errors.New("no ecs results found in the pasted text")
+
+var (
+	// ecs 报告末尾的“时间”与“花费”两行，用于还原外部运行的时间
+	importTimePattern = regexp.MustCompile(`(?m)^\s*(?:时间|Current Time)\s*:\s*(.+?)\s*$`)
+	importCostPattern = regexp.MustCompile(`(?m)^\s*(?:花费|Cost\s+Time)\s*:\s*(\d+)\s*(?:分|min)\s*(\d+)\s*(?:秒|sec)`)
+)
+
+// importedTests 按报告中解析到的分区返回测试项，与运行记录中的 Tests 一致
+func importedTests(report *results.Report) []string {
+	var tests []string
+	for _, item := range []struct {
+		key string
+		ok  bool
+	}{
+		{"basic", len(report.System) > 0},
+		{"cpu", len(report.CPU) > 0},
+		{"memory", len(report.Memory) > 0},
+		{"disk", len(report.Disk) > 0},
+		{"unlock", len(report.Unlock) > 0},
+		{"security", len(report.IPQuality) > 0},
+		{"backtrace", len(report.Backtrace) > 0},
+		{"nt3", len(report.Routes) > 0},
+		{"speed", len(report.Speed) > 0},
+	} {
+		if item.ok {
+			tests = append(tests, item.key)
+		}
+	}
+	return tests
+}
+
+// importedRun 把粘贴的 ecs 文本报告解析为一条历史记录；报告末尾有时间与花费时按它们还原运行时间，否则记为 now
+func importedRun(text, host, label string, now time.Time) (history.Run, error) {
+	output := strings.TrimSpace(strings.ReplaceAll(results.StripANSI(text), "\r\n", "\n"))
+	report := results.Parse(output)
+	tests := importedTests(report)
+	if len(tests) == 0 {
+		return history.Run{}, errImportNoResults
+	}
+	finished := now
+	if m := importTimePattern.FindStringSubmatch(output); m != nil {
+		if t, err := time.Parse("Mon Jan 2 15:04:05 MST 2006", m[1]); err == nil {
+			finished = t
+		}
+	}
+	started := finished
+	if m := importCostPattern.FindStringSubmatch(output); m != nil {
+		minutes, _ := strconv.Atoi(m[1])
+		seconds, _ := strconv.Atoi(m[2])
+		started = finished.Add(-time.Duration(minutes*60+seconds) * time.Second)
+	}
+	return history.Run{
+		Summary: history.Summary{
+			StartedAt:  started,
+			FinishedAt: finished,
+			Duration:   finished.Sub(started),
+			Status:     importedStatus,
+			Host:       strings.TrimSpace(host),
+			Label:      strings.TrimSpace(label),
+			Tests:      tests,
+		},
+		Output:  output + "\n",
+		Results: report,
+	}, nil
+}
+
+// showImportResultDialog 导入从论坛帖子或服务器上手动运行复制的 ecs 文本报告，保存到历史记录后可参与对比与趋势
+func (ui *TestUI) showImportResultDialog() {
+	text := widget.NewMultiLineEntry()
+	text.SetPlaceHolder(ui.tr("import.placeholder"))
+	text.SetMinRowsVisible(12)
+	text.Wrapping = fyne.TextWrapOff
+	if clip := ui.App.Clipboard().Content(); len(importedTests(results.Parse(clip))) > 0 {
+		text.SetText(clip)
+	}
+	host := widget.NewEntry()
+	host.SetPlaceHolder(ui.tr("import.host_hint"))
+	label := widget.NewEntry()
+	label.SetPlaceHolder(ui.tr("import.label_hint"))
+	paste := widget.NewButton(ui.tr("import.paste"), func() { text.SetText(ui.App.Clipboard().Content()) })
+	hint := widget.NewLabel(ui.tr("import.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	content := container.NewBorder(
+		container.NewBorder(nil, nil, nil, paste, hint),
+		container.New(layout.NewFormLayout(), widget.NewLabel(ui.tr("label.remote_host")), host, widget.NewLabel(ui.tr("import.label")), label),
+		nil, nil, text,
+	)
+	d := dialog.NewCustomConfirm(ui.tr("import.title"), ui.tr("import.run"), ui.tr("compare.cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		run, err := importedRun(text.Text, host.Text, label.Text, time.Now())
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		ui.saveHistoryRun(run)
+		dialog.ShowInformation(ui.tr("import.title"), fmt.Sprintf(ui.tr("import.done"), len(results.Metrics(run.Results))), ui.Window)
+	}, ui.Window)
+	d.Resize(fyne.NewSize(720, 560))
+	d.Show()
+}
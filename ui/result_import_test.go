@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+const importedReport = "\x1b[33m---------------------CPU测试-通过sysbench测试---------------------\x1b[0m\r\n" +
+	"1 线程测试(单核)得分:      1234\r\n" +
+	"-----------就近节点测速-----------\r\n" +
+	" Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\r\n" +
+	"--------------------------------------------------\r\n" +
+	"花费          : 2 分 30 秒\r\n" +
+	"时间          : Sat Mar 1 10:20:30 UTC 2025\r\n"
+
+func TestImportedRunParsesExternalReport(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	run, err := importedRun(importedReport, " 10.0.0.9 ", " forum post ", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.Status != importedStatus || run.Host != "10.0.0.9" || run.Label != "forum post" || !slices.Equal(run.Tests, []string{"cpu", "speed"}) {
+		t.Fatalf("summary = %#v", run.Summary)
+	}
+	if want := time.Date(2025, 3, 1, 10, 20, 30, 0, time.UTC); !run.FinishedAt.Equal(want) || run.Duration != 150*time.Second {
+		t.Fatalf("finished = %v, duration = %v", run.FinishedAt, run.Duration)
+	}
+	if len(run.Results.CPU) != 1 || len(run.Results.Speed) != 1 {
+		t.Fatalf("results = %#v", run.Results)
+	}
+
+	if run, err := importedRun("1 线程测试(单核)得分: 1\n", "", "", now); err == nil {
+		t.Fatalf("text outside an ecs section should not import, run = %#v", run)
+	}
+}
+
+func TestImportedRunJoinsHistory(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	run, err := importedRun("---------------------CPU测试-通过sysbench测试---------------------\n1 线程测试(单核)得分: 1000\n", "", "", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ui.saveHistoryRun(run)
+	if len(ui.historyItems) != 1 || ui.historyItems[0].Status != importedStatus || ui.historyItemText(ui.historyItems[0]) == "" {
+		t.Fatalf("historyItems = %#v", ui.historyItems)
+	}
+	ui.App.Clipboard().SetContent(importedReport)
+	ui.showImportResultDialog()
+}
@@ -8,7 +8,7 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-// createResultTab 创建测试结果页面
+// createResultTab 创建测试结果页面，主运行之外的运行各自在新的标签页中显示
 func (ui *TestUI) createResultTab() fyne.CanvasObject {
 	statusRow := container.NewHBox(
 		ui.StatusLabel,
@@ -17,17 +17,27 @@ func (ui *TestUI) createResultTab() fyne.CanvasObject {
 	)
 	statusBar := container.NewVBox(statusRow, ui.CurrentItem, ui.ProgressBar, ui.DataStatusLabel, ui.PartialReasonLabel)
 
+	ui.terminalFollow = newTerminalFollower(ui.Terminal, ui.tr)
+	ui.speedChart = newSpeedChart(ui.tr)
+
 	copyButton := widget.NewButtonWithIcon(ui.tr("button.copy"), theme.ContentCopyIcon(), ui.copyResults)
-	exportButton := widget.NewButtonWithIcon(ui.tr("button.export"), theme.DownloadIcon(), ui.exportResults)
+	exportButton := widget.NewButtonWithIcon(ui.tr("button.export"), theme.DownloadIcon(), nil)
+	exportButton.OnTapped = func() { ui.showExportMenu(exportButton) }
+	saveLogButton := widget.NewButtonWithIcon(ui.tr("button.save_log"), theme.DocumentSaveIcon(), ui.saveTerminalLog)
 	shareButton := widget.NewButtonWithIcon(ui.tr("button.share"), theme.MailForwardIcon(), ui.shareResults)
 	clearButton := widget.NewButtonWithIcon(ui.tr("button.clear"), theme.DeleteIcon(), ui.clearResults)
+	runTabsButton := ui.createRunTabsButton()
+	privacyCheck := ui.createPrivacyCheck()
+	if privacyCheck.Checked {
+		ui.applyPrivacy()
+	}
 
-	actions := []fyne.CanvasObject{clearButton, copyButton, exportButton, shareButton}
+	actions := []fyne.CanvasObject{runTabsButton, clearButton, copyButton, exportButton, saveLogButton, shareButton}
 	actionsBar := container.NewHBox(actions...)
 	if isMobilePlatform() {
-		actionsBar = container.NewAdaptiveGrid(2, actions...)
+		actionsBar = container.NewAdaptiveGrid(2, append([]fyne.CanvasObject{ui.terminalFollow.toggle, privacyCheck}, actions...)...)
 	} else {
-		actionsBar = container.NewHBox(layout.NewSpacer(), clearButton, copyButton, exportButton, shareButton)
+		actionsBar = container.NewHBox(ui.terminalFollow.toggle, privacyCheck, layout.NewSpacer(), ui.createLayoutButton(), runTabsButton, clearButton, copyButton, exportButton, saveLogButton, shareButton)
 	}
 
 	header := widget.NewCard("", "", container.NewVBox(
@@ -35,17 +45,21 @@ func (ui *TestUI) createResultTab() fyne.CanvasObject {
 		actionsBar,
 	))
 
-	terminalScroll := container.NewScroll(container.NewPadded(ui.Terminal))
-	structuredCaption := widget.NewLabelWithStyle(ui.tr("result.structured.title"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
-	structuredPanel := container.NewBorder(structuredCaption, nil, nil, nil, container.NewPadded(ui.StructuredDetailsView))
-	resultsSplit := container.NewVSplit(terminalScroll, structuredPanel)
-	resultsSplit.Offset = 0.68
+	ui.stdinBar = ui.newStdinBar()
+	terminalPanel := container.NewBorder(
+		container.NewVBox(ui.createTerminalFilterBar(), ui.createTerminalSearchBar(), ui.createTerminalOverflowLabel()),
+		ui.stdinBar.content, nil, ui.speedChart,
+		ui.terminalFollow.Content(),
+	)
+	structuredPanel := container.NewPadded(ui.StructuredDetailsView)
+	terminalSlot, resultsSlot := ui.createPopoutPanels(terminalPanel, newStageRerunArea(ui.createResultsTabs(structuredPanel), ui.stageRerunMenu))
+	resultsSplit := ui.createResultsSplit(terminalSlot, resultsSlot)
 
-	return container.NewBorder(
+	return ui.createRunTabs(container.NewBorder(
 		header,
 		nil,
 		nil,
 		nil,
 		resultsSplit,
-	)
+	))
 }
@@ -0,0 +1,260 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// parsedResultTab 描述结果面板中的一个分类标签页，tests 为产生该分类结果的测试项，可在结果面板中单独重跑；
+// sections 为该页展示的分区，用于标出受虚拟化注意事项影响的标签页
+type parsedResultTab struct {
+	titleKey string
+	build    func(ui *TestUI, report *results.Report) fyne.CanvasObject
+	tests    []string
+	sections []results.Section
+}
+
+var parsedResultTabs = []parsedResultTab{
+	{titleKey: "results.tab.cpu", build: (*TestUI).cpuResultsView, tests: []string{"cpu"}, sections: []results.Section{results.SectionCPU}},
+	{titleKey: "results.tab.memory", build: (*TestUI).memoryResultsView, tests: []string{"memory"}, sections: []results.Section{results.SectionMemory}},
+	{titleKey: "results.tab.disk", build: (*TestUI).diskResultsView, tests: []string{"disk"}, sections: []results.Section{results.SectionDisk}},
+	{titleKey: "results.tab.speed", build: (*TestUI).speedResultsView, tests: []string{"speed"}, sections: []results.Section{results.SectionSpeed}},
+	{titleKey: "results.tab.ip_quality", build: (*TestUI).ipQualityResultsView, tests: []string{"security"}, sections: []results.Section{results.SectionIPQuality}},
+	{titleKey: "results.tab.unlock", build: (*TestUI).unlockResultsView, tests: []string{"unlock"}, sections: []results.Section{results.SectionUnlock}},
+	{titleKey: "results.tab.route", build: (*TestUI).routeResultsView, tests: []string{"backtrace", "nt3"}, sections: []results.Section{results.SectionBacktrace, results.SectionRoute}},
+	{titleKey: "results.tab.gpu", build: (*TestUI).gpuResultsView, sections: []results.Section{results.SectionGPU}},
+	{titleKey: "results.tab.raw", build: (*TestUI).rawResultsView},
+}
+
+// createResultsTabs 创建终端下方的结果面板：第一页为测试概览，其余为解析后的分类结果
+func (ui *TestUI) createResultsTabs(overview fyne.CanvasObject) *container.AppTabs {
+	ui.resultsOverview = overview
+	items := []*container.TabItem{container.NewTabItem(ui.tr("result.structured.title"), overview)}
+	for _, tab := range parsedResultTabs {
+		items = append(items, container.NewTabItem(ui.tr(tab.titleKey), widget.NewLabel(ui.tr("results.empty"))))
+	}
+	ui.resultsTabs = container.NewAppTabs(items...)
+	ui.resultsTabs.SetTabLocation(container.TabLocationTop)
+	ui.renderParsedResults(ui.ParsedResults)
+	return ui.resultsTabs
+}
+
+// refreshParsedResults 从终端输出解析结构化结果并刷新面板（线程安全）
+func (ui *TestUI) refreshParsedResults() {
+	if ui.Terminal == nil {
+		return
+	}
+	report := results.Parse(ui.Terminal.GetText())
+	ui.Mu.Lock()
+	ui.ParsedResults = report
+	ui.Mu.Unlock()
+	ui.runOnUI(func() { ui.renderParsedResults(report) })
+}
+
+// renderParsedResults 按分类重建结果面板内容，必须在 UI 线程调用
+func (ui *TestUI) renderParsedResults(report *results.Report) {
+	if ui.resultsTabs == nil {
+		return
+	}
+	ui.Mu.Lock()
+	record := ui.resultsRecord
+	ui.Mu.Unlock()
+	var caveats []results.Caveat
+	if report != nil {
+		caveats = report.Caveats()
+	}
+	ui.resultsTabs.Items[0].Content = ui.withCaveats(caveats, ui.resultsOverview)
+	for i, tab := range parsedResultTabs {
+		item := ui.resultsTabs.Items[i+1]
+		item.Text = ui.tr(tab.titleKey)
+		if record != nil && !retestedAt(*record, tab.tests).IsZero() {
+			item.Text += " ↻"
+		}
+		if report == nil {
+			item.Content = widget.NewLabel(ui.tr("results.empty"))
+			continue
+		}
+		tabCaveats := results.CaveatsFor(caveats, tab.sections...)
+		if len(tabCaveats) > 0 {
+			item.Text += " ⚠"
+		}
+		item.Content = ui.withCaveats(tabCaveats, tab.build(ui, report))
+	}
+	ui.resultsTabs.Refresh()
+}
+
+// withCaveats 在 content 上方列出虚拟化注意事项，没有注意事项时原样返回
+func (ui *TestUI) withCaveats(caveats []results.Caveat, content fyne.CanvasObject) fyne.CanvasObject {
+	if len(caveats) == 0 || content == nil {
+		return content
+	}
+	lines := make([]string, 0, len(caveats))
+	for _, caveat := range caveats {
+		sections := make([]string, 0, len(caveat.Sections))
+		for _, section := range caveat.Sections {
+			sections = append(sections, ui.tr("virt.section."+string(section)))
+		}
+		lines = append(lines, fmt.Sprintf(ui.tr("virt.caveat_line"), ui.tr("virt.caveat."+caveat.Key), strings.Join(sections, ui.tr("virt.section_sep"))))
+	}
+	warning := widget.NewLabel(strings.Join(lines, "\n"))
+	warning.Wrapping = fyne.TextWrapWord
+	warning.Importance = widget.WarningImportance
+	banner := container.NewBorder(nil, nil, widget.NewIcon(theme.WarningIcon()), nil, warning)
+	return container.NewBorder(banner, nil, nil, nil, content)
+}
+
+func (ui *TestUI) cpuResultsView(report *results.Report) fyne.CanvasObject {
+	rows := make([][]string, 0, len(report.CPU))
+	for _, score := range report.CPU {
+		threads := "-"
+		if score.Threads > 0 {
+			threads = fmt.Sprintf("%d", score.Threads)
+		}
+		rows = append(rows, []string{score.Label, threads, formatResultNumber(score.Score)})
+	}
+	table := ui.resultTable([]string{ui.tr("results.col.item"), ui.tr("results.col.threads"), ui.tr("results.col.score")}, rows)
+	if links := ui.geekbenchLinks(report); links != nil {
+		return container.NewBorder(nil, links, nil, nil, table)
+	}
+	return table
+}
+
+func (ui *TestUI) memoryResultsView(report *results.Report) fyne.CanvasObject {
+	rows := make([][]string, 0, len(report.Memory))
+	for _, item := range report.Memory {
+		rows = append(rows, []string{item.Label, formatResultNumber(item.MBps) + " MB/s"})
+	}
+	return ui.resultTable([]string{ui.tr("results.col.item"), ui.tr("results.col.bandwidth")}, rows)
+}
+
+func (ui *TestUI) diskResultsView(report *results.Report) fyne.CanvasObject {
+	metric := func(m results.DiskMetric) string {
+		if m.IOPS > 0 {
+			return fmt.Sprintf("%s MB/s (%s IOPS)", formatResultNumber(m.MBps), formatResultNumber(m.IOPS))
+		}
+		return formatResultNumber(m.MBps) + " MB/s"
+	}
+	rows := make([][]string, 0, len(report.Disk))
+	for _, item := range report.Disk {
+		rows = append(rows, []string{item.Path, item.Block, metric(item.Read), metric(item.Write), metric(item.Total)})
+	}
+	table := ui.resultTable([]string{
+		ui.tr("results.col.path"), ui.tr("results.col.block"),
+		ui.tr("results.col.read"), ui.tr("results.col.write"), ui.tr("results.col.total"),
+	}, rows)
+	if report.DiskFileSize == "" {
+		return table
+	}
+	// 显示 GUI 自定义 fio 的测试文件大小，安全模式缩小过时标黄提示
+	size := widget.NewLabel(fmt.Sprintf(ui.tr("results.disk_size"), report.DiskFileSize))
+	if report.DiskShrunkFrom != "" {
+		size.SetText(fmt.Sprintf(ui.tr("results.disk_shrunk"), report.DiskFileSize, report.DiskShrunkFrom))
+		size.Importance = widget.WarningImportance
+	}
+	return container.NewBorder(size, nil, nil, nil, table)
+}
+
+func (ui *TestUI) speedResultsView(report *results.Report) fyne.CanvasObject {
+	rows := make([][]string, 0, len(report.Speed))
+	for _, item := range report.Speed {
+		rows = append(rows, []string{
+			item.Node,
+			formatResultNumber(item.UploadMbps) + " Mbps",
+			formatResultNumber(item.DownloadMbps) + " Mbps",
+			formatResultNumber(item.LatencyMs) + " ms",
+			item.PacketLoss,
+		})
+	}
+	return ui.resultTable([]string{
+		ui.tr("results.col.node"), ui.tr("results.col.upload"), ui.tr("results.col.download"),
+		ui.tr("results.col.latency"), ui.tr("results.col.loss"),
+	}, rows)
+}
+
+// gpuResultsView 每块显卡一张卡片（型号、显存与驱动版本），下方为 hashcat 基准成绩
+func (ui *TestUI) gpuResultsView(report *results.Report) fyne.CanvasObject {
+	if len(report.GPU) == 0 {
+		return widget.NewLabel(ui.tr("results.empty"))
+	}
+	cards := container.NewVBox()
+	for _, card := range report.GPU {
+		var details []string
+		if card.Memory != "" {
+			details = append(details, fmt.Sprintf(ui.tr("results.gpu.memory"), card.Memory))
+		}
+		if card.Driver != "" {
+			details = append(details, fmt.Sprintf(ui.tr("results.gpu.driver"), card.Driver))
+		}
+		cards.Add(widget.NewCard(card.Model, strings.Join(details, " · "), nil))
+	}
+	if len(report.GPUBench) == 0 {
+		return container.NewVScroll(cards)
+	}
+	rows := make([][]string, 0, len(report.GPUBench))
+	for _, bench := range report.GPUBench {
+		rows = append(rows, []string{bench.Device, bench.Rate})
+	}
+	table := ui.resultTable([]string{ui.tr("results.col.device"), ui.tr("results.col.sha256")}, rows)
+	return container.NewBorder(cards, nil, nil, nil, table)
+}
+
+// rawResultsView 以原文卡片展示解析器尚未支持的分区，ecs 新增分区时不至于丢失内容
+func (ui *TestUI) rawResultsView(report *results.Report) fyne.CanvasObject {
+	if len(report.Raw) == 0 {
+		return widget.NewLabel(ui.tr("results.empty"))
+	}
+	cards := container.NewVBox()
+	for _, section := range report.Raw {
+		body := widget.NewLabel(strings.Join(section.Lines, "\n"))
+		body.TextStyle = fyne.TextStyle{Monospace: true}
+		cards.Add(widget.NewCard(section.Title, "", body))
+	}
+	return container.NewVScroll(cards)
+}
+
+// resultTable 创建带表头的只读表格，列宽按内容自适应
+func (ui *TestUI) resultTable(headers []string, rows [][]string) fyne.CanvasObject {
+	if len(rows) == 0 {
+		return widget.NewLabel(ui.tr("results.empty"))
+	}
+	cell := func(row, col int) string {
+		if row == 0 {
+			return headers[col]
+		}
+		if col < len(rows[row-1]) {
+			return rows[row-1][col]
+		}
+		return ""
+	}
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(headers) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			label := obj.(*widget.Label)
+			label.TextStyle = fyne.TextStyle{Bold: id.Row == 0}
+			label.SetText(cell(id.Row, id.Col))
+		},
+	)
+	padding := theme.Padding() * 4
+	for col, header := range headers {
+		width := fyne.MeasureText(header, theme.TextSize(), fyne.TextStyle{Bold: true}).Width
+		for row := range rows {
+			width = max(width, fyne.MeasureText(cell(row+1, col), theme.TextSize(), fyne.TextStyle{}).Width)
+		}
+		table.SetColumnWidth(col, min(width+padding, 360))
+	}
+	return table
+}
+
+func formatResultNumber(v float64) string {
+	if v == float64(int64(v)) {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%.2f", v)
+}
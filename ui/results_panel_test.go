@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+func TestRefreshParsedResultsFillsTabs(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("-----------就近节点测速-----------\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n")
+	ui.refreshParsedResults()
+
+	ui.Mu.Lock()
+	report := ui.ParsedResults
+	ui.Mu.Unlock()
+	if report == nil || len(report.Speed) != 1 {
+		t.Fatalf("ParsedResults = %#v", report)
+	}
+	speedIndex := 0
+	for i, tab := range parsedResultTabs {
+		if tab.titleKey == "results.tab.speed" {
+			speedIndex = i + 1
+		}
+	}
+	if _, ok := ui.resultsTabs.Items[speedIndex].Content.(*widget.Table); !ok {
+		t.Fatalf("speed tab content = %T, want *widget.Table", ui.resultsTabs.Items[speedIndex].Content)
+	}
+	if _, ok := ui.resultsTabs.Items[1].Content.(*widget.Label); !ok {
+		t.Fatalf("empty CPU tab content = %T, want *widget.Label", ui.resultsTabs.Items[1].Content)
+	}
+}
+
+func TestUnknownSectionRendersRawCard(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("-----------GPU测试-----------\nGPU Model: NVIDIA T4\n")
+	ui.refreshParsedResults()
+
+	rawIndex := len(parsedResultTabs)
+	if parsedResultTabs[rawIndex-1].titleKey != "results.tab.raw" {
+		t.Fatal("raw tab should be the last result tab")
+	}
+	scroll, ok := ui.resultsTabs.Items[rawIndex].Content.(*container.Scroll)
+	if !ok {
+		t.Fatalf("raw tab content = %T, want *container.Scroll", ui.resultsTabs.Items[rawIndex].Content)
+	}
+	card := scroll.Content.(*fyne.Container).Objects[0].(*widget.Card)
+	if card.Title != "GPU测试" {
+		t.Fatalf("raw card title = %q", card.Title)
+	}
+}
+
+func TestVirtCaveatsFlagAffectedTabs(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("-----------系统基础信息-----------\n 虚拟化架构          : LXC\n")
+	ui.refreshParsedResults()
+
+	for i, tab := range parsedResultTabs {
+		item := ui.resultsTabs.Items[i+1]
+		flagged := strings.HasSuffix(item.Text, " ⚠")
+		if want := tab.titleKey == "results.tab.disk"; flagged != want {
+			t.Errorf("tab %s flagged = %v, want %v", tab.titleKey, flagged, want)
+		}
+	}
+	if ui.resultsTabs.Items[0].Content == ui.resultsOverview {
+		t.Fatal("overview should carry the caveat banner")
+	}
+
+	ui.Terminal.SetFullText("-----------系统基础信息-----------\n 虚拟化架构          : KVM\n")
+	ui.refreshParsedResults()
+	if ui.resultsTabs.Items[0].Content != ui.resultsOverview {
+		t.Fatal("overview should be shown as is without caveats")
+	}
+}
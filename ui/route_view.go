@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// routeTierColor 精品线路为绿色，优质线路为主题色，普通线路保持正文颜色
+func routeTierColor(tier results.RouteTier) fyne.ThemeColorName {
+	switch tier {
+	case results.RouteTierPremium:
+		return theme.ColorNameSuccess
+	case results.RouteTierQuality:
+		return theme.ColorNamePrimary
+	}
+	return theme.ColorNameForeground
+}
+
+// routeNode 是路由路径中连续属于同一 ASN 与网络的若干跳
+type routeNode struct {
+	hop   results.RouteHop
+	count int
+}
+
+// collapseRouteHops 合并连续的同网络跳，保留最后一跳的延迟与位置
+func collapseRouteHops(hops []results.RouteHop) []routeNode {
+	var nodes []routeNode
+	for _, hop := range hops {
+		if n := len(nodes); n > 0 && nodes[n-1].hop.ASN == hop.ASN && nodes[n-1].hop.Owner == hop.Owner {
+			nodes[n-1].hop, nodes[n-1].count = hop, nodes[n-1].count+1
+			continue
+		}
+		nodes = append(nodes, routeNode{hop: hop, count: 1})
+	}
+	return nodes
+}
+
+// routeNodeText 生成路径图中一个节点的文字，如 "AS4809 CN2-BACKBONE 中国, 广东 ×3 160.01ms"
+func routeNodeText(node routeNode) string {
+	parts := make([]string, 0, 5)
+	for _, part := range []string{node.hop.ASN, node.hop.Owner, node.hop.Location} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "*")
+	}
+	if node.count > 1 {
+		parts = append(parts, fmt.Sprintf("×%d", node.count))
+	}
+	if node.hop.RTT != "" && node.hop.RTT != "*" {
+		parts = append(parts, node.hop.RTT)
+	}
+	return strings.Join(parts, " ")
+}
+
+func routeSegment(text string, color fyne.ThemeColorName, bold bool) *widget.TextSegment {
+	return &widget.TextSegment{Text: text, Style: widget.RichTextStyle{ColorName: color, Inline: true, TextStyle: fyne.TextStyle{Bold: bold}}}
+}
+
+// routeResultsView 按目的地显示回程线路等级，以及逐跳路由折叠后的简单路径图
+func (ui *TestUI) routeResultsView(report *results.Report) fyne.CanvasObject {
+	if len(report.Backtrace) == 0 && len(report.Routes) == 0 {
+		return widget.NewLabel(ui.tr("results.empty"))
+	}
+	content := container.NewVBox(ui.routeLegend())
+	if len(report.Backtrace) > 0 {
+		form := container.New(layout.NewFormLayout())
+		for _, result := range report.Backtrace {
+			var segments []widget.RichTextSegment
+			for i, line := range result.Lines {
+				if i > 0 {
+					segments = append(segments, routeSegment("  ", theme.ColorNameForeground, false))
+				}
+				segments = append(segments, routeSegment(line.Name, routeTierColor(line.Tier), line.Tier == results.RouteTierPremium))
+			}
+			if len(segments) == 0 {
+				segments = append(segments, routeSegment(result.Error, theme.ColorNameError, false))
+			}
+			routes := widget.NewRichText(segments...)
+			routes.Wrapping = fyne.TextWrapWord
+			form.Add(widget.NewLabel(result.Destination + "  " + result.Target))
+			form.Add(routes)
+		}
+		content.Add(widget.NewLabelWithStyle(ui.tr("route.backtrace"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		content.Add(form)
+	}
+	if len(report.Routes) > 0 {
+		content.Add(widget.NewLabelWithStyle(ui.tr("route.paths"), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		for _, path := range report.Routes {
+			content.Add(ui.routePathView(path))
+		}
+	}
+	return container.NewVScroll(content)
+}
+
+// routePathView 以 "节点 → 节点" 的形式显示一条路径，节点按线路等级着色
+func (ui *TestUI) routePathView(path results.RoutePath) fyne.CanvasObject {
+	title := strings.TrimSpace(path.Destination + " " + path.Target)
+	var segments []widget.RichTextSegment
+	for i, node := range collapseRouteHops(path.Hops) {
+		if i > 0 {
+			segments = append(segments, routeSegment(" → ", theme.ColorNamePlaceHolder, false))
+		}
+		segments = append(segments, routeSegment(routeNodeText(node), routeTierColor(node.hop.Tier), node.hop.Tier == results.RouteTierPremium))
+	}
+	if len(segments) == 0 {
+		segments = append(segments, routeSegment(ui.tr("route.no_hops"), theme.ColorNameError, false))
+	}
+	diagram := widget.NewRichText(segments...)
+	diagram.Wrapping = fyne.TextWrapWord
+	return widget.NewCard("", title, diagram)
+}
+
+// routeLegend 说明三种线路等级对应的颜色
+func (ui *TestUI) routeLegend() fyne.CanvasObject {
+	return widget.NewRichText(
+		routeSegment("■ "+ui.tr("route.tier.premium")+"   ", routeTierColor(results.RouteTierPremium), true),
+		routeSegment("■ "+ui.tr("route.tier.quality")+"   ", routeTierColor(results.RouteTierQuality), false),
+		routeSegment("■ "+ui.tr("route.tier.ordinary"), routeTierColor(results.RouteTierOrdinary), false),
+	)
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestCollapseRouteHopsMergesSameNetwork(t *testing.T) {
+	nodes := collapseRouteHops([]results.RouteHop{
+		{RTT: "0.5ms", ASN: "AS906", Owner: "DMIT"},
+		{RTT: "150ms", ASN: "AS4809", Owner: "CN2-BACKBONE", Location: "中国, 上海", Tier: results.RouteTierPremium},
+		{RTT: "160ms", ASN: "AS4809", Owner: "CN2-BACKBONE", Location: "中国, 广东", Tier: results.RouteTierPremium},
+	})
+	if len(nodes) != 2 || nodes[1].count != 2 {
+		t.Fatalf("nodes = %+v", nodes)
+	}
+	if got := routeNodeText(nodes[1]); got != "AS4809 CN2-BACKBONE 中国, 广东 ×2 160ms" {
+		t.Fatalf("routeNodeText() = %q", got)
+	}
+}
+
+func TestRouteResultsView(t *testing.T) {
+	ui := newTestUIForTest(t)
+	if _, ok := ui.routeResultsView(&results.Report{}).(*widget.Label); !ok {
+		t.Fatal("empty route view should be a label")
+	}
+	report := &results.Report{Backtrace: []results.BacktraceResult{{Destination: "上海移动v4", Target: "211.136.112.200", Lines: []results.RouteLine{{Name: "移动CMIN2", Tier: results.RouteTierPremium}}}}}
+	if _, ok := ui.routeResultsView(report).(*container.Scroll); !ok {
+		t.Fatal("route view should scroll")
+	}
+}
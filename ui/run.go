@@ -0,0 +1,142 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/expect"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// Run 是一次测试运行。Context 负责取消、超时与暂停，Host、Tests、Label 与 Started 用于日志和历史记录的归属，
+// 输出与进度经 Output 与 Progress 发出；执行器、结果记录与界面都从同一个 Run 取得这些信息，
+// 同时进行的多个运行互不影响
+type Run struct {
+	Context context.Context
+	Config  ExecutionConfig
+	Host    string    // 目标主机，见 runHost
+	Tests   []string  // 所选测试项
+	Label   string    // 独立运行标签页或批量测试中的名称，主运行为空
+	Started time.Time // 开始时间
+
+	output    func(string)
+	progress  func(ProgressUpdate)
+	input     chan string
+	stdin     io.Reader         // 本机运行启动的外部工具读取的标准输入，见 Stdin
+	responder *expect.Responder // 按 Config.Expect 自动回应提示，没有规则时为 nil
+	// sudoPassword 在远程 sudo 需要密码时询问，为 nil 时不询问（批量与命令行运行）
+	sudoPassword func(ctx context.Context, target remote.Target) string
+
+	mu        sync.Mutex
+	artifacts []history.Artifact // 从远程目标取回的文件，由 mu 保护
+}
+
+// runInputBuffer 是尚未被执行器取走的输入行数上限
+const runInputBuffer = 16
+
+// newRun 创建一次运行；ctx 为 nil 时运行不可取消，output 与 progress 可以为 nil
+func newRun(ctx context.Context, config ExecutionConfig, output func(string), progress func(ProgressUpdate)) *Run {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return &Run{
+		Context:   ctx,
+		Config:    config,
+		Host:      runHost(config),
+		Tests:     journalTests(config),
+		Started:   time.Now(),
+		output:    output,
+		progress:  progress,
+		input:     make(chan string, runInputBuffer),
+		responder: expect.NewResponder(config.Expect.Rules()),
+	}
+}
+
+// SendInput 把一行文本发往运行中进程的标准输入，缓冲已满时返回 false，可在任意 goroutine 调用
+func (r *Run) SendInput(line string) bool {
+	select {
+	case r.input <- line + "\n":
+		return true
+	default:
+		return false
+	}
+}
+
+// Input 返回待写入标准输入的文本，执行器把它转给子进程或 SSH 会话
+func (r *Run) Input() <-chan string {
+	return r.input
+}
+
+// Stdin 返回本机运行期间承载 Input 的读取端，交给运行中启动的外部工具；不在本机运行中时为 nil
+func (r *Run) Stdin() io.Reader {
+	return r.stdin
+}
+
+// Output 发出一段输出，输出停在已知的提示上时按规则自动回复并注明，可在任意 goroutine 调用
+func (r *Run) Output(text string) {
+	if r.output != nil {
+		r.output(text)
+	}
+	if r.responder == nil {
+		return
+	}
+	for _, rule := range r.responder.Feed(text) {
+		if !r.SendInput(rule.Reply) {
+			continue
+		}
+		if r.output != nil {
+			r.output(fmt.Sprintf(pickLanguage(r.Config.Language, "\n[自动回复] %s → %q\n", "\n[auto reply] %s → %q\n"), rule.Name, rule.Reply))
+		}
+	}
+}
+
+// Progress 发出一次进度更新，可在任意 goroutine 调用
+func (r *Run) Progress(update ProgressUpdate) {
+	if r.progress != nil {
+		r.progress(update)
+	}
+}
+
+// Elapsed 返回自开始以来经过的时间
+func (r *Run) Elapsed() time.Duration {
+	return durationSince(r.Started)
+}
+
+// AddArtifacts 记录从远程目标取回的文件，随历史记录保存，可在任意 goroutine 调用
+func (r *Run) AddArtifacts(artifacts []history.Artifact) {
+	r.mu.Lock()
+	r.artifacts = append(r.artifacts, artifacts...)
+	r.mu.Unlock()
+}
+
+// Artifacts 返回已取回的文件
+func (r *Run) Artifacts() []history.Artifact {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.artifacts)
+}
+
+// Record 把本次运行的输出与解析结果整理为历史记录，statusKey 为 status.done 等状态键
+func (r *Run) Record(statusKey string, finished time.Time, output string, report *results.Report) history.Run {
+	return history.Run{
+		Summary: history.Summary{
+			StartedAt:  r.Started,
+			FinishedAt: finished,
+			Status:     strings.TrimPrefix(statusKey, "status."),
+			Host:       r.Host,
+			Preset:     r.Config.PresetKey,
+			Label:      r.Label,
+			Tests:      r.Tests,
+		},
+		Output:    output,
+		Results:   report,
+		Artifacts: r.Artifacts(),
+	}
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/hooks"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const hooksDirName = "hooks"
+
+// runHooks 是一次主运行使用的钩子脚本，脚本输出与错误写入终端
+type runHooks struct {
+	engine *hooks.Engine
+	run    hooks.Run
+}
+
+// loadRunHooks 每次运行前重新加载脚本，修改后无需重启；print 接收脚本输出
+func (ui *TestUI) loadRunHooks(config ExecutionConfig, startTime time.Time, print func(string)) *runHooks {
+	engine, err := hooks.Load(ui.appDataDir(hooksDirName), hooks.Options{
+		Print:  func(msg string) { print(ui.tr("hooks.prefix") + msg + "\n") },
+		Client: proxyHTTPClient(ui.proxySettings(), proxy.Notifications, hooks.DefaultTimeout),
+	})
+	if err != nil {
+		runnerLog.Warn("hook scripts failed to load", "err", err)
+		print(fmt.Sprintf(ui.tr("hooks.load_failed"), err) + "\n")
+	}
+	if engine.Empty() {
+		return nil
+	}
+	runnerLog.Info("hook scripts loaded", "scripts", engine.Scripts())
+	return &runHooks{engine: engine, run: hooks.Run{
+		Host:      runHost(config),
+		Preset:    config.PresetKey,
+		Tests:     journalTests(config),
+		StartedAt: startTime,
+	}}
+}
+
+func (h *runHooks) preRun() error {
+	if h == nil {
+		return nil
+	}
+	return h.engine.PreRun(h.run)
+}
+
+// stageComplete 传给脚本的阶段名去掉了 "progress." 前缀，如 "cpu"、"ip_quality"
+func (h *runHooks) stageComplete(itemKey string) {
+	if h == nil {
+		return
+	}
+	h.engine.StageComplete(h.run, strings.TrimPrefix(itemKey, "progress."))
+}
+
+// postRun 传给脚本的状态为 "done"、"failed" 或 "stopped"，结果为解析后的报告
+func (h *runHooks) postRun(statusKey string, parsed *results.Report) error {
+	if h == nil {
+		return nil
+	}
+	if parsed == nil {
+		parsed = &results.Report{}
+	}
+	return h.engine.PostRun(h.run, strings.TrimPrefix(statusKey, "status."), parsed)
+}
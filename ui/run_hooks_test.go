@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/hooks"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestRunHooksReceiveStagesAndResults(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	dir := ui.appDataDir(hooksDirName)
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	script := `
+def stage_complete(run, stage):
+    print("stage", stage)
+
+def post_run(run, status, results):
+    if status == "done" and not results.get("cpu"):
+        return "no cpu score for " + run.host
+`
+	if err := os.WriteFile(filepath.Join(dir, "policy.star"), []byte(script), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var out strings.Builder
+	config := ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.9"}, SelectedOptions: map[string]bool{"cpu": true}}
+	scripts := ui.loadRunHooks(config, time.Now(), func(text string) { out.WriteString(text) })
+	if scripts == nil {
+		t.Fatal("the hook script should be loaded")
+	}
+	if err := scripts.preRun(); err != nil {
+		t.Fatalf("preRun() = %v", err)
+	}
+	scripts.stageComplete("progress.cpu")
+	if want := ui.tr("hooks.prefix") + "[policy.star] stage cpu\n"; out.String() != want {
+		t.Fatalf("hook output = %q, want %q", out.String(), want)
+	}
+
+	var rejection *hooks.Rejection
+	if err := scripts.postRun("status.done", nil); !errors.As(err, &rejection) || rejection.Reason != "no cpu score for 10.0.0.9" {
+		t.Fatalf("postRun(empty) = %v", err)
+	}
+	parsed := &results.Report{CPU: []results.CPUScore{{Label: "single"}}}
+	if err := scripts.postRun("status.done", parsed); err != nil {
+		t.Fatalf("postRun(with cpu) = %v", err)
+	}
+}
+
+func TestLoadRunHooksWithoutScripts(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	os.RemoveAll(ui.appDataDir(hooksDirName))
+	scripts := ui.loadRunHooks(ExecutionConfig{}, time.Now(), func(string) { t.Fatal("nothing should be printed") })
+	if scripts != nil {
+		t.Fatal("no scripts should mean no hooks")
+	}
+	if err := scripts.postRun("status.done", nil); err != nil {
+		t.Fatalf("nil hooks postRun() = %v", err)
+	}
+}
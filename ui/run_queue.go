@@ -0,0 +1,355 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	queueConcurrencyPreferenceKey = "queue_concurrency"
+	defaultQueueConcurrency       = 2
+)
+
+// queueJob 是运行队列中的一项：一台主机与入队时的测试配置
+type queueJob struct {
+	name      string
+	config    ExecutionConfig
+	statusKey string // queue.pending、status.running、status.done 等
+	tab       *runTab
+}
+
+func (job *queueJob) pending() bool { return job.statusKey == "queue.pending" }
+func (job *queueJob) running() bool { return job.statusKey == "status.running" }
+
+// runQueue 按顺序执行排队的运行，同时运行的数量不超过 limit。
+// 排在前面的任务优先启动；本机任务同一时间只运行一个，等待时后面的远程任务可以先启动。
+// 每个任务在自己的运行标签页中执行。除 limit 的偏好设置外，所有字段仅在 UI 线程访问。
+type runQueue struct {
+	ui    *TestUI
+	jobs  []*queueJob
+	limit int
+
+	// 队列窗口，未打开时为 nil
+	window fyne.Window
+	list   *widget.List
+	status *widget.Label
+}
+
+// runQueue 返回运行队列，首次调用时创建
+func (ui *TestUI) runQueue() *runQueue {
+	if ui.queue == nil {
+		limit := defaultQueueConcurrency
+		if ui.App != nil {
+			limit = ui.App.Preferences().IntWithFallback(queueConcurrencyPreferenceKey, defaultQueueConcurrency)
+		}
+		ui.queue = &runQueue{ui: ui, limit: min(max(limit, 1), maxBatchConcurrency)}
+	}
+	return ui.queue
+}
+
+// add 把一项运行加入队尾并尝试启动
+func (q *runQueue) add(name string, config ExecutionConfig) *queueJob {
+	job := &queueJob{name: name, config: config, statusKey: "queue.pending"}
+	q.jobs = append(q.jobs, job)
+	q.dispatch()
+	return job
+}
+
+// dispatch 按队列顺序启动等待中的任务，直到达到并发上限
+func (q *runQueue) dispatch() {
+	running := 0
+	for _, job := range q.jobs {
+		if job.running() {
+			running++
+		}
+	}
+	for _, job := range q.jobs {
+		if running >= q.limit {
+			break
+		}
+		if !job.pending() || !q.ui.reserveTabRun(job.config) {
+			continue
+		}
+		q.start(job)
+		running++
+	}
+	q.refresh()
+}
+
+func (q *runQueue) start(job *queueJob) {
+	job.statusKey = "status.running"
+	job.tab = q.ui.openRunTab(job.config)
+	job.tab.onFinish = func(statusKey string) {
+		job.statusKey = statusKey
+		q.dispatch()
+	}
+	job.tab.start()
+}
+
+// move 把等待中的任务与相邻的等待任务交换位置，delta 为 -1 表示提前，1 表示推后
+func (q *runQueue) move(job *queueJob, delta int) {
+	from := q.index(job)
+	if from < 0 || !job.pending() {
+		return
+	}
+	for to := from + delta; to >= 0 && to < len(q.jobs); to += delta {
+		if q.jobs[to].pending() {
+			q.jobs[from], q.jobs[to] = q.jobs[to], q.jobs[from]
+			break
+		}
+	}
+	q.refresh()
+}
+
+// cancel 取消任务：等待中的任务不再启动，运行中的任务停止
+func (q *runQueue) cancel(job *queueJob) {
+	switch {
+	case job.pending():
+		job.statusKey = "queue.cancelled"
+		q.refresh()
+	case job.running():
+		job.tab.stop()
+	}
+}
+
+// cancelPending 取消所有等待中的任务，用于关闭主窗口
+func (q *runQueue) cancelPending() {
+	for _, job := range q.jobs {
+		if job.pending() {
+			job.statusKey = "queue.cancelled"
+		}
+	}
+}
+
+// clearFinished 从队列中移除已结束与已取消的任务
+func (q *runQueue) clearFinished() {
+	kept := q.jobs[:0]
+	for _, job := range q.jobs {
+		if job.pending() || job.running() {
+			kept = append(kept, job)
+		}
+	}
+	clear(q.jobs[len(kept):])
+	q.jobs = kept
+	q.refresh()
+}
+
+// setLimit 修改并发上限并保存，提高上限时立即启动更多任务
+func (q *runQueue) setLimit(limit int) {
+	q.limit = min(max(limit, 1), maxBatchConcurrency)
+	if q.ui.App != nil {
+		q.ui.App.Preferences().SetInt(queueConcurrencyPreferenceKey, q.limit)
+	}
+	q.dispatch()
+}
+
+func (q *runQueue) index(job *queueJob) int {
+	for i, j := range q.jobs {
+		if j == job {
+			return i
+		}
+	}
+	return -1
+}
+
+// counts 返回等待、运行中与已结束的任务数
+func (q *runQueue) counts() (pending, running, finished int) {
+	for _, job := range q.jobs {
+		switch {
+		case job.pending():
+			pending++
+		case job.running():
+			running++
+		default:
+			finished++
+		}
+	}
+	return
+}
+
+// setEnabled 按 on 启用或禁用按钮
+func setEnabled(button *widget.Button, on bool) {
+	if on {
+		button.Enable()
+	} else {
+		button.Disable()
+	}
+}
+
+func (q *runQueue) jobLabel(job *queueJob) string {
+	return job.name + " · " + q.ui.presetLabelByKey(job.config.PresetKey)
+}
+
+func (q *runQueue) refresh() {
+	if q.window == nil {
+		return
+	}
+	pending, running, finished := q.counts()
+	q.status.SetText(fmt.Sprintf(q.ui.tr("queue.summary"), pending, running, finished))
+	q.list.Refresh()
+}
+
+// showQueueWindow 打开运行队列窗口：任务列表、调整顺序、取消与并发上限
+func (ui *TestUI) showQueueWindow() {
+	q := ui.runQueue()
+	if q.window != nil {
+		q.window.RequestFocus()
+		return
+	}
+	q.status = widget.NewLabel("")
+	q.list = widget.NewList(
+		func() int { return len(q.jobs) },
+		func() fyne.CanvasObject {
+			up := widget.NewButtonWithIcon("", theme.MoveUpIcon(), nil)
+			down := widget.NewButtonWithIcon("", theme.MoveDownIcon(), nil)
+			cancel := widget.NewButtonWithIcon("", theme.CancelIcon(), nil)
+			open := widget.NewButtonWithIcon("", theme.VisibilityIcon(), nil)
+			return container.NewBorder(nil, nil, nil,
+				container.NewHBox(widget.NewLabel(""), open, up, down, cancel),
+				widget.NewLabel(""))
+		},
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(q.jobs) {
+				return
+			}
+			job := q.jobs[id]
+			row := obj.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(q.jobLabel(job))
+			buttons := row.Objects[1].(*fyne.Container).Objects
+			buttons[0].(*widget.Label).SetText(ui.tr(job.statusKey))
+			open, up, down, cancel := buttons[1].(*widget.Button), buttons[2].(*widget.Button), buttons[3].(*widget.Button), buttons[4].(*widget.Button)
+			open.OnTapped = func() {
+				if job.tab != nil && ui.findRunTab(job.tab.item) != nil {
+					ui.runTabs.Select(job.tab.item)
+					ui.showResultTab()
+					ui.Window.RequestFocus()
+				}
+			}
+			up.OnTapped = func() { q.move(job, -1) }
+			down.OnTapped = func() { q.move(job, 1) }
+			cancel.OnTapped = func() { q.cancel(job) }
+			setEnabled(open, job.tab != nil)
+			setEnabled(up, job.pending())
+			setEnabled(down, job.pending())
+			setEnabled(cancel, job.pending() || job.running())
+		},
+	)
+
+	limit := widget.NewEntry()
+	limit.SetText(strconv.Itoa(q.limit))
+	limit.OnSubmitted = func(text string) {
+		if n, err := strconv.Atoi(strings.TrimSpace(text)); err == nil && n > 0 {
+			q.setLimit(n)
+		}
+		limit.SetText(strconv.Itoa(q.limit))
+	}
+	addButton := widget.NewButtonWithIcon(ui.tr("queue.add"), theme.ContentAddIcon(), ui.showEnqueueDialog)
+	clearButton := widget.NewButtonWithIcon(ui.tr("queue.clear_finished"), theme.DeleteIcon(), q.clearFinished)
+	header := container.NewHBox(q.status, layout.NewSpacer(),
+		widget.NewLabel(ui.tr("queue.concurrency")), limit, addButton, clearButton)
+
+	win := ui.App.NewWindow(ui.tr("queue.title"))
+	win.SetContent(container.NewBorder(header, nil, nil, nil, q.list))
+	win.Resize(fyne.NewSize(820, 480))
+	win.SetOnClosed(func() { q.window = nil })
+	q.window = win
+	q.refresh()
+	win.Show()
+}
+
+// showEnqueueDialog 选择目标主机，以当前配置页的测试参数为每台主机加入一项运行。
+// 修改预设后再次加入即可为同一主机排队多个预设。
+func (ui *TestUI) showEnqueueDialog() {
+	if !ui.hasSelectedTests() {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_tests"), ui.Window)
+		return
+	}
+	if _, err := ui.remoteTarget(); err != nil {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.remote_invalid")+"\n"+err.Error(), ui.Window)
+		return
+	}
+	config := ui.collectExecutionConfig()
+	current := fmt.Sprintf(ui.tr("queue.current_target"), runHost(config))
+	names := []string{current}
+	if ui.hostProfiles != nil {
+		for _, profile := range ui.hostProfiles.List() {
+			names = append(names, profile.Name)
+		}
+	}
+	group := widget.NewCheckGroup(names, nil)
+	group.SetSelected([]string{current})
+	scroll := container.NewVScroll(group)
+	scroll.SetMinSize(fyne.NewSize(420, 220))
+	var top fyne.CanvasObject = widget.NewLabel(fmt.Sprintf(ui.tr("queue.preset"), ui.presetLabelByKey(config.PresetKey)))
+	var dlg *dialog.ConfirmDialog
+	if ui.hostProfiles == nil {
+		unlock := widget.NewButton(ui.tr("queue.unlock_hosts"), func() {
+			dlg.Hide()
+			ui.unlockHostProfiles(ui.showEnqueueDialog)
+		})
+		top = container.NewHBox(top, layout.NewSpacer(), unlock)
+	}
+	dlg = dialog.NewCustomConfirm(ui.tr("queue.add"), ui.tr("queue.add"), ui.tr("compare.cancel"),
+		container.NewBorder(top, nil, nil, nil, scroll), func(ok bool) {
+			if !ok || len(group.Selected) == 0 {
+				return
+			}
+			ui.enqueueRuns(config, current, group.Selected)
+		}, ui.Window)
+	dlg.Show()
+}
+
+// enqueueRuns 确认权限等启动条件后把所选主机加入队列；current 表示配置页当前的目标
+func (ui *TestUI) enqueueRuns(config ExecutionConfig, current string, names []string) {
+	type entry struct {
+		name   string
+		config ExecutionConfig
+	}
+	var entries []entry
+	needsLocal := false
+	for _, name := range names {
+		if name == current {
+			entries = append(entries, entry{runHost(config), config})
+			needsLocal = needsLocal || config.local()
+			continue
+		}
+		target, err := ui.hostProfiles.Resolve(name, ui.knownHostsPath())
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		hostConfig := config
+		hostConfig.Remote = &target
+		hostConfig.Docker = nil
+		hostConfig.RemoteBinary = strings.TrimSpace(ui.RemoteBinaryEntry.Text)
+		entries = append(entries, entry{name, hostConfig})
+	}
+	enqueue := func() {
+		q := ui.runQueue()
+		for _, e := range entries {
+			q.add(e.name, e.config)
+		}
+		ui.showQueueWindow()
+	}
+	if !needsLocal {
+		// 远程主机不需要本机的权限与磁盘确认
+		enqueue()
+		return
+	}
+	ui.confirmLaunch(config, func(confirmed ExecutionConfig) {
+		for i := range entries {
+			if entries[i].config.local() {
+				entries[i].config = confirmed
+			}
+		}
+		enqueue()
+	}, func() {})
+}
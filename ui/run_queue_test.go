@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+// blockingRunner 在 release 关闭或上下文取消前不会结束
+type blockingRunner struct {
+	release chan struct{}
+}
+
+func (r blockingRunner) Run(run *Run) executionOutcome {
+	select {
+	case <-r.release:
+		run.Output("done\n")
+		return executionOutcome{}
+	case <-run.Context.Done():
+		return executionOutcome{Err: run.Context.Err()}
+	}
+}
+
+func TestRunQueueRespectsLimitAndOrder(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	t.Cleanup(ui.closeAllRunTabs)
+	// 通道在启动任何任务前建好，运行中的任务只读取这张表
+	release := map[string]chan struct{}{}
+	for _, host := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		release[host] = make(chan struct{})
+	}
+	original := runTabRunner
+	t.Cleanup(func() { runTabRunner = original })
+	runTabRunner = func(config ExecutionConfig) executionRunner {
+		return blockingRunner{release: release[config.Remote.Host]}
+	}
+	remoteConfig := func(host string) ExecutionConfig {
+		return ExecutionConfig{Remote: &remote.Target{Host: host}}
+	}
+
+	q := ui.runQueue()
+	q.limit = 1
+	a := q.add("a", remoteConfig("10.0.0.1"))
+	b := q.add("b", remoteConfig("10.0.0.2"))
+	c := q.add("c", remoteConfig("10.0.0.3"))
+	if !a.running() || !b.pending() || !c.pending() {
+		t.Fatalf("states = %s %s %s, want only the first running", a.statusKey, b.statusKey, c.statusKey)
+	}
+
+	q.move(c, -1)
+	if q.jobs[1] != c || q.jobs[2] != b {
+		t.Fatal("moving c up should put it before b")
+	}
+	q.cancel(b)
+	if b.statusKey != "queue.cancelled" {
+		t.Fatalf("b = %s, want cancelled", b.statusKey)
+	}
+
+	close(release["10.0.0.1"])
+	waitClosed(t, a.tab.done)
+	if a.statusKey != "status.done" || !c.running() {
+		t.Fatalf("after a finished: a = %s, c = %s", a.statusKey, c.statusKey)
+	}
+	q.cancel(c)
+	waitClosed(t, c.tab.done)
+	if c.statusKey != "status.stopped" || b.tab != nil {
+		t.Fatalf("c = %s, b started = %v", c.statusKey, b.tab != nil)
+	}
+
+	q.clearFinished()
+	if len(q.jobs) != 0 {
+		t.Fatalf("jobs after clearing = %d", len(q.jobs))
+	}
+}
+
+func TestRunQueueRunsOneLocalJobAtATime(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	t.Cleanup(ui.closeAllRunTabs)
+	release := make(chan struct{})
+	original := runTabRunner
+	t.Cleanup(func() { runTabRunner = original })
+	runTabRunner = func(ExecutionConfig) executionRunner { return blockingRunner{release: release} }
+
+	q := ui.runQueue()
+	q.limit = 3
+	first := q.add("local", ExecutionConfig{})
+	second := q.add("local", ExecutionConfig{})
+	remoteJob := q.add("remote", ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.1"}})
+	if !first.running() || !second.pending() || !remoteJob.running() {
+		t.Fatalf("states = %s %s %s, want the second local job to wait", first.statusKey, second.statusKey, remoteJob.statusKey)
+	}
+	close(release)
+	waitClosed(t, first.tab.done)
+	waitClosed(t, remoteJob.tab.done)
+	if second.tab == nil {
+		t.Fatal("second local job should start after the first finished")
+	}
+	waitClosed(t, second.tab.done)
+}
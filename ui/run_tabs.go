@@ -0,0 +1,370 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// runTabRunner 返回标签页运行使用的执行器，测试中可替换
+var runTabRunner = executionRunnerFor
+
+// runTab 是结果页中一个独立的运行标签页，拥有自己的终端、进度与结果面板
+type runTab struct {
+	ui     *TestUI
+	title  string
+	config ExecutionConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	// 以下字段仅在 UI 线程访问
+	item       *container.TabItem
+	terminal   *TerminalOutput
+	progress   *widget.ProgressBar
+	status     *widget.Label
+	current    *widget.Label
+	results    *container.AppTabs
+	stopButton *widget.Button
+	stdin      *stdinBar
+	// onFinish 在运行结束后于 UI 线程调用
+	onFinish func(statusKey string)
+	// rerun 非空时这是结果面板中的单项重跑，结果合并进原记录而不另存
+	rerun *stageRerun
+
+	// 以下字段由 mu 保护
+	mu        sync.Mutex
+	output    strings.Builder
+	statusKey string
+	started   time.Time
+	finished  time.Time
+	report    *results.Report
+}
+
+// createRunTabs 把主运行的结果视图放入第一个标签页（不可关闭），其后为独立运行的标签页
+func (ui *TestUI) createRunTabs(main fyne.CanvasObject) *container.DocTabs {
+	ui.mainRunItem = container.NewTabItemWithIcon(ui.tr("run_tabs.main"), theme.HomeIcon(), main)
+	ui.runTabs = container.NewDocTabs(ui.mainRunItem)
+	ui.runTabs.CloseIntercept = func(item *container.TabItem) {
+		if tab := ui.findRunTab(item); tab != nil {
+			ui.closeRunTab(tab)
+		}
+	}
+	return ui.runTabs
+}
+
+// createRunTabsButton 创建运行标签页的概览菜单按钮：新建运行、运行队列、切换标签页与关闭已结束的标签页
+func (ui *TestUI) createRunTabsButton() *widget.Button {
+	button := widget.NewButtonWithIcon(ui.tr("run_tabs.menu"), theme.ListIcon(), nil)
+	button.OnTapped = func() {
+		menu := fyne.NewMenu("", ui.runTabsMenuItems()...)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(button)
+		if canvas == nil {
+			return
+		}
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
+	}
+	return button
+}
+
+// runTabsMenuItems 返回概览菜单项，每个标签页显示标题与当前状态
+func (ui *TestUI) runTabsMenuItems() []*fyne.MenuItem {
+	items := []*fyne.MenuItem{
+		fyne.NewMenuItem(ui.tr("run_tabs.new"), ui.startRunTab),
+		fyne.NewMenuItem(ui.tr("queue.title"), ui.showQueueWindow),
+		fyne.NewMenuItemSeparator(),
+	}
+	ui.Mu.Lock()
+	mainStatus := "status.ready"
+	if ui.IsRunning {
+		mainStatus = "status.running"
+	}
+	ui.Mu.Unlock()
+	items = append(items, fyne.NewMenuItem(ui.tr("run_tabs.main")+" — "+ui.tr(mainStatus), func() {
+		ui.runTabs.Select(ui.mainRunItem)
+	}))
+	for _, tab := range ui.extraRuns {
+		items = append(items, fyne.NewMenuItem(tab.title+" — "+ui.tr(tab.currentStatus()), func() {
+			ui.runTabs.Select(tab.item)
+		}))
+	}
+	closeFinished := fyne.NewMenuItem(ui.tr("run_tabs.close_finished"), ui.closeFinishedRunTabs)
+	closeFinished.Disabled = len(ui.extraRuns) == 0
+	return append(items, fyne.NewMenuItemSeparator(), closeFinished)
+}
+
+// startRunTab 按当前配置在新标签页中启动一次独立运行，不影响主运行的终端与状态
+func (ui *TestUI) startRunTab() {
+	if !ui.hasSelectedTests() {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_tests"), ui.Window)
+		return
+	}
+	if _, err := ui.remoteTarget(); err != nil {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.remote_invalid")+"\n"+err.Error(), ui.Window)
+		return
+	}
+	config := ui.collectExecutionConfig()
+	_ = ui.saveSettings()
+	if !ui.reserveTabRun(config) {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("run_tabs.local_busy"), ui.Window)
+		return
+	}
+	ui.confirmLaunch(config, func(config ExecutionConfig) {
+		tab := ui.openRunTab(config)
+		ui.runTabs.Select(tab.item)
+		ui.showResultTab()
+		tab.start()
+	}, func() { ui.releaseTabRun(config) })
+}
+
+// reserveTabRun 为本机运行登记占用：多个本机测试会互相争用 CPU、磁盘与网络，
+// 同一时间只允许一个（主运行或标签页运行），远程与容器运行不受限制
+func (ui *TestUI) reserveTabRun(config ExecutionConfig) bool {
+	if !config.local() {
+		return true
+	}
+	ui.Mu.Lock()
+	defer ui.Mu.Unlock()
+	if ui.localTabRuns > 0 || (ui.IsRunning && ui.mainRunLocal) {
+		return false
+	}
+	ui.localTabRuns++
+	return true
+}
+
+func (ui *TestUI) releaseTabRun(config ExecutionConfig) {
+	if !config.local() {
+		return
+	}
+	ui.Mu.Lock()
+	ui.localTabRuns--
+	ui.Mu.Unlock()
+}
+
+// localTabRunActive 返回是否有标签页正在本机运行，调用方需持有 ui.Mu
+func (ui *TestUI) localTabRunActive() bool {
+	return ui.localTabRuns > 0
+}
+
+// openRunTab 创建运行标签页（不切换到该页），需在 UI 线程调用；调用方已通过 reserveTabRun 登记
+func (ui *TestUI) openRunTab(config ExecutionConfig) *runTab {
+	ui.runTabSeq++
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	tab := &runTab{
+		ui:        ui,
+		title:     fmt.Sprintf(ui.tr("run_tabs.title"), ui.runTabSeq, runHost(config)),
+		config:    config,
+		ctx:       ctx,
+		cancel:    cancel,
+		done:      make(chan struct{}),
+		statusKey: "status.running",
+	}
+	ui.addRunTab(tab, theme.MediaPlayIcon(), ui.tr("progress.precheck"))
+	return tab
+}
+
+// addRunTab 创建标签页的终端、进度与结果面板并加入结果页，需在 UI 线程调用
+func (ui *TestUI) addRunTab(tab *runTab, icon fyne.Resource, current string) {
+	tab.terminal = NewTerminalOutput()
+	tab.progress = widget.NewProgressBar()
+	tab.status = widget.NewLabel(ui.tr(tab.statusKey))
+	tab.current = widget.NewLabel(current)
+	tab.terminal.Translate = ui.tr
+	tab.terminal.IPActions = ui.ipMenuItems
+	ui.applyTerminalFont(tab.terminal)
+	tab.terminal.SetGutter(ui.terminalGutterSetting())
+	tab.terminal.Bookmarkable = true
+	tab.terminal.SetWrap(ui.terminalWrap)
+	ui.setupTerminalFolds(tab.terminal, func() bool { return tab.currentStatus() == "status.running" })
+	if redact := ui.redactor(runHost(tab.config)); redact != nil {
+		tab.terminal.SetRedact(redact)
+	}
+
+	var items []*container.TabItem
+	for _, result := range parsedResultTabs {
+		items = append(items, container.NewTabItem(ui.tr(result.titleKey), widget.NewLabel(ui.tr("results.empty"))))
+	}
+	tab.results = container.NewAppTabs(items...)
+	tab.stopButton = widget.NewButtonWithIcon(ui.tr("button.stop"), theme.MediaStopIcon(), tab.stop)
+
+	header := container.NewVBox(
+		container.NewHBox(tab.status, layout.NewSpacer(), tab.stopButton),
+		tab.current,
+		tab.progress,
+	)
+	tab.stdin = ui.newStdinBar()
+	terminal := container.NewBorder(nil, tab.stdin.content, nil, nil, newTerminalFollower(tab.terminal, ui.tr).Content())
+	split := container.NewVSplit(terminal, tab.results)
+	split.Offset = 0.68
+	tab.item = container.NewTabItemWithIcon(tab.title, icon, container.NewBorder(header, nil, nil, nil, split))
+
+	ui.extraRuns = append(ui.extraRuns, tab)
+	ui.runTabs.Append(tab.item)
+}
+
+// start 在后台执行测试，需在 UI 线程调用
+func (tab *runTab) start() {
+	run := newRun(tab.ctx, tab.config, func(text string) {
+		tab.mu.Lock()
+		tab.output.WriteString(text)
+		tab.mu.Unlock()
+		tab.terminal.AppendText(text)
+		tab.stdin.Observe(text)
+	}, func(update ProgressUpdate) {
+		tab.ui.runOnUI(func() {
+			tab.progress.SetValue(update.Fraction)
+			tab.current.SetText(tab.ui.tr(update.ItemKey))
+		})
+	})
+	run.Label = tab.title
+	run.sudoPassword = tab.ui.askSudoPassword
+	tab.mu.Lock()
+	tab.started = run.Started
+	tab.mu.Unlock()
+	tab.stdin.attach(run)
+	tab.stdin.setEnabled(true)
+	go func() {
+		defer close(tab.done)
+		defer tab.stdin.detach()
+		runnerLog.Info("tab run started", "tab", tab.title, "host", run.Host)
+		outcome := executeWithRunner(runTabRunner(tab.config), run)
+		tab.finish(run, outcome.Err)
+	}()
+}
+
+// stop 停止运行：先更新界面再取消，运行结束时的界面更新不会被"正在停止"覆盖
+func (tab *runTab) stop() {
+	tab.stopButton.Disable()
+	tab.status.SetText(tab.ui.tr("status.stopping"))
+	tab.cancel()
+}
+
+// finish 解析结果、写入历史记录并刷新标签页
+func (tab *runTab) finish(run *Run, err error) {
+	ui := tab.ui
+	tab.cancel()
+	ui.releaseTabRun(tab.config)
+
+	tab.mu.Lock()
+	tab.finished = time.Now()
+	switch {
+	case err == nil:
+		tab.statusKey = "status.done"
+	case errors.Is(err, context.Canceled):
+		tab.statusKey = "status.stopped"
+	default:
+		tab.statusKey = "status.failed"
+	}
+	output := results.StripANSI(tab.output.String())
+	tab.report = results.Parse(output)
+	finished, statusKey, report := tab.finished, tab.statusKey, tab.report
+	tab.mu.Unlock()
+	runnerLog.Info("tab run finished", "tab", tab.title, "status", statusKey, "duration", finished.Sub(run.Started), "err", err)
+
+	if err != nil {
+		tab.terminal.AppendText(fmt.Sprintf("\n%s%s\n", ui.tr("log.error_prefix"), ui.friendlyErrorMessage(err)))
+	}
+	switch {
+	case tab.rerun != nil:
+		if statusKey == "status.done" {
+			if err := ui.mergeStageRerun(tab.rerun, finished, output, report); err != nil {
+				tab.terminal.AppendText(fmt.Sprintf("\n%s%v\n", ui.tr("rerun.merge_failed"), err))
+			}
+		}
+	case strings.TrimSpace(output) != "":
+		ui.saveHistoryRun(run.Record(statusKey, finished, output, report))
+	}
+	if statusKey == "status.done" {
+		ui.checkBaselineRegression(run.Host, report, tab.terminal.AppendText)
+	}
+	ui.runOnUI(func() {
+		tab.stopButton.Disable()
+		tab.stdin.setEnabled(false)
+		tab.status.SetText(ui.tr(statusKey))
+		tab.current.SetText("")
+		tab.terminal.sync() // 最后一个阶段也已结束，按需自动折叠
+		if statusKey == "status.done" {
+			tab.progress.SetValue(1)
+		}
+		for i, result := range parsedResultTabs {
+			tab.results.Items[i].Content = result.build(ui, report)
+		}
+		tab.results.Refresh()
+		if tab.onFinish != nil {
+			tab.onFinish(statusKey)
+		}
+	})
+}
+
+func (tab *runTab) currentStatus() string {
+	tab.mu.Lock()
+	defer tab.mu.Unlock()
+	return tab.statusKey
+}
+
+func (tab *runTab) isDone() bool {
+	select {
+	case <-tab.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ui *TestUI) findRunTab(item *container.TabItem) *runTab {
+	for _, tab := range ui.extraRuns {
+		if tab.item == item {
+			return tab
+		}
+	}
+	return nil
+}
+
+// closeRunTab 关闭标签页：仍在运行时先取消，结束后释放终端资源
+func (ui *TestUI) closeRunTab(tab *runTab) {
+	tab.cancel()
+	for i, t := range ui.extraRuns {
+		if t == tab {
+			ui.extraRuns = append(ui.extraRuns[:i], ui.extraRuns[i+1:]...)
+			break
+		}
+	}
+	ui.runTabs.Remove(tab.item)
+	go func() {
+		<-tab.done
+		tab.terminal.Destroy()
+	}()
+}
+
+// closeFinishedRunTabs 关闭所有已结束的运行标签页
+func (ui *TestUI) closeFinishedRunTabs() {
+	for _, tab := range append([]*runTab(nil), ui.extraRuns...) {
+		if tab.isDone() {
+			ui.closeRunTab(tab)
+		}
+	}
+}
+
+// closeAllRunTabs 在窗口关闭时取消所有标签页运行
+func (ui *TestUI) closeAllRunTabs() {
+	if ui.queue != nil {
+		ui.queue.cancelPending()
+	}
+	for _, tab := range ui.extraRuns {
+		tab.cancel()
+		tab.terminal.Destroy()
+	}
+	ui.extraRuns = nil
+}
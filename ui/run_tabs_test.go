@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func TestRunTabsKeepIndependentOutput(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	t.Cleanup(ui.closeAllRunTabs)
+	original := runTabRunner
+	t.Cleanup(func() { runTabRunner = original })
+	runTabRunner = func(config ExecutionConfig) executionRunner {
+		return fakeRemoteRunner{output: "-----------就近节点测速-----------\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n" + config.Remote.Host + "\n"}
+	}
+	ui.Terminal.SetFullText("main run output")
+
+	first := ui.openRunTab(ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.1"}})
+	second := ui.openRunTab(ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.2"}})
+	first.start()
+	second.start()
+	waitClosed(t, first.done)
+	waitClosed(t, second.done)
+
+	if len(ui.runTabs.Items) != 3 {
+		t.Fatalf("tabs = %d, want main + 2", len(ui.runTabs.Items))
+	}
+	if got := first.output.String(); !strings.Contains(got, "10.0.0.1") || strings.Contains(got, "10.0.0.2") {
+		t.Fatalf("first tab output = %q", got)
+	}
+	if got := ui.Terminal.GetText(); got != "main run output" {
+		t.Fatalf("main terminal = %q, want untouched", got)
+	}
+	if first.currentStatus() != "status.done" || first.report == nil || len(first.report.Speed) != 1 {
+		t.Fatalf("first tab status = %q, report = %+v", first.currentStatus(), first.report)
+	}
+	if len(ui.historyItems) != 2 {
+		t.Fatalf("history items = %d, want one per tab", len(ui.historyItems))
+	}
+
+	ui.closeFinishedRunTabs()
+	if len(ui.runTabs.Items) != 1 || len(ui.extraRuns) != 0 {
+		t.Fatalf("after closing finished tabs: %d tabs, %d runs", len(ui.runTabs.Items), len(ui.extraRuns))
+	}
+}
+
+func TestRunTabsAllowOneLocalRun(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	local := ExecutionConfig{}
+	if !ui.reserveTabRun(local) {
+		t.Fatal("first local run should be allowed")
+	}
+	if ui.reserveTabRun(local) {
+		t.Fatal("second concurrent local run should be refused")
+	}
+	if !ui.reserveTabRun(ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.1"}}) {
+		t.Fatal("remote runs should not be limited")
+	}
+	if err := ui.startBackgroundRun(executionForm{checks: map[string]bool{"basic": true}}, nil, "", nil); err != errRunInProgress {
+		t.Fatalf("local background run = %v, want errRunInProgress", err)
+	}
+	ui.releaseTabRun(local)
+
+	ui.IsRunning, ui.mainRunLocal = true, true
+	if ui.reserveTabRun(local) {
+		t.Fatal("local tab run should wait for the local main run")
+	}
+}
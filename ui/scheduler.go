@@ -0,0 +1,459 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+	"github.com/oneclickvirt/ecs-gui/schedule"
+	"github.com/oneclickvirt/ecs-gui/sink"
+)
+
+const (
+	schedulesFileName = "schedules.json"
+	// scheduleCheckInterval 是检查到期任务的间隔
+	scheduleCheckInterval = 30 * time.Second
+	// scheduleRegressionThreshold 是发送退化通知的百分比阈值
+	scheduleRegressionThreshold = 10.0
+)
+
+// scheduler 计算定时任务的下一次触发时间。下一次时间在首次看到任务（或表达式变化）时
+// 从当时开始计算，因此应用未运行期间错过的触发不会补跑。
+type scheduler struct {
+	store *schedule.Store
+
+	mu   sync.Mutex
+	next map[string]scheduledNext
+	stop chan struct{}
+}
+
+type scheduledNext struct {
+	spec string
+	at   time.Time
+}
+
+func newScheduler(store *schedule.Store) *scheduler {
+	return &scheduler{store: store, next: map[string]scheduledNext{}}
+}
+
+// due 返回 now 时已到期的启用任务
+func (s *scheduler) due(now time.Time) []schedule.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var jobs []schedule.Job
+	for _, job := range s.store.List() {
+		if !job.Enabled {
+			delete(s.next, job.ID)
+			continue
+		}
+		entry, ok := s.next[job.ID]
+		if !ok || entry.spec != job.Spec {
+			at, err := job.Next(now)
+			if err != nil {
+				continue
+			}
+			entry = scheduledNext{spec: job.Spec, at: at}
+			s.next[job.ID] = entry
+		}
+		if !entry.at.IsZero() && !now.Before(entry.at) {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs
+}
+
+// nextRun 返回任务的下一次触发时间，任务未启用或表达式无效时返回零值
+func (s *scheduler) nextRun(job schedule.Job, now time.Time) time.Time {
+	if !job.Enabled {
+		return time.Time{}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if entry, ok := s.next[job.ID]; ok && entry.spec == job.Spec {
+		return entry.at
+	}
+	at, _ := job.Next(now)
+	return at
+}
+
+// advance 记录任务已在 at 运行（或被跳过），并计算下一次触发时间
+func (s *scheduler) advance(job schedule.Job, at time.Time) {
+	_ = s.store.MarkRun(job.ID, at)
+	job.LastRun = at
+	next, _ := job.Next(at)
+	s.mu.Lock()
+	s.next[job.ID] = scheduledNext{spec: job.Spec, at: next}
+	s.mu.Unlock()
+}
+
+func (ui *TestUI) schedulerOrOpen() *scheduler {
+	ui.Mu.Lock()
+	defer ui.Mu.Unlock()
+	if ui.scheduler != nil {
+		return ui.scheduler
+	}
+	store, err := schedule.Open(ui.appDataDir(schedulesFileName))
+	if err != nil {
+		return nil
+	}
+	ui.scheduler = newScheduler(store)
+	return ui.scheduler
+}
+
+// startScheduler 在后台定期检查并运行到期的定时任务
+func (ui *TestUI) startScheduler() {
+	s := ui.schedulerOrOpen()
+	if s == nil || s.stop != nil {
+		return
+	}
+	s.stop = make(chan struct{})
+	stop := s.stop
+	go func() {
+		ticker := time.NewTicker(scheduleCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				ui.runDueSchedules(now)
+			}
+		}
+	}()
+}
+
+func (ui *TestUI) stopScheduler() {
+	if s := ui.scheduler; s != nil && s.stop != nil {
+		close(s.stop)
+		s.stop = nil
+	}
+}
+
+// runDueSchedules 启动第一个到期的任务；已有运行时保持到期状态，下次检查时再试
+func (ui *TestUI) runDueSchedules(now time.Time) {
+	s := ui.schedulerOrOpen()
+	if s == nil || ui.isRunning() {
+		return
+	}
+	for _, job := range s.due(now) {
+		err := ui.startScheduledRun(job)
+		if errors.Is(err, errRunInProgress) {
+			return
+		}
+		s.advance(job, now)
+		if err != nil {
+			ui.sendNotification(fmt.Sprintf(ui.tr("schedule.failed_title"), job.Name), ui.friendlyErrorMessage(err))
+			continue
+		}
+		return
+	}
+}
+
+// startScheduledRun 以当前配置页的测试参数运行任务中的测试项
+func (ui *TestUI) startScheduledRun(job schedule.Job) error {
+	var target *remote.Target
+	host := runHost(ExecutionConfig{})
+	if job.Host != "" {
+		if ui.hostProfiles == nil {
+			return errors.New(ui.tr("schedule.hosts_locked"))
+		}
+		resolved, err := ui.hostProfiles.Resolve(job.Host, ui.knownHostsPath())
+		if err != nil {
+			return err
+		}
+		target, host = &resolved, resolved.Host
+	}
+	var form executionForm
+	uiDoAndWait(func() { form = ui.currentExecutionForm() })
+	if err := form.selectTests(job.Tests); err != nil {
+		return err
+	}
+	return ui.startBackgroundRun(form, target, "", &scheduledRun{ui: ui, job: job, host: host})
+}
+
+// scheduledRun 在定时任务结束后与同一主机的上一次结果比较，指标明显下降时发送通知
+type scheduledRun struct {
+	ui   *TestUI
+	job  schedule.Job
+	host string
+}
+
+func (r *scheduledRun) Output(string) {}
+
+func (r *scheduledRun) Finish(status string, _ *StructuredRunResult) {
+	if status != "status.done" {
+		return
+	}
+	r.pushMetrics()
+	if !r.job.NotifyRegression {
+		return
+	}
+	store := r.ui.historyStoreOrOpen()
+	if store == nil {
+		return
+	}
+	count, err := regressedMetrics(store, r.host, scheduleRegressionThreshold)
+	if err != nil || count == 0 {
+		return
+	}
+	r.ui.sendNotification(
+		fmt.Sprintf(r.ui.tr("schedule.regression_title"), r.job.Name),
+		fmt.Sprintf(r.ui.tr("schedule.regression_body"), count, scheduleRegressionThreshold))
+}
+
+// pushMetrics 把本次结果推送到任务配置的 InfluxDB 或 Pushgateway，失败时发送桌面通知
+func (r *scheduledRun) pushMetrics() {
+	if r.job.Sink == nil {
+		return
+	}
+	r.ui.Mu.Lock()
+	report := r.ui.ParsedResults
+	r.ui.Mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), notifyPushTimeout)
+	defer cancel()
+	snapshot := results.Snapshot{Host: r.host, Time: time.Now(), Report: report}
+	if err := sink.Push(ctx, r.ui.notifyHTTPClient(), *r.job.Sink, snapshot); err != nil {
+		r.ui.sendNotification(fmt.Sprintf(r.ui.tr("schedule.sink_failed_title"), r.job.Name), r.ui.friendlyErrorMessage(err))
+	}
+}
+
+// regressedMetrics 比较主机最近两次完成的运行，返回下降超过 threshold 百分比的指标数
+func regressedMetrics(store *history.Store, host string, threshold float64) (int, error) {
+	summaries, err := store.List()
+	if err != nil {
+		return 0, err
+	}
+	var reports []*results.Report
+	for _, summary := range summaries {
+		if summary.Host != host || summary.Status != "done" {
+			continue
+		}
+		run, err := store.Load(summary.ID)
+		if err != nil || run.Results == nil {
+			continue
+		}
+		reports = append(reports, run.Results)
+		if len(reports) == 2 {
+			break
+		}
+	}
+	if len(reports) < 2 {
+		return 0, nil
+	}
+	count := 0
+	// reports[0] 为最新一次，以上一次为基准比较
+	for _, row := range results.Compare(reports[1], reports[0]) {
+		if row.Regressed(1, threshold) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// showScheduleManager 显示定时任务列表：新增、编辑、删除
+func (ui *TestUI) showScheduleManager() {
+	s := ui.schedulerOrOpen()
+	if s == nil {
+		dialog.ShowError(errors.New(ui.tr("schedule.open_failed")), ui.Window)
+		return
+	}
+	var jobs []schedule.Job
+	selected := -1
+	list := widget.NewList(
+		func() int { return len(jobs) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id < len(jobs) {
+				obj.(*widget.Label).SetText(ui.scheduleItemText(s, jobs[id]))
+			}
+		},
+	)
+	reload := func() {
+		jobs = s.store.List()
+		selected = -1
+		list.UnselectAll()
+		list.Refresh()
+	}
+	list.OnSelected = func(id widget.ListItemID) { selected = id }
+	list.OnUnselected = func(widget.ListItemID) { selected = -1 }
+	reload()
+
+	edit := func(job schedule.Job) {
+		ui.withHostProfiles(func() { ui.editScheduledJob(s, job, reload) })
+	}
+	addButton := widget.NewButtonWithIcon(ui.tr("hosts.add"), theme.ContentAddIcon(), func() {
+		edit(schedule.Job{Enabled: true, NotifyRegression: true})
+	})
+	editButton := widget.NewButtonWithIcon(ui.tr("hosts.edit"), theme.DocumentCreateIcon(), func() {
+		if selected >= 0 && selected < len(jobs) {
+			edit(jobs[selected])
+		}
+	})
+	deleteButton := widget.NewButtonWithIcon(ui.tr("history.delete"), theme.DeleteIcon(), func() {
+		if selected < 0 || selected >= len(jobs) {
+			return
+		}
+		if err := s.store.Delete(jobs[selected].ID); err != nil {
+			dialog.ShowError(err, ui.Window)
+		}
+		reload()
+	})
+	actions := container.NewHBox(addButton, editButton, deleteButton, layout.NewSpacer())
+	if isMobilePlatform() {
+		actions = container.NewAdaptiveGrid(3, addButton, editButton, deleteButton)
+	}
+	manager := dialog.NewCustom(ui.tr("schedule.title"), ui.tr("hosts.close"), container.NewBorder(nil, actions, nil, nil, list), ui.Window)
+	manager.Resize(fyne.NewSize(640, 420))
+	manager.Show()
+}
+
+func (ui *TestUI) scheduleItemText(s *scheduler, job schedule.Job) string {
+	target := ui.tr("schedule.local")
+	if job.Host != "" {
+		target = job.Host
+	}
+	parts := []string{job.Name, job.Spec, target}
+	if next := s.nextRun(job, time.Now()); !next.IsZero() {
+		parts = append(parts, fmt.Sprintf(ui.tr("schedule.next"), next.Local().Format("2006-01-02 15:04")))
+	} else {
+		parts = append(parts, ui.tr("schedule.disabled"))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// withHostProfiles 已保存主机时先解锁主机列表再继续，取消解锁则不继续
+func (ui *TestUI) withHostProfiles(next func()) {
+	if ui.hostProfiles == nil && remote.ProfilesExist(ui.hostProfilesPath()) {
+		ui.unlockHostProfiles(next)
+		return
+	}
+	next()
+}
+
+// editScheduledJob 新增或编辑一条定时任务
+func (ui *TestUI) editScheduledJob(s *scheduler, job schedule.Job, onSaved func()) {
+	name := widget.NewEntry()
+	name.SetText(job.Name)
+	spec := widget.NewEntry()
+	spec.SetText(job.Spec)
+	spec.SetPlaceHolder("0 3 * * 0 / @every 6h")
+
+	local := ui.tr("schedule.local")
+	targets := []string{local}
+	if ui.hostProfiles != nil {
+		for _, p := range ui.hostProfiles.List() {
+			targets = append(targets, p.Name)
+		}
+	} else if job.Host != "" {
+		targets = append(targets, job.Host)
+	}
+	target := widget.NewSelect(targets, nil)
+	target.SetSelected(local)
+	if job.Host != "" {
+		target.SetSelected(job.Host)
+	}
+
+	labelToKey := make(map[string]string, len(selectionModules))
+	var labels, chosen []string
+	for _, module := range selectionModules {
+		label := ui.tr(module.labelKey)
+		labelToKey[label] = module.key
+		labels = append(labels, label)
+		for _, key := range job.Tests {
+			if key == module.key {
+				chosen = append(chosen, label)
+			}
+		}
+	}
+	tests := widget.NewCheckGroup(labels, nil)
+	tests.Horizontal = true
+	tests.SetSelected(chosen)
+	enabled := widget.NewCheck(ui.tr("schedule.enabled"), nil)
+	enabled.SetChecked(job.Enabled)
+	notify := widget.NewCheck(fmt.Sprintf(ui.tr("schedule.notify_regression"), scheduleRegressionThreshold), nil)
+	notify.SetChecked(job.NotifyRegression)
+
+	hint := widget.NewLabel(ui.tr("schedule.spec_hint"))
+	hint.Wrapping = fyne.TextWrapWord
+
+	off := ui.tr("schedule.sink_off")
+	sinkKinds := map[string]sink.Kind{off: "", "InfluxDB": sink.KindInfluxDB, "Pushgateway": sink.KindPushgateway}
+	sinkKind := widget.NewSelect([]string{off, "InfluxDB", "Pushgateway"}, nil)
+	sinkURL := widget.NewEntry()
+	sinkURL.SetPlaceHolder("http://influxdb:8086/api/v2/write?org=ops&bucket=ecs")
+	sinkToken := widget.NewPasswordEntry()
+	sinkToken.SetPlaceHolder(ui.tr("schedule.sink_token_placeholder"))
+	sinkKind.OnChanged = func(label string) {
+		if sinkKinds[label] == "" {
+			sinkURL.Disable()
+			sinkToken.Disable()
+			return
+		}
+		sinkURL.Enable()
+		sinkToken.Enable()
+	}
+	sinkKind.SetSelected(off)
+	if job.Sink != nil {
+		for label, kind := range sinkKinds {
+			if kind == job.Sink.Kind {
+				sinkKind.SetSelected(label)
+			}
+		}
+		sinkURL.SetText(job.Sink.URL)
+		sinkToken.SetText(job.Sink.Token)
+	}
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("hosts.name"), name),
+		widget.NewFormItem(ui.tr("schedule.spec"), spec),
+		widget.NewFormItem("", hint),
+		widget.NewFormItem(ui.tr("schedule.target"), target),
+		widget.NewFormItem(ui.tr("schedule.tests"), tests),
+		widget.NewFormItem("", container.NewVBox(enabled, notify)),
+		{Text: ui.tr("schedule.sink"), Widget: container.NewBorder(nil, nil, sinkKind, nil, sinkURL), HintText: ui.tr("schedule.sink_hint")},
+		widget.NewFormItem(ui.tr("schedule.sink_token"), sinkToken),
+	}
+	title := ui.tr("hosts.add")
+	if job.ID != "" {
+		title = ui.tr("hosts.edit")
+	}
+	form := dialog.NewForm(title, ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		job.Name, job.Spec = name.Text, spec.Text
+		job.Host = ""
+		if target.Selected != local {
+			job.Host = target.Selected
+		}
+		job.Tests = job.Tests[:0:0]
+		for _, label := range tests.Selected {
+			job.Tests = append(job.Tests, labelToKey[label])
+		}
+		job.Enabled, job.NotifyRegression = enabled.Checked, notify.Checked
+		job.Sink = nil
+		if kind := sinkKinds[sinkKind.Selected]; kind != "" {
+			job.Sink = &sink.Target{Kind: kind, URL: strings.TrimSpace(sinkURL.Text), Token: strings.TrimSpace(sinkToken.Text), Job: sink.DefaultJob}
+		}
+		if _, err := s.store.Put(job); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		if onSaved != nil {
+			onSaved()
+		}
+	}, ui.Window)
+	form.Resize(fyne.NewSize(640, 0))
+	form.Show()
+}
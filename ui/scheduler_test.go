@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+	"github.com/oneclickvirt/ecs-gui/schedule"
+	"github.com/oneclickvirt/ecs-gui/sink"
+)
+
+func TestSchedulerDueAndAdvance(t *testing.T) {
+	store, err := schedule.Open(filepath.Join(t.TempDir(), schedulesFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := store.Put(schedule.Job{Name: "net", Spec: "@every 6h", Tests: []string{"speed"}, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Put(schedule.Job{Name: "off", Spec: "@every 1h", Tests: []string{"cpu"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	s := newScheduler(store)
+	start := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	if due := s.due(start); len(due) != 0 {
+		t.Fatalf("due at start = %v, want none", due)
+	}
+	if due := s.due(start.Add(6 * time.Hour)); len(due) != 1 || due[0].ID != job.ID {
+		t.Fatalf("due after interval = %v", due)
+	}
+	s.advance(job, start.Add(6*time.Hour))
+	if due := s.due(start.Add(7 * time.Hour)); len(due) != 0 {
+		t.Fatalf("due after advance = %v", due)
+	}
+	if saved, _ := store.Get(job.ID); !saved.LastRun.Equal(start.Add(6 * time.Hour)) {
+		t.Fatalf("last run = %v", saved.LastRun)
+	}
+	if next := s.nextRun(job, start); !next.Equal(start.Add(12 * time.Hour)) {
+		t.Fatalf("next run = %v", next)
+	}
+}
+
+func TestRegressedMetricsComparesLatestRunsForHost(t *testing.T) {
+	store, err := history.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	base := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	save := func(offset time.Duration, host, status string, score float64) {
+		t.Helper()
+		_, err := store.Save(history.Run{
+			Summary: history.Summary{StartedAt: base.Add(offset), Host: host, Status: status},
+			Results: &results.Report{CPU: []results.CPUScore{{Label: "1 线程", Score: score}}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	save(0, "a", "done", 1000)
+	save(time.Hour, "b", "done", 100)
+	save(2*time.Hour, "a", "failed", 10)
+	save(3*time.Hour, "a", "done", 850)
+
+	if count, err := regressedMetrics(store, "a", 10); err != nil || count != 1 {
+		t.Fatalf("regressedMetrics(a) = %d, %v; want 1", count, err)
+	}
+	if count, _ := regressedMetrics(store, "a", 20); count != 0 {
+		t.Fatalf("regressedMetrics(a, 20%%) = %d, want 0", count)
+	}
+	if count, _ := regressedMetrics(store, "b", 10); count != 0 {
+		t.Fatalf("regressedMetrics(b) = %d, want 0 with a single run", count)
+	}
+}
+
+func TestRunDueSchedulesSkipsLockedHosts(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	s := ui.schedulerOrOpen()
+	job, err := s.store.Put(schedule.Job{Name: "remote", Spec: "@every 1h", Host: "vps", Tests: []string{"cpu"}, Enabled: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	s.due(now)
+	ui.runDueSchedules(now.Add(time.Hour))
+
+	if ui.isRunning() {
+		t.Fatal("run started without unlocked host profiles")
+	}
+	if saved, _ := s.store.Get(job.ID); !saved.LastRun.Equal(now.Add(time.Hour)) {
+		t.Fatalf("skipped job not advanced: last run = %v", saved.LastRun)
+	}
+}
+
+func TestScheduledRunPushesMetricsToSink(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body = r.Method + " " + r.URL.Path + "\n" + string(data)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	ui.Mu.Lock()
+	ui.ParsedResults = &results.Report{CPU: []results.CPUScore{{Label: "1", Score: 900}}}
+	ui.Mu.Unlock()
+
+	job := schedule.Job{Name: "nightly", Sink: &sink.Target{Kind: sink.KindInfluxDB, URL: server.URL + "/write?db=ecs"}}
+	(&scheduledRun{ui: ui, job: job, host: "vps"}).Finish("status.failed", nil)
+	if body != "" {
+		t.Fatalf("failed run pushed metrics: %q", body)
+	}
+	(&scheduledRun{ui: ui, job: job, host: "vps"}).Finish("status.done", nil)
+	if !strings.HasPrefix(body, "POST /write\n") || !strings.Contains(body, "ecs_cpu_score,host=vps,test=1 value=900 ") {
+		t.Fatalf("pushed %q", body)
+	}
+}
@@ -0,0 +1,134 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// selectionModule 描述侧边栏中的一个测试模块，check 返回配置页中对应的主复选框
+type selectionModule struct {
+	key      string
+	labelKey string
+	check    func(ui *TestUI) *widget.Check
+}
+
+var selectionModules = []selectionModule{
+	{key: "basic", labelKey: "check.basic", check: func(ui *TestUI) *widget.Check { return ui.BasicCheck }},
+	{key: "cpu", labelKey: "check.cpu", check: func(ui *TestUI) *widget.Check { return ui.CpuCheck }},
+	{key: "memory", labelKey: "check.memory", check: func(ui *TestUI) *widget.Check { return ui.MemoryCheck }},
+	{key: "disk", labelKey: "check.disk", check: func(ui *TestUI) *widget.Check { return ui.DiskCheck }},
+	{key: "unlock", labelKey: "check.unlock", check: func(ui *TestUI) *widget.Check { return ui.UnlockCheck }},
+	{key: "security", labelKey: "check.security", check: func(ui *TestUI) *widget.Check { return ui.SecurityCheck }},
+	{key: "email", labelKey: "check.email", check: func(ui *TestUI) *widget.Check { return ui.EmailCheck }},
+	{key: "backtrace", labelKey: "check.backtrace", check: func(ui *TestUI) *widget.Check { return ui.BacktraceCheck }},
+	{key: "nt3", labelKey: "check.nt3", check: func(ui *TestUI) *widget.Check { return ui.Nt3Check }},
+	{key: "speed", labelKey: "check.speed", check: func(ui *TestUI) *widget.Check { return ui.SpeedCheck }},
+	{key: "ping", labelKey: "check.ping", check: func(ui *TestUI) *widget.Check { return ui.PingCheck }},
+}
+
+// createSelectionSidebar 创建启动页侧边栏：模块勾选清单 + 常用预设 + 按所选项开始
+func (ui *TestUI) createSelectionSidebar() fyne.CanvasObject {
+	ui.sidebarChecks = make(map[string]*widget.Check, len(selectionModules))
+	checks := make([]fyne.CanvasObject, 0, len(selectionModules))
+	for _, module := range selectionModules {
+		module := module
+		check := widget.NewCheck(ui.tr(module.labelKey), func(checked bool) {
+			if ui.syncingSidebar {
+				return
+			}
+			ui.setModuleSelected(module, checked)
+		})
+		ui.sidebarChecks[module.key] = check
+		checks = append(checks, check)
+	}
+	ui.sidebarSummary = widget.NewLabel("")
+
+	presetButton := func(labelKey, presetKey string) fyne.CanvasObject {
+		return widget.NewButton(ui.tr(labelKey), func() { ui.applyPreset(presetKey) })
+	}
+	presets := container.NewGridWithColumns(3,
+		presetButton("sidebar.preset.minimal", "minimal"),
+		presetButton("sidebar.preset.full", "full"),
+		presetButton("sidebar.preset.network", "network_only"),
+	)
+	startButton := widget.NewButtonWithIcon(ui.tr("sidebar.start"), theme.MediaPlayIcon(), func() {
+		ui.startTests()
+	})
+	startButton.Importance = widget.HighImportance
+
+	return widget.NewCard(ui.tr("sidebar.title"), ui.tr("sidebar.subtitle"), container.NewVBox(
+		presets,
+		widget.NewSeparator(),
+		container.NewVBox(checks...),
+		ui.sidebarSummary,
+		startButton,
+	))
+}
+
+// setModuleSelected 将侧边栏的勾选同步到配置页，并将预设切换为自定义
+func (ui *TestUI) setModuleSelected(module selectionModule, checked bool) {
+	main := module.check(ui)
+	if main == nil {
+		return
+	}
+	main.Checked = checked
+	main.Refresh()
+	ui.selectedPresetKey = "custom"
+	if ui.PresetSelect != nil {
+		ui.suppressPresetChange = true
+		ui.PresetSelect.SetSelected(ui.presetLabelByKey("custom"))
+		ui.suppressPresetChange = false
+	}
+	ui.updateSidebarSummary()
+}
+
+// syncSelectionSidebar 从配置页的主复选框回填侧边栏状态
+func (ui *TestUI) syncSelectionSidebar() {
+	if ui.sidebarChecks == nil {
+		return
+	}
+	ui.syncingSidebar = true
+	for _, module := range selectionModules {
+		check := ui.sidebarChecks[module.key]
+		main := module.check(ui)
+		if check == nil || main == nil {
+			continue
+		}
+		check.SetChecked(main.Checked)
+		if main.Disabled() {
+			check.Disable()
+		} else {
+			check.Enable()
+		}
+	}
+	ui.syncingSidebar = false
+	ui.updateSidebarSummary()
+}
+
+func (ui *TestUI) updateSidebarSummary() {
+	if ui.sidebarSummary == nil {
+		return
+	}
+	selected := 0
+	for _, module := range selectionModules {
+		if main := module.check(ui); main != nil && main.Checked {
+			selected++
+		}
+	}
+	ui.sidebarSummary.SetText(fmt.Sprintf(ui.tr("sidebar.summary"), selected, len(selectionModules)))
+}
+
+// applyPreset 应用预设但不立即开始测试
+func (ui *TestUI) applyPreset(presetKey string) {
+	ui.suppressPresetChange = true
+	ui.selectedPresetKey = presetKey
+	if ui.PresetSelect != nil {
+		ui.PresetSelect.SetSelected(ui.presetLabelByKey(presetKey))
+	}
+	ui.suppressPresetChange = false
+	ui.onPresetChanged(ui.presetLabelByKey(presetKey))
+}
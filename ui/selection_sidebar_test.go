@@ -0,0 +1,24 @@
+package ui
+
+import "testing"
+
+func TestSelectionSidebarMirrorsMainChecks(t *testing.T) {
+	ui := newTestUIForTest(t)
+
+	ui.applyPreset("network_only")
+	if ui.sidebarChecks["basic"].Checked || !ui.sidebarChecks["speed"].Checked {
+		t.Fatal("sidebar was not synced from the network_only preset")
+	}
+
+	ui.sidebarChecks["cpu"].SetChecked(true)
+	if !ui.CpuCheck.Checked {
+		t.Fatal("sidebar toggle did not update the main CPU check")
+	}
+	if ui.selectedPresetKey != "custom" {
+		t.Fatalf("selectedPresetKey = %q, want custom after manual toggle", ui.selectedPresetKey)
+	}
+	config := ui.collectExecutionConfig()
+	if !config.SelectedOptions["cpu"] || config.SelectedOptions["basic"] {
+		t.Fatalf("SelectedOptions = %#v", config.SelectedOptions)
+	}
+}
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/journal"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const journalDirName = "journal"
+
+// journalTestKeys 是会话中记录的测试项，TGDC 与网站延迟使用 applySingleSelection 的键
+var journalTestKeys = append(slices.Clone(testOptionKeys), "tgdc", "web")
+
+// testStageKeys 是各测试项对应的进度阶段，全部完成后该测试项才算完成
+var testStageKeys = map[string][]string{
+	"basic":     {"progress.basic_security"},
+	"cpu":       {"progress.cpu"},
+	"memory":    {"progress.memory"},
+	"disk":      {"progress.disk"},
+	"unlock":    {"progress.unlock"},
+	"security":  {"progress.basic_security", "progress.ip_quality"},
+	"email":     {"progress.email"},
+	"backtrace": {"progress.backtrace"},
+	"nt3":       {"progress.nt3"},
+	"speed":     {"progress.speed"},
+	"ping":      {"progress.ping"},
+	"tgdc":      {"progress.tgdc"},
+	"web":       {"progress.web"},
+}
+
+// journalTests 返回本次运行选中的测试项
+func journalTests(config ExecutionConfig) []string {
+	var tests []string
+	for _, key := range journalTestKeys {
+		if config.SelectedOptions[key] || key == "tgdc" && config.PingTgdc || key == "web" && config.PingWeb {
+			tests = append(tests, key)
+		}
+	}
+	return tests
+}
+
+// remainingTests 返回中断时尚未完成的测试项
+func remainingTests(session journal.Session) []string {
+	var remaining []string
+	for _, test := range session.Tests {
+		for _, stage := range testStageKeys[test] {
+			if !slices.Contains(session.Completed, stage) {
+				remaining = append(remaining, test)
+				break
+			}
+		}
+	}
+	return remaining
+}
+
+// startRunJournal 开始记录主运行，失败时返回 nil，只是失去崩溃恢复能力
+func (ui *TestUI) startRunJournal(config ExecutionConfig, startTime time.Time) *journal.Journal {
+	j, err := journal.Start(ui.appDataDir(journalDirName), journal.Session{
+		Host:      runHost(config),
+		Preset:    config.PresetKey,
+		StartedAt: startTime,
+		Tests:     journalTests(config),
+	})
+	if err != nil {
+		runnerLog.Warn("run journal unavailable", "err", err)
+		return nil
+	}
+	return j
+}
+
+// restoreInterruptedSession 检查上次是否有未正常结束的运行：把已记录的日志存入历史，
+// 并提示查看日志或只重跑剩余的测试项
+func (ui *TestUI) restoreInterruptedSession() {
+	dir := ui.appDataDir(journalDirName)
+	session, output, updated, err := journal.Load(dir)
+	if err != nil {
+		return
+	}
+	_ = journal.Discard(dir)
+	uiLog.Warn("found interrupted session", "host", session.Host, "started", session.StartedAt, "completed", session.Completed)
+	if strings.TrimSpace(output) != "" {
+		ui.saveHistoryRun(history.Run{
+			Summary: history.Summary{
+				StartedAt:  session.StartedAt,
+				FinishedAt: updated,
+				Status:     "interrupted",
+				Host:       session.Host,
+				Preset:     session.Preset,
+			},
+			Output:  output,
+			Results: results.Parse(output),
+		})
+	}
+	ui.showSessionRestore(session, output)
+}
+
+func (ui *TestUI) testLabels(tests []string) string {
+	labels := make([]string, len(tests))
+	for i, test := range tests {
+		switch test {
+		case "tgdc":
+			labels[i] = ui.tr("check.ping_tgdc")
+		case "web":
+			labels[i] = ui.tr("check.ping_web")
+		default:
+			labels[i] = ui.tr("check." + test)
+		}
+	}
+	return strings.Join(labels, ", ")
+}
+
+// showSessionRestore 显示中断会话的摘要与恢复选项，必须在 UI 线程调用
+func (ui *TestUI) showSessionRestore(session journal.Session, output string) {
+	remaining := remainingTests(session)
+	var done []string
+	for _, test := range session.Tests {
+		if !slices.Contains(remaining, test) {
+			done = append(done, test)
+		}
+	}
+	none := ui.tr("session.none")
+	completed, pending := ui.testLabels(done), ui.testLabels(remaining)
+	if completed == "" {
+		completed = none
+	}
+	if pending == "" {
+		pending = none
+	}
+	body := widget.NewLabel(fmt.Sprintf(ui.tr("session.body"),
+		session.Host, session.StartedAt.Local().Format("2006-01-02 15:04:05"), completed, pending))
+	body.Wrapping = fyne.TextWrapWord
+
+	var prompt dialog.Dialog
+	dismiss := widget.NewButton(ui.tr("session.dismiss"), func() { prompt.Hide() })
+	openLog := widget.NewButtonWithIcon(ui.tr("session.open_log"), theme.DocumentIcon(), func() {
+		prompt.Hide()
+		ui.Terminal.SetFullText(output)
+		ui.Mu.Lock()
+		ui.resultsRecord = nil
+		ui.Mu.Unlock()
+		ui.refreshParsedResults()
+		ui.showResultTab()
+	})
+	if strings.TrimSpace(output) == "" {
+		openLog.Disable()
+	}
+	rerun := widget.NewButtonWithIcon(ui.tr("session.rerun"), theme.MediaReplayIcon(), func() {
+		prompt.Hide()
+		ui.applySingleSelection(remaining...)
+		ui.startTests()
+	})
+	rerun.Importance = widget.HighImportance
+	if len(remaining) == 0 {
+		rerun.Disable()
+	}
+	content := container.NewVBox(body, container.NewHBox(layout.NewSpacer(), dismiss, openLog, rerun))
+	prompt = dialog.NewCustomWithoutButtons(ui.tr("session.title"), content, ui.Window)
+	prompt.Resize(fyne.NewSize(560, 0))
+	prompt.Show()
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/journal"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func TestRemainingTestsNeedEveryStage(t *testing.T) {
+	config := ExecutionConfig{
+		SelectedOptions: map[string]bool{"basic": true, "security": true, "cpu": true, "disk": true},
+		PingWeb:         true,
+	}
+	session := journal.Session{
+		Tests:     journalTests(config),
+		Completed: []string{"progress.basic_security", "progress.cpu"},
+	}
+	if want := []string{"basic", "cpu", "disk", "security", "web"}; !slices.Equal(session.Tests, want) {
+		t.Fatalf("journalTests() = %v, want %v", session.Tests, want)
+	}
+	// security 还需要 IP 质量阶段，因此仍算未完成
+	if got, want := remainingTests(session), []string{"disk", "security", "web"}; !slices.Equal(got, want) {
+		t.Fatalf("remainingTests() = %v, want %v", got, want)
+	}
+}
+
+func TestRestoreInterruptedSessionSavesPartialRun(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	config := ExecutionConfig{
+		SelectedOptions: map[string]bool{"cpu": true, "speed": true},
+		Remote:          &remote.Target{Host: "10.0.0.9"},
+	}
+	j := ui.startRunJournal(config, time.Now().Add(-time.Minute))
+	if j == nil {
+		t.Fatal("startRunJournal() returned nil")
+	}
+	t.Cleanup(func() { _ = j.Close() })
+	j.Write("-----------就近节点测速-----------\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n")
+	j.Complete("progress.cpu")
+
+	ui.restoreInterruptedSession()
+	if len(ui.historyItems) != 1 {
+		t.Fatalf("history items = %d, want the interrupted run", len(ui.historyItems))
+	}
+	if got := ui.historyItems[0]; got.Status != "interrupted" || got.Host != "10.0.0.9" {
+		t.Fatalf("summary = %+v", got)
+	}
+	if _, _, _, err := journal.Load(ui.appDataDir(journalDirName)); !errors.Is(err, journal.ErrNotFound) {
+		t.Fatalf("journal should be discarded after restore, got %v", err)
+	}
+
+	ui.restoreInterruptedSession()
+	if len(ui.historyItems) != 1 {
+		t.Fatal("a restored session must not be offered twice")
+	}
+}
@@ -0,0 +1,295 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+const (
+	settingsFileName       = "settings.json"
+	settingsExportFileName = "ecs-gui-settings.json"
+	settingsProfileVersion = 1
+)
+
+// settingsProfile 是设置文件格式，自动保存与导入导出共用。
+// 密码类字段不会写入：表单中的远程密码被忽略，主机配置中的密码与私钥口令被清空。
+type settingsProfile struct {
+	Version     int               `json:"version"`
+	Preset      string            `json:"preset,omitempty"`
+	Checks      map[string]bool   `json:"checks,omitempty"`
+	Selections  map[string]string `json:"selections,omitempty"`
+	Entries     map[string]string `json:"entries,omitempty"`
+	Preferences map[string]any    `json:"preferences,omitempty"`
+	Hosts       []remote.Profile  `json:"hosts,omitempty"`
+}
+
+// 随设置文件一起保存的应用偏好项
+var (
+	settingsStringPreferences = []string{
+		languagePreferenceKey, themePreferenceKey, terminalSchemePreferenceKey,
+		terminalBufferPreferenceKey, terminalFontPathPreferenceKey, apiAddressPreferenceKey,
+	}
+	settingsBoolPreferences  = []string{logKeepANSIPreferenceKey, logAutoSavePreferenceKey, apiEnabledPreferenceKey, notifyDisabledPreferenceKey, trayMinimizePreferenceKey}
+	settingsFloatPreferences = []string{terminalFontSizePreferenceKey}
+)
+
+// settingsSecretEntries 是不写入设置文件的表单项
+var settingsSecretEntries = map[string]bool{"remotePassword": true, "remotePassphrase": true}
+
+func (ui *TestUI) settingsPath() string {
+	return filepath.Join(ui.appDataDir(""), settingsFileName)
+}
+
+// currentSettingsProfile 收集当前表单与偏好设置
+func (ui *TestUI) currentSettingsProfile() settingsProfile {
+	state := ui.snapshotUIState()
+	profile := settingsProfile{
+		Version:     settingsProfileVersion,
+		Preset:      state.presetKey,
+		Checks:      state.checks,
+		Selections:  make(map[string]string, len(state.selections)),
+		Entries:     make(map[string]string, len(state.entries)),
+		Preferences: map[string]any{},
+	}
+	for key, value := range state.selections {
+		// 语言与主题通过偏好项保存，选择框中的显示名随语言变化
+		if key != "language" && key != "theme" {
+			profile.Selections[key] = value
+		}
+	}
+	for key, value := range state.entries {
+		if !settingsSecretEntries[key] {
+			profile.Entries[key] = value
+		}
+	}
+	prefs := ui.App.Preferences()
+	for _, key := range settingsStringPreferences {
+		if value := prefs.String(key); value != "" {
+			profile.Preferences[key] = value
+		}
+	}
+	for _, key := range settingsBoolPreferences {
+		profile.Preferences[key] = prefs.Bool(key)
+	}
+	for _, key := range settingsFloatPreferences {
+		profile.Preferences[key] = prefs.Float(key)
+	}
+	return profile
+}
+
+// mergeInto 把设置文件中的表单内容合并到界面快照，文件中缺失的项保持不变
+func (p settingsProfile) mergeInto(state *uiStateSnapshot) {
+	if p.Preset != "" {
+		state.presetKey = p.Preset
+	}
+	for key, value := range p.Checks {
+		if _, ok := state.checks[key]; ok {
+			state.checks[key] = value
+		}
+	}
+	for key, value := range p.Selections {
+		if _, ok := state.selections[key]; ok && key != "language" && key != "theme" {
+			state.selections[key] = value
+		}
+	}
+	for key, value := range p.Entries {
+		if _, ok := state.entries[key]; ok && !settingsSecretEntries[key] {
+			state.entries[key] = value
+		}
+	}
+}
+
+// applyPreferences 把设置文件中的偏好项写入应用偏好，类型不符的项被忽略
+func (p settingsProfile) applyPreferences(prefs fyne.Preferences) {
+	for _, key := range settingsStringPreferences {
+		if value, ok := p.Preferences[key].(string); ok {
+			prefs.SetString(key, value)
+		}
+	}
+	for _, key := range settingsBoolPreferences {
+		if value, ok := p.Preferences[key].(bool); ok {
+			prefs.SetBool(key, value)
+		}
+	}
+	for _, key := range settingsFloatPreferences {
+		if value, ok := p.Preferences[key].(float64); ok {
+			prefs.SetFloat(key, value)
+		}
+	}
+}
+
+func parseSettingsProfile(data []byte) (settingsProfile, error) {
+	var profile settingsProfile
+	if err := json.Unmarshal(data, &profile); err != nil {
+		return profile, err
+	}
+	if profile.Version == 0 || profile.Version > settingsProfileVersion {
+		return profile, fmt.Errorf("unsupported settings version %d", profile.Version)
+	}
+	return profile, nil
+}
+
+// saveSettings 把当前设置写入应用数据目录
+func (ui *TestUI) saveSettings() error {
+	if ui.App == nil || ui.BasicCheck == nil {
+		return nil
+	}
+	data, err := json.MarshalIndent(ui.currentSettingsProfile(), "", "  ")
+	if err != nil {
+		return err
+	}
+	path := ui.settingsPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+// loadSettings 启动时恢复上次保存的表单内容；文件不存在或无法解析时保持默认值
+func (ui *TestUI) loadSettings() {
+	data, err := os.ReadFile(ui.settingsPath())
+	if err != nil {
+		return
+	}
+	profile, err := parseSettingsProfile(data)
+	if err != nil {
+		return
+	}
+	state := ui.snapshotUIState()
+	profile.mergeInto(&state)
+	ui.restoreUIState(state)
+}
+
+// applySettingsProfile 导入设置：写入偏好项后按新设置重建界面
+func (ui *TestUI) applySettingsProfile(profile settingsProfile) {
+	prefs := ui.App.Preferences()
+	profile.applyPreferences(prefs)
+
+	ui.languageSetting = normalizeLanguage(prefs.StringWithFallback(languagePreferenceKey, langZH))
+	if ui.languageSetting == "" {
+		ui.languageSetting = langZH
+	}
+	ui.uiLang = resolveLanguage(ui.languageSetting)
+	ui.terminalScheme = prefs.String(terminalSchemePreferenceKey)
+
+	state := ui.snapshotUIState()
+	profile.mergeInto(&state)
+	state.selections["language"] = languageLabel(ui.languageSetting)
+	state.themeMode = normalizeThemeMode(prefs.StringWithFallback(themePreferenceKey, themeModeLight))
+	ui.rebuildUI(state)
+	if err := ui.applyAPISetting(); err != nil {
+		dialog.ShowError(errors.Join(errors.New(ui.tr("api.start_failed")), err), ui.Window)
+	}
+}
+
+// importHostProfiles 把导入的主机配置合并到主机列表。
+// 导入文件不含密码，同名主机沿用已保存的密码；仍缺少认证信息的主机被跳过并计数。
+func (ui *TestUI) importHostProfiles(hosts []remote.Profile) (skipped int) {
+	for _, host := range hosts {
+		oldName := ""
+		if existing, ok := ui.hostProfiles.Get(host.Name); ok {
+			oldName = host.Name
+			if host.Password == "" {
+				host.Password = existing.Password
+			}
+			if host.KeyPassphrase == "" {
+				host.KeyPassphrase = existing.KeyPassphrase
+			}
+		}
+		if err := ui.hostProfiles.Put(oldName, host); err != nil {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// exportSettings 导出设置；已保存主机时询问是否一并导出（需要主密码）
+func (ui *TestUI) exportSettings() {
+	export := func(withHosts bool) {
+		profile := ui.currentSettingsProfile()
+		if withHosts && ui.hostProfiles != nil {
+			for _, host := range ui.hostProfiles.List() {
+				host.Password, host.KeyPassphrase = "", ""
+				profile.Hosts = append(profile.Hosts, host)
+			}
+		}
+		data, err := json.MarshalIndent(profile, "", "  ")
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		ui.saveExportFile(settingsExportFileName, data)
+	}
+	switch {
+	case ui.hostProfiles != nil:
+		export(true)
+	case remote.ProfilesExist(ui.hostProfilesPath()):
+		dialog.ShowConfirm(ui.tr("settings.export"), ui.tr("settings.export_hosts"), func(ok bool) {
+			if !ok {
+				export(false)
+				return
+			}
+			ui.unlockHostProfiles(func() { export(true) })
+		}, ui.Window)
+	default:
+		export(false)
+	}
+}
+
+// importSettings 选择设置文件并导入；文件包含主机时先解锁主机列表
+func (ui *TestUI) importSettings() {
+	if ui.isRunning() {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.running_no_switch"), ui.Window)
+		return
+	}
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		data, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		profile, err := parseSettingsProfile(data)
+		if err != nil {
+			dialog.ShowError(errors.Join(errors.New(ui.tr("settings.invalid")), err), ui.Window)
+			return
+		}
+		ui.applySettingsProfile(profile)
+		_ = ui.saveSettings()
+		if len(profile.Hosts) == 0 {
+			dialog.ShowInformation(ui.tr("dialog.success"), ui.tr("settings.imported"), ui.Window)
+			return
+		}
+		importHosts := func() {
+			skipped := ui.importHostProfiles(profile.Hosts)
+			message := fmt.Sprintf(ui.tr("settings.imported_hosts"), len(profile.Hosts)-skipped)
+			if skipped > 0 {
+				message += "\n" + fmt.Sprintf(ui.tr("settings.hosts_skipped"), skipped)
+			}
+			dialog.ShowInformation(ui.tr("dialog.success"), message, ui.Window)
+		}
+		if ui.hostProfiles != nil {
+			importHosts()
+			return
+		}
+		ui.unlockHostProfiles(importHosts)
+	}, ui.Window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+	open.Show()
+}
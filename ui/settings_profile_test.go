@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestSettingsPersistAcrossRestart(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.CpuCheck.SetChecked(false)
+	ui.SpeedCheck.SetChecked(true)
+	ui.SpNumEntry.SetText("5")
+	ui.RemoteHostEntry.SetText("203.0.113.7")
+	ui.RemotePasswordEntry.SetText("secret")
+	if err := ui.saveSettings(); err != nil {
+		t.Fatalf("saveSettings() error = %v", err)
+	}
+
+	data, err := os.ReadFile(ui.settingsPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var saved settingsProfile
+	if err := json.Unmarshal(data, &saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := saved.Entries["remotePassword"]; ok {
+		t.Fatal("remote password written to settings file")
+	}
+
+	restarted := newTestUIForTest(t)
+	if restarted.CpuCheck.Checked || !restarted.SpeedCheck.Checked {
+		t.Fatalf("checks not restored: cpu=%v speed=%v", restarted.CpuCheck.Checked, restarted.SpeedCheck.Checked)
+	}
+	if restarted.SpNumEntry.Text != "5" || restarted.RemoteHostEntry.Text != "203.0.113.7" {
+		t.Fatalf("entries not restored: %q %q", restarted.SpNumEntry.Text, restarted.RemoteHostEntry.Text)
+	}
+	if restarted.RemotePasswordEntry.Text != "" {
+		t.Fatal("remote password restored from settings file")
+	}
+}
+
+func TestApplySettingsProfileImportsPreferences(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	source := newTestUIForTest(t)
+	source.App.Preferences().SetString(languagePreferenceKey, langEN)
+	source.App.Preferences().SetString(terminalSchemePreferenceKey, "dracula")
+	source.App.Preferences().SetBool(logAutoSavePreferenceKey, true)
+	source.App.Preferences().SetFloat(terminalFontSizePreferenceKey, 18)
+	source.MemoryCheck.SetChecked(false)
+	data, err := json.Marshal(source.currentSettingsProfile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := test.NewApp()
+	target := NewTestUI(app)
+	t.Cleanup(func() {
+		target.Terminal.Destroy()
+		app.Quit()
+	})
+	profile, err := parseSettingsProfile(data)
+	if err != nil {
+		t.Fatalf("parseSettingsProfile() error = %v", err)
+	}
+	target.applySettingsProfile(profile)
+
+	if target.uiLang != langEN || target.LanguageSelect.Selected != languageLabel(langEN) {
+		t.Fatalf("language = %q / %q", target.uiLang, target.LanguageSelect.Selected)
+	}
+	if target.terminalScheme != "dracula" || !app.Preferences().Bool(logAutoSavePreferenceKey) {
+		t.Fatal("preferences not imported")
+	}
+	if target.Terminal.fontSize != 18 {
+		t.Fatalf("terminal font size = %v, want 18", target.Terminal.fontSize)
+	}
+	if target.MemoryCheck.Checked {
+		t.Fatal("memory check not imported")
+	}
+}
+
+func TestParseSettingsProfileRejectsUnknownVersion(t *testing.T) {
+	for _, data := range []string{`{}`, `{"version": 99}`, `not json`} {
+		if _, err := parseSettingsProfile([]byte(data)); err == nil {
+			t.Errorf("parseSettingsProfile(%s) accepted", data)
+		}
+	}
+}
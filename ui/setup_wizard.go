@@ -0,0 +1,254 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// setupDonePreferenceKey 记录是否已完成或跳过首次运行向导
+const setupDonePreferenceKey = "setup_wizard_done"
+
+// setupPresets 是向导中可选的默认测试预设
+var setupPresets = []string{"minimal", "standard", "full", "hardware_only", "network_only"}
+
+// setupChoices 是向导中的选择，完成时一次性应用
+type setupChoices struct {
+	language     string // zh、en 或 auto
+	remoteBinary string // 远程测试上传的本地 goecs，空表示按版本下载
+	preset       string
+	offline      bool
+	upload       bool
+	redact       bool
+	smokeTest    bool
+}
+
+// setupWizard 是首次运行向导：语言、goecs、默认测试项、隐私选项与快速试运行，每页一个步骤
+type setupWizard struct {
+	ui       *TestUI
+	step     int
+	steps    []string // 各步骤标题的翻译键
+	pages    []fyne.CanvasObject
+	title    *widget.Label
+	body     *fyne.Container
+	back     *widget.Button
+	next     *widget.Button
+	dialog   dialog.Dialog
+	language *widget.RadioGroup
+	binary   *widget.Entry
+	preset   *widget.RadioGroup
+	offline  *widget.Check
+	upload   *widget.Check
+	redact   *widget.Check
+	smoke    *widget.Check
+}
+
+// needsSetupWizard 返回是否需要显示首次运行向导；升级前已经保存过设置的用户视为已完成
+func (ui *TestUI) needsSetupWizard() bool {
+	if ui.App == nil || ui.App.Preferences().Bool(setupDonePreferenceKey) {
+		return false
+	}
+	if _, err := os.Stat(ui.settingsPath()); err == nil {
+		ui.App.Preferences().SetBool(setupDonePreferenceKey, true)
+		return false
+	}
+	return true
+}
+
+// showSetupWizardIfFirstRun 在首次启动时打开向导
+func (ui *TestUI) showSetupWizardIfFirstRun() {
+	if ui.needsSetupWizard() {
+		ui.showSetupWizard()
+	}
+}
+
+// showSetupWizard 打开首次运行向导，也可以从启动页再次打开
+func (ui *TestUI) showSetupWizard() {
+	w := ui.newSetupWizard()
+	content := container.NewBorder(w.title, container.NewHBox(
+		widget.NewButton(ui.tr("setup.skip"), w.skip),
+		layout.NewSpacer(),
+		w.back,
+		w.next,
+	), nil, nil, w.body)
+	w.dialog = dialog.NewCustomWithoutButtons(ui.tr("setup.title"), content, ui.Window)
+	w.dialog.Resize(fyne.NewSize(560, 440))
+	w.show(0)
+	w.dialog.Show()
+}
+
+func (ui *TestUI) newSetupWizard() *setupWizard {
+	w := &setupWizard{
+		ui:    ui,
+		steps: []string{"setup.language", "setup.backend", "setup.tests", "setup.privacy", "setup.smoke"},
+		title: widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		body:  container.NewStack(),
+	}
+	w.back = widget.NewButtonWithIcon(ui.tr("setup.back"), theme.NavigateBackIcon(), func() { w.show(w.step - 1) })
+	w.next = widget.NewButtonWithIcon(ui.tr("setup.next"), theme.NavigateNextIcon(), func() {
+		if w.step == len(w.steps)-1 {
+			w.finish()
+			return
+		}
+		w.show(w.step + 1)
+	})
+	w.next.Importance = widget.HighImportance
+
+	w.language = widget.NewRadioGroup(languageLabels(), nil)
+	w.language.SetSelected(languageLabel(ui.languageSetting))
+
+	w.binary = widget.NewEntry()
+	w.binary.SetPlaceHolder(ui.tr("placeholder.remote_binary"))
+	if ui.RemoteBinaryEntry != nil {
+		w.binary.SetText(ui.RemoteBinaryEntry.Text)
+	}
+
+	labels := make([]string, 0, len(setupPresets))
+	for _, key := range setupPresets {
+		labels = append(labels, ui.presetLabelByKey(key))
+	}
+	w.preset = widget.NewRadioGroup(labels, nil)
+	w.preset.SetSelected(ui.presetLabelByKey("standard"))
+	w.offline = widget.NewCheck(ui.tr("check.offline_mode"), nil)
+	w.offline.Checked = ui.offlineModeEnabled()
+
+	w.upload = widget.NewCheck(ui.tr("check.result_upload"), nil)
+	w.upload.Checked = ui.ResultUploadCheck != nil && ui.ResultUploadCheck.Checked
+	w.redact = widget.NewCheck(ui.tr("privacy.mode"), nil)
+	w.redact.Checked = ui.privacyEnabled()
+
+	w.smoke = widget.NewCheck(ui.tr("setup.smoke.run"), nil)
+	w.smoke.Checked = true
+
+	hint := func(key string) *widget.Label {
+		label := widget.NewLabel(ui.tr(key))
+		label.Wrapping = fyne.TextWrapWord
+		return label
+	}
+	w.pages = []fyne.CanvasObject{
+		container.NewVBox(hint("setup.language.hint"), w.language),
+		container.NewVBox(
+			hint("setup.backend.hint"),
+			container.NewBorder(nil, nil, nil, widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() { ui.pickLocalFile(w.binary) }), w.binary),
+			widget.NewButtonWithIcon(ui.tr("ecs.title"), theme.DownloadIcon(), ui.showECSManager),
+		),
+		container.NewVBox(hint("setup.tests.hint"), w.preset, w.offline),
+		container.NewVBox(hint("setup.privacy.hint"), w.upload, w.redact),
+		container.NewVBox(hint("setup.smoke.hint"), w.smoke),
+	}
+	return w
+}
+
+// show 切换到第 step 步
+func (w *setupWizard) show(step int) {
+	w.step = min(max(step, 0), len(w.steps)-1)
+	w.title.SetText(fmt.Sprintf("%d/%d  %s", w.step+1, len(w.steps), w.ui.tr(w.steps[w.step])))
+	w.body.Objects = []fyne.CanvasObject{w.pages[w.step]}
+	w.body.Refresh()
+	if w.step == 0 {
+		w.back.Disable()
+	} else {
+		w.back.Enable()
+	}
+	if w.step == len(w.steps)-1 {
+		w.next.SetText(w.ui.tr("setup.finish"))
+		w.next.SetIcon(theme.ConfirmIcon())
+	} else {
+		w.next.SetText(w.ui.tr("setup.next"))
+		w.next.SetIcon(theme.NavigateNextIcon())
+	}
+}
+
+// choices 返回向导中当前的选择
+func (w *setupWizard) choices() setupChoices {
+	choices := setupChoices{
+		language:     languageSettingByLabel(w.language.Selected),
+		remoteBinary: strings.TrimSpace(w.binary.Text),
+		preset:       "standard",
+		offline:      w.offline.Checked,
+		upload:       w.upload.Checked,
+		redact:       w.redact.Checked,
+		smokeTest:    w.smoke.Checked,
+	}
+	for _, key := range setupPresets {
+		if w.ui.presetLabelByKey(key) == w.preset.Selected {
+			choices.preset = key
+		}
+	}
+	if choices.language == "" {
+		choices.language = w.ui.languageSetting
+	}
+	return choices
+}
+
+// skip 关闭向导并不再自动显示，界面保持默认设置
+func (w *setupWizard) skip() {
+	w.ui.App.Preferences().SetBool(setupDonePreferenceKey, true)
+	w.dialog.Hide()
+}
+
+func (w *setupWizard) finish() {
+	w.dialog.Hide()
+	w.ui.applySetupChoices(w.choices())
+}
+
+// applySetupChoices 应用向导的选择并保存设置；切换语言时重建界面，选择了试运行时在新标签页中运行
+func (ui *TestUI) applySetupChoices(choices setupChoices) {
+	prefs := ui.App.Preferences()
+	prefs.SetBool(setupDonePreferenceKey, true)
+	prefs.SetBool(privacyModePreferenceKey, choices.redact)
+	ui.applyPrivacy()
+	ui.RemoteBinaryEntry.SetText(choices.remoteBinary)
+	ui.applyPreset(choices.preset)
+	// 离线模式会取消勾选并禁用结果上传，需要先设置上传
+	ui.ResultUploadCheck.SetChecked(choices.upload)
+	ui.OfflineModeCheck.SetChecked(choices.offline)
+
+	ui.saveLanguageSetting(choices.language)
+	if lang := resolveLanguage(choices.language); lang != ui.uiLang {
+		state := ui.snapshotUIState()
+		state.selections["language"] = languageLabel(choices.language)
+		ui.uiLang = lang
+		ui.rebuildUI(state)
+	}
+	_ = ui.saveSettings()
+	if choices.smokeTest {
+		ui.startSmokeTest()
+	}
+}
+
+// smokeTestConfig 返回快速试运行的配置：只测基础信息与 sysbench CPU，不上传结果，约一分钟
+func (ui *TestUI) smokeTestConfig() ExecutionConfig {
+	config := ui.collectExecutionConfig()
+	config.SelectedOptions = map[string]bool{"basic": true, "cpu": true}
+	config.PresetKey = "custom"
+	config.CpuMethod = "sysbench"
+	config.PingTgdc, config.PingWeb = false, false
+	config.IperfTargets, config.TunnelInterface = nil, ""
+	config.GPUInfo, config.GPUBenchmark = false, false
+	config.EnableUpload = false
+	config.Remote, config.Docker = nil, nil
+	return config
+}
+
+// startSmokeTest 在新标签页中运行快速试运行，不改变配置页中的勾选
+func (ui *TestUI) startSmokeTest() {
+	config := ui.smokeTestConfig()
+	if !ui.reserveTabRun(config) {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("run_tabs.local_busy"), ui.Window)
+		return
+	}
+	ui.confirmLaunch(config, func(config ExecutionConfig) {
+		tab := ui.openRunTab(config)
+		ui.runTabs.Select(tab.item)
+		ui.showResultTab()
+		tab.start()
+	}, func() { ui.releaseTabRun(config) })
+}
@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetupWizardShowsOnlyOnFirstRun(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	if !ui.needsSetupWizard() {
+		t.Fatal("a fresh install should show the setup wizard")
+	}
+	if err := os.MkdirAll(filepath.Dir(ui.settingsPath()), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ui.settingsPath(), []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if ui.needsSetupWizard() || !ui.App.Preferences().Bool(setupDonePreferenceKey) {
+		t.Fatal("users upgrading with saved settings should not see the wizard")
+	}
+}
+
+func TestSetupWizardStepsAndChoices(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	w := ui.newSetupWizard()
+	w.show(0)
+	if !w.back.Disabled() || w.next.Text != ui.tr("setup.next") {
+		t.Fatal("the first step should not go back")
+	}
+	for range w.steps {
+		w.show(w.step + 1)
+	}
+	if w.step != len(w.steps)-1 || w.next.Text != ui.tr("setup.finish") {
+		t.Fatalf("step = %d, next = %q", w.step, w.next.Text)
+	}
+
+	w.preset.SetSelected(ui.presetLabelByKey("hardware_only"))
+	w.binary.SetText(" /opt/goecs ")
+	w.upload.SetChecked(true)
+	choices := w.choices()
+	if choices.preset != "hardware_only" || choices.remoteBinary != "/opt/goecs" || !choices.upload || !choices.smokeTest || choices.language != ui.languageSetting {
+		t.Fatalf("choices = %+v", choices)
+	}
+}
+
+func TestApplySetupChoices(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.applySetupChoices(setupChoices{language: langEN, remoteBinary: "/opt/goecs", preset: "hardware_only", upload: true, redact: true})
+	if !ui.App.Preferences().Bool(setupDonePreferenceKey) || !ui.privacyEnabled() {
+		t.Fatal("the wizard should be marked done and privacy mode saved")
+	}
+	if ui.uiLang != langEN || ui.RemoteBinaryEntry.Text != "/opt/goecs" || ui.selectedPresetKey != "hardware_only" {
+		t.Fatalf("lang = %s, binary = %q, preset = %s", ui.uiLang, ui.RemoteBinaryEntry.Text, ui.selectedPresetKey)
+	}
+	if !ui.ResultUploadCheck.Checked || !ui.CpuCheck.Checked || ui.SpeedCheck.Checked {
+		t.Fatal("the form should follow the chosen preset and upload setting")
+	}
+	if _, err := os.Stat(ui.settingsPath()); err != nil {
+		t.Fatalf("settings should be saved: %v", err)
+	}
+
+	ui.applySetupChoices(setupChoices{language: langEN, preset: "standard", upload: true, offline: true})
+	if ui.ResultUploadCheck.Checked || !ui.offlineModeEnabled() || ui.SpeedCheck.Checked {
+		t.Fatal("offline mode should win over uploads and network tests")
+	}
+}
+
+func TestSmokeTestConfigRunsBasicAndCPUOnly(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.applyPreset("full")
+	ui.ResultUploadCheck.SetChecked(true)
+	config := ui.smokeTestConfig()
+	for key, selected := range config.SelectedOptions {
+		if selected != (key == "basic" || key == "cpu") {
+			t.Fatalf("%s selected = %v", key, selected)
+		}
+	}
+	if config.EnableUpload || config.CpuMethod != "sysbench" || !config.local() || !ui.SpeedCheck.Checked {
+		t.Fatalf("config = %+v, the form should keep its selection", config)
+	}
+}
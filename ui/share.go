@@ -0,0 +1,147 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/paste"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+	"github.com/oneclickvirt/ecs-gui/redact"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	shareServicePreferenceKey   = "share_service"
+	shareURLPreferenceKey       = "share_url"
+	shareFieldPreferenceKey     = "share_field"
+	shareFormatPreferenceKey    = "share_format"
+	shareAnonymizePreferenceKey = "share_anonymize"
+
+	shareFormatText     = "text"
+	shareFormatMarkdown = "markdown"
+	shareTimeout        = 30 * time.Second
+)
+
+// sharePaste 上传分享内容，测试中替换
+var sharePaste = paste.Share
+
+// shareResults 选择分享服务、格式与是否隐去 IP 后上传当前结果，分享链接复制到剪贴板
+func (ui *TestUI) shareResults() {
+	source := ui.redactSource(ui.currentExportSource())
+	if strings.TrimSpace(source.content) == "" {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
+		return
+	}
+	prefs := ui.App.Preferences()
+	kinds := []string{paste.KindECS, paste.KindGeneric}
+	kindLabels := []string{ui.tr("share.service.ecs"), ui.tr("share.service.generic")}
+	endpoint := widget.NewEntry()
+	endpoint.SetPlaceHolder("https://paste.example.com/api")
+	endpoint.SetText(prefs.String(shareURLPreferenceKey))
+	field := widget.NewEntry()
+	field.SetPlaceHolder(ui.tr("placeholder.share_field"))
+	field.SetText(prefs.String(shareFieldPreferenceKey))
+	service := widget.NewSelect(kindLabels, func(label string) {
+		if label == kindLabels[1] {
+			endpoint.Enable()
+			field.Enable()
+		} else {
+			endpoint.Disable()
+			field.Disable()
+		}
+	})
+	if prefs.String(shareServicePreferenceKey) == paste.KindGeneric {
+		service.SetSelected(kindLabels[1])
+	} else {
+		service.SetSelected(kindLabels[0])
+	}
+	formatLabels := []string{ui.tr("share.format.text"), ui.tr("share.format.markdown")}
+	format := widget.NewRadioGroup(formatLabels, nil)
+	format.Horizontal = true
+	format.Required = true
+	if prefs.StringWithFallback(shareFormatPreferenceKey, shareFormatText) == shareFormatMarkdown {
+		format.SetSelected(formatLabels[1])
+	} else {
+		format.SetSelected(formatLabels[0])
+	}
+	anonymize := widget.NewCheck(ui.tr("share.anonymize"), nil)
+	anonymize.SetChecked(prefs.Bool(shareAnonymizePreferenceKey))
+
+	items := []*widget.FormItem{
+		widget.NewFormItem(ui.tr("share.service"), service),
+		widget.NewFormItem("URL", endpoint),
+		widget.NewFormItem(ui.tr("share.field"), field),
+		widget.NewFormItem(ui.tr("share.format"), format),
+		widget.NewFormItem("", anonymize),
+	}
+	dialog.ShowForm(ui.tr("button.share"), ui.tr("button.share"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		target := paste.Service{Kind: kinds[service.SelectedIndex()], URL: strings.TrimSpace(endpoint.Text), Field: strings.TrimSpace(field.Text)}
+		if err := target.Validate(); err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		chosenFormat := shareFormatText
+		if format.Selected == formatLabels[1] {
+			chosenFormat = shareFormatMarkdown
+		}
+		prefs.SetString(shareServicePreferenceKey, target.Kind)
+		prefs.SetString(shareURLPreferenceKey, target.URL)
+		prefs.SetString(shareFieldPreferenceKey, target.Field)
+		prefs.SetString(shareFormatPreferenceKey, chosenFormat)
+		prefs.SetBool(shareAnonymizePreferenceKey, anonymize.Checked)
+		ui.uploadShare(target, chosenFormat, shareContent(source, chosenFormat, anonymize.Checked))
+	}, ui.Window)
+}
+
+// shareContent 按格式生成分享内容：文本为终端输出，Markdown 为结构化结果表格（无法解析时为代码块中的原始输出）
+func shareContent(source exportSource, format string, anonymize bool) string {
+	content := results.StripANSI(source.content)
+	switch {
+	case format != shareFormatMarkdown:
+	case source.report != nil && !source.report.Empty():
+		content = results.EncodeMarkdown(source.report)
+	default:
+		content = formatResultExport(content)
+	}
+	if anonymize {
+		content = redact.IPs(content)
+	}
+	return content
+}
+
+// uploadShare 在后台上传分享内容，成功后把链接复制到剪贴板。返回的通道在结果提示后关闭。
+func (ui *TestUI) uploadShare(service paste.Service, format, content string) <-chan struct{} {
+	done := make(chan struct{})
+	if service.Kind == paste.KindECS && len(content) > paste.MaxSize {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.share_too_large"), ui.Window)
+		close(done)
+		return done
+	}
+	name := "goecs-result.txt"
+	if format == shareFormatMarkdown {
+		name = "goecs-result.md"
+	}
+	client := proxyHTTPClient(ui.proxySettings(), proxy.Uploads, shareTimeout)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), shareTimeout)
+		link, err := sharePaste(ctx, client, service, name, content)
+		cancel()
+		ui.runOnUI(func() {
+			defer close(done)
+			if err != nil {
+				dialog.ShowError(errors.Join(errors.New(ui.tr("dialog.share_failed")), err), ui.Window)
+				return
+			}
+			ui.App.Clipboard().SetContent(link)
+			dialog.ShowInformation(ui.tr("dialog.success"), ui.tr("dialog.share_ok")+link, ui.Window)
+		})
+	}()
+	return done
+}
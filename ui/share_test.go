@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/paste"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestShareContentFormatsAndAnonymizes(t *testing.T) {
+	source := exportSource{content: "\x1b[32mIPV4 地址: 203.0.113.7\x1b[0m\n"}
+	if got := shareContent(source, shareFormatText, true); got != "IPV4 地址: 203.0.113.*\n" {
+		t.Fatalf("text = %q", got)
+	}
+	if got := shareContent(source, shareFormatMarkdown, false); !strings.HasPrefix(got, "# GOECS Result") || !strings.Contains(got, "203.0.113.7") {
+		t.Fatalf("markdown fallback = %q", got)
+	}
+	source.report = &results.Report{CPU: []results.CPUScore{{Label: "Single-Core", Score: 1234}}}
+	if got := shareContent(source, shareFormatMarkdown, false); got != results.EncodeMarkdown(source.report) {
+		t.Fatalf("markdown = %q", got)
+	}
+}
+
+func TestUploadShareCopiesLink(t *testing.T) {
+	old := sharePaste
+	t.Cleanup(func() { sharePaste = old })
+	var got paste.Service
+	var name string
+	sharePaste = func(_ context.Context, _ *http.Client, service paste.Service, n, _ string) (string, error) {
+		got, name = service, n
+		return "https://paste.example/abc", nil
+	}
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+
+	service := paste.Service{Kind: paste.KindGeneric, URL: "https://paste.example/api"}
+	waitClosed(t, ui.uploadShare(service, shareFormatMarkdown, "| a |"))
+	if got != service || name != "goecs-result.md" {
+		t.Fatalf("uploaded to %+v as %q", got, name)
+	}
+	if clip := ui.App.Clipboard().Content(); clip != "https://paste.example/abc" {
+		t.Fatalf("clipboard = %q", clip)
+	}
+
+	got = paste.Service{}
+	waitClosed(t, ui.uploadShare(paste.Service{Kind: paste.KindECS}, shareFormatText, strings.Repeat("x", paste.MaxSize+1)))
+	if got.Kind != "" {
+		t.Fatal("oversized result should not be uploaded to the goecs paste service")
+	}
+}
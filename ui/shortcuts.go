@@ -0,0 +1,291 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+const shortcutsPreferenceKey = "shortcuts"
+
+// 可绑定快捷键的操作，顺序即设置面板中的显示顺序
+const (
+	shortcutRun      = "run"
+	shortcutStop     = "stop"
+	shortcutClear    = "clear"
+	shortcutSearch   = "search"
+	shortcutSaveLog  = "save_log"
+	shortcutNextTab  = "next_tab"
+	shortcutDebug    = "debug_log"
+	shortcutNextMark = "next_bookmark"
+	shortcutPrevMark = "prev_bookmark"
+)
+
+var shortcutActions = []string{shortcutRun, shortcutStop, shortcutClear, shortcutSearch, shortcutSaveLog, shortcutNextTab, shortcutDebug, shortcutNextMark, shortcutPrevMark}
+
+// defaultKeymap 默认按键；Ctrl 在 macOS 上对应 Cmd
+var defaultKeymap = map[string]string{
+	shortcutRun:      "Ctrl+R",
+	shortcutStop:     "Ctrl+.",
+	shortcutClear:    "Ctrl+L",
+	shortcutSearch:   "Ctrl+F",
+	shortcutSaveLog:  "Ctrl+S",
+	shortcutNextTab:  "Ctrl+Tab",
+	shortcutDebug:    "Ctrl+Shift+D",
+	shortcutNextMark: "Ctrl+F2",
+	shortcutPrevMark: "Ctrl+Shift+F2",
+}
+
+var shortcutModifiers = []struct {
+	name     string
+	modifier fyne.KeyModifier
+}{
+	{"Ctrl", fyne.KeyModifierShortcutDefault},
+	{"Alt", fyne.KeyModifierAlt},
+	{"Shift", fyne.KeyModifierShift},
+	{"Super", fyne.KeyModifierSuper},
+}
+
+// parseShortcut 解析 "Ctrl+Shift+R" 形式的按键组合，修饰键不区分大小写
+func parseShortcut(value string) (*desktop.CustomShortcut, error) {
+	parts := strings.Split(strings.TrimSpace(value), "+")
+	// 允许 "Ctrl++" 表示加号键
+	if len(parts) > 1 && parts[len(parts)-1] == "" && parts[len(parts)-2] == "" {
+		parts = append(parts[:len(parts)-2], "+")
+	}
+	shortcut := &desktop.CustomShortcut{}
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		if i < len(parts)-1 {
+			modifier, ok := shortcutModifier(part)
+			if !ok || shortcut.Modifier&modifier != 0 {
+				return nil, fmt.Errorf("invalid modifier %q in %q", part, value)
+			}
+			shortcut.Modifier |= modifier
+			continue
+		}
+		if part == "" {
+			return nil, fmt.Errorf("missing key in %q", value)
+		}
+		if _, ok := shortcutModifier(part); ok {
+			return nil, fmt.Errorf("missing key in %q", value)
+		}
+		key, ok := shortcutKeyName(part)
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q in %q", part, value)
+		}
+		shortcut.KeyName = key
+	}
+	if shortcut.Modifier == 0 {
+		return nil, fmt.Errorf("shortcut %q needs a modifier", value)
+	}
+	return shortcut, nil
+}
+
+func shortcutModifier(name string) (fyne.KeyModifier, bool) {
+	switch strings.ToLower(name) {
+	case "cmd", "command", "control":
+		return fyne.KeyModifierShortcutDefault, true
+	}
+	for _, m := range shortcutModifiers {
+		if strings.EqualFold(m.name, name) {
+			return m.modifier, true
+		}
+	}
+	return 0, false
+}
+
+// shortcutNamedKeys 可用作快捷键的多字符按键名，单个字符按键直接使用其大写形式
+var shortcutNamedKeys = []fyne.KeyName{
+	fyne.KeyEscape, fyne.KeyReturn, fyne.KeyTab, fyne.KeyBackspace, fyne.KeyInsert, fyne.KeyDelete,
+	fyne.KeyRight, fyne.KeyLeft, fyne.KeyDown, fyne.KeyUp, fyne.KeyPageUp, fyne.KeyPageDown,
+	fyne.KeyHome, fyne.KeyEnd, fyne.KeySpace,
+	fyne.KeyF1, fyne.KeyF2, fyne.KeyF3, fyne.KeyF4, fyne.KeyF5, fyne.KeyF6,
+	fyne.KeyF7, fyne.KeyF8, fyne.KeyF9, fyne.KeyF10, fyne.KeyF11, fyne.KeyF12,
+}
+
+func shortcutKeyName(name string) (fyne.KeyName, bool) {
+	if len(name) == 1 {
+		return fyne.KeyName(strings.ToUpper(name)), true
+	}
+	if strings.EqualFold(name, "enter") {
+		return fyne.KeyReturn, true
+	}
+	for _, key := range shortcutNamedKeys {
+		if strings.EqualFold(string(key), name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// formatShortcut 把按键组合格式化为规范写法，作为键位表的比较与存储形式
+func formatShortcut(shortcut *desktop.CustomShortcut) string {
+	var parts []string
+	for _, m := range shortcutModifiers {
+		if shortcut.Modifier&m.modifier != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	return strings.Join(append(parts, string(shortcut.KeyName)), "+")
+}
+
+// normalizeKeymap 校验键位表：空值表示不绑定，缺失的操作使用默认按键，按键冲突时返回错误
+func normalizeKeymap(keymap map[string]string) (map[string]string, error) {
+	normalized := make(map[string]string, len(shortcutActions))
+	owners := make(map[string]string)
+	for _, action := range shortcutActions {
+		value, ok := keymap[action]
+		if !ok {
+			value = defaultKeymap[action]
+		}
+		if strings.TrimSpace(value) == "" {
+			normalized[action] = ""
+			continue
+		}
+		shortcut, err := parseShortcut(value)
+		if err != nil {
+			return nil, err
+		}
+		key := formatShortcut(shortcut)
+		if owner, taken := owners[key]; taken {
+			return nil, fmt.Errorf("shortcut %s is bound to both %s and %s", key, owner, action)
+		}
+		owners[key] = action
+		normalized[action] = key
+	}
+	return normalized, nil
+}
+
+// loadKeymap 读取保存的键位表，未保存或已损坏时使用默认按键
+func (ui *TestUI) loadKeymap() map[string]string {
+	var saved map[string]string
+	if ui.App != nil {
+		_ = json.Unmarshal([]byte(ui.App.Preferences().String(shortcutsPreferenceKey)), &saved)
+	}
+	keymap, err := normalizeKeymap(saved)
+	if err != nil {
+		keymap, _ = normalizeKeymap(nil)
+	}
+	return keymap
+}
+
+// saveKeymap 校验并保存键位表，随后重新注册窗口快捷键
+func (ui *TestUI) saveKeymap(keymap map[string]string) error {
+	normalized, err := normalizeKeymap(keymap)
+	if err != nil {
+		return err
+	}
+	data, _ := json.Marshal(normalized)
+	ui.App.Preferences().SetString(shortcutsPreferenceKey, string(data))
+	uiLog.Info("keymap saved", "keymap", normalized)
+	ui.registerShortcuts()
+	return nil
+}
+
+func (ui *TestUI) shortcutHandler(action string) func() {
+	switch action {
+	case shortcutRun:
+		return ui.startTests
+	case shortcutStop:
+		return ui.stopTests
+	case shortcutClear:
+		return ui.clearResults
+	case shortcutSearch:
+		return ui.showTerminalSearch
+	case shortcutSaveLog:
+		return ui.saveTerminalLog
+	case shortcutNextTab:
+		return ui.selectNextRunTab
+	case shortcutDebug:
+		return ui.showDebugLog
+	case shortcutNextMark:
+		return func() { ui.Terminal.JumpToBookmark(true) }
+	case shortcutPrevMark:
+		return func() { ui.Terminal.JumpToBookmark(false) }
+	}
+	return nil
+}
+
+// registerShortcuts 按键位表在主窗口与弹出窗口上注册快捷键，重复调用时先移除上一次注册的按键
+func (ui *TestUI) registerShortcuts() {
+	if ui.Window == nil {
+		return
+	}
+	canvases := []fyne.Canvas{ui.Window.Canvas()}
+	for _, panel := range ui.popouts {
+		if panel.poppedOut() {
+			canvases = append(canvases, panel.window.Canvas())
+		}
+	}
+	for _, canvas := range canvases {
+		for _, shortcut := range ui.shortcuts {
+			canvas.RemoveShortcut(shortcut)
+		}
+	}
+	ui.shortcuts = nil
+	keymap := ui.loadKeymap()
+	for _, action := range shortcutActions {
+		shortcut, err := parseShortcut(keymap[action])
+		if err != nil {
+			continue
+		}
+		handler := ui.shortcutHandler(action)
+		for _, canvas := range canvases {
+			canvas.AddShortcut(shortcut, func(fyne.Shortcut) { handler() })
+		}
+		ui.shortcuts = append(ui.shortcuts, shortcut)
+	}
+}
+
+// selectNextRunTab 切换到下一个运行标签页，到末尾后回到主运行
+func (ui *TestUI) selectNextRunTab() {
+	if ui.runTabs == nil || len(ui.runTabs.Items) == 0 {
+		return
+	}
+	ui.showResultTab()
+	ui.runTabs.SelectIndex((ui.runTabs.SelectedIndex() + 1) % len(ui.runTabs.Items))
+}
+
+// showShortcutSettings 编辑各操作的快捷键，留空表示不绑定
+func (ui *TestUI) showShortcutSettings() {
+	keymap := ui.loadKeymap()
+	entries := make(map[string]*widget.Entry, len(shortcutActions))
+	var items []*widget.FormItem
+	for _, action := range shortcutActions {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(defaultKeymap[action])
+		entry.SetText(keymap[action])
+		entries[action] = entry
+		items = append(items, widget.NewFormItem(ui.tr("shortcuts.action."+action), entry))
+	}
+	reset := widget.NewButton(ui.tr("shortcuts.reset"), func() {
+		for _, action := range shortcutActions {
+			entries[action].SetText(defaultKeymap[action])
+		}
+	})
+	hint := widget.NewLabel(ui.tr("shortcuts.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	items = append(items, widget.NewFormItem("", container.NewBorder(nil, nil, nil, reset, hint)))
+
+	form := dialog.NewForm(ui.tr("shortcuts.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		next := make(map[string]string, len(entries))
+		for action, entry := range entries {
+			next[action] = strings.TrimSpace(entry.Text)
+		}
+		if err := ui.saveKeymap(next); err != nil {
+			dialog.ShowError(err, ui.Window)
+		}
+	}, ui.Window)
+	form.Resize(fyne.NewSize(480, 0))
+	form.Show()
+}
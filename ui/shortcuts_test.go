@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"github.com/oneclickvirt/ecs-gui/remote"
+)
+
+func TestParseShortcutNormalizesSpelling(t *testing.T) {
+	tests := map[string]string{
+		"Ctrl+R":         "Ctrl+R",
+		"ctrl + shift+r": "Ctrl+Shift+R",
+		"Shift+Ctrl+.":   "Ctrl+Shift+.",
+		"Cmd+Tab":        "Ctrl+Tab",
+		"Alt++":          "Alt++",
+		"Ctrl+F5":        "Ctrl+F5",
+	}
+	for input, want := range tests {
+		shortcut, err := parseShortcut(input)
+		if err != nil {
+			t.Fatalf("parseShortcut(%q) error = %v", input, err)
+		}
+		if got := formatShortcut(shortcut); got != want {
+			t.Fatalf("parseShortcut(%q) = %q, want %q", input, got, want)
+		}
+	}
+	for _, input := range []string{"", "R", "Ctrl+", "Ctrl+Shift", "Hyper+R", "Ctrl+Foo", "Ctrl+Ctrl+R"} {
+		if _, err := parseShortcut(input); err == nil {
+			t.Fatalf("parseShortcut(%q) should fail", input)
+		}
+	}
+}
+
+func TestNormalizeKeymapFillsDefaultsAndRejectsConflicts(t *testing.T) {
+	keymap, err := normalizeKeymap(map[string]string{shortcutRun: "ctrl+enter", shortcutClear: ""})
+	if err != nil {
+		t.Fatalf("normalizeKeymap() error = %v", err)
+	}
+	if keymap[shortcutRun] != "Ctrl+Return" || keymap[shortcutClear] != "" || keymap[shortcutStop] != defaultKeymap[shortcutStop] {
+		t.Fatalf("unexpected keymap %#v", keymap)
+	}
+	if _, err := normalizeKeymap(map[string]string{shortcutRun: "Ctrl+F"}); err == nil {
+		t.Fatal("binding run to the search key should conflict")
+	}
+}
+
+func TestSaveKeymapRebindsWindowShortcuts(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	if err := ui.saveKeymap(map[string]string{shortcutSearch: "Ctrl+K", shortcutNextTab: "Ctrl+G"}); err != nil {
+		t.Fatalf("saveKeymap() error = %v", err)
+	}
+	if got := ui.loadKeymap()[shortcutSearch]; got != "Ctrl+K" {
+		t.Fatalf("persisted search shortcut = %q", got)
+	}
+	canvas := ui.Window.Canvas().(interface{ TypedShortcut(fyne.Shortcut) })
+	canvas.TypedShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyF, Modifier: fyne.KeyModifierShortcutDefault})
+	if ui.searchBar.Visible() {
+		t.Fatal("the old search shortcut should be unbound")
+	}
+	canvas.TypedShortcut(&desktop.CustomShortcut{KeyName: fyne.KeyK, Modifier: fyne.KeyModifierShortcutDefault})
+	if !ui.searchBar.Visible() {
+		t.Fatal("the remapped search shortcut should open the search bar")
+	}
+	if len(ui.shortcuts) != len(shortcutActions) {
+		t.Fatalf("registered %d shortcuts, want %d", len(ui.shortcuts), len(shortcutActions))
+	}
+}
+
+func TestSelectNextRunTabWraps(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	t.Cleanup(ui.closeAllRunTabs)
+	ui.openRunTab(ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.1"}})
+	ui.runTabs.SelectIndex(0)
+
+	ui.selectNextRunTab()
+	if ui.runTabs.SelectedIndex() != 1 {
+		t.Fatalf("selected index = %d, want 1", ui.runTabs.SelectedIndex())
+	}
+	ui.selectNextRunTab()
+	if ui.runTabs.SelectedIndex() != 0 {
+		t.Fatalf("selected index = %d, want 0 after wrapping", ui.runTabs.SelectedIndex())
+	}
+}
@@ -0,0 +1,191 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// speedSample 是测速阶段的一个节点结果
+type speedSample struct {
+	at       time.Duration
+	node     string
+	down, up float64
+}
+
+// speedChart 在终端旁实时绘制测速阶段各节点的下载/上传速度，没有数据时隐藏。
+// 所有方法都需在界面线程调用。
+type speedChart struct {
+	widget.BaseWidget
+	tr      func(string) string
+	now     func() time.Time
+	start   time.Time
+	samples []speedSample
+}
+
+func newSpeedChart(tr func(string) string) *speedChart {
+	chart := &speedChart{tr: tr, now: time.Now}
+	chart.ExtendBaseWidget(chart)
+	chart.Hide()
+	return chart
+}
+
+// Add 追加一个节点结果并显示图表
+func (c *speedChart) Add(result results.SpeedResult) {
+	now := c.now()
+	if len(c.samples) == 0 {
+		c.start = now
+	}
+	c.samples = append(c.samples, speedSample{at: now.Sub(c.start), node: result.Node, down: result.DownloadMbps, up: result.UploadMbps})
+	c.Show()
+	c.Refresh()
+}
+
+// Reset 清空数据并隐藏图表
+func (c *speedChart) Reset() {
+	c.samples = nil
+	c.Hide()
+	c.Refresh()
+}
+
+func (c *speedChart) MinSize() fyne.Size {
+	return fyne.NewSize(240, 160)
+}
+
+func (c *speedChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &speedChartRenderer{
+		chart:      c,
+		background: canvas.NewRectangle(color.Transparent),
+		legend:     canvas.NewText("", color.Black),
+		down:       canvas.NewText("", color.Black),
+		up:         canvas.NewText("", color.Black),
+		peak:       canvas.NewText("", color.Black),
+		last:       canvas.NewText("", color.Black),
+	}
+	r.legend.TextStyle.Bold = true
+	return r
+}
+
+type speedChartRenderer struct {
+	chart      *speedChart
+	background *canvas.Rectangle
+	legend     *canvas.Text
+	down       *canvas.Text
+	up         *canvas.Text
+	peak       *canvas.Text
+	last       *canvas.Text
+	plot       []fyne.CanvasObject
+	size       fyne.Size
+}
+
+func (r *speedChartRenderer) Destroy() {}
+
+func (r *speedChartRenderer) MinSize() fyne.Size {
+	return r.chart.MinSize()
+}
+
+func (r *speedChartRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.background, r.legend, r.down, r.up, r.peak, r.last}
+	return append(objects, r.plot...)
+}
+
+func (r *speedChartRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.Refresh()
+}
+
+func (r *speedChartRenderer) Refresh() {
+	th := r.chart.Theme()
+	variant := fyne.CurrentApp().Settings().ThemeVariant()
+	pad := th.Size(theme.SizeNameInnerPadding)
+	textSize := th.Size(theme.SizeNameCaptionText)
+	downColor := th.Color(theme.ColorNamePrimary, variant)
+	upColor := th.Color(theme.ColorNameSuccess, variant)
+	foreground := th.Color(theme.ColorNameForeground, variant)
+
+	r.background.FillColor = th.Color(theme.ColorNameInputBackground, variant)
+	r.background.CornerRadius = th.Size(theme.SizeNameInputRadius)
+	r.background.Resize(r.size)
+
+	samples := r.chart.samples
+	peak := 0.0
+	for _, s := range samples {
+		peak = max(peak, s.down, s.up)
+	}
+	r.legend.Text = r.chart.tr("chart.speed.title")
+	r.down.Text = r.chart.tr("chart.speed.down")
+	r.up.Text = r.chart.tr("chart.speed.up")
+	r.peak.Text = fmt.Sprintf(r.chart.tr("chart.speed.peak"), peak)
+	r.last.Text = ""
+	if len(samples) > 0 {
+		s := samples[len(samples)-1]
+		r.last.Text = fmt.Sprintf("%s ↓%.0f ↑%.0f", s.node, s.down, s.up)
+	}
+	for _, text := range []*canvas.Text{r.legend, r.down, r.up, r.peak, r.last} {
+		text.TextSize = textSize
+		text.Color = foreground
+	}
+	r.down.Color, r.up.Color = downColor, upColor
+	r.legend.Move(fyne.NewPos(pad, pad))
+	x := pad + r.legend.MinSize().Width + pad
+	r.down.Move(fyne.NewPos(x, pad))
+	r.up.Move(fyne.NewPos(x+r.down.MinSize().Width+pad, pad))
+	r.peak.Move(fyne.NewPos(pad, pad+textSize*1.4))
+	r.last.Move(fyne.NewPos(pad, r.size.Height-pad-textSize*1.4))
+
+	// 绘图区位于图例与底部文字之间
+	left, top := pad, pad+textSize*3
+	width, height := r.size.Width-2*pad, r.size.Height-top-pad-textSize*2
+	r.plot = r.plot[:0]
+	if width <= 0 || height <= 0 || len(samples) == 0 {
+		canvas.Refresh(r.chart)
+		return
+	}
+	span := samples[len(samples)-1].at
+	point := func(i int, value float64) fyne.Position {
+		// 所有结果几乎同时到达（例如结构化后端一次性返回）时按序号均匀分布
+		fraction := float32(0.5)
+		switch {
+		case span >= time.Second:
+			fraction = float32(samples[i].at) / float32(span)
+		case len(samples) > 1:
+			fraction = float32(i) / float32(len(samples)-1)
+		}
+		y := float32(0)
+		if peak > 0 {
+			y = float32(value / peak)
+		}
+		return fyne.NewPos(left+fraction*width, top+(1-y)*height)
+	}
+	axis := canvas.NewLine(th.Color(theme.ColorNameSeparator, variant))
+	axis.Position1, axis.Position2 = fyne.NewPos(left, top+height), fyne.NewPos(left+width, top+height)
+	r.plot = append(r.plot, axis)
+	for _, series := range []struct {
+		color color.Color
+		value func(speedSample) float64
+	}{
+		{downColor, func(s speedSample) float64 { return s.down }},
+		{upColor, func(s speedSample) float64 { return s.up }},
+	} {
+		for i := range samples {
+			p := point(i, series.value(samples[i]))
+			if i > 0 {
+				line := canvas.NewLine(series.color)
+				line.StrokeWidth = 2
+				line.Position1, line.Position2 = point(i-1, series.value(samples[i-1])), p
+				r.plot = append(r.plot, line)
+			}
+			dot := canvas.NewCircle(series.color)
+			dot.Move(p.SubtractXY(2.5, 2.5))
+			dot.Resize(fyne.NewSize(5, 5))
+			r.plot = append(r.plot, dot)
+		}
+	}
+	canvas.Refresh(r.chart)
+}
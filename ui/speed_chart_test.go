@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestSpeedChartPlotsSamples(t *testing.T) {
+	ui := newTestUIForTest(t)
+	chart := ui.speedChart
+	if chart == nil || chart.Visible() {
+		t.Fatal("speed chart should exist and start hidden")
+	}
+	clock := time.Date(2026, 3, 4, 10, 0, 0, 0, time.UTC)
+	chart.now = func() time.Time { return clock }
+	for i, node := range []string{"a", "b", "c"} {
+		clock = clock.Add(time.Duration(i) * 20 * time.Second)
+		chart.Add(results.SpeedResult{Node: node, DownloadMbps: float64(100 * (i + 1)), UploadMbps: 50})
+	}
+	if !chart.Visible() || len(chart.samples) != 3 || chart.samples[2].at != 60*time.Second {
+		t.Fatalf("samples = %+v", chart.samples)
+	}
+
+	renderer := test.TempWidgetRenderer(t, chart)
+	renderer.Layout(fyne.NewSize(300, 200))
+	lines, dots := 0, 0
+	for _, obj := range renderer.Objects() {
+		switch obj.(type) {
+		case *canvas.Line:
+			lines++
+		case *canvas.Circle:
+			dots++
+		}
+	}
+	// 坐标轴 + 两条折线各 2 段，每个结果上下行各一个点
+	if lines != 5 || dots != 6 {
+		t.Fatalf("lines = %d, dots = %d", lines, dots)
+	}
+
+	ui.clearResults()
+	if chart.Visible() || len(chart.samples) != 0 {
+		t.Fatal("clearResults did not reset the speed chart")
+	}
+}
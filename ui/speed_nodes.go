@@ -0,0 +1,242 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	speedtestmodel "github.com/oneclickvirt/speedtest/model"
+)
+
+// speedNodeGroups 是节点选择器中的节点分组，键与 SpeedTestCustom 的 operator 一致，nearby 表示就近节点
+var speedNodeGroups = []string{"nearby", "ct", "cu", "cmcc", "global", "hk", "tw", "jp", "sg"}
+
+// speedCarriers 是服务器列表的运营商筛选项，空字符串表示全部
+var speedCarriers = []string{"", "ct", "cu", "cmcc", "other"}
+
+// parseSpeedNodes 解析保存在表单中的节点选择，如 "nearby,ct,id:16204"；空文本表示使用预设的默认节点
+func parseSpeedNodes(text string) (groups, serverIDs []string) {
+	for _, token := range strings.Split(text, ",") {
+		token = strings.ToLower(strings.TrimSpace(token))
+		if id, ok := strings.CutPrefix(token, "id:"); ok {
+			if id = strings.TrimSpace(id); id != "" && !slices.Contains(serverIDs, id) {
+				serverIDs = append(serverIDs, id)
+			}
+		} else if slices.Contains(speedNodeGroups, token) && !slices.Contains(groups, token) {
+			groups = append(groups, token)
+		}
+	}
+	return groups, serverIDs
+}
+
+// formatSpeedNodes 是 parseSpeedNodes 的逆操作
+func formatSpeedNodes(groups, serverIDs []string) string {
+	tokens := append([]string(nil), groups...)
+	for _, id := range serverIDs {
+		tokens = append(tokens, "id:"+id)
+	}
+	return strings.Join(tokens, ",")
+}
+
+// speedServerCarrier 把服务器提供商归为电信、联通、移动或其他
+func speedServerCarrier(server speedtestmodel.ServerMetadata) string {
+	provider := strings.ToLower(server.Provider)
+	switch {
+	case provider == "ct" || strings.Contains(provider, "telecom") && strings.Contains(provider, "china"):
+		return "ct"
+	case provider == "cu" || strings.Contains(provider, "unicom"):
+		return "cu"
+	case provider == "cm" || strings.Contains(provider, "china mobile"):
+		return "cmcc"
+	}
+	return "other"
+}
+
+// filterSpeedServers 按关键字（ID、名称、城市、国家或提供商）、国家与运营商筛选服务器
+func filterSpeedServers(servers []speedtestmodel.ServerMetadata, query, country, carrier string) []speedtestmodel.ServerMetadata {
+	query = strings.ToLower(strings.TrimSpace(query))
+	var matched []speedtestmodel.ServerMetadata
+	for _, server := range servers {
+		if country != "" && server.Country != country || carrier != "" && speedServerCarrier(server) != carrier {
+			continue
+		}
+		text := strings.ToLower(strings.Join([]string{server.ID, server.Name, server.City, server.Country, server.Provider}, " "))
+		if query == "" || strings.Contains(text, query) {
+			matched = append(matched, server)
+		}
+	}
+	return matched
+}
+
+// speedServerCountries 返回服务器列表中出现的国家，按名称排序
+func speedServerCountries(servers []speedtestmodel.ServerMetadata) []string {
+	var countries []string
+	for _, server := range servers {
+		if server.Country != "" && !slices.Contains(countries, server.Country) {
+			countries = append(countries, server.Country)
+		}
+	}
+	slices.Sort(countries)
+	return countries
+}
+
+// loadSpeedServers 加载测速服务器列表；离线模式只使用内置快照，在线加载失败时同样回退到内置快照
+func loadSpeedServers(ctx context.Context, offline bool) ([]speedtestmodel.ServerMetadata, error) {
+	var sources []speedtestmodel.RegistrySource
+	if !offline {
+		sources = speedtestmodel.DefaultRegistrySources()
+	}
+	result, err := speedtestmodel.LoadServerRegistry(ctx, nil, sources, 1)
+	return result.Servers, err
+}
+
+// speedNodesSummary 是配置页按钮上显示的当前选择
+func (ui *TestUI) speedNodesSummary() string {
+	groups, serverIDs := parseSpeedNodes(ui.speedNodes)
+	if len(groups)+len(serverIDs) == 0 {
+		return ui.tr("speed.nodes.default")
+	}
+	return fmt.Sprintf(ui.tr("speed.nodes.summary"), len(groups), len(serverIDs))
+}
+
+// setSpeedNodes 保存节点选择并刷新配置页按钮
+func (ui *TestUI) setSpeedNodes(text string) {
+	groups, serverIDs := parseSpeedNodes(text)
+	ui.speedNodes = formatSpeedNodes(groups, serverIDs)
+	if ui.SpeedNodesButton != nil {
+		ui.SpeedNodesButton.SetText(ui.speedNodesSummary())
+	}
+}
+
+// showSpeedNodePicker 打开测速节点选择对话框：勾选运营商/地区分组，或搜索并勾选具体服务器，也可直接填写服务器 ID
+func (ui *TestUI) showSpeedNodePicker() {
+	groups, serverIDs := parseSpeedNodes(ui.speedNodes)
+	groupLabels := make([]string, len(speedNodeGroups))
+	labelToGroup := make(map[string]string, len(speedNodeGroups))
+	var chosenGroups []string
+	for i, group := range speedNodeGroups {
+		groupLabels[i] = ui.tr("speed.group." + group)
+		labelToGroup[groupLabels[i]] = group
+		if slices.Contains(groups, group) {
+			chosenGroups = append(chosenGroups, groupLabels[i])
+		}
+	}
+	groupCheck := widget.NewCheckGroup(groupLabels, nil)
+	groupCheck.Horizontal = true
+	groupCheck.SetSelected(chosenGroups)
+
+	chosen := map[string]bool{}
+	for _, id := range serverIDs {
+		chosen[id] = true
+	}
+	var servers, visible []speedtestmodel.ServerMetadata
+	list := widget.NewList(
+		func() int { return len(visible) },
+		func() fyne.CanvasObject { return widget.NewCheck("", nil) },
+		func(id widget.ListItemID, obj fyne.CanvasObject) {
+			if id >= len(visible) {
+				return
+			}
+			server := visible[id]
+			check := obj.(*widget.Check)
+			check.OnChanged = nil
+			check.SetText(fmt.Sprintf("%s · %s, %s · %s", server.ID, server.Name, server.Country, server.Provider))
+			check.SetChecked(chosen[server.ID])
+			check.OnChanged = func(on bool) { chosen[server.ID] = on }
+		},
+	)
+
+	search := widget.NewEntry()
+	search.SetPlaceHolder(ui.tr("speed.nodes.search"))
+	allLabel := ui.tr("speed.nodes.all")
+	country := widget.NewSelect([]string{allLabel}, nil)
+	country.SetSelected(allLabel)
+	carrierLabels := make([]string, len(speedCarriers))
+	labelToCarrier := make(map[string]string, len(speedCarriers))
+	for i, carrier := range speedCarriers {
+		carrierLabels[i] = allLabel
+		if carrier != "" {
+			carrierLabels[i] = ui.tr("speed.carrier." + carrier)
+		}
+		labelToCarrier[carrierLabels[i]] = carrier
+	}
+	carrier := widget.NewSelect(carrierLabels, nil)
+	carrier.SetSelected(allLabel)
+	applyFilter := func() {
+		countryValue := country.Selected
+		if countryValue == allLabel {
+			countryValue = ""
+		}
+		visible = filterSpeedServers(servers, search.Text, countryValue, labelToCarrier[carrier.Selected])
+		list.Refresh()
+	}
+	search.OnChanged = func(string) { applyFilter() }
+	country.OnChanged = func(string) { applyFilter() }
+	carrier.OnChanged = func(string) { applyFilter() }
+
+	// 不在服务器列表中的 ID 放在手动输入框中，列表加载失败时全部放入
+	manualEntry := widget.NewEntry()
+	manualEntry.SetPlaceHolder(ui.tr("speed.nodes.manual"))
+	manualEntry.SetText(strings.Join(serverIDs, ","))
+	status := widget.NewLabel(ui.tr("speed.nodes.loading"))
+	offline := ui.DataOfflineCheck != nil && ui.DataOfflineCheck.Checked
+	go func() {
+		loaded, err := loadSpeedServers(context.Background(), offline)
+		uiDo(func() {
+			if err != nil {
+				status.SetText(err.Error())
+				return
+			}
+			servers = loaded
+			var unknown []string
+			for _, id := range serverIDs {
+				if !slices.ContainsFunc(servers, func(s speedtestmodel.ServerMetadata) bool { return s.ID == id }) {
+					unknown = append(unknown, id)
+				}
+			}
+			manualEntry.SetText(strings.Join(unknown, ","))
+			country.Options = append([]string{allLabel}, speedServerCountries(servers)...)
+			status.SetText(fmt.Sprintf(ui.tr("speed.nodes.loaded"), len(servers)))
+			applyFilter()
+		})
+	}()
+
+	filters := container.NewGridWithColumns(3, search, country, carrier)
+	top := container.NewVBox(
+		widget.NewLabel(ui.tr("speed.nodes.groups")),
+		groupCheck,
+		widget.NewSeparator(),
+		filters,
+		status,
+	)
+	bottom := container.NewVBox(manualEntry, widget.NewLabel(ui.tr("speed.nodes.hint")))
+	content := container.NewBorder(top, bottom, nil, nil, list)
+
+	picker := dialog.NewCustomConfirm(ui.tr("speed.nodes.title"), ui.tr("hosts.ok"), ui.tr("compare.cancel"), content, func(ok bool) {
+		if !ok {
+			return
+		}
+		var pickedGroups, pickedIDs []string
+		for _, label := range groupCheck.Selected {
+			pickedGroups = append(pickedGroups, labelToGroup[label])
+		}
+		for _, server := range servers {
+			if chosen[server.ID] && !slices.Contains(pickedIDs, server.ID) {
+				pickedIDs = append(pickedIDs, server.ID)
+			}
+		}
+		for _, id := range strings.FieldsFunc(manualEntry.Text, func(r rune) bool { return r == ',' || r == ' ' }) {
+			if !slices.Contains(pickedIDs, id) {
+				pickedIDs = append(pickedIDs, id)
+			}
+		}
+		ui.setSpeedNodes(formatSpeedNodes(pickedGroups, pickedIDs))
+	}, ui.Window)
+	picker.Resize(fyne.NewSize(760, 560))
+	picker.Show()
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"slices"
+	"testing"
+
+	speedtestmodel "github.com/oneclickvirt/speedtest/model"
+)
+
+func TestParseSpeedNodes(t *testing.T) {
+	groups, ids := parseSpeedNodes(" CT, nearby,unknown,id:16204, ct,id:16204,id:99 ")
+	if !slices.Equal(groups, []string{"ct", "nearby"}) || !slices.Equal(ids, []string{"16204", "99"}) {
+		t.Fatalf("parseSpeedNodes() = %v %v", groups, ids)
+	}
+	if got := formatSpeedNodes(groups, ids); got != "ct,nearby,id:16204,id:99" {
+		t.Fatalf("formatSpeedNodes() = %q", got)
+	}
+	config := buildExecutionConfig(executionForm{checks: map[string]bool{}, entries: map[string]string{"speedNodes": "cu,id:1"}})
+	if !slices.Equal(config.SpeedGroups, []string{"cu"}) || !slices.Equal(config.SpeedServerIDs, []string{"1"}) {
+		t.Fatalf("config = %v %v", config.SpeedGroups, config.SpeedServerIDs)
+	}
+}
+
+func TestFilterSpeedServers(t *testing.T) {
+	servers := []speedtestmodel.ServerMetadata{
+		{ID: "16204", Name: "Suzhou", Country: "China", Provider: "CM"},
+		{ID: "5145", Name: "Beijing", Country: "China", Provider: "China Unicom 5G"},
+		{ID: "3", Name: "London", Country: "United Kingdom", Provider: "BT"},
+	}
+	if got := filterSpeedServers(servers, "", "China", "cmcc"); len(got) != 1 || got[0].ID != "16204" {
+		t.Fatalf("carrier filter = %+v", got)
+	}
+	if got := filterSpeedServers(servers, "lond", "", ""); len(got) != 1 || got[0].ID != "3" {
+		t.Fatalf("search = %+v", got)
+	}
+	if got := speedServerCountries(servers); !slices.Equal(got, []string{"China", "United Kingdom"}) {
+		t.Fatalf("countries = %v", got)
+	}
+}
+
+// recordingSpeedCore 只记录测速调用
+type recordingSpeedCore struct {
+	CoreRunner
+	calls []string
+}
+
+func (c *recordingSpeedCore) SpeedTestNearby() { c.calls = append(c.calls, "nearby") }
+
+func (c *recordingSpeedCore) SpeedTestCustom(platform, operator string, num int, language string) {
+	c.calls = append(c.calls, operator)
+}
+
+func (c *recordingSpeedCore) SpeedTestServers(ids []string, offline bool, language string) {
+	c.calls = append(c.calls, ids...)
+}
+
+func TestRunSpeedProfileUsesPickedNodes(t *testing.T) {
+	core := &recordingSpeedCore{}
+	runSpeedProfile(core, ExecutionConfig{PresetKey: "full", SpeedGroups: []string{"nearby", "ct"}, SpeedServerIDs: []string{"16204"}}, "zh")
+	if !slices.Equal(core.calls, []string{"nearby", "ct", "16204"}) {
+		t.Fatalf("calls = %v", core.calls)
+	}
+}
@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	splitHorizontalPreferenceKey = "results_split_horizontal"
+	splitOffsetPreferenceKey     = "results_split_offset"
+	defaultSplitOffset           = 0.68
+)
+
+// createResultsSplit 创建终端与结果面板的分栏，恢复上次的方向与比例
+func (ui *TestUI) createResultsSplit(terminal, results fyne.CanvasObject) *container.Split {
+	prefs := ui.App.Preferences()
+	ui.resultsSplit = container.NewVSplit(terminal, results)
+	ui.resultsSplit.Horizontal = prefs.Bool(splitHorizontalPreferenceKey)
+	ui.resultsSplit.Offset = clampSplitOffset(prefs.FloatWithFallback(splitOffsetPreferenceKey, defaultSplitOffset))
+	return ui.resultsSplit
+}
+
+// clampSplitOffset 避免保存的比例把某一侧完全挤掉
+func clampSplitOffset(offset float64) float64 {
+	if offset < 0.1 || offset > 0.9 {
+		return defaultSplitOffset
+	}
+	return offset
+}
+
+// setSplitHorizontal 切换为左右（true）或上下（false）分栏并保存
+func (ui *TestUI) setSplitHorizontal(horizontal bool) {
+	if ui.resultsSplit == nil || ui.resultsSplit.Horizontal == horizontal {
+		return
+	}
+	ui.resultsSplit.Horizontal = horizontal
+	ui.resultsSplit.Refresh()
+	ui.saveSplitLayout()
+}
+
+// saveSplitLayout 保存分栏方向与当前比例；分隔条拖动没有回调，因此在切换方向和关闭窗口时保存
+func (ui *TestUI) saveSplitLayout() {
+	if ui.resultsSplit == nil || ui.App == nil {
+		return
+	}
+	prefs := ui.App.Preferences()
+	prefs.SetBool(splitHorizontalPreferenceKey, ui.resultsSplit.Horizontal)
+	prefs.SetFloat(splitOffsetPreferenceKey, clampSplitOffset(ui.resultsSplit.Offset))
+}
+
+// createLayoutButton 创建布局菜单按钮：分栏方向与弹出窗口。移动端只有一个窗口，不提供该按钮
+func (ui *TestUI) createLayoutButton() *widget.Button {
+	button := widget.NewButtonWithIcon("", theme.GridIcon(), nil)
+	button.OnTapped = func() {
+		menu := fyne.NewMenu("", ui.layoutMenuItems()...)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(button)
+		if canvas == nil {
+			return
+		}
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
+	}
+	return button
+}
+
+func (ui *TestUI) layoutMenuItems() []*fyne.MenuItem {
+	vertical := fyne.NewMenuItem(ui.tr("split.vertical"), func() { ui.setSplitHorizontal(false) })
+	horizontal := fyne.NewMenuItem(ui.tr("split.horizontal"), func() { ui.setSplitHorizontal(true) })
+	if ui.resultsSplit != nil {
+		horizontal.Checked = ui.resultsSplit.Horizontal
+		vertical.Checked = !ui.resultsSplit.Horizontal
+	}
+	items := []*fyne.MenuItem{vertical, horizontal, fyne.NewMenuItemSeparator()}
+	return append(items, ui.popoutMenuItems()...)
+}
@@ -0,0 +1,35 @@
+package ui
+
+import "testing"
+
+func TestResultsSplitRestoresAndSavesLayout(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	prefs := ui.App.Preferences()
+	prefs.SetBool(splitHorizontalPreferenceKey, true)
+	prefs.SetFloat(splitOffsetPreferenceKey, 0.4)
+	ui.createResultTab()
+	if !ui.resultsSplit.Horizontal || ui.resultsSplit.Offset != 0.4 {
+		t.Fatalf("restored split = horizontal %v offset %v", ui.resultsSplit.Horizontal, ui.resultsSplit.Offset)
+	}
+
+	ui.resultsSplit.SetOffset(0.55)
+	ui.setSplitHorizontal(false)
+	if prefs.Bool(splitHorizontalPreferenceKey) || prefs.Float(splitOffsetPreferenceKey) != 0.55 {
+		t.Fatalf("saved split = horizontal %v offset %v", prefs.Bool(splitHorizontalPreferenceKey), prefs.Float(splitOffsetPreferenceKey))
+	}
+	if items := ui.layoutMenuItems(); !items[0].Checked || items[1].Checked {
+		t.Fatal("menu should mark the vertical layout as active")
+	}
+}
+
+func TestClampSplitOffsetFallsBackToDefault(t *testing.T) {
+	for _, offset := range []float64{0, 0.05, 0.95, 1} {
+		if got := clampSplitOffset(offset); got != defaultSplitOffset {
+			t.Fatalf("clampSplitOffset(%v) = %v, want default", offset, got)
+		}
+	}
+	if got := clampSplitOffset(0.3); got != 0.3 {
+		t.Fatalf("clampSplitOffset(0.3) = %v", got)
+	}
+}
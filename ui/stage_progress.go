@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// sectionStageKeys 把 ecs 输出中的分区标题映射到进度阶段
+var sectionStageKeys = map[results.Section]string{
+	results.SectionBasic:     "progress.basic_security",
+	results.SectionCPU:       "progress.cpu",
+	results.SectionMemory:    "progress.memory",
+	results.SectionDisk:      "progress.disk",
+	results.SectionUnlock:    "progress.unlock",
+	results.SectionIPQuality: "progress.ip_quality",
+	results.SectionEmail:     "progress.email",
+	results.SectionBacktrace: "progress.backtrace",
+	results.SectionRoute:     "progress.nt3",
+	results.SectionPing:      "progress.ping",
+	results.SectionSpeed:     "progress.speed",
+	results.SectionGPU:       "progress.gpu",
+}
+
+// typicalStageDurations 是各阶段在普通 VPS 上的典型耗时，用于估算剩余时间
+var typicalStageDurations = map[string]time.Duration{
+	"progress.precheck":       3 * time.Second,
+	"progress.basic_security": 20 * time.Second,
+	"progress.cpu":            30 * time.Second,
+	"progress.memory":         20 * time.Second,
+	"progress.disk":           60 * time.Second,
+	"progress.deep_hardware":  45 * time.Second,
+	"progress.unlock":         45 * time.Second,
+	"progress.ip_quality":     15 * time.Second,
+	"progress.email":          20 * time.Second,
+	"progress.backtrace":      20 * time.Second,
+	"progress.nt3":            60 * time.Second,
+	"progress.ping":           40 * time.Second,
+	"progress.tgdc":           10 * time.Second,
+	"progress.web":            15 * time.Second,
+	"progress.nat":            10 * time.Second,
+	"progress.tcp":            5 * time.Second,
+	"progress.speed":          90 * time.Second,
+	"progress.iperf3":         30 * time.Second,
+	"progress.tunnel":         40 * time.Second,
+	"progress.gpu":            20 * time.Second,
+	"progress.summary":        5 * time.Second,
+	"progress.upload":         10 * time.Second,
+	"progress.finish":         time.Second,
+	"progress.remote_connect": 5 * time.Second,
+	"progress.remote_prepare": 20 * time.Second,
+}
+
+const defaultStageDuration = 15 * time.Second
+
+func typicalStageDuration(key string) time.Duration {
+	if d, ok := typicalStageDurations[key]; ok {
+		return d
+	}
+	return defaultStageDuration
+}
+
+// outputStageSteps 返回能从输出标题识别出来的阶段（按执行顺序），用于远程等只有文本输出的后端
+func outputStageSteps(config ExecutionConfig) []string {
+	detectable := make(map[string]bool, len(sectionStageKeys))
+	for _, key := range sectionStageKeys {
+		detectable[key] = true
+	}
+	var steps []string
+	for _, key := range buildProgressSteps(config, true) {
+		if detectable[key] {
+			steps = append(steps, key)
+		}
+	}
+	return steps
+}
+
+// stageWatcher 逐行扫描输出流，遇到分区标题时推进进度阶段
+type stageWatcher struct {
+	tracker *progressTracker
+	partial string
+	current string
+}
+
+func newStageWatcher(tracker *progressTracker) *stageWatcher {
+	return &stageWatcher{tracker: tracker}
+}
+
+// Observe 处理一段输出，不完整的行会保留到下一段
+func (w *stageWatcher) Observe(text string) {
+	text = w.partial + text
+	lines := strings.Split(text, "\n")
+	w.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		w.observeLine(line)
+	}
+}
+
+func (w *stageWatcher) observeLine(line string) {
+	section, ok := results.DetectSection(results.StripANSI(line))
+	if !ok {
+		return
+	}
+	key, ok := sectionStageKeys[section]
+	if !ok || key == w.current {
+		return
+	}
+	if w.current != "" {
+		w.tracker.finish(w.current)
+	}
+	w.current = key
+	w.tracker.start(key)
+}
+
+// Close 结束最后一个阶段
+func (w *stageWatcher) Close() {
+	if w.partial != "" {
+		w.observeLine(w.partial)
+		w.partial = ""
+	}
+	if w.current != "" {
+		w.tracker.finish(w.current)
+		w.current = ""
+	}
+}
+
+// etaEstimator 根据典型阶段耗时估算剩余时间，并按已完成阶段的实际/典型耗时比例校正
+type etaEstimator struct {
+	mu         sync.Mutex
+	last       ProgressUpdate
+	current    string
+	stageStart time.Time
+	typical    time.Duration
+	actual     time.Duration
+}
+
+// Observe 记录一次进度更新
+func (e *etaEstimator) Observe(update ProgressUpdate, now time.Time) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.last = update
+	if e.current != "" && (update.ItemKey != e.current || update.Done) {
+		e.typical += typicalStageDuration(e.current)
+		e.actual += now.Sub(e.stageStart)
+		e.current = ""
+	}
+	if !update.Done {
+		e.current = update.ItemKey
+		e.stageStart = now
+	}
+}
+
+// Last 返回最近一次进度更新
+func (e *etaEstimator) Last() ProgressUpdate {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last
+}
+
+// Remaining 返回估算的剩余时间；尚无任何进度时 ok 为 false
+func (e *etaEstimator) Remaining(now time.Time) (time.Duration, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.last.ItemKey == "" {
+		return 0, false
+	}
+	scale := 1.0
+	if e.typical > 0 {
+		scale = float64(e.actual) / float64(e.typical)
+		scale = min(max(scale, 0.3), 4)
+	}
+	var remaining time.Duration
+	for _, key := range e.last.Pending {
+		remaining += time.Duration(float64(typicalStageDuration(key)) * scale)
+	}
+	if e.current != "" {
+		left := time.Duration(float64(typicalStageDuration(e.current))*scale) - now.Sub(e.stageStart)
+		if left > 0 {
+			remaining += left
+		}
+	}
+	return remaining, true
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStageWatcherFollowsSectionHeaders(t *testing.T) {
+	var updates []ProgressUpdate
+	tracker := newProgressTracker(func(u ProgressUpdate) { updates = append(updates, u) },
+		[]string{"progress.cpu", "progress.disk", "progress.speed"})
+	watcher := newStageWatcher(tracker)
+
+	watcher.Observe("\x1b[32m-------CPU测试-通过sysbench测试-------\x1b[0m\n1 线程测试(单核)得分: 100\n----硬")
+	watcher.Observe("盘测试----\nnoise\n")
+	watcher.Observe("--------就近节点测速--------\n")
+	watcher.Close()
+
+	var got []string
+	for _, u := range updates {
+		state := "start"
+		if u.Done {
+			state = "done"
+		}
+		got = append(got, state+":"+u.ItemKey)
+	}
+	want := []string{
+		"start:progress.cpu", "done:progress.cpu",
+		"start:progress.disk", "done:progress.disk",
+		"start:progress.speed", "done:progress.speed",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("updates = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("updates = %v, want %v", got, want)
+		}
+	}
+	if last := updates[len(updates)-1]; last.Fraction != 1 || len(last.Pending) != 0 {
+		t.Fatalf("last update = %#v", last)
+	}
+	if pending := updates[0].Pending; len(pending) != 2 || pending[0] != "progress.disk" {
+		t.Fatalf("pending after cpu start = %v", pending)
+	}
+}
+
+func TestETAEstimatorScalesByObservedSpeed(t *testing.T) {
+	var eta etaEstimator
+	if _, ok := eta.Remaining(time.Now()); ok {
+		t.Fatal("Remaining() without progress should not be ok")
+	}
+	start := time.Unix(1000, 0)
+	eta.Observe(ProgressUpdate{ItemKey: "progress.cpu", Pending: []string{"progress.disk"}}, start)
+	// CPU 典型 30s，实际 60s：之后的阶段按 2 倍估算
+	eta.Observe(ProgressUpdate{ItemKey: "progress.cpu", Done: true, Pending: []string{"progress.disk"}}, start.Add(60*time.Second))
+	if got, _ := eta.Remaining(start.Add(60 * time.Second)); got != 120*time.Second {
+		t.Fatalf("Remaining() between stages = %v, want 2m0s", got)
+	}
+	now := start.Add(60 * time.Second)
+	eta.Observe(ProgressUpdate{ItemKey: "progress.disk"}, now)
+	if got, _ := eta.Remaining(now.Add(30 * time.Second)); got != 90*time.Second {
+		t.Fatalf("Remaining() during disk = %v, want 1m30s", got)
+	}
+	if got, _ := eta.Remaining(now.Add(10 * time.Minute)); got != 0 {
+		t.Fatalf("Remaining() after overrun = %v, want 0", got)
+	}
+}
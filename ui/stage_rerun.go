@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+var errRerunNoResults = errors.New("the re-run produced no results for the stage")
+
+// stageRerun 标记只重跑部分测试项的运行标签页，结束后把新结果合并进原历史记录 runID，不另存记录
+type stageRerun struct {
+	runID string
+	tests []string
+}
+
+// retestedAt 返回 tests 中任一项最近一次重跑的时间，未重跑过时返回零值
+func retestedAt(summary history.Summary, tests []string) time.Time {
+	var at time.Time
+	for _, test := range tests {
+		if t := summary.RetestedAt(test); t.After(at) {
+			at = t
+		}
+	}
+	return at
+}
+
+// rerunTests 返回分类 tab 需要重跑的测试项：只取原运行中选过的，原运行没有记录测试项时取全部
+func rerunTests(tab parsedResultTab, record history.Summary) []string {
+	var tests []string
+	for _, test := range tab.tests {
+		if slices.Contains(record.Tests, test) {
+			tests = append(tests, test)
+		}
+	}
+	if len(tests) == 0 {
+		return slices.Clone(tab.tests)
+	}
+	return tests
+}
+
+// stageRerunConfig 在 config 的基础上只保留 tests，其余设置（目标、方式、线程等）不变
+func stageRerunConfig(config ExecutionConfig, tests []string) ExecutionConfig {
+	selected := maps.Clone(config.SelectedOptions)
+	for key := range selected {
+		selected[key] = slices.Contains(tests, key)
+	}
+	config.SelectedOptions = selected
+	config.PingTgdc = false
+	config.PingWeb = false
+	return config
+}
+
+// mergeRerunReport 返回 base 的副本，其中 tests 对应的分区换成 fresh 中的结果；fresh 中没有结果的分区保留原值
+func mergeRerunReport(base, fresh *results.Report, tests []string) (*results.Report, error) {
+	merged := &results.Report{}
+	if base != nil {
+		copied := *base
+		merged = &copied
+	}
+	replaced := false
+	for _, test := range tests {
+		switch test {
+		case "cpu":
+			if len(fresh.CPU) > 0 {
+				merged.CPU, merged.GeekbenchLink, merged.GeekbenchClaim = fresh.CPU, fresh.GeekbenchLink, fresh.GeekbenchClaim
+				replaced = true
+			}
+		case "memory":
+			if len(fresh.Memory) > 0 {
+				merged.Memory, replaced = fresh.Memory, true
+			}
+		case "disk":
+			if len(fresh.Disk) > 0 {
+				merged.Disk, replaced = fresh.Disk, true
+			}
+		case "speed":
+			if len(fresh.Speed) > 0 {
+				merged.Speed, replaced = fresh.Speed, true
+			}
+		case "security":
+			if len(fresh.IPQuality) > 0 {
+				merged.IPQuality, merged.IPDatabases, replaced = fresh.IPQuality, fresh.IPDatabases, true
+			}
+		case "unlock":
+			if len(fresh.Unlock) > 0 {
+				merged.Unlock, replaced = fresh.Unlock, true
+			}
+		case "backtrace":
+			if len(fresh.Backtrace) > 0 {
+				merged.Backtrace, replaced = fresh.Backtrace, true
+			}
+		case "nt3":
+			if len(fresh.Routes) > 0 {
+				merged.Routes, replaced = fresh.Routes, true
+			}
+		}
+	}
+	if !replaced {
+		return nil, errRerunNoResults
+	}
+	return merged, nil
+}
+
+// stageRerunArea 包住结果面板，右键（移动端长按）时弹出当前分类的重跑菜单
+type stageRerunArea struct {
+	widget.BaseWidget
+	content fyne.CanvasObject
+	menu    func() *fyne.Menu
+}
+
+func newStageRerunArea(content fyne.CanvasObject, menu func() *fyne.Menu) *stageRerunArea {
+	area := &stageRerunArea{content: content, menu: menu}
+	area.ExtendBaseWidget(area)
+	return area
+}
+
+func (a *stageRerunArea) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(a.content)
+}
+
+// TappedSecondary 弹出重跑菜单，当前分类不能重跑时不弹出
+func (a *stageRerunArea) TappedSecondary(e *fyne.PointEvent) {
+	menu := a.menu()
+	if menu == nil {
+		return
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(a); c != nil {
+		widget.ShowPopUpMenuAtPosition(menu, c, e.AbsolutePosition)
+	}
+}
+
+// selectedResultTab 返回结果面板当前选中的分类，概览页返回 false
+func (ui *TestUI) selectedResultTab() (parsedResultTab, bool) {
+	if ui.resultsTabs == nil {
+		return parsedResultTab{}, false
+	}
+	index := ui.resultsTabs.SelectedIndex() - 1
+	if index < 0 || index >= len(parsedResultTabs) {
+		return parsedResultTab{}, false
+	}
+	return parsedResultTabs[index], true
+}
+
+// stageRerunMenu 返回结果面板当前分类的右键菜单；分类没有对应的测试项时返回 nil
+func (ui *TestUI) stageRerunMenu() *fyne.Menu {
+	tab, ok := ui.selectedResultTab()
+	if !ok || len(tab.tests) == 0 {
+		return nil
+	}
+	ui.Mu.Lock()
+	record := ui.resultsRecord
+	running := ui.IsRunning
+	ui.Mu.Unlock()
+	rerun := fyne.NewMenuItem(fmt.Sprintf(ui.tr("rerun.menu"), ui.tr(tab.titleKey)), func() { ui.rerunStage(tab) })
+	rerun.Disabled = record == nil || running
+	items := []*fyne.MenuItem{rerun}
+	if record != nil {
+		if at := retestedAt(*record, tab.tests); !at.IsZero() {
+			info := fyne.NewMenuItem(fmt.Sprintf(ui.tr("rerun.retested_at"), at.Local().Format("2006-01-02 15:04:05")), nil)
+			info.Disabled = true
+			items = append(items, info)
+		}
+	}
+	return fyne.NewMenu("", items...)
+}
+
+// rerunStage 按当前设置在新标签页中只重跑分类 tab 对应的测试项，结束后合并进结果面板当前展示的记录，需在 UI 线程调用
+func (ui *TestUI) rerunStage(tab parsedResultTab) {
+	ui.Mu.Lock()
+	record := ui.resultsRecord
+	ui.Mu.Unlock()
+	if record == nil {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("rerun.no_record"), ui.Window)
+		return
+	}
+	if _, err := ui.remoteTarget(); err != nil {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.remote_invalid")+"\n"+err.Error(), ui.Window)
+		return
+	}
+	tests := rerunTests(tab, *record)
+	config := stageRerunConfig(ui.collectExecutionConfig(), tests)
+	if host := runHost(config); host != record.Host {
+		dialog.ShowInformation(ui.tr("dialog.hint"), fmt.Sprintf(ui.tr("rerun.host_changed"), record.Host, host), ui.Window)
+		return
+	}
+	if !ui.reserveTabRun(config) {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("run_tabs.local_busy"), ui.Window)
+		return
+	}
+	runID := record.ID
+	ui.confirmLaunch(config, func(config ExecutionConfig) {
+		run := ui.openRunTab(config)
+		run.rerun = &stageRerun{runID: runID, tests: tests}
+		run.title = fmt.Sprintf(ui.tr("rerun.tab_title"), ui.tr(tab.titleKey))
+		run.item.Text = run.title
+		ui.runTabs.Refresh()
+		ui.runTabs.Select(run.item)
+		ui.showResultTab()
+		run.start()
+	}, func() { ui.releaseTabRun(config) })
+}
+
+// mergeStageRerun 把重跑的输出与结果合并进原记录并注明重跑时间；结果面板仍展示该记录时一并刷新，可在任意 goroutine 调用
+func (ui *TestUI) mergeStageRerun(rerun *stageRerun, at time.Time, output string, report *results.Report) error {
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return errors.New("history is unavailable")
+	}
+	run, err := store.Load(rerun.runID)
+	if err != nil {
+		return err
+	}
+	base := run.Results
+	if base == nil {
+		base = results.Parse(run.Output)
+	}
+	merged, err := mergeRerunReport(base, report, rerun.tests)
+	if err != nil {
+		return err
+	}
+	marker := fmt.Sprintf(ui.tr("rerun.marker"), ui.testLabels(rerun.tests), at.Local().Format("2006-01-02 15:04:05"))
+	run.Output = strings.TrimRight(run.Output, "\n") + "\n\n" + marker + "\n" + output
+	run.Results = merged
+	run.Retests = append(run.Retests, history.Retest{Tests: rerun.tests, At: at})
+	saved, ok := ui.saveHistoryRun(run)
+	if !ok {
+		return errors.New("failed to save the history record")
+	}
+
+	ui.Mu.Lock()
+	current := ui.resultsRecord != nil && ui.resultsRecord.ID == saved.ID
+	if current {
+		ui.resultsRecord = &saved.Summary
+		ui.ParsedResults = merged
+	}
+	ui.Mu.Unlock()
+	if current {
+		ui.runOnUI(func() { ui.renderParsedResults(merged) })
+	}
+	return nil
+}
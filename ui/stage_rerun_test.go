@@ -0,0 +1,103 @@
+package ui
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestStageRerunMergesIntoRecord(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	t.Cleanup(ui.closeAllRunTabs)
+	original := runTabRunner
+	t.Cleanup(func() { runTabRunner = original })
+	runTabRunner = func(ExecutionConfig) executionRunner {
+		return fakeRemoteRunner{output: "-----------就近节点测速-----------\n Speedtest.net   300 Mbps    400 Mbps    1.2 ms    0.0%\n"}
+	}
+
+	saved, ok := ui.saveHistoryRun(history.Run{
+		Summary: history.Summary{StartedAt: time.Now().Add(-time.Hour), Host: "10.0.0.1", Tests: []string{"cpu", "speed"}},
+		Output:  "original output\n",
+		Results: &results.Report{
+			CPU:   []results.CPUScore{{Label: "1 线程", Score: 1000}},
+			Speed: []results.SpeedResult{{Node: "Speedtest.net", UploadMbps: 1, DownloadMbps: 2}},
+		},
+	})
+	if !ok {
+		t.Fatal("the record should be saved")
+	}
+	ui.loadHistoryRun(saved)
+
+	speed := parsedResultTabs[3]
+	tests := rerunTests(speed, saved.Summary)
+	tab := ui.openRunTab(stageRerunConfig(ExecutionConfig{Remote: &remote.Target{Host: "10.0.0.1"}}, tests))
+	tab.rerun = &stageRerun{runID: saved.ID, tests: tests}
+	tab.start()
+	waitClosed(t, tab.done)
+
+	store := ui.historyStoreOrOpen()
+	items, _ := store.List()
+	if len(items) != 1 {
+		t.Fatalf("history items = %d, the re-run should not add a record", len(items))
+	}
+	run, err := store.Load(saved.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(run.Results.Speed) != 1 || run.Results.Speed[0].DownloadMbps != 400 || len(run.Results.CPU) != 1 {
+		t.Fatalf("merged results = %+v", run.Results)
+	}
+	if run.RetestedAt("speed").IsZero() || !run.RetestedAt("cpu").IsZero() {
+		t.Fatalf("retests = %+v", run.Retests)
+	}
+	if !strings.HasPrefix(run.Output, "original output\n\n=====") || !strings.Contains(run.Output, "400 Mbps") {
+		t.Fatalf("output = %q", run.Output)
+	}
+	ui.Mu.Lock()
+	report := ui.ParsedResults
+	ui.Mu.Unlock()
+	if report.Speed[0].DownloadMbps != 400 || !strings.HasSuffix(ui.resultsTabs.Items[4].Text, "↻") {
+		t.Fatalf("the results panel should show the merged record, speed tab = %q", ui.resultsTabs.Items[4].Text)
+	}
+}
+
+func TestStageRerunSelectsOnlyTheSection(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	route := parsedResultTabs[6]
+	tests := rerunTests(route, history.Summary{Tests: []string{"basic", "nt3", "speed"}})
+	if !slices.Equal(tests, []string{"nt3"}) {
+		t.Fatalf("tests = %v, want only the traced routes of the original run", tests)
+	}
+	config := stageRerunConfig(ExecutionConfig{SelectedOptions: ui.GetSelectedOptions(), PingWeb: true}, tests)
+	for key, selected := range config.SelectedOptions {
+		if selected != (key == "nt3") {
+			t.Fatalf("%s selected = %v", key, selected)
+		}
+	}
+	if config.PingWeb {
+		t.Fatal("latency checks should not be re-run")
+	}
+
+	if _, err := mergeRerunReport(&results.Report{}, &results.Report{}, []string{"cpu"}); err != errRerunNoResults {
+		t.Fatalf("err = %v, an empty re-run should not replace results", err)
+	}
+
+	ui.createResultTab()
+	ui.resultsTabs.SelectIndex(0)
+	if ui.stageRerunMenu() != nil {
+		t.Fatal("the overview tab has no stage to re-run")
+	}
+	ui.resultsTabs.SelectIndex(1)
+	menu := ui.stageRerunMenu()
+	if menu == nil || !menu.Items[0].Disabled {
+		t.Fatal("re-running needs a saved record")
+	}
+}
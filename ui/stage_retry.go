@@ -0,0 +1,129 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	// maxStageRetries 是网络阶段最多的重试次数
+	maxStageRetries = 5
+	// defaultRetryBackoff 是第一次重试前的默认等待时间，之后每次加倍
+	defaultRetryBackoff = 10 * time.Second
+)
+
+// retryStageKeys 是失败后可以自动重试的网络阶段
+var retryStageKeys = []string{"progress.unlock", "progress.speed"}
+
+// retryStageSections 是检查各阶段输出时使用的分区标题
+var retryStageSections = map[string]string{
+	"progress.unlock": "Unlock",
+	"progress.speed":  "Speed",
+}
+
+// transientUnlockStatuses 是表示请求失败而不是平台给出结论的解锁状态
+var transientUnlockStatuses = []string{"Failed", "Error", "TIMEOUT", "Unknown"}
+
+// parseStageRetries 解析表单中的重试次数与退避时间，无效或留空时分别为 0 与 defaultRetryBackoff
+func parseStageRetries(count, backoff string) (int, time.Duration) {
+	retries, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || retries < 0 {
+		retries = 0
+	}
+	wait, err := time.ParseDuration(strings.TrimSpace(backoff))
+	if err != nil || wait <= 0 {
+		wait = defaultRetryBackoff
+	}
+	return min(retries, maxStageRetries), wait
+}
+
+// stageAttemptFailed 判断一次网络阶段的输出是否像是网络抖动导致的失败：
+// 测速没有得到任何结果，或流媒体解锁的每个平台都没有给出结论
+func stageAttemptFailed(key, output string) bool {
+	title, ok := retryStageSections[key]
+	if !ok {
+		return false
+	}
+	report := results.Parse(centeredTitle(title, 40) + "\n" + output)
+	switch key {
+	case "progress.speed":
+		return len(report.Speed) == 0
+	case "progress.unlock":
+		for _, result := range report.Unlock {
+			if !slices.Contains(transientUnlockStatuses, result.Status) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// retryNetworkStage 运行网络阶段的一次尝试 attempt，结果看起来是网络抖动导致的失败时按 config 设置的次数重试，
+// 第 n 次重试前调用 retrying 并等待 RetryBackoff 的 2^(n-1) 倍；返回最后一次尝试的输出与重试次数
+func retryNetworkStage(ctx context.Context, config ExecutionConfig, key string, attempt func() string, retrying func(retry int, wait time.Duration)) (string, int) {
+	output := attempt()
+	retries := 0
+	for retries < config.StageRetries && slices.Contains(retryStageKeys, key) && stageAttemptFailed(key, output) {
+		wait := config.RetryBackoff << retries
+		if retrying != nil {
+			retrying(retries+1, wait)
+		}
+		select {
+		case <-ctx.Done():
+			return output, retries
+		case <-time.After(wait):
+		}
+		retries++
+		output = attempt()
+	}
+	return output, retries
+}
+
+// stageRetryNote 是重试前写入终端的提示
+func stageRetryNote(language string, retry, total int, wait time.Duration) string {
+	return pickLanguage(language,
+		fmt.Sprintf(" 未得到有效结果，%s 后重试（%d/%d）\n", wait, retry, total),
+		fmt.Sprintf(" No usable results, retrying in %s (%d/%d)\n", wait, retry, total))
+}
+
+// stageRetriedNote 是重试后得到结果时写在阶段输出之后的提示
+func stageRetriedNote(language string, retries int) string {
+	return pickLanguage(language,
+		fmt.Sprintf(" 因网络问题已自动重试 %d 次\n", retries),
+		fmt.Sprintf(" Retried %d times after network errors\n", retries))
+}
+
+// teeStdout 运行 fn 并返回其写到标准输出的内容，输出同时照常写到原来的标准输出
+func teeStdout(fn func()) string {
+	out := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		fn()
+		return ""
+	}
+	var captured strings.Builder
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = io.Copy(io.MultiWriter(out, &captured), r)
+	}()
+	os.Stdout = w
+	fn()
+	// 阶段超时后被放弃时执行器可能已经恢复了标准输出，此时不再改回
+	if os.Stdout == w {
+		os.Stdout = out
+	}
+	_ = w.Close()
+	<-done
+	_ = r.Close()
+	return captured.String()
+}
@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStageRetries(t *testing.T) {
+	tests := []struct {
+		count, backoff string
+		retries        int
+		wait           time.Duration
+	}{
+		{"", "", 0, defaultRetryBackoff},
+		{"2", "3s", 2, 3 * time.Second},
+		{"9", "-1s", maxStageRetries, defaultRetryBackoff},
+		{"many", "soon", 0, defaultRetryBackoff},
+	}
+	for _, tt := range tests {
+		if retries, wait := parseStageRetries(tt.count, tt.backoff); retries != tt.retries || wait != tt.wait {
+			t.Fatalf("parseStageRetries(%q, %q) = %d, %s", tt.count, tt.backoff, retries, wait)
+		}
+	}
+}
+
+func TestStageAttemptFailed(t *testing.T) {
+	speed := " Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n"
+	if stageAttemptFailed("progress.speed", speed) || !stageAttemptFailed("progress.speed", " 测速节点获取失败\n") {
+		t.Fatal("a speed test is failed only when no node produced a result")
+	}
+	failed := " Netflix                   Failed\n YouTube Premium           TIMEOUT\n"
+	if !stageAttemptFailed("progress.unlock", failed) || !stageAttemptFailed("progress.unlock", "") {
+		t.Fatal("an unlock check where every platform failed should be retried")
+	}
+	if stageAttemptFailed("progress.unlock", failed+" Disney+                   NO\n") {
+		t.Fatal("a definite answer from any platform means the network worked")
+	}
+	if stageAttemptFailed("progress.nt3", "") {
+		t.Fatal("only network stages with a retry policy can fail an attempt")
+	}
+}
+
+func TestRetryNetworkStageBacksOffUntilResults(t *testing.T) {
+	config := ExecutionConfig{StageRetries: 3, RetryBackoff: time.Millisecond}
+	attempts := 0
+	var waits []time.Duration
+	output, retries := retryNetworkStage(context.Background(), config, "progress.unlock", func() string {
+		attempts++
+		if attempts < 3 {
+			return " Netflix                   Failed\n"
+		}
+		return " Netflix                   YES (Region: US)\n"
+	}, func(retry int, wait time.Duration) { waits = append(waits, wait) })
+	if attempts != 3 || retries != 2 || !strings.Contains(output, "YES") {
+		t.Fatalf("attempts = %d, retries = %d, output = %q", attempts, retries, output)
+	}
+	if fmt.Sprint(waits) != "[1ms 2ms]" {
+		t.Fatalf("waits = %v, want the delay to double", waits)
+	}
+
+	attempts = 0
+	_, retries = retryNetworkStage(context.Background(), config, "progress.speed", func() string { attempts++; return "" }, nil)
+	if attempts != 4 || retries != 3 {
+		t.Fatalf("attempts = %d, retries = %d, want the retry count to be the limit", attempts, retries)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	attempts = 0
+	if _, retries = retryNetworkStage(ctx, config, "progress.speed", func() string { attempts++; return "" }, nil); attempts != 1 || retries != 0 {
+		t.Fatalf("a canceled run should not retry, attempts = %d", attempts)
+	}
+}
+
+func TestTeeStdoutCapturesAndForwards(t *testing.T) {
+	file, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	old := os.Stdout
+	os.Stdout = file
+	t.Cleanup(func() { os.Stdout = old })
+
+	captured := teeStdout(func() { fmt.Print("speed line\n") })
+	if captured != "speed line\n" || os.Stdout != file {
+		t.Fatalf("captured = %q, stdout restored = %v", captured, os.Stdout == file)
+	}
+	data, _ := os.ReadFile(file.Name())
+	if string(data) != "speed line\n" {
+		t.Fatalf("forwarded = %q", data)
+	}
+}
+
+func TestStructuredDetailsShowRetries(t *testing.T) {
+	tracker := newProgressTracker(nil, []string{"progress.speed"})
+	tracker.retries["progress.speed"] = 2
+	tracker.finish("progress.speed")
+	report := buildGUIStructuredReport(ExecutionConfig{SelectedOptions: map[string]bool{"speed": true}}, true, tracker, nil, context.Background(), time.Now(), time.Now())
+	if !strings.Contains(formatStructuredDetails(report, langEN), "retried 2 times") {
+		t.Fatalf("details = %s", formatStructuredDetails(report, langEN))
+	}
+}
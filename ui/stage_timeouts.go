@@ -0,0 +1,211 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// stageTimeoutKeys 是可以单独设置超时的阶段，顺序与执行顺序一致；基础信息阶段会写入后续阶段依赖的 IP 信息，不能中途放弃
+var stageTimeoutKeys = []string{
+	"progress.cpu", "progress.memory", "progress.disk", "progress.unlock", "progress.email",
+	"progress.backtrace", "progress.nt3", "progress.ping", "progress.speed", "progress.iperf3", "progress.tunnel", "progress.gpu",
+}
+
+// defaultStageTimeouts 是未单独设置时的超时，流媒体解锁与邮件端口检测一直有这两个时限
+var defaultStageTimeouts = map[string]time.Duration{
+	"progress.unlock": 5 * time.Minute,
+	"progress.email":  3 * time.Minute,
+}
+
+// errStageTimeout 表示阶段超过了设置的时限
+var errStageTimeout = errors.New("stage timed out")
+
+// parseStageTimeouts 解析保存在表单中的阶段超时，如 "nt3=5m, backtrace=2m"；阶段名为去掉 progress. 前缀的进度项
+func parseStageTimeouts(text string) (map[string]time.Duration, error) {
+	timeouts := map[string]time.Duration{}
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool { return r == ',' || r == '\n' }) {
+		name, value, ok := strings.Cut(strings.TrimSpace(field), "=")
+		if !ok {
+			if strings.TrimSpace(field) == "" {
+				continue
+			}
+			return nil, fmt.Errorf("invalid stage timeout %q, want stage=duration", strings.TrimSpace(field))
+		}
+		key := "progress." + strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(stageTimeoutKeys, key) {
+			return nil, fmt.Errorf("unknown stage %q", strings.TrimSpace(name))
+		}
+		limit, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q for stage %s", strings.TrimSpace(value), strings.TrimSpace(name))
+		}
+		timeouts[key] = limit
+	}
+	return timeouts, nil
+}
+
+// formatStageTimeouts 是 parseStageTimeouts 的逆操作，按执行顺序输出
+func formatStageTimeouts(timeouts map[string]time.Duration) string {
+	var fields []string
+	for _, key := range stageTimeoutKeys {
+		if limit := timeouts[key]; limit > 0 {
+			fields = append(fields, strings.TrimPrefix(key, "progress.")+"="+limit.String())
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// stageTimeout 返回 key 阶段的时限，为 0 表示不限时
+func (config ExecutionConfig) stageTimeout(key string) time.Duration {
+	if limit, ok := config.StageTimeouts[key]; ok {
+		return limit
+	}
+	return defaultStageTimeouts[key]
+}
+
+// stageTimeoutNote 是阶段超时后写入终端的提示
+func stageTimeoutNote(language string, limit time.Duration) string {
+	return pickLanguage(language,
+		fmt.Sprintf(" 该阶段超过 %s 未完成，已结束并跳过\n", limit),
+		fmt.Sprintf(" Stage did not finish within %s and was skipped\n", limit))
+}
+
+// stageTimeoutReason 是超时阶段在报告中的原因
+func stageTimeoutReason(limit time.Duration) string {
+	return fmt.Sprintf("stage timed out after %s", limit)
+}
+
+// runStageWithTimeout 运行一个阶段，timeout 为 0 时直接运行。超时后取消 fn 收到的 ctx、调用 onTimeout 结束阶段派生的进程，
+// 等 fn 返回后再返回 errStageTimeout，以免其输出混入下一阶段；整个测试被取消时返回 parent 的错误
+func runStageWithTimeout(parent context.Context, timeout time.Duration, fn func(ctx context.Context), onTimeout func()) error {
+	if timeout <= 0 {
+		fn(parent)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(parent, timeout)
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		fn(ctx)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		if err := parent.Err(); err != nil {
+			return err
+		}
+	}
+	onTimeout()
+	select {
+	case <-done:
+		return errStageTimeout
+	case <-parent.Done():
+		return parent.Err()
+	}
+}
+
+// runTimedStage 在 key 阶段的时限内运行界面自行实现、支持取消的阶段（iperf3、隧道对比），
+// 超时时在 out 中提示并把分区与组件记为 timeout
+func runTimedStage(parent context.Context, config ExecutionConfig, key string, out io.Writer, run func(ctx context.Context) (StructuredSection, StructuredComponent)) (StructuredSection, StructuredComponent) {
+	limit := config.stageTimeout(key)
+	ctx, cancel := parent, context.CancelFunc(func() {})
+	if limit > 0 {
+		ctx, cancel = context.WithTimeout(parent, limit)
+	}
+	defer cancel()
+	section, component := run(ctx)
+	if ctx.Err() != nil && parent.Err() == nil {
+		fmt.Fprint(out, stageTimeoutNote(config.Language, limit))
+		section.Status, section.Reason = "timeout", stageTimeoutReason(limit)
+		component.Status, component.Reason = section.Status, section.Reason
+	}
+	return section, component
+}
+
+// setStageTimeouts 保存阶段超时设置，无效的文本按未设置处理
+func (ui *TestUI) setStageTimeouts(text string) {
+	timeouts, err := parseStageTimeouts(text)
+	if err != nil {
+		timeouts = nil
+	}
+	ui.stageTimeouts = formatStageTimeouts(timeouts)
+}
+
+// showStageTimeouts 为每个可单独限时的阶段编辑超时，留空表示不限时（流媒体与邮件检测默认 5m 与 3m），
+// 并编辑测速与流媒体解锁失败后的重试次数与等待时间
+func (ui *TestUI) showStageTimeouts() {
+	timeouts, _ := parseStageTimeouts(ui.stageTimeouts)
+	entries := make(map[string]*widget.Entry, len(stageTimeoutKeys))
+	var items []*widget.FormItem
+	for _, key := range stageTimeoutKeys {
+		entry := widget.NewEntry()
+		entry.SetPlaceHolder(ui.tr("stage_timeout.none"))
+		if limit := defaultStageTimeouts[key]; limit > 0 {
+			entry.SetPlaceHolder(limit.String())
+		}
+		if limit := timeouts[key]; limit > 0 {
+			entry.SetText(limit.String())
+		}
+		entry.Validator = func(text string) error {
+			if strings.TrimSpace(text) == "" {
+				return nil
+			}
+			_, err := parseStageTimeouts(strings.TrimPrefix(key, "progress.") + "=" + text)
+			return err
+		}
+		entries[key] = entry
+		items = append(items, widget.NewFormItem(ui.tr(key), entry))
+	}
+	retries := widget.NewEntry()
+	retries.SetPlaceHolder("0")
+	retries.SetText(ui.stageRetries)
+	retries.Validator = func(text string) error {
+		if n, err := strconv.Atoi(strings.TrimSpace(text)); strings.TrimSpace(text) != "" && (err != nil || n < 0 || n > maxStageRetries) {
+			return fmt.Errorf("retries must be between 0 and %d", maxStageRetries)
+		}
+		return nil
+	}
+	backoff := widget.NewEntry()
+	backoff.SetPlaceHolder(defaultRetryBackoff.String())
+	backoff.SetText(ui.retryBackoff)
+	backoff.Validator = func(text string) error {
+		if d, err := time.ParseDuration(strings.TrimSpace(text)); strings.TrimSpace(text) != "" && (err != nil || d <= 0) {
+			return fmt.Errorf("invalid backoff %q", text)
+		}
+		return nil
+	}
+	items = append(items,
+		widget.NewFormItem(ui.tr("stage_retry.count"), retries),
+		widget.NewFormItem(ui.tr("stage_retry.backoff"), backoff),
+	)
+	hint := widget.NewLabel(ui.tr("stage_timeout.hint"))
+	hint.Wrapping = fyne.TextWrapWord
+	items = append(items, widget.NewFormItem("", hint))
+	form := dialog.NewForm(ui.tr("stage_timeout.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		var fields []string
+		for _, key := range stageTimeoutKeys {
+			if text := strings.TrimSpace(entries[key].Text); text != "" {
+				fields = append(fields, strings.TrimPrefix(key, "progress.")+"="+text)
+			}
+		}
+		ui.setStageTimeouts(strings.Join(fields, ","))
+		ui.stageRetries, ui.retryBackoff = strings.TrimSpace(retries.Text), strings.TrimSpace(backoff.Text)
+	}, ui.Window)
+	form.Resize(fyne.NewSize(480, 0))
+	form.Show()
+}
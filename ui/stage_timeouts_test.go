@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStageTimeouts(t *testing.T) {
+	got, err := parseStageTimeouts(" NT3=5m,\nbacktrace = 90s ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got["progress.nt3"] != 5*time.Minute || got["progress.backtrace"] != 90*time.Second || len(got) != 2 {
+		t.Fatalf("timeouts = %v", got)
+	}
+	if text := formatStageTimeouts(got); text != "backtrace=1m30s, nt3=5m0s" {
+		t.Fatalf("formatStageTimeouts() = %q", text)
+	}
+	for _, text := range []string{"nt3", "basic=1m", "nt3=soon", "nt3=0s"} {
+		if _, err := parseStageTimeouts(text); err == nil {
+			t.Fatalf("parseStageTimeouts(%q) should fail", text)
+		}
+	}
+
+	config := ExecutionConfig{StageTimeouts: got}
+	if config.stageTimeout("progress.nt3") != 5*time.Minute || config.stageTimeout("progress.unlock") != 5*time.Minute || config.stageTimeout("progress.cpu") != 0 {
+		t.Fatal("stageTimeout should fall back to the defaults")
+	}
+}
+
+func TestRunStageWithTimeoutWaitsForTimedOutStage(t *testing.T) {
+	release := make(chan struct{})
+	finished := false
+	err := runStageWithTimeout(context.Background(), 20*time.Millisecond, func(context.Context) {
+		<-release
+		finished = true
+	}, func() { close(release) })
+	if !errors.Is(err, errStageTimeout) || !finished {
+		t.Fatalf("err = %v, finished = %v; want a stage timeout after the stage returned", err, finished)
+	}
+
+	var stageCtx context.Context
+	if err := runStageWithTimeout(context.Background(), time.Minute, func(ctx context.Context) { stageCtx = ctx }, func() { t.Error("onTimeout called") }); err != nil {
+		t.Fatal(err)
+	}
+	if stageCtx.Err() == nil {
+		t.Fatal("the stage context should be released once the stage returns")
+	}
+
+	hung := make(chan struct{})
+	t.Cleanup(func() { close(hung) })
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := runStageWithTimeout(ctx, time.Minute, func(context.Context) { <-hung }, func() {}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want the run's cancellation", err)
+	}
+
+	// 超时后阶段迟迟不返回时，整个测试被取消即不再等待
+	ctx, cancel = context.WithCancel(context.Background())
+	start := time.Now()
+	if err := runStageWithTimeout(ctx, 20*time.Millisecond, func(context.Context) { <-hung }, cancel); !errors.Is(err, context.Canceled) || time.Since(start) > 5*time.Second {
+		t.Fatalf("err = %v after %s, want the run's cancellation", err, time.Since(start))
+	}
+}
+
+func TestRunTimedStageMarksSectionTimedOut(t *testing.T) {
+	config := ExecutionConfig{Language: "en", StageTimeouts: map[string]time.Duration{"progress.iperf3": 20 * time.Millisecond}}
+	var out strings.Builder
+	section, component := runTimedStage(context.Background(), config, "progress.iperf3", &out, func(ctx context.Context) (StructuredSection, StructuredComponent) {
+		<-ctx.Done()
+		return StructuredSection{Name: "iperf3", Enabled: true, Status: "canceled"}, StructuredComponent{Name: "iperf3", Status: "canceled"}
+	})
+	if section.Status != "timeout" || component.Status != "timeout" || !strings.Contains(section.Reason, "20ms") {
+		t.Fatalf("section = %+v, component = %+v", section, component)
+	}
+	if !strings.Contains(out.String(), "did not finish within 20ms") {
+		t.Fatalf("output = %q", out.String())
+	}
+}
+
+func TestTimedOutStageIsReportedAsTimeout(t *testing.T) {
+	tracker := newProgressTracker(nil, []string{"progress.cpu", "progress.nt3"})
+	tracker.finish("progress.cpu")
+	tracker.timeout("progress.nt3", 2*time.Minute)
+	config := ExecutionConfig{SelectedOptions: map[string]bool{"cpu": true, "nt3": true}}
+	report := buildGUIStructuredReport(config, true, tracker, nil, context.Background(), time.Now(), time.Now())
+	for _, section := range report.Sections {
+		switch section.Name {
+		case "routes":
+			if section.Status != "timeout" || section.Reason != "stage timed out after 2m0s" {
+				t.Fatalf("routes = %+v", section)
+			}
+		case "cpu":
+			if section.Status != "partial" {
+				t.Fatalf("cpu = %+v", section)
+			}
+		}
+	}
+}
+
+func TestStageTimeoutsFollowSettings(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.setStageTimeouts("speed=10m,nt3=2m")
+	if got := ui.collectExecutionConfig().StageTimeouts; got["progress.nt3"] != 2*time.Minute || got["progress.speed"] != 10*time.Minute {
+		t.Fatalf("StageTimeouts = %v", got)
+	}
+	if ui.formEntries()["stageTimeouts"] != "nt3=2m0s, speed=10m0s" {
+		t.Fatalf("entry = %q", ui.formEntries()["stageTimeouts"])
+	}
+	ui.setStageTimeouts("nt3=never")
+	if ui.stageTimeouts != "" {
+		t.Fatalf("invalid settings should be dropped, got %q", ui.stageTimeouts)
+	}
+}
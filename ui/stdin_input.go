@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"sync"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// promptSuffixes 是交互式提示常见的结尾，输出停在这些字符上且没有换行时视为在等待输入
+var promptSuffixes = []string{":", "?", "]", ">", "：", "？"}
+
+// looksLikePrompt 判断输出的最后一行是否像在等待输入，返回该行去掉颜色后的文本
+func looksLikePrompt(line string) (string, bool) {
+	line = strings.TrimSpace(results.StripANSI(line))
+	if line == "" {
+		return "", false
+	}
+	for _, suffix := range promptSuffixes {
+		if strings.HasSuffix(line, suffix) {
+			return line, true
+		}
+	}
+	return "", false
+}
+
+// stdinBar 是终端下方的输入栏，把一行文本发往运行中进程的标准输入，避免后端的提示让运行一直挂起。
+// 没有运行时禁用；输出停在提示上时高亮发送按钮并把提示显示为占位文字。启用与禁用随运行按钮一起
+// 在 UI 线程切换（launchRun/resetUIState 与运行标签页的 start/finish），attach/detach 只交接 run
+type stdinBar struct {
+	ui      *TestUI
+	entry   *widget.Entry
+	send    *widget.Button
+	content *fyne.Container
+
+	// 以下字段由 mu 保护
+	mu      sync.Mutex
+	run     *Run
+	pending string // 尚未换行的最后一段输出
+	prompt  string // 当前显示的提示，空表示未在等待输入
+}
+
+// newStdinBar 创建输入栏，需在 UI 线程调用
+func (ui *TestUI) newStdinBar() *stdinBar {
+	bar := &stdinBar{ui: ui}
+	bar.entry = widget.NewEntry()
+	bar.entry.SetPlaceHolder(ui.tr("stdin.placeholder"))
+	bar.entry.OnSubmitted = func(string) { bar.submit() }
+	bar.send = widget.NewButtonWithIcon(ui.tr("stdin.send"), theme.MailSendIcon(), bar.submit)
+	bar.content = container.NewBorder(nil, nil, nil, bar.send, bar.entry)
+	bar.setEnabled(false)
+	return bar
+}
+
+// setEnabled 启用或禁用输入栏，需在 UI 线程调用
+func (bar *stdinBar) setEnabled(enabled bool) {
+	if enabled {
+		bar.entry.Enable()
+		bar.send.Enable()
+		return
+	}
+	bar.entry.Disable()
+	bar.send.Disable()
+	bar.send.Importance = widget.MediumImportance
+	bar.entry.SetPlaceHolder(bar.ui.tr("stdin.placeholder"))
+	bar.send.Refresh()
+}
+
+// attach 把输入栏接到 run 上，可在任意 goroutine 调用
+func (bar *stdinBar) attach(run *Run) {
+	bar.mu.Lock()
+	bar.run, bar.pending, bar.prompt = run, "", ""
+	bar.mu.Unlock()
+}
+
+// detach 在运行结束后断开输入栏，可在任意 goroutine 调用
+func (bar *stdinBar) detach() {
+	bar.mu.Lock()
+	bar.run, bar.pending, bar.prompt = nil, "", ""
+	bar.mu.Unlock()
+}
+
+// Observe 跟踪运行输出的最后一行，停在提示上时提醒用户输入，可在任意 goroutine 调用
+func (bar *stdinBar) Observe(text string) {
+	bar.mu.Lock()
+	if bar.run == nil {
+		bar.mu.Unlock()
+		return
+	}
+	bar.pending += text
+	if i := strings.LastIndexAny(bar.pending, "\r\n"); i >= 0 {
+		bar.pending = bar.pending[i+1:]
+	}
+	prompt, waiting := looksLikePrompt(bar.pending)
+	changed := prompt != bar.prompt
+	bar.prompt = prompt
+	bar.mu.Unlock()
+	if !changed {
+		// 提示没有变化时不打扰 UI 线程，大量输出不会各自排队一次界面更新
+		return
+	}
+	bar.ui.runOnUI(func() {
+		if waiting {
+			bar.send.Importance = widget.HighImportance
+			bar.entry.SetPlaceHolder(prompt)
+		} else {
+			bar.send.Importance = widget.MediumImportance
+			bar.entry.SetPlaceHolder(bar.ui.tr("stdin.placeholder"))
+		}
+		bar.send.Refresh()
+	})
+}
+
+// submit 发送输入框中的一行并清空，需在 UI 线程调用。容器运行的伪终端会回显输入；
+// 本机运行没有终端，SSH 会话申请伪终端时关闭了回显，由这里回显
+func (bar *stdinBar) submit() {
+	bar.mu.Lock()
+	run := bar.run
+	bar.pending, bar.prompt = "", ""
+	bar.mu.Unlock()
+	if run == nil {
+		return
+	}
+	line := bar.entry.Text
+	if !run.SendInput(line) {
+		dialog.ShowInformation(bar.ui.tr("dialog.hint"), bar.ui.tr("stdin.busy"), bar.ui.Window)
+		return
+	}
+	bar.entry.SetText("")
+	bar.send.Importance = widget.MediumImportance
+	bar.entry.SetPlaceHolder(bar.ui.tr("stdin.placeholder"))
+	bar.send.Refresh()
+	if run.Config.Docker == nil {
+		run.Output(line + "\n")
+	}
+}
+
+// inputPipe 创建一个管道，input 中的文本写入管道，返回读取端与关闭管道的函数。
+// 读取端是 *os.File，exec 直接把它交给子进程，进程退出后 Wait 不必等待复制
+func inputPipe(input <-chan string) (*os.File, func()) {
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		return nil, func() {}
+	}
+	stop := make(chan struct{})
+	go remote.ForwardInput(writer, input, stop)
+	return reader, func() {
+		close(stop)
+		_ = writer.Close()
+		_ = reader.Close()
+	}
+}
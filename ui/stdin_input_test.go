@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestLooksLikePrompt(t *testing.T) {
+	for _, line := range []string{"Continue? [y/N]", "请输入选项：", "\x1b[32mPassword:\x1b[0m ", "> "} {
+		if _, ok := looksLikePrompt(line); !ok {
+			t.Fatalf("%q should look like a prompt", line)
+		}
+	}
+	for _, line := range []string{"", "  ", "CPU 测试完成", "Speedtest.net 300 Mbps"} {
+		if _, ok := looksLikePrompt(line); ok {
+			t.Fatalf("%q is not a prompt", line)
+		}
+	}
+	if prompt, _ := looksLikePrompt("\x1b[1mContinue? \x1b[0m"); prompt != "Continue?" {
+		t.Fatalf("prompt = %q, colours should be stripped", prompt)
+	}
+}
+
+func TestRunSendInputIsBounded(t *testing.T) {
+	run := newRun(nil, ExecutionConfig{}, nil, nil)
+	for i := 0; i < runInputBuffer; i++ {
+		if !run.SendInput("y") {
+			t.Fatalf("line %d should be queued", i)
+		}
+	}
+	if run.SendInput("y") {
+		t.Fatal("a full buffer should refuse input instead of blocking the UI")
+	}
+	if line := <-run.Input(); line != "y\n" {
+		t.Fatalf("line = %q, a newline should be appended", line)
+	}
+}
+
+func TestInputPipeFeedsLocalRun(t *testing.T) {
+	previous := os.Stdin
+	input := make(chan string, 1)
+	stdin, closeStdin := inputPipe(input)
+	defer closeStdin()
+	input <- "2\n"
+	line, err := bufio.NewReader(stdin).ReadString('\n')
+	if err != nil || line != "2\n" {
+		t.Fatalf("line = %q, err = %v", line, err)
+	}
+	if os.Stdin != previous {
+		t.Fatal("a local run must not replace the process-wide os.Stdin")
+	}
+}
+
+func TestStdinBarFollowsTheRun(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.createResultTab()
+	bar := ui.stdinBar
+	if !bar.entry.Disabled() || !bar.send.Disabled() {
+		t.Fatal("the input bar should be disabled without a run")
+	}
+
+	var echoed string
+	run := newRun(nil, ExecutionConfig{}, func(text string) { echoed += text }, nil)
+	// launchRun 与 resetUIState 在 UI 线程切换输入栏，attach/detach 只交接 run
+	bar.attach(run)
+	bar.setEnabled(true)
+	if bar.entry.Disabled() {
+		t.Fatal("the input bar should be enabled during a run")
+	}
+	bar.Observe("checking...\nContinue? [y/N] ")
+	if bar.entry.PlaceHolder != "Continue? [y/N]" {
+		t.Fatalf("placeholder = %q, the waiting prompt should be shown", bar.entry.PlaceHolder)
+	}
+	bar.entry.SetText("y")
+	bar.submit()
+	if line := <-run.Input(); line != "y\n" || bar.entry.Text != "" {
+		t.Fatalf("sent %q, entry %q", line, bar.entry.Text)
+	}
+	if echoed != "y\n" {
+		t.Fatalf("echo = %q, local runs have no terminal to echo the input", echoed)
+	}
+	bar.Observe("ok\n")
+	if bar.entry.PlaceHolder != ui.tr("stdin.placeholder") {
+		t.Fatal("the prompt hint should clear once output moves on")
+	}
+
+	bar.detach()
+	bar.setEnabled(false)
+	if !bar.entry.Disabled() {
+		t.Fatal("the input bar should be disabled after the run")
+	}
+}
+
+// stdinRunner 把 Run.Stdin 读到的第一行作为输出
+type stdinRunner struct{}
+
+func (stdinRunner) Run(run *Run) executionOutcome {
+	if run.Stdin() == nil {
+		return executionOutcome{Err: errors.New("no stdin")}
+	}
+	line, err := bufio.NewReader(run.Stdin()).ReadString('\n')
+	run.Output(line)
+	return executionOutcome{Err: err}
+}
+
+func TestLocalRunnerPassesInputAsStdin(t *testing.T) {
+	var output string
+	run := newRun(nil, ExecutionConfig{}, func(text string) { output += text }, nil)
+	run.SendInput("y")
+	if outcome := (localExecutionRunner{stdinRunner{}}).Run(run); outcome.Err != nil || output != "y\n" {
+		t.Fatalf("output = %q, err = %v", output, outcome.Err)
+	}
+}
@@ -36,6 +36,9 @@ func formatStructuredDetails(report StructuredRunResult, languages ...string) st
 			continue
 		}
 		value := overviewStatus(section.Status, zh)
+		if section.Retries > 0 {
+			value += " | " + fmt.Sprintf(overviewPick(zh, "重试 %d 次", "retried %d times"), section.Retries)
+		}
 		if section.Reason != "" {
 			value += " | " + section.Reason
 		}
@@ -140,7 +143,7 @@ func overviewComponentName(name string, zh bool) string {
 		"speed": {"节点测速", "Speed"}, "speed.registry": {"节点测速", "Speed"}, "nat": {"NAT类型", "NAT"}, "gostun.nat": {"NAT类型", "NAT"},
 		"deep_hardware": {"深度硬件", "Deep Hardware"}, "disktest.deep_multi": {"多目录磁盘", "Multi-Path Disk"},
 		"basics.smart_selftest": {"SMART自检", "SMART Self-Test"}, "cputest.burn": {"CPU压力", "CPU Burn"},
-		"basics.gpu_compute": {"GPU计算", "GPU Compute"},
+		"basics.gpu_compute": {"GPU计算", "GPU Compute"}, "gpu": {"GPU信息", "GPU Info"},
 	}
 	if value, ok := names[name]; ok {
 		if zh {
@@ -24,6 +24,9 @@ var sectionProgressKeys = map[string]string{
 	"tgdc":          "progress.tgdc",
 	"web":           "progress.web",
 	"speed":         "progress.speed",
+	"iperf3":        "progress.iperf3",
+	"tunnel":        "progress.tunnel",
+	"gpu":           "progress.gpu",
 	"nat":           "progress.nat",
 	"tcp":           "progress.tcp",
 	"analysis":      "progress.summary",
@@ -185,6 +188,9 @@ func buildGUIStructuredSections(config ExecutionConfig, connected bool, tracker
 		{"tgdc", "progress.tgdc", tgdcEnabled, true},
 		{"web", "progress.web", webEnabled, true},
 		{"speed", "progress.speed", selected["speed"], true},
+		{"iperf3", "progress.iperf3", len(config.IperfTargets) > 0, true},
+		{"tunnel", "progress.tunnel", config.TunnelInterface != "", true},
+		{"gpu", "progress.gpu", config.GPUInfo, false},
 	}
 	sections := make([]StructuredSection, 0, len(definitions))
 	for _, definition := range definitions {
@@ -194,6 +200,8 @@ func buildGUIStructuredSections(config ExecutionConfig, connected bool, tracker
 			section.Status, section.Reason = "skipped", "disabled"
 		case definition.network && !connected:
 			section.Status, section.Reason = "unavailable", "network unavailable"
+		case tracker != nil && tracker.timedOut[definition.progress] > 0:
+			section.Status, section.Reason = "timeout", stageTimeoutReason(tracker.timedOut[definition.progress])
 		case tracker != nil && tracker.finished[definition.progress]:
 			section.Status = "ok"
 		case runStatus != "ok":
@@ -201,6 +209,9 @@ func buildGUIStructuredSections(config ExecutionConfig, connected bool, tracker
 		default:
 			section.Status, section.Reason = "partial", "no structured section result"
 		}
+		if tracker != nil {
+			section.Retries = tracker.retries[definition.progress]
+		}
 		sections = append(sections, section)
 	}
 	return sections
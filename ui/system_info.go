@@ -0,0 +1,212 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/sysinfo"
+)
+
+// systemInfoTimeout 是 SSH 目标连接与采集的总超时
+const systemInfoTimeout = 20 * time.Second
+
+var (
+	// collectLocalInfo 与 collectRemoteInfo 采集测试目标信息，测试中替换
+	collectLocalInfo  = sysinfo.Local
+	collectRemoteInfo = func(ctx context.Context, target remote.Target) (sysinfo.Info, error) {
+		client, err := remote.Dial(ctx, target)
+		if err != nil {
+			return sysinfo.Info{}, err
+		}
+		defer client.Close()
+		script := sysinfo.Script
+		if target.IsWindows() {
+			script = remote.PowerShell(sysinfo.WindowsScript)
+		}
+		out, err := client.Output(ctx, script)
+		if err != nil {
+			return sysinfo.Info{}, err
+		}
+		return sysinfo.ParseScript(out), nil
+	}
+)
+
+// systemInfoCard 是启动页上的目标系统信息卡片
+type systemInfoCard struct {
+	card    *widget.Card
+	values  []*widget.Label
+	status  *widget.Label
+	refresh *widget.Button
+	bbr     *widget.Button
+	// seq 递增以丢弃过期的采集结果（例如采集期间切换了远程目标）
+	seq int
+	// done 在本次采集结果处理完后关闭
+	done chan struct{}
+	// info 与 target 为最近一次采集结果，重建界面时沿用
+	info   *sysinfo.Info
+	target string
+	// remote 为最近一次采集的 SSH 目标，本机时为 nil
+	remote *remote.Target
+}
+
+// systemInfoRows 返回卡片各行的标题键与取值
+func systemInfoRows(info sysinfo.Info) [][2]string {
+	cores := ""
+	if info.Cores > 0 {
+		cores = fmt.Sprint(info.Cores)
+	}
+	diskText := ""
+	if info.DiskTotal > 0 {
+		diskText = fmt.Sprintf("%s / %s", sysinfo.FormatBytes(info.DiskUsed), sysinfo.FormatBytes(info.DiskTotal))
+	}
+	osText := info.OS
+	if info.Arch != "" {
+		osText += " (" + info.Arch + ")"
+	}
+	return [][2]string{
+		{"sysinfo.host", info.Hostname},
+		{"sysinfo.cpu", info.CPUModel},
+		{"sysinfo.cores", cores},
+		{"sysinfo.memory", sysinfo.FormatBytes(info.Memory)},
+		{"sysinfo.disk", diskText},
+		{"sysinfo.virt", info.Virtualization},
+		{"sysinfo.os", osText},
+		{"sysinfo.kernel", info.Kernel},
+		{"sysinfo.tcp_cc", info.TCPCongestion},
+		{"sysinfo.qdisc", info.Qdisc},
+	}
+}
+
+// createSystemInfoCard 创建目标系统信息卡片；本机信息在程序启动后采集，
+// 远程目标需手动刷新，避免启动时就发起 SSH 连接
+func (ui *TestUI) createSystemInfoCard() fyne.CanvasObject {
+	card := &systemInfoCard{status: widget.NewLabel("")}
+	if previous := ui.systemInfo; previous != nil {
+		card.info, card.target, card.remote = previous.info, previous.target, previous.remote
+	}
+	card.status.Wrapping = fyne.TextWrapWord
+	grid := container.New(layout.NewFormLayout())
+	for _, row := range systemInfoRows(sysinfo.Info{}) {
+		value := widget.NewLabel("-")
+		value.Truncation = fyne.TextTruncateEllipsis
+		card.values = append(card.values, value)
+		grid.Add(widget.NewLabelWithStyle(ui.tr(row[0]), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+		grid.Add(value)
+	}
+	card.refresh = widget.NewButtonWithIcon(ui.tr("sysinfo.refresh"), theme.ViewRefreshIcon(), ui.refreshSystemInfo)
+	card.bbr = widget.NewButtonWithIcon(ui.tr("bbr.enable"), theme.SettingsIcon(), ui.confirmEnableBBR)
+	card.card = widget.NewCard(ui.tr("sysinfo.title"), "", container.NewVBox(
+		grid,
+		container.NewBorder(nil, nil, nil, container.NewHBox(card.bbr, card.refresh), card.status),
+	))
+	card.updateBBRButton()
+	ui.systemInfo = card
+	if card.info != nil {
+		card.show(*card.info)
+		card.card.SetSubTitle(card.target)
+	}
+	return card.card
+}
+
+// show 把采集结果填入卡片
+func (card *systemInfoCard) show(info sysinfo.Info) {
+	for i, row := range systemInfoRows(info) {
+		value := row[1]
+		if value == "" {
+			value = "-"
+		}
+		card.values[i].SetText(value)
+	}
+}
+
+// updateBBRButton 只在 SSH 目标上显示启用 BBR 的按钮，目标支持 BBR 且尚未同时启用 bbr 与 fq 时可用
+func (card *systemInfoCard) updateBBRButton() {
+	if card.remote == nil || card.remote.IsWindows() {
+		card.bbr.Hide()
+		return
+	}
+	card.bbr.Show()
+	if info := card.info; info != nil && info.BBRSupported() && !(info.BBREnabled() && info.Qdisc == "fq") {
+		card.bbr.Enable()
+	} else {
+		card.bbr.Disable()
+	}
+}
+
+// systemInfoTargetChanged 在切换本机/远程测试时调用：本机立即采集，远程清空卡片等待手动刷新
+func (ui *TestUI) systemInfoTargetChanged() {
+	card := ui.systemInfo
+	if card == nil {
+		return
+	}
+	if !ui.remoteEnabled() {
+		ui.refreshSystemInfo()
+		return
+	}
+	card.seq++
+	card.info, card.remote = nil, nil
+	card.updateBBRButton()
+	card.show(sysinfo.Info{})
+	card.card.SetSubTitle(ui.tr("sysinfo.remote_pending"))
+	card.status.SetText("")
+	card.refresh.Enable()
+}
+
+// refreshSystemInfo 在后台采集本机或 SSH 目标的信息并刷新卡片，需在界面线程调用
+func (ui *TestUI) refreshSystemInfo() {
+	card := ui.systemInfo
+	if card == nil {
+		return
+	}
+	target, err := ui.remoteTarget()
+	if err != nil {
+		card.status.SetText(ui.tr("dialog.remote_invalid") + " " + err.Error())
+		return
+	}
+	card.seq++
+	seq := card.seq
+	done := make(chan struct{})
+	card.done = done
+	subtitle := ui.tr("sysinfo.local")
+	if target != nil {
+		subtitle = fmt.Sprintf("%s@%s", target.User, target.Address())
+	}
+	card.card.SetSubTitle(subtitle)
+	card.status.SetText(ui.tr("sysinfo.collecting"))
+	card.refresh.Disable()
+	card.bbr.Disable()
+
+	go func() {
+		var info sysinfo.Info
+		var err error
+		if target == nil {
+			info = collectLocalInfo(context.Background())
+		} else {
+			ctx, cancel := context.WithTimeout(context.Background(), systemInfoTimeout)
+			info, err = collectRemoteInfo(ctx, *target)
+			cancel()
+		}
+		ui.runOnUI(func() {
+			defer close(done)
+			if seq != card.seq {
+				return
+			}
+			card.refresh.Enable()
+			if err != nil {
+				card.status.SetText(ui.tr("sysinfo.failed") + " " + err.Error())
+				return
+			}
+			card.info, card.target, card.remote = &info, subtitle, target
+			card.updateBBRButton()
+			card.show(info)
+			card.status.SetText(fmt.Sprintf(ui.tr("sysinfo.updated"), time.Now().Format("15:04:05")))
+		})
+	}()
+}
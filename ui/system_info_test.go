@@ -0,0 +1,75 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/sysinfo"
+)
+
+func waitSystemInfo(t *testing.T, card *systemInfoCard) {
+	t.Helper()
+	select {
+	case <-card.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("system info collection did not finish")
+	}
+}
+
+func TestSystemInfoCardCollectsLocalAndRemote(t *testing.T) {
+	oldLocal, oldRemote := collectLocalInfo, collectRemoteInfo
+	t.Cleanup(func() { collectLocalInfo, collectRemoteInfo = oldLocal, oldRemote })
+	collectLocalInfo = func(context.Context) sysinfo.Info {
+		return sysinfo.Info{Hostname: "desk", Cores: 8, Memory: 16 << 30, Virtualization: "none", TCPCongestion: "cubic"}
+	}
+	var dialed string
+	remoteErr := error(nil)
+	collectRemoteInfo = func(ctx context.Context, target remote.Target) (sysinfo.Info, error) {
+		dialed = target.Address()
+		return sysinfo.Info{Hostname: "vps-1", Virtualization: "kvm", DiskTotal: 40 << 30, DiskUsed: 10 << 30}, remoteErr
+	}
+
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	card := ui.systemInfo
+	if card.values[0].Text != "-" {
+		t.Fatal("local info should be collected after the app starts, not while building the UI")
+	}
+	ui.refreshSystemInfo()
+	waitSystemInfo(t, card)
+	if card.values[0].Text != "desk" || card.values[3].Text != "16.00 GiB" || card.values[8].Text != "cubic" || card.values[1].Text != "-" {
+		t.Fatalf("local values = %q %q %q %q", card.values[0].Text, card.values[3].Text, card.values[8].Text, card.values[1].Text)
+	}
+
+	// 切换到远程后不自动连接，清空旧的本机信息
+	ui.RemoteEnableCheck.SetChecked(true)
+	if card.values[0].Text != "-" || dialed != "" {
+		t.Fatalf("remote pending: host %q, dialed %q", card.values[0].Text, dialed)
+	}
+	ui.RemoteHostEntry.SetText("203.0.113.10")
+	ui.RemotePasswordEntry.SetText("secret")
+	card.refresh.OnTapped()
+	waitSystemInfo(t, card)
+	if dialed != "203.0.113.10:22" || card.values[0].Text != "vps-1" || card.values[4].Text != "10.00 GiB / 40.00 GiB" || card.card.Subtitle != "root@203.0.113.10:22" {
+		t.Fatalf("remote: dialed %q, host %q, disk %q, subtitle %q", dialed, card.values[0].Text, card.values[4].Text, card.card.Subtitle)
+	}
+
+	// 重建界面（如切换语言）时沿用上次结果
+	ui.createSystemInfoCard()
+	if rebuilt := ui.systemInfo; rebuilt.values[0].Text != "vps-1" || rebuilt.card.Subtitle != "root@203.0.113.10:22" {
+		t.Fatalf("rebuilt card: host %q, subtitle %q", rebuilt.values[0].Text, rebuilt.card.Subtitle)
+	}
+	card = ui.systemInfo
+
+	remoteErr = errors.New("auth failed")
+	card.refresh.OnTapped()
+	waitSystemInfo(t, card)
+	if !strings.Contains(card.status.Text, "auth failed") || card.refresh.Disabled() {
+		t.Fatalf("status = %q, refresh disabled %v", card.status.Text, card.refresh.Disabled())
+	}
+}
@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// terminalBookmark 是终端中一行的书签；text 在添加时保存，该行被移出内存后仍可导出
+type terminalBookmark struct {
+	text string
+	note string
+}
+
+// bookmarkMenuLimit 是书签菜单中直接列出的书签数
+const bookmarkMenuLimit = 20
+
+// bookmarkAt 返回快照中第 line 行的书签
+func (t *TerminalOutput) bookmarkAt(line int) (terminalBookmark, bool) {
+	mark, ok := t.bookmarks[t.lineBase+line]
+	return mark, ok
+}
+
+// ToggleBookmark 为第 line 行添加或移除书签（需在 UI 线程调用）
+func (t *TerminalOutput) ToggleBookmark(line int) {
+	if _, ok := t.bookmarkAt(line); ok {
+		delete(t.bookmarks, t.lineBase+line)
+	} else {
+		t.SetBookmarkNote(line, "")
+		return
+	}
+	t.generation++
+	t.body.Refresh()
+}
+
+// SetBookmarkNote 为第 line 行添加书签并设置批注，已有书签时只更新批注（需在 UI 线程调用）
+func (t *TerminalOutput) SetBookmarkNote(line int, note string) {
+	if t.bookmarks == nil {
+		t.bookmarks = map[int]terminalBookmark{}
+	}
+	t.bookmarks[t.lineBase+line] = terminalBookmark{text: t.line(line).plain, note: strings.TrimSpace(note)}
+	t.generation++
+	t.body.Refresh()
+}
+
+// bookmarkIDs 返回按行号排序的书签
+func (t *TerminalOutput) bookmarkIDs() []int {
+	ids := make([]int, 0, len(t.bookmarks))
+	for id := range t.bookmarks {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// JumpToBookmark 滚动到下一个（forward 为 false 时为上一个）书签并选中该行，到达末尾后从头开始；
+// 书签所在阶段被折叠时先展开，已移出内存或被过滤隐藏的书签会被跳过（需在 UI 线程调用）
+func (t *TerminalOutput) JumpToBookmark(forward bool) bool {
+	ids := t.bookmarkIDs()
+	if !forward {
+		slices.Reverse(ids)
+	}
+	start := 0
+	for i, id := range ids {
+		if (forward && id > t.bookmarkCursor) || (!forward && id < t.bookmarkCursor) {
+			start = i
+			break
+		}
+	}
+	for i := range ids {
+		id := ids[(start+i)%len(ids)]
+		if t.revealLine(id - t.lineBase) {
+			t.bookmarkCursor = id
+			return true
+		}
+	}
+	return false
+}
+
+// revealLine 展开包含第 line 行的已折叠阶段，滚动到该行并选中，该行不在显示内容中时返回 false
+func (t *TerminalOutput) revealLine(line int) bool {
+	if line < 0 || line >= t.lineCount() {
+		return false
+	}
+	for i := len(t.headers) - 1; i >= 0; i-- {
+		if header := t.headers[i]; header < line {
+			if t.collapsedLine(header) {
+				t.setCollapsed(header, false)
+				t.sync()
+			}
+			break
+		}
+	}
+	row, ok := t.segmentRow(line, 0)
+	if !ok {
+		return false
+	}
+	t.ScrollToRow(row)
+	t.setSelection(terminalSelection{
+		Anchor: terminalPos{Row: row},
+		Cursor: terminalPos{Row: row, Col: len(t.row(row).plain)},
+		Active: true,
+	})
+	return true
+}
+
+// Annotations 返回按行号排序的书签，用于导出报告（需在 UI 线程调用）
+func (t *TerminalOutput) Annotations() []results.Annotation {
+	var annotations []results.Annotation
+	for _, id := range t.bookmarkIDs() {
+		mark := t.bookmarks[id]
+		annotations = append(annotations, results.Annotation{Line: id + 1, Text: mark.text, Note: mark.note})
+	}
+	return annotations
+}
+
+// resetBookmarks 在内容被清空或整体替换时清除书签
+func (t *TerminalOutput) resetBookmarks() {
+	t.bookmarks = nil
+	t.bookmarkCursor = -1
+}
+
+// markAt 返回内容层坐标处书签列所在的行
+func (t *TerminalOutput) markAt(pos fyne.Position) (int, bool) {
+	if !t.Bookmarkable || t.rowCount() == 0 {
+		return 0, false
+	}
+	m := t.body.metrics()
+	row := int((pos.Y - m.pad) / m.row)
+	if pos.Y < m.pad || row >= t.rowCount() || pos.X < m.pad || pos.X >= m.pad+m.mark {
+		return 0, false
+	}
+	return t.rowLine(row), true
+}
+
+// bookmarkMenuItems 返回右键菜单中第 row 个显示行的书签操作
+func (t *TerminalOutput) bookmarkMenuItems(row int) []*fyne.MenuItem {
+	if row < 0 || row >= t.rowCount() {
+		return nil
+	}
+	line := t.rowLine(row)
+	mark, marked := t.bookmarkAt(line)
+	toggle := fyne.NewMenuItem(t.tr("bookmark.add"), func() { t.ToggleBookmark(line) })
+	if marked {
+		toggle.Label = t.tr("bookmark.remove")
+	}
+	note := fyne.NewMenuItem(t.tr("bookmark.edit_note"), func() { t.editBookmarkNote(line, mark.note) })
+	return []*fyne.MenuItem{toggle, note}
+}
+
+// editBookmarkNote 弹出输入框编辑第 line 行的批注，保存后该行自动加入书签
+func (t *TerminalOutput) editBookmarkNote(line int, note string) {
+	c := fyne.CurrentApp().Driver().CanvasForObject(t.body)
+	var parent fyne.Window
+	for _, w := range fyne.CurrentApp().Driver().AllWindows() {
+		if w.Canvas() == c {
+			parent = w
+		}
+	}
+	if parent == nil {
+		return
+	}
+	entry := widget.NewEntry()
+	entry.SetText(note)
+	entry.SetPlaceHolder(t.tr("bookmark.note_placeholder"))
+	text := widget.NewLabel(t.line(line).plain)
+	text.Truncation = fyne.TextTruncateEllipsis
+	dlg := dialog.NewForm(t.tr("bookmark.edit_note"), t.tr("hosts.save"), t.tr("compare.cancel"),
+		[]*widget.FormItem{
+			widget.NewFormItem(fmt.Sprintf(t.tr("bookmark.line"), t.lineBase+line+1), text),
+			widget.NewFormItem(t.tr("bookmark.note"), entry),
+		},
+		func(ok bool) {
+			if ok {
+				t.SetBookmarkNote(line, entry.Text)
+			}
+		}, parent)
+	dlg.Resize(fyne.NewSize(480, dlg.MinSize().Height))
+	dlg.Show()
+	parent.Canvas().Focus(entry)
+}
+
+// bookmarkNavigationItems 返回跳转书签的菜单项，列出前 bookmarkMenuLimit 个书签
+func (t *TerminalOutput) bookmarkNavigationItems() []*fyne.MenuItem {
+	prev := fyne.NewMenuItem(t.tr("bookmark.prev"), func() { t.JumpToBookmark(false) })
+	next := fyne.NewMenuItem(t.tr("bookmark.next"), func() { t.JumpToBookmark(true) })
+	clearAll := fyne.NewMenuItem(t.tr("bookmark.clear"), func() {
+		t.resetBookmarks()
+		t.generation++
+		t.body.Refresh()
+	})
+	if len(t.bookmarks) == 0 {
+		prev.Disabled, next.Disabled, clearAll.Disabled = true, true, true
+	}
+	items := []*fyne.MenuItem{prev, next}
+	ids := t.bookmarkIDs()
+	if len(ids) > 0 {
+		items = append(items, fyne.NewMenuItemSeparator())
+	}
+	for _, id := range ids[:min(len(ids), bookmarkMenuLimit)] {
+		mark := t.bookmarks[id]
+		label := mark.note
+		if label == "" {
+			label = strings.TrimSpace(mark.text)
+		}
+		items = append(items, fyne.NewMenuItem(fmt.Sprintf(t.tr("bookmark.line"), id+1)+"  "+truncateRunes(label, 40), func() {
+			if t.revealLine(id - t.lineBase) {
+				t.bookmarkCursor = id
+			}
+		}))
+	}
+	return append(items, fyne.NewMenuItemSeparator(), clearAll)
+}
+
+// truncateRunes 把 s 截断为最多 n 个字符，超出部分以省略号表示
+func truncateRunes(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// createTerminalBookmarkButton 创建终端工具栏中浏览书签的按钮
+func (ui *TestUI) createTerminalBookmarkButton() *widget.Button {
+	button := widget.NewButtonWithIcon("", theme.RadioButtonFillIcon(), nil)
+	button.OnTapped = func() {
+		menu := fyne.NewMenu("", ui.Terminal.bookmarkNavigationItems()...)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(button)
+		if canvas == nil {
+			return
+		}
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
+	}
+	return button
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestTerminalBookmarksNavigateAndExport(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.Foldable = true
+	terminal.Bookmarkable = true
+	feedTerminal(terminal, foldSample)
+	terminal.sync()
+
+	terminal.ToggleBookmark(2)
+	terminal.SetBookmarkNote(6, "this node looks throttled")
+	terminal.ToggleFold(4)
+	if !terminal.JumpToBookmark(true) || terminal.SelectedText() != "1 线程测试(单核)得分: 1000" {
+		t.Fatalf("first jump should select line 3, got %q", terminal.SelectedText())
+	}
+	if !terminal.JumpToBookmark(true) || terminal.SelectedText() != "上海电信 100Mbps" {
+		t.Fatalf("second jump should select line 7, got %q", terminal.SelectedText())
+	}
+	if terminal.collapsedLine(4) {
+		t.Fatal("jumping into a folded stage should expand it")
+	}
+	if !terminal.JumpToBookmark(true) || terminal.bookmarkCursor != 2 {
+		t.Fatal("navigation should wrap around to the first bookmark")
+	}
+
+	// 行被移出内存后书签仍可导出
+	terminal.mu.Lock()
+	terminal.lines = terminal.lines[3:]
+	terminal.dropped = 3
+	terminal.mu.Unlock()
+	terminal.sync()
+	annotations := terminal.Annotations()
+	if len(annotations) != 2 || annotations[0].Line != 3 || annotations[1].Line != 7 ||
+		annotations[1].Text != "上海电信 100Mbps" || annotations[1].Note != "this node looks throttled" {
+		t.Fatalf("Annotations() = %+v", annotations)
+	}
+	if !terminal.JumpToBookmark(false) || terminal.bookmarkCursor != 6 {
+		t.Fatal("lines that left memory should be skipped when navigating")
+	}
+
+	terminal.ToggleBookmark(3)
+	if _, ok := terminal.bookmarkAt(3); ok || len(terminal.Annotations()) != 1 {
+		t.Fatal("toggling a bookmarked line should remove it")
+	}
+	terminal.Clear()
+	if len(terminal.Annotations()) != 0 {
+		t.Fatal("clearing the terminal should drop its bookmarks")
+	}
+}
+
+func TestExportSourceIncludesAnnotations(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText(foldSample)
+	ui.Terminal.SetBookmarkNote(2, "single core is slow")
+	source := ui.currentExportSource()
+	if len(source.report.Annotations) != 1 || source.report.Annotations[0].Note != "single core is slow" {
+		t.Fatalf("export source annotations = %+v", source.report.Annotations)
+	}
+	ui.Mu.Lock()
+	parsed := ui.ParsedResults
+	ui.Mu.Unlock()
+	if parsed != nil && len(parsed.Annotations) != 0 {
+		t.Fatal("merging annotations must not modify the parsed results")
+	}
+}
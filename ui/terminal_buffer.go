@@ -0,0 +1,235 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	terminalBufferPreferenceKey = "terminal_buffer"
+	// terminalBufferSpill 表示不限制日志长度：超出内存上限的旧行写入临时文件
+	terminalBufferSpill = "spill"
+	// terminalSpillMemoryMB 是转存模式下内存中保留的字节上限（MB）
+	terminalSpillMemoryMB = 16
+
+	terminalBackpressurePreferenceKey = "terminal_backpressure"
+	// terminalBackpressureCoalesce 表示输出过快时跳过较早的待刷新文本，写入方不会被阻塞
+	terminalBackpressureCoalesce = "coalesce"
+	// terminalBackpressureBlock 表示输出过快时让写入方等待界面刷新，不丢失任何输出
+	terminalBackpressureBlock = "block"
+)
+
+// terminalBackpressureOptions 是输出过快时的可选处理方式
+var terminalBackpressureOptions = []string{terminalBackpressureCoalesce, terminalBackpressureBlock}
+
+// terminalBufferOptions 是可选的内存上限（MB）
+var terminalBufferOptions = []string{"8", "16", "32", "64", "128", terminalBufferSpill}
+
+func defaultTerminalBuffer() string {
+	if isMobilePlatform() {
+		return "8"
+	}
+	return "32"
+}
+
+// parseTerminalBuffer 把设置值转换为内存上限与是否转存到磁盘
+func parseTerminalBuffer(value string) (int, bool) {
+	if value == terminalBufferSpill {
+		return terminalSpillMemoryMB * 1024 * 1024, true
+	}
+	mb, err := strconv.Atoi(value)
+	if err != nil || mb <= 0 {
+		mb, _ = strconv.Atoi(defaultTerminalBuffer())
+	}
+	return mb * 1024 * 1024, false
+}
+
+func (ui *TestUI) terminalBufferSetting() string {
+	if ui.App == nil {
+		return defaultTerminalBuffer()
+	}
+	return ui.App.Preferences().StringWithFallback(terminalBufferPreferenceKey, defaultTerminalBuffer())
+}
+
+// applyTerminalBuffer 保存缓冲设置并应用到当前终端
+func (ui *TestUI) applyTerminalBuffer(value string) {
+	if ui.App != nil {
+		ui.App.Preferences().SetString(terminalBufferPreferenceKey, value)
+	}
+	if ui.Terminal != nil {
+		ui.Terminal.SetBufferLimit(parseTerminalBuffer(value))
+	}
+}
+
+func (ui *TestUI) terminalBufferLabel(value string) string {
+	if value == terminalBufferSpill {
+		return ui.tr("terminal_buffer.spill")
+	}
+	return value + " MB"
+}
+
+// createTerminalBufferSelect 创建终端缓冲大小选项
+func (ui *TestUI) createTerminalBufferSelect() *widget.Select {
+	labels := make([]string, len(terminalBufferOptions))
+	for i, value := range terminalBufferOptions {
+		labels[i] = ui.terminalBufferLabel(value)
+	}
+	selectWidget := widget.NewSelect(labels, nil)
+	selectWidget.SetSelected(ui.terminalBufferLabel(ui.terminalBufferSetting()))
+	selectWidget.OnChanged = func(label string) {
+		for i, candidate := range labels {
+			if candidate == label {
+				ui.applyTerminalBuffer(terminalBufferOptions[i])
+			}
+		}
+	}
+	return selectWidget
+}
+
+func (ui *TestUI) terminalBackpressureSetting() string {
+	if ui.App == nil {
+		return terminalBackpressureCoalesce
+	}
+	return ui.App.Preferences().StringWithFallback(terminalBackpressurePreferenceKey, terminalBackpressureCoalesce)
+}
+
+// applyTerminalBackpressure 保存输出过快时的处理方式并应用到当前终端
+func (ui *TestUI) applyTerminalBackpressure(value string) {
+	if ui.App != nil {
+		ui.App.Preferences().SetString(terminalBackpressurePreferenceKey, value)
+	}
+	if ui.Terminal != nil {
+		ui.Terminal.SetBackpressure(value == terminalBackpressureBlock)
+	}
+}
+
+// createTerminalBackpressureSelect 创建输出过快时处理方式的选项，显示在溢出提示旁
+func (ui *TestUI) createTerminalBackpressureSelect() *widget.Select {
+	labels := make([]string, len(terminalBackpressureOptions))
+	for i, value := range terminalBackpressureOptions {
+		labels[i] = ui.tr("terminal_backpressure." + value)
+	}
+	selectWidget := widget.NewSelect(labels, nil)
+	selectWidget.SetSelected(ui.tr("terminal_backpressure." + ui.terminalBackpressureSetting()))
+	selectWidget.OnChanged = func(label string) {
+		for i, candidate := range labels {
+			if candidate == label {
+				ui.applyTerminalBackpressure(terminalBackpressureOptions[i])
+			}
+		}
+	}
+	return selectWidget
+}
+
+// createTerminalOverflowLabel 创建终端上方的溢出提示，日志不完整、部分转存或输出过快时显示；
+// 输出过快时旁边显示处理方式的选项
+func (ui *TestUI) createTerminalOverflowLabel() fyne.CanvasObject {
+	label := widget.NewLabel("")
+	label.Importance = widget.WarningImportance
+	mode := ui.createTerminalBackpressureSelect()
+	row := container.NewBorder(nil, nil, nil, mode, label)
+	row.Hide()
+	var dropped, spilled, skipped, waited int
+	update := func() {
+		var notes []string
+		switch {
+		case dropped > 0:
+			notes = append(notes, fmt.Sprintf(ui.tr("terminal.truncated"), dropped))
+		case spilled > 0:
+			notes = append(notes, fmt.Sprintf(ui.tr("terminal.spilled"), spilled))
+		}
+		if skipped > 0 {
+			notes = append(notes, fmt.Sprintf(ui.tr("terminal.skipped"), (skipped+1023)/1024))
+		}
+		if waited > 0 {
+			notes = append(notes, fmt.Sprintf(ui.tr("terminal.waited"), waited))
+		}
+		if skipped > 0 || waited > 0 {
+			mode.Show()
+		} else {
+			mode.Hide()
+		}
+		if len(notes) == 0 {
+			row.Hide()
+			return
+		}
+		label.SetText(strings.Join(notes, " · "))
+		row.Show()
+	}
+	ui.Terminal.OnOverflow = func(d, s int) {
+		dropped, spilled = d, s
+		update()
+	}
+	ui.Terminal.OnBackpressure = func(s, w int) {
+		skipped, waited = s, w
+		update()
+	}
+	return row
+}
+
+// SetBufferLimit 设置内存中保留的字节上限；spill 为 true 时超出部分写入临时文件而不是丢弃
+func (t *TerminalOutput) SetBufferLimit(maxBytes int, spill bool) {
+	t.mu.Lock()
+	t.maxBytes = maxBytes
+	t.spill = spill
+	t.trimLocked()
+	t.mu.Unlock()
+
+	uiDo(t.sync)
+}
+
+// SetBackpressure 设置待刷新文本已满时的处理方式：block 为 true 时 AppendText 等待下次刷新，
+// 否则跳过较早的待刷新文本并计入 OnSkipped
+func (t *TerminalOutput) SetBackpressure(block bool) {
+	t.mu.Lock()
+	t.block = block
+	t.drained.Broadcast()
+	t.mu.Unlock()
+}
+
+// spillLocked 把被挤出内存的行追加到临时文件，失败时返回 false，调用方按丢弃处理
+func (t *TerminalOutput) spillLocked(lines []terminalLine) bool {
+	if t.spillFile == nil {
+		file, err := os.CreateTemp("", "ecs-gui-terminal-*.log")
+		if err != nil {
+			return false
+		}
+		t.spillFile = file
+	}
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line.raw)
+		b.WriteByte('\n')
+	}
+	_, err := t.spillFile.WriteString(b.String())
+	return err == nil
+}
+
+// readSpillLocked 读取已转存的内容，读取失败时返回空
+func (t *TerminalOutput) readSpillLocked() string {
+	if t.spillFile == nil {
+		return ""
+	}
+	data, err := os.ReadFile(t.spillFile.Name())
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+func (t *TerminalOutput) closeSpillLocked() {
+	if t.spillFile == nil {
+		return
+	}
+	name := t.spillFile.Name()
+	_ = t.spillFile.Close()
+	_ = os.Remove(name)
+	t.spillFile = nil
+	t.spilled = 0
+}
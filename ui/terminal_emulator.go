@@ -0,0 +1,322 @@
+package ui
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// terminalCursorCommands 是终端模拟处理的光标控制序列（CSI 结尾字母）：
+// 上下移动、左右移动、到行首、到指定列与清除行
+const terminalCursorCommands = "ABCDEFGK"
+
+// terminalEmuState 是跨 AppendText 保留的光标状态
+type terminalEmuState struct {
+	up      int    // 光标所在行距末行的行数，0 表示在末行
+	col     int    // 光标所在列，仅在 placed 时有效
+	placed  bool   // false 表示光标位于末行末尾，即普通的追加模式
+	partial string // 被截断在块尾的不完整转义序列
+}
+
+// idle 报告光标是否处于追加模式，此时不含控制字符的文本可以直接按行追加
+func (s terminalEmuState) idle() bool {
+	return s.up == 0 && !s.placed && s.partial == ""
+}
+
+// needsTerminalEmulation 报告文本是否包含需要原地改写的控制字符：
+// 单独的 \r、退格或光标控制序列；块尾不完整的 CSI 序列也交给模拟层处理
+func needsTerminalEmulation(text string) bool {
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '\b':
+			return true
+		case '\r':
+			if i+1 >= len(text) || text[i+1] != '\n' {
+				return true
+			}
+		case 0x1b:
+			if i+1 >= len(text) {
+				return true
+			}
+			if text[i+1] != '[' {
+				continue
+			}
+			end := i + 2
+			for end < len(text) && !isCSIFinalByte(text[end]) {
+				end++
+			}
+			if end >= len(text) || strings.IndexByte(terminalCursorCommands, text[end]) >= 0 {
+				return true
+			}
+			i = end
+		}
+	}
+	return false
+}
+
+// terminalCell 是模拟层中的一个字符及其样式
+type terminalCell struct {
+	r     rune
+	style ansiStyle
+}
+
+// terminalScreen 在缓冲区末尾的若干行上执行一次模拟：被改写的行先展开为字符格，
+// 处理完后重新编码为带 SGR 序列的原始文本写回
+type terminalScreen struct {
+	t      *TerminalOutput
+	cells  map[int][]terminalCell // 已展开的行，键为行号，len(t.lines) 为末行
+	row    int
+	col    int
+	pen    ansiStyle // 当前写入样式
+	at     time.Time // 本段文本的写入时间
+	openAt time.Time // 末行的写入时间
+	cloned bool      // t.lines 是否已复制，快照与其共用底层数组，修改前必须复制
+}
+
+// emulateLocked 以终端语义处理文本：\r 回到行首，\b 左移一列，
+// ESC[K 清除行，ESC[nA/B/C/D/E/F/G 移动光标，进度条与转圈提示因此原地更新
+func (t *TerminalOutput) emulateLocked(text string, at time.Time) {
+	text = t.emu.partial + text
+	t.emu.partial = ""
+
+	p := t.openParser
+	p.Parse(t.open.raw)
+	s := &terminalScreen{t: t, cells: make(map[int][]terminalCell), row: len(t.lines) - t.emu.up, pen: p.style, at: at, openAt: t.openAt(at)}
+	if t.emu.placed {
+		s.col = t.emu.col
+	} else {
+		s.col = len(s.line(s.row))
+	}
+
+	for i := 0; i < len(text); {
+		switch c := text[i]; c {
+		case 0x1b:
+			if i+1 >= len(text) {
+				t.emu.partial = text[i:]
+				i = len(text)
+				continue
+			}
+			if text[i+1] != '[' {
+				// 非 CSI 的转义序列（如 ESC c），与解析器一致直接跳过
+				i += 2
+				continue
+			}
+			end := i + 2
+			for end < len(text) && !isCSIFinalByte(text[end]) {
+				end++
+			}
+			if end >= len(text) {
+				t.emu.partial = text[i:]
+				i = len(text)
+				continue
+			}
+			s.control(text[end], text[i+2:end])
+			i = end + 1
+		case '\n':
+			s.newline()
+			i++
+		case '\r':
+			s.col = 0
+			i++
+		case '\b':
+			s.col = max(s.col-1, 0)
+			i++
+		default:
+			r, size := utf8.DecodeRuneInString(text[i:])
+			s.write(r)
+			i += size
+		}
+	}
+	s.flush()
+}
+
+// line 返回第 row 行展开后的字符格，首次访问时从原始文本解析
+func (s *terminalScreen) line(row int) []terminalCell {
+	if cells, ok := s.cells[row]; ok {
+		return cells
+	}
+	line, start := s.t.open, s.t.openParser.style
+	if row < len(s.t.lines) {
+		line, start = s.t.lines[row], s.t.lines[row].style
+	}
+	p := ansiParser{style: start}
+	var cells []terminalCell
+	for _, seg := range p.Parse(line.raw) {
+		for _, r := range seg.Text {
+			cells = append(cells, terminalCell{r: r, style: seg.Style})
+		}
+	}
+	s.cells[row] = cells
+	return cells
+}
+
+func (s *terminalScreen) openRow() int {
+	return len(s.t.lines)
+}
+
+func (s *terminalScreen) write(r rune) {
+	cells := s.line(s.row)
+	for len(cells) < s.col {
+		cells = append(cells, terminalCell{r: ' '})
+	}
+	cell := terminalCell{r: r, style: s.pen}
+	if s.col < len(cells) {
+		cells[s.col] = cell
+	} else {
+		cells = append(cells, cell)
+	}
+	s.cells[s.row] = cells
+	s.col++
+}
+
+// newline 在末行时把末行提交到缓冲区，否则移到下一行行首
+func (s *terminalScreen) newline() {
+	if s.row < s.openRow() {
+		s.row++
+		s.col = 0
+		return
+	}
+	t := s.t
+	line := s.encode(s.row, s.pen)
+	line.at = s.openAt
+	t.maskLocked(&line)
+	t.lines = append(t.lines, line)
+	t.bytes += len(line.raw) + len(line.plain)
+	delete(s.cells, s.row)
+	t.open = terminalLine{style: s.pen}
+	t.openParser = ansiParser{style: s.pen}
+	s.row = s.openRow()
+	s.cells[s.row] = nil
+	s.col = 0
+	s.openAt = s.at
+}
+
+// control 执行一个 CSI 序列，不认识的序列与解析器一样忽略
+func (s *terminalScreen) control(final byte, params string) {
+	if final == 'm' {
+		s.pen = applySGR(s.pen, params)
+		return
+	}
+	n, err := strconv.Atoi(params)
+	if err != nil || n < 1 {
+		n = 1
+	}
+	switch final {
+	case 'A':
+		s.row = max(s.row-n, 0)
+	case 'B':
+		s.row = min(s.row+n, s.openRow())
+	case 'C':
+		s.col += n
+	case 'D':
+		s.col = max(s.col-n, 0)
+	case 'E':
+		s.row, s.col = min(s.row+n, s.openRow()), 0
+	case 'F':
+		s.row, s.col = max(s.row-n, 0), 0
+	case 'G':
+		s.col = n - 1
+	case 'K':
+		cells := s.line(s.row)
+		switch params {
+		case "", "0":
+			if s.col < len(cells) {
+				cells = cells[:s.col]
+			}
+		case "1":
+			for i := 0; i < len(cells) && i <= s.col; i++ {
+				cells[i] = terminalCell{r: ' '}
+			}
+		case "2":
+			cells = nil
+		}
+		s.cells[s.row] = cells
+	}
+}
+
+// encode 把第 row 行的字符格编码为终端行，行尾切换到 end 样式，使原始文本拼接后样式仍然连续
+func (s *terminalScreen) encode(row int, end ansiStyle) terminalLine {
+	start := s.t.openParser.style
+	if row < len(s.t.lines) {
+		start = s.t.lines[row].style
+	}
+	var b strings.Builder
+	style := start
+	for _, cell := range s.cells[row] {
+		if cell.style != style {
+			b.WriteString(sgrSequence(cell.style))
+			style = cell.style
+		}
+		b.WriteRune(cell.r)
+	}
+	if style != end {
+		b.WriteString(sgrSequence(end))
+	}
+	p := ansiParser{style: start}
+	return newTerminalLine(&p, b.String())
+}
+
+// flush 把改写过的行写回缓冲区并记录光标位置
+func (s *terminalScreen) flush() {
+	t := s.t
+	for row := range s.cells {
+		if row >= len(t.lines) {
+			continue
+		}
+		if !s.cloned {
+			t.lines = slices.Clone(t.lines)
+			t.rewritten = true
+			s.cloned = true
+		}
+		end := t.openParser.style
+		if row+1 < len(t.lines) {
+			end = t.lines[row+1].style
+		}
+		old := t.lines[row]
+		line := s.encode(row, end)
+		line.at = old.at
+		t.maskLocked(&line)
+		t.lines[row] = line
+		t.bytes += len(line.raw) + len(line.plain) - len(old.raw) - len(old.plain)
+	}
+	openCells := s.line(s.openRow())
+	t.open = s.encode(s.openRow(), s.pen)
+	t.open.at = s.openAt
+	t.maskLocked(&t.open)
+
+	t.emu.up = s.openRow() - s.row
+	t.emu.col = s.col
+	t.emu.placed = t.emu.up > 0 || s.col != len(openCells)
+}
+
+// sgrSequence 返回把样式从默认设置为 style 的 SGR 序列
+func sgrSequence(style ansiStyle) string {
+	codes := []string{"0"}
+	for _, attr := range []struct {
+		on   bool
+		code string
+	}{{style.Bold, "1"}, {style.Faint, "2"}, {style.Italic, "3"}, {style.Underline, "4"}, {style.Inverse, "7"}} {
+		if attr.on {
+			codes = append(codes, attr.code)
+		}
+	}
+	codes = append(codes, sgrColor(style.FG, 30, 90, "38")...)
+	codes = append(codes, sgrColor(style.BG, 40, 100, "48")...)
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+func sgrColor(c ansiColor, base, bright int, extended string) []string {
+	switch {
+	case c.Kind == ansiColorIndexed && c.Index < 8:
+		return []string{strconv.Itoa(base + int(c.Index))}
+	case c.Kind == ansiColorIndexed && c.Index < 16:
+		return []string{strconv.Itoa(bright + int(c.Index) - 8)}
+	case c.Kind == ansiColorIndexed:
+		return []string{extended, "5", strconv.Itoa(int(c.Index))}
+	case c.Kind == ansiColorRGB:
+		return []string{extended, "2", strconv.Itoa(int(c.R)), strconv.Itoa(int(c.G)), strconv.Itoa(int(c.B))}
+	}
+	return nil
+}
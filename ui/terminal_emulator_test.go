@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+// feedTerminal 按块写入文本，模拟多次 AppendText 之间的边界
+func feedTerminal(terminal *TerminalOutput, chunks ...string) {
+	terminal.mu.Lock()
+	defer terminal.mu.Unlock()
+	for _, chunk := range chunks {
+		terminal.appendLinesLocked(chunk, time.Now())
+	}
+}
+
+func TestTerminalCarriageReturnUpdatesInPlace(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	feedTerminal(terminal, "start\n", "progress 10%", "\rprogress 55%", "\r", "progress 100%\n", "done\r\n")
+	if got := terminal.GetText(); got != "start\nprogress 100%\ndone\n" {
+		t.Fatalf("GetText() = %q", got)
+	}
+	// 覆盖写入较短的内容时保留行尾，与真实终端一致
+	terminal.SetFullText("downloading\rok\n")
+	if got := terminal.GetText(); got != "okwnloading\n" {
+		t.Fatalf("GetText() after overwrite = %q", got)
+	}
+}
+
+func TestTerminalLineClearAndBackspace(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	feedTerminal(terminal, "testing |", "\b/", "\b-", "\r\x1b[Kfinished", "\x1b[", "2K\x1b[1Gall done\n")
+	if got := terminal.GetText(); got != "all done\n" {
+		t.Fatalf("GetText() = %q", got)
+	}
+	if !terminal.emu.idle() {
+		t.Fatalf("cursor should be back in append mode, got %+v", terminal.emu)
+	}
+	feedTerminal(terminal, "next\n")
+	if got := terminal.GetText(); got != "all done\nnext\n" {
+		t.Fatalf("GetText() after append = %q", got)
+	}
+}
+
+func TestTerminalCursorUpRewritesEarlierLines(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	feedTerminal(terminal, "header\n", "cpu  [    ]\ndisk [    ]\n")
+	terminal.sync()
+	snapshot := terminal.snapshot
+	feedTerminal(terminal, "\x1b[2A\rcpu  [####]\n\x1b[2Kdisk [##  ]\n")
+	if got := terminal.GetText(); got != "header\ncpu  [####]\ndisk [##  ]\n" {
+		t.Fatalf("GetText() = %q", got)
+	}
+	if snapshot[1].plain != "cpu  [    ]" {
+		t.Fatal("rewriting a line must not modify the snapshot shown by the UI thread")
+	}
+	if terminal.emu.up != 0 {
+		t.Fatalf("cursor should end on the last line, got %+v", terminal.emu)
+	}
+}
+
+func TestTerminalEmulationKeepsColors(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	feedTerminal(terminal, "\x1b[32m 50%\x1b[0m", "\r\x1b[1;32m100%\x1b[0m ok\n")
+	terminal.mu.Lock()
+	line := terminal.lines[0]
+	terminal.mu.Unlock()
+	if line.plain != "100% ok" {
+		t.Fatalf("plain = %q", line.plain)
+	}
+	segments := parseANSI(line.raw)
+	if len(segments) != 2 || segments[0].Text != "100%" || !segments[0].Style.Bold ||
+		segments[0].Style.FG != (ansiColor{Kind: ansiColorIndexed, Index: 2}) || segments[1].Style != (ansiStyle{}) {
+		t.Fatalf("segments = %#v", segments)
+	}
+}
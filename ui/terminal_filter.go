@@ -0,0 +1,192 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// 终端过滤的日志级别
+const (
+	filterLevelAll      = ""
+	filterLevelWarnings = "warnings"
+	filterLevelErrors   = "errors"
+)
+
+var (
+	errorLinePattern   = regexp.MustCompile(`(?i)\b(error|fatal|panic|failed|failure|timeout|timed out)\b|错误|失败|超时|异常`)
+	warningLinePattern = regexp.MustCompile(`(?i)\b(warn|warning|deprecated|skipped|unavailable)\b|警告|注意|跳过|不可用`)
+)
+
+// terminalFilter 决定哪些行显示在终端中，只影响渲染，不修改底层缓冲
+type terminalFilter struct {
+	Level   string
+	Section results.Section
+	Pattern *regexp.Regexp
+}
+
+func (f terminalFilter) active() bool {
+	return f.Level != filterLevelAll || f.Section != results.SectionNone || f.Pattern != nil
+}
+
+// lineLevel 把一行归类为 errors / warnings / 普通
+func lineLevel(plain string) string {
+	switch {
+	case errorLinePattern.MatchString(plain):
+		return filterLevelErrors
+	case warningLinePattern.MatchString(plain):
+		return filterLevelWarnings
+	}
+	return filterLevelAll
+}
+
+func (f terminalFilter) keep(plain string, section results.Section, header bool) bool {
+	if f.Section != results.SectionNone {
+		if section != f.Section {
+			return false
+		}
+		// 选择分区时保留其标题行，便于辨认上下文
+		if header && f.Level == filterLevelAll && f.Pattern == nil {
+			return true
+		}
+	}
+	switch f.Level {
+	case filterLevelErrors:
+		if lineLevel(plain) != filterLevelErrors {
+			return false
+		}
+	case filterLevelWarnings:
+		if lineLevel(plain) == filterLevelAll {
+			return false
+		}
+	}
+	return f.Pattern == nil || f.Pattern.MatchString(plain)
+}
+
+// rows 返回保留下来的行号；未设置过滤条件时返回 nil，表示全部显示
+func (f terminalFilter) rows(total int, line func(int) terminalLine) []int {
+	rows, _ := f.appendRows(nil, results.SectionNone, 0, total, total, line)
+	return rows
+}
+
+// appendRows 从第 from 行起继续过滤：rows 与 section 是上次过滤到第 from 行时的结果，
+// from 为 0 时从头开始。返回的分区是过滤到第 commit 行（已完成的行数）时的状态，供下次继续。
+func (f terminalFilter) appendRows(rows []int, section results.Section, from, commit, total int, line func(int) terminalLine) ([]int, results.Section) {
+	if !f.active() {
+		return nil, results.SectionNone
+	}
+	if from == 0 || rows == nil {
+		rows, section, from = []int{}, results.SectionNone, 0
+	} else {
+		rows = rows[:sort.SearchInts(rows, from)]
+	}
+	saved := section
+	for i := from; i < total; i++ {
+		if i == commit {
+			saved = section
+		}
+		plain := line(i).plain
+		next, header := results.DetectSection(plain)
+		if header {
+			section = next
+		}
+		if f.keep(plain, section, header) {
+			rows = append(rows, i)
+		}
+	}
+	if total <= commit {
+		saved = section
+	}
+	return rows, saved
+}
+
+// SetFilter 设置行过滤条件并重新渲染（需在 UI 线程调用）
+func (t *TerminalOutput) SetFilter(filter terminalFilter) {
+	t.filter = filter
+	t.activeMatch = 0
+	t.selection = terminalSelection{}
+	t.sync()
+}
+
+// createTerminalFilterBar 创建终端上方的过滤栏：级别、测试分区与正则
+func (ui *TestUI) createTerminalFilterBar() fyne.CanvasObject {
+	levels := []string{ui.tr("filter.level_all"), ui.tr("filter.level_warnings"), ui.tr("filter.level_errors")}
+	levelValues := []string{filterLevelAll, filterLevelWarnings, filterLevelErrors}
+	sections := []results.Section{
+		results.SectionNone, results.SectionBasic, results.SectionCPU, results.SectionMemory, results.SectionDisk,
+		results.SectionUnlock, results.SectionIPQuality, results.SectionEmail, results.SectionBacktrace,
+		results.SectionRoute, results.SectionPing, results.SectionSpeed, results.SectionGPU,
+	}
+	sectionLabels := make([]string, len(sections))
+	for i, section := range sections {
+		if section == results.SectionNone {
+			sectionLabels[i] = ui.tr("filter.section_all")
+		} else {
+			sectionLabels[i] = ui.tr("filter.section." + string(section))
+		}
+	}
+
+	level := widget.NewSelect(levels, nil)
+	section := widget.NewSelect(sectionLabels, nil)
+	pattern := widget.NewEntry()
+	pattern.SetPlaceHolder(ui.tr("filter.regex_placeholder"))
+	status := widget.NewLabel("")
+
+	apply := func() {
+		filter := terminalFilter{}
+		for i, label := range levels {
+			if label == level.Selected {
+				filter.Level = levelValues[i]
+			}
+		}
+		for i, label := range sectionLabels {
+			if label == section.Selected {
+				filter.Section = sections[i]
+			}
+		}
+		if text := strings.TrimSpace(pattern.Text); text != "" {
+			re, err := regexp.Compile("(?i)" + text)
+			if err != nil {
+				status.SetText(ui.tr("search.invalid"))
+				return
+			}
+			filter.Pattern = re
+		}
+		ui.Terminal.SetFilter(filter)
+	}
+	ui.Terminal.OnFilterUpdate = func(shown, total int) {
+		if shown == total {
+			status.SetText("")
+			return
+		}
+		status.SetText(fmt.Sprintf(ui.tr("filter.status"), shown, total))
+	}
+
+	level.SetSelected(levels[0])
+	section.SetSelected(sectionLabels[0])
+	level.OnChanged = func(string) { apply() }
+	section.OnChanged = func(string) { apply() }
+	pattern.OnChanged = func(string) { apply() }
+	reset := widget.NewButtonWithIcon("", theme.ContentClearIcon(), func() {
+		pattern.SetText("")
+		level.SetSelected(levels[0])
+		section.SetSelected(sectionLabels[0])
+	})
+
+	gutter := ui.createTerminalGutterButton()
+	fold := ui.createTerminalFoldButton()
+	bookmarks := ui.createTerminalBookmarkButton()
+	wrap := ui.createTerminalWrapButton()
+	selectors := container.NewHBox(widget.NewIcon(theme.VisibilityIcon()), level, section)
+	if isMobilePlatform() {
+		return container.NewVBox(container.NewGridWithColumns(2, level, section), container.NewBorder(nil, nil, nil, container.NewHBox(status, reset, wrap, gutter, fold, bookmarks), pattern))
+	}
+	return container.NewBorder(nil, nil, selectors, container.NewHBox(status, reset, wrap, gutter, fold, bookmarks), pattern)
+}
@@ -0,0 +1,71 @@
+package ui
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// visibleText 拼接当前显示行的原始内容，每行以换行结束
+func visibleText(terminal *TerminalOutput) string {
+	var b strings.Builder
+	for i := 0; i < terminal.rowCount(); i++ {
+		b.WriteString(terminal.row(i).raw + "\n")
+	}
+	return b.String()
+}
+
+func TestTerminalFilterRows(t *testing.T) {
+	content := "----CPU测试----\n" +
+		"单核得分: 1000\n" +
+		"\x1b[31mError: sysbench failed\x1b[0m\n" +
+		"----硬盘测试----\n" +
+		"Warning: fio unavailable, using dd\n" +
+		"4K Read 100MB/s\n"
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.SetFullText(content)
+	terminal.sync()
+
+	tests := []struct {
+		name   string
+		filter terminalFilter
+		want   string
+	}{
+		{"none", terminalFilter{}, content},
+		{"errors", terminalFilter{Level: filterLevelErrors}, "\x1b[31mError: sysbench failed\x1b[0m\n"},
+		{"warnings", terminalFilter{Level: filterLevelWarnings}, "\x1b[31mError: sysbench failed\x1b[0m\nWarning: fio unavailable, using dd\n"},
+		{"section", terminalFilter{Section: results.SectionDisk}, "----硬盘测试----\nWarning: fio unavailable, using dd\n4K Read 100MB/s\n"},
+		{"section+regex", terminalFilter{Section: results.SectionDisk, Pattern: regexp.MustCompile(`(?i)read`)}, "4K Read 100MB/s\n"},
+		{"regex", terminalFilter{Pattern: regexp.MustCompile(`得分`)}, "单核得分: 1000\n"},
+	}
+	for _, tt := range tests {
+		shown, total := -1, -1
+		terminal.OnFilterUpdate = func(s, n int) { shown, total = s, n }
+		terminal.SetFilter(tt.filter)
+		if got := visibleText(terminal); got != tt.want || shown != strings.Count(tt.want, "\n") || total != 6 {
+			t.Fatalf("%s: visible %q, %d/%d; want %q", tt.name, got, shown, total, tt.want)
+		}
+	}
+}
+
+func TestTerminalSetFilterKeepsBuffer(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.SetFullText("ok\nError: boom\nok again\n")
+	terminal.sync()
+
+	terminal.SetFilter(terminalFilter{Level: filterLevelErrors})
+	if got := visibleText(terminal); got != "Error: boom\n" {
+		t.Fatalf("visible %q", got)
+	}
+	if terminal.GetText() != "ok\nError: boom\nok again\n" {
+		t.Fatalf("GetText() = %q, filter must not change the buffer", terminal.GetText())
+	}
+	terminal.SetFilter(terminalFilter{})
+	if got := visibleText(terminal); got != terminal.GetText() {
+		t.Fatalf("visible %q after clearing filter", got)
+	}
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const terminalAutoFoldPreferenceKey = "terminal_auto_fold"
+
+// terminalFolds 记录终端中各阶段的折叠状态，键为绝对行号（含已移出内存的行），
+// 旧行被移出缓冲区后仍能对应到同一个标题；仅在 UI 线程访问
+type terminalFolds struct {
+	collapsed map[int]bool // 已折叠的标题行
+	auto      map[int]bool // 已自动折叠过的标题行，用户展开后不再自动折叠
+}
+
+// isTerminalSectionHeader 判断一行是否为带标题的阶段分隔行，纯 "-----" 分隔线不算
+func isTerminalSectionHeader(plain string) bool {
+	_, ok := results.DetectSection(plain)
+	return ok && strings.Trim(strings.TrimSpace(plain), "-") != ""
+}
+
+// sectionHeaders 返回快照中阶段标题行的行号，from 之前的标题沿用上次的结果
+func (t *TerminalOutput) sectionHeaders(from, total int) []int {
+	if !t.Foldable {
+		return nil
+	}
+	headers := t.headers[:sort.SearchInts(t.headers, from)]
+	for i := from; i < total; i++ {
+		if t.line(i).header {
+			headers = append(headers, i)
+		}
+	}
+	return headers
+}
+
+// sectionEnd 返回第 i 个标题所在阶段的最后一行
+func (t *TerminalOutput) sectionEnd(i, total int) int {
+	if i+1 < len(t.headers) {
+		return t.headers[i+1] - 1
+	}
+	return total - 1
+}
+
+func (t *TerminalOutput) collapsedLine(line int) bool {
+	return t.folds.collapsed[t.lineBase+line]
+}
+
+// foldRows 从显示行中去掉已折叠阶段的内容，标题行保留；rows 为 nil 表示全部显示。
+// from 之前的行沿用上次的结果，只处理之后的行
+func (t *TerminalOutput) foldRows(rows []int, from, total int) []int {
+	folded := false
+	for _, header := range t.headers {
+		if t.collapsedLine(header) {
+			folded = true
+			break
+		}
+	}
+	if !folded {
+		return rows
+	}
+	kept := []int{}
+	if from > 0 && t.rows != nil {
+		kept = t.rows[:sort.SearchInts(t.rows, from)]
+	} else {
+		from = 0
+	}
+	h := sort.SearchInts(t.headers, from+1) - 1 // 不晚于 from 的最后一个标题
+	visit := func(line int) {
+		for h+1 < len(t.headers) && t.headers[h+1] <= line {
+			h++
+		}
+		if h < 0 || t.headers[h] == line || !t.collapsedLine(t.headers[h]) {
+			kept = append(kept, line)
+		}
+	}
+	if rows == nil {
+		for line := from; line < total; line++ {
+			visit(line)
+		}
+	} else {
+		for _, line := range rows[sort.SearchInts(rows, from):] {
+			visit(line)
+		}
+	}
+	return kept
+}
+
+// foldedLines 返回标题行所在阶段被折叠的行数，未折叠或不是标题时返回 0
+func (t *TerminalOutput) foldedLines(line int) int {
+	if !t.collapsedLine(line) {
+		return 0
+	}
+	for i, header := range t.headers {
+		if header == line {
+			return t.sectionEnd(i, t.lineCount()) - header
+		}
+	}
+	return 0
+}
+
+// finishedSections 返回已经结束的阶段数：仍在输出时最后一个阶段尚未结束
+func (t *TerminalOutput) finishedSections() int {
+	if t.Running != nil && t.Running() && len(t.headers) > 0 {
+		return len(t.headers) - 1
+	}
+	return len(t.headers)
+}
+
+// applyAutoFold 开启自动折叠时折叠新结束的阶段，每个阶段只自动折叠一次；有阶段被折叠时返回 true
+func (t *TerminalOutput) applyAutoFold() bool {
+	if !t.autoFold {
+		return false
+	}
+	changed := false
+	for _, header := range t.headers[:t.finishedSections()] {
+		id := t.lineBase + header
+		if t.folds.auto[id] {
+			continue
+		}
+		if t.folds.auto == nil {
+			t.folds.auto = map[int]bool{}
+		}
+		t.folds.auto[id] = true
+		t.setCollapsed(header, true)
+		changed = true
+	}
+	return changed
+}
+
+func (t *TerminalOutput) setCollapsed(line int, collapsed bool) {
+	if t.folds.collapsed == nil {
+		t.folds.collapsed = map[int]bool{}
+	}
+	if collapsed {
+		t.folds.collapsed[t.lineBase+line] = true
+	} else {
+		delete(t.folds.collapsed, t.lineBase+line)
+	}
+}
+
+// ToggleFold 折叠或展开 line 所在的阶段，line 必须是标题行（需在 UI 线程调用）
+func (t *TerminalOutput) ToggleFold(line int) {
+	t.setCollapsed(line, !t.collapsedLine(line))
+	t.selection = terminalSelection{}
+	t.sync()
+}
+
+// CollapseFinished 折叠所有已经结束的阶段（需在 UI 线程调用）
+func (t *TerminalOutput) CollapseFinished() {
+	for _, header := range t.headers[:t.finishedSections()] {
+		t.setCollapsed(header, true)
+	}
+	t.selection = terminalSelection{}
+	t.sync()
+}
+
+// ExpandAll 展开所有阶段（需在 UI 线程调用）
+func (t *TerminalOutput) ExpandAll() {
+	t.folds.collapsed = nil
+	t.selection = terminalSelection{}
+	t.sync()
+}
+
+// SetAutoFold 设置阶段结束后是否自动折叠，开启时立即折叠已结束的阶段（需在 UI 线程调用）
+func (t *TerminalOutput) SetAutoFold(on bool) {
+	t.autoFold = on
+	t.sync()
+}
+
+// resetFolds 在内容被清空或整体替换时清除折叠状态
+func (t *TerminalOutput) resetFolds() {
+	t.folds = terminalFolds{}
+}
+
+// foldAt 返回内容层坐标处折叠标记所在的标题行
+func (t *TerminalOutput) foldAt(pos fyne.Position) (int, bool) {
+	if !t.Foldable || t.rowCount() == 0 {
+		return 0, false
+	}
+	m := t.body.metrics()
+	row := int((pos.Y - m.pad) / m.row)
+	if pos.Y < m.pad || row >= t.rowCount() || pos.X < m.pad+m.mark || pos.X >= m.pad+m.mark+m.fold {
+		return 0, false
+	}
+	line := t.rowLine(row)
+	return line, t.line(line).header
+}
+
+// foldMenuItems 返回折叠相关的菜单项，用于右键菜单与工具栏
+func (t *TerminalOutput) foldMenuItems() []*fyne.MenuItem {
+	return []*fyne.MenuItem{
+		fyne.NewMenuItem(t.tr("fold.collapse_finished"), t.CollapseFinished),
+		fyne.NewMenuItem(t.tr("fold.expand_all"), t.ExpandAll),
+	}
+}
+
+// foldMarker 返回标题行的折叠标记图标
+func foldMarker(collapsed bool) fyne.Resource {
+	if collapsed {
+		return theme.MenuExpandIcon()
+	}
+	return theme.MenuDropDownIcon()
+}
+
+func (t *TerminalOutput) foldedNote(n int) string {
+	return fmt.Sprintf(t.tr("fold.folded"), n)
+}
+
+// setTerminalAutoFold 保存自动折叠设置并应用到主终端与各运行标签页
+func (ui *TestUI) setTerminalAutoFold(on bool) {
+	if ui.App != nil {
+		ui.App.Preferences().SetBool(terminalAutoFoldPreferenceKey, on)
+	}
+	if ui.Terminal != nil {
+		ui.Terminal.SetAutoFold(on)
+	}
+	for _, tab := range ui.extraRuns {
+		tab.terminal.SetAutoFold(on)
+	}
+}
+
+// terminalAutoFoldSetting 返回是否自动折叠已结束的阶段，默认开启
+func (ui *TestUI) terminalAutoFoldSetting() bool {
+	return ui.App == nil || ui.App.Preferences().BoolWithFallback(terminalAutoFoldPreferenceKey, true)
+}
+
+// setupTerminalFolds 为显示测试输出的终端开启按阶段折叠
+func (ui *TestUI) setupTerminalFolds(terminal *TerminalOutput, running func() bool) {
+	terminal.Foldable = true
+	terminal.Running = running
+	terminal.SetAutoFold(ui.terminalAutoFoldSetting())
+}
+
+func (ui *TestUI) terminalFoldMenuItems() []*fyne.MenuItem {
+	auto := fyne.NewMenuItem(ui.tr("fold.auto"), func() { ui.setTerminalAutoFold(!ui.terminalAutoFoldSetting()) })
+	auto.Checked = ui.terminalAutoFoldSetting()
+	return append(ui.Terminal.foldMenuItems(), fyne.NewMenuItemSeparator(), auto)
+}
+
+// createTerminalFoldButton 创建终端工具栏中折叠阶段的按钮
+func (ui *TestUI) createTerminalFoldButton() *widget.Button {
+	button := widget.NewButtonWithIcon("", theme.ListIcon(), nil)
+	button.OnTapped = func() {
+		menu := fyne.NewMenu("", ui.terminalFoldMenuItems()...)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(button)
+		if canvas == nil {
+			return
+		}
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
+	}
+	return button
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+const foldSample = "banner\n" +
+	"----CPU测试-通过sysbench测试----\n" +
+	"1 线程测试(单核)得分: 1000\n" +
+	"--------------------\n" +
+	"----就近节点测速----\n" +
+	"位置 上传速度 下载速度\n" +
+	"上海电信 100Mbps\n"
+
+func shownText(terminal *TerminalOutput) string {
+	var rows []string
+	for i := 0; i < terminal.rowCount(); i++ {
+		rows = append(rows, terminal.row(i).plain)
+	}
+	return strings.Join(rows, "\n")
+}
+
+func TestTerminalFoldsSections(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.Foldable = true
+	feedTerminal(terminal, foldSample)
+	terminal.sync()
+	if len(terminal.headers) != 2 || terminal.headers[0] != 1 || terminal.headers[1] != 4 {
+		t.Fatalf("headers = %v, plain separators must not count", terminal.headers)
+	}
+
+	terminal.ToggleFold(1)
+	want := "banner\n----CPU测试-通过sysbench测试----\n----就近节点测速----\n位置 上传速度 下载速度\n上海电信 100Mbps"
+	if got := shownText(terminal); got != want {
+		t.Fatalf("shown after folding CPU = %q", got)
+	}
+	if n := terminal.foldedLines(1); n != 2 {
+		t.Fatalf("foldedLines() = %d, want 2", n)
+	}
+
+	// 旧行被移出内存后折叠仍跟随同一个标题
+	terminal.mu.Lock()
+	terminal.lines = terminal.lines[1:]
+	terminal.dropped = 1
+	terminal.mu.Unlock()
+	terminal.sync()
+	if !terminal.collapsedLine(0) || terminal.rowCount() != 4 {
+		t.Fatalf("fold should follow the header after trimming, shown %q", shownText(terminal))
+	}
+
+	terminal.ExpandAll()
+	if terminal.rowCount() != terminal.lineCount() {
+		t.Fatal("ExpandAll should show every line")
+	}
+	if got := terminal.GetText(); !strings.Contains(got, "----CPU测试-通过sysbench测试----\n1 线程测试") {
+		t.Fatalf("folding must not change the text, got %q", got)
+	}
+}
+
+func TestTerminalAutoFoldSkipsRunningStage(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	running := true
+	terminal.Foldable = true
+	terminal.Running = func() bool { return running }
+	terminal.SetAutoFold(true)
+	feedTerminal(terminal, foldSample)
+	terminal.sync()
+	if !terminal.collapsedLine(1) || terminal.collapsedLine(4) {
+		t.Fatal("only finished stages should be collapsed while running")
+	}
+
+	// 用户展开后不会再被自动折叠
+	terminal.ToggleFold(1)
+	running = false
+	terminal.sync()
+	if terminal.collapsedLine(1) || !terminal.collapsedLine(4) {
+		t.Fatal("the last stage should collapse once the run ends, without refolding expanded stages")
+	}
+
+	terminal.SetAutoFold(false)
+	terminal.SetFullText(foldSample)
+	if terminal.collapsedLine(1) || terminal.collapsedLine(4) {
+		t.Fatal("replacing the text should reset folds")
+	}
+	terminal.CollapseFinished()
+	if !terminal.collapsedLine(1) || !terminal.collapsedLine(4) {
+		t.Fatal("CollapseFinished should fold every stage after the run")
+	}
+}
+
+func TestSetTerminalAutoFoldPersists(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	if !ui.Terminal.Foldable || !ui.terminalAutoFoldSetting() {
+		t.Fatal("the main terminal should fold stages and auto-collapse by default")
+	}
+	items := ui.terminalFoldMenuItems()
+	auto := items[len(items)-1]
+	if !auto.Checked {
+		t.Fatal("the auto-collapse item should be checked by default")
+	}
+	auto.Action()
+	if ui.terminalAutoFoldSetting() || ui.Terminal.autoFold {
+		t.Fatal("turning auto-collapse off should persist and apply to the terminal")
+	}
+}
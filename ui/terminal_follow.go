@@ -0,0 +1,117 @@
+package ui
+
+import (
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// 距离底部小于该值时视为“在底部”
+const followBottomSlack = 8
+
+// terminalFollower 让终端跟随最新输出：用户向上滚动时自动暂停跟随，
+// 并在右下角显示“跳到底部（N 行新输出）”按钮。所有方法都在 UI 线程调用。
+type terminalFollower struct {
+	terminal  *TerminalOutput
+	scroll    *container.Scroll
+	jump      *widget.Button
+	toggle    *widget.Check
+	tr        func(string) string
+	following bool
+	newLines  int
+	// 程序触发的滚动不应被当作用户操作
+	autoScrolling bool
+}
+
+func newTerminalFollower(terminal *TerminalOutput, tr func(string) string) *terminalFollower {
+	f := &terminalFollower{
+		terminal:  terminal,
+		scroll:    terminal.scroll,
+		tr:        tr,
+		following: true,
+	}
+	f.jump = widget.NewButtonWithIcon("", theme.MoveDownIcon(), f.ScrollToBottom)
+	f.jump.Importance = widget.HighImportance
+	f.jump.Hide()
+	f.toggle = widget.NewCheck(tr("terminal.follow"), f.SetFollowing)
+	f.toggle.SetChecked(true)
+	terminal.OnScrolled = f.onScrolled
+	terminal.OnContentChanged = f.onContentChanged
+	return f
+}
+
+// Content 返回带悬浮按钮的终端
+func (f *terminalFollower) Content() fyne.CanvasObject {
+	return container.NewStack(
+		f.terminal,
+		container.NewVBox(layout.NewSpacer(), container.NewHBox(layout.NewSpacer(), container.NewPadded(f.jump))),
+	)
+}
+
+// SetFollowing 打开或关闭自动滚动，打开时立即跳到底部
+func (f *terminalFollower) SetFollowing(on bool) {
+	if on == f.following {
+		return
+	}
+	if on {
+		f.ScrollToBottom()
+		return
+	}
+	f.following = false
+	f.toggle.SetChecked(false)
+}
+
+// ScrollToBottom 跳到最新输出并恢复跟随
+func (f *terminalFollower) ScrollToBottom() {
+	f.following = true
+	f.newLines = 0
+	f.jump.Hide()
+	f.toggle.SetChecked(true)
+	f.autoScrolling = true
+	f.scroll.ScrollToBottom()
+	f.autoScrolling = false
+}
+
+func (f *terminalFollower) onContentChanged(appended int, reset bool) {
+	if reset {
+		f.newLines = 0
+		f.jump.Hide()
+		if f.following {
+			f.scroll.ScrollToTop()
+		}
+		return
+	}
+	if f.following {
+		f.autoScrolling = true
+		f.scroll.ScrollToBottom()
+		f.autoScrolling = false
+		return
+	}
+	if appended <= 0 {
+		return
+	}
+	f.newLines += appended
+	f.jump.SetText(fmt.Sprintf(f.tr("terminal.jump_bottom"), f.newLines))
+	f.jump.Show()
+}
+
+func (f *terminalFollower) onScrolled(pos fyne.Position) {
+	if f.autoScrolling {
+		return
+	}
+	bottom := f.scroll.Content.MinSize().Height - f.scroll.Size().Height
+	if pos.Y >= bottom-followBottomSlack {
+		if !f.following {
+			f.ScrollToBottom()
+		}
+		return
+	}
+	if f.following {
+		f.following = false
+		f.toggle.SetChecked(false)
+	}
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestTerminalFollowerSuspendsWhenScrolledUp(t *testing.T) {
+	ui := newTestUIForTest(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	follow := newTerminalFollower(terminal, ui.tr)
+	win := test.NewWindow(follow.Content())
+	t.Cleanup(win.Close)
+	win.Resize(fyne.NewSize(400, 200))
+
+	// 用固定高度的内容代替终端渲染结果，只验证跟随逻辑
+	tall := canvas.NewRectangle(color.Transparent)
+	tall.SetMinSize(fyne.NewSize(300, 2000))
+	follow.scroll.Content = tall
+	follow.scroll.Refresh()
+	follow.onContentChanged(5, false)
+	bottom := follow.scroll.Content.MinSize().Height - follow.scroll.Size().Height
+	if follow.scroll.Offset.Y < bottom-followBottomSlack {
+		t.Fatalf("offset = %v, want bottom %v while following", follow.scroll.Offset.Y, bottom)
+	}
+
+	// 用户向上滚动：暂停跟随，新输出只累计行数
+	follow.scroll.Offset = fyne.NewPos(0, 0)
+	follow.onScrolled(follow.scroll.Offset)
+	if follow.following || follow.toggle.Checked {
+		t.Fatal("scrolling up should suspend auto-scroll")
+	}
+	follow.onContentChanged(3, false)
+	follow.onContentChanged(4, false)
+	if follow.scroll.Offset.Y != 0 {
+		t.Fatalf("offset moved to %v while suspended", follow.scroll.Offset.Y)
+	}
+	if follow.jump.Hidden || follow.jump.Text != fmt.Sprintf(ui.tr("terminal.jump_bottom"), 7) {
+		t.Fatalf("jump button hidden=%v text=%q", follow.jump.Hidden, follow.jump.Text)
+	}
+
+	test.Tap(follow.jump)
+	if !follow.following || !follow.jump.Hidden || !follow.toggle.Checked {
+		t.Fatal("jump to bottom should resume following")
+	}
+	if follow.scroll.Offset.Y < bottom-followBottomSlack {
+		t.Fatalf("offset = %v after jump, want bottom", follow.scroll.Offset.Y)
+	}
+
+	follow.toggle.SetChecked(false)
+	if follow.following {
+		t.Fatal("unchecking the toggle should stop following")
+	}
+}
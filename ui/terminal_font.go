@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	terminalFontPathPreferenceKey = "terminal_font_path"
+	terminalFontSizePreferenceKey = "terminal_font_size"
+)
+
+// terminalFontSizes 是可选的终端字号，缩放时按此列表逐级调整
+var terminalFontSizes = []float32{10, 11, 12, 13, 14, 15, 16, 18, 20, 22, 24, 28, 32, 36}
+
+// measure 按终端当前字体计算文本尺寸
+func (t *TerminalOutput) measure(text string, size float32, style fyne.TextStyle) fyne.Size {
+	s, _ := fyne.CurrentApp().Driver().RenderedTextSize(text, size, style, t.fontSource)
+	return s
+}
+
+// SetFont 设置终端字体与字号；source 为 nil 使用内置等宽字体，size 为 0 跟随主题（需在 UI 线程调用）
+func (t *TerminalOutput) SetFont(source fyne.Resource, size float32) {
+	t.fontSource = source
+	t.fontSize = size
+	t.generation++
+	t.body.Refresh()
+	t.scroll.Refresh()
+}
+
+// Zoom 按 terminalFontSizes 放大（steps > 0）或缩小字号，返回新字号
+func (t *TerminalOutput) Zoom(steps int) float32 {
+	current := t.body.metrics().text
+	idx := len(terminalFontSizes) - 1
+	for i, size := range terminalFontSizes {
+		if size >= current {
+			idx = i
+			break
+		}
+	}
+	if steps > 0 && terminalFontSizes[idx] > current {
+		steps--
+	}
+	idx = max(0, min(idx+steps, len(terminalFontSizes)-1))
+	t.SetFont(t.fontSource, terminalFontSizes[idx])
+	if t.OnZoom != nil {
+		t.OnZoom(t.fontSize)
+	}
+	return t.fontSize
+}
+
+// ResetZoom 恢复跟随主题的字号
+func (t *TerminalOutput) ResetZoom() {
+	t.SetFont(t.fontSource, 0)
+	if t.OnZoom != nil {
+		t.OnZoom(0)
+	}
+}
+
+var _ fyne.Scrollable = (*terminalBody)(nil)
+
+// Scrolled 按住 Ctrl（macOS 为 Cmd）滚动滚轮时缩放字号，否则照常滚动
+func (b *terminalBody) Scrolled(e *fyne.ScrollEvent) {
+	if d, ok := fyne.CurrentApp().Driver().(desktop.Driver); ok && d.CurrentKeyModifiers()&fyne.KeyModifierShortcutDefault != 0 {
+		switch {
+		case e.Scrolled.DY > 0:
+			b.term.Zoom(1)
+		case e.Scrolled.DY < 0:
+			b.term.Zoom(-1)
+		}
+		return
+	}
+	b.term.scroll.Scrolled(e)
+}
+
+// applyTerminalFont 把保存的字体设置应用到终端，并在缩放时保存新字号
+func (ui *TestUI) applyTerminalFont(terminal *TerminalOutput) {
+	prefs := ui.App.Preferences()
+	var source fyne.Resource
+	if path := prefs.String(terminalFontPathPreferenceKey); path != "" {
+		if res, err := fyne.LoadResourceFromPath(path); err == nil {
+			source = res
+		}
+	}
+	terminal.SetFont(source, float32(prefs.Float(terminalFontSizePreferenceKey)))
+	terminal.OnZoom = func(size float32) {
+		prefs.SetFloat(terminalFontSizePreferenceKey, float64(size))
+		if ui.FontSizeSelect != nil {
+			ui.FontSizeSelect.SetSelected(ui.fontSizeLabel(size))
+		}
+	}
+}
+
+// registerZoomShortcuts 注册 Ctrl+= / Ctrl+- / Ctrl+0 调整终端字号
+func (ui *TestUI) registerZoomShortcuts() {
+	if ui.Window == nil {
+		return
+	}
+	add := func(key fyne.KeyName, action func()) {
+		shortcut := &desktop.CustomShortcut{KeyName: key, Modifier: fyne.KeyModifierShortcutDefault}
+		ui.Window.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) { action() })
+	}
+	zoomIn := func() { ui.Terminal.Zoom(1) }
+	add(fyne.KeyEqual, zoomIn)
+	add(fyne.KeyPlus, zoomIn)
+	add(fyne.KeyMinus, func() { ui.Terminal.Zoom(-1) })
+	add(fyne.Key0, func() { ui.Terminal.ResetZoom() })
+}
+
+func (ui *TestUI) fontSizeLabel(size float32) string {
+	if size <= 0 {
+		return ui.tr("font.size_auto")
+	}
+	return strconv.FormatFloat(float64(size), 'f', -1, 32)
+}
+
+// createTerminalFontSizeSelect 创建终端字号选项，切换后立即应用并保存
+func (ui *TestUI) createTerminalFontSizeSelect() *widget.Select {
+	prefs := ui.App.Preferences()
+	labels := []string{ui.fontSizeLabel(0)}
+	for _, size := range terminalFontSizes {
+		labels = append(labels, ui.fontSizeLabel(size))
+	}
+	selectWidget := widget.NewSelect(labels, nil)
+	selectWidget.SetSelected(ui.fontSizeLabel(float32(prefs.Float(terminalFontSizePreferenceKey))))
+	selectWidget.OnChanged = func(label string) {
+		size := float32(0)
+		if v, err := strconv.ParseFloat(label, 32); err == nil {
+			size = float32(v)
+		}
+		prefs.SetFloat(terminalFontSizePreferenceKey, float64(size))
+		if ui.Terminal != nil && ui.Terminal.fontSize != size {
+			ui.Terminal.SetFont(ui.Terminal.fontSource, size)
+		}
+	}
+	return selectWidget
+}
+
+// createTerminalFontSelect 创建终端字体选项：内置等宽字体、已选的字体文件或选择新的 TTF/OTF 文件
+func (ui *TestUI) createTerminalFontSelect() *widget.Select {
+	prefs := ui.App.Preferences()
+	builtin := ui.tr("font.builtin")
+	choose := ui.tr("font.choose")
+	options := []string{builtin}
+	current := builtin
+	if path := prefs.String(terminalFontPathPreferenceKey); path != "" {
+		current = filepath.Base(path)
+		options = append(options, current)
+	}
+	selectWidget := widget.NewSelect(append(options, choose), nil)
+	selectWidget.SetSelected(current)
+	selectWidget.OnChanged = func(label string) {
+		switch label {
+		case current:
+		case builtin:
+			current = builtin
+			prefs.SetString(terminalFontPathPreferenceKey, "")
+			ui.Terminal.SetFont(nil, ui.Terminal.fontSize)
+		case choose:
+			selectWidget.SetSelected(current)
+			ui.chooseTerminalFont(func(name string) {
+				current = name
+				selectWidget.SetOptions([]string{builtin, name, choose})
+				selectWidget.SetSelected(name)
+			})
+		}
+	}
+	return selectWidget
+}
+
+// chooseTerminalFont 让用户选择 TTF/OTF 字体文件，成功后应用并保存路径
+func (ui *TestUI) chooseTerminalFont(done func(name string)) {
+	open := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		if reader == nil {
+			return
+		}
+		defer reader.Close()
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			dialog.ShowError(err, ui.Window)
+			return
+		}
+		name := reader.URI().Name()
+		ui.App.Preferences().SetString(terminalFontPathPreferenceKey, reader.URI().Path())
+		ui.Terminal.SetFont(fyne.NewStaticResource(name, data), ui.Terminal.fontSize)
+		done(name)
+	}, ui.Window)
+	open.SetFilter(storage.NewExtensionFileFilter([]string{".ttf", ".otf"}))
+	open.Show()
+}
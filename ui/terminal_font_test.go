@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2/theme"
+)
+
+func TestTerminalZoomStepsThroughSizes(t *testing.T) {
+	ui := newTestUIForTest(t)
+	term := ui.Terminal
+	base := ui.App.Settings().Theme().Size(theme.SizeNameText)
+
+	if got := term.Zoom(1); got <= base {
+		t.Fatalf("zoom in from %v = %v", base, got)
+	}
+	if got := ui.App.Preferences().Float(terminalFontSizePreferenceKey); float32(got) != term.fontSize {
+		t.Fatalf("saved size = %v, want %v", got, term.fontSize)
+	}
+	if ui.FontSizeSelect.Selected != ui.fontSizeLabel(term.fontSize) {
+		t.Fatalf("size select = %q", ui.FontSizeSelect.Selected)
+	}
+	if got := term.Zoom(-1); got != base {
+		t.Fatalf("zoom back = %v, want %v", got, base)
+	}
+	if got := term.Zoom(-100); got != terminalFontSizes[0] {
+		t.Fatalf("zoom out clamp = %v", got)
+	}
+	if got := term.Zoom(100); got != terminalFontSizes[len(terminalFontSizes)-1] {
+		t.Fatalf("zoom in clamp = %v", got)
+	}
+	if m := term.body.metrics(); m.text != term.fontSize {
+		t.Fatalf("rendered text size = %v, want %v", m.text, term.fontSize)
+	}
+
+	term.ResetZoom()
+	if term.fontSize != 0 || ui.App.Preferences().Float(terminalFontSizePreferenceKey) != 0 {
+		t.Fatalf("reset size = %v", term.fontSize)
+	}
+	if ui.FontSizeSelect.Selected != ui.fontSizeLabel(0) {
+		t.Fatalf("size select after reset = %q", ui.FontSizeSelect.Selected)
+	}
+}
+
+func TestTerminalFontSizeSelectAppliesSize(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.FontSizeSelect.SetSelected("20")
+	if ui.Terminal.fontSize != 20 {
+		t.Fatalf("terminal size = %v, want 20", ui.Terminal.fontSize)
+	}
+
+	// 新建的终端沿用保存的字号
+	term := NewTerminalOutput()
+	defer term.Destroy()
+	ui.applyTerminalFont(term)
+	if term.fontSize != 20 {
+		t.Fatalf("restored size = %v, want 20", term.fontSize)
+	}
+}
@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const terminalGutterPreferenceKey = "terminal_gutter"
+
+// 终端行首时间列的显示方式
+const (
+	terminalGutterOff     = ""
+	terminalGutterClock   = "clock"   // 写入时的本地时间
+	terminalGutterElapsed = "elapsed" // 距第一行写入的耗时
+)
+
+var terminalGutterModes = []string{terminalGutterOff, terminalGutterClock, terminalGutterElapsed}
+
+// terminalGutterCols 是时间列的宽度（字符），两种格式都不超过该宽度
+const terminalGutterCols = 12
+
+// formatTerminalStamp 按显示方式格式化一行的写入时间，没有时间或关闭时返回空
+func formatTerminalStamp(mode string, at, origin time.Time) string {
+	if at.IsZero() {
+		return ""
+	}
+	switch mode {
+	case terminalGutterClock:
+		return at.Local().Format("15:04:05.000")
+	case terminalGutterElapsed:
+		ms := max(at.Sub(origin), 0).Milliseconds()
+		if h := ms / 3600000; h > 0 {
+			return fmt.Sprintf("+%d:%02d:%02d.%d", h, ms/60000%60, ms/1000%60, ms%1000/100)
+		}
+		return fmt.Sprintf("+%02d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+	}
+	return ""
+}
+
+// SetGutter 设置行首时间列，mode 为 terminalGutterModes 之一
+func (t *TerminalOutput) SetGutter(mode string) {
+	t.mu.Lock()
+	t.gutter = mode
+	t.mu.Unlock()
+
+	uiDo(t.sync)
+}
+
+func (t *TerminalOutput) gutterMode() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.gutter
+}
+
+// stamp 返回一行在时间列中显示的文字
+func (t *TerminalOutput) stamp(line terminalLine) string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return formatTerminalStamp(t.gutter, line.at, t.origin)
+}
+
+// stampLocked 返回保存日志时的行首时间，如 "[12:00:01.250] "，时间列关闭或没有时间时为空
+func (t *TerminalOutput) stampLocked(line terminalLine) string {
+	if stamp := formatTerminalStamp(t.gutter, line.at, t.origin); stamp != "" {
+		return "[" + stamp + "] "
+	}
+	return ""
+}
+
+func (ui *TestUI) terminalGutterSetting() string {
+	if ui.App == nil {
+		return terminalGutterOff
+	}
+	return ui.App.Preferences().String(terminalGutterPreferenceKey)
+}
+
+// setTerminalGutter 保存时间列设置并应用到主终端与各运行标签页
+func (ui *TestUI) setTerminalGutter(mode string) {
+	if ui.App != nil {
+		ui.App.Preferences().SetString(terminalGutterPreferenceKey, mode)
+	}
+	if ui.Terminal != nil {
+		ui.Terminal.SetGutter(mode)
+	}
+	for _, tab := range ui.extraRuns {
+		tab.terminal.SetGutter(mode)
+	}
+}
+
+func (ui *TestUI) terminalGutterMenuItems() []*fyne.MenuItem {
+	current := ui.terminalGutterSetting()
+	items := make([]*fyne.MenuItem, len(terminalGutterModes))
+	for i, mode := range terminalGutterModes {
+		key := "gutter.off"
+		if mode != terminalGutterOff {
+			key = "gutter." + mode
+		}
+		item := fyne.NewMenuItem(ui.tr(key), func() { ui.setTerminalGutter(mode) })
+		item.Checked = mode == current
+		items[i] = item
+	}
+	return items
+}
+
+// createTerminalGutterButton 创建终端工具栏中切换时间列的按钮
+func (ui *TestUI) createTerminalGutterButton() *widget.Button {
+	button := widget.NewButtonWithIcon("", theme.HistoryIcon(), nil)
+	button.OnTapped = func() {
+		menu := fyne.NewMenu("", ui.terminalGutterMenuItems()...)
+		canvas := fyne.CurrentApp().Driver().CanvasForObject(button)
+		if canvas == nil {
+			return
+		}
+		pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(button).AddXY(0, button.Size().Height)
+		widget.ShowPopUpMenuAtPosition(menu, canvas, pos)
+	}
+	return button
+}
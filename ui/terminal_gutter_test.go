@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormatTerminalStamp(t *testing.T) {
+	origin := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	tests := []struct {
+		mode string
+		at   time.Time
+		want string
+	}{
+		{terminalGutterClock, origin.Add(1250 * time.Millisecond), "12:00:01.250"},
+		{terminalGutterElapsed, origin.Add(75*time.Second + 5*time.Millisecond), "+01:15.005"},
+		{terminalGutterElapsed, origin.Add(time.Hour + 2*time.Minute + 3*time.Second + 400*time.Millisecond), "+1:02:03.4"},
+		{terminalGutterElapsed, origin.Add(-time.Second), "+00:00.000"},
+		{terminalGutterOff, origin, ""},
+		{terminalGutterClock, time.Time{}, ""},
+	}
+	for _, tt := range tests {
+		got := formatTerminalStamp(tt.mode, tt.at, origin)
+		if got != tt.want {
+			t.Fatalf("formatTerminalStamp(%q, %v) = %q, want %q", tt.mode, tt.at, got, tt.want)
+		}
+		if len(got) > terminalGutterCols {
+			t.Fatalf("stamp %q is wider than the gutter", got)
+		}
+	}
+}
+
+func TestTerminalStampsLinesAtAppendTime(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	base := time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	terminal.mu.Lock()
+	terminal.appendPendingLocked("first\nsec")
+	terminal.pendingAt[0].at = base
+	terminal.appendPendingLocked("ond\nthird\n")
+	terminal.pendingAt[1].at = base.Add(1500 * time.Millisecond)
+	terminal.mu.Unlock()
+
+	terminal.SetGutter(terminalGutterElapsed)
+	want := "[+00:00.000] first\n[+00:00.000] second\n[+00:01.500] third\n"
+	if got := terminal.GetLogText(false); got != want {
+		t.Fatalf("GetLogText() = %q, want %q", got, want)
+	}
+	if got := terminal.GetText(); got != "first\nsecond\nthird\n" {
+		t.Fatalf("GetText() should not include timestamps, got %q", got)
+	}
+
+	terminal.SetGutter(terminalGutterOff)
+	if got := terminal.GetLogText(false); got != "first\nsecond\nthird\n" {
+		t.Fatalf("GetLogText() with the gutter off = %q", got)
+	}
+	// 整体载入的文本没有写入时间
+	terminal.SetGutter(terminalGutterClock)
+	terminal.SetFullText("loaded\n")
+	if got := terminal.GetLogText(false); got != "loaded\n" {
+		t.Fatalf("GetLogText() for loaded text = %q", got)
+	}
+}
+
+func TestSetTerminalGutterPersistsAndApplies(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	items := ui.terminalGutterMenuItems()
+	if len(items) != len(terminalGutterModes) || !items[0].Checked {
+		t.Fatal("the gutter should default to off")
+	}
+	items[1].Action()
+	if ui.terminalGutterSetting() != terminalGutterClock || ui.Terminal.gutterMode() != terminalGutterClock {
+		t.Fatal("choosing wall-clock time should persist and apply to the terminal")
+	}
+	if m := ui.Terminal.body.metrics(); m.gutter <= 0 {
+		t.Fatal("the terminal should reserve room for the gutter")
+	}
+	if !ui.terminalGutterMenuItems()[1].Checked {
+		t.Fatal("the menu should mark the current mode")
+	}
+}
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"context"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/childproc"
+)
+
+var (
+	urlLinkPattern  = regexp.MustCompile(`https?://[^\s<>"'` + "`" + `]+`)
+	ipv4LinkPattern = regexp.MustCompile(`\d{1,3}(?:\.\d{1,3}){3}`)
+	ipv6LinkPattern = regexp.MustCompile(`[0-9A-Fa-f]{0,4}(?::[0-9A-Fa-f]{0,4}){2,7}`)
+)
+
+// terminalLink 是一行纯文本中可点击的区间 [Start, End)
+type terminalLink struct {
+	Start int
+	End   int
+	Text  string
+	IP    bool // true 为 IP 地址，否则为 URL
+}
+
+// findTerminalLinks 查找一行中的 URL 与 IP 地址，结果按位置排序且互不重叠
+func findTerminalLinks(plain string) []terminalLink {
+	if !strings.ContainsAny(plain, ".:") {
+		return nil
+	}
+	var links []terminalLink
+	for _, loc := range urlLinkPattern.FindAllStringIndex(plain, -1) {
+		// 去掉句末标点与不成对的右括号
+		text := strings.TrimRight(plain[loc[0]:loc[1]], ".,;:!?)]}）。，")
+		if _, err := url.Parse(text); err == nil && len(text) > len("https://") {
+			links = append(links, terminalLink{Start: loc[0], End: loc[0] + len(text), Text: text})
+		}
+	}
+	addIP := func(start, end int, neighbour func(byte) bool) {
+		// 排除版本号等更长的数字串中的片段
+		if start > 0 && neighbour(plain[start-1]) || end < len(plain) && neighbour(plain[end]) {
+			return
+		}
+		if ip := net.ParseIP(plain[start:end]); ip == nil || ip.IsUnspecified() {
+			return
+		}
+		for _, link := range links {
+			if start < link.End && end > link.Start {
+				return
+			}
+		}
+		links = append(links, terminalLink{Start: start, End: end, Text: plain[start:end], IP: true})
+	}
+	for _, loc := range ipv4LinkPattern.FindAllStringIndex(plain, -1) {
+		addIP(loc[0], loc[1], isIPv4Neighbour)
+	}
+	if strings.Count(plain, ":") >= 2 {
+		for _, loc := range ipv6LinkPattern.FindAllStringIndex(plain, -1) {
+			if strings.Contains(plain[loc[0]:loc[1]], "::") || strings.Count(plain[loc[0]:loc[1]], ":") == 7 {
+				addIP(loc[0], loc[1], isIPv6Neighbour)
+			}
+		}
+	}
+	sort.Slice(links, func(i, j int) bool { return links[i].Start < links[j].Start })
+	return links
+}
+
+func isIPv4Neighbour(c byte) bool {
+	return c == '.' || c >= '0' && c <= '9'
+}
+
+func isIPv6Neighbour(c byte) bool {
+	return c == '.' || c == ':' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// charIndexAt 返回横坐标 x 处字符的字节偏移，超出行尾时返回 -1
+func (t *TerminalOutput) charIndexAt(text string, x, size float32) int {
+	width := float32(0)
+	for i, r := range text {
+		width += t.textWidth(string(r), size)
+		if x < width {
+			return i
+		}
+	}
+	return -1
+}
+
+// linkAt 返回内容层坐标处的链接
+func (t *TerminalOutput) linkAt(pos fyne.Position) (terminalLink, bool) {
+	if t.rowCount() == 0 {
+		return terminalLink{}, false
+	}
+	m := t.body.metrics()
+	row := int((pos.Y - m.pad) / m.row)
+	if pos.Y < m.pad || row >= t.rowCount() {
+		return terminalLink{}, false
+	}
+	plain := t.row(row).plain
+	idx := t.charIndexAt(plain, pos.X-m.pad-m.indent(), m.text)
+	if idx < 0 {
+		return terminalLink{}, false
+	}
+	for _, link := range findTerminalLinks(plain) {
+		if idx >= link.Start && idx < link.End {
+			return link, true
+		}
+	}
+	return terminalLink{}, false
+}
+
+// openLink 打开 URL，或在 IP 上弹出操作菜单
+func (t *TerminalOutput) openLink(link terminalLink, abs fyne.Position) {
+	if !link.IP {
+		if u, err := url.Parse(link.Text); err == nil {
+			_ = fyne.CurrentApp().OpenURL(u)
+		}
+		return
+	}
+	c := fyne.CurrentApp().Driver().CanvasForObject(t.body)
+	if c == nil {
+		return
+	}
+	items := []*fyne.MenuItem{fyne.NewMenuItem(t.tr("terminal.copy"), func() {
+		fyne.CurrentApp().Clipboard().SetContent(link.Text)
+	})}
+	if t.IPActions != nil {
+		items = t.IPActions(link.Text)
+	}
+	widget.ShowPopUpMenuAtPosition(fyne.NewMenu("", items...), c, abs)
+}
+
+var (
+	_ desktop.Hoverable  = (*terminalBody)(nil)
+	_ desktop.Cursorable = (*terminalBody)(nil)
+)
+
+func (b *terminalBody) MouseIn(e *desktop.MouseEvent) {
+	b.MouseMoved(e)
+}
+
+// MouseMoved 在链接、书签列或折叠标记上方时切换为手型光标
+func (b *terminalBody) MouseMoved(e *desktop.MouseEvent) {
+	_, b.overLink = b.term.linkAt(e.Position)
+	_, mark := b.term.markAt(e.Position)
+	_, fold := b.term.foldAt(e.Position)
+	b.overLink = b.overLink || mark || fold
+}
+
+func (b *terminalBody) MouseOut() {
+	b.overLink = false
+}
+
+func (b *terminalBody) Cursor() desktop.Cursor {
+	if b.overLink {
+		return desktop.PointerCursor
+	}
+	return desktop.TextCursor
+}
+
+// ipMenuItems 返回终端中 IP 地址的操作：查询 whois、ping 与复制
+func (ui *TestUI) ipMenuItems(ip string) []*fyne.MenuItem {
+	return []*fyne.MenuItem{
+		fyne.NewMenuItem("whois", func() {
+			if u, err := url.Parse("https://www.whois.com/whois/" + url.PathEscape(ip)); err == nil {
+				_ = ui.App.OpenURL(u)
+			}
+		}),
+		fyne.NewMenuItem("ping", func() { ui.showPingDialog(ip) }),
+		fyne.NewMenuItem(ui.tr("terminal.copy"), func() { ui.App.Clipboard().SetContent(ip) }),
+	}
+}
+
+// showPingDialog 在后台 ping 指定地址并在对话框中显示输出
+func (ui *TestUI) showPingDialog(ip string) {
+	output := widget.NewLabel(ui.tr("ping.running"))
+	output.TextStyle = fyne.TextStyle{Monospace: true}
+	scroll := container.NewScroll(output)
+	scroll.SetMinSize(fyne.NewSize(520, 240))
+	dlg := dialog.NewCustom("ping "+ip, ui.tr("dialog.close"), scroll, ui.Window)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	dlg.SetOnClosed(cancel)
+	dlg.Show()
+
+	go func() {
+		defer cancel()
+		out, err := childproc.CombinedOutput(pingCommand(ctx, ip, 4))
+		text := strings.TrimSpace(string(out))
+		if err != nil && text == "" {
+			text = err.Error()
+		}
+		ui.runOnUI(func() { output.SetText(text) })
+	}()
+}
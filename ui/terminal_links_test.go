@@ -0,0 +1,77 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestFindTerminalLinks(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{"Result: https://www.speedtest.net/result/c/abc-123.", []string{"https://www.speedtest.net/result/c/abc-123"}},
+		{"(see https://browser.geekbench.com/v6/cpu/1)", []string{"https://browser.geekbench.com/v6/cpu/1"}},
+		{"IPV4 ASN: AS13335 IP: 1.1.1.1, gateway 10.0.0.1", []string{"1.1.1.1", "10.0.0.1"}},
+		{"IPv6: 2606:4700:4700::1111 time 12:30:45", []string{"2606:4700:4700::1111"}},
+		{"version 1.2.3.4.5 and 300.1.1.1", nil},
+		{"http://203.0.113.9:8080/path", []string{"http://203.0.113.9:8080/path"}},
+	}
+	for _, tt := range tests {
+		links := findTerminalLinks(tt.line)
+		if len(links) != len(tt.want) {
+			t.Fatalf("%q: links = %#v, want %q", tt.line, links, tt.want)
+		}
+		for i, link := range links {
+			if link.Text != tt.want[i] || tt.line[link.Start:link.End] != link.Text {
+				t.Fatalf("%q: link %d = %#v, want %q", tt.line, i, link, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestBuildTerminalRunsMarksLinks(t *testing.T) {
+	line := "IP: 1.1.1.1 ok"
+	runs := buildTerminalRuns(parseANSI(line), []terminalMatch{{Start: 4, End: 5}}, findTerminalLinks(line))
+	var linked string
+	for _, run := range runs {
+		if run.Link >= 0 {
+			linked += run.Text
+		}
+	}
+	if linked != "1.1.1.1" || runs[1].Text != "1" || runs[1].Match != 0 || runs[1].Link != 0 {
+		t.Fatalf("runs = %#v", runs)
+	}
+}
+
+func TestTerminalTapOnIPShowsActions(t *testing.T) {
+	app := test.NewTempApp(t)
+	app.Settings().SetTheme(NewCustomTheme(""))
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.SetFullText("Gateway: 192.0.2.1\n")
+	terminal.sync()
+	win := test.NewWindow(terminal)
+	t.Cleanup(win.Close)
+	win.Resize(fyne.NewSize(400, 200))
+
+	var asked string
+	terminal.IPActions = func(ip string) []*fyne.MenuItem {
+		asked = ip
+		return []*fyne.MenuItem{fyne.NewMenuItem("copy", nil)}
+	}
+	m := terminal.body.metrics()
+	x := m.pad + terminal.textWidth("Gateway: 19", m.text)
+	terminal.body.Tapped(&fyne.PointEvent{Position: fyne.NewPos(x, m.pad+m.row/2)})
+	if asked != "192.0.2.1" {
+		t.Fatalf("IPActions called with %q", asked)
+	}
+
+	asked = ""
+	terminal.body.Tapped(&fyne.PointEvent{Position: fyne.NewPos(m.pad+1, m.pad+m.row/2)})
+	if asked != "" {
+		t.Fatal("tapping plain text should not open the IP menu")
+	}
+}
@@ -0,0 +1,128 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	logKeepANSIPreferenceKey = "log_keep_ansi"
+	logAutoSavePreferenceKey = "log_auto_save"
+)
+
+// terminalLogFilename 生成形如 ecs_2024-06-01_hostname.log 的日志文件名
+func terminalLogFilename(host string, at time.Time) string {
+	host = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		}
+		return '_'
+	}, strings.TrimSpace(host))
+	if host == "" {
+		host = "localhost"
+	}
+	return fmt.Sprintf("ecs_%s_%s.log", at.Format("2006-01-02"), host)
+}
+
+// runHost 返回本次测试的目标主机名，本机测试时使用本机主机名
+func runHost(config ExecutionConfig) string {
+	if config.Docker != nil {
+		return config.Docker.Label()
+	}
+	if config.Remote != nil {
+		return config.Remote.Host
+	}
+	host, _ := os.Hostname()
+	return host
+}
+
+// terminalLogContent 返回完整的终端输出，keepANSI 为 true 时保留颜色控制序列
+func (ui *TestUI) terminalLogContent(keepANSI bool) string {
+	if ui.Terminal == nil {
+		return ""
+	}
+	return ui.Terminal.GetLogText(keepANSI)
+}
+
+func (ui *TestUI) lastHost() string {
+	ui.Mu.Lock()
+	host := ui.lastRunHost
+	ui.Mu.Unlock()
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+	return host
+}
+
+// saveTerminalLog 弹出保存对话框，把完整终端输出写入日志文件，隐私模式下先脱敏
+func (ui *TestUI) saveTerminalLog() {
+	keepANSI := ui.App.Preferences().Bool(logKeepANSIPreferenceKey)
+	content := ui.redactExport(ui.terminalLogContent(keepANSI))
+	if strings.TrimSpace(content) == "" {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
+		return
+	}
+	ui.saveExportFile(terminalLogFilename(ui.lastHost(), time.Now()), []byte(content))
+}
+
+// autoSaveTerminalLog 在测试结束后把日志保存到应用数据目录，重名时追加序号
+func (ui *TestUI) autoSaveTerminalLog(host string) (string, error) {
+	content := ui.terminalLogContent(ui.App.Preferences().Bool(logKeepANSIPreferenceKey))
+	if strings.TrimSpace(content) == "" {
+		return "", nil
+	}
+	dir := ui.appDataDir("logs")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	name := terminalLogFilename(host, time.Now())
+	base := strings.TrimSuffix(name, ".log")
+	for i := 2; ; i++ {
+		file, err := os.OpenFile(filepath.Join(dir, name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			name = fmt.Sprintf("%s_%d.log", base, i)
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		return file.Name(), err
+	}
+}
+
+// autoSaveAfterRun 在开启自动保存时写入日志，并在终端末尾提示保存位置
+func (ui *TestUI) autoSaveAfterRun(host string) {
+	if ui.App == nil || !ui.App.Preferences().Bool(logAutoSavePreferenceKey) {
+		return
+	}
+	path, err := ui.autoSaveTerminalLog(host)
+	switch {
+	case err != nil:
+		ui.Terminal.AppendText(fmt.Sprintf(ui.tr("log.autosave_failed"), err))
+	case path != "":
+		ui.Terminal.AppendText(fmt.Sprintf(ui.tr("log.autosaved"), path))
+	}
+}
+
+// createLogPreferenceChecks 创建“保留 ANSI 颜色代码”与“结束后自动保存日志”两个选项
+func (ui *TestUI) createLogPreferenceChecks() (*widget.Check, *widget.Check) {
+	prefs := ui.App.Preferences()
+	keepANSI := widget.NewCheck(ui.tr("check.log_keep_ansi"), nil)
+	keepANSI.SetChecked(prefs.Bool(logKeepANSIPreferenceKey))
+	keepANSI.OnChanged = func(on bool) { prefs.SetBool(logKeepANSIPreferenceKey, on) }
+	autoSave := widget.NewCheck(ui.tr("check.log_auto_save"), nil)
+	autoSave.SetChecked(prefs.Bool(logAutoSavePreferenceKey))
+	autoSave.OnChanged = func(on bool) { prefs.SetBool(logAutoSavePreferenceKey, on) }
+	return keepANSI, autoSave
+}
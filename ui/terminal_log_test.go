@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTerminalLogFilename(t *testing.T) {
+	at := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	if got := terminalLogFilename("vps-01.example.com", at); got != "ecs_2024-06-01_vps-01.example.com.log" {
+		t.Fatalf("filename = %q", got)
+	}
+	if got := terminalLogFilename("fe80::1%eth0", at); got != "ecs_2024-06-01_fe80__1_eth0.log" {
+		t.Fatalf("filename = %q", got)
+	}
+	if got := terminalLogFilename("", at); got != "ecs_2024-06-01_localhost.log" {
+		t.Fatalf("filename = %q", got)
+	}
+}
+
+func TestAutoSaveTerminalLogKeepsEarlierFiles(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("\x1b[32mCPU ok\x1b[0m\n")
+
+	first, err := ui.autoSaveTerminalLog("host")
+	if err != nil {
+		t.Fatalf("autoSaveTerminalLog() error = %v", err)
+	}
+	ui.App.Preferences().SetBool(logKeepANSIPreferenceKey, true)
+	second, err := ui.autoSaveTerminalLog("host")
+	if err != nil {
+		t.Fatalf("autoSaveTerminalLog() error = %v", err)
+	}
+	if first == second || !strings.HasSuffix(second, "_2.log") || filepath.Dir(first) != ui.appDataDir("logs") {
+		t.Fatalf("saved to %q and %q", first, second)
+	}
+	plain, _ := os.ReadFile(first)
+	raw, _ := os.ReadFile(second)
+	if string(plain) != "CPU ok\n" || string(raw) != "\x1b[32mCPU ok\x1b[0m\n" {
+		t.Fatalf("plain = %q, raw = %q", plain, raw)
+	}
+}
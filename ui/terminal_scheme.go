@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"image/color"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	terminalSchemePreferenceKey = "terminal_scheme"
+
+	// 终端专用的主题颜色名，由 CustomTheme 按当前配色方案解析
+	terminalColorBackground fyne.ThemeColorName = "terminal.background"
+	terminalColorForeground fyne.ThemeColorName = "terminal.foreground"
+)
+
+// terminalScheme 是终端配色方案：背景、默认文字颜色与标准 16 色
+type terminalScheme struct {
+	Background color.NRGBA
+	Foreground color.NRGBA
+	Palette    [16]color.NRGBA
+}
+
+func hexColor(v uint32) color.NRGBA {
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
+
+func hexPalette(values ...uint32) [16]color.NRGBA {
+	var palette [16]color.NRGBA
+	for i, v := range values {
+		palette[i] = hexColor(v)
+	}
+	return palette
+}
+
+var solarizedPalette = hexPalette(
+	0x073642, 0xdc322f, 0x859900, 0xb58900, 0x268bd2, 0xd33682, 0x2aa198, 0xeee8d5,
+	0x002b36, 0xcb4b16, 0x586e75, 0x657b83, 0x839496, 0x6c71c4, 0x93a1a1, 0xfdf6e3,
+)
+
+// terminalSchemes 按名称索引配色方案；空名称表示跟随应用主题
+var terminalSchemes = map[string]*terminalScheme{
+	"solarized_dark":  {Background: hexColor(0x002b36), Foreground: hexColor(0x839496), Palette: solarizedPalette},
+	"solarized_light": {Background: hexColor(0xfdf6e3), Foreground: hexColor(0x657b83), Palette: solarizedPalette},
+	"dracula": {
+		Background: hexColor(0x282a36),
+		Foreground: hexColor(0xf8f8f2),
+		Palette: hexPalette(
+			0x21222c, 0xff5555, 0x50fa7b, 0xf1fa8c, 0xbd93f9, 0xff79c6, 0x8be9fd, 0xf8f8f2,
+			0x6272a4, 0xff6e6e, 0x69ff94, 0xffffa5, 0xd6acff, 0xff92df, 0xa4ffff, 0xffffff,
+		),
+	},
+	"green": {Background: hexColor(0x000000), Foreground: hexColor(0x33ff33), Palette: ansiPaletteDark},
+}
+
+// terminalSchemeNames 是配色方案在设置中的显示顺序
+var terminalSchemeNames = []string{"", "solarized_dark", "solarized_light", "dracula", "green"}
+
+// terminalSchemeColor 解析终端使用的颜色；scheme 为 nil 时跟随应用主题
+func terminalSchemeColor(scheme *terminalScheme, name fyne.ThemeColorName, variant fyne.ThemeVariant) (color.Color, bool) {
+	switch name {
+	case terminalColorBackground:
+		if scheme == nil {
+			return color.Transparent, true
+		}
+		return scheme.Background, true
+	case terminalColorForeground:
+		if scheme == nil {
+			return theme.DefaultTheme().Color(theme.ColorNameForeground, variant), true
+		}
+		return scheme.Foreground, true
+	}
+	if scheme != nil {
+		if idx, err := strconv.Atoi(strings.TrimPrefix(string(name), ansiColorPrefix)); err == nil && idx >= 0 && idx < 16 {
+			return scheme.Palette[idx], true
+		}
+	}
+	return ansiThemeColor(name, variant)
+}
+
+func (ui *TestUI) terminalSchemeLabel(name string) string {
+	if name == "" {
+		return ui.tr("scheme.default")
+	}
+	return ui.tr("scheme." + name)
+}
+
+// createTerminalSchemeSelect 创建终端配色方案选项，切换后立即应用并保存
+func (ui *TestUI) createTerminalSchemeSelect() *widget.Select {
+	labels := make([]string, len(terminalSchemeNames))
+	for i, name := range terminalSchemeNames {
+		labels[i] = ui.terminalSchemeLabel(name)
+	}
+	selectWidget := widget.NewSelect(labels, nil)
+	selectWidget.SetSelected(ui.terminalSchemeLabel(ui.terminalScheme))
+	selectWidget.OnChanged = func(label string) {
+		for i, candidate := range labels {
+			if candidate == label {
+				ui.applyTerminalScheme(terminalSchemeNames[i])
+			}
+		}
+	}
+	return selectWidget
+}
+
+// applyTerminalScheme 保存配色方案并重新应用主题
+func (ui *TestUI) applyTerminalScheme(name string) {
+	if _, ok := terminalSchemes[name]; !ok {
+		name = ""
+	}
+	ui.terminalScheme = name
+	if ui.App != nil {
+		ui.App.Preferences().SetString(terminalSchemePreferenceKey, name)
+	}
+	ui.applyThemeMode(ui.themeMode)
+}
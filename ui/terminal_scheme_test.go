@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"image/color"
+	"testing"
+
+	"fyne.io/fyne/v2/theme"
+)
+
+func TestTerminalSchemeColors(t *testing.T) {
+	dracula := terminalSchemes["dracula"]
+	if c, _ := terminalSchemeColor(dracula, terminalColorBackground, theme.VariantLight); c != hexColor(0x282a36) {
+		t.Fatalf("dracula background = %v", c)
+	}
+	if c, _ := terminalSchemeColor(dracula, ansiColor{Kind: ansiColorIndexed, Index: 1}.ColorName(), theme.VariantLight); c != hexColor(0xff5555) {
+		t.Fatalf("dracula red = %v", c)
+	}
+	// 256 色与真彩色不受配色方案影响
+	if c, _ := terminalSchemeColor(dracula, ansiColor{Kind: ansiColorIndexed, Index: 196}.ColorName(), theme.VariantLight); c != ansiIndexedColor(196, theme.VariantLight) {
+		t.Fatalf("indexed 196 = %v", c)
+	}
+	if c, _ := terminalSchemeColor(nil, terminalColorBackground, theme.VariantDark); c != color.Transparent {
+		t.Fatalf("default background = %v, want transparent", c)
+	}
+	if _, ok := terminalSchemeColor(dracula, theme.ColorNamePrimary, theme.VariantDark); ok {
+		t.Fatal("non-terminal colors must fall through to the app theme")
+	}
+}
+
+func TestApplyTerminalSchemePersistsAndThemes(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.applyThemeMode(themeModeSystem)
+	ui.applyTerminalScheme("solarized_dark")
+
+	if got := ui.App.Preferences().String(terminalSchemePreferenceKey); got != "solarized_dark" {
+		t.Fatalf("saved scheme = %q", got)
+	}
+	th, ok := ui.App.Settings().Theme().(*CustomTheme)
+	if !ok || th.scheme != terminalSchemes["solarized_dark"] || th.forceVariant {
+		t.Fatalf("theme = %#v", ui.App.Settings().Theme())
+	}
+	if c := th.Color(terminalColorForeground, theme.VariantLight); c != hexColor(0x839496) {
+		t.Fatalf("terminal foreground = %v", c)
+	}
+	if ui.themeModeByLabel(ui.themeLabelByMode(themeModeSystem)) != themeModeSystem {
+		t.Fatal("system theme label does not round-trip")
+	}
+
+	ui.applyTerminalScheme("unknown")
+	if ui.terminalScheme != "" {
+		t.Fatalf("unknown scheme kept as %q", ui.terminalScheme)
+	}
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxTerminalMatches 限制单次搜索的命中数量，避免超长输出下高亮片段过多
+const maxTerminalMatches = 5000
+
+type terminalSearchOptions struct {
+	Query      string
+	IgnoreCase bool
+	Regex      bool
+}
+
+// terminalMatch 是第 Row 个显示行纯文本中的一个命中区间 [Start, End)
+type terminalMatch struct {
+	Row   int
+	Start int
+	End   int
+}
+
+// compileTerminalSearch 将搜索条件编译为正则表达式
+func compileTerminalSearch(opts terminalSearchOptions) (*regexp.Regexp, error) {
+	pattern := opts.Query
+	if !opts.Regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if opts.IgnoreCase {
+		pattern = "(?i)" + pattern
+	}
+	return regexp.Compile(pattern)
+}
+
+// findTerminalMatches 在纯文本中查找所有命中，忽略零长度匹配
+func findTerminalMatches(text string, opts terminalSearchOptions) ([]terminalMatch, error) {
+	if opts.Query == "" {
+		return nil, nil
+	}
+	re, err := compileTerminalSearch(opts)
+	if err != nil {
+		return nil, err
+	}
+	var matches []terminalMatch
+	for _, loc := range re.FindAllStringIndex(text, maxTerminalMatches) {
+		if loc[1] > loc[0] {
+			matches = append(matches, terminalMatch{Start: loc[0], End: loc[1]})
+		}
+	}
+	return matches, nil
+}
+
+// SetSearch 设置搜索条件并重新高亮，返回命中数量（需在 UI 线程调用）
+func (t *TerminalOutput) SetSearch(opts terminalSearchOptions) (int, error) {
+	if opts.Query != "" {
+		if _, err := compileTerminalSearch(opts); err != nil {
+			return 0, err
+		}
+	}
+	t.search = opts
+	t.activeMatch = 0
+	t.sync()
+	return len(t.matches), nil
+}
+
+// StepMatch 向前或向后移动当前命中，返回当前序号（从 1 开始）与总数
+func (t *TerminalOutput) StepMatch(delta int) (int, int) {
+	total := len(t.matches)
+	if total == 0 {
+		return 0, 0
+	}
+	t.activeMatch = ((t.activeMatch+delta)%total + total) % total
+	t.generation++
+	t.body.Refresh()
+	return t.activeMatch + 1, total
+}
+
+// ActiveMatchRow 返回当前命中所在的显示行
+func (t *TerminalOutput) ActiveMatchRow() (int, bool) {
+	if t.activeMatch < 0 || t.activeMatch >= len(t.matches) {
+		return 0, false
+	}
+	return t.matches[t.activeMatch].Row, true
+}
+
+// ActiveMatchFraction 返回当前命中所在行相对全文的位置（0-1）
+func (t *TerminalOutput) ActiveMatchFraction() (float32, bool) {
+	row, ok := t.ActiveMatchRow()
+	if !ok {
+		return 0, false
+	}
+	return float32(row) / float32(max(t.rowCount(), 1)), true
+}
+
+// rowMatches 返回第 row 个显示行内的命中，以及其中第一个命中的全局序号
+func (t *TerminalOutput) rowMatches(row int) ([]terminalMatch, int) {
+	first := sort.Search(len(t.matches), func(i int) bool { return t.matches[i].Row >= row })
+	end := first
+	for end < len(t.matches) && t.matches[end].Row == row {
+		end++
+	}
+	return t.matches[first:end], first
+}
+
+// refreshSearchMatches 在内容或过滤条件变化后逐行重新计算命中，第 from 行之前的命中沿用上次的结果
+func (t *TerminalOutput) refreshSearchMatches(from int) {
+	first := t.firstRowOf(from)
+	t.matches = t.matches[:sort.Search(len(t.matches), func(i int) bool { return t.matches[i].Row >= first })]
+	if t.search.Query != "" {
+		if re, err := compileTerminalSearch(t.search); err == nil {
+			count := t.rowCount()
+			for row := first; row < count && len(t.matches) < maxTerminalMatches; row++ {
+				for _, loc := range re.FindAllStringIndex(t.row(row).plain, maxTerminalMatches-len(t.matches)) {
+					if loc[1] > loc[0] {
+						t.matches = append(t.matches, terminalMatch{Row: row, Start: loc[0], End: loc[1]})
+					}
+				}
+			}
+		}
+	}
+	if t.activeMatch >= len(t.matches) {
+		t.activeMatch = max(len(t.matches)-1, 0)
+	}
+	if t.search.Query != "" && t.OnSearchUpdate != nil {
+		current := 0
+		if len(t.matches) > 0 {
+			current = t.activeMatch + 1
+		}
+		t.OnSearchUpdate(current, len(t.matches))
+	}
+}
+
+// createTerminalSearchBar 创建终端输出上方的搜索栏，默认隐藏
+func (ui *TestUI) createTerminalSearchBar() fyne.CanvasObject {
+	ui.searchEntry = widget.NewEntry()
+	ui.searchEntry.SetPlaceHolder(ui.tr("search.placeholder"))
+	ui.searchStatus = widget.NewLabel("")
+	ui.searchIgnoreCase = widget.NewCheck(ui.tr("search.ignore_case"), nil)
+	ui.searchIgnoreCase.SetChecked(true)
+	ui.searchIgnoreCase.OnChanged = func(bool) { ui.applyTerminalSearch() }
+	ui.searchRegex = widget.NewCheck(ui.tr("search.regex"), func(bool) { ui.applyTerminalSearch() })
+
+	ui.searchEntry.OnChanged = func(string) { ui.applyTerminalSearch() }
+	ui.searchEntry.OnSubmitted = func(string) { ui.stepTerminalSearch(1) }
+	ui.Terminal.OnSearchUpdate = func(current, total int) {
+		ui.updateSearchStatus(current, total)
+	}
+
+	prevButton := widget.NewButtonWithIcon("", theme.MoveUpIcon(), func() { ui.stepTerminalSearch(-1) })
+	nextButton := widget.NewButtonWithIcon("", theme.MoveDownIcon(), func() { ui.stepTerminalSearch(1) })
+	closeButton := widget.NewButtonWithIcon("", theme.CancelIcon(), ui.hideTerminalSearch)
+
+	controls := container.NewHBox(ui.searchStatus, ui.searchIgnoreCase, ui.searchRegex, prevButton, nextButton, closeButton)
+	ui.searchBar = container.NewBorder(nil, nil, nil, controls, ui.searchEntry)
+	ui.searchBar.Hide()
+	return ui.searchBar
+}
+
+func (ui *TestUI) showTerminalSearch() {
+	if ui.searchBar == nil {
+		return
+	}
+	ui.showResultTab()
+	ui.searchBar.Show()
+	if ui.Window != nil {
+		ui.Window.Canvas().Focus(ui.searchEntry)
+	}
+	ui.applyTerminalSearch()
+}
+
+func (ui *TestUI) hideTerminalSearch() {
+	if ui.searchBar == nil {
+		return
+	}
+	ui.searchBar.Hide()
+	_, _ = ui.Terminal.SetSearch(terminalSearchOptions{})
+	ui.searchStatus.SetText("")
+}
+
+func (ui *TestUI) applyTerminalSearch() {
+	if ui.searchEntry == nil || ui.Terminal == nil {
+		return
+	}
+	total, err := ui.Terminal.SetSearch(terminalSearchOptions{
+		Query:      ui.searchEntry.Text,
+		IgnoreCase: ui.searchIgnoreCase.Checked,
+		Regex:      ui.searchRegex.Checked,
+	})
+	if err != nil {
+		ui.searchStatus.SetText(ui.tr("search.invalid"))
+		return
+	}
+	current := 0
+	if total > 0 {
+		current = 1
+	}
+	ui.updateSearchStatus(current, total)
+	ui.scrollToActiveMatch()
+}
+
+func (ui *TestUI) stepTerminalSearch(delta int) {
+	if ui.Terminal == nil {
+		return
+	}
+	current, total := ui.Terminal.StepMatch(delta)
+	ui.updateSearchStatus(current, total)
+	ui.scrollToActiveMatch()
+}
+
+func (ui *TestUI) updateSearchStatus(current, total int) {
+	if ui.searchStatus == nil || ui.searchEntry == nil {
+		return
+	}
+	switch {
+	case ui.searchEntry.Text == "":
+		ui.searchStatus.SetText("")
+	case total == 0:
+		ui.searchStatus.SetText(ui.tr("search.none"))
+	default:
+		ui.searchStatus.SetText(fmt.Sprintf("%d/%d", current, total))
+	}
+}
+
+// scrollToActiveMatch 将终端滚动到当前命中所在行，并留出约三分之一视口的上文
+func (ui *TestUI) scrollToActiveMatch() {
+	if ui.Terminal == nil {
+		return
+	}
+	row, ok := ui.Terminal.ActiveMatchRow()
+	if !ok {
+		return
+	}
+	// 定位到搜索结果时暂停跟随，避免新输出把视图拉回底部
+	if ui.terminalFollow != nil {
+		ui.terminalFollow.SetFollowing(false)
+	}
+	ui.Terminal.ScrollToRow(row)
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"testing"
+)
+
+func TestFindTerminalMatchesModes(t *testing.T) {
+	text := "Geekbench 6\ngeekbench score: 1234\nSpeedtest node"
+	tests := []struct {
+		name string
+		opts terminalSearchOptions
+		want int
+	}{
+		{name: "case sensitive", opts: terminalSearchOptions{Query: "Geekbench"}, want: 1},
+		{name: "ignore case", opts: terminalSearchOptions{Query: "geekbench", IgnoreCase: true}, want: 2},
+		{name: "regex", opts: terminalSearchOptions{Query: `\d+`, Regex: true}, want: 2},
+		{name: "literal metacharacters", opts: terminalSearchOptions{Query: `\d+`}, want: 0},
+		{name: "empty match ignored", opts: terminalSearchOptions{Query: `x*`, Regex: true}, want: 0},
+	}
+	for _, tt := range tests {
+		matches, err := findTerminalMatches(text, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: unexpected error %v", tt.name, err)
+		}
+		if len(matches) != tt.want {
+			t.Fatalf("%s: matches = %#v, want %d", tt.name, matches, tt.want)
+		}
+	}
+	if _, err := findTerminalMatches(text, terminalSearchOptions{Query: "(", Regex: true}); err == nil {
+		t.Fatal("invalid regex should return an error")
+	}
+}
+
+func TestBuildTerminalRunsSplitsHighlights(t *testing.T) {
+	parsed := parseANSI("ab\x1b[31mcdef\x1b[0mgh")
+	runs := buildTerminalRuns(parsed, []terminalMatch{{Start: 1, End: 3}, {Start: 5, End: 7}}, nil)
+	want := []struct {
+		text  string
+		match int
+	}{{"a", -1}, {"b", 0}, {"c", 0}, {"de", -1}, {"f", 1}, {"g", 1}, {"h", -1}}
+	if len(runs) != len(want) {
+		t.Fatalf("runs = %#v", runs)
+	}
+	for i := range want {
+		if runs[i].Text != want[i].text || runs[i].Match != want[i].match {
+			t.Fatalf("run %d = %#v, want %q match %d", i, runs[i], want[i].text, want[i].match)
+		}
+	}
+	if runs[2].Style.FG.Kind == ansiColorDefault {
+		t.Fatal("highlighted run should keep its ANSI color")
+	}
+}
+
+func TestTerminalSearchNavigationWraps(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.Terminal.SetFullText("node A\nnode B\nnode C\n")
+	ui.Terminal.sync()
+
+	total, err := ui.Terminal.SetSearch(terminalSearchOptions{Query: "NODE", IgnoreCase: true})
+	if err != nil || total != 3 {
+		t.Fatalf("SetSearch() = %d, %v", total, err)
+	}
+	if current, _ := ui.Terminal.StepMatch(-1); current != 3 {
+		t.Fatalf("previous from first match = %d, want 3", current)
+	}
+	if current, _ := ui.Terminal.StepMatch(1); current != 1 {
+		t.Fatalf("next from last match = %d, want 1", current)
+	}
+	fraction, ok := ui.Terminal.ActiveMatchFraction()
+	if !ok || fraction != 0 {
+		t.Fatalf("ActiveMatchFraction() = %v, %v", fraction, ok)
+	}
+	if row, ok := ui.Terminal.ActiveMatchRow(); !ok || row != 0 {
+		t.Fatalf("ActiveMatchRow() = %d, %v", row, ok)
+	}
+	if current, _ := ui.Terminal.StepMatch(2); current != 3 {
+		t.Fatalf("StepMatch(2) = %d, want 3", current)
+	}
+	if matches, first := ui.Terminal.rowMatches(2); len(matches) != 1 || first != 2 {
+		t.Fatalf("rowMatches(2) = %v, %d", matches, first)
+	}
+}
@@ -0,0 +1,275 @@
+package ui
+
+import (
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// terminalPos 是显示内容中的位置：Row 为显示行号，Col 为该行纯文本中的字节偏移
+type terminalPos struct {
+	Row int
+	Col int
+}
+
+func (p terminalPos) before(o terminalPos) bool {
+	return p.Row < o.Row || (p.Row == o.Row && p.Col < o.Col)
+}
+
+// terminalSelection 是用户拖动选择的区间，Anchor 为起点，Cursor 随鼠标移动
+type terminalSelection struct {
+	Anchor terminalPos
+	Cursor terminalPos
+	Active bool
+}
+
+// bounds 返回按先后排序的起止位置
+func (s terminalSelection) bounds() (terminalPos, terminalPos) {
+	if s.Cursor.before(s.Anchor) {
+		return s.Cursor, s.Anchor
+	}
+	return s.Anchor, s.Cursor
+}
+
+func (s terminalSelection) empty() bool {
+	return !s.Active || s.Anchor == s.Cursor
+}
+
+// textWidth 返回文本按终端字体绘制时的宽度，制表符按 4 个空格计
+func (t *TerminalOutput) textWidth(text string, size float32) float32 {
+	return t.measure(strings.ReplaceAll(text, "\t", "    "), size, fyne.TextStyle{Monospace: true}).Width
+}
+
+// textOffsetAt 返回横坐标 x 处最接近的字符边界（字节偏移）
+func (t *TerminalOutput) textOffsetAt(text string, x, size float32) int {
+	width := float32(0)
+	for i, r := range text {
+		w := t.textWidth(string(r), size)
+		if x < width+w/2 {
+			return i
+		}
+		width += w
+	}
+	return len(text)
+}
+
+// SelectedText 返回当前选中的纯文本，跨行选择以换行连接（需在 UI 线程调用）
+func (t *TerminalOutput) SelectedText() string {
+	if t.selection.empty() {
+		return ""
+	}
+	start, end := t.selection.bounds()
+	var b strings.Builder
+	for row := start.Row; row <= end.Row && row < t.rowCount(); row++ {
+		plain := t.row(row).plain
+		from, to := 0, len(plain)
+		if row == start.Row {
+			from = min(start.Col, len(plain))
+		}
+		if row == end.Row {
+			to = min(end.Col, len(plain))
+		}
+		// 自动换行产生的续行与上一段属于同一行
+		if first, _ := t.rowSpan(row); row > start.Row && first {
+			b.WriteByte('\n')
+		}
+		if from < to {
+			b.WriteString(plain[from:to])
+		}
+	}
+	return b.String()
+}
+
+// SelectAll 选中全部显示内容
+func (t *TerminalOutput) SelectAll() {
+	count := t.rowCount()
+	if count == 0 {
+		return
+	}
+	t.setSelection(terminalSelection{
+		Cursor: terminalPos{Row: count - 1, Col: len(t.row(count - 1).plain)},
+		Active: true,
+	})
+}
+
+// ClearSelection 取消选择
+func (t *TerminalOutput) ClearSelection() {
+	if t.selection.Active {
+		t.setSelection(terminalSelection{})
+	}
+}
+
+func (t *TerminalOutput) setSelection(selection terminalSelection) {
+	t.selection = selection
+	t.generation++
+	t.body.Refresh()
+}
+
+// CopySelection 把选中内容复制到剪贴板，没有选择时不做任何事
+func (t *TerminalOutput) CopySelection() {
+	if text := t.SelectedText(); text != "" {
+		fyne.CurrentApp().Clipboard().SetContent(text)
+	}
+}
+
+// CopyAll 把完整输出（包括已转存和被过滤隐藏的行）复制到剪贴板，隐私模式下先脱敏
+func (t *TerminalOutput) CopyAll() {
+	fyne.CurrentApp().Clipboard().SetContent(t.redactText(t.GetText()))
+}
+
+// rowSelection 返回第 row 行被选中的字节区间；toEnd 表示选择延续到下一行
+func (t *TerminalOutput) rowSelection(row int) (from, to int, toEnd, ok bool) {
+	if t.selection.empty() {
+		return 0, 0, false, false
+	}
+	start, end := t.selection.bounds()
+	if row < start.Row || row > end.Row {
+		return 0, 0, false, false
+	}
+	plain := t.row(row).plain
+	from, to = 0, len(plain)
+	if row == start.Row {
+		from = min(start.Col, len(plain))
+	}
+	if row == end.Row {
+		to = min(end.Col, len(plain))
+	}
+	return from, to, row < end.Row, true
+}
+
+// posAt 把内容层坐标转换为显示位置，超出范围时吸附到最近的行
+func (t *TerminalOutput) posAt(pos fyne.Position) terminalPos {
+	count := t.rowCount()
+	if count == 0 {
+		return terminalPos{}
+	}
+	m := t.body.metrics()
+	row := int(math.Floor(float64((pos.Y - m.pad) / m.row)))
+	if row < 0 {
+		return terminalPos{}
+	}
+	if row >= count {
+		return terminalPos{Row: count - 1, Col: len(t.row(count - 1).plain)}
+	}
+	return terminalPos{Row: row, Col: t.textOffsetAt(t.row(row).plain, pos.X-m.pad-m.indent(), m.text)}
+}
+
+// showContextMenu 在 e 处弹出复制菜单，开启书签时附带所指行的书签操作
+func (t *TerminalOutput) showContextMenu(e *fyne.PointEvent) {
+	c := fyne.CurrentApp().Driver().CanvasForObject(t.body)
+	if c == nil {
+		return
+	}
+	copyItem := fyne.NewMenuItem(t.tr("terminal.copy"), t.CopySelection)
+	copyItem.Disabled = t.selection.empty()
+	menu := fyne.NewMenu("",
+		copyItem,
+		fyne.NewMenuItem(t.tr("terminal.copy_all"), t.CopyAll),
+		fyne.NewMenuItem(t.tr("terminal.select_all"), t.SelectAll),
+	)
+	if t.Bookmarkable && t.rowCount() > 0 {
+		menu.Items = append(append(menu.Items, fyne.NewMenuItemSeparator()), t.bookmarkMenuItems(t.posAt(e.Position).Row)...)
+	}
+	if t.Foldable {
+		menu.Items = append(append(menu.Items, fyne.NewMenuItemSeparator()), t.foldMenuItems()...)
+	}
+	widget.ShowPopUpMenuAtPosition(menu, c, e.AbsolutePosition)
+}
+
+func (t *TerminalOutput) tr(key string) string {
+	if t.Translate != nil {
+		return t.Translate(key)
+	}
+	return key
+}
+
+var (
+	_ fyne.Draggable         = (*terminalBody)(nil)
+	_ fyne.Tappable          = (*terminalBody)(nil)
+	_ fyne.DoubleTappable    = (*terminalBody)(nil)
+	_ fyne.SecondaryTappable = (*terminalBody)(nil)
+	_ fyne.Focusable         = (*terminalBody)(nil)
+	_ fyne.Shortcutable      = (*terminalBody)(nil)
+)
+
+// Tapped 点击书签列时添加或移除书签，点击折叠标记时折叠或展开该阶段，点击链接时打开链接，
+// 否则获取键盘焦点并取消选择
+func (b *terminalBody) Tapped(e *fyne.PointEvent) {
+	if line, ok := b.term.markAt(e.Position); ok {
+		b.term.ToggleBookmark(line)
+		return
+	}
+	if line, ok := b.term.foldAt(e.Position); ok {
+		b.term.ToggleFold(line)
+		return
+	}
+	if link, ok := b.term.linkAt(e.Position); ok {
+		b.term.openLink(link, e.AbsolutePosition)
+		return
+	}
+	if c := fyne.CurrentApp().Driver().CanvasForObject(b); c != nil {
+		c.Focus(b)
+	}
+	b.term.ClearSelection()
+}
+
+// DoubleTapped 选中整行
+func (b *terminalBody) DoubleTapped(e *fyne.PointEvent) {
+	pos := b.term.posAt(e.Position)
+	if b.term.rowCount() == 0 {
+		return
+	}
+	b.term.setSelection(terminalSelection{
+		Anchor: terminalPos{Row: pos.Row},
+		Cursor: terminalPos{Row: pos.Row, Col: len(b.term.row(pos.Row).plain)},
+		Active: true,
+	})
+}
+
+// TappedSecondary 弹出右键菜单
+func (b *terminalBody) TappedSecondary(e *fyne.PointEvent) {
+	b.term.showContextMenu(e)
+}
+
+// Dragged 按下并拖动鼠标时扩展选择
+func (b *terminalBody) Dragged(e *fyne.DragEvent) {
+	term := b.term
+	if !b.dragging {
+		b.dragging = true
+		if c := fyne.CurrentApp().Driver().CanvasForObject(b); c != nil {
+			c.Focus(b)
+		}
+		anchor := term.posAt(e.Position.Subtract(e.Dragged))
+		term.selection = terminalSelection{Anchor: anchor, Cursor: anchor, Active: true}
+	}
+	cursor := term.posAt(e.Position)
+	if cursor != term.selection.Cursor {
+		term.selection.Cursor = cursor
+		term.setSelection(term.selection)
+	}
+}
+
+func (b *terminalBody) DragEnd() {
+	b.dragging = false
+}
+
+func (b *terminalBody) FocusGained()            {}
+func (b *terminalBody) FocusLost()              {}
+func (b *terminalBody) TypedRune(rune)          {}
+func (b *terminalBody) TypedKey(*fyne.KeyEvent) {}
+
+// TypedShortcut 处理复制与全选，其他快捷键（如 Ctrl+F）交还给窗口
+func (b *terminalBody) TypedShortcut(s fyne.Shortcut) {
+	switch s.(type) {
+	case *fyne.ShortcutCopy:
+		b.term.CopySelection()
+	case *fyne.ShortcutSelectAll:
+		b.term.SelectAll()
+	default:
+		if c, ok := fyne.CurrentApp().Driver().CanvasForObject(b).(fyne.Shortcutable); ok {
+			c.TypedShortcut(s)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestTerminalDragSelectionAndCopy(t *testing.T) {
+	app := test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.SetFullText("\x1b[32mCPU score: 1000\x1b[0m\nMemory: 20 GB/s\nDisk ok\n")
+	terminal.sync()
+	win := test.NewWindow(terminal)
+	t.Cleanup(win.Close)
+	win.Resize(fyne.NewSize(400, 300))
+
+	m := terminal.body.metrics()
+	at := func(row, col int) fyne.Position {
+		x := terminal.textWidth(terminal.row(row).plain[:col], m.text)
+		return fyne.NewPos(m.pad+x+1, m.pad+float32(row)*m.row+m.row/2)
+	}
+	start, end := at(0, 4), at(1, 6)
+	terminal.body.Dragged(&fyne.DragEvent{PointEvent: fyne.PointEvent{Position: end}, Dragged: fyne.NewDelta(end.X-start.X, end.Y-start.Y)})
+	terminal.body.DragEnd()
+	if got := terminal.SelectedText(); got != "score: 1000\nMemory" {
+		t.Fatalf("SelectedText() = %q", got)
+	}
+
+	terminal.body.TypedShortcut(&fyne.ShortcutCopy{})
+	if got := app.Clipboard().Content(); got != "score: 1000\nMemory" {
+		t.Fatalf("clipboard = %q after Ctrl+C", got)
+	}
+
+	terminal.body.TypedShortcut(&fyne.ShortcutSelectAll{})
+	if got := terminal.SelectedText(); got != "CPU score: 1000\nMemory: 20 GB/s\nDisk ok" {
+		t.Fatalf("SelectedText() after select all = %q", got)
+	}
+
+	terminal.body.DoubleTapped(&fyne.PointEvent{Position: at(2, 1)})
+	if got := terminal.SelectedText(); got != "Disk ok" {
+		t.Fatalf("double tap selected %q, want the whole line", got)
+	}
+
+	terminal.body.Tapped(&fyne.PointEvent{Position: at(0, 0)})
+	if terminal.SelectedText() != "" {
+		t.Fatal("tapping should clear the selection")
+	}
+
+	terminal.CopyAll()
+	if got := app.Clipboard().Content(); got != terminal.GetText() {
+		t.Fatalf("copy all = %q", got)
+	}
+}
@@ -0,0 +1,240 @@
+package ui
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"golang.org/x/text/width"
+)
+
+// terminalMinWrapCols 是自动换行的最小列数，窗口过窄时按该宽度换行
+const terminalMinWrapCols = 20
+
+// terminalSegment 是自动换行后的一个显示行：第 line 行纯文本的 [from, to) 部分
+type terminalSegment struct {
+	line, from, to int
+}
+
+// wrapTerminalText 按显示列数 cols 切分一行，返回每段的结束位置（字节偏移）；
+// 优先在空格后断开，一段内没有合适的空格时按列数硬断开
+func wrapTerminalText(plain string, cols int) []int {
+	var cuts []int
+	start, used, space := 0, 0, -1
+	for i, r := range plain {
+		w := 1
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			w = 2
+		}
+		if used+w > cols && i > start {
+			cut := i
+			if space > start {
+				cut = space
+			}
+			cuts = append(cuts, cut)
+			used = displayColumns(plain[cut:i])
+			start, space = cut, -1
+		}
+		used += w
+		if r == ' ' {
+			space = i + 1
+		}
+	}
+	return append(cuts, len(plain))
+}
+
+// sliceTerminalLine 返回由 line 纯文本 [from, to) 部分组成的行，颜色与原行一致
+func sliceTerminalLine(line terminalLine, from, to int) terminalLine {
+	p := ansiParser{style: line.style}
+	var b strings.Builder
+	var start, style ansiStyle
+	started, pos := false, 0
+	for _, seg := range p.Parse(line.raw) {
+		end := pos + len(seg.Text)
+		if lo, hi := max(from, pos), min(to, end); lo < hi {
+			switch {
+			case !started:
+				start, style, started = seg.Style, seg.Style, true
+			case seg.Style != style:
+				b.WriteString(sgrSequence(seg.Style))
+				style = seg.Style
+			}
+			b.WriteString(seg.Text[lo-pos : hi-pos])
+		}
+		pos = end
+	}
+	p = ansiParser{style: start}
+	sliced := newTerminalLine(&p, b.String())
+	sliced.at = line.at
+	return sliced
+}
+
+// wrapColumns 返回按当前可见宽度自动换行的列数
+func (t *TerminalOutput) wrapColumns() int {
+	m := t.body.metrics()
+	w := t.scroll.Size().Width - 2*m.pad - m.indent() - t.body.Theme().Size(theme.SizeNameScrollBar)
+	return max(int(math.Floor(float64(w/m.char))), terminalMinWrapCols)
+}
+
+// wrapRows 按当前宽度把显示的行切分为显示段，未开启自动换行时返回 nil；
+// 宽度不变时 from 之前的行沿用上次的结果
+func (t *TerminalOutput) wrapRows(from int) []terminalSegment {
+	if !t.wrap {
+		return nil
+	}
+	if cols := t.wrapColumns(); cols != t.wrapCols || t.wrapped == nil {
+		t.wrapCols, from = cols, 0
+	}
+	segments := t.wrapped[:sort.Search(len(t.wrapped), func(i int) bool { return t.wrapped[i].line >= from })]
+	first, count := from, t.lineCount()
+	if t.rows != nil {
+		first, count = sort.SearchInts(t.rows, from), len(t.rows)
+	}
+	for i := first; i < count; i++ {
+		index := i
+		if t.rows != nil {
+			index = t.rows[i]
+		}
+		line := t.line(index)
+		if line.cols <= t.wrapCols {
+			segments = append(segments, terminalSegment{index, 0, len(line.plain)})
+			continue
+		}
+		from := 0
+		for _, to := range wrapTerminalText(line.plain, t.wrapCols) {
+			segments = append(segments, terminalSegment{index, from, to})
+			from = to
+		}
+	}
+	return segments
+}
+
+// rewrap 在可见宽度变化后重新换行（需在 UI 线程调用）
+func (t *TerminalOutput) rewrap() {
+	if !t.wrap || t.wrapColumns() == t.wrapCols {
+		return
+	}
+	t.wrapped = t.wrapRows(0)
+	t.selection = terminalSelection{}
+	t.refreshSearchMatches(0)
+	t.generation++
+	t.body.Refresh()
+}
+
+// segmentRow 返回第 line 行中包含字节偏移 col 的显示行
+func (t *TerminalOutput) segmentRow(line, col int) (int, bool) {
+	if t.wrapped == nil {
+		if t.rows == nil {
+			return line, line >= 0 && line < t.lineCount()
+		}
+		row := sort.SearchInts(t.rows, line)
+		return row, row < len(t.rows) && t.rows[row] == line
+	}
+	row := sort.Search(len(t.wrapped), func(i int) bool {
+		seg := t.wrapped[i]
+		return seg.line > line || (seg.line == line && seg.to > col)
+	})
+	if row == len(t.wrapped) || t.wrapped[row].line != line {
+		if row > 0 && t.wrapped[row-1].line == line {
+			return row - 1, true
+		}
+		return 0, false
+	}
+	return row, true
+}
+
+// firstRowOf 返回第 line 行之前的显示行数，即第 line 行（或其后第一个显示的行）的显示行号
+func (t *TerminalOutput) firstRowOf(line int) int {
+	switch {
+	case t.wrapped != nil:
+		return sort.Search(len(t.wrapped), func(i int) bool { return t.wrapped[i].line >= line })
+	case t.rows != nil:
+		return sort.SearchInts(t.rows, line)
+	}
+	return min(line, t.lineCount())
+}
+
+// rowSpan 报告第 row 个显示行是否为所在行的第一段与最后一段
+func (t *TerminalOutput) rowSpan(row int) (first, last bool) {
+	if t.wrapped == nil {
+		return true, true
+	}
+	seg := t.wrapped[row]
+	return seg.from == 0, row+1 == len(t.wrapped) || t.wrapped[row+1].line != seg.line
+}
+
+// SetWrap 切换自动换行，切换前后顶部可见的内容保持不变，并记住不换行时的横向滚动位置（需在 UI 线程调用）
+func (t *TerminalOutput) SetWrap(on bool) {
+	if on == t.wrap {
+		return
+	}
+	m := t.body.metrics()
+	offset := t.scroll.Offset
+	line, col, anchored := 0, 0, false
+	if top := int(math.Floor(float64((offset.Y - m.pad) / m.row))); top >= 0 && top < t.rowCount() {
+		line, anchored = t.rowLine(top), true
+		if t.wrapped != nil {
+			col = t.wrapped[top].from
+		}
+	}
+	if on {
+		t.hscroll = offset.X
+		t.scroll.Direction = container.ScrollVerticalOnly
+	} else {
+		t.scroll.Direction = container.ScrollBoth
+	}
+	t.wrap = on
+	t.selection = terminalSelection{}
+	t.sync()
+	t.scroll.Refresh()
+
+	x := float32(0)
+	if !on {
+		x = t.hscroll
+	}
+	y := float32(0)
+	if row, ok := t.segmentRow(line, col); ok && anchored {
+		y = m.pad + float32(row)*m.row
+	}
+	t.scroll.ScrollToOffset(fyne.NewPos(x, y))
+	t.body.Refresh()
+}
+
+// Wrapped 返回是否开启了自动换行
+func (t *TerminalOutput) Wrapped() bool {
+	return t.wrap
+}
+
+// setTerminalWrap 切换主终端与各运行标签页的自动换行，设置在本次运行期间保持
+func (ui *TestUI) setTerminalWrap(on bool) {
+	ui.terminalWrap = on
+	if ui.Terminal != nil {
+		ui.Terminal.SetWrap(on)
+	}
+	for _, tab := range ui.extraRuns {
+		tab.terminal.SetWrap(on)
+	}
+}
+
+// createTerminalWrapButton 创建终端工具栏中切换自动换行的按钮，开启时高亮显示
+func (ui *TestUI) createTerminalWrapButton() *widget.Button {
+	button := widget.NewButtonWithIcon("", theme.MenuIcon(), nil)
+	update := func() {
+		button.Importance = widget.MediumImportance
+		if ui.terminalWrap {
+			button.Importance = widget.HighImportance
+		}
+		button.Refresh()
+	}
+	button.OnTapped = func() {
+		ui.setTerminalWrap(!ui.terminalWrap)
+		update()
+	}
+	update()
+	return button
+}
@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestWrapTerminalText(t *testing.T) {
+	tests := []struct {
+		plain string
+		cols  int
+		want  []string
+	}{
+		{"short", 10, []string{"short"}},
+		{"hello world again", 11, []string{"hello ", "world again"}},
+		{"abcdefghij", 4, []string{"abcd", "efgh", "ij"}},
+		{"上海电信测速", 5, []string{"上海", "电信", "测速"}},
+	}
+	for _, tt := range tests {
+		var got []string
+		from := 0
+		for _, to := range wrapTerminalText(tt.plain, tt.cols) {
+			got = append(got, tt.plain[from:to])
+			from = to
+		}
+		if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Fatalf("wrapTerminalText(%q, %d) = %q, want %q", tt.plain, tt.cols, got, tt.want)
+		}
+	}
+}
+
+func TestSliceTerminalLineKeepsColors(t *testing.T) {
+	p := ansiParser{}
+	line := newTerminalLine(&p, "ab\x1b[31mcdef\x1b[0mgh")
+	sliced := sliceTerminalLine(line, 3, 7)
+	if sliced.plain != "defg" {
+		t.Fatalf("plain = %q", sliced.plain)
+	}
+	// 切片从红色中间开始，颜色保存在行首样式中
+	p = ansiParser{style: sliced.style}
+	segments := p.Parse(sliced.raw)
+	red := ansiColor{Kind: ansiColorIndexed, Index: 1}
+	if len(segments) != 2 || segments[0].Text != "def" || segments[0].Style.FG != red || segments[1].Style != (ansiStyle{}) {
+		t.Fatalf("segments = %#v", segments)
+	}
+}
+
+func TestTerminalWrapKeepsTopLine(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	w := test.NewWindow(terminal)
+	t.Cleanup(w.Close)
+	w.Resize(fyne.NewSize(360, 200))
+
+	var b strings.Builder
+	for i := 0; i < 40; i++ {
+		b.WriteString(strings.Repeat("wide ", 30) + "\n")
+	}
+	feedTerminal(terminal, b.String())
+	terminal.sync()
+	terminal.scroll.Refresh()
+	m := terminal.body.metrics()
+	terminal.scroll.ScrollToOffset(fyne.NewPos(50, m.pad+10*m.row))
+
+	terminal.SetWrap(true)
+	if terminal.rowCount() <= terminal.lineCount() {
+		t.Fatal("long lines should be split into several rows")
+	}
+	top, _ := terminal.segmentRow(10, 0)
+	if got := terminal.scroll.Offset; got.X != 0 || got.Y != m.pad+float32(top)*m.row {
+		t.Fatalf("offset after wrapping = %v, want line 10 (row %d) at the top", got, top)
+	}
+	if first, last := terminal.rowSpan(top); !first || last {
+		t.Fatal("the top row should be the first of several segments")
+	}
+	terminal.SelectAll()
+	if got := terminal.SelectedText(); got != strings.TrimSuffix(terminal.GetText(), "\n") {
+		t.Fatal("copying wrapped rows should not insert extra line breaks")
+	}
+
+	terminal.SetWrap(false)
+	if got := terminal.scroll.Offset; got.X != 50 || got.Y != m.pad+10*m.row {
+		t.Fatalf("offset after unwrapping = %v, want the saved horizontal position and line 10", got)
+	}
+}
@@ -9,8 +9,9 @@ import (
 )
 
 const (
-	themeModeLight = "light"
-	themeModeDark  = "dark"
+	themeModeLight  = "light"
+	themeModeDark   = "dark"
+	themeModeSystem = "system"
 
 	themePreferenceKey = "theme_mode"
 )
@@ -18,21 +19,25 @@ const (
 type CustomTheme struct {
 	Variant      fyne.ThemeVariant
 	forceVariant bool
+	scheme       *terminalScheme // 终端配色方案，nil 表示跟随主题
 }
 
 var _ fyne.Theme = (*CustomTheme)(nil)
 
+// NewCustomTheme 创建指定模式的主题；跟随系统时不强制明暗
 func NewCustomTheme(mode string) *CustomTheme {
-	variant := theme.VariantLight
-	if mode == themeModeDark {
-		variant = theme.VariantDark
+	switch mode {
+	case themeModeDark:
+		return &CustomTheme{Variant: theme.VariantDark, forceVariant: true}
+	case themeModeSystem:
+		return &CustomTheme{}
 	}
-	return &CustomTheme{Variant: variant, forceVariant: true}
+	return &CustomTheme{Variant: theme.VariantLight, forceVariant: true}
 }
 
 func normalizeThemeMode(mode string) string {
-	if mode == themeModeDark {
-		return themeModeDark
+	if mode == themeModeDark || mode == themeModeSystem {
+		return mode
 	}
 	return themeModeLight
 }
@@ -41,20 +46,28 @@ func (ui *TestUI) applyThemeMode(mode string) {
 	ui.themeMode = normalizeThemeMode(mode)
 	if ui.App != nil {
 		ui.App.Preferences().SetString(themePreferenceKey, ui.themeMode)
-		ui.App.Settings().SetTheme(NewCustomTheme(ui.themeMode))
+		th := NewCustomTheme(ui.themeMode)
+		th.scheme = terminalSchemes[ui.terminalScheme]
+		ui.App.Settings().SetTheme(th)
 	}
 }
 
 func (ui *TestUI) themeLabelByMode(mode string) string {
-	if normalizeThemeMode(mode) == themeModeDark {
+	switch normalizeThemeMode(mode) {
+	case themeModeDark:
 		return ui.tr("theme.dark")
+	case themeModeSystem:
+		return ui.tr("theme.system")
 	}
 	return ui.tr("theme.light")
 }
 
 func (ui *TestUI) themeModeByLabel(label string) string {
-	if label == ui.tr("theme.dark") || label == "Dark" || label == "深色" {
+	switch label {
+	case ui.tr("theme.dark"), "Dark", "深色":
 		return themeModeDark
+	case ui.tr("theme.system"), "System", "跟随系统":
+		return themeModeSystem
 	}
 	return themeModeLight
 }
@@ -67,6 +80,14 @@ func (m *CustomTheme) Color(name fyne.ThemeColorName, variant fyne.ThemeVariant)
 	if name == theme.ColorNameDisabled {
 		return theme.DefaultTheme().Color(theme.ColorNameForeground, variant)
 	}
+	// 终端背景、默认文字与 ANSI 颜色
+	var scheme *terminalScheme
+	if m != nil {
+		scheme = m.scheme
+	}
+	if c, ok := terminalSchemeColor(scheme, name, variant); ok {
+		return c
+	}
 	return theme.DefaultTheme().Color(name, variant)
 }
 
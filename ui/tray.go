@@ -0,0 +1,102 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// trayMinimizePreferenceKey 为真时关闭主窗口只隐藏到系统托盘，测试与定时任务继续运行
+const trayMinimizePreferenceKey = "tray_minimize"
+
+// trayQuickPreset 是托盘“快速测试”使用的预设
+const trayQuickPreset = "minimal"
+
+// setupTray 在桌面平台注册托盘图标与菜单，语言切换后需重新调用以刷新菜单文字
+func (ui *TestUI) setupTray() {
+	desk, ok := ui.App.(desktop.App)
+	if !ok {
+		return
+	}
+	desk.SetSystemTrayMenu(ui.trayMenu())
+	if icon := ui.App.Icon(); icon != nil {
+		desk.SetSystemTrayIcon(icon)
+	}
+	desk.SetSystemTrayWindow(ui.Window)
+}
+
+func (ui *TestUI) trayMenu() *fyne.Menu {
+	quit := fyne.NewMenuItem(ui.tr("tray.quit"), func() {
+		// 直接关闭主窗口，走与正常关闭相同的清理流程
+		ui.Window.Close()
+	})
+	quit.IsQuit = true
+	return fyne.NewMenu(ui.tr("app.title"),
+		fyne.NewMenuItem(ui.tr("tray.quick_test"), ui.trayQuickTest),
+		fyne.NewMenuItem(ui.tr("tray.show"), ui.showMainWindow),
+		fyne.NewMenuItem(ui.tr("tray.last_result"), ui.notifyLastResult),
+		fyne.NewMenuItemSeparator(),
+		quit,
+	)
+}
+
+func (ui *TestUI) showMainWindow() {
+	ui.Window.Show()
+	ui.Window.RequestFocus()
+}
+
+// trayQuickTest 显示主窗口并以快速预设开始测试；已有测试在运行时只显示窗口
+func (ui *TestUI) trayQuickTest() {
+	ui.showMainWindow()
+	ui.Mu.Lock()
+	running := ui.IsRunning
+	ui.Mu.Unlock()
+	if running {
+		return
+	}
+	ui.applyPresetAndStart(trayQuickPreset)
+}
+
+// notifyLastResult 以通知形式显示最近一次测试的摘要，不打断当前窗口
+func (ui *TestUI) notifyLastResult() {
+	ui.App.SendNotification(fyne.NewNotification(ui.tr("tray.last_result"), ui.lastResultSummary()))
+}
+
+func (ui *TestUI) lastResultSummary() string {
+	store := ui.historyStoreOrOpen()
+	if store == nil {
+		return ui.tr("tray.no_result")
+	}
+	runs, err := store.List()
+	if err != nil || len(runs) == 0 {
+		return ui.tr("tray.no_result")
+	}
+	latest := runs[0]
+	var parsed *results.Report
+	if run, err := store.Load(latest.ID); err == nil {
+		parsed = run.Results
+	}
+	summary := ui.notificationSummary("status."+latest.Status, latest.Duration, parsed, nil)
+	prefix := ui.tr("status." + latest.Status)
+	if latest.Host != "" {
+		prefix = latest.Host + " · " + prefix
+	}
+	return prefix + " · " + summary
+}
+
+// onWindowCloseRequest 处理用户关闭主窗口：启用托盘最小化时隐藏窗口，否则正常关闭
+func (ui *TestUI) onWindowCloseRequest() {
+	if _, ok := ui.App.(desktop.App); ok && ui.App.Preferences().Bool(trayMinimizePreferenceKey) {
+		ui.Window.Hide()
+		return
+	}
+	ui.Window.Close()
+}
+
+func (ui *TestUI) createTrayCheck() *widget.Check {
+	prefs := ui.App.Preferences()
+	check := widget.NewCheck(ui.tr("check.tray"), func(on bool) { prefs.SetBool(trayMinimizePreferenceKey, on) })
+	check.SetChecked(prefs.Bool(trayMinimizePreferenceKey))
+	return check
+}
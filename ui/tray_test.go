@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTrayMenuItems(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	menu := ui.trayMenu()
+	var labels []string
+	for _, item := range menu.Items {
+		if !item.IsSeparator {
+			labels = append(labels, item.Label)
+		}
+	}
+	if got := strings.Join(labels, "|"); got != "Run quick test|Show window|Last result summary|Quit" {
+		t.Fatalf("tray menu = %q", got)
+	}
+	if last := menu.Items[len(menu.Items)-1]; !last.IsQuit {
+		t.Fatal("quit item is not marked IsQuit")
+	}
+}
+
+func TestLastResultSummaryUsesLatestHistoryRun(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	ui.uiLang = langEN
+	if got := ui.lastResultSummary(); got != "No test runs yet" {
+		t.Fatalf("empty summary = %q", got)
+	}
+
+	ui.Terminal.SetFullText("-----------就近节点测速-----------\n Speedtest.net   100.5 Mbps    200.25 Mbps    1.2 ms    0.0%\n")
+	ui.refreshParsedResults()
+	run := newRun(nil, ExecutionConfig{PresetKey: "minimal"}, nil, nil)
+	run.Started = time.Now().Add(-time.Minute)
+	ui.recordRunHistory(run, "status.done")
+	got := ui.lastResultSummary()
+	if !strings.Contains(got, "Completed · took ") || !strings.Contains(got, "Mbps") {
+		t.Fatalf("summary = %q", got)
+	}
+}
+
+func TestTrayCheckStoresPreference(t *testing.T) {
+	ui := newTestUIForTest(t)
+	check := ui.createTrayCheck()
+	if check.Checked {
+		t.Fatal("minimize to tray should be off by default")
+	}
+	check.SetChecked(true)
+	if !ui.App.Preferences().Bool(trayMinimizePreferenceKey) {
+		t.Fatal("checking did not store the preference")
+	}
+}
@@ -0,0 +1,262 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"sort"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// trendPoint 是某次完成运行中的一个指标值
+type trendPoint struct {
+	at    time.Time
+	value float64
+}
+
+// trendHosts 返回历史中有完成运行的主机，按运行次数从多到少排列
+func trendHosts(summaries []history.Summary) []string {
+	counts := map[string]int{}
+	var hosts []string
+	for _, s := range summaries {
+		if s.Status != "done" || s.Host == "" {
+			continue
+		}
+		if counts[s.Host] == 0 {
+			hosts = append(hosts, s.Host)
+		}
+		counts[s.Host]++
+	}
+	sort.SliceStable(hosts, func(i, j int) bool { return counts[hosts[i]] > counts[hosts[j]] })
+	return hosts
+}
+
+// loadTrendSeries 读取主机所有完成的运行，按时间从早到晚返回各项趋势指标
+func loadTrendSeries(store *history.Store, host string) (map[results.TrendMetric][]trendPoint, error) {
+	summaries, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	series := make(map[results.TrendMetric][]trendPoint, len(results.TrendMetrics))
+	// List 按时间倒序，倒着遍历得到正序
+	for i := len(summaries) - 1; i >= 0; i-- {
+		summary := summaries[i]
+		if summary.Host != host || summary.Status != "done" {
+			continue
+		}
+		run, err := store.Load(summary.ID)
+		if err != nil || run.Results == nil {
+			continue
+		}
+		for _, metric := range results.TrendMetrics {
+			if value, ok := results.TrendValue(run.Results, metric); ok {
+				series[metric] = append(series[metric], trendPoint{at: summary.StartedAt, value: value})
+			}
+		}
+	}
+	return series, nil
+}
+
+// createTrendsTab 创建趋势页：选择主机后显示各项指标随时间的变化
+func (ui *TestUI) createTrendsTab() fyne.CanvasObject {
+	ui.trendCharts = container.NewVBox()
+	ui.trendHost = widget.NewSelect(nil, func(string) { ui.renderTrends() })
+	ui.trendHost.PlaceHolder = ui.tr("trends.pick_host")
+	refresh := widget.NewButtonWithIcon(ui.tr("history.refresh"), theme.ViewRefreshIcon(), ui.reloadTrendHosts)
+	bar := container.NewBorder(nil, nil, widget.NewLabel(ui.tr("trends.host")), refresh, ui.trendHost)
+	ui.reloadTrendHosts()
+	return container.NewBorder(bar, nil, nil, nil, container.NewVScroll(ui.trendCharts))
+}
+
+// reloadTrendHosts 刷新主机列表并保持当前选择，必须在界面线程调用
+func (ui *TestUI) reloadTrendHosts() {
+	if ui.trendHost == nil {
+		return
+	}
+	var hosts []string
+	if store := ui.historyStoreOrOpen(); store != nil {
+		if summaries, err := store.List(); err == nil {
+			hosts = trendHosts(summaries)
+		}
+	}
+	selected := ui.trendHost.Selected
+	ui.trendHost.Options = hosts
+	switch {
+	case len(hosts) == 0:
+		ui.trendHost.ClearSelected()
+	case !containsString(hosts, selected):
+		ui.trendHost.SetSelected(hosts[0])
+		return
+	}
+	ui.trendHost.Refresh()
+	ui.renderTrends()
+}
+
+// renderTrends 按当前选择的主机重建趋势图
+func (ui *TestUI) renderTrends() {
+	if ui.trendCharts == nil {
+		return
+	}
+	ui.trendCharts.RemoveAll()
+	store := ui.historyStoreOrOpen()
+	host := ui.trendHost.Selected
+	if store == nil || host == "" {
+		ui.trendCharts.Add(widget.NewLabel(ui.tr("trends.empty")))
+		return
+	}
+	series, err := loadTrendSeries(store, host)
+	if err != nil {
+		ui.trendCharts.Add(widget.NewLabel(ui.friendlyErrorMessage(err)))
+		return
+	}
+	for _, metric := range results.TrendMetrics {
+		points := series[metric]
+		if len(points) == 0 {
+			continue
+		}
+		ui.trendCharts.Add(newTrendChart(ui.tr("trends.metric."+string(metric)), points))
+	}
+	if len(ui.trendCharts.Objects) == 0 {
+		ui.trendCharts.Add(widget.NewLabel(ui.tr("trends.empty")))
+	}
+}
+
+// trendChart 绘制单项指标随时间变化的折线，异常点用错误色标出
+type trendChart struct {
+	widget.BaseWidget
+	title     string
+	points    []trendPoint
+	anomalies []bool
+}
+
+func newTrendChart(title string, points []trendPoint) *trendChart {
+	values := make([]float64, len(points))
+	for i, p := range points {
+		values[i] = p.value
+	}
+	chart := &trendChart{title: title, points: points, anomalies: results.Anomalies(values)}
+	chart.ExtendBaseWidget(chart)
+	return chart
+}
+
+func (c *trendChart) MinSize() fyne.Size {
+	return fyne.NewSize(320, 180)
+}
+
+func (c *trendChart) CreateRenderer() fyne.WidgetRenderer {
+	r := &trendChartRenderer{chart: c, background: canvas.NewRectangle(color.Transparent)}
+	for i := range r.labels {
+		r.labels[i] = canvas.NewText("", color.Black)
+	}
+	r.labels[0].TextStyle.Bold = true
+	return r
+}
+
+type trendChartRenderer struct {
+	chart      *trendChart
+	background *canvas.Rectangle
+	// 标题、范围、起始日期、结束日期
+	labels [4]*canvas.Text
+	plot   []fyne.CanvasObject
+	size   fyne.Size
+}
+
+func (r *trendChartRenderer) Destroy() {}
+
+func (r *trendChartRenderer) MinSize() fyne.Size {
+	return r.chart.MinSize()
+}
+
+func (r *trendChartRenderer) Objects() []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{r.background}
+	for _, label := range r.labels {
+		objects = append(objects, label)
+	}
+	return append(objects, r.plot...)
+}
+
+func (r *trendChartRenderer) Layout(size fyne.Size) {
+	r.size = size
+	r.Refresh()
+}
+
+func (r *trendChartRenderer) Refresh() {
+	th := r.chart.Theme()
+	variant := fyne.CurrentApp().Settings().ThemeVariant()
+	pad := th.Size(theme.SizeNameInnerPadding)
+	textSize := th.Size(theme.SizeNameCaptionText)
+	lineColor := th.Color(theme.ColorNamePrimary, variant)
+	anomalyColor := th.Color(theme.ColorNameError, variant)
+
+	r.background.FillColor = th.Color(theme.ColorNameInputBackground, variant)
+	r.background.CornerRadius = th.Size(theme.SizeNameInputRadius)
+	r.background.Resize(r.size)
+
+	points := r.chart.points
+	low, high := points[0].value, points[0].value
+	for _, p := range points {
+		low, high = min(low, p.value), max(high, p.value)
+	}
+	title, rangeText, first, last := r.labels[0], r.labels[1], r.labels[2], r.labels[3]
+	title.Text = r.chart.title
+	rangeText.Text = fmt.Sprintf("%.0f – %.0f", low, high)
+	first.Text = points[0].at.Local().Format("01-02")
+	last.Text = points[len(points)-1].at.Local().Format("01-02")
+	for _, label := range r.labels {
+		label.TextSize = textSize
+		label.Color = th.Color(theme.ColorNameForeground, variant)
+	}
+	title.Move(fyne.NewPos(pad, pad))
+	rangeText.Move(fyne.NewPos(r.size.Width-pad-rangeText.MinSize().Width, pad))
+	bottom := r.size.Height - pad - textSize*1.4
+	first.Move(fyne.NewPos(pad, bottom))
+	last.Move(fyne.NewPos(r.size.Width-pad-last.MinSize().Width, bottom))
+
+	left, top := pad+4, pad+textSize*2.5
+	width, height := r.size.Width-2*left, bottom-top-pad
+	r.plot = r.plot[:0]
+	if width <= 0 || height <= 0 {
+		canvas.Refresh(r.chart)
+		return
+	}
+	span := points[len(points)-1].at.Sub(points[0].at)
+	point := func(i int) fyne.Position {
+		fraction := float32(0.5)
+		switch {
+		case span > 0:
+			fraction = float32(points[i].at.Sub(points[0].at)) / float32(span)
+		case len(points) > 1:
+			fraction = float32(i) / float32(len(points)-1)
+		}
+		y := float32(0.5)
+		if high > low {
+			y = float32((points[i].value - low) / (high - low))
+		}
+		return fyne.NewPos(left+fraction*width, top+(1-y)*height)
+	}
+	for i := range points {
+		p := point(i)
+		if i > 0 {
+			line := canvas.NewLine(lineColor)
+			line.StrokeWidth = 2
+			line.Position1, line.Position2 = point(i-1), p
+			r.plot = append(r.plot, line)
+		}
+		dotColor, radius := lineColor, float32(2.5)
+		if r.chart.anomalies[i] {
+			dotColor, radius = anomalyColor, 4
+		}
+		dot := canvas.NewCircle(dotColor)
+		dot.Move(p.SubtractXY(radius, radius))
+		dot.Resize(fyne.NewSize(2*radius, 2*radius))
+		r.plot = append(r.plot, dot)
+	}
+	canvas.Refresh(r.chart)
+}
@@ -0,0 +1,56 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestTrendsTabChartsHostHistory(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	store := ui.historyStoreOrOpen()
+	base := time.Date(2026, 3, 1, 3, 0, 0, 0, time.UTC)
+	save := func(day int, host, status string, score float64) {
+		t.Helper()
+		_, err := store.Save(history.Run{
+			Summary: history.Summary{StartedAt: base.AddDate(0, 0, day), Host: host, Status: status},
+			Results: &results.Report{
+				CPU:   []results.CPUScore{{Label: "1 线程", Score: score}},
+				Speed: []results.SpeedResult{{Node: "a", DownloadMbps: 900, UploadMbps: 400}},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for day, score := range []float64{1000, 1010, 990, 600, 1005} {
+		save(day, "vps", "done", score)
+	}
+	save(1, "other", "done", 100)
+	save(6, "vps", "failed", 1)
+
+	series, err := loadTrendSeries(store, "vps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cpu := series[results.TrendCPU]
+	if len(cpu) != 5 || cpu[0].value != 1000 || cpu[4].value != 1005 || len(series[results.TrendDiskIOPS]) != 0 {
+		t.Fatalf("series = %+v", series)
+	}
+
+	ui.reloadTrendHosts()
+	if got := ui.trendHost.Options; len(got) != 2 || got[0] != "vps" || ui.trendHost.Selected != "vps" {
+		t.Fatalf("hosts = %v, selected %q", got, ui.trendHost.Selected)
+	}
+	// CPU、下载、上传三张图；没有硬盘数据时不显示硬盘图
+	if len(ui.trendCharts.Objects) != 3 {
+		t.Fatalf("charts = %d, want 3", len(ui.trendCharts.Objects))
+	}
+	chart := ui.trendCharts.Objects[0].(*trendChart)
+	if !chart.anomalies[3] || chart.anomalies[0] {
+		t.Fatalf("anomalies = %v, want the 600 score flagged", chart.anomalies)
+	}
+}
@@ -0,0 +1,183 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os/exec"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/iperf"
+	"github.com/oneclickvirt/ecs-gui/latency"
+	"github.com/oneclickvirt/ecs-gui/tunnel"
+)
+
+const tunnelComponentSchema = "ecs-gui.tunnel/v1"
+
+// 隧道对比使用的探测目标与下载地址，测试中替换
+var (
+	tunnelProbeTargets = []latency.Target{{Host: "1.1.1.1", Port: 443}, {Host: "8.8.8.8", Port: 443}}
+	tunnelDownloadURL  = "https://speed.cloudflare.com/__down?bytes=25000000"
+	tunnelDialer       = tunnel.Dialer
+)
+
+const (
+	tunnelProbeCount    = 5
+	tunnelDownloadLimit = 25_000_000
+)
+
+// tunnelReport 是隧道对比组件的负载
+type tunnelReport struct {
+	Interface string       `json:"interface"`
+	LocalIP   string       `json:"local_ip,omitempty"`
+	Rows      []tunnel.Row `json:"rows"`
+}
+
+// runTunnelStage 分别经默认路由与绑定到网卡 iface 运行 TCP 延迟、HTTP 下载与 iperf3 测试，
+// 把两组结果并排写到 out，返回报告中的分区与组件
+func runTunnelStage(ctx context.Context, out io.Writer, iface string, iperfTargets []iperf.Target, language string, width int) (StructuredSection, StructuredComponent) {
+	started := time.Now()
+	section := StructuredSection{Name: "tunnel", Enabled: true}
+	component := StructuredComponent{Name: "tunnel", SchemaVersion: tunnelComponentSchema}
+	fmt.Fprintln(out, centeredTitle(pickLanguage(language, "隧道对比", "Tunnel-Comparison"), width))
+	fail := func(status, reason string) (StructuredSection, StructuredComponent) {
+		fmt.Fprintln(out, reason)
+		section.Status, section.Reason = status, reason
+		component.Status, component.Reason = status, reason
+		return section, component
+	}
+	bound, err := tunnelDialer(iface, 5*time.Second)
+	if err != nil {
+		return fail("unavailable", err.Error())
+	}
+	report := tunnelReport{Interface: iface}
+	if addr, ok := bound.LocalAddr.(*net.TCPAddr); ok {
+		report.LocalIP = addr.IP.String()
+	}
+	direct := &net.Dialer{Timeout: 5 * time.Second}
+
+	fmt.Fprintf(out, "%-28s %-14s %-14s %s\n", pickLanguage(language, "指标", "Metric"), pickLanguage(language, "默认路由", "Default"), iface, pickLanguage(language, "损耗", "Overhead"))
+	printRow := func(row tunnel.Row) {
+		report.Rows = append(report.Rows, row)
+		value := func(v float64) string {
+			if v <= 0 {
+				return "-"
+			}
+			return fmt.Sprintf("%.2f %s", v, row.Unit)
+		}
+		overhead := row.Error
+		if percent, ok := row.Overhead(); ok {
+			overhead = fmt.Sprintf("%+.1f%%", percent)
+		}
+		fmt.Fprintf(out, "%-28s %-14s %-14s %s\n", row.Metric, value(row.Default), value(row.Bound), overhead)
+	}
+	// pair 依次得到两列的值，任一失败时记录第一条错误
+	pair := func(row tunnel.Row, measure func(*net.Dialer) (float64, error)) {
+		var errs []string
+		for i, dialer := range []*net.Dialer{direct, bound} {
+			if ctx.Err() != nil {
+				return
+			}
+			value, err := measure(dialer)
+			if err != nil {
+				errs = append(errs, err.Error())
+				continue
+			}
+			if i == 0 {
+				row.Default = value
+			} else {
+				row.Bound = value
+			}
+		}
+		if len(errs) > 0 {
+			row.Error = errs[0]
+		}
+		printRow(row)
+	}
+
+	for _, target := range tunnelProbeTargets {
+		pair(tunnel.Row{Metric: "TCP " + target.String(), Unit: "ms", LowerBetter: true}, func(dialer *net.Dialer) (float64, error) {
+			result := latency.ProbeTCPWith(ctx, dialer, target, tunnelProbeCount)
+			if result.Error != "" {
+				return 0, fmt.Errorf("%s", result.Error)
+			}
+			return result.AvgMs, nil
+		})
+	}
+	pair(tunnel.Row{Metric: pickLanguage(language, "HTTP 下载", "HTTP download"), Unit: "Mbps"}, func(dialer *net.Dialer) (float64, error) {
+		return tunnel.Download(ctx, tunnel.HTTPClient(dialer, time.Minute), tunnelDownloadURL, tunnelDownloadLimit)
+	})
+	if binary, err := exec.LookPath(iperfBinary); err == nil && report.LocalIP != "" {
+		for _, target := range iperfTargets {
+			label := "iperf3 " + target.Address()
+			if target.Reverse {
+				label += " (-R)"
+			}
+			pair(tunnel.Row{Metric: label, Unit: "Mbps"}, func(dialer *net.Dialer) (float64, error) {
+				if dialer == bound {
+					target.Bind = report.LocalIP
+				}
+				result, err := iperf.Run(ctx, binary, target)
+				return result.ReceivedMbps, err
+			})
+		}
+	}
+
+	failed := 0
+	for _, row := range report.Rows {
+		if row.Default <= 0 || row.Bound <= 0 {
+			failed++
+		}
+	}
+	switch {
+	case ctx.Err() != nil:
+		section.Status, section.Reason = "canceled", ctx.Err().Error()
+	case failed == len(report.Rows):
+		section.Status, section.Reason = "error", "no metric succeeded on both routes"
+	case failed > 0:
+		section.Status, section.Reason = "partial", fmt.Sprintf("%d of %d metrics incomplete", failed, len(report.Rows))
+	default:
+		section.Status = "ok"
+	}
+	component.Status, component.Reason = section.Status, section.Reason
+	component.DurationMS = time.Since(started).Milliseconds()
+	component.Payload, _ = json.Marshal(report)
+	return section, component
+}
+
+// setTunnelInterface 保存对比使用的网卡（空为关闭）并刷新配置页的 iperf3 按钮
+func (ui *TestUI) setTunnelInterface(name string) {
+	ui.tunnelInterface = strings.TrimSpace(name)
+	if ui.IperfButton != nil {
+		ui.IperfButton.SetText(ui.iperfTargetsSummary())
+	}
+}
+
+// tunnelSelect 创建隧道对比的网卡选择框，列表来自本机已启用的网卡；返回选择框与取出所选网卡名的函数
+func (ui *TestUI) tunnelSelect() (*widget.Select, func() string) {
+	off := ui.tr("tunnel.off")
+	options := []string{off}
+	names := map[string]string{off: ""}
+	interfaces, _ := tunnel.Interfaces()
+	selected := off
+	for _, iface := range interfaces {
+		options = append(options, iface.Label())
+		names[iface.Label()] = iface.Name
+		if iface.Name == ui.tunnelInterface {
+			selected = iface.Label()
+		}
+	}
+	if ui.tunnelInterface != "" && selected == off {
+		// 保存的网卡当前不存在（隧道未连接），仍保留为可选项
+		options = append(options, ui.tunnelInterface)
+		names[ui.tunnelInterface] = ui.tunnelInterface
+		selected = ui.tunnelInterface
+	}
+	choice := widget.NewSelect(options, nil)
+	choice.SetSelected(selected)
+	return choice, func() string { return names[choice.Selected] }
+}
@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/latency"
+)
+
+func TestRunTunnelStageComparesRoutes(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 64<<10)))
+	}))
+	defer server.Close()
+	target, _ := latency.ParseTarget(listener.Addr().String())
+
+	oldTargets, oldURL, oldDialer, oldIperf := tunnelProbeTargets, tunnelDownloadURL, tunnelDialer, iperfBinary
+	t.Cleanup(func() {
+		tunnelProbeTargets, tunnelDownloadURL, tunnelDialer, iperfBinary = oldTargets, oldURL, oldDialer, oldIperf
+	})
+	tunnelProbeTargets = []latency.Target{target}
+	tunnelDownloadURL = server.URL
+	iperfBinary = filepath.Join(t.TempDir(), "missing-iperf3")
+	tunnelDialer = func(name string, timeout time.Duration) (*net.Dialer, error) {
+		return &net.Dialer{Timeout: timeout, LocalAddr: &net.TCPAddr{IP: net.IPv4(127, 0, 0, 1)}}, nil
+	}
+	oldInterval := latency.TCPInterval
+	latency.TCPInterval = time.Millisecond
+	t.Cleanup(func() { latency.TCPInterval = oldInterval })
+
+	var out strings.Builder
+	section, component := runTunnelStage(context.Background(), &out, "wg0", nil, "en", 82)
+	if section.Status != "ok" || component.Name != "tunnel" {
+		t.Fatalf("section = %+v", section)
+	}
+	text := out.String()
+	if !strings.Contains(text, "Tunnel-Comparison") || !strings.Contains(text, "wg0") || !strings.Contains(text, "TCP "+target.String()) || !strings.Contains(text, "HTTP download") {
+		t.Fatalf("output = %q", text)
+	}
+	var report tunnelReport
+	if err := json.Unmarshal(component.Payload, &report); err != nil || report.Interface != "wg0" || report.LocalIP != "127.0.0.1" || len(report.Rows) != 2 {
+		t.Fatalf("payload = %s, %v", component.Payload, err)
+	}
+	if !report.Rows[0].LowerBetter || report.Rows[1].Bound <= 0 {
+		t.Fatalf("rows = %+v", report.Rows)
+	}
+}
+
+func TestRunTunnelStageMissingInterface(t *testing.T) {
+	var out strings.Builder
+	section, _ := runTunnelStage(context.Background(), &out, "no-such-iface0", nil, "zh", 82)
+	if section.Status != "unavailable" || !strings.Contains(out.String(), "隧道对比") {
+		t.Fatalf("section = %+v, output = %q", section, out.String())
+	}
+}
+
+func TestTunnelInterfaceAddsProgressStep(t *testing.T) {
+	ui := newTestUIForTest(t)
+	ui.setTunnelInterface(" wg0 ")
+	if got := ui.iperfTargetsSummary(); !strings.HasSuffix(got, "+ wg0") {
+		t.Fatalf("button = %q", got)
+	}
+	config := buildExecutionConfig(ui.currentExecutionForm())
+	if config.TunnelInterface != "wg0" {
+		t.Fatalf("TunnelInterface = %q", config.TunnelInterface)
+	}
+	steps := strings.Join(buildProgressSteps(config, true), ",")
+	if !strings.Contains(steps, "progress.tunnel") {
+		t.Fatalf("steps = %s", steps)
+	}
+}
@@ -2,10 +2,10 @@ package ui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
-	"runtime"
 	"strings"
 	"time"
 
@@ -13,8 +13,11 @@ import (
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
-	apputils "github.com/oneclickvirt/ecs-gui/utils"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
 )
 
 const maxLogViewBytes int64 = 1024 * 1024
@@ -184,13 +187,7 @@ func (ui *TestUI) onPresetChanged(preset string) {
 }
 
 func (ui *TestUI) applyPresetAndStart(presetKey string) {
-	ui.suppressPresetChange = true
-	ui.selectedPresetKey = presetKey
-	if ui.PresetSelect != nil {
-		ui.PresetSelect.SetSelected(ui.presetLabelByKey(presetKey))
-	}
-	ui.suppressPresetChange = false
-	ui.onPresetChanged(ui.presetLabelByKey(presetKey))
+	ui.applyPreset(presetKey)
 	ui.startTests()
 	ui.showResultTab()
 }
@@ -258,11 +255,13 @@ func (ui *TestUI) setAllChecks(checked bool) {
 
 // refreshAllChecks 刷新所有测试项的显示
 func (ui *TestUI) refreshAllChecks() {
+	ui.clearOfflineChecks()
 	for _, check := range ui.testChecks {
 		if check != nil {
 			check.Refresh()
 		}
 	}
+	ui.syncSelectionSidebar()
 }
 
 // refreshSpeedTestChecks 刷新测速配置的显示
@@ -279,6 +278,9 @@ func (ui *TestUI) refreshSpeedTestChecks() {
 	if ui.AutoDiskMethodCheck != nil {
 		ui.AutoDiskMethodCheck.Refresh()
 	}
+	if ui.DiskSafeModeCheck != nil {
+		ui.DiskSafeModeCheck.Refresh()
+	}
 	if ui.UnlockShowIPCheck != nil {
 		ui.UnlockShowIPCheck.Refresh()
 	}
@@ -302,34 +304,97 @@ func (ui *TestUI) startTests() {
 		return
 	}
 
-	config := ui.collectExecutionConfig()
-
-	// 权限检测：检查是否有需要管理员/root权限的测试项
-	if needsPriv, testsZH, testsEN := needsPrivilege(config); needsPriv && !isPrivileged() {
-		var body string
-		if config.Language == "en" {
-			body = fmt.Sprintf(ui.tr("dialog.no_privilege_body"), testsEN)
-		} else {
-			body = fmt.Sprintf(ui.tr("dialog.no_privilege_body"), testsZH)
-		}
-		if runtime.GOOS == "windows" {
-			if err := requestPrivilegeRestart(); err == nil {
-				dialog.ShowInformation(ui.tr("dialog.no_privilege_title"), ui.tr("dialog.uac_started"), ui.Window)
-			} else {
-				dialog.ShowInformation(ui.tr("dialog.no_privilege_title"), body+"\n\n"+ui.tr("dialog.uac_failed"), ui.Window)
-			}
-		} else {
-			dialog.ShowInformation(ui.tr("dialog.no_privilege_title"), body, ui.Window)
-		}
+	if _, err := ui.remoteTarget(); err != nil {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.remote_invalid")+"\n"+err.Error(), ui.Window)
 		ui.Mu.Lock()
 		ui.IsRunning = false
 		ui.Mu.Unlock()
 		return
 	}
 
+	config := ui.collectExecutionConfig()
+	_ = ui.saveSettings()
+
+	ui.Mu.Lock()
+	busy := config.local() && ui.localTabRunActive()
+	ui.mainRunLocal = config.local()
+	ui.Mu.Unlock()
+	if busy {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("run_tabs.local_busy"), ui.Window)
+		ui.cancelStart()
+		return
+	}
+
+	ui.confirmLaunch(config, func(config ExecutionConfig) { ui.launchRun(config, nil) }, ui.cancelStart)
+}
+
+// confirmLaunch 依次确认权限、Geekbench 许可协议与磁盘空间，全部通过后调用 next，任一步取消都调用 cancel
+func (ui *TestUI) confirmLaunch(config ExecutionConfig, next func(ExecutionConfig), cancel func()) {
+	ui.confirmPrivileges(config, func(config ExecutionConfig) {
+		ui.confirmGeekbenchLicense(config, func(config ExecutionConfig) {
+			ui.confirmDiskSpace(config, next, cancel)
+		}, cancel)
+	}, cancel)
+}
+
+// cancelStart 在启动前的确认步骤被取消时释放运行状态
+func (ui *TestUI) cancelStart() {
+	ui.Mu.Lock()
+	ui.IsRunning = false
+	ui.Mu.Unlock()
+}
+
+var (
+	errRunInProgress     = errors.New("a test run is already in progress")
+	errRunNeedsPrivilege = errors.New("administrator/root privileges required")
+	errNoTestsSelected   = errors.New("no tests selected")
+)
+
+// startBackgroundRun 不经过开始按钮启动一次运行（本地 API、定时任务），界面中的勾选保持不变。
+// 已有运行时返回 errRunInProgress，可在任意 goroutine 调用。
+func (ui *TestUI) startBackgroundRun(form executionForm, target *remote.Target, remoteBinary string, observer runObserver) error {
+	if !form.hasTests() {
+		return errNoTestsSelected
+	}
+	config := buildExecutionConfig(form)
+	// 后台运行无法弹出许可协议，未接受时 Geekbench 改用 sysbench
+	config.GeekbenchAccepted = ui.geekbenchLicenseAccepted(config.GeekbenchVersion)
+	config.Remote = target
+	if target != nil {
+		config.RemoteBinary = remoteBinary
+	}
+	ui.applyECSBackend(&config)
+	config.Proxy = ui.proxySettings()
+	config.Mirror = ui.mirrorSettings()
+	if needsPriv, _, testsEN := needsPrivilege(config); config.Remote == nil && needsPriv && !isPrivileged() {
+		return fmt.Errorf("%w: %s", errRunNeedsPrivilege, testsEN)
+	}
+
+	ui.Mu.Lock()
+	if ui.IsRunning || (target == nil && ui.localTabRunActive()) {
+		ui.Mu.Unlock()
+		return errRunInProgress
+	}
+	ui.IsRunning = true
+	ui.mainRunLocal = target == nil
+	ui.Mu.Unlock()
+	uiDo(func() { ui.launchRun(config, observer) })
+	return nil
+}
+
+// launchRun 把界面切换到运行状态并在后台执行测试，需在界面线程调用且已置 IsRunning。
+// observer 非空时同时接收输出与结束状态。
+func (ui *TestUI) launchRun(config ExecutionConfig, observer runObserver) {
 	// 禁用开始按钮，启用停止按钮
 	ui.StartButton.Disable()
 	ui.StopButton.Enable()
+	// 远程与容器测试是单个进程，无法在阶段之间挂起
+	if config.local() {
+		ui.PauseButton.Enable()
+	}
+	if ui.stdinBar != nil {
+		ui.stdinBar.setEnabled(true)
+	}
 	ui.ProgressBar.Show()
 	ui.setStatus("status.running")
 	if ui.CurrentItem != nil {
@@ -341,38 +406,105 @@ func (ui *TestUI) startTests() {
 	if ui.Terminal != nil {
 		ui.Terminal.Clear()
 	}
+	ui.Mu.Lock()
+	ui.resultsRecord = nil
+	ui.runProcesses = nil
+	if config.local() {
+		ui.runProcesses = newProcessScope()
+	}
+	ui.Mu.Unlock()
+	if ui.speedChart != nil {
+		ui.speedChart.Reset()
+	}
 
 	// 创建新的取消上下文
 	ui.CancelCtx, ui.CancelFn = context.WithTimeout(context.Background(), 15*time.Minute)
+	ui.pauseGate = newPauseGate(ui.onPipelinePaused)
+	ui.eta = &etaEstimator{}
+	ui.CancelCtx = withPauseGate(ui.CancelCtx, ui.pauseGate)
 
 	// 在新 goroutine 中运行测试
-	go ui.runTestsWithExecutor(config)
+	go ui.runTestsWithExecutor(config, observer)
 }
 
-// stopTests 停止正在执行的测试
+// stopTests 停止正在执行的测试。第一次点击为优雅停止：取消上下文并向子进程发送 SIGINT，
+// 当前阶段收尾后结束；停止过程中再次点击则强制结束子进程。
 func (ui *TestUI) stopTests() {
 	ui.Mu.Lock()
 	if !ui.IsRunning {
 		ui.Mu.Unlock()
 		return
 	}
+	force := ui.stopping
+	ui.stopping = true
+	uiLog.Info("stop requested", "force", force)
 
-	// 调用取消函数
+	// 调用取消函数（远程测试会随之发送 SIGINT 并关闭 SSH 会话）
 	if ui.CancelFn != nil {
 		ui.CancelFn()
 	}
+	processes := ui.runProcesses
 	ui.Mu.Unlock()
+	processes.signal(force)
 
-	// 更新UI状态
+	ui.runOnUI(func() { ui.PauseButton.Disable() })
+	if force {
+		ui.runOnUI(func() { ui.StopButton.Disable() })
+		ui.Terminal.AppendText(ui.tr("log.force_stopped"))
+		return
+	}
+
+	// 更新UI状态，停止按钮切换为“强制停止”
 	ui.runOnUI(func() {
 		ui.setStatus("status.stopping")
-		ui.StopButton.Disable()
+		ui.StopButton.SetText(ui.tr("button.force_stop"))
+		ui.StopButton.Importance = widget.DangerImportance
+		ui.StopButton.Refresh()
 	})
 	ui.Terminal.AppendText(ui.tr("log.interrupted"))
 
 	// resetUIState 会在 runTestsWithExecutor 的 defer 中调用
 }
 
+// togglePause 在阶段之间暂停或继续测试；暂停请求在当前阶段结束后生效
+func (ui *TestUI) togglePause() {
+	ui.Mu.Lock()
+	gate := ui.pauseGate
+	running := ui.IsRunning && !ui.stopping
+	ui.Mu.Unlock()
+	if gate == nil || !running {
+		return
+	}
+
+	if gate.Pause() {
+		ui.runOnUI(func() {
+			ui.setStatus("status.pause_pending")
+			ui.PauseButton.SetText(ui.tr("button.resume"))
+			ui.PauseButton.SetIcon(theme.MediaPlayIcon())
+		})
+		return
+	}
+	gate.Resume()
+	ui.Terminal.AppendText(ui.tr("log.resumed"))
+	ui.runOnUI(func() {
+		ui.setStatus("status.executing")
+		ui.PauseButton.SetText(ui.tr("button.pause"))
+		ui.PauseButton.SetIcon(theme.MediaPauseIcon())
+	})
+}
+
+// onPipelinePaused 在流水线真正停在阶段边界时由执行 goroutine 调用
+func (ui *TestUI) onPipelinePaused() {
+	ui.Terminal.AppendText(ui.tr("log.paused"))
+	ui.Mu.Lock()
+	stopping := ui.stopping
+	ui.Mu.Unlock()
+	if stopping {
+		return
+	}
+	ui.runOnUI(func() { ui.setStatus("status.paused") })
+}
+
 // clearResults 清空测试结果
 func (ui *TestUI) clearResults() {
 	if ui.Terminal != nil {
@@ -380,8 +512,13 @@ func (ui *TestUI) clearResults() {
 	}
 	ui.Mu.Lock()
 	ui.StructuredResult = nil
+	ui.ParsedResults = nil
 	ui.Mu.Unlock()
 	ui.runOnUI(func() {
+		ui.renderParsedResults(nil)
+		if ui.speedChart != nil {
+			ui.speedChart.Reset()
+		}
 		ui.setStatus("status.ready")
 		ui.ProgressBar.SetValue(0)
 		if ui.CurrentItem != nil {
@@ -400,7 +537,7 @@ func (ui *TestUI) clearResults() {
 func (ui *TestUI) copyResults() {
 	var content string
 	if ui.Terminal != nil {
-		content = ui.Terminal.GetText()
+		content = ui.redactExport(ui.Terminal.GetText())
 	}
 
 	if content == "" {
@@ -413,20 +550,127 @@ func (ui *TestUI) copyResults() {
 	dialog.ShowInformation(ui.tr("dialog.success"), ui.tr("dialog.copy_ok"), ui.Window)
 }
 
-// exportResults 导出测试结果
-func (ui *TestUI) exportResults() {
-	var content string
+// exportRawResults 导出原始测试输出
+func (ui *TestUI) exportRawResults(content string) {
+	if strings.TrimSpace(content) == "" {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
+		return
+	}
+
+	ui.saveExportFile("goecs-result.md", []byte(formatResultExport(content)))
+}
+
+// exportSource 是一份待导出的结果：原始输出与解析后的结构化结果
+type exportSource struct {
+	content string
+	report  *results.Report
+	// raw 保留 ANSI 颜色，用于导出图片，为空时使用 content
+	raw string
+	// artifacts 是远程运行后取回的结果文件，按原样另存
+	artifacts []history.Artifact
+}
+
+// currentExportSource 返回当前结果页的内容；若尚未解析则从终端输出即时解析
+func (ui *TestUI) currentExportSource() exportSource {
+	var source exportSource
 	if ui.Terminal != nil {
-		content = ui.Terminal.GetText()
+		source.content = ui.Terminal.GetText()
+		source.raw = ui.Terminal.GetRawText()
 	}
+	ui.Mu.Lock()
+	source.report = ui.ParsedResults
+	ui.Mu.Unlock()
+	if source.report == nil {
+		source.report = results.Parse(source.content)
+	}
+	// 延迟、端口检测与路由监测工具页的结果、终端书签随当前报告一起导出，复制一份以免改动已解析的结果
+	latencyResults := ui.latency.results()
+	portScan := ui.ports.results()
+	monitor := ui.monitor.results()
+	var annotations []results.Annotation
+	if ui.Terminal != nil {
+		annotations = ui.Terminal.Annotations()
+	}
+	if len(latencyResults) > 0 || portScan != nil || monitor != nil || len(annotations) > 0 {
+		merged := *source.report
+		if len(latencyResults) > 0 {
+			merged.Latency = latencyResults
+		}
+		if portScan != nil {
+			merged.PortScan = portScan
+		}
+		if monitor != nil {
+			merged.Monitor = monitor
+		}
+		merged.Annotations = annotations
+		source.report = &merged
+	}
+	return source
+}
 
-	if content == "" {
+// showExportMenu 在导出按钮下方弹出格式选择菜单
+func (ui *TestUI) showExportMenu(anchor fyne.CanvasObject) {
+	ui.showExportMenuFor(anchor, ui.currentExportSource())
+}
+
+// showExportMenuFor 为指定的结果弹出导出格式菜单，隐私模式下导出脱敏后的内容
+func (ui *TestUI) showExportMenuFor(anchor fyne.CanvasObject, source exportSource) {
+	redacted := ui.redactSource(source)
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem(ui.tr("export.raw"), func() { ui.exportRawResults(redacted.content) }),
+		fyne.NewMenuItem("JSON", func() { ui.exportParsedResults(redacted.report, results.FormatJSON) }),
+		fyne.NewMenuItem("CSV", func() { ui.exportParsedResults(redacted.report, results.FormatCSV) }),
+		fyne.NewMenuItem(ui.tr("export.markdown"), func() { ui.exportParsedResults(redacted.report, results.FormatMarkdown) }),
+		fyne.NewMenuItem(ui.tr("export.html"), func() { ui.exportHTMLReport(redacted) }),
+		fyne.NewMenuItem(ui.tr("export.pdf"), func() { ui.exportParsedResults(redacted.report, results.FormatPDF) }),
+		// 图片在渲染时按对话框中的选择脱敏，传入原始内容
+		fyne.NewMenuItem(ui.tr("export.image"), func() { ui.showImageExport(source) }),
+	)
+	if len(source.artifacts) > 0 {
+		files := fyne.NewMenuItem(ui.tr("export.artifacts"), nil)
+		files.ChildMenu = fyne.NewMenu("")
+		for _, artifact := range source.artifacts {
+			files.ChildMenu.Items = append(files.ChildMenu.Items, fyne.NewMenuItem(artifact.Name, func() {
+				ui.saveExportFile(artifact.Name, artifact.Data)
+			}))
+		}
+		menu.Items = append(menu.Items, fyne.NewMenuItemSeparator(), files)
+	}
+	canvas := ui.Window.Canvas()
+	pos := fyne.CurrentApp().Driver().AbsolutePositionForObject(anchor)
+	widget.ShowPopUpMenuAtPosition(menu, canvas, pos.Add(fyne.NewPos(0, anchor.Size().Height)))
+}
+
+// exportParsedResults 将解析后的结构化结果按指定格式导出
+func (ui *TestUI) exportParsedResults(report *results.Report, format results.Format) {
+	if report.Empty() {
 		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
 		return
 	}
+	data, err := results.Encode(report, format)
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	ui.saveExportFile("goecs-result"+format.Extension(), data)
+}
 
-	defaultFilename := "goecs-result.md"
+// exportHTMLReport 导出自包含的 HTML 报告，原始输出放在报告末尾的折叠区域中
+func (ui *TestUI) exportHTMLReport(source exportSource) {
+	if source.report.Empty() {
+		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
+		return
+	}
+	data, err := results.EncodeHTML(source.report, results.HTMLOptions{Log: source.content})
+	if err != nil {
+		dialog.ShowError(err, ui.Window)
+		return
+	}
+	ui.saveExportFile("goecs-result.html", data)
+}
 
+// saveExportFile 弹出保存对话框并写入导出内容
+func (ui *TestUI) saveExportFile(defaultFilename string, data []byte) {
 	// 创建保存对话框，设置默认文件名
 	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
 		if err != nil {
@@ -438,7 +682,7 @@ func (ui *TestUI) exportResults() {
 		}
 		defer writer.Close()
 
-		_, err = writer.Write([]byte(formatResultExport(content)))
+		_, err = writer.Write(data)
 		if err != nil {
 			dialog.ShowError(err, ui.Window)
 			return
@@ -469,66 +713,6 @@ func formatResultExport(content string) string {
 	return "# GOECS Result\n\n```text\n" + clean + "\n```\n"
 }
 
-func (ui *TestUI) shareResults() {
-	var content string
-	if ui.Terminal != nil {
-		content = ui.Terminal.GetText()
-	}
-
-	if content == "" {
-		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.no_export"), ui.Window)
-		return
-	}
-
-	exportContent := formatResultExport(content)
-	if len([]byte(exportContent)) > 25*1024 {
-		dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.share_too_large"), ui.Window)
-		return
-	}
-
-	go func() {
-		tmp, err := os.CreateTemp("", "goecs-share-*.md")
-		if err != nil {
-			ui.runOnUI(func() {
-				dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.share_failed"), ui.Window)
-			})
-			return
-		}
-		defer os.Remove(tmp.Name())
-
-		if _, err := tmp.Write([]byte(exportContent)); err != nil {
-			tmp.Close()
-			ui.runOnUI(func() {
-				dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.share_failed"), ui.Window)
-			})
-			return
-		}
-		if err := tmp.Close(); err != nil {
-			ui.runOnUI(func() {
-				dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.share_failed"), ui.Window)
-			})
-			return
-		}
-
-		httpURL, httpsURL, err := apputils.UploadText(tmp.Name())
-		if err != nil {
-			ui.runOnUI(func() {
-				dialog.ShowInformation(ui.tr("dialog.hint"), ui.tr("dialog.share_failed"), ui.Window)
-			})
-			return
-		}
-
-		shareURL := httpsURL
-		if shareURL == "" {
-			shareURL = httpURL
-		}
-		ui.runOnUI(func() {
-			ui.App.Clipboard().SetContent(shareURL)
-			dialog.ShowInformation(ui.tr("dialog.success"), ui.tr("dialog.share_ok")+shareURL, ui.Window)
-		})
-	}()
-}
-
 // onLogCheckChanged 当日志复选框状态改变时调用
 func (ui *TestUI) onLogCheckChanged(checked bool) {
 	if checked {
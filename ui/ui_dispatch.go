@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"sync"
+
+	"fyne.io/fyne/v2"
+)
+
+// uiQueue 保存等待在 UI 线程执行的界面更新。所有后台 goroutine 的界面更新都经过这里，
+// 按提交顺序逐个执行，任意两个更新不会同时运行；
+// 测试驱动等在调用方 goroutine 直接执行 fyne.Do 的驱动下也是如此
+var uiQueue struct {
+	mu      sync.Mutex
+	pending []func()
+	running bool
+}
+
+// uiDo 把界面更新排入队列并在 UI 线程执行，不等待执行完成；可在任意 goroutine 调用
+func uiDo(fn func()) {
+	uiQueue.mu.Lock()
+	uiQueue.pending = append(uiQueue.pending, fn)
+	if uiQueue.running {
+		// 正在执行的队列会依次取走这个更新
+		uiQueue.mu.Unlock()
+		return
+	}
+	uiQueue.running = true
+	uiQueue.mu.Unlock()
+	fyne.Do(drainUIQueue)
+}
+
+// uiDoAndWait 与 uiDo 相同，但等待 fn 执行完成后返回；不能在界面更新内部调用
+func uiDoAndWait(fn func()) {
+	done := make(chan struct{})
+	uiDo(func() {
+		defer close(done)
+		fn()
+	})
+	<-done
+}
+
+// drainUIQueue 在 UI 线程依次执行队列中的更新，执行期间新提交的更新也会被取走
+func drainUIQueue() {
+	defer func() {
+		// 某个更新 panic 时放开队列，之后的更新由下一次 uiDo 重新调度
+		uiQueue.mu.Lock()
+		uiQueue.running = false
+		uiQueue.mu.Unlock()
+	}()
+	for {
+		uiQueue.mu.Lock()
+		if len(uiQueue.pending) == 0 {
+			uiQueue.mu.Unlock()
+			return
+		}
+		fn := uiQueue.pending[0]
+		uiQueue.pending[0] = nil
+		uiQueue.pending = uiQueue.pending[1:]
+		uiQueue.mu.Unlock()
+		fn()
+	}
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"sync"
+	"testing"
+
+	"fyne.io/fyne/v2/test"
+)
+
+func TestUIDoRunsUpdatesOneAtATime(t *testing.T) {
+	test.NewTempApp(t)
+	// 测试驱动在调用方 goroutine 直接执行 fyne.Do，并发提交时更新仍应逐个执行
+	var (
+		wg      sync.WaitGroup
+		active  int
+		overlap bool
+		order   = map[int][]int{}
+	)
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				uiDo(func() {
+					active++
+					if active > 1 {
+						overlap = true
+					}
+					order[worker] = append(order[worker], i)
+					active--
+				})
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	var nested []string
+	uiDoAndWait(func() {
+		uiDo(func() { nested = append(nested, "inner") })
+		nested = append(nested, "outer")
+	})
+	uiDoAndWait(func() {})
+	if overlap {
+		t.Fatal("UI updates ran concurrently")
+	}
+	for worker, seen := range order {
+		for i, value := range seen {
+			if value != i {
+				t.Fatalf("worker %d updates ran out of order: %v", worker, seen[:i+1])
+			}
+		}
+		if len(seen) != 200 {
+			t.Fatalf("worker %d ran %d of 200 updates", worker, len(seen))
+		}
+	}
+	if len(nested) != 2 || nested[0] != "outer" {
+		t.Fatalf("nested updates = %v, want the inner one queued after the outer", nested)
+	}
+}
@@ -2,41 +2,124 @@ package ui
 
 import (
 	"fmt"
+	"sync"
 	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
 )
 
+// runObserver 接收一次运行的输出与结束状态，供本地 API 等界面之外的调用方使用
+type runObserver interface {
+	Output(text string)
+	// Finish 在界面恢复空闲后调用，status 为 status.done、status.failed 或 status.stopped
+	Finish(status string, report *StructuredRunResult)
+}
+
 // runTestsWithExecutor 使用命令执行器运行测试
-func (ui *TestUI) runTestsWithExecutor(config ExecutionConfig) {
-	startTime := time.Now()
+func (ui *TestUI) runTestsWithExecutor(config ExecutionConfig, observer runObserver) {
+	run := newRun(ui.CancelCtx, config, nil, nil)
+	run.sudoPassword = ui.askSudoPassword
+	host := run.Host
+	ui.Mu.Lock()
+	ui.lastRunHost = host
+	ui.Mu.Unlock()
+	if ui.privacyEnabled() {
+		// 目标主机可能变了，按新主机名重新脱敏
+		ui.applyPrivacy()
+	}
+	// 运行期间把输出与已完成阶段写入磁盘，崩溃后下次启动可以恢复
+	runJournal := ui.startRunJournal(config, run.Started)
+	runnerLog.Info("run started", "host", host, "tests", run.Tests, "local", config.local())
+	finalStatus := ""
+	var finalReport *StructuredRunResult
+	finish := func(statusKey string) {
+		finalStatus = statusKey
+	}
 
 	// 添加错误恢复
 	defer func() {
 		if r := recover(); r != nil {
+			runnerLog.Error("run panicked", "host", host, "panic", r)
 			// 安全地更新UI
 			errorMsg := fmt.Sprintf("%s%s\n", ui.tr("log.fatal_prefix"), ui.tr("error.generic"))
 			ui.Terminal.AppendText(errorMsg)
+			if observer != nil {
+				observer.Output(errorMsg)
+			}
 			ui.runOnUI(func() {
 				ui.setStatus("status.failed")
 			})
-			ui.notifyTestFinished("status.failed", time.Since(startTime))
+			finalStatus = "status.failed"
+			ui.notifyTestFinished(finalStatus, run.Elapsed(), nil, finalReport)
+		}
+		if runJournal != nil {
+			_ = runJournal.Close()
 		}
 		// 确保UI状态被重置
 		ui.resetUIState()
+		if observer != nil {
+			observer.Finish(finalStatus, finalReport)
+		}
 	}()
 
 	// The build-specific runner owns the single execution. Legacy builds wrap
 	// CommandExecutor; ecs_structured builds call ecs/api directly.
-	output := func(text string) {
+	var speedMu sync.Mutex
+	var speeds results.SpeedStream
+	run.output = func(text string) {
 		// 这个回调会从 executor 的 goroutine 调用
 		// TerminalOutput 的 AppendText 已经是线程安全的
 		ui.Terminal.AppendText(text)
+		if ui.stdinBar != nil {
+			ui.stdinBar.Observe(text)
+		}
+		if runJournal != nil {
+			runJournal.Write(text)
+		}
+		if observer != nil {
+			observer.Output(text)
+		}
+		speedMu.Lock()
+		found := speeds.Feed(text)
+		speedMu.Unlock()
+		if len(found) > 0 && ui.speedChart != nil {
+			ui.runOnUI(func() {
+				for _, result := range found {
+					ui.speedChart.Add(result)
+				}
+			})
+		}
+	}
+	// 用户的钩子脚本可以拒绝运行、跟踪阶段，并在结束后按自定义策略判定结果
+	scripts := ui.loadRunHooks(config, run.Started, run.Output)
+	if err := scripts.preRun(); err != nil {
+		runnerLog.Warn("run rejected by hook", "host", host, "err", err)
+		run.Output(fmt.Sprintf(ui.tr("hooks.rejected"), err) + "\n")
+		ui.runOnUI(func() { ui.setStatus("status.stopped") })
+		finish("status.stopped")
+		return
 	}
-	progress := func(update ProgressUpdate) {
+	run.progress = func(update ProgressUpdate) {
+		if update.Done && runJournal != nil {
+			runJournal.Complete(update.ItemKey)
+		}
+		if update.Done {
+			scripts.stageComplete(update.ItemKey)
+		}
 		ui.runOnUI(func() {
 			ui.setProgress(update)
 		})
 	}
 
+	if ui.stdinBar != nil {
+		ui.stdinBar.attach(run)
+		defer ui.stdinBar.detach()
+	}
+
+	// 阶段之间没有进度事件时也定期刷新剩余时间
+	stopETA := ui.startETATicker()
+	defer stopETA()
+
 	// 更新进度
 	ui.runOnUI(func() {
 		ui.ProgressBar.SetValue(0.02)
@@ -45,7 +128,7 @@ func (ui *TestUI) runTestsWithExecutor(config ExecutionConfig) {
 
 	// Execute exactly once through the selected build backend. Structured
 	// builds receive the same cancellation context all the way into goecs/api.
-	outcome := executeWithRunner(ui.CancelCtx, newExecutionRunner(), config, output, progress)
+	outcome := executeWithRunner(executionRunnerFor(config), run)
 	err := outcome.Err
 	var reportReason string
 	structuredStatus := ""
@@ -53,13 +136,15 @@ func (ui *TestUI) runTestsWithExecutor(config ExecutionConfig) {
 		structuredStatus = outcome.Report.Status
 		_, reportReason = summarizeStructuredRun(*outcome.Report)
 		report := *outcome.Report
+		finalReport = &report
 		ui.runOnUI(func() { ui.ApplyStructuredReport(report) })
 	}
 	if err != nil && reportReason == "" {
 		ui.runOnUI(func() { ui.updatePartialReason(ui.friendlyErrorMessage(err)) })
 	}
 	if err != nil {
-		ui.Terminal.AppendText(fmt.Sprintf("%s%s\n", ui.tr("log.error_prefix"), ui.friendlyErrorMessage(err)))
+		runnerLog.Warn("run error", "host", host, "err", err)
+		run.Output(fmt.Sprintf("%s%s\n", ui.tr("log.error_prefix"), ui.friendlyErrorMessage(err)))
 	}
 
 	// A structured status is authoritative. Do not replace a partial report
@@ -68,25 +153,25 @@ func (ui *TestUI) runTestsWithExecutor(config ExecutionConfig) {
 		switch structuredStatus {
 		case "timeout":
 			ui.runOnUI(func() { ui.setStatus("status.timeout") })
-			ui.notifyTestFinished("status.failed", durationSince(startTime))
+			finish("status.failed")
 		case "canceled":
 			ui.runOnUI(func() { ui.setStatus("status.stopped") })
-			ui.notifyTestFinished("status.stopped", durationSince(startTime))
+			finish("status.stopped")
 		case "error":
 			ui.runOnUI(func() { ui.setStatus("status.failed") })
-			ui.notifyTestFinished("status.failed", durationSince(startTime))
+			finish("status.failed")
 		case "partial", "unavailable":
 			ui.runOnUI(func() {
 				ui.setStatus("status.partial")
 				ui.ProgressBar.SetValue(1)
 			})
-			ui.notifyTestFinished("status.done", durationSince(startTime))
+			finish("status.done")
 		default:
 			ui.runOnUI(func() {
 				ui.setStatus("status.done")
 				ui.ProgressBar.SetValue(1.0)
 			})
-			ui.notifyTestFinished("status.done", durationSince(startTime))
+			finish("status.done")
 		}
 	} else if err != nil {
 		// Legacy execution normally supplies a partial/error report. This branch
@@ -96,37 +181,58 @@ func (ui *TestUI) runTestsWithExecutor(config ExecutionConfig) {
 			ui.runOnUI(func() {
 				ui.setStatus("status.timeout")
 			})
-			ui.notifyTestFinished("status.failed", durationSince(startTime))
+			finish("status.failed")
 		} else if ui.isCancelled() {
 			ui.runOnUI(func() {
 				ui.setStatus("status.stopped")
 			})
-			ui.notifyTestFinished("status.stopped", durationSince(startTime))
+			finish("status.stopped")
 		} else {
 			ui.runOnUI(func() {
 				ui.setStatus("status.failed")
 			})
-			ui.notifyTestFinished("status.failed", durationSince(startTime))
+			finish("status.failed")
 		}
 	} else if ui.isTimedOut() {
 		ui.runOnUI(func() {
 			ui.setStatus("status.timeout")
 		})
-		ui.notifyTestFinished("status.failed", durationSince(startTime))
+		finish("status.failed")
 	} else if ui.isCancelled() {
-		ui.Terminal.AppendText(ui.tr("log.interrupted_short"))
+		run.Output(ui.tr("log.interrupted_short"))
 		ui.runOnUI(func() {
 			ui.setStatus("status.stopped")
 		})
-		ui.notifyTestFinished("status.stopped", durationSince(startTime))
+		finish("status.stopped")
 	} else {
 		ui.runOnUI(func() {
 			ui.setStatus("status.done")
 			ui.ProgressBar.SetValue(1.0)
 		})
-		ui.notifyTestFinished("status.done", durationSince(startTime))
+		finish("status.done")
 	}
 
+	runnerLog.Info("run finished", "host", host, "status", finalStatus, "structured", structuredStatus, "duration", run.Elapsed())
+	ui.refreshParsedResults()
+	ui.Mu.Lock()
+	parsed := ui.ParsedResults
+	ui.Mu.Unlock()
+	if err := scripts.postRun(finalStatus, parsed); err != nil {
+		runnerLog.Warn("results rejected by hook", "host", host, "err", err)
+		run.Output(fmt.Sprintf(ui.tr("hooks.policy_failed"), err) + "\n")
+		if finalStatus == "status.done" {
+			ui.runOnUI(func() { ui.setStatus("status.failed") })
+			finish("status.failed")
+		}
+	}
+	ui.notifyTestFinished(finalStatus, run.Elapsed(), parsed, finalReport)
+	ui.recordRunHistory(run, finalStatus)
+	if finalStatus == "status.done" {
+		ui.checkBaselineRegression(host, parsed, ui.Terminal.AppendText)
+	}
+	ui.pushRunNotification(host, finalStatus, run.Elapsed(), parsed, finalReport)
+	ui.autoSaveAfterRun(host)
+
 	// Structured and legacy backends use the same component log file. Refresh
 	// after every terminal state so partial and failed runs remain inspectable.
 	if config.LogEnabled {
@@ -141,3 +247,28 @@ func durationSince(start time.Time) time.Duration {
 	}
 	return time.Since(start)
 }
+
+// startETATicker 每隔几秒刷新一次预计剩余时间，返回停止函数
+func (ui *TestUI) startETATicker() func() {
+	eta := ui.currentETA()
+	if eta == nil {
+		return func() {}
+	}
+	ticker := time.NewTicker(5 * time.Second)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				update := eta.Last()
+				ui.runOnUI(func() { ui.refreshProgressText(update) })
+			}
+		}
+	}()
+	return func() {
+		ticker.Stop()
+		close(done)
+	}
+}
@@ -5,12 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"runtime"
-	"strconv"
 	"strings"
 	"time"
 
-	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/remote"
 )
 
 type uiStateSnapshot struct {
@@ -42,7 +42,7 @@ func (ui *TestUI) setDeepInputsEnabled(enabled bool) {
 }
 
 func (ui *TestUI) runOnUI(fn func()) {
-	fyne.Do(fn)
+	uiDo(fn)
 }
 
 func isMobilePlatform() bool {
@@ -78,6 +78,8 @@ func (ui *TestUI) statusBadge(statusKey string) string {
 	switch statusKey {
 	case "status.running", "status.executing":
 		return ui.tr("badge.running")
+	case "status.paused", "status.pause_pending":
+		return ui.tr("badge.paused")
 	case "status.stopping", "status.stopped":
 		return ui.tr("badge.stopped")
 	case "status.failed":
@@ -113,32 +115,33 @@ func (ui *TestUI) setProgress(update ProgressUpdate) {
 		}
 		ui.ProgressBar.SetValue(value)
 	}
-	if ui.CurrentItem != nil {
-		item := ui.tr(update.ItemKey)
-		if update.Total > 0 {
-			ui.CurrentItem.SetText(fmt.Sprintf(ui.tr("status.current"), item, update.Current, update.Total))
-		} else {
-			ui.CurrentItem.SetText(item)
-		}
+	if eta := ui.currentETA(); eta != nil {
+		eta.Observe(update, time.Now())
 	}
+	ui.refreshProgressText(update)
 }
 
-func (ui *TestUI) notifyTestFinished(statusKey string, duration time.Duration) {
-	if ui.App == nil {
+func (ui *TestUI) currentETA() *etaEstimator {
+	ui.Mu.Lock()
+	defer ui.Mu.Unlock()
+	return ui.eta
+}
+
+// refreshProgressText 显示当前阶段、已完成数量和预计剩余时间
+func (ui *TestUI) refreshProgressText(update ProgressUpdate) {
+	if ui.CurrentItem == nil || update.ItemKey == "" {
 		return
 	}
-	titleKey := "notify.done_title"
-	body := fmt.Sprintf(ui.tr("notify.done_body"), formatHumanDuration(duration, ui.uiLang))
-	switch statusKey {
-	case "status.failed":
-		titleKey = "notify.failed_title"
-		body = ui.tr("notify.failed_body")
-	case "status.stopped":
-		titleKey = "notify.stopped_title"
-		body = ui.tr("notify.stopped_body")
+	text := ui.tr(update.ItemKey)
+	if update.Total > 0 {
+		text = fmt.Sprintf(ui.tr("status.current"), text, update.Current, update.Total)
 	}
-
-	ui.App.SendNotification(fyne.NewNotification(ui.tr(titleKey), body))
+	if eta := ui.currentETA(); eta != nil {
+		if remaining, ok := eta.Remaining(time.Now()); ok && remaining >= time.Second {
+			text += fmt.Sprintf(ui.tr("status.eta"), formatHumanDuration(remaining.Round(time.Second), ui.uiLang))
+		}
+	}
+	ui.CurrentItem.SetText(text)
 }
 
 func (ui *TestUI) friendlyErrorMessage(err error) string {
@@ -147,6 +150,10 @@ func (ui *TestUI) friendlyErrorMessage(err error) string {
 	}
 	msg := strings.ToLower(err.Error())
 	switch {
+	case errors.Is(err, remote.ErrHostKeyMismatch):
+		return ui.tr("error.remote_host_key")
+	case errors.Is(err, remote.ErrNoAuth) || strings.Contains(msg, "unable to authenticate"):
+		return ui.tr("error.remote_auth")
 	case strings.Contains(msg, "取消") || strings.Contains(msg, "cancel"):
 		return ui.tr("error.cancelled")
 	case strings.Contains(msg, "permission") || strings.Contains(msg, "权限") || strings.Contains(msg, "denied"):
@@ -165,21 +172,12 @@ func (ui *TestUI) tr(key string) string {
 	if lang != langEN {
 		lang = langZH
 	}
-
-	if table, ok := i18nText[key]; ok {
-		if val, ok := table[lang]; ok && val != "" {
-			return val
-		}
-		if val, ok := table[langZH]; ok {
-			return val
-		}
-	}
-	return key
+	return translate(lang, key)
 }
 
 func (ui *TestUI) selectedLanguageCode() string {
-	if ui.LanguageSelect != nil && ui.LanguageSelect.Selected == "English" {
-		return langEN
+	if ui.LanguageSelect != nil {
+		return resolveLanguage(languageSettingByLabel(ui.LanguageSelect.Selected))
 	}
 	return langZH
 }
@@ -211,66 +209,108 @@ func (ui *TestUI) presetLabelByKey(key string) string {
 	return ui.tr("preset.custom")
 }
 
+// formChecks 返回全部复选框的状态
+func (ui *TestUI) formChecks() map[string]bool {
+	return map[string]bool{
+		"basic":        ui.BasicCheck.Checked,
+		"cpu":          ui.CpuCheck.Checked,
+		"memory":       ui.MemoryCheck.Checked,
+		"disk":         ui.DiskCheck.Checked,
+		"unlock":       ui.UnlockCheck.Checked,
+		"security":     ui.SecurityCheck.Checked,
+		"email":        ui.EmailCheck.Checked,
+		"backtrace":    ui.BacktraceCheck.Checked,
+		"nt3":          ui.Nt3Check.Checked,
+		"speed":        ui.SpeedCheck.Checked,
+		"ping":         ui.PingCheck.Checked,
+		"gpu":          ui.GPUCheck.Checked,
+		"gpuBench":     ui.GPUBenchCheck.Checked,
+		"diskMulti":    ui.DiskMultiCheck.Checked,
+		"deepMode":     ui.DeepModeCheck.Checked,
+		"chinaMode":    ui.ChinaModeCheck.Checked,
+		"pingTgdc":     ui.PingTgdcCheck.Checked,
+		"pingWeb":      ui.PingWebCheck.Checked,
+		"enableLog":    ui.LogCheck.Checked,
+		"autoDisk":     ui.AutoDiskMethodCheck.Checked,
+		"diskSafeMode": ui.DiskSafeModeCheck.Checked,
+		"unlockShowIP": ui.UnlockShowIPCheck.Checked,
+		"resultUpload": ui.ResultUploadCheck.Checked,
+		"analysis":     ui.AnalyzeResultCheck.Checked,
+		"dataOffline":  ui.DataOfflineCheck.Checked,
+		"privacyMode":  ui.PrivacyModeCheck.Checked,
+		"offlineMode":  ui.OfflineModeCheck.Checked,
+		"remote":       ui.RemoteEnableCheck.Checked,
+		"remoteClean":  ui.RemoteCleanupCheck.Checked,
+		"remoteWin":    ui.RemoteWindowsCheck.Checked,
+	}
+}
+
+// formSelections 返回全部选择框的值；解锁地区保存为与语言无关的代码
+func (ui *TestUI) formSelections() map[string]string {
+	return map[string]string{
+		"language":         ui.LanguageSelect.Selected,
+		"theme":            ui.themeMode,
+		"cpuMethod":        ui.CpuMethodSelect.Selected,
+		"geekbenchVersion": ui.GeekbenchVersionSelect.Selected,
+		"threadMode":       ui.ThreadModeSelect.Selected,
+		"memMethod":        ui.MemoryMethodSelect.Selected,
+		"diskMethod":       ui.DiskMethodSelect.Selected,
+		"nt3Loc":           ui.Nt3LocationSelect.Selected,
+		"nt3Type":          ui.Nt3TypeSelect.Selected,
+		"pingSort":         ui.PingSortSelect.Selected,
+		"pingScope":        ui.PingScopeSelect.Selected,
+		"tcpSort":          ui.TCPSortSelect.Selected,
+		"unlockRegion":     unlockRegionLabelToCode(ui.UnlockRegionSelect.Selected, ui.uiLang),
+		"unlockIpVer":      ui.UnlockIpVersionSelect.Selected,
+	}
+}
+
+// formEntries 返回全部输入框的文本
+func (ui *TestUI) formEntries() map[string]string {
+	return map[string]string{
+		"diskPath":          ui.DiskPathEntry.Text,
+		"diskBlockSizes":    ui.DiskBlockSizesEntry.Text,
+		"diskFileSize":      ui.DiskFileSizeEntry.Text,
+		"deepDiskPaths":     ui.DeepDiskPathsEntry.Text,
+		"deepSMART":         ui.DeepSMARTEntry.Text,
+		"deepBurn":          ui.DeepBurnEntry.Text,
+		"deepGPU":           ui.DeepGPUEntry.Text,
+		"spNum":             ui.SpNumEntry.Text,
+		"speedNodes":        ui.speedNodes,
+		"iperfTargets":      ui.iperfTargets,
+		"tunnelInterface":   ui.tunnelInterface,
+		"stageTimeouts":     ui.stageTimeouts,
+		"stageRetries":      ui.stageRetries,
+		"retryBackoff":      ui.retryBackoff,
+		"networkLoad":       ui.networkLoad,
+		"bandwidthCap":      ui.bandwidthCap,
+		"backendEnv":        ui.backendEnv,
+		"backendFlags":      ui.backendFlags,
+		"outputWidth":       ui.OutputWidthEntry.Text,
+		"outputFile":        ui.OutputFileEntry.Text,
+		"jsonPath":          ui.JSONPathEntry.Text,
+		"maxDuration":       ui.MaxDurationEntry.Text,
+		"hardwareBudget":    ui.HardwareBudgetEntry.Text,
+		"unlockInterface":   ui.UnlockInterfaceEntry.Text,
+		"unlockDNS":         ui.UnlockDNSEntry.Text,
+		"unlockHTTPProxy":   ui.UnlockHTTPProxyEntry.Text,
+		"unlockSOCKSProxy":  ui.UnlockSOCKSProxyEntry.Text,
+		"unlockConcurrency": ui.UnlockConcurrencyEntry.Text,
+		"remoteHost":        ui.RemoteHostEntry.Text,
+		"remotePort":        ui.RemotePortEntry.Text,
+		"remoteUser":        ui.RemoteUserEntry.Text,
+		"remotePassword":    ui.RemotePasswordEntry.Text,
+		"remoteKeyPath":     ui.RemoteKeyPathEntry.Text,
+		"remotePassphrase":  ui.RemotePassphraseEntry.Text,
+		"remoteBinary":      ui.RemoteBinaryEntry.Text,
+	}
+}
+
 func (ui *TestUI) snapshotUIState() uiStateSnapshot {
 	state := uiStateSnapshot{
-		checks: map[string]bool{
-			"basic":        ui.BasicCheck.Checked,
-			"cpu":          ui.CpuCheck.Checked,
-			"memory":       ui.MemoryCheck.Checked,
-			"disk":         ui.DiskCheck.Checked,
-			"unlock":       ui.UnlockCheck.Checked,
-			"security":     ui.SecurityCheck.Checked,
-			"email":        ui.EmailCheck.Checked,
-			"backtrace":    ui.BacktraceCheck.Checked,
-			"nt3":          ui.Nt3Check.Checked,
-			"speed":        ui.SpeedCheck.Checked,
-			"ping":         ui.PingCheck.Checked,
-			"diskMulti":    ui.DiskMultiCheck.Checked,
-			"deepMode":     ui.DeepModeCheck.Checked,
-			"chinaMode":    ui.ChinaModeCheck.Checked,
-			"pingTgdc":     ui.PingTgdcCheck.Checked,
-			"pingWeb":      ui.PingWebCheck.Checked,
-			"enableLog":    ui.LogCheck.Checked,
-			"autoDisk":     ui.AutoDiskMethodCheck.Checked,
-			"unlockShowIP": ui.UnlockShowIPCheck.Checked,
-			"resultUpload": ui.ResultUploadCheck.Checked,
-			"analysis":     ui.AnalyzeResultCheck.Checked,
-			"dataOffline":  ui.DataOfflineCheck.Checked,
-			"privacyMode":  ui.PrivacyModeCheck.Checked,
-		},
-		selections: map[string]string{
-			"language":     ui.LanguageSelect.Selected,
-			"theme":        ui.themeMode,
-			"cpuMethod":    ui.CpuMethodSelect.Selected,
-			"threadMode":   ui.ThreadModeSelect.Selected,
-			"memMethod":    ui.MemoryMethodSelect.Selected,
-			"diskMethod":   ui.DiskMethodSelect.Selected,
-			"nt3Loc":       ui.Nt3LocationSelect.Selected,
-			"nt3Type":      ui.Nt3TypeSelect.Selected,
-			"pingSort":     ui.PingSortSelect.Selected,
-			"pingScope":    ui.PingScopeSelect.Selected,
-			"tcpSort":      ui.TCPSortSelect.Selected,
-			"unlockRegion": unlockRegionLabelToCode(ui.UnlockRegionSelect.Selected, ui.uiLang),
-			"unlockIpVer":  ui.UnlockIpVersionSelect.Selected,
-		},
-		entries: map[string]string{
-			"diskPath":          ui.DiskPathEntry.Text,
-			"deepDiskPaths":     ui.DeepDiskPathsEntry.Text,
-			"deepSMART":         ui.DeepSMARTEntry.Text,
-			"deepBurn":          ui.DeepBurnEntry.Text,
-			"deepGPU":           ui.DeepGPUEntry.Text,
-			"spNum":             ui.SpNumEntry.Text,
-			"outputWidth":       ui.OutputWidthEntry.Text,
-			"outputFile":        ui.OutputFileEntry.Text,
-			"jsonPath":          ui.JSONPathEntry.Text,
-			"maxDuration":       ui.MaxDurationEntry.Text,
-			"hardwareBudget":    ui.HardwareBudgetEntry.Text,
-			"unlockInterface":   ui.UnlockInterfaceEntry.Text,
-			"unlockDNS":         ui.UnlockDNSEntry.Text,
-			"unlockHTTPProxy":   ui.UnlockHTTPProxyEntry.Text,
-			"unlockSOCKSProxy":  ui.UnlockSOCKSProxyEntry.Text,
-			"unlockConcurrency": ui.UnlockConcurrencyEntry.Text,
-		},
+		checks:     ui.formChecks(),
+		selections: ui.formSelections(),
+		entries:    ui.formEntries(),
 		presetKey:  ui.selectedPresetKey,
 		logContent: ui.LogContent,
 		logEnabled: ui.LogCheck.Checked,
@@ -284,7 +324,7 @@ func (ui *TestUI) snapshotUIState() uiStateSnapshot {
 	}
 
 	if ui.Terminal != nil {
-		state.terminalText = ui.Terminal.GetText()
+		state.terminalText = ui.Terminal.GetRawText()
 	}
 
 	return state
@@ -307,6 +347,8 @@ func (ui *TestUI) restoreUIState(state uiStateSnapshot) {
 	ui.Nt3Check.Checked = state.checks["nt3"]
 	ui.SpeedCheck.Checked = state.checks["speed"]
 	ui.PingCheck.Checked = state.checks["ping"]
+	ui.GPUCheck.SetChecked(state.checks["gpu"])
+	ui.GPUBenchCheck.Checked = state.checks["gpuBench"]
 	ui.DiskMultiCheck.Checked = state.checks["diskMulti"]
 	ui.DeepModeCheck.Checked = state.checks["deepMode"]
 	ui.setDeepInputsEnabled(ui.DeepModeCheck.Checked)
@@ -315,11 +357,18 @@ func (ui *TestUI) restoreUIState(state uiStateSnapshot) {
 	ui.PingWebCheck.Checked = state.checks["pingWeb"]
 	ui.LogCheck.Checked = state.checks["enableLog"]
 	ui.AutoDiskMethodCheck.Checked = state.checks["autoDisk"]
+	if safe, ok := state.checks["diskSafeMode"]; ok {
+		ui.DiskSafeModeCheck.Checked = safe
+	}
 	ui.UnlockShowIPCheck.Checked = state.checks["unlockShowIP"]
 	ui.ResultUploadCheck.Checked = state.checks["resultUpload"]
 	ui.AnalyzeResultCheck.Checked = state.checks["analysis"]
 	ui.DataOfflineCheck.Checked = state.checks["dataOffline"]
 	ui.PrivacyModeCheck.Checked = state.checks["privacyMode"]
+	ui.OfflineModeCheck.SetChecked(state.checks["offlineMode"])
+	ui.RemoteEnableCheck.SetChecked(state.checks["remote"])
+	ui.RemoteCleanupCheck.SetChecked(state.checks["remoteClean"])
+	ui.RemoteWindowsCheck.SetChecked(state.checks["remoteWin"])
 
 	ui.LanguageSelect.SetSelected(state.selections["language"])
 	if ui.ThemeSelect != nil {
@@ -331,6 +380,10 @@ func (ui *TestUI) restoreUIState(state uiStateSnapshot) {
 		ui.ThemeSelect.SetSelected(ui.themeLabelByMode(mode))
 	}
 	ui.CpuMethodSelect.SetSelected(state.selections["cpuMethod"])
+	if value := state.selections["geekbenchVersion"]; value != "" {
+		ui.GeekbenchVersionSelect.SetSelected(value)
+	}
+	ui.refreshGeekbenchVersion()
 	ui.ThreadModeSelect.SetSelected(state.selections["threadMode"])
 	ui.MemoryMethodSelect.SetSelected(state.selections["memMethod"])
 	ui.DiskMethodSelect.SetSelected(state.selections["diskMethod"])
@@ -353,11 +406,20 @@ func (ui *TestUI) restoreUIState(state uiStateSnapshot) {
 	}
 
 	ui.DiskPathEntry.SetText(state.entries["diskPath"])
+	ui.DiskBlockSizesEntry.SetText(state.entries["diskBlockSizes"])
+	ui.DiskFileSizeEntry.SetText(state.entries["diskFileSize"])
 	ui.DeepDiskPathsEntry.SetText(state.entries["deepDiskPaths"])
 	ui.DeepSMARTEntry.SetText(state.entries["deepSMART"])
 	ui.DeepBurnEntry.SetText(state.entries["deepBurn"])
 	ui.DeepGPUEntry.SetText(state.entries["deepGPU"])
 	ui.SpNumEntry.SetText(state.entries["spNum"])
+	ui.setSpeedNodes(state.entries["speedNodes"])
+	ui.setIperfTargets(state.entries["iperfTargets"])
+	ui.setTunnelInterface(state.entries["tunnelInterface"])
+	ui.setStageTimeouts(state.entries["stageTimeouts"])
+	ui.stageRetries, ui.retryBackoff = state.entries["stageRetries"], state.entries["retryBackoff"]
+	ui.setNetworkLimits(state.entries["networkLoad"], state.entries["bandwidthCap"])
+	ui.setBackendPassthrough(state.entries["backendEnv"], state.entries["backendFlags"])
 	ui.OutputWidthEntry.SetText(state.entries["outputWidth"])
 	ui.OutputFileEntry.SetText(state.entries["outputFile"])
 	ui.JSONPathEntry.SetText(state.entries["jsonPath"])
@@ -368,6 +430,13 @@ func (ui *TestUI) restoreUIState(state uiStateSnapshot) {
 	ui.UnlockHTTPProxyEntry.SetText(state.entries["unlockHTTPProxy"])
 	ui.UnlockSOCKSProxyEntry.SetText(state.entries["unlockSOCKSProxy"])
 	ui.UnlockConcurrencyEntry.SetText(state.entries["unlockConcurrency"])
+	ui.RemoteHostEntry.SetText(state.entries["remoteHost"])
+	ui.RemotePortEntry.SetText(state.entries["remotePort"])
+	ui.RemoteUserEntry.SetText(state.entries["remoteUser"])
+	ui.RemotePasswordEntry.SetText(state.entries["remotePassword"])
+	ui.RemoteKeyPathEntry.SetText(state.entries["remoteKeyPath"])
+	ui.RemotePassphraseEntry.SetText(state.entries["remotePassphrase"])
+	ui.RemoteBinaryEntry.SetText(state.entries["remoteBinary"])
 
 	ui.refreshAllChecks()
 	ui.refreshSpeedTestChecks()
@@ -402,7 +471,8 @@ func (ui *TestUI) hasSelectedTests() bool {
 		}
 	}
 	return (ui.PingTgdcCheck != nil && ui.PingTgdcCheck.Checked) ||
-		(ui.PingWebCheck != nil && ui.PingWebCheck.Checked)
+		(ui.PingWebCheck != nil && ui.PingWebCheck.Checked) ||
+		(ui.GPUCheck != nil && ui.GPUCheck.Checked)
 }
 
 // isCancelled 检查测试是否被取消
@@ -435,6 +505,10 @@ func (ui *TestUI) isRunning() bool {
 func (ui *TestUI) resetUIState() {
 	ui.Mu.Lock()
 	ui.IsRunning = false
+	ui.stopping = false
+	ui.pauseGate = nil
+	ui.eta = nil
+	ui.runProcesses = nil
 	cancel := ui.CancelFn
 	ui.CancelFn = nil
 	ui.CancelCtx = nil
@@ -446,15 +520,31 @@ func (ui *TestUI) resetUIState() {
 	ui.runOnUI(func() {
 		ui.StartButton.Enable()
 		ui.StopButton.Disable()
+		ui.StopButton.SetText(ui.tr("button.stop"))
+		ui.StopButton.Importance = widget.MediumImportance
+		ui.StopButton.Refresh()
+		ui.PauseButton.Disable()
+		ui.PauseButton.SetText(ui.tr("button.pause"))
+		ui.PauseButton.SetIcon(theme.MediaPauseIcon())
+		if ui.stdinBar != nil {
+			ui.stdinBar.setEnabled(false)
+		}
 		ui.ProgressBar.Hide()
 		ui.ProgressBar.SetValue(0)
 		if ui.CurrentItem != nil {
 			ui.CurrentItem.SetText(ui.tr("progress.idle"))
 		}
+		if ui.Terminal != nil {
+			ui.Terminal.sync() // 最后一个阶段也已结束，按需自动折叠
+		}
 
 		if ui.StatusLabel.Text == ui.tr("status.stopping") {
 			ui.setStatus("status.stopped")
 		}
+		// 本机主运行结束后，排队的本机任务可以开始
+		if ui.queue != nil {
+			ui.queue.dispatch()
+		}
 	})
 }
 
@@ -474,169 +564,3 @@ func (ui *TestUI) GetSelectedOptions() map[string]bool {
 		"ping":      ui.PingCheck.Checked,
 	}
 }
-
-func (ui *TestUI) collectExecutionConfig() ExecutionConfig {
-	language := "zh"
-	if ui.selectedLanguageCode() == langEN {
-		language = "en"
-	}
-
-	cpuMethod := ui.CpuMethodSelect.Selected
-	if cpuMethod == "" {
-		cpuMethod = "sysbench"
-	}
-
-	threadMode := ui.ThreadModeSelect.Selected
-	if threadMode == "" {
-		threadMode = "multi"
-	}
-
-	memoryMethod := ui.MemoryMethodSelect.Selected
-	if memoryMethod == "" {
-		memoryMethod = "stream"
-	}
-
-	diskMethod := ui.DiskMethodSelect.Selected
-	if diskMethod == "" {
-		diskMethod = "fio"
-	}
-
-	nt3Location := ui.Nt3LocationSelect.Selected
-	if nt3Location == "" {
-		nt3Location = "GZ"
-	}
-
-	nt3Type := ui.Nt3TypeSelect.Selected
-	if nt3Type == "" {
-		nt3Type = "both"
-	}
-
-	spNum := 2
-	if ui.SpNumEntry.Text != "" {
-		if parsed, err := strconv.Atoi(ui.SpNumEntry.Text); err == nil && parsed > 0 {
-			spNum = parsed
-		}
-	}
-	if spNum > 20 {
-		spNum = 20
-	}
-
-	outputWidth := 82
-	if ui.OutputWidthEntry.Text != "" {
-		if parsed, err := strconv.Atoi(ui.OutputWidthEntry.Text); err == nil && parsed >= 60 {
-			outputWidth = parsed
-		}
-	}
-	if outputWidth > 120 {
-		outputWidth = 120
-	}
-
-	logEnabled := false
-	if ui.LogCheck != nil {
-		logEnabled = ui.LogCheck.Checked
-	}
-
-	pingTgdc := ui.PingTgdcCheck.Checked
-	pingWeb := ui.PingWebCheck.Checked
-	pingSortOrder := selectedOrDefault(ui.PingSortSelect, "latency")
-	pingScope := selectedOrDefault(ui.PingScopeSelect, "auto")
-	tcpSortOrder := selectedOrDefault(ui.TCPSortSelect, "name")
-
-	unlockRegion := unlockRegionLabelToCode(ui.UnlockRegionSelect.Selected, language)
-	if unlockRegion == "" {
-		unlockRegion = "0"
-	}
-	unlockIpVersion := ui.UnlockIpVersionSelect.Selected
-	if unlockIpVersion == "" {
-		unlockIpVersion = "auto"
-	}
-	unlockConcurrency := 20
-	if value := strings.TrimSpace(ui.UnlockConcurrencyEntry.Text); value != "" {
-		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
-			unlockConcurrency = parsed
-		}
-	}
-	if unlockConcurrency > 100 {
-		unlockConcurrency = 100
-	}
-	filePath := ui.OutputFileEntry.Text
-	if filePath == "" {
-		filePath = "goecs.md"
-	}
-	deepMode := ui.DeepModeCheck.Checked
-	deepDiskPaths, deepSMARTDevices, deepGPUDevice := "", "", ""
-	deepBurnDuration := time.Duration(0)
-	if deepMode {
-		deepDiskPaths = strings.TrimSpace(ui.DeepDiskPathsEntry.Text)
-		deepSMARTDevices = strings.TrimSpace(ui.DeepSMARTEntry.Text)
-		deepGPUDevice = strings.TrimSpace(ui.DeepGPUEntry.Text)
-		if value := strings.TrimSpace(ui.DeepBurnEntry.Text); value != "" {
-			if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
-				deepBurnDuration = parsed
-			}
-		}
-	}
-	maxDuration := 15 * time.Minute
-	if value := strings.TrimSpace(ui.MaxDurationEntry.Text); value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 && parsed <= 15*time.Minute {
-			maxDuration = parsed
-		}
-	}
-	hardwareBudgetLimit := min(2*time.Minute, maxDuration)
-	if deepMode {
-		hardwareBudgetLimit = maxDuration
-	}
-	hardwareBudget := hardwareBudgetLimit
-	if value := strings.TrimSpace(ui.HardwareBudgetEntry.Text); value != "" {
-		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 && parsed <= hardwareBudgetLimit {
-			hardwareBudget = parsed
-		}
-	}
-	privacyMode := ui.PrivacyModeCheck.Checked
-	enableUpload := ui.ResultUploadCheck.Checked && !privacyMode
-
-	return ExecutionConfig{
-		SelectedOptions:   ui.GetSelectedOptions(),
-		Language:          language,
-		ChinaModeEnabled:  ui.ChinaModeCheck.Checked,
-		DeepMode:          deepMode,
-		DeepDiskPaths:     deepDiskPaths,
-		DeepSMARTDevices:  deepSMARTDevices,
-		DeepBurnDuration:  deepBurnDuration,
-		DeepGPUDevice:     deepGPUDevice,
-		AutoDiskMethod:    ui.AutoDiskMethodCheck.Checked,
-		CpuMethod:         cpuMethod,
-		ThreadMode:        threadMode,
-		MemoryMethod:      memoryMethod,
-		DiskMethod:        diskMethod,
-		DiskPath:          ui.DiskPathEntry.Text,
-		DiskMulti:         ui.DiskMultiCheck.Checked,
-		Nt3Location:       nt3Location,
-		Nt3Type:           nt3Type,
-		SpNum:             spNum,
-		PingSortOrder:     pingSortOrder,
-		PingScope:         pingScope,
-		TCPSortOrder:      tcpSortOrder,
-		PingTgdc:          pingTgdc,
-		PingWeb:           pingWeb,
-		UnlockRegion:      unlockRegion,
-		UnlockIpVersion:   unlockIpVersion,
-		UnlockShowIP:      ui.UnlockShowIPCheck.Checked,
-		UnlockInterface:   strings.TrimSpace(ui.UnlockInterfaceEntry.Text),
-		UnlockDNS:         strings.TrimSpace(ui.UnlockDNSEntry.Text),
-		UnlockHTTPProxy:   strings.TrimSpace(ui.UnlockHTTPProxyEntry.Text),
-		UnlockSOCKSProxy:  strings.TrimSpace(ui.UnlockSOCKSProxyEntry.Text),
-		UnlockConcurrency: unlockConcurrency,
-		EnableUpload:      enableUpload,
-		AnalyzeResult:     ui.AnalyzeResultCheck.Checked,
-		FilePath:          filePath,
-		JSONPath:          strings.TrimSpace(ui.JSONPathEntry.Text),
-		OutputWidth:       outputWidth,
-		MaxDuration:       maxDuration,
-		HardwareBudget:    hardwareBudget,
-		DataOffline:       ui.DataOfflineCheck.Checked,
-		PrivacyMode:       privacyMode,
-		PresetKey:         ui.selectedPresetKey,
-		LogEnabled:        logEnabled,
-	}
-}
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"image/color"
+	"math"
+	"os"
 	"regexp"
 	"runtime"
 	"strings"
@@ -8,48 +11,181 @@ import (
 	"time"
 
 	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"golang.org/x/text/width"
+
+	"github.com/oneclickvirt/ecs-gui/results"
 )
 
 var ansiRegex = regexp.MustCompile(`\x1B\[[0-9;]*[a-zA-Z]`)
 
-// TerminalOutput 是一个类似终端的输出组件
+const (
+	terminalTrimmedNotice = "[历史输出过长，已保留最近内容]"
+	terminalDroppedNotice = "\n[输出过快，已丢弃部分历史日志]\n"
+)
+
+// terminalLine 是缓冲区中的一行（不含换行符）
+type terminalLine struct {
+	raw   string    // 原始内容，保留 ANSI 序列
+	plain string    // 纯文本，用于搜索、过滤与导出
+	style ansiStyle // 行首样式，单独渲染该行时从这里开始解析
+	cols  int       // 显示宽度（列），全角字符按 2 列计
+	at    time.Time // 行首文字写入的时间，整体载入的文本为零值
+	// header 表示该行是阶段标题（如 "----CPU测试----"），可折叠其后的内容
+	header bool
+	// masked 是隐私模式下显示的内容，与原内容相同或未开启时为 nil
+	masked *terminalLine
+}
+
+// newTerminalLine 用解析器 p 解析一行，p 的状态会前进到行尾
+func newTerminalLine(p *ansiParser, raw string) terminalLine {
+	line := terminalLine{raw: raw, style: p.style}
+	line.plain = joinANSIText(p.Parse(raw))
+	line.cols = displayColumns(line.plain)
+	line.header = isTerminalSectionHeader(line.plain)
+	return line
+}
+
+// maskTerminalLine 用 redact 对一行脱敏，内容不变时返回 nil。颜色序列打断匹配时退回不带颜色的纯文本。
+func maskTerminalLine(line terminalLine, redact func(string) string) *terminalLine {
+	if redact == nil {
+		return nil
+	}
+	plain := redact(line.plain)
+	if plain == line.plain {
+		return nil
+	}
+	p := ansiParser{style: line.style}
+	masked := newTerminalLine(&p, redact(line.raw))
+	if masked.plain != plain {
+		p = ansiParser{style: line.style}
+		masked = newTerminalLine(&p, plain)
+	}
+	masked.at = line.at
+	return &masked
+}
+
+func displayColumns(s string) int {
+	cols := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			cols += 2
+		default:
+			cols++
+		}
+	}
+	return cols
+}
+
+// TerminalOutput 是一个类似终端的输出组件：内容按行保存，只绘制可见的行，
+// 因此内存与绘制开销不随日志长度增长。ANSI 颜色会被保留用于渲染。
 type TerminalOutput struct {
-	widget.Entry
+	widget.BaseWidget
 	mu          sync.Mutex
 	closeOnce   sync.Once
-	content     string        // 存储完整内容
-	maxBytes    int           // 最大字节数限制
-	maxLines    int           // 最大显示行数
-	maxPending  int           // 待刷新文本最大字节数
-	pendingText string        // 待刷新的文本
-	updateChan  chan string   // 更新通道
-	stopChan    chan struct{} // 停止通道
+	lines       []terminalLine      // 已以换行结束的行
+	open        terminalLine        // 末尾尚未结束的行
+	openParser  ansiParser          // 末行行首的解析状态
+	emu         terminalEmuState    // 光标位置，用于 \r 与光标控制序列的原地改写
+	origin      time.Time           // 清空后第一行的写入时间，耗时列以此为起点
+	gutter      string              // 行首时间列的显示方式，见 terminalGutterModes
+	bytes       int                 // lines 占用的字节数
+	maxCols     int                 // 最长行的列数
+	dropped     int                 // 因超出上限被丢弃的行数
+	spill       bool                // 超出上限的旧行是否转存到磁盘
+	spillFile   *os.File            // 转存文件，首次转存时创建
+	spilled     int                 // 已转存到磁盘的行数
+	maxBytes    int                 // 最大字节数限制
+	maxLines    int                 // 最大保留行数
+	maxPending  int                 // 待刷新文本最大字节数
+	redact      func(string) string // 隐私模式的脱敏函数，nil 表示关闭
+	pendingText string              // 待刷新的文本
+	pendingAt   []pendingMark       // 待刷新文本中各段的写入时间
+	rewritten   bool                // 已完成的行被改写、移出或清空，下次同步需要全部重新计算
+	block       bool                // 待刷新文本已满时阻塞写入方，而不是跳过较早的待刷新文本
+	drained     *sync.Cond          // 待刷新文本被取走或组件销毁时唤醒阻塞的写入方
+	closed      bool                // 组件已销毁，不再阻塞写入方
+	skipped     int                 // 合并模式下跳过的待刷新字节数
+	waited      int                 // 阻塞模式下写入方等待刷新的次数
+	stopChan    chan struct{}       // 停止通道
+
+	// 以下字段仅在 UI 线程访问
+	snapshot       []terminalLine             // 最近一次同步的已完成行
+	snapshotOpen   terminalLine               // 最近一次同步的末行
+	rows           []int                      // 过滤后显示的行号，nil 表示全部显示
+	generation     int                        // 显示内容版本，变化时可见行需要重建
+	overflow       [4]int                     // 最近一次通知的丢弃、转存行数，跳过的字节数与等待次数
+	search         terminalSearchOptions      // 当前搜索条件
+	matches        []terminalMatch            // 搜索命中位置
+	activeMatch    int                        // 当前定位的命中
+	selection      terminalSelection          // 鼠标选择的区间
+	filter         terminalFilter             // 当前行过滤条件
+	folds          terminalFolds              // 各阶段的折叠状态
+	autoFold       bool                       // 阶段结束后自动折叠
+	headers        []int                      // 快照中阶段标题行的行号
+	lineBase       int                        // 快照第一行之前已移出内存的行数
+	bookmarks      map[int]terminalBookmark   // 书签，键为绝对行号
+	bookmarkCursor int                        // 最近跳转到的书签，-1 表示尚未跳转
+	wrap           bool                       // 是否按可见宽度自动换行
+	wrapCols       int                        // 最近一次换行使用的列数
+	wrapped        []terminalSegment          // 自动换行后的显示行，nil 表示未开启
+	hscroll        float32                    // 开启自动换行前的横向滚动位置
+	derived        int                        // 上次同步时已完成的行数，增量同步从这里开始
+	filterRows     []int                      // 过滤后保留的行号，nil 表示未过滤
+	filterSection  results.Section            // 过滤到第 derived 行时所在的分区
+	fontSource     fyne.Resource              // 自定义字体，nil 使用内置等宽字体
+	fontSize       float32                    // 字号，0 跟随主题
+	scroll         *container.Scroll          // 可见区域
+	body           *terminalBody              // 按需绘制可见行的内容层
+	OnSearchUpdate func(current, total int)   // 命中数量变化时回调
+	OnFilterUpdate func(shown, total int)     // 过滤后显示行数变化时回调
+	OnOverflow     func(dropped, spilled int) // 丢弃或转存的行数变化时回调
+	// OnBackpressure 在输出过快时回调：skipped 为合并模式下跳过的字节数，waited 为阻塞模式下写入方等待的次数
+	OnBackpressure func(skipped, waited int)
+	// OnContentChanged 在内容重新渲染后回调：appended 为新增行数，reset 表示内容被清空或整体替换
+	OnContentChanged func(appended int, reset bool)
+	// OnScrolled 在用户或程序滚动后回调
+	OnScrolled func(fyne.Position)
+	// Translate 用于右键菜单文字，未设置时显示键名
+	Translate func(string) string
+	// IPActions 返回点击 IP 地址时的菜单项，未设置时只提供复制
+	IPActions func(ip string) []*fyne.MenuItem
+	// OnZoom 在快捷键或 Ctrl+滚轮改变字号后回调，0 表示恢复跟随主题
+	OnZoom func(size float32)
+	// Foldable 为 true 时在行首显示阶段折叠标记
+	Foldable bool
+	// Bookmarkable 为 true 时在行首显示书签列，点击即可为该行添加书签
+	Bookmarkable bool
+	// Running 报告输出是否仍在进行，此时最后一个阶段不算已结束；nil 表示已结束
+	Running func() bool
 }
 
 // NewTerminalOutput 创建新的终端输出组件
 func NewTerminalOutput() *TerminalOutput {
-	maxBytes := 2 * 1024 * 1024
-	maxPending := 128 * 1024
-	maxLines := 5000
+	maxBytes, _ := parseTerminalBuffer(defaultTerminalBuffer())
+	maxPending := 256 * 1024
+	maxLines := 100000
 	if runtime.GOOS == "android" || runtime.GOOS == "ios" {
-		maxBytes = 768 * 1024
 		maxPending = 64 * 1024
-		maxLines = 1600
+		maxLines = 20000
 	}
 	terminal := &TerminalOutput{
-		content:    "",
 		maxBytes:   maxBytes,
 		maxLines:   maxLines,
 		maxPending: maxPending,
-		updateChan: make(chan string, 96),
 		stopChan:   make(chan struct{}),
+
+		bookmarkCursor: -1,
 	}
+	terminal.drained = sync.NewCond(&terminal.mu)
+	terminal.body = newTerminalBody(terminal)
+	terminal.scroll = container.NewScroll(terminal.body)
+	terminal.scroll.OnScrolled = terminal.handleScrolled
 	terminal.ExtendBaseWidget(terminal)
-	terminal.MultiLine = true
-	terminal.Wrapping = fyne.TextWrapOff // 禁用自动换行，支持水平滚动
-	terminal.TextStyle = fyne.TextStyle{Monospace: true}
-	terminal.Disable() // 禁用编辑
 
 	// 启动批量更新 goroutine
 	go terminal.batchUpdateLoop()
@@ -57,6 +193,41 @@ func NewTerminalOutput() *TerminalOutput {
 	return terminal
 }
 
+// CreateRenderer 实现 fyne.Widget：配色方案的背景在下，滚动区域在上
+func (t *TerminalOutput) CreateRenderer() fyne.WidgetRenderer {
+	r := &terminalRenderer{term: t, background: canvas.NewRectangle(color.Transparent)}
+	r.Refresh()
+	return r
+}
+
+type terminalRenderer struct {
+	term       *TerminalOutput
+	background *canvas.Rectangle
+}
+
+func (r *terminalRenderer) Destroy() {}
+
+func (r *terminalRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+	r.term.scroll.Resize(size)
+	r.term.rewrap()
+}
+
+func (r *terminalRenderer) MinSize() fyne.Size {
+	return r.term.scroll.MinSize()
+}
+
+func (r *terminalRenderer) Objects() []fyne.CanvasObject {
+	return []fyne.CanvasObject{r.background, r.term.scroll}
+}
+
+func (r *terminalRenderer) Refresh() {
+	th := r.term.Theme()
+	r.background.FillColor = th.Color(terminalColorBackground, fyne.CurrentApp().Settings().ThemeVariant())
+	r.background.Refresh()
+	r.term.scroll.Refresh()
+}
+
 // batchUpdateLoop 批量更新循环，减少UI刷新频率
 func (t *TerminalOutput) batchUpdateLoop() {
 	interval := 180 * time.Millisecond
@@ -70,92 +241,112 @@ func (t *TerminalOutput) batchUpdateLoop() {
 		select {
 		case <-t.stopChan:
 			return
-		case text := <-t.updateChan:
-			t.mu.Lock()
-			t.appendPendingLocked(text)
-			t.mu.Unlock()
 		case <-ticker.C:
 			t.mu.Lock()
-			if t.pendingText != "" {
-				t.content += t.pendingText
-				t.pendingText = ""
-				t.trimToMaxContentLocked()
-
-				currentContent := t.content
-				t.mu.Unlock()
-
-				fyne.Do(func() {
-					t.Entry.SetText(currentContent)
-					t.Refresh()
-				})
-			} else {
+			if t.pendingText == "" {
 				t.mu.Unlock()
+				continue
 			}
+			appended := t.flushPendingLocked()
+			t.mu.Unlock()
+
+			uiDo(func() {
+				t.syncAppended()
+				t.notifyContentChanged(appended, false)
+			})
 		}
 	}
 }
 
-// AppendText 追加文本到终端（线程安全）
+// AppendText 追加文本到终端（线程安全），ANSI 颜色序列会被保留用于渲染。
+// 文本按调用顺序进入待刷新文本；待刷新文本已满时按 SetBackpressure 的设置等待下次刷新，或跳过较早的待刷新文本
 func (t *TerminalOutput) AppendText(text string) {
-	cleanText := t.stripANSI(text)
-
-	// 发送到更新通道，非阻塞
-	select {
-	case t.updateChan <- cleanText:
-		// 成功发送
-	default:
-		t.mu.Lock()
-		t.appendPendingLocked(cleanText)
-		t.mu.Unlock()
+	t.mu.Lock()
+	if t.block && !t.closed && t.pendingText != "" && len(t.pendingText)+len(text) > t.maxPending {
+		t.waited++
+		for t.block && !t.closed && t.pendingText != "" && len(t.pendingText)+len(text) > t.maxPending {
+			t.drained.Wait()
+		}
 	}
+	t.appendPendingLocked(text)
+	t.mu.Unlock()
 }
 
 // Clear 清空终端内容
 func (t *TerminalOutput) Clear() {
 	t.mu.Lock()
-	t.content = ""
+	t.resetLocked()
 	t.pendingText = ""
+	t.pendingAt = nil
+	t.drained.Broadcast()
 	t.mu.Unlock()
 
-	fyne.Do(func() {
-		t.Entry.SetText("")
-		t.Refresh()
+	uiDo(func() {
+		t.selection = terminalSelection{}
+		t.resetFolds()
+		t.resetBookmarks()
+		t.sync()
+		t.notifyContentChanged(0, true)
 	})
 }
 
 // SetFullText 设置完整文本（覆盖现有内容）
 func (t *TerminalOutput) SetFullText(text string) {
 	t.mu.Lock()
-
-	cleanText := t.stripANSI(text)
-	t.content = cleanText
+	t.resetLocked()
 	t.pendingText = ""
-	t.trimToMaxContentLocked()
-
-	currentContent := t.content
+	t.pendingAt = nil
+	t.drained.Broadcast()
+	t.appendLinesLocked(text, time.Time{})
+	t.trimLocked()
 	t.mu.Unlock()
 
-	fyne.Do(func() {
-		t.Entry.SetText(currentContent)
-		t.Refresh()
+	uiDo(func() {
+		t.selection = terminalSelection{}
+		t.resetFolds()
+		t.resetBookmarks()
+		t.sync()
+		t.notifyContentChanged(0, true)
 	})
 }
 
+func (t *TerminalOutput) notifyContentChanged(appended int, reset bool) {
+	if t.OnContentChanged != nil {
+		t.OnContentChanged(appended, reset)
+	}
+}
+
 // Destroy 销毁终端输出组件，清理资源
 func (t *TerminalOutput) Destroy() {
 	t.closeOnce.Do(func() {
 		close(t.stopChan)
+		t.mu.Lock()
+		t.closed = true
+		t.drained.Broadcast()
+		t.closeSpillLocked()
+		t.mu.Unlock()
 	})
 }
 
 // stripANSI 移除ANSI转义序列
 func (t *TerminalOutput) stripANSI(text string) string {
-	return ansiRegex.ReplaceAllString(text, "")
+	return joinANSIText(parseANSI(text))
+}
+
+// pendingMark 记录待刷新文本从 offset 开始的一段的写入时间
+type pendingMark struct {
+	offset int
+	at     time.Time
 }
 
+// appendPendingLocked 暂存文本并记下写入时间，时间列显示的是写入时间而不是刷新到界面的时间
 func (t *TerminalOutput) appendPendingLocked(text string) {
+	if text == "" {
+		return
+	}
+	t.pendingAt = append(t.pendingAt, pendingMark{offset: len(t.pendingText), at: time.Now()})
 	t.pendingText += text
-	if len(t.pendingText) <= t.maxPending {
+	if t.block || len(t.pendingText) <= t.maxPending {
 		return
 	}
 
@@ -163,51 +354,639 @@ func (t *TerminalOutput) appendPendingLocked(text string) {
 	if idx := strings.Index(keep, "\n"); idx > 0 {
 		keep = keep[idx+1:]
 	}
-	t.pendingText = "\n[输出过快，已丢弃部分历史日志]\n" + keep
+	cut := len(t.pendingText) - len(keep)
+	t.skipped += cut
+	var marks []pendingMark
+	for _, mark := range t.pendingAt {
+		if mark.offset <= cut {
+			// 被截断的一段与提示文字一起从头开始
+			marks = []pendingMark{{offset: 0, at: mark.at}}
+			continue
+		}
+		marks = append(marks, pendingMark{offset: mark.offset - cut + len(terminalDroppedNotice), at: mark.at})
+	}
+	t.pendingText = terminalDroppedNotice + keep
+	t.pendingAt = marks
 }
 
-func (t *TerminalOutput) trimToMaxContentLocked() {
-	if len(t.content) <= t.maxBytes {
-		t.trimLinesLocked()
-		return
+// flushPendingLocked 把待刷新文本并入行缓冲，返回新增的行数
+func (t *TerminalOutput) flushPendingLocked() int {
+	if t.pendingText == "" {
+		return 0
 	}
-	t.content = t.content[len(t.content)-t.maxBytes:]
-	if idx := strings.Index(t.content, "\n"); idx > 0 {
-		t.content = t.content[idx+1:]
+	appended := strings.Count(t.pendingText, "\n")
+	for i, mark := range t.pendingAt {
+		end := len(t.pendingText)
+		if i+1 < len(t.pendingAt) {
+			end = t.pendingAt[i+1].offset
+		}
+		t.appendLinesLocked(t.pendingText[mark.offset:end], mark.at)
 	}
-	t.trimLinesLocked()
+	t.pendingText = ""
+	t.pendingAt = nil
+	t.drained.Broadcast()
+	t.trimLocked()
+	return appended
 }
 
-func (t *TerminalOutput) trimLinesLocked() {
-	if t.maxLines <= 0 {
-		return
+func (t *TerminalOutput) resetLocked() {
+	t.lines = nil
+	t.open = terminalLine{}
+	t.openParser = ansiParser{}
+	t.emu = terminalEmuState{}
+	t.origin = time.Time{}
+	t.bytes = 0
+	t.maxCols = 0
+	t.dropped = 0
+	t.skipped = 0
+	t.waited = 0
+	t.rewritten = true
+	t.closeSpillLocked()
+}
+
+// appendLinesLocked 把文本切分为行追加到缓冲区，末尾未结束的行会与下一段文本拼接；
+// 含有 \r、退格或光标控制序列时交给终端模拟层原地改写。at 是文本的写入时间，
+// 续写的末行保留原来的时间。
+func (t *TerminalOutput) appendLinesLocked(text string, at time.Time) {
+	if t.origin.IsZero() && text != "" {
+		t.origin = at
 	}
-	lineCount := strings.Count(t.content, "\n")
-	if lineCount <= t.maxLines {
+	if !t.emu.idle() || needsTerminalEmulation(text) {
+		t.emulateLocked(text, at)
 		return
 	}
-	cut := 0
-	toDrop := lineCount - t.maxLines
-	for i := 0; i < toDrop; i++ {
-		next := strings.Index(t.content[cut:], "\n")
-		if next < 0 {
+	data := t.open.raw + text
+	lineAt := t.openAt(at)
+	p := t.openParser
+	for {
+		idx := strings.IndexByte(data, '\n')
+		if idx < 0 {
 			break
 		}
-		cut += next + 1
+		line := newTerminalLine(&p, strings.TrimSuffix(data[:idx], "\r"))
+		line.at = lineAt
+		t.maskLocked(&line)
+		t.lines = append(t.lines, line)
+		t.bytes += len(line.raw) + len(line.plain)
+		data = data[idx+1:]
+		lineAt = at
+	}
+	t.openParser = p
+	t.open = newTerminalLine(&p, data)
+	t.open.at = lineAt
+	t.maskLocked(&t.open)
+}
+
+// openAt 返回末行的写入时间，末行为空时从 at 开始
+func (t *TerminalOutput) openAt(at time.Time) time.Time {
+	if t.open.raw == "" {
+		return at
+	}
+	return t.open.at
+}
+
+// maskLocked 按当前的脱敏函数计算隐私模式下的显示内容，并更新最长行的列数
+func (t *TerminalOutput) maskLocked(line *terminalLine) {
+	line.masked = maskTerminalLine(*line, t.redact)
+	t.maxCols = max(t.maxCols, line.cols)
+	if line.masked != nil {
+		t.maxCols = max(t.maxCols, line.masked.cols)
+	}
+}
+
+// SetRedact 设置隐私模式的脱敏函数并重新计算已有行的显示内容，nil 表示关闭。
+// 缓冲区中的原始内容不变，关闭后恢复原样显示。
+func (t *TerminalOutput) SetRedact(redact func(string) string) {
+	t.mu.Lock()
+	t.redact = redact
+	// 快照与 lines 共用底层数组，复制一份再修改以免与 UI 线程竞争
+	lines := make([]terminalLine, len(t.lines))
+	copy(lines, t.lines)
+	for i := range lines {
+		t.maskLocked(&lines[i])
+	}
+	t.lines = lines
+	t.rewritten = true
+	t.maskLocked(&t.open)
+	t.mu.Unlock()
+
+	uiDo(t.sync)
+}
+
+// redactText 在隐私模式下对导出的文本脱敏
+func (t *TerminalOutput) redactText(text string) string {
+	t.mu.Lock()
+	redact := t.redact
+	t.mu.Unlock()
+	if redact == nil {
+		return text
 	}
-	if cut > 0 && cut < len(t.content) {
-		t.content = "[历史输出过长，已保留最近内容]\n" + t.content[cut:]
+	return redact(text)
+}
+
+// trimLocked 超出行数或字节上限时移出最旧的行，一次多移约 10% 以摊薄复制开销；
+// 转存模式下移出的行写入磁盘，否则直接丢弃
+func (t *TerminalOutput) trimLocked() {
+	if len(t.lines) <= t.maxLines && t.bytes <= t.maxBytes {
+		return
 	}
+	drop, freed := 0, 0
+	for drop < len(t.lines) && (len(t.lines)-drop > t.maxLines*9/10 || t.bytes-freed > t.maxBytes*9/10) {
+		freed += len(t.lines[drop].raw) + len(t.lines[drop].plain)
+		drop++
+	}
+	t.bytes -= freed
+	if t.spill && t.spillLocked(t.lines[:drop]) {
+		t.spilled += drop
+	} else {
+		t.dropped += drop
+	}
+	t.lines = append([]terminalLine(nil), t.lines[drop:]...)
+	t.rewritten = true
+	t.emu.up = min(t.emu.up, len(t.lines))
 }
 
-// GetText 获取当前文本内容
+// GetText 获取当前文本内容（已移除 ANSI 序列，用于复制与导出）
 func (t *TerminalOutput) GetText() string {
+	return t.joinLines(true, false)
+}
+
+// GetRawText 获取保留 ANSI 序列的原始内容
+func (t *TerminalOutput) GetRawText() string {
+	return t.joinLines(false, false)
+}
+
+// GetLogText 获取用于保存日志的内容：开启时间列时每行前加上写入时间，keepANSI 为 false 时移除 ANSI 序列
+func (t *TerminalOutput) GetLogText(keepANSI bool) string {
+	return t.joinLines(!keepANSI, true)
+}
+
+// joinLines 拼接转存内容与内存中的行，plain 为 true 时移除 ANSI 序列，
+// stamped 为 true 时按时间列设置加上行首时间（已转存到磁盘的行没有时间）
+func (t *TerminalOutput) joinLines(plain, stamped bool) string {
 	t.mu.Lock()
 	defer t.mu.Unlock()
-	if t.pendingText != "" {
-		t.content += t.pendingText
-		t.pendingText = ""
-		t.trimToMaxContentLocked()
+	t.flushPendingLocked()
+	text := func(line terminalLine) string {
+		prefix := ""
+		if stamped {
+			prefix = t.stampLocked(line)
+		}
+		if plain {
+			return prefix + line.plain
+		}
+		return prefix + line.raw
+	}
+	var b strings.Builder
+	if t.dropped > 0 {
+		b.WriteString(terminalTrimmedNotice + "\n")
+	}
+	if spilled := t.readSpillLocked(); plain {
+		b.WriteString(joinANSIText(parseANSI(spilled)))
+	} else {
+		b.WriteString(spilled)
+	}
+	for _, line := range t.lines {
+		b.WriteString(text(line))
+		b.WriteByte('\n')
+	}
+	if t.open.raw != "" {
+		b.WriteString(text(t.open))
+	}
+	return b.String()
+}
+
+// sync 把缓冲区快照同步到界面，重新计算过滤、折叠、换行与搜索结果（需在 UI 线程调用）
+func (t *TerminalOutput) sync() {
+	t.syncRows(false)
+}
+
+// syncAppended 在追加输出后同步：已完成的行没有变化时只处理新增的行，
+// 每次刷新的开销只与新增内容有关，不随日志长度增长（需在 UI 线程调用）
+func (t *TerminalOutput) syncAppended() {
+	t.syncRows(true)
+}
+
+func (t *TerminalOutput) syncRows(appended bool) {
+	t.mu.Lock()
+	rewritten := t.rewritten
+	t.rewritten = false
+	t.snapshot = t.lines[:len(t.lines):len(t.lines)]
+	t.snapshotOpen = t.open
+	overflow := [4]int{t.dropped, t.spilled, t.skipped, t.waited}
+	t.mu.Unlock()
+
+	if overflow != t.overflow {
+		if t.OnOverflow != nil && (overflow[0] != t.overflow[0] || overflow[1] != t.overflow[1]) {
+			t.OnOverflow(overflow[0], overflow[1])
+		}
+		if t.OnBackpressure != nil && (overflow[2] != t.overflow[2] || overflow[3] != t.overflow[3]) {
+			t.OnBackpressure(overflow[2], overflow[3])
+		}
+		t.overflow = overflow
+	}
+
+	total := t.lineCount()
+	from := 0
+	if appended && !rewritten && t.derived <= len(t.snapshot) {
+		from = t.derived
+	}
+	t.derived = len(t.snapshot)
+	t.lineBase = overflow[0] + overflow[1]
+	t.headers = t.sectionHeaders(from, total)
+	if t.applyAutoFold() {
+		from = 0
+	}
+	t.filterRows, t.filterSection = t.filter.appendRows(t.filterRows, t.filterSection, from, len(t.snapshot), total, t.line)
+	t.rows = t.foldRows(t.filterRows, from, total)
+	t.wrapped = t.wrapRows(from)
+	if t.OnFilterUpdate != nil {
+		shown := total
+		if t.rows != nil {
+			shown = len(t.rows)
+		}
+		t.OnFilterUpdate(shown, total)
+	}
+	t.refreshSearchMatches(from)
+	t.generation++
+	t.body.Refresh()
+}
+
+// lineCount 返回快照中的总行数（包括未结束的末行）
+func (t *TerminalOutput) lineCount() int {
+	if t.snapshotOpen.raw == "" {
+		return len(t.snapshot)
+	}
+	return len(t.snapshot) + 1
+}
+
+// line 返回快照中的第 i 行，隐私模式下返回脱敏后的内容
+func (t *TerminalOutput) line(i int) terminalLine {
+	line := t.snapshotOpen
+	if i < len(t.snapshot) {
+		line = t.snapshot[i]
+	}
+	if line.masked != nil {
+		return *line.masked
+	}
+	return line
+}
+
+// rowCount 返回显示的行数，自动换行时按换行后的段计
+func (t *TerminalOutput) rowCount() int {
+	if t.wrapped != nil {
+		return len(t.wrapped)
+	}
+	if t.rows == nil {
+		return t.lineCount()
+	}
+	return len(t.rows)
+}
+
+// row 返回第 i 个显示行，自动换行时为所在行的一段
+func (t *TerminalOutput) row(i int) terminalLine {
+	line := t.line(t.rowLine(i))
+	if t.wrapped != nil {
+		if seg := t.wrapped[i]; seg.from > 0 || seg.to < len(line.plain) {
+			return sliceTerminalLine(line, seg.from, seg.to)
+		}
+	}
+	return line
+}
+
+// rowLine 返回第 i 个显示行在快照中的行号
+func (t *TerminalOutput) rowLine(i int) int {
+	if t.wrapped != nil {
+		return t.wrapped[i].line
+	}
+	if t.rows == nil {
+		return i
+	}
+	return t.rows[i]
+}
+
+func (t *TerminalOutput) handleScrolled(pos fyne.Position) {
+	t.body.Refresh()
+	if t.OnScrolled != nil {
+		t.OnScrolled(pos)
+	}
+}
+
+// ScrollToRow 滚动使第 row 个显示行位于可见区域上方约三分之一处
+func (t *TerminalOutput) ScrollToRow(row int) {
+	metrics := t.body.metrics()
+	viewport := t.scroll.Size().Height
+	content := t.body.MinSize().Height
+	y := metrics.pad + float32(row)*metrics.row - viewport/3
+	y = max(0, min(y, content-viewport))
+	t.scroll.ScrollToOffset(fyne.NewPos(t.scroll.Offset.X, y))
+	t.body.Refresh()
+}
+
+// terminalRun 是一行中样式一致的一段文字
+type terminalRun struct {
+	Text  string
+	Style ansiStyle
+	// Match 为 -1 表示不是搜索命中，否则为该命中在传入 matches 中的下标
+	Match int
+	// Link 为 -1 表示不是链接，否则为该链接在传入 links 中的下标
+	Link int
+}
+
+// buildTerminalRuns 按搜索命中与链接位置切分 ANSI 片段；偏移均基于片段拼接后的纯文本
+func buildTerminalRuns(parsed []ansiSegment, matches []terminalMatch, links []terminalLink) []terminalRun {
+	runs := make([]terminalRun, 0, len(parsed)+2*len(matches)+2*len(links))
+	pos, mi, li := 0, 0, 0
+	for _, seg := range parsed {
+		text := seg.Text
+		for len(text) > 0 {
+			for mi < len(matches) && matches[mi].End <= pos {
+				mi++
+			}
+			for li < len(links) && links[li].End <= pos {
+				li++
+			}
+			n, match, link := len(text), -1, -1
+			if mi < len(matches) {
+				n, match = clipSpan(n, pos, matches[mi].Start, matches[mi].End, mi)
+			}
+			if li < len(links) {
+				n, link = clipSpan(n, pos, links[li].Start, links[li].End, li)
+			}
+			runs = append(runs, terminalRun{Text: text[:n], Style: seg.Style, Match: match, Link: link})
+			text = text[n:]
+			pos += n
+		}
+	}
+	return runs
+}
+
+// clipSpan 把长度 n 截断到区间 [start, end) 的下一个边界；pos 位于区间内时同时返回 idx，否则返回 -1
+func clipSpan(n, pos, start, end, idx int) (int, int) {
+	if start > pos {
+		return min(n, start-pos), -1
+	}
+	return min(n, end-pos), idx
+}
+
+func terminalTextColor(style ansiStyle) fyne.ThemeColorName {
+	if style.Faint && style.effectiveForeground().Kind == ansiColorDefault {
+		return theme.ColorNamePlaceHolder
+	}
+	if fg := style.effectiveForeground(); fg.Kind != ansiColorDefault {
+		return fg.ColorName()
+	}
+	return terminalColorForeground
+}
+
+// terminalBody 是终端的内容层：最小尺寸覆盖全部行，但只为可见行创建绘制对象
+type terminalBody struct {
+	widget.BaseWidget
+	term     *TerminalOutput
+	dragging bool
+	overLink bool
+}
+
+func newTerminalBody(term *TerminalOutput) *terminalBody {
+	body := &terminalBody{term: term}
+	body.ExtendBaseWidget(body)
+	return body
+}
+
+// terminalMetrics 是按当前主题计算的行高、字宽与边距；行首依次为书签列 mark、折叠标记列 fold 与时间列 gutter
+type terminalMetrics struct {
+	row, char, pad, text, mark, fold, gutter float32
+}
+
+// indent 返回行首到正文的距离
+func (m terminalMetrics) indent() float32 {
+	return m.mark + m.fold + m.gutter
+}
+
+func (b *terminalBody) metrics() terminalMetrics {
+	th := b.Theme()
+	size := b.term.fontSize
+	if size <= 0 {
+		size = th.Size(theme.SizeNameText)
+	}
+	cell := b.term.measure("M", size, fyne.TextStyle{Monospace: true})
+	m := terminalMetrics{
+		row:  float32(math.Ceil(float64(cell.Height + th.Size(theme.SizeNameLineSpacing)))),
+		char: cell.Width,
+		pad:  th.Size(theme.SizeNameInnerPadding),
+		text: size,
+	}
+	if b.term.gutterMode() != terminalGutterOff {
+		m.gutter = float32(terminalGutterCols+1) * cell.Width
+	}
+	if b.term.Bookmarkable {
+		m.mark = m.row
+	}
+	if b.term.Foldable {
+		m.fold = m.row
+	}
+	return m
+}
+
+func (b *terminalBody) CreateRenderer() fyne.WidgetRenderer {
+	return &terminalBodyRenderer{body: b, views: map[int]*terminalRowView{}}
+}
+
+// terminalRowView 是一个可见行的绘制对象
+type terminalRowView struct {
+	box        *fyne.Container
+	generation int
+}
+
+type terminalBodyRenderer struct {
+	body    *terminalBody
+	views   map[int]*terminalRowView
+	pool    []*terminalRowView
+	objects []fyne.CanvasObject
+	// 主题或明暗变化时需要按新颜色重建所有可见行
+	theme   fyne.Theme
+	variant fyne.ThemeVariant
+}
+
+func (r *terminalBodyRenderer) Destroy() {}
+
+func (r *terminalBodyRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *terminalBodyRenderer) MinSize() fyne.Size {
+	m := r.body.metrics()
+	term := r.body.term
+	term.mu.Lock()
+	cols := term.maxCols
+	term.mu.Unlock()
+	if term.wrap {
+		// 自动换行时宽度跟随可见区域，不撑大窗口
+		cols = terminalMinWrapCols
+	}
+	return fyne.NewSize(float32(cols)*m.char+m.indent()+2*m.pad, float32(term.rowCount())*m.row+2*m.pad)
+}
+
+func (r *terminalBodyRenderer) Layout(fyne.Size) {
+	r.updateVisible()
+}
+
+func (r *terminalBodyRenderer) Refresh() {
+	th, variant := r.body.Theme(), fyne.CurrentApp().Settings().ThemeVariant()
+	if th != r.theme || variant != r.variant {
+		r.theme, r.variant = th, variant
+		for _, view := range r.views {
+			view.generation = -1
+		}
+	}
+	r.updateVisible()
+	canvas.Refresh(r.body)
+}
+
+// updateVisible 根据滚动位置回收离开视口的行，并为新进入视口的行创建绘制对象
+func (r *terminalBodyRenderer) updateVisible() {
+	term := r.body.term
+	m := r.body.metrics()
+	count := term.rowCount()
+	viewTop := term.scroll.Offset.Y - m.pad
+	viewHeight := term.scroll.Size().Height
+	first := max(int(math.Floor(float64(viewTop/m.row))), 0)
+	last := min(int(math.Ceil(float64((viewTop+viewHeight)/m.row))), count-1)
+
+	for row, view := range r.views {
+		if row < first || row > last {
+			delete(r.views, row)
+			r.pool = append(r.pool, view)
+		}
+	}
+	r.objects = r.objects[:0]
+	for row := first; row <= last; row++ {
+		view, ok := r.views[row]
+		if !ok {
+			if n := len(r.pool); n > 0 {
+				view, r.pool = r.pool[n-1], r.pool[:n-1]
+			} else {
+				view = &terminalRowView{box: container.NewWithoutLayout()}
+			}
+			view.generation = -1
+			r.views[row] = view
+		}
+		if view.generation != term.generation {
+			r.buildRow(view, row, m)
+			view.generation = term.generation
+		}
+		view.box.Move(fyne.NewPos(m.pad, m.pad+float32(row)*m.row))
+		r.objects = append(r.objects, view.box)
+	}
+}
+
+// buildRow 为一行创建带颜色的文字与搜索高亮
+func (r *terminalBodyRenderer) buildRow(view *terminalRowView, row int, m terminalMetrics) {
+	term := r.body.term
+	th := r.body.Theme()
+	variant := fyne.CurrentApp().Settings().ThemeVariant()
+	line := term.row(row)
+	parser := ansiParser{style: line.style}
+	matches, firstMatch := term.rowMatches(row)
+	runs := buildTerminalRuns(parser.Parse(line.raw), matches, findTerminalLinks(line.plain))
+
+	objects := view.box.Objects[:0]
+	// 自动换行时行首标记只画在第一段，批注只画在最后一段
+	first, last := term.rowSpan(row)
+	bookmark, marked := term.bookmarkAt(term.rowLine(row))
+	if marked && first && m.mark > 0 {
+		icon := canvas.NewImageFromResource(theme.NewPrimaryThemedResource(theme.RadioButtonFillIcon()))
+		icon.FillMode = canvas.ImageFillContain
+		icon.Resize(fyne.NewSquareSize(m.mark))
+		objects = append(objects, icon)
+	}
+	if line.header && first && m.fold > 0 {
+		marker := canvas.NewImageFromResource(theme.NewThemedResource(foldMarker(term.collapsedLine(term.rowLine(row)))))
+		marker.FillMode = canvas.ImageFillContain
+		marker.Move(fyne.NewPos(m.mark, 0))
+		marker.Resize(fyne.NewSquareSize(m.fold))
+		objects = append(objects, marker)
+	}
+	if m.gutter > 0 && first {
+		stamp := canvas.NewText(term.stamp(line), th.Color(theme.ColorNamePlaceHolder, variant))
+		stamp.TextStyle = fyne.TextStyle{Monospace: true}
+		stamp.TextSize = m.text
+		stamp.FontSource = term.fontSource
+		stamp.Move(fyne.NewPos(m.mark+m.fold, 0))
+		stamp.Resize(fyne.NewSize(m.gutter, m.row))
+		objects = append(objects, stamp)
+	}
+	if from, to, toEnd, ok := term.rowSelection(row); ok {
+		x0 := term.textWidth(line.plain[:from], m.text)
+		x1 := term.textWidth(line.plain[:to], m.text)
+		if toEnd {
+			x1 += m.char
+		}
+		selected := canvas.NewRectangle(th.Color(theme.ColorNameSelection, variant))
+		selected.Move(fyne.NewPos(m.indent()+x0, 0))
+		selected.Resize(fyne.NewSize(x1-x0, m.row))
+		objects = append(objects, selected)
+	}
+	x := m.indent()
+	for _, run := range runs {
+		text := strings.ReplaceAll(run.Text, "\t", "    ")
+		style := fyne.TextStyle{Monospace: true, Bold: run.Style.Bold, Italic: run.Style.Italic, Underline: run.Link >= 0}
+		w := term.measure(text, m.text, style).Width
+		if run.Match >= 0 {
+			highlight := canvas.NewRectangle(searchHighlightColor(th, variant, firstMatch+run.Match == term.activeMatch))
+			highlight.Move(fyne.NewPos(x, 0))
+			highlight.Resize(fyne.NewSize(w, m.row))
+			objects = append(objects, highlight)
+		}
+		colorName := terminalTextColor(run.Style)
+		if run.Link >= 0 {
+			colorName = theme.ColorNameHyperlink
+		}
+		label := canvas.NewText(text, th.Color(colorName, variant))
+		label.TextStyle = style
+		label.TextSize = m.text
+		label.FontSource = term.fontSource
+		label.Move(fyne.NewPos(x, 0))
+		label.Resize(fyne.NewSize(w, m.row))
+		objects = append(objects, label)
+		x += w
+	}
+	if n := term.foldedLines(term.rowLine(row)); n > 0 && last {
+		text := "  " + term.foldedNote(n)
+		style := fyne.TextStyle{Monospace: true}
+		w := term.measure(text, m.text, style).Width
+		note := canvas.NewText(text, th.Color(theme.ColorNamePlaceHolder, variant))
+		note.TextStyle = style
+		note.TextSize = m.text
+		note.FontSource = term.fontSource
+		note.Move(fyne.NewPos(x, 0))
+		note.Resize(fyne.NewSize(w, m.row))
+		objects = append(objects, note)
+		x += w
+	}
+	if marked && last && bookmark.note != "" {
+		text := "  ◀ " + bookmark.note
+		style := fyne.TextStyle{Monospace: true}
+		w := term.measure(text, m.text, style).Width
+		note := canvas.NewText(text, th.Color(theme.ColorNamePrimary, variant))
+		note.TextStyle = style
+		note.TextSize = m.text
+		note.FontSource = term.fontSource
+		note.Move(fyne.NewPos(x, 0))
+		note.Resize(fyne.NewSize(w, m.row))
+		objects = append(objects, note)
+		x += w
+	}
+	view.box.Objects = objects
+	view.box.Resize(fyne.NewSize(x, m.row))
+	view.box.Refresh()
+}
+
+func searchHighlightColor(th fyne.Theme, variant fyne.ThemeVariant, active bool) color.Color {
+	name := theme.ColorNameSelection
+	if active {
+		name = theme.ColorNamePrimary
 	}
-	return t.content
+	r, g, b, _ := th.Color(name, variant).RGBA()
+	return color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0x90}
 }
@@ -0,0 +1,305 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+)
+
+func TestTerminalOutputDrawsOnlyVisibleRows(t *testing.T) {
+	app := test.NewTempApp(t)
+	app.Settings().SetTheme(NewCustomTheme(""))
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	var b strings.Builder
+	for i := 0; i < 10000; i++ {
+		fmt.Fprintf(&b, "\x1b[32mline %d\x1b[0m\n", i)
+	}
+	terminal.SetFullText(b.String())
+	terminal.sync()
+
+	win := test.NewWindow(terminal)
+	t.Cleanup(win.Close)
+	win.Resize(fyne.NewSize(400, 300))
+
+	renderer := test.WidgetRenderer(terminal.body).(*terminalBodyRenderer)
+	renderer.Refresh()
+	// 视口上下各允许一行部分可见
+	visible := int(terminal.scroll.Size().Height/terminal.body.metrics().row) + 3
+	if n := len(renderer.Objects()); n == 0 || n > visible {
+		t.Fatalf("rendered %d rows, want at most %d of 10000", n, visible)
+	}
+	if h := terminal.body.MinSize().Height; h < 10000*terminal.body.metrics().row {
+		t.Fatalf("body height %v does not cover all rows", h)
+	}
+
+	terminal.ScrollToRow(9000)
+	renderer.Refresh()
+	if _, ok := renderer.views[9000]; !ok {
+		t.Fatal("row 9000 should be drawn after scrolling to it")
+	}
+	if n := len(renderer.Objects()); n > visible {
+		t.Fatalf("rendered %d rows after scrolling, want at most %d", n, visible)
+	}
+}
+
+func TestTerminalOutputTrimsOldestLines(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.maxLines = 100
+	var b strings.Builder
+	for i := 0; i < 250; i++ {
+		fmt.Fprintf(&b, "line %d\n", i)
+	}
+	terminal.SetFullText(b.String() + "tail")
+
+	text := terminal.GetText()
+	if !strings.HasPrefix(text, terminalTrimmedNotice+"\n") {
+		t.Fatalf("trimmed text should start with notice, got %q", text[:40])
+	}
+	if !strings.HasSuffix(text, "line 249\ntail") {
+		t.Fatalf("trimmed text lost the newest lines: %q", text[len(text)-40:])
+	}
+	if n := strings.Count(text, "\n"); n > 100 {
+		t.Fatalf("kept %d lines, want at most 100", n)
+	}
+}
+
+func TestTerminalOutputJoinsPartialLines(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.mu.Lock()
+	terminal.appendPendingLocked("\x1b[31mhel")
+	terminal.appendPendingLocked("lo\x1b[0m\r\nworld")
+	terminal.mu.Unlock()
+	terminal.sync()
+
+	if got := terminal.GetText(); got != "hello\nworld" {
+		t.Fatalf("GetText() = %q", got)
+	}
+	terminal.sync()
+	if terminal.lineCount() != 2 || terminal.line(0).plain != "hello" || terminal.line(1).style.FG.Kind != ansiColorDefault {
+		t.Fatalf("lines = %#v", terminal.snapshot)
+	}
+}
+
+func TestTerminalOutputSpillsOverflowToDisk(t *testing.T) {
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.maxLines = 100
+	terminal.SetBufferLimit(parseTerminalBuffer(terminalBufferSpill))
+	var overflow [2]int
+	terminal.OnOverflow = func(dropped, spilled int) { overflow = [2]int{dropped, spilled} }
+	var b strings.Builder
+	for i := 0; i < 250; i++ {
+		fmt.Fprintf(&b, "\x1b[33mline %d\x1b[0m\n", i)
+	}
+	terminal.SetFullText(b.String())
+
+	terminal.mu.Lock()
+	spilled, dropped, kept, file := terminal.spilled, terminal.dropped, len(terminal.lines), terminal.spillFile
+	terminal.mu.Unlock()
+	if spilled == 0 || dropped != 0 || spilled+kept != 250 || file == nil {
+		t.Fatalf("spilled=%d dropped=%d kept=%d", spilled, dropped, kept)
+	}
+	if got := terminal.GetRawText(); got != b.String() {
+		t.Fatal("raw text should include spilled lines in order")
+	}
+	if got := terminal.GetText(); !strings.HasPrefix(got, "line 0\nline 1\n") || strings.Contains(got, "\x1b") {
+		t.Fatalf("plain text = %q...", got[:30])
+	}
+
+	terminal.sync()
+	if overflow != [2]int{0, spilled} {
+		t.Fatalf("OnOverflow = %v", overflow)
+	}
+
+	name := file.Name()
+	terminal.Clear()
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		t.Fatalf("spill file should be removed on clear, stat err = %v", err)
+	}
+}
+
+func TestParseTerminalBuffer(t *testing.T) {
+	if size, spill := parseTerminalBuffer("64"); size != 64*1024*1024 || spill {
+		t.Fatalf("parse 64 = %d, %v", size, spill)
+	}
+	if _, spill := parseTerminalBuffer(terminalBufferSpill); !spill {
+		t.Fatal("spill option should enable disk spill")
+	}
+	want, _ := parseTerminalBuffer(defaultTerminalBuffer())
+	if size, _ := parseTerminalBuffer("bogus"); size != want {
+		t.Fatalf("invalid value = %d, want default %d", size, want)
+	}
+}
+
+func TestTerminalSyncAppendedMatchesFullSync(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	w := test.NewWindow(terminal)
+	t.Cleanup(w.Close)
+	w.Resize(fyne.NewSize(360, 200))
+	running := true
+	terminal.Foldable = true
+	terminal.Running = func() bool { return running }
+	terminal.SetAutoFold(true)
+	terminal.SetWrap(true)
+	terminal.SetFilter(terminalFilter{Pattern: regexp.MustCompile("Mbps|得分|----")})
+	if _, err := terminal.SetSearch(terminalSearchOptions{Query: "100"}); err != nil {
+		t.Fatal(err)
+	}
+
+	state := func() string {
+		return fmt.Sprint(terminal.headers, terminal.rows, terminal.wrapped, terminal.matches)
+	}
+	chunks := []string{
+		"banner\n----CPU测试-通过sysbench测试----\n1 线程测试(单核)",
+		"得分: 1000\n", "----就近节点测速----\n位置 上传速度 下载速度\n",
+		"上海电信 100Mbps " + strings.Repeat("很长的一行 ", 20) + "\n北京联通 9",
+		"\r北京联通 100Mbps\n", "----磁盘测试----\n写入 100MB/s\n",
+	}
+	for _, chunk := range chunks {
+		feedTerminal(terminal, chunk)
+		terminal.syncAppended()
+		got := state()
+		terminal.sync()
+		if want := state(); got != want {
+			t.Fatalf("after %q incremental sync = %s\nwant %s", chunk, got, want)
+		}
+	}
+	if !reflect.DeepEqual(terminal.headers, []int{1, 3, 7}) || !terminal.collapsedLine(3) || terminal.collapsedLine(7) {
+		t.Fatalf("headers = %v, finished stages should be collapsed", terminal.headers)
+	}
+
+	// 改写已完成的行后需要全部重新计算
+	feedTerminal(terminal, "\x1b[2A\r----CPU测试-重新开始----\n\n")
+	terminal.syncAppended()
+	got := state()
+	terminal.sync()
+	if want := state(); got != want {
+		t.Fatalf("after rewriting a line incremental sync = %s\nwant %s", got, want)
+	}
+}
+
+// BenchmarkTerminalSyncAppended 测量日志已有 1/4/16 MB 时追加一块输出并同步的开销，
+// 每次操作的耗时应与已有日志的大小无关
+func BenchmarkTerminalSyncAppended(b *testing.B) {
+	test.NewTempApp(b)
+	line := "\x1b[32m上海电信\x1b[0m 上传 100.5 Mbps 下载 932.1 Mbps 延迟 3.2 ms\n"
+	chunk := strings.Repeat(line, 20)
+	for _, mb := range []int{1, 4, 16} {
+		b.Run(fmt.Sprintf("%dMB", mb), func(b *testing.B) {
+			terminal := NewTerminalOutput()
+			b.Cleanup(terminal.Destroy)
+			terminal.Foldable = true
+			terminal.SetFilter(terminalFilter{Level: filterLevelWarnings})
+			var fill strings.Builder
+			fill.WriteString("----就近节点测速----\n")
+			for fill.Len() < mb<<20 {
+				fill.WriteString(line)
+			}
+			feedTerminal(terminal, fill.String())
+			terminal.sync()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				feedTerminal(terminal, chunk)
+				terminal.syncAppended()
+			}
+		})
+	}
+}
+
+func TestTerminalBackpressureKeepsOrder(t *testing.T) {
+	terminal := NewTerminalOutput()
+	// 停止后台刷新，由测试自己取走待刷新文本，避免与测试中的同步并发
+	terminal.closeOnce.Do(func() { close(terminal.stopChan) })
+	terminal.maxPending = 256
+	terminal.SetBackpressure(true)
+	var want strings.Builder
+	for i := 0; i < 500; i++ {
+		fmt.Fprintf(&want, "line %d\n", i)
+	}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for _, line := range strings.SplitAfter(want.String(), "\n") {
+			terminal.AppendText(line)
+		}
+	}()
+	for finished := false; !finished; {
+		select {
+		case <-done:
+			finished = true
+		default:
+		}
+		terminal.mu.Lock()
+		terminal.flushPendingLocked()
+		terminal.mu.Unlock()
+	}
+	var skipped, waited int
+	terminal.OnBackpressure = func(s, w int) { skipped, waited = s, w }
+	terminal.sync()
+	if got := terminal.GetText(); got != want.String() || waited == 0 || skipped != 0 {
+		t.Fatalf("blocking mode should keep every line in order, waited = %d, skipped = %d", waited, skipped)
+	}
+
+	// 合并模式下写入方不等待，跳过的字节数通过 OnBackpressure 通知
+	terminal.SetBackpressure(false)
+	for i := 0; i < 100; i++ {
+		terminal.AppendText(fmt.Sprintf("fast %d\n", i))
+	}
+	terminal.mu.Lock()
+	terminal.flushPendingLocked()
+	terminal.mu.Unlock()
+	terminal.sync()
+	if got := terminal.GetText(); skipped == 0 || !strings.Contains(got, terminalDroppedNotice) || !strings.HasSuffix(got, "fast 98\nfast 99\n") {
+		t.Fatalf("skipped = %d, text ends with %q", skipped, got[len(got)-40:])
+	}
+}
+
+func TestTerminalConcurrentAppendText(t *testing.T) {
+	test.NewTempApp(t)
+	terminal := NewTerminalOutput()
+	t.Cleanup(terminal.Destroy)
+	terminal.SetBackpressure(true)
+	const workers, lines = 8, 500
+	var wg sync.WaitGroup
+	for worker := 0; worker < workers; worker++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for i := 0; i < lines; i++ {
+				terminal.AppendText(fmt.Sprintf("w%d %d\n", worker, i))
+			}
+		}(worker)
+	}
+	wg.Wait()
+
+	// 后台刷新在 UI 线程同步，读取界面状态也要经过同一个队列
+	var shown int
+	for deadline := time.Now().Add(5 * time.Second); shown < workers*lines && time.Now().Before(deadline); {
+		time.Sleep(20 * time.Millisecond)
+		uiDoAndWait(func() { shown = terminal.lineCount() })
+	}
+	if shown != workers*lines {
+		t.Fatalf("shown %d lines, want %d", shown, workers*lines)
+	}
+	next := make([]int, workers)
+	for _, line := range strings.Split(strings.TrimSuffix(terminal.GetText(), "\n"), "\n") {
+		var worker, i int
+		if _, err := fmt.Sscanf(line, "w%d %d", &worker, &i); err != nil || i != next[worker] {
+			t.Fatalf("line %q out of order, want w%d %d", line, worker, next[worker])
+		}
+		next[worker]++
+	}
+}
@@ -9,27 +9,52 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/container"
 	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/docker"
+	"github.com/oneclickvirt/ecs-gui/expect"
+	"github.com/oneclickvirt/ecs-gui/history"
+	"github.com/oneclickvirt/ecs-gui/iperf"
+	"github.com/oneclickvirt/ecs-gui/mirror"
+	"github.com/oneclickvirt/ecs-gui/proxy"
+	"github.com/oneclickvirt/ecs-gui/remote"
+	"github.com/oneclickvirt/ecs-gui/results"
 )
 
 type ExecutionConfig struct {
-	SelectedOptions   map[string]bool
-	Language          string
-	ChinaModeEnabled  bool
-	DeepMode          bool
-	DeepDiskPaths     string
-	DeepSMARTDevices  string
-	DeepBurnDuration  time.Duration
-	DeepGPUDevice     string
-	AutoDiskMethod    bool
-	CpuMethod         string
+	SelectedOptions  map[string]bool
+	Language         string
+	ChinaModeEnabled bool
+	DeepMode         bool
+	DeepDiskPaths    string
+	DeepSMARTDevices string
+	DeepBurnDuration time.Duration
+	DeepGPUDevice    string
+	AutoDiskMethod   bool
+	CpuMethod        string
+	// GeekbenchVersion 为 Geekbench 主版本或 skip；GeekbenchAccepted 表示已接受该版本的许可协议，
+	// 未接受时 Geekbench 改用 sysbench 测试
+	GeekbenchVersion  string
+	GeekbenchAccepted bool
 	ThreadMode        string
 	MemoryMethod      string
 	DiskMethod        string
 	DiskPath          string
 	DiskMulti         bool
-	Nt3Location       string
-	Nt3Type           string
-	SpNum             int
+	// DiskBlockSizes 与 DiskFileSize 为 fio 的块大小与测试文件大小，不是默认值时由 GUI 自行调用 fio，
+	// 只有本机经典执行器支持；DiskSafeMode 在本机测试目录所在磁盘几乎写满时跳过硬盘测试
+	DiskBlockSizes []string
+	DiskFileSize   int64
+	DiskSafeMode   bool
+	Nt3Location    string
+	Nt3Type        string
+	SpNum          int
+	// SpeedGroups 与 SpeedServerIDs 来自测速节点选择器，都为空时按预设选择节点；
+	// 只有本机经典执行器支持，远程与结构化后端使用 goecs 的默认节点
+	SpeedGroups    []string
+	SpeedServerIDs []string
+	// IperfTargets 非空时在测速之后增加 iperf3 阶段，同样只有本机运行支持
+	IperfTargets []iperf.Target
+	// TunnelInterface 非空时在 iperf3 之后增加隧道对比阶段，网络测试分别经默认路由与该网卡运行，仅本机运行支持
+	TunnelInterface   string
 	PingSortOrder     string
 	PingScope         string
 	TCPSortOrder      string
@@ -52,8 +77,39 @@ type ExecutionConfig struct {
 	HardwareBudget    time.Duration
 	DataOffline       bool
 	PrivacyMode       bool
+	OfflineMode       bool // 离线/内网模式，只运行不需要公网的测试，见 applyOfflineMode
 	PresetKey         string
 	LogEnabled        bool
+	Remote            *remote.Target  // 非空时通过 SSH 在远程主机上运行
+	Docker            *docker.Target  // 非空时通过 docker exec 在容器中运行，与 Remote 互斥
+	RemoteBinary      string          // 上传到远程主机的本地 goecs 路径，为空时按 RemoteVersion 获取发布包
+	RemoteVersion     string          // 远程使用的 goecs 版本，为空时使用界面内置的 ecsVersion
+	RemoteCache       string          // 本机缓存已校验 goecs 的目录，为空时由远程主机直接下载
+	RemoteCleanup     bool            // 取回远程运行写出的结果文件后从远程主机删除
+	Proxy             proxy.Settings  // 界面自身发起的下载、上传与推送所用的代理
+	Mirror            mirror.Settings // 发布包下载使用的 GitHub 镜像
+	// StageTimeouts 是各阶段（键为进度项）的时限，超时后结束该阶段并继续下一阶段，只有本机运行支持
+	StageTimeouts map[string]time.Duration
+	// StageRetries 是测速与流媒体解锁看起来因网络抖动失败时的重试次数，第 n 次重试前等待 RetryBackoff 的 2^(n-1) 倍，只有本机运行支持
+	StageRetries int
+	RetryBackoff time.Duration
+	// NetworkLoad 为 full 或 light，BandwidthCap 为 iperf3 的带宽上限（Mbps），二者都会让测速只测一个节点，见 applyNetworkLimits
+	NetworkLoad  string
+	BandwidthCap int
+	// BackendEnv 是传给 ecs 后端的环境变量（KEY=VALUE），BackendFlags 是追加在界面生成的参数之后的 goecs 参数，
+	// 两者只有 SSH 远程与容器运行支持，本机运行忽略
+	BackendEnv   []string
+	BackendFlags []string
+	// Expect 是自动回应后端交互式提示的规则，回复经标准输入发送
+	Expect expect.Settings
+	// GPUInfo 为真时在网络测试之后增加 GPU 信息阶段，GPUBenchmark 再用 hashcat 运行一次 SHA-256 基准，只有本机运行支持
+	GPUInfo      bool
+	GPUBenchmark bool
+}
+
+// local 返回是否在本机运行测试
+func (config ExecutionConfig) local() bool {
+	return config.Remote == nil && config.Docker == nil
 }
 
 type ProgressUpdate struct {
@@ -61,6 +117,10 @@ type ProgressUpdate struct {
 	Current  int
 	Total    int
 	Fraction float64
+	// Done 为 true 表示 ItemKey 阶段刚完成，否则表示刚开始
+	Done bool
+	// Pending 是尚未开始的阶段，用于估算剩余时间
+	Pending []string
 }
 
 type StructuredSection struct {
@@ -68,6 +128,7 @@ type StructuredSection struct {
 	Enabled bool   `json:"enabled"`
 	Status  string `json:"status"`
 	Reason  string `json:"reason,omitempty"`
+	Retries int    `json:"retries,omitempty"`
 }
 
 type StructuredDataVersion struct {
@@ -141,38 +202,54 @@ type TestUI struct {
 	Nt3Check               *widget.Check // 三网回程路由
 	SpeedCheck             *widget.Check // 网络测速
 	PingCheck              *widget.Check // 三网PING值
+	GPUCheck               *widget.Check // GPU 信息，仅本机运行
+	GPUBenchCheck          *widget.Check // 在 GPU 信息之后运行 hashcat 基准
 	LogCheck               *widget.Check // 启用日志记录
+	LogKeepANSICheck       *widget.Check // 保存日志时保留 ANSI 颜色代码
+	LogAutoSaveCheck       *widget.Check // 测试结束后自动保存日志
 
 	// 预设模式选择
 	PresetSelect *widget.Select
 
 	// 配置选项
-	LanguageSelect      *widget.Select
-	ThemeSelect         *widget.Select
-	CpuMethodSelect     *widget.Select
-	MemoryMethodSelect  *widget.Select
-	DiskMethodSelect    *widget.Select
-	DiskPathEntry       *widget.Entry
-	ThreadModeSelect    *widget.Select
-	Nt3LocationSelect   *widget.Select
-	Nt3TypeSelect       *widget.Select
-	DiskMultiCheck      *widget.Check
-	AutoDiskMethodCheck *widget.Check
-	DeepModeCheck       *widget.Check
-	DeepDiskPathsEntry  *widget.Entry
-	DeepSMARTEntry      *widget.Entry
-	DeepBurnEntry       *widget.Entry
-	DeepGPUEntry        *widget.Entry
-	SpNumEntry          *widget.Entry
-	OutputWidthEntry    *widget.Entry
-	OutputFileEntry     *widget.Entry
-	JSONPathEntry       *widget.Entry
-	MaxDurationEntry    *widget.Entry
-	HardwareBudgetEntry *widget.Entry
-	DataOfflineCheck    *widget.Check
-	PrivacyModeCheck    *widget.Check
-	ResultUploadCheck   *widget.Check
-	AnalyzeResultCheck  *widget.Check
+	LanguageSelect         *widget.Select
+	ThemeSelect            *widget.Select
+	BufferSizeSelect       *widget.Select
+	SchemeSelect           *widget.Select
+	FontSizeSelect         *widget.Select
+	CpuMethodSelect        *widget.Select
+	GeekbenchVersionSelect *widget.Select
+	MemoryMethodSelect     *widget.Select
+	DiskMethodSelect       *widget.Select
+	DiskPathEntry          *widget.Entry
+	ThreadModeSelect       *widget.Select
+	Nt3LocationSelect      *widget.Select
+	Nt3TypeSelect          *widget.Select
+	DiskMultiCheck         *widget.Check
+	AutoDiskMethodCheck    *widget.Check
+	DiskBlockSizesEntry    *widget.Entry
+	DiskFileSizeEntry      *widget.Entry
+	DiskSafeModeCheck      *widget.Check
+	DeepModeCheck          *widget.Check
+	DeepDiskPathsEntry     *widget.Entry
+	DeepSMARTEntry         *widget.Entry
+	DeepBurnEntry          *widget.Entry
+	DeepGPUEntry           *widget.Entry
+	SpNumEntry             *widget.Entry
+	SpeedNodesButton       *widget.Button
+	IperfButton            *widget.Button
+	NetworkLimitButton     *widget.Button
+	OutputWidthEntry       *widget.Entry
+	OutputFileEntry        *widget.Entry
+	JSONPathEntry          *widget.Entry
+	MaxDurationEntry       *widget.Entry
+	HardwareBudgetEntry    *widget.Entry
+	DataOfflineCheck       *widget.Check
+	PrivacyModeCheck       *widget.Check
+	OfflineModeCheck       *widget.Check // 离线/内网模式
+	ResultUploadCheck      *widget.Check
+	AnalyzeResultCheck     *widget.Check
+	APICheck               *widget.Check // 启用本地 HTTP API
 	// 中国模式
 	ChinaModeCheck *widget.Check // 启用中国专项测试
 
@@ -185,9 +262,22 @@ type TestUI struct {
 	TCPSortSelect     *widget.Select
 	UnlockShowIPCheck *widget.Check // 是否显示解锁测试IP标签
 
+	// 远程测试（SSH）
+	RemoteEnableCheck     *widget.Check
+	RemoteHostEntry       *widget.Entry
+	RemotePortEntry       *widget.Entry
+	RemoteUserEntry       *widget.Entry
+	RemotePasswordEntry   *widget.Entry
+	RemoteKeyPathEntry    *widget.Entry
+	RemotePassphraseEntry *widget.Entry
+	RemoteBinaryEntry     *widget.Entry
+	RemoteCleanupCheck    *widget.Check
+	RemoteWindowsCheck    *widget.Check
+
 	// 控制按钮
 	StartButton *widget.Button
 	StopButton  *widget.Button
+	PauseButton *widget.Button
 
 	// 结果显示 - 使用终端输出组件
 	Terminal              *TerminalOutput
@@ -209,15 +299,117 @@ type TestUI struct {
 	IsRunning        bool
 	CancelCtx        context.Context
 	CancelFn         context.CancelFunc
+	stopping         bool // 已请求停止，再次点击停止按钮将强制结束
+	pauseGate        *pauseGate
+	eta              *etaEstimator
 	Mu               sync.Mutex
 	StructuredResult *StructuredRunResult
+	ParsedResults    *results.Report // 从终端输出解析的分类结果
+	lastRunHost      string          // 最近一次测试的目标主机，用于日志文件名
 
 	testChecks []*widget.Check
 
+	// 主运行终端下方的标准输入栏
+	stdinBar *stdinBar
+
+	// 终端搜索
+	terminalFollow   *terminalFollower
+	speedChart       *speedChart // 测速阶段的实时速度曲线
+	searchBar        *fyne.Container
+	searchEntry      *widget.Entry
+	searchStatus     *widget.Label
+	searchIgnoreCase *widget.Check
+	searchRegex      *widget.Check
+	shortcuts        []fyne.Shortcut // 当前注册的窗口快捷键，修改键位表时整体替换
+	terminalWrap     bool            // 终端是否自动换行，仅在本次运行期间保持
+
+	resultsTabs  *container.AppTabs
+	resultsSplit *container.Split        // 终端与结果面板的分栏，方向与比例保存在偏好中
+	popouts      map[string]*popoutPanel // 可弹出到独立窗口的终端与结果面板
+	// 调试日志窗口，未打开时为 nil
+	debugLogWindow fyne.Window
+
+	// 运行标签页：第一个为主运行，其余为独立运行，仅在 UI 线程访问
+	runTabs     *container.DocTabs
+	mainRunItem *container.TabItem
+	extraRuns   []*runTab
+	runTabSeq   int
+	queue       *runQueue
+	// 以下字段由 Mu 保护：正在本机运行的标签页数与主运行是否在本机执行
+	localTabRuns int
+	mainRunLocal bool
+	// runProcesses 由 Mu 保护，记录本机主运行开始时已有的子进程，停止时只通知之后派生的；非本机运行时为 nil
+	runProcesses *processScope
+
+	// 历史记录
+	historyStore     *history.Store
+	historyList      *widget.List
+	historyItems     []history.Summary
+	historySelected  int
+	historyTag       string // 历史记录按标签筛选，空表示全部
+	historyTagSelect *widget.Select
+	// resultsRecord 为结果面板当前展示的历史记录，单项重跑的结果合并到该记录，由 Mu 保护
+	resultsRecord *history.Summary
+	// resultsOverview 为结果面板第一页的测试概览，存在虚拟化注意事项时在其上方加提示
+	resultsOverview fyne.CanvasObject
+
+	// 趋势
+	trendHost   *widget.Select
+	trendCharts *fyne.Container
+
+	// 延迟工具
+	latency *latencyTool
+	// 端口检测工具
+	ports *portTool
+	// 路由监测工具
+	monitor *monitorTool
+	// 启动页的目标系统信息卡片
+	systemInfo *systemInfoCard
+
+	// 远程主机管理
+	hostProfiles    *remote.ProfileStore
+	remoteJump      *remote.Target
+	remoteProxy     string
+	remoteJumpLabel *widget.Label
+	remoteJumpRow   *fyne.Container
+
+	// Docker 容器目标，非空时代替 SSH 远程目标
+	dockerTarget *docker.Target
+	dockerLabel  *widget.Label
+	dockerRow    *fyne.Container
+
+	// backendSummaryLabel 显示高级设置中附加的环境变量与参数个数
+	backendSummaryLabel *widget.Label
+
+	// 本地 HTTP API
+	api *apiServer
+
+	// 定时任务
+	scheduler *scheduler
+	// folderWatch 为正在监视的结果目录，由 Mu 保护
+	folderWatch *folderWatch
+
+	// 启动页侧边栏
+	sidebarChecks  map[string]*widget.Check
+	sidebarSummary *widget.Label
+	syncingSidebar bool
+
 	uiLang               string
+	languageSetting      string // 语言设置：auto、zh 或 en
 	themeMode            string
+	terminalScheme       string // 终端配色方案名，空表示跟随主题
 	presetLabelToKey     map[string]string
 	selectedPresetKey    string
+	speedNodes           string // 测速节点选择，格式见 parseSpeedNodes
+	iperfTargets         string // iperf3 目标，每行一个，格式见 iperf.ParseTarget
+	tunnelInterface      string // 隧道对比使用的网卡名，空表示关闭
+	stageTimeouts        string // 各阶段超时，格式见 parseStageTimeouts
+	stageRetries         string // 网络阶段失败后的重试次数
+	retryBackoff         string // 第一次重试前的等待时间
+	networkLoad          string // 网络测试强度，full 或 light
+	bandwidthCap         string // 带宽上限（Mbps），空表示不限制
+	backendEnv           string // 传给 ecs 后端的环境变量，格式见 parseBackendEnv
+	backendFlags         string // 附加的 goecs 命令行参数，格式见 parseBackendFlags
 	suppressPresetChange bool
 	inBackground         bool
 }
@@ -0,0 +1,203 @@
+package ui
+
+import (
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+// unlockLevel 是解锁状态的颜色分级
+type unlockLevel int
+
+const (
+	unlockLevelNone unlockLevel = iota
+	unlockLevelOK
+	unlockLevelPartial
+	unlockLevelBlocked
+)
+
+// unlockStatusLevel 把解锁状态归为绿（解锁）、黄（受限或无法判断）、红（未解锁或失败）
+func unlockStatusLevel(status string) unlockLevel {
+	switch status {
+	case "":
+		return unlockLevelNone
+	case "YES":
+		return unlockLevelOK
+	case "NO", "Banned", "Failed":
+		return unlockLevelBlocked
+	default:
+		return unlockLevelPartial
+	}
+}
+
+func unlockLevelIcon(level unlockLevel) fyne.Resource {
+	switch level {
+	case unlockLevelOK:
+		return theme.NewSuccessThemedResource(theme.ConfirmIcon())
+	case unlockLevelPartial:
+		return theme.NewWarningThemedResource(theme.WarningIcon())
+	case unlockLevelBlocked:
+		return theme.NewErrorThemedResource(theme.CancelIcon())
+	}
+	return nil
+}
+
+// unlockMatrixRow 是同一分组中同一平台在各 IP 版本下的结果
+type unlockMatrixRow struct {
+	group, platform string
+	cells           map[string]results.UnlockResult
+}
+
+// buildUnlockMatrix 按分组与平台合并结果，返回按出现顺序排列的 IP 版本列与行
+func buildUnlockMatrix(items []results.UnlockResult) ([]string, []unlockMatrixRow) {
+	var stacks []string
+	var rows []unlockMatrixRow
+	index := map[string]int{}
+	for _, item := range items {
+		if !containsString(stacks, item.Stack) {
+			stacks = append(stacks, item.Stack)
+		}
+		key := item.Group + "\x00" + item.Platform
+		i, ok := index[key]
+		if !ok {
+			i = len(rows)
+			index[key] = i
+			rows = append(rows, unlockMatrixRow{group: item.Group, platform: item.Platform, cells: map[string]results.UnlockResult{}})
+		}
+		rows[i].cells[item.Stack] = item
+	}
+	return stacks, rows
+}
+
+// unlockResultsView 以“平台 × IP 版本”矩阵显示解锁结果，悬停或点击单元格查看原始结果行
+func (ui *TestUI) unlockResultsView(report *results.Report) fyne.CanvasObject {
+	stacks, rows := buildUnlockMatrix(report.Unlock)
+	if len(rows) == 0 {
+		return widget.NewLabel(ui.tr("results.empty"))
+	}
+	headers := []string{ui.tr("results.col.group"), ui.tr("results.col.platform")}
+	for _, stack := range stacks {
+		if stack == "" {
+			stack = ui.tr("results.col.status")
+		}
+		headers = append(headers, stack)
+	}
+	table := widget.NewTable(
+		func() (int, int) { return len(rows) + 1, len(headers) },
+		func() fyne.CanvasObject { return newUnlockCell() },
+		func(id widget.TableCellID, obj fyne.CanvasObject) {
+			cell := obj.(*unlockCell)
+			switch {
+			case id.Row == 0:
+				cell.set(headers[id.Col], true, unlockLevelNone, "")
+			case id.Col == 0:
+				// 分组名只在每组第一行显示
+				group := rows[id.Row-1].group
+				if id.Row > 1 && rows[id.Row-2].group == group {
+					group = ""
+				}
+				cell.set(group, false, unlockLevelNone, "")
+			case id.Col == 1:
+				cell.set(rows[id.Row-1].platform, false, unlockLevelNone, "")
+			default:
+				row := rows[id.Row-1]
+				item, ok := row.cells[stacks[id.Col-2]]
+				if !ok {
+					cell.set("", false, unlockLevelNone, "")
+					return
+				}
+				text := item.Status
+				if item.Region != "" {
+					text += " " + item.Region
+				}
+				cell.set(text, false, unlockStatusLevel(item.Status), strings.TrimSpace(item.Platform+"  "+item.Status+" "+item.Detail))
+			}
+		},
+	)
+	table.SetColumnWidth(0, 140)
+	table.SetColumnWidth(1, 200)
+	for col := 2; col < len(headers); col++ {
+		table.SetColumnWidth(col, 150)
+	}
+	return table
+}
+
+// unlockCell 是矩阵中的单元格：状态图标 + 文字，带原始结果行时悬停（移动端点击）显示浮层
+type unlockCell struct {
+	widget.BaseWidget
+	icon   *widget.Icon
+	label  *widget.Label
+	detail string
+	popup  *widget.PopUp
+}
+
+var (
+	_ desktop.Hoverable = (*unlockCell)(nil)
+	_ fyne.Tappable     = (*unlockCell)(nil)
+)
+
+func newUnlockCell() *unlockCell {
+	cell := &unlockCell{icon: widget.NewIcon(nil), label: widget.NewLabel("")}
+	cell.label.Truncation = fyne.TextTruncateEllipsis
+	cell.ExtendBaseWidget(cell)
+	return cell
+}
+
+func (c *unlockCell) set(text string, bold bool, level unlockLevel, detail string) {
+	c.hideDetail()
+	c.detail = detail
+	c.label.TextStyle = fyne.TextStyle{Bold: bold}
+	c.label.SetText(text)
+	if icon := unlockLevelIcon(level); icon != nil {
+		c.icon.SetResource(icon)
+		c.icon.Show()
+	} else {
+		c.icon.Hide()
+	}
+}
+
+func (c *unlockCell) CreateRenderer() fyne.WidgetRenderer {
+	return widget.NewSimpleRenderer(container.NewBorder(nil, nil, c.icon, nil, c.label))
+}
+
+func (c *unlockCell) showDetail(pos fyne.Position) {
+	if c.detail == "" || c.popup != nil {
+		return
+	}
+	canvas := fyne.CurrentApp().Driver().CanvasForObject(c)
+	if canvas == nil {
+		return
+	}
+	c.popup = widget.NewPopUp(widget.NewLabel(c.detail), canvas)
+	c.popup.ShowAtPosition(pos.AddXY(12, 12))
+}
+
+func (c *unlockCell) hideDetail() {
+	if c.popup != nil {
+		c.popup.Hide()
+		c.popup = nil
+	}
+}
+
+func (c *unlockCell) MouseIn(ev *desktop.MouseEvent) {
+	c.showDetail(ev.AbsolutePosition)
+}
+
+func (c *unlockCell) MouseMoved(*desktop.MouseEvent) {}
+
+func (c *unlockCell) MouseOut() {
+	c.hideDetail()
+}
+
+func (c *unlockCell) Tapped(ev *fyne.PointEvent) {
+	if c.popup != nil {
+		c.hideDetail()
+		return
+	}
+	c.showDetail(ev.AbsolutePosition)
+}
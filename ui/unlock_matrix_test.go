@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"testing"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/test"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestBuildUnlockMatrixMergesStacks(t *testing.T) {
+	stacks, rows := buildUnlockMatrix([]results.UnlockResult{
+		{Platform: "Netflix", Status: "YES", Region: "US", Group: "Multination", Stack: "IPV4"},
+		{Platform: "TikTok", Status: "Failed", Group: "Multination", Stack: "IPV4"},
+		{Platform: "Netflix", Status: "N/A", Group: "Multination", Stack: "IPV6"},
+		{Platform: "Now E", Status: "NO", Group: "Hong Kong", Stack: "IPV4"},
+	})
+	if len(stacks) != 2 || stacks[0] != "IPV4" || stacks[1] != "IPV6" {
+		t.Fatalf("stacks = %v", stacks)
+	}
+	if len(rows) != 3 || rows[0].platform != "Netflix" || len(rows[0].cells) != 2 || rows[2].group != "Hong Kong" {
+		t.Fatalf("rows = %+v", rows)
+	}
+	for status, want := range map[string]unlockLevel{"YES": unlockLevelOK, "N/A": unlockLevelPartial, "Rate Limited": unlockLevelPartial, "Banned": unlockLevelBlocked, "": unlockLevelNone} {
+		if got := unlockStatusLevel(status); got != want {
+			t.Errorf("unlockStatusLevel(%q) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestUnlockResultsViewShowsDetailPopup(t *testing.T) {
+	ui := newTestUIForTest(t)
+	view := ui.unlockResultsView(&results.Report{Unlock: []results.UnlockResult{
+		{Platform: "Netflix", Status: "YES", Region: "US", Detail: "(Region: US) [Native]", Stack: "IPV4"},
+	}})
+	if _, ok := view.(*widget.Table); !ok {
+		t.Fatalf("view = %T, want *widget.Table", view)
+	}
+
+	cell := newUnlockCell()
+	w := test.NewWindow(cell)
+	defer w.Close()
+	cell.set("YES US", false, unlockLevelOK, "Netflix  YES (Region: US) [Native]")
+	cell.Tapped(&fyne.PointEvent{})
+	if cell.popup == nil || !cell.popup.Visible() {
+		t.Fatal("tapping a result cell did not show its detail")
+	}
+	cell.MouseOut()
+	if cell.popup != nil {
+		t.Fatal("detail popup not hidden")
+	}
+	cell.set("Platform", true, unlockLevelNone, "")
+	cell.Tapped(&fyne.PointEvent{})
+	if cell.popup != nil || cell.icon.Visible() {
+		t.Fatal("header cell should have no icon or popup")
+	}
+}
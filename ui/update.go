@@ -0,0 +1,179 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/internal/appmeta"
+	"github.com/oneclickvirt/ecs-gui/selfupdate"
+)
+
+const (
+	// updateCheckPreferenceKey 为 true 时启动后检查新版本（默认关闭）
+	updateCheckPreferenceKey = "update_check"
+	// updateSkipPreferenceKey 记录用户选择跳过的版本，启动检查时不再提示
+	updateSkipPreferenceKey = "update_skip_version"
+	updateCheckTimeout      = 20 * time.Second
+	updateDownloadTimeout   = 15 * time.Minute
+)
+
+var (
+	// checkForUpdate、stageUpdate 与 applyUpdate 访问网络或替换程序文件，测试中替换
+	checkForUpdate    = selfupdate.Check
+	stageUpdate       = selfupdate.Stage
+	applyUpdate       = selfupdate.Apply
+	currentExecutable = os.Executable
+)
+
+func (ui *TestUI) updateDir() string {
+	return ui.appDataDir("update")
+}
+
+// createUpdateRow 生成配置页中的启动检查更新开关与立即检查按钮
+func (ui *TestUI) createUpdateRow() fyne.CanvasObject {
+	prefs := ui.App.Preferences()
+	check := widget.NewCheck(ui.tr("check.update"), func(on bool) { prefs.SetBool(updateCheckPreferenceKey, on) })
+	check.SetChecked(prefs.Bool(updateCheckPreferenceKey))
+	now := widget.NewButton(ui.tr("update.check_now"), func() { ui.checkForUpdates(true) })
+	return container.NewBorder(nil, nil, nil, now, check)
+}
+
+// startupUpdateTasks 在程序启动后删除上次更新留下的旧版本、提示上次安装失败，并按设置检查新版本
+func (ui *TestUI) startupUpdateTasks() {
+	if isMobilePlatform() {
+		return
+	}
+	if exe, err := currentExecutable(); err == nil {
+		selfupdate.Cleanup(exe)
+	}
+	if staged, ok := selfupdate.Pending(ui.updateDir()); ok && staged.Error != "" {
+		dialog.ShowConfirm(ui.tr("update.title"), fmt.Sprintf(ui.tr("update.apply_failed"), staged.Version, staged.Error, staged.Path), func(discard bool) {
+			if discard {
+				_ = selfupdate.Discard(ui.updateDir())
+			}
+		}, ui.Window)
+		return
+	}
+	if ui.App.Preferences().Bool(updateCheckPreferenceKey) {
+		ui.checkForUpdates(false)
+	}
+}
+
+// applyStagedUpdate 在程序退出时安装已下载的更新
+func (ui *TestUI) applyStagedUpdate() {
+	if isMobilePlatform() {
+		return
+	}
+	if exe, err := currentExecutable(); err == nil {
+		_ = applyUpdate(ui.updateDir(), exe)
+	}
+}
+
+// checkForUpdates 在后台检查新版本。manual 为 true 时（点击“检查更新”）已是最新或检查失败也会提示，
+// 并忽略“跳过此版本”。返回的通道在结果处理完后关闭。
+func (ui *TestUI) checkForUpdates(manual bool) <-chan struct{} {
+	done := make(chan struct{})
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), updateCheckTimeout)
+		update, err := checkForUpdate(ctx, ui.downloadClient(), appmeta.Version)
+		cancel()
+		ui.runOnUI(func() {
+			defer close(done)
+			switch {
+			case err != nil:
+				if manual {
+					dialog.ShowError(errors.Join(errors.New(ui.tr("update.check_failed")), err), ui.Window)
+				}
+			case update == nil:
+				if manual {
+					dialog.ShowInformation(ui.tr("update.title"), fmt.Sprintf(ui.tr("update.latest"), appmeta.Version), ui.Window)
+				}
+			case ui.updateStaged(update.Version):
+				if manual {
+					dialog.ShowInformation(ui.tr("update.title"), fmt.Sprintf(ui.tr("update.staged"), update.Version), ui.Window)
+				}
+			case !manual && ui.App.Preferences().String(updateSkipPreferenceKey) == update.Version:
+			default:
+				ui.showUpdateDialog(update)
+			}
+		})
+	}()
+	return done
+}
+
+// updateStaged 判断该版本是否已下载并等待退出时安装
+func (ui *TestUI) updateStaged(version string) bool {
+	staged, ok := selfupdate.Pending(ui.updateDir())
+	return ok && staged.Error == "" && staged.Version == version
+}
+
+// showUpdateDialog 显示新版本的更新说明，可跳过此版本、稍后提醒，或下载后在退出时安装；
+// 没有本平台安装包时改为打开发布页面
+func (ui *TestUI) showUpdateDialog(update *selfupdate.Update) {
+	header := widget.NewLabelWithStyle(fmt.Sprintf(ui.tr("update.available"), update.Version, appmeta.Version), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	notes := widget.NewRichTextFromMarkdown(update.Notes)
+	notes.Wrapping = fyne.TextWrapWord
+	scroll := container.NewVScroll(notes)
+	scroll.SetMinSize(fyne.NewSize(520, 300))
+
+	var prompt dialog.Dialog
+	skip := widget.NewButton(ui.tr("update.skip"), func() {
+		prompt.Hide()
+		ui.App.Preferences().SetString(updateSkipPreferenceKey, update.Version)
+	})
+	later := widget.NewButton(ui.tr("update.later"), func() { prompt.Hide() })
+	var install *widget.Button
+	if update.Asset != "" {
+		install = widget.NewButtonWithIcon(ui.tr("update.install"), theme.DownloadIcon(), func() {
+			prompt.Hide()
+			ui.downloadUpdate(update)
+		})
+	} else {
+		install = widget.NewButtonWithIcon(ui.tr("update.open_page"), theme.ComputerIcon(), func() {
+			prompt.Hide()
+			if parsed, err := url.Parse(update.URL); err == nil && update.URL != "" {
+				_ = ui.App.OpenURL(parsed)
+			}
+		})
+	}
+	install.Importance = widget.HighImportance
+	content := container.NewBorder(header, container.NewHBox(layout.NewSpacer(), skip, later, install), nil, nil, scroll)
+	prompt = dialog.NewCustomWithoutButtons(ui.tr("update.title"), content, ui.Window)
+	prompt.Resize(fyne.NewSize(600, 460))
+	prompt.Show()
+}
+
+// downloadUpdate 在后台下载、校验并暂存更新，返回的通道在结果处理完后关闭
+func (ui *TestUI) downloadUpdate(update *selfupdate.Update) <-chan struct{} {
+	done := make(chan struct{})
+	bar := widget.NewProgressBarInfinite()
+	progress := dialog.NewCustomWithoutButtons(ui.tr("update.title"),
+		container.NewVBox(widget.NewLabel(fmt.Sprintf(ui.tr("update.downloading"), update.Version)), bar), ui.Window)
+	progress.Show()
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), updateDownloadTimeout)
+		_, err := stageUpdate(ctx, ui.downloadClient(), ui.updateDir(), update)
+		cancel()
+		ui.runOnUI(func() {
+			defer close(done)
+			bar.Stop()
+			progress.Hide()
+			if err != nil {
+				dialog.ShowError(errors.Join(errors.New(ui.tr("update.download_failed")), err), ui.Window)
+				return
+			}
+			dialog.ShowInformation(ui.tr("update.title"), fmt.Sprintf(ui.tr("update.staged"), update.Version), ui.Window)
+		})
+	}()
+	return done
+}
@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/selfupdate"
+)
+
+func waitClosed(t *testing.T, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("background update task did not finish")
+	}
+}
+
+func TestUpdateCheckHonoursSkippedVersion(t *testing.T) {
+	oldCheck, oldStage, oldApply, oldExe := checkForUpdate, stageUpdate, applyUpdate, currentExecutable
+	t.Cleanup(func() {
+		checkForUpdate, stageUpdate, applyUpdate, currentExecutable = oldCheck, oldStage, oldApply, oldExe
+	})
+	checked := make(chan struct{}, 4)
+	checkForUpdate = func(context.Context, *http.Client, string) (*selfupdate.Update, error) {
+		checked <- struct{}{}
+		return &selfupdate.Update{Version: "9.0.0", Notes: "## v9.0.0\n\n- new", Asset: "ecs-gui-linux-amd64-v9.0.0.tar.xz"}, nil
+	}
+	var stagedDir string
+	stageUpdate = func(_ context.Context, _ *http.Client, dir string, update *selfupdate.Update) (selfupdate.Staged, error) {
+		stagedDir = dir
+		return selfupdate.Staged{Version: update.Version}, nil
+	}
+	var applied [2]string
+	applyUpdate = func(dir, exe string) error {
+		applied = [2]string{dir, exe}
+		return nil
+	}
+	currentExecutable = func() (string, error) { return "/opt/ecs-gui/ecs-gui", nil }
+
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	overlays := ui.Window.Canvas().Overlays()
+
+	// 默认不在启动时检查
+	ui.startupUpdateTasks()
+	if len(checked) != 0 {
+		t.Fatal("update check should be opt-in")
+	}
+
+	ui.App.Preferences().SetString(updateSkipPreferenceKey, "9.0.0")
+	waitClosed(t, ui.checkForUpdates(false))
+	if overlays.Top() != nil {
+		t.Fatal("skipped version should not be offered at startup")
+	}
+	waitClosed(t, ui.checkForUpdates(true))
+	if overlays.Top() == nil {
+		t.Fatal("manual check should offer the skipped version")
+	}
+	overlays.Top().Hide()
+
+	update, _ := checkForUpdate(context.Background(), nil, "")
+	waitClosed(t, ui.downloadUpdate(update))
+	if stagedDir != ui.updateDir() {
+		t.Fatalf("staged into %q, want %q", stagedDir, ui.updateDir())
+	}
+	ui.applyStagedUpdate()
+	if applied != [2]string{ui.updateDir(), "/opt/ecs-gui/ecs-gui"} {
+		t.Fatalf("applyUpdate(%q)", applied)
+	}
+}
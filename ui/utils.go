@@ -27,17 +27,20 @@ func PrintHead(language string, width int, version string) {
 
 // PrintCenteredTitle 打印居中的标题
 func PrintCenteredTitle(title string, width int) {
+	fmt.Println(centeredTitle(title, width))
+}
+
+// centeredTitle 返回用 "-" 填充到 width 的居中标题
+func centeredTitle(title string, width int) string {
 	if title == "" {
-		fmt.Println(strings.Repeat("-", width))
-		return
+		return strings.Repeat("-", width)
 	}
 	titleLen := runewidth.StringWidth(title)
 	if titleLen >= width {
-		fmt.Println(title)
-		return
+		return title
 	}
 	padding := (width - titleLen) / 2
-	fmt.Printf("%s%s%s\n", strings.Repeat("-", padding), title, strings.Repeat("-", width-padding-titleLen))
+	return strings.Repeat("-", padding) + title + strings.Repeat("-", width-padding-titleLen)
 }
 
 func BuildResultSummary(language, output string) string {
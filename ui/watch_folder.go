@@ -0,0 +1,221 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+const (
+	watchFolderPreferenceKey = "watch_folder"
+	watchImportedFileName    = "watch_imported.json"
+	// watchFolderInterval 是扫描监视目录的间隔
+	watchFolderInterval = 15 * time.Second
+	// watchSettleTime 是文件最后一次修改后等待的时间，避免导入仍在同步中的文件
+	watchSettleTime = 5 * time.Second
+)
+
+// watchHostSuffix 匹配文件名末尾的日期时间部分，如 hk-1_20250301-1020 中的 _20250301-1020
+var watchHostSuffix = regexp.MustCompile(`[_ -]\d{4}-?\d{2}-?\d{2}[0-9T_:-]*$`)
+
+// folderWatch 定期扫描监视目录，把新出现的 ecs 输出文件导入历史记录。已导入的文件按路径与修改时间
+// 记录在 statePath 中，文件被重新同步（修改时间变化）时会再次导入。
+type folderWatch struct {
+	dir       string
+	statePath string
+
+	mu   sync.Mutex
+	seen map[string]time.Time
+	stop chan struct{}
+}
+
+func newFolderWatch(dir, statePath string) *folderWatch {
+	w := &folderWatch{dir: dir, statePath: statePath, seen: map[string]time.Time{}}
+	if data, err := os.ReadFile(statePath); err == nil {
+		_ = json.Unmarshal(data, &w.seen)
+	}
+	return w
+}
+
+// pending 返回 now 时可以导入的新文件，按名称排序；隐藏文件（如 rsync 的临时文件）与仍在写入的文件会跳过
+func (w *folderWatch) pending(now time.Time) []string {
+	entries, err := os.ReadDir(w.dir)
+	if err != nil {
+		return nil
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	var paths []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !containsString(droppedLogExtensions, strings.ToLower(filepath.Ext(name))) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || now.Sub(info.ModTime()) < watchSettleTime || info.Size() > maxDroppedLogSize {
+			continue
+		}
+		path := filepath.Join(w.dir, name)
+		if seen, ok := w.seen[path]; ok && seen.Equal(info.ModTime()) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// markSeen 记录文件已处理，无论是否解析到结果，避免每次扫描都重试
+func (w *folderWatch) markSeen(path string, modTime time.Time) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.seen[path] = modTime
+	data, err := json.MarshalIndent(w.seen, "", "  ")
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(w.statePath), 0o755); err == nil {
+		_ = os.WriteFile(w.statePath, data, 0o600)
+	}
+}
+
+// watchedHost 返回外部结果的主机名：优先取输出中基础信息的主机名，否则取文件名去掉扩展名与末尾的日期时间
+func watchedHost(path string, report *results.Report) string {
+	for _, field := range report.System {
+		if field.Name == "主机名" || strings.EqualFold(field.Name, "Hostname") {
+			if value := strings.TrimSpace(field.Value); value != "" {
+				return value
+			}
+		}
+	}
+	stem := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if host := watchHostSuffix.ReplaceAllString(stem, ""); host != "" {
+		return host
+	}
+	return stem
+}
+
+// importWatchedFile 解析一个监视目录中的文件并保存到历史记录，返回是否导入了结果
+func (ui *TestUI) importWatchedFile(w *folderWatch, path string) bool {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	defer w.markSeen(path, info.ModTime())
+	output, err := readDroppedLog(path)
+	if err != nil {
+		uiLog.Warn("watch folder read failed", "file", path, "err", err)
+		return false
+	}
+	host := watchedHost(path, results.Parse(results.StripANSI(output)))
+	run, err := importedRun(output, host, filepath.Base(path), info.ModTime())
+	if err != nil {
+		uiLog.Info("watch folder file skipped", "file", path, "err", err)
+		return false
+	}
+	ui.saveHistoryRun(run)
+	uiLog.Info("watch folder file imported", "file", path, "host", host)
+	return true
+}
+
+// scanWatchFolder 导入监视目录中的新文件，有新结果时发送通知
+func (ui *TestUI) scanWatchFolder(w *folderWatch, now time.Time) {
+	imported := 0
+	for _, path := range w.pending(now) {
+		if ui.importWatchedFile(w, path) {
+			imported++
+		}
+	}
+	if imported > 0 {
+		ui.sendNotification(ui.tr("watch.title"), fmt.Sprintf(ui.tr("watch.imported"), imported))
+	}
+}
+
+// startFolderWatch 按设置开始监视目录，已在监视时先停止；未设置目录时只停止
+func (ui *TestUI) startFolderWatch() {
+	ui.stopFolderWatch()
+	dir := strings.TrimSpace(ui.App.Preferences().String(watchFolderPreferenceKey))
+	if dir == "" {
+		return
+	}
+	w := newFolderWatch(dir, ui.appDataDir(watchImportedFileName))
+	w.stop = make(chan struct{})
+	ui.Mu.Lock()
+	ui.folderWatch = w
+	ui.Mu.Unlock()
+	stop := w.stop
+	go func() {
+		ui.scanWatchFolder(w, time.Now())
+		ticker := time.NewTicker(watchFolderInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case now := <-ticker.C:
+				ui.scanWatchFolder(w, now)
+			}
+		}
+	}()
+}
+
+func (ui *TestUI) stopFolderWatch() {
+	ui.Mu.Lock()
+	w := ui.folderWatch
+	ui.folderWatch = nil
+	ui.Mu.Unlock()
+	if w != nil && w.stop != nil {
+		close(w.stop)
+	}
+}
+
+// showWatchFolderSettings 设置监视目录，留空表示不监视
+func (ui *TestUI) showWatchFolderSettings() {
+	prefs := ui.App.Preferences()
+	dir := widget.NewEntry()
+	dir.SetPlaceHolder(ui.tr("watch.none"))
+	dir.SetText(prefs.String(watchFolderPreferenceKey))
+	browse := widget.NewButtonWithIcon("", theme.FolderOpenIcon(), func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil {
+				dialog.ShowError(err, ui.Window)
+				return
+			}
+			if uri != nil {
+				dir.SetText(uri.Path())
+			}
+		}, ui.Window)
+	})
+	items := []*widget.FormItem{
+		{Text: ui.tr("watch.folder"), Widget: container.NewBorder(nil, nil, nil, browse, dir), HintText: ui.tr("watch.hint")},
+	}
+	form := dialog.NewForm(ui.tr("watch.title"), ui.tr("hosts.save"), ui.tr("compare.cancel"), items, func(ok bool) {
+		if !ok {
+			return
+		}
+		value := strings.TrimSpace(dir.Text)
+		if value != "" {
+			if info, err := os.Stat(value); err != nil || !info.IsDir() {
+				dialog.ShowError(fmt.Errorf("%s: not a directory", value), ui.Window)
+				return
+			}
+		}
+		prefs.SetString(watchFolderPreferenceKey, value)
+		ui.startFolderWatch()
+	}, ui.Window)
+	form.Resize(fyne.NewSize(560, 0))
+	form.Show()
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/oneclickvirt/ecs-gui/results"
+)
+
+func TestWatchedHost(t *testing.T) {
+	tests := map[string]string{
+		"/sync/hk-1_20250301-1020.log": "hk-1",
+		"/sync/vps.example.com.txt":    "vps.example.com",
+		"/sync/10.0.0.2.log":           "10.0.0.2",
+		"/sync/tokyo 2025-03-01.log":   "tokyo",
+	}
+	for path, want := range tests {
+		if got := watchedHost(path, &results.Report{}); got != want {
+			t.Fatalf("watchedHost(%q) = %q, want %q", path, got, want)
+		}
+	}
+	report := &results.Report{System: []results.InfoField{{Name: "主机名", Value: "la-2"}}}
+	if got := watchedHost("/sync/hk-1.log", report); got != "la-2" {
+		t.Fatalf("watchedHost() = %q, want the hostname from the output", got)
+	}
+}
+
+func TestFolderWatchImportsNewFilesOnce(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	ui := newTestUIForTest(t)
+	dir := t.TempDir()
+	now := time.Now()
+	write := func(name, content string, modTime time.Time) {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	report := "---------------------CPU测试-通过sysbench测试---------------------\n1 线程测试(单核)得分: 1000\n"
+	write("hk-1_20250301.log", report, now.Add(-time.Hour))
+	write("notes.txt", "nothing to import\n", now.Add(-time.Hour))
+	write(".la-2.log.Xy12", report, now.Add(-time.Hour))
+	write("la-2.log", report, now)
+
+	statePath := filepath.Join(t.TempDir(), watchImportedFileName)
+	w := newFolderWatch(dir, statePath)
+	ui.scanWatchFolder(w, now)
+	if len(ui.historyItems) != 1 || ui.historyItems[0].Host != "hk-1" || ui.historyItems[0].Status != importedStatus {
+		t.Fatalf("historyItems = %#v, want only the settled report", ui.historyItems)
+	}
+
+	// 重新启动后不会重复导入，写入完成的文件在下一次扫描时导入
+	w = newFolderWatch(dir, statePath)
+	ui.scanWatchFolder(w, now.Add(watchFolderInterval))
+	if len(ui.historyItems) != 2 || ui.historyItems[0].Host != "la-2" {
+		t.Fatalf("historyItems = %#v", ui.historyItems)
+	}
+	if paths := w.pending(now.Add(2 * watchFolderInterval)); len(paths) != 0 {
+		t.Fatalf("pending = %v, want every file handled", paths)
+	}
+}